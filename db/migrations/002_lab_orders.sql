@@ -0,0 +1,156 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Lab technicians enter and verify results.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'lab_technician';
+
+CREATE TYPE lab_specimen_type AS ENUM ('blood', 'serum', 'plasma', 'urine', 'stool', 'sputum', 'csf', 'swab', 'tissue', 'other');
+CREATE TYPE lab_order_priority AS ENUM ('routine', 'urgent', 'stat');
+CREATE TYPE lab_order_status AS ENUM ('ordered', 'collected', 'resulted', 'verified');
+CREATE TYPE lab_result_flag AS ENUM ('normal', 'low', 'high', 'critical_low', 'critical_high', 'abnormal');
+
+-- Test catalog (e.g. CBC, LFT)
+CREATE TABLE lab_tests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    specimen_type lab_specimen_type NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Analytes reported by a test (e.g. hemoglobin, WBC for a CBC)
+CREATE TABLE lab_analytes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    test_id UUID NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    unit VARCHAR(50),
+    reference_low DOUBLE PRECISION,
+    reference_high DOUBLE PRECISION,
+    critical_low DOUBLE PRECISION,
+    critical_high DOUBLE PRECISION,
+    display_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_lab_analytes_test
+        FOREIGN KEY(test_id)
+        REFERENCES lab_tests(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uq_lab_analytes_test_code UNIQUE (test_id, code)
+);
+
+CREATE INDEX idx_lab_analytes_code ON lab_analytes(code);
+
+-- Orders placed against a visit
+CREATE TABLE lab_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    visit_id UUID NOT NULL,
+    test_id UUID NOT NULL,
+    ordered_by_user_id UUID NOT NULL,
+    priority lab_order_priority NOT NULL DEFAULT 'routine',
+    status lab_order_status NOT NULL DEFAULT 'ordered',
+    clinical_notes TEXT,
+    ordered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    collected_at TIMESTAMPTZ,
+    collected_by_user_id UUID,
+    resulted_at TIMESTAMPTZ,
+    resulted_by_user_id UUID,
+    verified_at TIMESTAMPTZ,
+    verified_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_lab_orders_visit
+        FOREIGN KEY(visit_id)
+        REFERENCES patient_visits(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_lab_orders_test
+        FOREIGN KEY(test_id)
+        REFERENCES lab_tests(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_lab_orders_ordered_by
+        FOREIGN KEY(ordered_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_lab_orders_visit_id ON lab_orders(visit_id);
+CREATE INDEX idx_lab_orders_status ON lab_orders(status);
+
+-- One row per analyte result
+CREATE TABLE lab_results (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL,
+    analyte_id UUID NOT NULL,
+    value_numeric DOUBLE PRECISION,
+    value_text TEXT,
+    unit VARCHAR(50),
+    reference_low DOUBLE PRECISION,
+    reference_high DOUBLE PRECISION,
+    flag lab_result_flag NOT NULL DEFAULT 'normal',
+    comment TEXT,
+    entered_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_lab_results_order
+        FOREIGN KEY(order_id)
+        REFERENCES lab_orders(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_lab_results_analyte
+        FOREIGN KEY(analyte_id)
+        REFERENCES lab_analytes(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT uq_lab_results_order_analyte UNIQUE (order_id, analyte_id)
+);
+
+CREATE INDEX idx_lab_results_analyte_id ON lab_results(analyte_id);
+
+CREATE TRIGGER set_lab_tests_updated_at
+BEFORE UPDATE ON lab_tests
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_lab_analytes_updated_at
+BEFORE UPDATE ON lab_analytes
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_lab_orders_updated_at
+BEFORE UPDATE ON lab_orders
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_lab_results_updated_at
+BEFORE UPDATE ON lab_results
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS set_lab_results_updated_at ON lab_results;
+DROP TRIGGER IF EXISTS set_lab_orders_updated_at ON lab_orders;
+DROP TRIGGER IF EXISTS set_lab_analytes_updated_at ON lab_analytes;
+DROP TRIGGER IF EXISTS set_lab_tests_updated_at ON lab_tests;
+
+DROP TABLE IF EXISTS lab_results;
+DROP TABLE IF EXISTS lab_orders;
+DROP TABLE IF EXISTS lab_analytes;
+DROP TABLE IF EXISTS lab_tests;
+
+DROP TYPE IF EXISTS lab_result_flag;
+DROP TYPE IF EXISTS lab_order_status;
+DROP TYPE IF EXISTS lab_order_priority;
+DROP TYPE IF EXISTS lab_specimen_type;
+
+-- Postgres cannot drop a single enum value; lab_technician stays on user_role.
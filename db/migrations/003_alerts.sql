@@ -0,0 +1,160 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TYPE observation_source AS ENUM ('vital_sign', 'lab_result');
+CREATE TYPE alert_status AS ENUM ('open', 'acknowledged');
+
+-- Numeric observations (vital signs) recorded against a visit.
+-- Lab values live in lab_results and are evaluated from there.
+CREATE TABLE visit_observations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    visit_id UUID NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(50),
+    observed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    recorded_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_visit_observations_visit
+        FOREIGN KEY(visit_id)
+        REFERENCES patient_visits(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_visit_observations_recorded_by
+        FOREIGN KEY(recorded_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_visit_observations_visit_id ON visit_observations(visit_id);
+
+-- Critical thresholds per observation code, optionally narrowed by sex and age band.
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    observation_code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    sex gender_enum,
+    min_age_years INT,
+    max_age_years INT,
+    critical_low DOUBLE PRECISION,
+    critical_high DOUBLE PRECISION,
+    escalate_after_minutes INT NOT NULL DEFAULT 30,
+    escalation_doctor_id UUID,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_alert_rules_threshold
+        CHECK (critical_low IS NOT NULL OR critical_high IS NOT NULL),
+
+    CONSTRAINT chk_alert_rules_escalate_after
+        CHECK (escalate_after_minutes > 0),
+
+    CONSTRAINT fk_alert_rules_escalation_doctor
+        FOREIGN KEY(escalation_doctor_id)
+        REFERENCES users(id)
+        ON DELETE SET NULL,
+
+    CONSTRAINT fk_alert_rules_created_by
+        FOREIGN KEY(created_by_user_id)
+        REFERENCES users(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX idx_alert_rules_observation_code ON alert_rules(observation_code) WHERE is_active;
+
+CREATE TABLE alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id UUID NOT NULL,
+    visit_id UUID NOT NULL,
+    patient_id UUID NOT NULL,
+    source observation_source NOT NULL,
+    observation_code VARCHAR(50) NOT NULL,
+    observed_value DOUBLE PRECISION NOT NULL,
+    threshold_value DOUBLE PRECISION NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    message TEXT NOT NULL,
+    status alert_status NOT NULL DEFAULT 'open',
+    original_doctor_id UUID NOT NULL,
+    assigned_doctor_id UUID NOT NULL,
+    escalation_level INT NOT NULL DEFAULT 0,
+    escalate_at TIMESTAMPTZ,
+    escalated_at TIMESTAMPTZ,
+    acknowledged_at TIMESTAMPTZ,
+    acknowledged_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_alerts_direction CHECK (direction IN ('low', 'high')),
+
+    CONSTRAINT fk_alerts_rule
+        FOREIGN KEY(rule_id)
+        REFERENCES alert_rules(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_alerts_visit
+        FOREIGN KEY(visit_id)
+        REFERENCES patient_visits(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_alerts_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_alerts_original_doctor
+        FOREIGN KEY(original_doctor_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_alerts_assigned_doctor
+        FOREIGN KEY(assigned_doctor_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_alerts_assigned_doctor_status ON alerts(assigned_doctor_id, status);
+-- The escalation worker polls this index; acknowledged alerts have escalate_at cleared.
+CREATE INDEX idx_alerts_escalate_at ON alerts(escalate_at) WHERE status = 'open' AND escalate_at IS NOT NULL;
+
+CREATE TABLE alert_escalations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL,
+    from_doctor_id UUID NOT NULL,
+    to_doctor_id UUID NOT NULL,
+    escalation_level INT NOT NULL,
+    escalated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_alert_escalations_alert
+        FOREIGN KEY(alert_id)
+        REFERENCES alerts(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_alert_escalations_alert_id ON alert_escalations(alert_id);
+
+CREATE TRIGGER set_alert_rules_updated_at
+BEFORE UPDATE ON alert_rules
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_alerts_updated_at
+BEFORE UPDATE ON alerts
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS set_alerts_updated_at ON alerts;
+DROP TRIGGER IF EXISTS set_alert_rules_updated_at ON alert_rules;
+
+DROP TABLE IF EXISTS alert_escalations;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS alert_rules;
+DROP TABLE IF EXISTS visit_observations;
+
+DROP TYPE IF EXISTS alert_status;
+DROP TYPE IF EXISTS observation_source;
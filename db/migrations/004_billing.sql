@@ -0,0 +1,243 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- All money columns are BIGINT minor units (e.g. paise); tax rates are basis points (1800 = 18%).
+
+CREATE TYPE charge_source AS ENUM ('visit', 'procedure', 'lab_order', 'manual');
+CREATE TYPE invoice_status AS ENUM ('issued', 'partially_paid', 'paid');
+CREATE TYPE payment_method AS ENUM ('cash', 'card', 'insurance');
+
+-- Tax categories referenced by the charge master
+CREATE TABLE tax_categories (
+    code VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL,
+    rate_bps INT NOT NULL CHECK (rate_bps BETWEEN 0 AND 10000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tax_categories (code, description, rate_bps) VALUES ('exempt', 'Exempt healthcare services', 0);
+
+-- Service/charge master
+CREATE TABLE charge_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    unit_price_minor BIGINT NOT NULL CHECK (unit_price_minor >= 0),
+    tax_category_code VARCHAR(50) NOT NULL DEFAULT 'exempt',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_charge_items_tax_category
+        FOREIGN KEY(tax_category_code)
+        REFERENCES tax_categories(code)
+        ON DELETE RESTRICT
+);
+
+-- Lab tests bill through the charge master
+ALTER TABLE lab_tests ADD COLUMN charge_code VARCHAR(50)
+    CONSTRAINT fk_lab_tests_charge_code REFERENCES charge_items(code) ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- Invoices take their lines from these captured charges
+CREATE TABLE charges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    patient_id UUID NOT NULL,
+    visit_id UUID,
+    charge_item_id UUID NOT NULL,
+    source charge_source NOT NULL,
+    source_id UUID,
+    description VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    unit_price_minor BIGINT NOT NULL CHECK (unit_price_minor >= 0),
+    tax_rate_bps INT NOT NULL CHECK (tax_rate_bps BETWEEN 0 AND 10000),
+    invoice_id UUID,
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_charges_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_charges_visit
+        FOREIGN KEY(visit_id)
+        REFERENCES patient_visits(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_charges_charge_item
+        FOREIGN KEY(charge_item_id)
+        REFERENCES charge_items(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_charges_created_by
+        FOREIGN KEY(created_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_charges_patient_id ON charges(patient_id);
+CREATE INDEX idx_charges_unbilled ON charges(patient_id) WHERE invoice_id IS NULL;
+-- Automatic capture is idempotent per source record and item.
+CREATE UNIQUE INDEX uq_charges_source ON charges(source, source_id, charge_item_id) WHERE source_id IS NOT NULL;
+
+-- Gapless invoice numbering: the counter row is locked by the issuing transaction,
+-- so a rolled-back invoice never consumes a number.
+CREATE TABLE invoice_counters (
+    fiscal_year INT PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_number VARCHAR(50) UNIQUE NOT NULL,
+    fiscal_year INT NOT NULL,
+    sequence_number INT NOT NULL,
+    patient_id UUID NOT NULL,
+    status invoice_status NOT NULL DEFAULT 'issued',
+    subtotal_minor BIGINT NOT NULL,
+    discount_minor BIGINT NOT NULL DEFAULT 0,
+    tax_minor BIGINT NOT NULL DEFAULT 0,
+    total_minor BIGINT NOT NULL CHECK (total_minor >= 0),
+    paid_minor BIGINT NOT NULL DEFAULT 0 CHECK (paid_minor >= 0),
+    notes TEXT,
+    issued_by_user_id UUID NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_invoices_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_invoices_issued_by
+        FOREIGN KEY(issued_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT uq_invoices_fiscal_sequence UNIQUE (fiscal_year, sequence_number),
+    CONSTRAINT chk_invoices_paid_within_total CHECK (paid_minor <= total_minor)
+);
+
+CREATE INDEX idx_invoices_patient_id ON invoices(patient_id);
+
+ALTER TABLE charges ADD CONSTRAINT fk_charges_invoice
+    FOREIGN KEY(invoice_id) REFERENCES invoices(id) ON DELETE RESTRICT;
+
+CREATE TABLE invoice_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL,
+    charge_id UUID UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    unit_price_minor BIGINT NOT NULL,
+    discount_minor BIGINT NOT NULL,
+    tax_rate_bps INT NOT NULL,
+    tax_minor BIGINT NOT NULL,
+    line_total_minor BIGINT NOT NULL,
+
+    CONSTRAINT fk_invoice_lines_invoice
+        FOREIGN KEY(invoice_id)
+        REFERENCES invoices(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_invoice_lines_charge
+        FOREIGN KEY(charge_id)
+        REFERENCES charges(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_invoice_lines_invoice_id ON invoice_lines(invoice_id);
+
+CREATE TABLE payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL,
+    patient_id UUID NOT NULL,
+    method payment_method NOT NULL,
+    amount_minor BIGINT NOT NULL CHECK (amount_minor > 0),
+    reference VARCHAR(255),
+    received_by_user_id UUID NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_payments_invoice
+        FOREIGN KEY(invoice_id)
+        REFERENCES invoices(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_payments_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_payments_received_by
+        FOREIGN KEY(received_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_payments_invoice_id ON payments(invoice_id);
+CREATE INDEX idx_payments_patient_id ON payments(patient_id);
+
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payment_id UUID NOT NULL,
+    invoice_id UUID NOT NULL,
+    amount_minor BIGINT NOT NULL CHECK (amount_minor > 0),
+    reason TEXT NOT NULL,
+    refunded_by_user_id UUID NOT NULL,
+    refunded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_refunds_payment
+        FOREIGN KEY(payment_id)
+        REFERENCES payments(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_refunds_invoice
+        FOREIGN KEY(invoice_id)
+        REFERENCES invoices(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_refunds_refunded_by
+        FOREIGN KEY(refunded_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_refunds_payment_id ON refunds(payment_id);
+
+CREATE TRIGGER set_tax_categories_updated_at
+BEFORE UPDATE ON tax_categories
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_charge_items_updated_at
+BEFORE UPDATE ON charge_items
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_invoices_updated_at
+BEFORE UPDATE ON invoices
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS set_invoices_updated_at ON invoices;
+DROP TRIGGER IF EXISTS set_charge_items_updated_at ON charge_items;
+DROP TRIGGER IF EXISTS set_tax_categories_updated_at ON tax_categories;
+
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS invoice_lines;
+ALTER TABLE charges DROP CONSTRAINT IF EXISTS fk_charges_invoice;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_counters;
+DROP TABLE IF EXISTS charges;
+ALTER TABLE lab_tests DROP COLUMN IF EXISTS charge_code;
+DROP TABLE IF EXISTS charge_items;
+DROP TABLE IF EXISTS tax_categories;
+
+DROP TYPE IF EXISTS payment_method;
+DROP TYPE IF EXISTS invoice_status;
+DROP TYPE IF EXISTS charge_source;
@@ -0,0 +1,157 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TYPE policy_priority AS ENUM ('primary', 'secondary');
+CREATE TYPE eligibility_status AS ENUM ('eligible', 'ineligible', 'unknown');
+CREATE TYPE claim_status AS ENUM ('submitted', 'accepted', 'rejected', 'paid');
+
+-- Insurance policies held by a patient
+CREATE TABLE insurance_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    patient_id UUID NOT NULL,
+    payer_code VARCHAR(50) NOT NULL,
+    payer_name VARCHAR(255) NOT NULL,
+    plan_name VARCHAR(255),
+    member_id VARCHAR(100) NOT NULL,
+    group_number VARCHAR(100),
+    valid_from DATE NOT NULL,
+    valid_to DATE,
+    priority policy_priority NOT NULL DEFAULT 'primary',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    eligibility_status eligibility_status NOT NULL DEFAULT 'unknown',
+    eligibility_checked_at TIMESTAMPTZ,
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_insurance_policies_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_insurance_policies_created_by
+        FOREIGN KEY(created_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT chk_insurance_policies_validity CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX idx_insurance_policies_patient_id ON insurance_policies(patient_id);
+-- A patient has at most one active primary and one active secondary policy.
+CREATE UNIQUE INDEX uq_insurance_policies_active_priority ON insurance_policies(patient_id, priority) WHERE is_active;
+
+-- Eligibility verification history
+CREATE TABLE eligibility_checks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    policy_id UUID NOT NULL,
+    status eligibility_status NOT NULL,
+    message TEXT,
+    payer_reference VARCHAR(255),
+    checked_by_user_id UUID NOT NULL,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_eligibility_checks_policy
+        FOREIGN KEY(policy_id)
+        REFERENCES insurance_policies(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_eligibility_checks_checked_by
+        FOREIGN KEY(checked_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_eligibility_checks_policy_id ON eligibility_checks(policy_id);
+
+-- Claims raised from invoices
+CREATE TABLE insurance_claims (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL,
+    policy_id UUID NOT NULL,
+    patient_id UUID NOT NULL,
+    diagnosis_codes TEXT[] NOT NULL,
+    claimed_minor BIGINT NOT NULL CHECK (claimed_minor > 0),
+    paid_minor BIGINT,
+    status claim_status NOT NULL DEFAULT 'submitted',
+    payer_reference VARCHAR(255),
+    rejection_reason TEXT,
+    submitted_by_user_id UUID NOT NULL,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_insurance_claims_invoice
+        FOREIGN KEY(invoice_id)
+        REFERENCES invoices(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_insurance_claims_policy
+        FOREIGN KEY(policy_id)
+        REFERENCES insurance_policies(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_insurance_claims_patient
+        FOREIGN KEY(patient_id)
+        REFERENCES patients(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_insurance_claims_submitted_by
+        FOREIGN KEY(submitted_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT chk_insurance_claims_diagnosis CHECK (cardinality(diagnosis_codes) > 0)
+);
+
+CREATE INDEX idx_insurance_claims_invoice_id ON insurance_claims(invoice_id);
+CREATE INDEX idx_insurance_claims_status ON insurance_claims(status);
+-- Only a rejected claim may be resubmitted for the same invoice and policy.
+CREATE UNIQUE INDEX uq_insurance_claims_open ON insurance_claims(invoice_id, policy_id) WHERE status <> 'rejected';
+
+-- Every status a claim has been in, with who recorded it
+CREATE TABLE claim_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    claim_id UUID NOT NULL,
+    status claim_status NOT NULL,
+    note TEXT,
+    changed_by_user_id UUID NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_claim_status_history_claim
+        FOREIGN KEY(claim_id)
+        REFERENCES insurance_claims(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_claim_status_history_changed_by
+        FOREIGN KEY(changed_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_claim_status_history_claim_id ON claim_status_history(claim_id);
+
+CREATE TRIGGER set_insurance_policies_updated_at
+BEFORE UPDATE ON insurance_policies
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_insurance_claims_updated_at
+BEFORE UPDATE ON insurance_claims
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS set_insurance_claims_updated_at ON insurance_claims;
+DROP TRIGGER IF EXISTS set_insurance_policies_updated_at ON insurance_policies;
+
+DROP TABLE IF EXISTS claim_status_history;
+DROP TABLE IF EXISTS insurance_claims;
+DROP TABLE IF EXISTS eligibility_checks;
+DROP TABLE IF EXISTS insurance_policies;
+
+DROP TYPE IF EXISTS claim_status;
+DROP TYPE IF EXISTS eligibility_status;
+DROP TYPE IF EXISTS policy_priority;
@@ -0,0 +1,165 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Pharmacists receive, dispense and adjust stock.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'pharmacist';
+
+CREATE TYPE stock_movement_type AS ENUM ('receipt', 'dispense', 'return', 'adjustment');
+
+-- Stores that hold stock (e.g. main pharmacy, ward cabinet)
+CREATE TABLE pharmacy_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Drug formulary
+CREATE TABLE pharmacy_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    dosage_form VARCHAR(50),
+    strength VARCHAR(50),
+    unit VARCHAR(20) NOT NULL DEFAULT 'unit',
+    reorder_level INT NOT NULL DEFAULT 0 CHECK (reorder_level >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Manufacturer lots of an item
+CREATE TABLE pharmacy_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL,
+    lot_number VARCHAR(100) NOT NULL,
+    expiry_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_pharmacy_batches_item
+        FOREIGN KEY(item_id)
+        REFERENCES pharmacy_items(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT uq_pharmacy_batches_item_lot UNIQUE (item_id, lot_number)
+);
+
+CREATE INDEX idx_pharmacy_batches_item_expiry ON pharmacy_batches(item_id, expiry_date);
+
+-- Current quantity of each batch at each location. Kept in step with stock_movements
+-- in the same transaction; the ledger is the source of truth.
+CREATE TABLE stock_levels (
+    location_id UUID NOT NULL,
+    batch_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (location_id, batch_id),
+
+    CONSTRAINT fk_stock_levels_location
+        FOREIGN KEY(location_id)
+        REFERENCES pharmacy_locations(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_stock_levels_batch
+        FOREIGN KEY(batch_id)
+        REFERENCES pharmacy_batches(id)
+        ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_stock_levels_batch_id ON stock_levels(batch_id);
+
+-- Append-only ledger of every change in stock. quantity is signed: positive adds stock.
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    movement_type stock_movement_type NOT NULL,
+    location_id UUID NOT NULL,
+    batch_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity <> 0),
+    visit_id UUID,
+    returned_movement_id UUID,
+    reference VARCHAR(255),
+    reason TEXT,
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_stock_movements_location
+        FOREIGN KEY(location_id)
+        REFERENCES pharmacy_locations(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_stock_movements_batch
+        FOREIGN KEY(batch_id)
+        REFERENCES pharmacy_batches(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_stock_movements_visit
+        FOREIGN KEY(visit_id)
+        REFERENCES patient_visits(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_stock_movements_returned_movement
+        FOREIGN KEY(returned_movement_id)
+        REFERENCES stock_movements(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT fk_stock_movements_created_by
+        FOREIGN KEY(created_by_user_id)
+        REFERENCES users(id)
+        ON DELETE RESTRICT,
+
+    CONSTRAINT chk_stock_movements_shape CHECK (
+        (movement_type = 'receipt' AND quantity > 0)
+        OR (movement_type = 'dispense' AND quantity < 0 AND visit_id IS NOT NULL)
+        OR (movement_type = 'return' AND quantity > 0 AND returned_movement_id IS NOT NULL)
+        OR (movement_type = 'adjustment' AND reason IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_stock_movements_batch_location ON stock_movements(batch_id, location_id);
+CREATE INDEX idx_stock_movements_visit_id ON stock_movements(visit_id) WHERE visit_id IS NOT NULL;
+CREATE INDEX idx_stock_movements_returned_movement_id ON stock_movements(returned_movement_id) WHERE returned_movement_id IS NOT NULL;
+CREATE INDEX idx_stock_movements_created_at ON stock_movements(created_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reject_stock_movement_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'stock_movements is append-only; record a correcting movement instead';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER stock_movements_append_only
+BEFORE UPDATE OR DELETE ON stock_movements
+FOR EACH ROW
+EXECUTE FUNCTION reject_stock_movement_change();
+
+CREATE TRIGGER set_pharmacy_locations_updated_at
+BEFORE UPDATE ON pharmacy_locations
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TRIGGER set_pharmacy_items_updated_at
+BEFORE UPDATE ON pharmacy_items
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS set_pharmacy_items_updated_at ON pharmacy_items;
+DROP TRIGGER IF EXISTS set_pharmacy_locations_updated_at ON pharmacy_locations;
+DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS reject_stock_movement_change();
+
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_levels;
+DROP TABLE IF EXISTS pharmacy_batches;
+DROP TABLE IF EXISTS pharmacy_items;
+DROP TABLE IF EXISTS pharmacy_locations;
+
+DROP TYPE IF EXISTS stock_movement_type;
+-- Postgres cannot drop a single enum value; pharmacist stays on user_role.
@@ -0,0 +1,63 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Administrators review the audit trail. They are provisioned directly in the
+-- database; /auth/register cannot create them.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'admin';
+
+-- Append-only, hash-chained record of every PHI read and write. Each row's hash covers
+-- its own fields and the previous row's hash, so editing, deleting or reordering rows
+-- breaks the chain. seq is assigned without gaps under an advisory lock.
+CREATE TABLE audit_events (
+    seq BIGINT PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    actor_user_id UUID,
+    actor_role VARCHAR(50),
+    action VARCHAR(50) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id UUID,
+    patient_id UUID,
+    changes JSONB,
+    client_ip VARCHAR(64),
+    user_agent TEXT,
+    request_id VARCHAR(100),
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE,
+
+    CONSTRAINT chk_audit_events_seq CHECK (seq > 0)
+);
+
+-- No foreign keys: the trail must outlive the users and records it mentions.
+CREATE INDEX idx_audit_events_patient_id ON audit_events(patient_id, occurred_at) WHERE patient_id IS NOT NULL;
+CREATE INDEX idx_audit_events_actor_user_id ON audit_events(actor_user_id, occurred_at);
+CREATE INDEX idx_audit_events_resource ON audit_events(resource_type, resource_id);
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reject_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW
+EXECUTE FUNCTION reject_audit_event_change();
+
+CREATE TRIGGER audit_events_no_truncate
+BEFORE TRUNCATE ON audit_events
+FOR EACH STATEMENT
+EXECUTE FUNCTION reject_audit_event_change();
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+DROP FUNCTION IF EXISTS reject_audit_event_change();
+
+DROP TABLE IF EXISTS audit_events;
+-- Postgres cannot drop a single enum value; admin stays on user_role.
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Privacy officers review who accessed a patient's chart. Like admins they are
+-- provisioned directly in the database.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'privacy_officer';
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Postgres cannot drop a single enum value; privacy_officer stays on user_role.
@@ -0,0 +1,41 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- A break-glass grant gives one user time-boxed emergency access to one patient's chart.
+-- Grants are never deleted; they expire or are revoked.
+CREATE TABLE break_glass_grants (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    reason TEXT NOT NULL,
+    granted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_break_glass_grants_reason CHECK (char_length(btrim(reason)) > 0),
+    CONSTRAINT chk_break_glass_grants_window CHECK (expires_at > granted_at)
+);
+
+CREATE INDEX idx_break_glass_grants_user_patient ON break_glass_grants(user_id, patient_id, expires_at);
+CREATE INDEX idx_break_glass_grants_granted_at ON break_glass_grants(granted_at);
+
+-- Accesses made under a grant are flagged with it in the audit trail.
+ALTER TABLE audit_events ADD COLUMN break_glass_grant_id UUID;
+CREATE INDEX idx_audit_events_break_glass ON audit_events(break_glass_grant_id, occurred_at) WHERE break_glass_grant_id IS NOT NULL;
+
+-- One compliance review report per UTC day, generated once the day is over.
+CREATE TABLE break_glass_reports (
+    report_date DATE PRIMARY KEY,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    grant_count INT NOT NULL,
+    access_count INT NOT NULL,
+    report JSONB NOT NULL
+);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS break_glass_reports;
+DROP INDEX IF EXISTS idx_audit_events_break_glass;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS break_glass_grant_id;
+DROP TABLE IF EXISTS break_glass_grants;
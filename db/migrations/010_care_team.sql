@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Doctors assigned to look after a patient. A doctor also has access to the patients
+-- they have recorded a visit for; the care team covers everyone else, e.g. a consultant
+-- who has not yet seen the patient.
+CREATE TABLE care_team_members (
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    assigned_by_user_id UUID NOT NULL REFERENCES users(id),
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (patient_id, user_id)
+);
+
+CREATE INDEX idx_care_team_members_user_id ON care_team_members(user_id);
+CREATE INDEX idx_patient_visits_doctor_patient ON patient_visits(doctor_id, patient_id);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_patient_visits_doctor_patient;
+DROP TABLE IF EXISTS care_team_members;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Contact details and clinical text are stored encrypted (see internal/fieldcrypt), so
+-- the columns hold ciphertext longer than the plaintext limits and equality on them no
+-- longer means anything. Uniqueness and lookups move to keyed blind indexes of the
+-- normalised phone number and email address.
+ALTER TABLE patients DROP CONSTRAINT IF EXISTS patients_contact_phone_key;
+ALTER TABLE patients DROP CONSTRAINT IF EXISTS patients_contact_email_key;
+DROP INDEX IF EXISTS idx_patients_contact_phone;
+DROP INDEX IF EXISTS idx_patients_contact_email;
+
+ALTER TABLE patients ALTER COLUMN contact_phone TYPE TEXT;
+ALTER TABLE patients ALTER COLUMN contact_email TYPE TEXT;
+ALTER TABLE patients ADD COLUMN contact_phone_bidx TEXT;
+ALTER TABLE patients ADD COLUMN contact_email_bidx TEXT;
+
+CREATE UNIQUE INDEX idx_patients_contact_phone_bidx ON patients(contact_phone_bidx) WHERE contact_phone_bidx IS NOT NULL;
+CREATE UNIQUE INDEX idx_patients_contact_email_bidx ON patients(contact_email_bidx) WHERE contact_email_bidx IS NOT NULL;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Only succeeds while the contact columns still hold plaintext.
+DROP INDEX IF EXISTS idx_patients_contact_email_bidx;
+DROP INDEX IF EXISTS idx_patients_contact_phone_bidx;
+ALTER TABLE patients DROP COLUMN IF EXISTS contact_email_bidx;
+ALTER TABLE patients DROP COLUMN IF EXISTS contact_phone_bidx;
+ALTER TABLE patients ALTER COLUMN contact_email TYPE VARCHAR(255);
+ALTER TABLE patients ALTER COLUMN contact_phone TYPE VARCHAR(20);
+CREATE INDEX idx_patients_contact_phone ON patients(contact_phone) WHERE contact_phone IS NOT NULL;
+CREATE INDEX idx_patients_contact_email ON patients(contact_email) WHERE contact_email IS NOT NULL;
+ALTER TABLE patients ADD CONSTRAINT patients_contact_phone_key UNIQUE (contact_phone);
+ALTER TABLE patients ADD CONSTRAINT patients_contact_email_key UNIQUE (contact_email);
@@ -0,0 +1,32 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TYPE consent_type AS ENUM ('treatment', 'data_sharing', 'research', 'sms_contact');
+
+-- What a patient agreed to, on which version of the consent text, and who witnessed it.
+-- Records are never deleted: withdrawing sets withdrawn_at, and signing again (e.g. a new
+-- text version) withdraws the record it replaces, so the history stays complete.
+CREATE TABLE consent_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    consent_type consent_type NOT NULL,
+    text_version VARCHAR(50) NOT NULL,
+    signed_at TIMESTAMPTZ NOT NULL,
+    witnessed_by_user_id UUID NOT NULL REFERENCES users(id),
+    withdrawn_at TIMESTAMPTZ,
+    withdrawn_by_user_id UUID REFERENCES users(id),
+    withdrawal_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_consent_withdrawal CHECK ((withdrawn_at IS NULL) = (withdrawn_by_user_id IS NULL))
+);
+
+-- At most one consent in force per patient and type.
+CREATE UNIQUE INDEX idx_consent_records_active ON consent_records(patient_id, consent_type) WHERE withdrawn_at IS NULL;
+CREATE INDEX idx_consent_records_patient ON consent_records(patient_id, consent_type, signed_at DESC);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS consent_records;
+DROP TYPE IF EXISTS consent_type;
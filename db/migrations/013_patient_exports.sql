@@ -0,0 +1,38 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TYPE patient_export_status AS ENUM ('pending', 'running', 'completed', 'failed', 'expired');
+
+-- Subject access request exports. A worker claims pending jobs, builds the archive and
+-- stores it encrypted in patient_export_archives until expires_at.
+CREATE TABLE patient_export_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    requested_by_user_id UUID NOT NULL REFERENCES users(id),
+    status patient_export_status NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    size_bytes BIGINT,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_patient_export_jobs_patient ON patient_export_jobs(patient_id, requested_at DESC);
+CREATE INDEX idx_patient_export_jobs_queue ON patient_export_jobs(status, requested_at) WHERE status IN ('pending', 'running');
+-- One export in progress per patient at a time.
+CREATE UNIQUE INDEX idx_patient_export_jobs_open ON patient_export_jobs(patient_id) WHERE status IN ('pending', 'running');
+
+-- Kept apart from the jobs so listing jobs never reads the archives.
+CREATE TABLE patient_export_archives (
+    job_id UUID PRIMARY KEY REFERENCES patient_export_jobs(id) ON DELETE CASCADE,
+    data BYTEA NOT NULL
+);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS patient_export_archives;
+DROP TABLE IF EXISTS patient_export_jobs;
+DROP TYPE IF EXISTS patient_export_status;
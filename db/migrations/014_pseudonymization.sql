@@ -0,0 +1,36 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Set when a patient's direct identifiers were replaced by tokens. The row and its
+-- clinical records stay, so statistics and legally required retention are unaffected.
+ALTER TABLE patients ADD COLUMN pseudonymized_at TIMESTAMPTZ;
+
+CREATE TYPE pseudonymization_status AS ENUM ('pending', 'approved', 'rejected');
+
+-- Erasure requests. A privacy officer or admin asks, and a different admin approves,
+-- which pseudonymizes the patient in the same transaction.
+CREATE TABLE pseudonymization_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    patient_id UUID NOT NULL REFERENCES patients(id),
+    requested_by_user_id UUID NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    status pseudonymization_status NOT NULL DEFAULT 'pending',
+    decided_by_user_id UUID REFERENCES users(id),
+    decided_at TIMESTAMPTZ,
+    decision_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_pseudonymization_decision CHECK ((status = 'pending') = (decided_at IS NULL)),
+    CONSTRAINT chk_pseudonymization_four_eyes CHECK (decided_by_user_id IS NULL OR decided_by_user_id <> requested_by_user_id)
+);
+
+-- At most one request waiting per patient.
+CREATE UNIQUE INDEX idx_pseudonymization_requests_pending ON pseudonymization_requests(patient_id) WHERE status = 'pending';
+CREATE INDEX idx_pseudonymization_requests_status ON pseudonymization_requests(status, created_at DESC);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS pseudonymization_requests;
+DROP TYPE IF EXISTS pseudonymization_status;
+ALTER TABLE patients DROP COLUMN IF EXISTS pseudonymized_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Incremented by every update that changes what clients see, and exposed as the ETag of
+-- the patient or visit. An update only applies when the row still has the version the
+-- client read (If-Match), so concurrent edits cannot silently overwrite each other.
+ALTER TABLE patients ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE patient_visits ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE patient_visits DROP COLUMN IF EXISTS version;
+ALTER TABLE patients DROP COLUMN IF EXISTS version;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- A finalized (signed) visit can still be amended, but only with a reason.
+ALTER TABLE patient_visits ADD COLUMN finalized_at TIMESTAMPTZ;
+ALTER TABLE patient_visits ADD COLUMN finalized_by_user_id UUID REFERENCES users(id);
+ALTER TABLE patient_visits ADD CONSTRAINT chk_patient_visits_finalized
+    CHECK ((finalized_at IS NULL) = (finalized_by_user_id IS NULL));
+
+CREATE TYPE visit_revision_kind AS ENUM ('edit', 'finalize', 'addendum');
+
+-- One row per change to a visit, finalizing included: who made it, why (required for
+-- addenda to a finalized visit), and the visit as it was before. The clinical text is
+-- encrypted like the visit's. The application only ever inserts rows, apart from
+-- re-encrypting them under a new key.
+CREATE TABLE patient_visit_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    visit_id UUID NOT NULL REFERENCES patient_visits(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL, -- The version of the visit this change produced
+    kind visit_revision_kind NOT NULL,
+    reason TEXT,
+    author_user_id UUID NOT NULL REFERENCES users(id),
+    revised_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    previous_visit_date TIMESTAMPTZ NOT NULL,
+    previous_symptoms TEXT,
+    previous_diagnosis TEXT,
+    previous_prescription TEXT,
+    previous_notes TEXT,
+
+    CONSTRAINT uq_patient_visit_revisions_version UNIQUE (visit_id, version),
+    CONSTRAINT chk_patient_visit_revisions_reason CHECK (kind <> 'addendum' OR reason IS NOT NULL)
+);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS patient_visit_revisions;
+DROP TYPE IF EXISTS visit_revision_kind;
+ALTER TABLE patient_visits DROP CONSTRAINT IF EXISTS chk_patient_visits_finalized;
+ALTER TABLE patient_visits DROP COLUMN IF EXISTS finalized_by_user_id;
+ALTER TABLE patient_visits DROP COLUMN IF EXISTS finalized_at;
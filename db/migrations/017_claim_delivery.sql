@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- A claim is committed before it is sent to the payer, so a claim the payer never
+-- acknowledged can be sent again under the same ID. Claims raised before this were sent
+-- in the transaction that created them.
+ALTER TABLE insurance_claims ADD COLUMN sent_to_payer_at TIMESTAMPTZ;
+UPDATE insurance_claims SET sent_to_payer_at = submitted_at;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE insurance_claims DROP COLUMN IF EXISTS sent_to_payer_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Only numeric results are flagged against a reference range. A text result is flagged by
+-- the technician who enters it, or not at all. Text results were stored as normal before
+-- this without anyone assessing them, so those flags are cleared.
+ALTER TABLE lab_results ALTER COLUMN flag DROP NOT NULL, ALTER COLUMN flag DROP DEFAULT;
+UPDATE lab_results SET flag = NULL WHERE value_numeric IS NULL AND flag = 'normal';
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+UPDATE lab_results SET flag = 'normal' WHERE flag IS NULL;
+ALTER TABLE lab_results ALTER COLUMN flag SET DEFAULT 'normal', ALTER COLUMN flag SET NOT NULL;
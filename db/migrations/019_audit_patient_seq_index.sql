@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Access log exports page through a patient's events by seq.
+CREATE INDEX idx_audit_events_patient_seq ON audit_events(patient_id, seq) WHERE patient_id IS NOT NULL;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_audit_events_patient_seq;
@@ -0,0 +1,9 @@
+// Package migrations embeds the schema migrations so the binary can apply them itself.
+package migrations
+
+import "embed"
+
+// FS holds the goose migrations, named <version>_<name>.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,125 @@
+-- name: CreateVisitObservation :one
+INSERT INTO visit_observations (
+    visit_id, code, value, unit, observed_at, recorded_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListVisitObservations :many
+SELECT * FROM visit_observations
+WHERE visit_id = $1
+ORDER BY observed_at DESC;
+
+-- name: CreateAlertRule :one
+INSERT INTO alert_rules (
+    observation_code, name, sex, min_age_years, max_age_years,
+    critical_low, critical_high, escalate_after_minutes,
+    escalation_doctor_id, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: ListActiveAlertRules :many
+SELECT * FROM alert_rules
+WHERE is_active = TRUE
+ORDER BY observation_code, created_at;
+
+-- name: GetAlertRuleByID :one
+SELECT * FROM alert_rules
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListActiveAlertRulesByCode :many
+SELECT * FROM alert_rules
+WHERE is_active = TRUE AND observation_code = $1
+ORDER BY created_at;
+
+-- name: DeactivateAlertRule :one
+UPDATE alert_rules
+SET is_active = FALSE
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateAlert :one
+INSERT INTO alerts (
+    rule_id, visit_id, patient_id, source, observation_code,
+    observed_value, threshold_value, direction, message,
+    original_doctor_id, assigned_doctor_id, escalate_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, $11
+)
+RETURNING *;
+
+-- name: GetAlertByID :one
+SELECT * FROM alerts
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListAlertsForDoctor :many
+SELECT * FROM alerts
+WHERE assigned_doctor_id = sqlc.arg(doctor_id)
+  AND (sqlc.narg(status)::alert_status IS NULL OR status = sqlc.narg(status)::alert_status)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountAlertsForDoctor :one
+SELECT COUNT(*) FROM alerts
+WHERE assigned_doctor_id = sqlc.arg(doctor_id)
+  AND (sqlc.narg(status)::alert_status IS NULL OR status = sqlc.narg(status)::alert_status);
+
+-- name: AcknowledgeAlert :one
+UPDATE alerts
+SET
+    status = 'acknowledged',
+    acknowledged_at = NOW(),
+    acknowledged_by_user_id = $2,
+    escalate_at = NULL
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- Rows locked here are skipped by other replicas running the same query.
+-- name: LockDueAlerts :many
+SELECT * FROM alerts
+WHERE status = 'open' AND escalate_at IS NOT NULL AND escalate_at <= $1
+ORDER BY escalate_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: EscalateAlert :one
+UPDATE alerts
+SET
+    assigned_doctor_id = $2,
+    escalation_level = escalation_level + 1,
+    escalated_at = NOW(),
+    escalate_at = sqlc.narg(next_escalate_at)
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: PostponeAlertEscalation :one
+UPDATE alerts
+SET escalate_at = $2
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: CreateAlertEscalation :one
+INSERT INTO alert_escalations (
+    alert_id, from_doctor_id, to_doctor_id, escalation_level
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- Picks the active doctor, other than the current assignee, with the fewest open alerts.
+-- name: PickEscalationDoctor :one
+SELECT u.id FROM users u
+LEFT JOIN alerts a ON a.assigned_doctor_id = u.id AND a.status = 'open'
+WHERE u.role = 'doctor' AND u.is_active = TRUE AND u.id <> $1
+GROUP BY u.id, u.username
+ORDER BY COUNT(a.id), u.username
+LIMIT 1;
+
+-- name: TryAdvisoryXactLock :one
+SELECT pg_try_advisory_xact_lock($1)::boolean AS acquired;
@@ -0,0 +1,75 @@
+-- name: AcquireAdvisoryXactLock :exec
+-- Blocks until the lock is free; released when the transaction ends.
+SELECT pg_advisory_xact_lock($1);
+
+-- name: GetAuditChainHead :one
+SELECT seq, hash FROM audit_events
+ORDER BY seq DESC
+LIMIT 1;
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    seq, occurred_at, actor_user_id, actor_role, action, resource_type, resource_id,
+    patient_id, changes, client_ip, user_agent, request_id, prev_hash, hash,
+    break_glass_grant_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE (sqlc.narg(actor_user_id)::uuid IS NULL OR actor_user_id = sqlc.narg(actor_user_id))
+  AND (sqlc.narg(patient_id)::uuid IS NULL OR patient_id = sqlc.narg(patient_id))
+  AND (sqlc.narg(resource_type)::text IS NULL OR resource_type = sqlc.narg(resource_type))
+  AND (sqlc.narg(resource_id)::uuid IS NULL OR resource_id = sqlc.narg(resource_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(occurred_from)::timestamptz IS NULL OR occurred_at >= sqlc.narg(occurred_from))
+  AND (sqlc.narg(occurred_to)::timestamptz IS NULL OR occurred_at < sqlc.narg(occurred_to))
+ORDER BY seq DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountAuditEvents :one
+SELECT COUNT(*) FROM audit_events
+WHERE (sqlc.narg(actor_user_id)::uuid IS NULL OR actor_user_id = sqlc.narg(actor_user_id))
+  AND (sqlc.narg(patient_id)::uuid IS NULL OR patient_id = sqlc.narg(patient_id))
+  AND (sqlc.narg(resource_type)::text IS NULL OR resource_type = sqlc.narg(resource_type))
+  AND (sqlc.narg(resource_id)::uuid IS NULL OR resource_id = sqlc.narg(resource_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(occurred_from)::timestamptz IS NULL OR occurred_at >= sqlc.narg(occurred_from))
+  AND (sqlc.narg(occurred_to)::timestamptz IS NULL OR occurred_at < sqlc.narg(occurred_to));
+
+-- name: ListAuditEventsAfterSeq :many
+-- Pages through the chain in order for verification.
+SELECT * FROM audit_events
+WHERE seq > $1
+ORDER BY seq
+LIMIT $2;
+
+-- name: ListPatientAccessLog :many
+-- Every audited touch of a patient or their visits, newest first, with the actor's
+-- current username. Users deleted since keep their ID but lose the name.
+SELECT e.seq, e.occurred_at, e.actor_user_id, u.username AS actor_username, e.actor_role,
+       e.action, e.resource_type, e.resource_id, e.changes, e.client_ip, e.break_glass_grant_id
+FROM audit_events e
+LEFT JOIN users u ON u.id = e.actor_user_id
+WHERE e.patient_id = $1
+ORDER BY e.occurred_at DESC, e.seq DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListPatientAccessLogBeforeSeq :many
+-- Pages through a patient's access log newest first by seq, which new events cannot
+-- shift, for exports.
+SELECT e.seq, e.occurred_at, e.actor_user_id, u.username AS actor_username, e.actor_role,
+       e.action, e.resource_type, e.resource_id, e.changes, e.client_ip, e.break_glass_grant_id
+FROM audit_events e
+LEFT JOIN users u ON u.id = e.actor_user_id
+WHERE e.patient_id = sqlc.arg(patient_id) AND e.seq < sqlc.arg(before_seq)
+ORDER BY e.seq DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountPatientAccessLog :one
+SELECT COUNT(*) FROM audit_events
+WHERE patient_id = $1;
@@ -0,0 +1,181 @@
+-- name: CreateTaxCategory :one
+INSERT INTO tax_categories (
+    code, description, rate_bps
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListTaxCategories :many
+SELECT * FROM tax_categories
+ORDER BY code;
+
+-- name: CreateChargeItem :one
+INSERT INTO charge_items (
+    code, description, unit_price_minor, tax_category_code
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetChargeItemByCode :one
+SELECT ci.*, tc.rate_bps AS tax_rate_bps
+FROM charge_items ci
+JOIN tax_categories tc ON tc.code = ci.tax_category_code
+WHERE ci.code = $1
+LIMIT 1;
+
+-- name: ListChargeItems :many
+SELECT ci.*, tc.rate_bps AS tax_rate_bps
+FROM charge_items ci
+JOIN tax_categories tc ON tc.code = ci.tax_category_code
+WHERE ci.is_active = TRUE
+ORDER BY ci.code
+LIMIT $1
+OFFSET $2;
+
+-- name: CountChargeItems :one
+SELECT COUNT(*) FROM charge_items
+WHERE is_active = TRUE;
+
+-- name: CreateCharge :one
+-- Returns no row when the same source record was already charged for this item.
+INSERT INTO charges (
+    patient_id, visit_id, charge_item_id, source, source_id,
+    description, quantity, unit_price_minor, tax_rate_bps, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (source, source_id, charge_item_id) WHERE source_id IS NOT NULL DO NOTHING
+RETURNING *;
+
+-- name: ListChargesByPatientID :many
+SELECT * FROM charges
+WHERE patient_id = sqlc.arg(patient_id)
+  AND (NOT sqlc.arg(unbilled_only)::boolean OR invoice_id IS NULL)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountChargesByPatientID :one
+SELECT COUNT(*) FROM charges
+WHERE patient_id = sqlc.arg(patient_id)
+  AND (NOT sqlc.arg(unbilled_only)::boolean OR invoice_id IS NULL);
+
+-- name: ListUnbilledChargesByPatientID :many
+SELECT * FROM charges
+WHERE patient_id = $1 AND invoice_id IS NULL
+ORDER BY created_at;
+
+-- name: LockUnbilledCharges :many
+-- An empty charge_ids array selects every unbilled charge of the patient.
+SELECT * FROM charges
+WHERE patient_id = sqlc.arg(patient_id)
+  AND invoice_id IS NULL
+  AND (cardinality(sqlc.arg(charge_ids)::uuid[]) = 0 OR id = ANY(sqlc.arg(charge_ids)::uuid[]))
+ORDER BY created_at
+FOR UPDATE;
+
+-- name: AssignChargeToInvoice :exec
+UPDATE charges
+SET invoice_id = $2
+WHERE id = $1 AND invoice_id IS NULL;
+
+-- name: NextInvoiceSequence :one
+INSERT INTO invoice_counters (fiscal_year, last_number)
+VALUES ($1, 1)
+ON CONFLICT (fiscal_year) DO UPDATE
+SET last_number = invoice_counters.last_number + 1
+RETURNING last_number;
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+    invoice_number, fiscal_year, sequence_number, patient_id,
+    subtotal_minor, discount_minor, tax_minor, total_minor, notes, issued_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: CreateInvoiceLine :one
+INSERT INTO invoice_lines (
+    invoice_id, charge_id, description, quantity, unit_price_minor,
+    discount_minor, tax_rate_bps, tax_minor, line_total_minor
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetInvoiceByID :one
+SELECT * FROM invoices
+WHERE id = $1
+LIMIT 1;
+
+-- name: LockInvoice :one
+SELECT * FROM invoices
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListInvoicesByPatientID :many
+SELECT * FROM invoices
+WHERE patient_id = $1
+ORDER BY issued_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountInvoicesByPatientID :one
+SELECT COUNT(*) FROM invoices
+WHERE patient_id = $1;
+
+-- name: ListInvoiceLines :many
+SELECT * FROM invoice_lines
+WHERE invoice_id = $1
+ORDER BY id;
+
+-- name: UpdateInvoicePayment :one
+UPDATE invoices
+SET paid_minor = $2, status = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePayment :one
+INSERT INTO payments (
+    invoice_id, patient_id, method, amount_minor, reference, received_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: LockPayment :one
+SELECT * FROM payments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPaymentsByInvoiceID :many
+SELECT * FROM payments
+WHERE invoice_id = $1
+ORDER BY received_at;
+
+-- name: CreateRefund :one
+INSERT INTO refunds (
+    payment_id, invoice_id, amount_minor, reason, refunded_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: SumRefundsByPaymentID :one
+SELECT COALESCE(SUM(amount_minor), 0)::bigint FROM refunds
+WHERE payment_id = $1;
+
+-- name: ListRefundsByInvoiceID :many
+SELECT * FROM refunds
+WHERE invoice_id = $1
+ORDER BY refunded_at;
+
+-- name: GetPatientInvoiceTotals :one
+SELECT
+    COALESCE(SUM(total_minor), 0)::bigint AS invoiced_minor,
+    COALESCE(SUM(paid_minor), 0)::bigint AS paid_minor
+FROM invoices
+WHERE patient_id = $1;
@@ -0,0 +1,64 @@
+-- name: CreateBreakGlassGrant :one
+INSERT INTO break_glass_grants (
+    id, user_id, patient_id, reason, granted_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetActiveBreakGlassGrant :one
+-- The grant with the most time left, if the user holds one for the patient at as_of.
+SELECT * FROM break_glass_grants
+WHERE user_id = sqlc.arg(user_id)
+  AND patient_id = sqlc.arg(patient_id)
+  AND revoked_at IS NULL
+  AND granted_at <= sqlc.arg(as_of)
+  AND expires_at > sqlc.arg(as_of)
+ORDER BY expires_at DESC
+LIMIT 1;
+
+-- name: ListBreakGlassGrantsForReview :many
+-- Grants issued in the window, plus older grants that were still being used in it.
+SELECT g.*, u.username
+FROM break_glass_grants g
+JOIN users u ON u.id = g.user_id
+WHERE (g.granted_at >= sqlc.arg(window_start) AND g.granted_at < sqlc.arg(window_end))
+   OR EXISTS (
+       SELECT 1 FROM audit_events e
+       WHERE e.break_glass_grant_id = g.id
+         AND e.occurred_at >= sqlc.arg(window_start)
+         AND e.occurred_at < sqlc.arg(window_end)
+   )
+ORDER BY g.granted_at;
+
+-- name: ListBreakGlassAccesses :many
+SELECT seq, occurred_at, break_glass_grant_id, action, resource_type, resource_id
+FROM audit_events
+WHERE break_glass_grant_id IS NOT NULL
+  AND occurred_at >= sqlc.arg(window_start)
+  AND occurred_at < sqlc.arg(window_end)
+ORDER BY seq;
+
+-- name: CreateBreakGlassReport :one
+-- A report that already exists for the day is kept; the caller then gets no rows.
+INSERT INTO break_glass_reports (
+    report_date, grant_count, access_count, report
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (report_date) DO NOTHING
+RETURNING *;
+
+-- name: GetBreakGlassReport :one
+SELECT * FROM break_glass_reports
+WHERE report_date = $1;
+
+-- name: ListBreakGlassReports :many
+SELECT report_date, generated_at, grant_count, access_count
+FROM break_glass_reports
+ORDER BY report_date DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: CountBreakGlassReports :one
+SELECT COUNT(*) FROM break_glass_reports;
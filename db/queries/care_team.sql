@@ -0,0 +1,29 @@
+-- name: AddCareTeamMember :one
+INSERT INTO care_team_members (
+    patient_id, user_id, assigned_by_user_id
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: RemoveCareTeamMember :one
+DELETE FROM care_team_members
+WHERE patient_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ListCareTeamMembers :many
+SELECT ct.*, u.username, u.first_name, u.last_name, u.role
+FROM care_team_members ct
+JOIN users u ON u.id = ct.user_id
+WHERE ct.patient_id = $1
+ORDER BY ct.assigned_at;
+
+-- name: IsInCareOf :one
+-- Whether the user is on the patient's care team or has recorded a visit for them.
+SELECT EXISTS (
+    SELECT 1 FROM care_team_members ct
+    WHERE ct.patient_id = sqlc.arg(patient_id)::uuid AND ct.user_id = sqlc.arg(user_id)::uuid
+    UNION ALL
+    SELECT 1 FROM patient_visits pv
+    WHERE pv.patient_id = sqlc.arg(patient_id)::uuid AND pv.doctor_id = sqlc.arg(user_id)::uuid
+) AS in_care;
@@ -0,0 +1,42 @@
+-- name: CreateConsentRecord :one
+INSERT INTO consent_records (
+    patient_id, consent_type, text_version, signed_at, witnessed_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetConsentRecordByID :one
+SELECT * FROM consent_records
+WHERE id = $1 AND patient_id = $2;
+
+-- name: GetLatestConsentRecord :one
+-- The most recent record of the type, in force or not.
+SELECT * FROM consent_records
+WHERE patient_id = $1 AND consent_type = $2
+ORDER BY signed_at DESC, created_at DESC
+LIMIT 1;
+
+-- name: ListConsentRecordsByPatientID :many
+SELECT * FROM consent_records
+WHERE patient_id = $1
+ORDER BY signed_at DESC, created_at DESC;
+
+-- name: WithdrawConsentRecord :one
+UPDATE consent_records
+SET
+    withdrawn_at = sqlc.arg(withdrawn_at),
+    withdrawn_by_user_id = sqlc.arg(withdrawn_by_user_id),
+    withdrawal_reason = sqlc.narg(withdrawal_reason)
+WHERE id = sqlc.arg(id) AND withdrawn_at IS NULL
+RETURNING *;
+
+-- name: WithdrawActiveConsent :many
+-- Withdraws the record in force, if any, before a new one is signed.
+UPDATE consent_records
+SET
+    withdrawn_at = sqlc.arg(withdrawn_at),
+    withdrawn_by_user_id = sqlc.arg(withdrawn_by_user_id),
+    withdrawal_reason = sqlc.narg(withdrawal_reason)
+WHERE patient_id = sqlc.arg(patient_id) AND consent_type = sqlc.arg(consent_type) AND withdrawn_at IS NULL
+RETURNING *;
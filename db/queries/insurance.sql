@@ -0,0 +1,99 @@
+-- name: CreateInsurancePolicy :one
+INSERT INTO insurance_policies (
+    patient_id, payer_code, payer_name, plan_name, member_id,
+    group_number, valid_from, valid_to, priority, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetInsurancePolicyByID :one
+SELECT * FROM insurance_policies
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListInsurancePoliciesByPatientID :many
+SELECT * FROM insurance_policies
+WHERE patient_id = $1
+ORDER BY is_active DESC, priority, valid_from DESC;
+
+-- name: DeactivateInsurancePolicy :one
+UPDATE insurance_policies
+SET is_active = FALSE
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdatePolicyEligibility :one
+UPDATE insurance_policies
+SET eligibility_status = $2, eligibility_checked_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateEligibilityCheck :one
+INSERT INTO eligibility_checks (
+    policy_id, status, message, payer_reference, checked_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: CreateInsuranceClaim :one
+INSERT INTO insurance_claims (
+    invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor,
+    payer_reference, submitted_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetInsuranceClaimByID :one
+SELECT * FROM insurance_claims
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListInsuranceClaimsByInvoiceID :many
+SELECT * FROM insurance_claims
+WHERE invoice_id = $1
+ORDER BY submitted_at;
+
+-- name: ListInsuranceClaimsByStatus :many
+SELECT * FROM insurance_claims
+WHERE status = $1
+ORDER BY submitted_at
+LIMIT $2
+OFFSET $3;
+
+-- name: CountInsuranceClaimsByStatus :one
+SELECT COUNT(*) FROM insurance_claims
+WHERE status = $1;
+
+-- name: UpdateInsuranceClaimStatus :one
+-- Only applies when the claim is still in from_status, so concurrent updates cannot both win.
+UPDATE insurance_claims
+SET status = sqlc.arg(status),
+    rejection_reason = sqlc.narg(rejection_reason),
+    paid_minor = COALESCE(sqlc.narg(paid_minor), paid_minor),
+    payer_reference = COALESCE(sqlc.narg(payer_reference), payer_reference)
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: MarkInsuranceClaimSent :one
+-- Records the payer's acknowledgement; a claim keeps the first one.
+UPDATE insurance_claims
+SET sent_to_payer_at = NOW(),
+    payer_reference = COALESCE(sqlc.narg(payer_reference), payer_reference)
+WHERE id = sqlc.arg(id) AND sent_to_payer_at IS NULL
+RETURNING *;
+
+-- name: CreateClaimStatusHistory :one
+INSERT INTO claim_status_history (
+    claim_id, status, note, changed_by_user_id
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListClaimStatusHistory :many
+SELECT * FROM claim_status_history
+WHERE claim_id = $1
+ORDER BY changed_at;
@@ -0,0 +1,138 @@
+-- name: CreateLabTest :one
+INSERT INTO lab_tests (
+    code, name, specimen_type, description, charge_code
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetLabTestByID :one
+SELECT * FROM lab_tests
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetLabTestByCode :one
+SELECT * FROM lab_tests
+WHERE code = $1
+LIMIT 1;
+
+-- name: ListLabTests :many
+SELECT * FROM lab_tests
+WHERE is_active = TRUE
+ORDER BY code
+LIMIT $1
+OFFSET $2;
+
+-- name: CountLabTests :one
+SELECT COUNT(*) FROM lab_tests
+WHERE is_active = TRUE;
+
+-- name: CreateLabAnalyte :one
+INSERT INTO lab_analytes (
+    test_id, code, name, unit, reference_low, reference_high,
+    critical_low, critical_high, display_order
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: ListLabAnalytesByTestID :many
+SELECT * FROM lab_analytes
+WHERE test_id = $1
+ORDER BY display_order, code;
+
+-- name: CreateLabOrder :one
+INSERT INTO lab_orders (
+    visit_id, test_id, ordered_by_user_id, priority, clinical_notes
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetLabOrderByID :one
+SELECT * FROM lab_orders
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListLabOrdersByVisitID :many
+SELECT * FROM lab_orders
+WHERE visit_id = $1
+ORDER BY ordered_at DESC;
+
+-- name: ListLabOrdersByStatus :many
+SELECT * FROM lab_orders
+WHERE status = $1
+ORDER BY
+    CASE priority WHEN 'stat' THEN 0 WHEN 'urgent' THEN 1 ELSE 2 END,
+    ordered_at
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkLabOrderCollected :one
+UPDATE lab_orders
+SET
+    status = 'collected',
+    collected_at = NOW(),
+    collected_by_user_id = $2
+WHERE id = $1 AND status = 'ordered'
+RETURNING *;
+
+-- name: MarkLabOrderResulted :one
+UPDATE lab_orders
+SET
+    status = 'resulted',
+    resulted_at = NOW(),
+    resulted_by_user_id = $2
+WHERE id = $1 AND status IN ('collected', 'resulted')
+RETURNING *;
+
+-- name: MarkLabOrderVerified :one
+UPDATE lab_orders
+SET
+    status = 'verified',
+    verified_at = NOW(),
+    verified_by_user_id = $2
+WHERE id = $1 AND status = 'resulted'
+RETURNING *;
+
+-- name: UpsertLabResult :one
+INSERT INTO lab_results (
+    order_id, analyte_id, value_numeric, value_text, unit,
+    reference_low, reference_high, flag, comment, entered_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (order_id, analyte_id) DO UPDATE
+SET
+    value_numeric = EXCLUDED.value_numeric,
+    value_text = EXCLUDED.value_text,
+    unit = EXCLUDED.unit,
+    reference_low = EXCLUDED.reference_low,
+    reference_high = EXCLUDED.reference_high,
+    flag = EXCLUDED.flag,
+    comment = EXCLUDED.comment,
+    entered_by_user_id = EXCLUDED.entered_by_user_id
+RETURNING *;
+
+-- name: ListLabResultsByOrderID :many
+SELECT lr.*, la.code AS analyte_code, la.name AS analyte_name
+FROM lab_results lr
+JOIN lab_analytes la ON lr.analyte_id = la.id
+WHERE lr.order_id = $1
+ORDER BY la.display_order, la.code;
+
+-- Cumulative view across all visits of a patient, oldest first for trending.
+-- name: ListCumulativeLabResultsByPatientID :many
+SELECT lr.*, la.code AS analyte_code, la.name AS analyte_name,
+    lt.code AS test_code, lo.visit_id, lo.status AS order_status,
+    COALESCE(lo.collected_at, lo.ordered_at)::timestamptz AS observed_at
+FROM lab_results lr
+JOIN lab_orders lo ON lr.order_id = lo.id
+JOIN lab_analytes la ON lr.analyte_id = la.id
+JOIN lab_tests lt ON lo.test_id = lt.id
+JOIN patient_visits pv ON lo.visit_id = pv.id
+WHERE pv.patient_id = sqlc.arg(patient_id)
+  AND (sqlc.narg(analyte_code)::text IS NULL OR la.code = sqlc.narg(analyte_code)::text)
+ORDER BY la.code, observed_at
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
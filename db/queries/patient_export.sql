@@ -0,0 +1,85 @@
+-- name: CreatePatientExportJob :one
+INSERT INTO patient_export_jobs (
+    patient_id, requested_by_user_id
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetPatientExportJob :one
+SELECT * FROM patient_export_jobs
+WHERE id = $1 AND patient_id = $2;
+
+-- name: GetOpenPatientExportJob :one
+SELECT * FROM patient_export_jobs
+WHERE patient_id = $1 AND status IN ('pending', 'running');
+
+-- name: ListPatientExportJobs :many
+SELECT * FROM patient_export_jobs
+WHERE patient_id = $1
+ORDER BY requested_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountPatientExportJobs :one
+SELECT COUNT(*) FROM patient_export_jobs
+WHERE patient_id = $1;
+
+-- name: ClaimPatientExportJob :one
+-- Takes the oldest pending job, or a running one whose worker has not finished by
+-- stale_before (e.g. it crashed), without blocking other workers.
+UPDATE patient_export_jobs
+SET status = 'running', started_at = NOW(), attempts = attempts + 1
+WHERE id = (
+    SELECT queued.id FROM patient_export_jobs queued
+    WHERE queued.status = 'pending' OR (queued.status = 'running' AND queued.started_at < sqlc.arg(stale_before)::timestamptz)
+    ORDER BY queued.requested_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: SavePatientExportArchive :exec
+INSERT INTO patient_export_archives (job_id, data)
+VALUES ($1, $2)
+ON CONFLICT (job_id) DO UPDATE SET data = EXCLUDED.data;
+
+-- name: CompletePatientExportJob :one
+UPDATE patient_export_jobs
+SET status = 'completed', completed_at = NOW(), size_bytes = $2, expires_at = $3, error = NULL
+WHERE id = $1 AND status = 'running'
+RETURNING *;
+
+-- name: FailPatientExportJob :one
+UPDATE patient_export_jobs
+SET status = 'failed', completed_at = NOW(), error = $2
+WHERE id = $1 AND status = 'running'
+RETURNING *;
+
+-- name: GetPatientExportArchive :one
+SELECT data FROM patient_export_archives
+WHERE job_id = $1;
+
+-- name: ExpirePatientExportJobs :execrows
+UPDATE patient_export_jobs
+SET status = 'expired'
+WHERE status = 'completed' AND expires_at <= $1;
+
+-- name: DeleteExpiredPatientExportArchives :execrows
+DELETE FROM patient_export_archives a
+USING patient_export_jobs j
+WHERE a.job_id = j.id AND j.status IN ('expired', 'failed');
+
+-- name: CancelPatientExports :execrows
+-- Withdraws a patient's exports, e.g. once they are pseudonymized: archives already
+-- built expire and queued jobs fail.
+UPDATE patient_export_jobs
+SET
+    status = CASE WHEN status = 'completed' THEN 'expired'::patient_export_status ELSE 'failed'::patient_export_status END,
+    error = CASE WHEN status = 'completed' THEN error ELSE sqlc.arg(reason)::text END,
+    completed_at = COALESCE(completed_at, NOW())
+WHERE patient_id = sqlc.arg(patient_id) AND status IN ('pending', 'running', 'completed');
+
+-- name: DeletePatientExportArchives :execrows
+DELETE FROM patient_export_archives a
+USING patient_export_jobs j
+WHERE a.job_id = j.id AND j.patient_id = $1;
@@ -37,10 +37,28 @@ SET
     diagnosis = COALESCE(sqlc.narg(diagnosis), diagnosis),
     prescription = COALESCE(sqlc.narg(prescription), prescription),
     notes = COALESCE(sqlc.narg(notes), notes),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = sqlc.arg(id)
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(expected_version)
 RETURNING *;
 
 -- name: DeletePatientVisit :exec
 DELETE FROM patient_visits
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListPatientVisitsForReencryption :many
+SELECT id, symptoms, diagnosis, prescription, notes, updated_at
+FROM patient_visits
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ReencryptPatientVisit :execrows
+-- Leaves updated_at alone and skips the row if it changed since it was read.
+UPDATE patient_visits
+SET
+    symptoms = sqlc.narg(symptoms),
+    diagnosis = sqlc.narg(diagnosis),
+    prescription = sqlc.narg(prescription),
+    notes = sqlc.narg(notes)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(read_updated_at);
@@ -2,9 +2,9 @@
 INSERT INTO patients (
     first_name, last_name, date_of_birth, gender,
     contact_phone, contact_email, address, medical_history,
-    registered_by_user_id
+    registered_by_user_id, contact_phone_bidx, contact_email_bidx
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -13,6 +13,16 @@ SELECT * FROM patients
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1;
 
+-- name: GetPatientByContactPhoneIndex :one
+SELECT * FROM patients
+WHERE contact_phone_bidx = $1 AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetPatientByContactEmailIndex :one
+SELECT * FROM patients
+WHERE contact_email_bidx = $1 AND deleted_at IS NULL
+LIMIT 1;
+
 -- name: ListPatients :many
 SELECT * FROM patients
 WHERE deleted_at IS NULL
@@ -20,6 +30,26 @@ ORDER BY last_name, first_name
 LIMIT $1
 OFFSET $2;
 
+-- name: ListPatientsInCareOf :many
+-- Patients the user is on the care team of or has recorded a visit for.
+SELECT * FROM patients p
+WHERE p.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM care_team_members ct WHERE ct.patient_id = p.id AND ct.user_id = sqlc.arg(user_id))
+    OR EXISTS (SELECT 1 FROM patient_visits pv WHERE pv.patient_id = p.id AND pv.doctor_id = sqlc.arg(user_id))
+  )
+ORDER BY p.last_name, p.first_name
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountPatientsInCareOf :one
+SELECT COUNT(*) FROM patients p
+WHERE p.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM care_team_members ct WHERE ct.patient_id = p.id AND ct.user_id = sqlc.arg(user_id))
+    OR EXISTS (SELECT 1 FROM patient_visits pv WHERE pv.patient_id = p.id AND pv.doctor_id = sqlc.arg(user_id))
+  );
+
 -- name: UpdatePatient :one
 UPDATE patients
 SET
@@ -29,15 +59,18 @@ SET
     gender = COALESCE(sqlc.narg(gender), gender),
     contact_phone = COALESCE(sqlc.narg(contact_phone), contact_phone),
     contact_email = COALESCE(sqlc.narg(contact_email), contact_email),
+    contact_phone_bidx = COALESCE(sqlc.narg(contact_phone_bidx), contact_phone_bidx),
+    contact_email_bidx = COALESCE(sqlc.narg(contact_email_bidx), contact_email_bidx),
     address = COALESCE(sqlc.narg(address), address),
     medical_history = COALESCE(sqlc.narg(medical_history), medical_history),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND version = sqlc.arg(expected_version)
 RETURNING *;
 
 -- name: SoftDeletePatient :one
 UPDATE patients
-SET deleted_at = NOW(), updated_at = NOW()
+SET deleted_at = NOW(), version = version + 1, updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
@@ -50,10 +83,30 @@ WHERE id = $1;
 UPDATE patients
 SET
     medical_history = $2,
+    version = version + 1,
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: CountPatients :one
 SELECT COUNT(*) FROM patients
-WHERE deleted_at IS NULL;
\ No newline at end of file
+WHERE deleted_at IS NULL;
+
+-- name: ListPatientsForReencryption :many
+-- Includes soft-deleted patients: their data is still at rest.
+SELECT id, contact_phone, contact_email, medical_history, contact_phone_bidx, contact_email_bidx, updated_at
+FROM patients
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ReencryptPatient :execrows
+-- Leaves updated_at alone and skips the row if it changed since it was read.
+UPDATE patients
+SET
+    contact_phone = sqlc.narg(contact_phone),
+    contact_email = sqlc.narg(contact_email),
+    medical_history = sqlc.narg(medical_history),
+    contact_phone_bidx = sqlc.narg(contact_phone_bidx),
+    contact_email_bidx = sqlc.narg(contact_email_bidx)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(read_updated_at);
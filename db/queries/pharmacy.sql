@@ -0,0 +1,176 @@
+-- name: CreatePharmacyLocation :one
+INSERT INTO pharmacy_locations (
+    code, name
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetPharmacyLocationByID :one
+SELECT * FROM pharmacy_locations
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPharmacyLocations :many
+SELECT * FROM pharmacy_locations
+WHERE is_active = TRUE
+ORDER BY code;
+
+-- name: CreatePharmacyItem :one
+INSERT INTO pharmacy_items (
+    code, name, dosage_form, strength, unit, reorder_level
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetPharmacyItemByID :one
+SELECT * FROM pharmacy_items
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPharmacyItems :many
+SELECT * FROM pharmacy_items
+WHERE is_active = TRUE
+ORDER BY name
+LIMIT $1
+OFFSET $2;
+
+-- name: CountPharmacyItems :one
+SELECT COUNT(*) FROM pharmacy_items
+WHERE is_active = TRUE;
+
+-- name: UpsertPharmacyBatch :one
+-- Receiving a lot that is already known returns the existing batch unchanged.
+INSERT INTO pharmacy_batches (
+    item_id, lot_number, expiry_date
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (item_id, lot_number) DO UPDATE SET lot_number = EXCLUDED.lot_number
+RETURNING *;
+
+-- name: GetPharmacyBatchByID :one
+SELECT * FROM pharmacy_batches
+WHERE id = $1 LIMIT 1;
+
+-- name: ApplyStockDelta :one
+-- The CHECK on quantity rejects a delta that would take the level below zero.
+INSERT INTO stock_levels (
+    location_id, batch_id, quantity
+) VALUES (
+    sqlc.arg(location_id), sqlc.arg(batch_id), sqlc.arg(delta)
+)
+ON CONFLICT (location_id, batch_id) DO UPDATE
+SET quantity = stock_levels.quantity + EXCLUDED.quantity,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: LockItemStockAtLocation :many
+-- Candidate batches for FEFO selection, earliest expiry first.
+SELECT sl.batch_id, sl.quantity, b.lot_number, b.expiry_date
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+WHERE sl.location_id = $1
+  AND b.item_id = $2
+  AND sl.quantity > 0
+ORDER BY b.expiry_date, b.created_at
+FOR UPDATE OF sl;
+
+-- name: GetItemStockOnHand :one
+-- Unexpired quantity of an item at a location.
+SELECT COALESCE(SUM(sl.quantity), 0)::bigint AS on_hand
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+WHERE sl.location_id = sqlc.arg(location_id)
+  AND b.item_id = sqlc.arg(item_id)
+  AND b.expiry_date >= sqlc.arg(as_of)::date;
+
+-- name: ListStockLevels :many
+SELECT sl.location_id, pl.code AS location_code, sl.batch_id, b.lot_number, b.expiry_date,
+       i.id AS item_id, i.code AS item_code, i.name AS item_name, i.unit, sl.quantity, sl.updated_at
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+JOIN pharmacy_locations pl ON pl.id = sl.location_id
+WHERE sl.quantity > 0
+  AND (sqlc.narg(location_id)::uuid IS NULL OR sl.location_id = sqlc.narg(location_id))
+  AND (sqlc.narg(item_id)::uuid IS NULL OR i.id = sqlc.narg(item_id))
+ORDER BY i.name, pl.code, b.expiry_date;
+
+-- name: ListNearExpiryStock :many
+-- Stock on hand expiring on or before the cutoff, including lots already expired.
+SELECT sl.location_id, pl.code AS location_code, sl.batch_id, b.lot_number, b.expiry_date,
+       i.id AS item_id, i.code AS item_code, i.name AS item_name, i.unit, sl.quantity, sl.updated_at
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+JOIN pharmacy_locations pl ON pl.id = sl.location_id
+WHERE sl.quantity > 0
+  AND b.expiry_date <= sqlc.arg(cutoff)::date
+  AND (sqlc.narg(location_id)::uuid IS NULL OR sl.location_id = sqlc.narg(location_id))
+ORDER BY b.expiry_date, i.name, pl.code;
+
+-- name: ListItemsBelowReorderLevel :many
+-- Active items whose unexpired stock at an active location is at or below the reorder level.
+SELECT i.id AS item_id, i.code AS item_code, i.name AS item_name, i.reorder_level,
+       pl.id AS location_id, pl.code AS location_code,
+       COALESCE(SUM(sl.quantity) FILTER (WHERE b.expiry_date >= sqlc.arg(as_of)::date), 0)::bigint AS on_hand
+FROM pharmacy_items i
+CROSS JOIN pharmacy_locations pl
+LEFT JOIN pharmacy_batches b ON b.item_id = i.id
+LEFT JOIN stock_levels sl ON sl.batch_id = b.id AND sl.location_id = pl.id
+WHERE i.is_active = TRUE
+  AND pl.is_active = TRUE
+  AND i.reorder_level > 0
+  AND (sqlc.narg(location_id)::uuid IS NULL OR pl.id = sqlc.narg(location_id))
+GROUP BY i.id, pl.id
+HAVING COALESCE(SUM(sl.quantity) FILTER (WHERE b.expiry_date >= sqlc.arg(as_of)::date), 0) <= i.reorder_level
+ORDER BY pl.code, i.name;
+
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+    movement_type, location_id, batch_id, quantity, visit_id,
+    returned_movement_id, reference, reason, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: LockStockMovement :one
+-- Serialises returns against the same dispense.
+SELECT * FROM stock_movements
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: SumReturnedQuantity :one
+SELECT COALESCE(SUM(quantity), 0)::bigint AS returned
+FROM stock_movements
+WHERE returned_movement_id = $1;
+
+-- name: ListStockMovements :many
+SELECT sm.*, b.lot_number, i.id AS item_id, i.code AS item_code
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+WHERE (sqlc.narg(location_id)::uuid IS NULL OR sm.location_id = sqlc.narg(location_id))
+  AND (sqlc.narg(item_id)::uuid IS NULL OR i.id = sqlc.narg(item_id))
+  AND (sqlc.narg(batch_id)::uuid IS NULL OR sm.batch_id = sqlc.narg(batch_id))
+ORDER BY sm.created_at DESC, sm.id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountStockMovements :one
+SELECT COUNT(*)
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+WHERE (sqlc.narg(location_id)::uuid IS NULL OR sm.location_id = sqlc.narg(location_id))
+  AND (sqlc.narg(item_id)::uuid IS NULL OR b.item_id = sqlc.narg(item_id))
+  AND (sqlc.narg(batch_id)::uuid IS NULL OR sm.batch_id = sqlc.narg(batch_id));
+
+-- name: ListVisitStockMovements :many
+-- Dispenses against a visit and the returns made against them.
+SELECT sm.*, b.lot_number, i.id AS item_id, i.code AS item_code
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+WHERE sm.visit_id = $1
+ORDER BY sm.created_at, sm.id;
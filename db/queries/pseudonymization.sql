@@ -0,0 +1,67 @@
+-- name: CreatePseudonymizationRequest :one
+INSERT INTO pseudonymization_requests (
+    patient_id, requested_by_user_id, reason
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetPseudonymizationRequest :one
+SELECT * FROM pseudonymization_requests
+WHERE id = $1;
+
+-- name: LockPseudonymizationRequest :one
+SELECT * FROM pseudonymization_requests
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPseudonymizationRequests :many
+SELECT * FROM pseudonymization_requests
+WHERE sqlc.narg(status)::pseudonymization_status IS NULL OR status = sqlc.narg(status)::pseudonymization_status
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountPseudonymizationRequests :one
+SELECT COUNT(*) FROM pseudonymization_requests
+WHERE sqlc.narg(status)::pseudonymization_status IS NULL OR status = sqlc.narg(status)::pseudonymization_status;
+
+-- name: DecidePseudonymizationRequest :one
+UPDATE pseudonymization_requests
+SET
+    status = sqlc.arg(status),
+    decided_by_user_id = sqlc.arg(decided_by_user_id),
+    decided_at = NOW(),
+    decision_note = sqlc.narg(decision_note)
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: LockPatient :one
+SELECT * FROM patients
+WHERE id = $1
+FOR UPDATE;
+
+-- name: PseudonymizePatient :one
+-- Replaces the direct identifiers. Clinical fields and the row ID are kept.
+UPDATE patients
+SET
+    first_name = sqlc.arg(first_name),
+    last_name = sqlc.arg(last_name),
+    date_of_birth = sqlc.arg(date_of_birth),
+    contact_phone = NULL,
+    contact_phone_bidx = NULL,
+    contact_email = NULL,
+    contact_email_bidx = NULL,
+    address = NULL,
+    pseudonymized_at = NOW(),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND pseudonymized_at IS NULL
+RETURNING *;
+
+-- name: PseudonymizePatientPolicies :execrows
+-- Member IDs are required, so they take the patient's token; group numbers are cleared.
+UPDATE insurance_policies
+SET
+    member_id = sqlc.arg(member_id),
+    group_number = NULL
+WHERE patient_id = sqlc.arg(patient_id);
@@ -0,0 +1,16 @@
+-- name: ListResearchPatients :many
+-- Patients with research consent in force, in ID order for keyset paging.
+SELECT p.* FROM patients p
+WHERE p.deleted_at IS NULL
+  AND p.id > sqlc.arg(after_id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM consent_records cr
+    WHERE cr.patient_id = p.id AND cr.consent_type = 'research' AND cr.withdrawn_at IS NULL
+  )
+ORDER BY p.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListVisitsForPatients :many
+SELECT * FROM patient_visits
+WHERE patient_id = ANY(sqlc.arg(patient_ids)::uuid[])
+ORDER BY patient_id, visit_date, id;
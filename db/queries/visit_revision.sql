@@ -0,0 +1,50 @@
+-- name: LockPatientVisit :one
+SELECT * FROM patient_visits
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CreatePatientVisitRevision :one
+INSERT INTO patient_visit_revisions (
+    visit_id, version, kind, reason, author_user_id,
+    previous_visit_date, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: ListPatientVisitRevisions :many
+SELECT * FROM patient_visit_revisions
+WHERE visit_id = $1
+ORDER BY version;
+
+-- name: FinalizePatientVisit :one
+UPDATE patient_visits
+SET
+    finalized_at = NOW(),
+    finalized_by_user_id = sqlc.arg(finalized_by_user_id),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND finalized_at IS NULL AND version = sqlc.arg(expected_version)
+RETURNING *;
+
+-- name: ListPatientVisitRevisionsForReencryption :many
+SELECT id, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes
+FROM patient_visit_revisions
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ReencryptPatientVisitRevision :execrows
+-- Revisions never change otherwise, so the row is rewritten only if the ciphertext read
+-- is still there.
+UPDATE patient_visit_revisions
+SET
+    previous_symptoms = sqlc.narg(previous_symptoms),
+    previous_diagnosis = sqlc.narg(previous_diagnosis),
+    previous_prescription = sqlc.narg(previous_prescription),
+    previous_notes = sqlc.narg(previous_notes)
+WHERE id = sqlc.arg(id)
+  AND previous_symptoms IS NOT DISTINCT FROM sqlc.narg(read_symptoms)
+  AND previous_diagnosis IS NOT DISTINCT FROM sqlc.narg(read_diagnosis)
+  AND previous_prescription IS NOT DISTINCT FROM sqlc.narg(read_prescription)
+  AND previous_notes IS NOT DISTINCT FROM sqlc.narg(read_notes);
@@ -15,129 +15,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Log in as a user.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "User login",
-                "parameters": [
-                    {
-                        "description": "Login Data",
-                        "name": "loginRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.LoginResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation error or invalid input",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Create a new user",
-                "parameters": [
-                    {
-                        "description": "User Registration Data",
-                        "name": "userRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UserCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/model.User"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation error or invalid input",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    },
-                    "409": {
-                        "description": "User already exists",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    }
-                }
-            }
-        },
-        "/patients": {
+        "/alerts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Receptionists and Doctors can list all registered patients.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns critical alerts currently assigned to the authenticated doctor, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Alerts"
                 ],
-                "summary": "List all registered patients",
+                "summary": "List alerts assigned to the current doctor",
                 "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "acknowledged"
+                        ],
+                        "type": "string",
+                        "description": "Alert status",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "maximum": 100,
                         "minimum": 1,
@@ -167,7 +70,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/model.Patient"
+                                                "$ref": "#/definitions/model.Alert"
                                             }
                                         }
                                     }
@@ -176,7 +79,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid pagination parameters",
+                        "description": "Invalid status or pagination parameters",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -187,6 +90,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -196,52 +105,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/patients/create": {
-            "post": {
+        "/alerts/rules": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    },
-                    {
-                        "BearerToken": []
                     }
                 ],
-                "description": "Doctors can register a new patient.",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
-                ],
-                "summary": "Register a new patient",
-                "parameters": [
-                    {
-                        "description": "Patient Registration Data",
-                        "name": "patientRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.PatientCreateRequest"
-                        }
-                    }
+                    "Alerts"
                 ],
+                "summary": "List active critical alert rules",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/model.Patient"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation error or invalid input",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIError"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AlertRule"
+                            }
                         }
                     },
                     "401": {
@@ -257,16 +142,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/patients/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Receptionists and Doctors can get details of a specific patient.",
+                "description": "Doctors can add a critical low/high threshold for an observation or analyte code, optionally narrowed by sex and age. The most specific matching rule is used.",
                 "consumes": [
                     "application/json"
                 ],
@@ -274,27 +157,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Alerts"
                 ],
-                "summary": "Get details of a specific patient",
+                "summary": "Configure a critical alert rule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Patient ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Alert rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertRuleCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Patient"
+                            "$ref": "#/definitions/model.AlertRule"
                         }
                     },
                     "400": {
-                        "description": "Invalid patient ID format",
+                        "description": "Validation error or invalid input",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -305,8 +190,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "404": {
-                        "description": "Patient not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -318,34 +203,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/alerts/rules/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Only doctors can delete patient records",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Patients"
+                    "Alerts"
                 ],
-                "summary": "Delete a patient record",
+                "summary": "Deactivate a critical alert rule",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Patient ID (UUID)",
+                        "description": "Alert rule ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Patient record deleted successfully"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertRule"
+                        }
                     },
                     "400": {
-                        "description": "Validation error, invalid input, or invalid patient ID",
+                        "description": "Invalid alert rule ID format",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -357,70 +249,54 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden (e.g., if trying to update restricted fields)",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
                     "404": {
-                        "description": "Patient not found",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Alert rule not found",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/alerts/{id}/acknowledge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Receptionists can update most patient details. Doctors can update patient details, especially medical history.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The assigned doctor or the visit's original doctor acknowledges the alert, which stops escalation.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Patients"
+                    "Alerts"
                 ],
-                "summary": "Update patient details",
+                "summary": "Acknowledge a critical alert",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Patient ID (UUID)",
+                        "description": "Alert ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Patient Update Data (fields to update)",
-                        "name": "patientRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.PatientUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Patient"
+                            "$ref": "#/definitions/model.Alert"
                         }
                     },
                     "400": {
-                        "description": "Validation error, invalid input, or invalid patient ID",
+                        "description": "Invalid alert ID format",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -432,19 +308,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden (e.g., if trying to update restricted fields)",
+                        "description": "Not assigned to this alert",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
                     "404": {
-                        "description": "Patient not found",
+                        "description": "Alert not found",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "409": {
+                        "description": "Alert already acknowledged",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -452,41 +328,120 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits/create": {
-            "post": {
+        "/audit/events": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Doctors can record a visit for a patient.",
+                "description": "Admins only. Events are returned newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Audit"
                 ],
-                "summary": "Record a visit for a patient",
+                "summary": "Search the audit trail",
                 "parameters": [
                     {
-                        "description": "Visit details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.PatientVisitCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/model.PatientVisit"
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Acting user ID (UUID)",
+                        "name": "actor_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "patient_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "patient",
+                            "visit",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Resource ID (UUID)",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "read",
+                            "list",
+                            "update",
+                            "delete",
+                            "login",
+                            "login_failed"
+                        ],
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest occurrence (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest occurrence (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AuditEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid filter or pagination parameters",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -497,14 +452,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "404": {
-                        "description": "Patient not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
                     "500": {
-                        "description": "Failed to record patient visit",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -512,40 +467,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits/{id}": {
+        "/audit/verify": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Doctors and Receptionists can get details of a specific patient visit.",
+                "description": "Admins only. Re-computes every event hash from the first event and reports the first broken link, if any.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Audit"
                 ],
-                "summary": "Get details of a specific patient visit",
+                "summary": "Verify the audit trail has not been tampered with",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AuditChainVerification"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Log in as a user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Visit ID (UUID) for which to get details",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Login Data",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.PatientVisit"
+                            "$ref": "#/definitions/model.LoginResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid visit ID format",
+                        "description": "Validation error or invalid input",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -556,68 +553,66 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "404": {
-                        "description": "Visit not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Doctors can update patient visit details they recorded. Doctor ID is taken from authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Auth"
                 ],
-                "summary": "Update a specific patient visit",
+                "summary": "Create a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Visit ID (UUID) for which to update details",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patient visit details to update",
-                        "name": "visit",
+                        "description": "User Registration Data",
+                        "name": "userRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.PatientVisitUpdateRequest"
+                            "$ref": "#/definitions/model.UserCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.PatientVisit"
+                            "$ref": "#/definitions/model.User"
                         }
                     },
                     "400": {
-                        "description": "Validation failed",
+                        "description": "Validation error or invalid input",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "User already exists",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
                     },
-                    "404": {
-                        "description": "Visit not found",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -625,42 +620,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits/{id}/list": {
+        "/billing/charge-items": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Doctors and Receptionists can list patient visits for a specific patient.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Billing"
                 ],
-                "summary": "List patient visits for a specific patient",
+                "summary": "List the charge master",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Patient ID (UUID) for which to list visits",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "maximum": 100,
                         "minimum": 1,
                         "type": "integer",
-                        "description": "Added validation",
+                        "description": "Limit (default: 10)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
-                        "minimum": 0,
                         "type": "integer",
-                        "description": "Added validation",
+                        "description": "Offset (default: 0)",
                         "name": "offset",
                         "in": "query"
                     }
@@ -669,11 +654,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.PaginatedResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ChargeItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid patient ID format",
+                        "description": "Invalid pagination parameters",
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
@@ -683,316 +683,8646 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIError"
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "model.APIError": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "description": "Can be map[string]string for validation errors, or a simple string"
-                },
-                "message": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.Gender": {
-            "type": "string",
-            "enum": [
-                "male",
-                "female",
-                "other"
-            ],
-            "x-enum-varnames": [
-                "GenderMale",
-                "GenderFemale",
-                "GenderOther"
-            ]
-        },
-        "model.LoginRequest": {
-            "type": "object",
-            "required": [
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Prices are integer minor units (e.g. paise). Use code CONSULT to bill every recorded visit automatically.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Add an item to the charge master",
+                "parameters": [
+                    {
+                        "description": "Charge item",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChargeItemCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChargeItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unknown tax category",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Charge item already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/charges": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Visits and lab orders are charged automatically; use this for procedures and other billable services.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Post a procedure or manual charge to a visit",
+                "parameters": [
+                    {
+                        "description": "Charge",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChargeCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Charge"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unknown charge code",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/invoices": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bills the given unbilled charges (default: all of them) with an optional percentage discount. Invoice numbers are gapless per fiscal year.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Issue an invoice",
+                "parameters": [
+                    {
+                        "description": "Invoice",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.InvoiceCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or charge not billable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "No unbilled charges",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/invoices/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Get an invoice with its lines, payments and refunds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Invoice ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid invoice ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/invoices/{id}/claims": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "List claims raised from an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Invoice ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.InsuranceClaim"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid invoice ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/invoices/{id}/payments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts full or partial cash, card or insurance payments up to the outstanding balance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Record a payment against an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Invoice ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PaymentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment exceeds balance",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/payments/{id}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refunds part or all of a payment; the invoice balance is reopened by the refunded amount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Refund a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payment ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefundCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Refund exceeds payment",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/billing/tax-categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "List tax categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TaxCategory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rates are in basis points (1800 = 18%).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Add a tax category",
+                "parameters": [
+                    {
+                        "description": "Tax category",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TaxCategoryCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TaxCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Tax category already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/break-glass/reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers only. Newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Break Glass"
+                ],
+                "summary": "List daily break-glass review reports",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BreakGlassReportSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/break-glass/reports/{date}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers only. Lists the grants issued or used on the UTC day with the justification and every access made under each. Reports are generated shortly after midnight UTC, or on first request for an earlier day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Break Glass"
+                ],
+                "summary": "Get the break-glass review for one day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Day (YYYY-MM-DD, UTC)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BreakGlassReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The day is not over yet",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/claims": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "List claims by status",
+                "parameters": [
+                    {
+                        "enum": [
+                            "submitted",
+                            "accepted",
+                            "rejected",
+                            "paid"
+                        ],
+                        "type": "string",
+                        "description": "Claim status (default: submitted)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.InsuranceClaim"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Claims the invoice's outstanding balance from the policy's payer with the given ICD-10 diagnosis codes. The claim is saved before it is sent; when the payer cannot be reached the 502 names the claim, which can be sent again with POST /claims/{id}/send. Refused with 403 unless the patient's data_sharing consent is in force.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Generate and submit a claim from an invoice",
+                "parameters": [
+                    {
+                        "description": "Claim",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.InsuranceClaimCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsuranceClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, invalid diagnosis code or policy not applicable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice or policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Open claim exists or nothing to claim",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "502": {
+                        "description": "Payer unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "No payer gateway configured",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/claims/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Get a claim with its status history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Claim ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsuranceClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid claim ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Claim not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/claims/{id}/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The claim keeps its ID, which the payer uses to recognise a claim it already has. Refused with 403 unless the patient's data_sharing consent is in force.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Send a claim the payer has not acknowledged again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Claim ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsuranceClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid claim ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Claim not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Claim already acknowledged by the payer",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "502": {
+                        "description": "Payer unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "No payer gateway configured",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/claims/{id}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a claim to accepted, rejected (with reason) or paid. Paid claims post an insurance payment to the invoice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Record the payer's response to a claim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Claim ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClaimStatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsuranceClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Claim not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/consent-types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consent"
+                ],
+                "summary": "List the consent types a patient can give",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsentTypeInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/insurance-policies/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Deactivate an insurance policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Policy ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsurancePolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid policy ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/insurance-policies/{id}/eligibility": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the payer's answer on the policy. An unreachable payer yields status \"unknown\". Refused with 403 unless the patient's data_sharing consent is in force.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Verify a policy's eligibility with the payer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Policy ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Date of service",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.EligibilityCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.EligibilityCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "No payer gateway configured",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lab technicians can list orders in a given status (default: ordered), STAT first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "List lab orders by status",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ordered",
+                            "collected",
+                            "resulted",
+                            "verified"
+                        ],
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.LabOrder"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors can order a lab test from the catalog against a visit of a patient in their care; others get 404.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Order a lab test for a visit",
+                "parameters": [
+                    {
+                        "description": "Lab order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrderCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit or test not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors get orders of patients in their care; others get 404. Roles other than lab technician see the order without clinical notes or results.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Get a lab order with its results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Lab order ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid lab order ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lab order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/orders/{id}/collect": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Mark a lab order's specimen as collected",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Lab order ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid lab order ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lab order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is not awaiting collection",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/orders/{id}/results": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lab technicians record one value per analyte; results are flagged against reference and critical ranges.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Enter results for a lab order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Lab order ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Analyte results",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LabResultEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or unknown analyte",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lab order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is not awaiting results",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/orders/{id}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Verify the results of a lab order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Lab order ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid lab order ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Lab order not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Order has no results or is not awaiting verification",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lab/tests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Authenticated users can list active lab tests with their analytes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "List the lab test catalog",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.LabTest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lab technicians can add a test with its analytes, units and reference/critical ranges.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Add a test to the lab catalog",
+                "parameters": [
+                    {
+                        "description": "Lab test definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LabTestCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.LabTest"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Test code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors list the patients in their care. Other roles list all patients, demographics only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "List all registered patients",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Patient"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Doctors can register a new patient.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Register a new patient",
+                "parameters": [
+                    {
+                        "description": "Patient Registration Data",
+                        "name": "patientRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Patient"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exact match after normalising: email case and surrounding spaces are ignored, and phone numbers are compared on their digits and leading \"+\". Give exactly one of phone or email. The same scoping and redaction as getting a patient by ID apply.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Find a patient by phone number or email address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact phone number",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact email address",
+                        "name": "email",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Patient"
+                        }
+                    },
+                    "400": {
+                        "description": "Neither or both of phone and email given",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors see patients in their care (care team, own visits or break-glass grant); others get 404. Other roles see demographics only, without medical_history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Get details of a specific patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Patient"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the patient; send it as If-Match when updating"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only doctors can delete patient records",
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Delete a patient record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Patient record deleted successfully"
+                    },
+                    "400": {
+                        "description": "Validation error, invalid input, or invalid patient ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (e.g., if trying to update restricted fields)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists can update most patient details. Doctors can update patient details, especially medical history. Send the ETag of the patient as If-Match; if someone else updated the patient since, nothing is changed and the current patient is returned with 412.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patients"
+                ],
+                "summary": "Update patient details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from the last GET, or * to update whatever version is current",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Patient Update Data (fields to update)",
+                        "name": "patientRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Patient"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the patient"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, invalid input, or invalid patient ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (e.g., if trying to update restricted fields)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone or email taken, or the patient has been pseudonymized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "412": {
+                        "description": "Patient changed since the If-Match version; the current patient, with its ETag",
+                        "schema": {
+                            "$ref": "#/definitions/model.Patient"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/access-log": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers only. Lists every audited read or change of the patient and their visits, newest first, with the fields written. With ` + "`" + `format=csv` + "`" + ` the whole log is downloaded as CSV and limit/offset are ignored.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "List who accessed a patient's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AccessLogEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID, format or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Outstanding balance on issued invoices plus the value of charges not yet invoiced.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "Get a patient's running balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/break-glass": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors only. Issues a time-boxed grant (60 minutes unless asked otherwise, at most 240) to the calling doctor. The justification is recorded in the audit trail, every access made under the grant is flagged, and the grant appears in the daily compliance review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Break Glass"
+                ],
+                "summary": "Break the glass: get emergency access to a patient's chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Justification",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BreakGlassRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.BreakGlassGrant"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/care-team": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Team"
+                ],
+                "summary": "List a patient's care team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CareTeamMember"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists, or doctors for patients already in their care. Care team doctors can see the patient's full record.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Team"
+                ],
+                "summary": "Assign a doctor to a patient's care team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Doctor to assign",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CareTeamMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.CareTeamMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or the user is not a doctor",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already on the care team",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/care-team/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The doctor keeps access to patients they have recorded visits for.",
+                "tags": [
+                    "Care Team"
+                ],
+                "summary": "Remove a doctor from a patient's care team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID (UUID)",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found or user not on the care team",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/charges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "List a patient's charges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only charges not yet invoiced",
+                        "name": "unbilled",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Charge"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every consent the patient signed, newest first, including withdrawn ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consent"
+                ],
+                "summary": "List a patient's consents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsentRecord"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists and doctors. The calling user is recorded as the witness. A consent of the same type already in force is withdrawn as superseded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consent"
+                ],
+                "summary": "Record a consent signed by a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signed consent",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsentRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Consent changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/consents/{consentId}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists and doctors. The calling user is recorded as having taken the withdrawal.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consent"
+                ],
+                "summary": "Record that a patient withdrew a consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Consent record ID (UUID)",
+                        "name": "consentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsentWithdrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsentRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient or consent not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. Newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patient Export"
+                ],
+                "summary": "List a patient's data exports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PatientExport"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers, to answer a subject access request. The archive is built in the background; poll the export until it is completed, then follow download_url. While an export of the patient is pending or running, that export is returned. Refused with 403 unless the patient's data_sharing consent is in force.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patient Export"
+                ],
+                "summary": "Request an export of everything held about a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, or consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/export/{exportId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Patient Export"
+                ],
+                "summary": "Get the status of a patient data export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Export ID (UUID)",
+                        "name": "exportId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/export/{exportId}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. A ZIP of JSON files with everything held about the patient and report.html, a readable version for the patient. Available until the export's expires_at, while the patient's data_sharing consent is in force.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Patient Export"
+                ],
+                "summary": "Download a patient data export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Export ID (UUID)",
+                        "name": "exportId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, or consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Export not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "410": {
+                        "description": "Export expired",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/insurance-policies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists can list the policies they add and claim against.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "List a patient's insurance policies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.InsurancePolicy"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "A patient may have one active primary and one active secondary policy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insurance"
+                ],
+                "summary": "Add an insurance policy to a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.InsurancePolicyCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.InsurancePolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Priority already taken",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Billing"
+                ],
+                "summary": "List a patient's invoices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Invoice"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/lab-results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists all results across the patient's visits, grouped by analyte and ordered by time for trending. Doctors list patients in their care; others get 404. Roles other than lab technician get an empty list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "Cumulative lab results for a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Restrict to one analyte code",
+                        "name": "analyte",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.CumulativeLabResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/notifications/sms": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receptionists and doctors. Refused with 403 unless the patient's sms_contact consent is in force.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Send an SMS to a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SMSNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.NotificationReceipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or the patient has no contact phone",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, or consent missing or withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/pseudonymization": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. Records an erasure request; nothing changes until an admin other than the requester approves it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "Request that a patient be pseudonymized",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already pseudonymized or a request is waiting",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/pseudonymization/preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. A dry run listing the identifiers that would be replaced or cleared and what stays linked to the patient. Nothing is changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "Preview pseudonymizing a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Patient already pseudonymized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/adjustments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a counted correction, breakage or write-off. A negative quantity removes stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Adjust a batch's stock",
+                "parameters": [
+                    {
+                        "description": "Adjustment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StockAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Location or batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Adjustment would take stock below zero",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List the formulary",
+                "parameters": [
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PharmacyItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Add a drug to the formulary",
+                "parameters": [
+                    {
+                        "description": "Item",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PharmacyItemCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PharmacyItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/locations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List active pharmacy store locations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PharmacyLocation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Add a pharmacy store location",
+                "parameters": [
+                    {
+                        "description": "Location",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PharmacyLocationCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PharmacyLocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List the stock movement ledger",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Location ID (UUID)",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Item ID (UUID)",
+                        "name": "item_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Batch ID (UUID)",
+                        "name": "batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.StockMovement"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/movements/{id}/returns": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Puts part or all of a dispense back into the batch and location it came from.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Return dispensed stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Dispense movement ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Return",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StockReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or movement is not a dispense",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Return exceeds the quantity dispensed",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/near-expiry": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists stock on hand expiring within the given number of days, including lots already expired.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Report stock nearing expiry",
+                "parameters": [
+                    {
+                        "maximum": 730,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Window in days (default: 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Location ID (UUID)",
+                        "name": "location_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockLevel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/receipts": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Books delivered lots into a store location. Lots already known for an item must carry the same expiry date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Record a goods receipt",
+                "parameters": [
+                    {
+                        "description": "Goods receipt",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GoodsReceiptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, invalid input or expired lot",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Location or item not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Lot received with a different expiry date",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/reorder-alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only unexpired stock counts towards the on-hand quantity.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List items at or below their reorder level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Location ID (UUID)",
+                        "name": "location_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ReorderAlert"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacy/stock": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List stock on hand by batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Location ID (UUID)",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Item ID (UUID)",
+                        "name": "item_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockLevel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pseudonymization-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. Newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "List pseudonymization requests",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PseudonymizationRequest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pseudonymization-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "Get a pseudonymization request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Request ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pseudonymization-requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins other than the requester. Replaces the patient's name with a random token, truncates the date of birth to the year and clears contact details and address. Insurance member IDs take the same token and group numbers are cleared. Clinical records stay linked. The patient's data exports are cancelled. This cannot be undone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "Approve a pseudonymization request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Request ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, or the caller made the request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already decided, or the patient is already pseudonymized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pseudonymization-requests/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins other than the requester. The patient is not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pseudonymization"
+                ],
+                "summary": "Reject a pseudonymization request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Request ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PseudonymizationRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, or the caller made the request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Already decided",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/research/dataset": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins and privacy officers. A ZIP with patients and visits of every patient whose research consent is in force, de-identified Safe Harbor style, plus k_anonymity.json checking the quasi-identifiers (birth year, age, gender, region) and manifest.json. Names, contact details and the address are dropped; patients and visits get pseudonymous IDs that stay the same across exports. Dates are cut to the year or shifted by a per-patient offset, ages over 89 are reported as \"90+\" and the address becomes a three-digit postal area. Symptoms, diagnoses, prescriptions and notes are only included with free_text=true.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Research"
+                ],
+                "summary": "Export a de-identified research dataset",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Table format (default: csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "year",
+                            "shift"
+                        ],
+                        "type": "string",
+                        "description": "year keeps only the year, shift moves each patient's dates by a fixed random offset (default: year)",
+                        "name": "dates",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 3650,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Largest date shift in days with dates=shift (default: 365)",
+                        "name": "max_shift_days",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "postal3",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "postal3 keeps the first three digits of the postal code, none drops it (default: postal3)",
+                        "name": "geography",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include visit symptoms, diagnoses, prescriptions and notes (default: false)",
+                        "name": "free_text",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 2,
+                        "type": "integer",
+                        "description": "Smallest group size for the k-anonymity check (default: 5)",
+                        "name": "k",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid options",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Research exports not configured, or audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors can record a visit for a patient in their care, or one they hold a break-glass grant for. Recording a visit does not add the doctor to the care team.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Record a visit for a patient",
+                "parameters": [
+                    {
+                        "description": "Visit details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisitCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to record a visit for this patient",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to record patient visit",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors see visits of patients in their care; others get 404. Other roles see the visit without symptoms, diagnosis, prescription or notes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Get details of a specific patient visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID) for which to get details",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the visit; send it as If-Match when updating"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visit ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors can update patient visit details they recorded. Doctor ID is taken from authenticated user. Send the ETag of the visit as If-Match; if it was updated since, nothing is changed and the current visit is returned with 412. The version replaced is kept in the visit's history. Once the visit is finalized, changes are addenda and amendment_reason is required.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Update a specific patient visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID) for which to update details",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from the last GET, or * to update whatever version is current",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Patient visit details to update",
+                        "name": "visit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisitUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the visit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Visit is finalized and amendment_reason is missing",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "412": {
+                        "description": "Visit changed since the If-Match version; the current visit, with its ETag",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/dispensations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors list dispensations of visits of patients in their care; others get 404. Roles other than pharmacist see the movements without the item, batch or reason.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "List stock dispensed and returned for a visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visit ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/dispense": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Each line is drawn from the earliest-expiring unexpired batches at the location (FEFO).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pharmacy"
+                ],
+                "summary": "Dispense a visit's prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Items to dispense",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DispenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, invalid input or no prescription",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit, location or item not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The doctor who recorded the visit signs it. Later changes are addenda that need an amendment_reason. Send the ETag of the visit as If-Match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Finalize a patient visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from the last GET, or * to finalize whatever version is current",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the visit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visit ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the doctor who recorded the visit may finalize it",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Visit is already finalized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "412": {
+                        "description": "Visit changed since the If-Match version; the current visit, with its ETag",
+                        "schema": {
+                            "$ref": "#/definitions/model.PatientVisit"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every version of the visit, oldest first, with the fields each change made and its author. Addenda to a finalized visit carry their reason. Callers without clinical access see which clinical fields changed but not their values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Get the version history of a patient visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VisitHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visit ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/lab-orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors list lab orders of visits of patients in their care; others get 404. Roles other than lab technician see the orders without clinical notes or results.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab"
+                ],
+                "summary": "List lab orders for a visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LabOrder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visit ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Doctors list visits of patients in their care. Other roles see the visits without symptoms, diagnosis, prescription or notes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "List patient visits for a specific patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Patient ID (UUID) for which to list visits",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Added validation",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Added validation",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patient ID format",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Patient not found or not in the caller's care",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Audit trail unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/observations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a numeric observation and evaluates it against the critical alert rules. A breach raises an alert for the visit's doctor, returned in the ` + "`" + `alert` + "`" + ` field. Doctors record on visits of patients in their care; others get 404.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Record a vital sign on a visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Visit ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Observation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ObservationCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Observation"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error or invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.APIError": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "description": "Can be map[string]string for validation errors, or a simple string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "description": "Quote it when reporting the error",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccessLogEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "actor_user_id": {
+                    "type": "string"
+                },
+                "actor_username": {
+                    "type": "string"
+                },
+                "break_glass_grant_id": {
+                    "description": "BreakGlassGrantID is set when the access relied on emergency break-glass access.",
+                    "type": "string"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields written, sorted; empty for reads",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Alert": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by_user_id": {
+                    "type": "string"
+                },
+                "assigned_doctor_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "escalate_at": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "type": "string"
+                },
+                "escalation_level": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "observation_code": {
+                    "type": "string"
+                },
+                "observed_value": {
+                    "type": "number"
+                },
+                "original_doctor_id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/model.ObservationSource"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AlertStatus"
+                },
+                "threshold_value": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "critical_high": {
+                    "type": "number"
+                },
+                "critical_low": {
+                    "type": "number"
+                },
+                "escalate_after_minutes": {
+                    "type": "integer"
+                },
+                "escalation_doctor_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_age_years": {
+                    "type": "integer"
+                },
+                "min_age_years": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "observation_code": {
+                    "type": "string"
+                },
+                "sex": {
+                    "$ref": "#/definitions/model.Gender"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertRuleCreateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "observation_code"
+            ],
+            "properties": {
+                "critical_high": {
+                    "type": "number"
+                },
+                "critical_low": {
+                    "type": "number"
+                },
+                "escalate_after_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
+                },
+                "escalation_doctor_id": {
+                    "type": "string"
+                },
+                "max_age_years": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_age_years": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "observation_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "sex": {
+                    "enum": [
+                        "male",
+                        "female",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Gender"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.AlertStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "acknowledged"
+            ],
+            "x-enum-varnames": [
+                "AlertStatusOpen",
+                "AlertStatusAcknowledged"
+            ]
+        },
+        "model.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "read",
+                "list",
+                "update",
+                "delete",
+                "login",
+                "login_failed",
+                "break_glass",
+                "notify",
+                "pseudonymize",
+                "export"
+            ],
+            "x-enum-comments": {
+                "AuditActionBreakGlass": "Emergency access was granted",
+                "AuditActionExport": "A de-identified dataset was exported",
+                "AuditActionNotify": "A message was sent to the patient",
+                "AuditActionPseudonymize": "Direct identifiers were replaced"
+            },
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionRead",
+                "AuditActionList",
+                "AuditActionUpdate",
+                "AuditActionDelete",
+                "AuditActionLogin",
+                "AuditActionLoginFailed",
+                "AuditActionBreakGlass",
+                "AuditActionNotify",
+                "AuditActionPseudonymize",
+                "AuditActionExport"
+            ]
+        },
+        "model.AuditChainVerification": {
+            "type": "object",
+            "properties": {
+                "broken_at_seq": {
+                    "type": "integer"
+                },
+                "events_checked": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "actor_user_id": {
+                    "type": "string"
+                },
+                "break_glass_grant_id": {
+                    "description": "BreakGlassGrantID is set when the access relied on emergency break-glass access.",
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.FieldChange"
+                    }
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BreakGlassAccess": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "grant_id": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BreakGlassGrant": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BreakGlassReport": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "description": "Accesses under any grant that day",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD, UTC",
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "grant_count": {
+                    "description": "Grants issued that day",
+                    "type": "integer"
+                },
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BreakGlassReviewItem"
+                    }
+                }
+            }
+        },
+        "model.BreakGlassReportSummary": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "grant_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BreakGlassRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "description": "Defaults to 60",
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 5
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "minLength": 10
+                }
+            }
+        },
+        "model.BreakGlassReviewItem": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BreakGlassAccess"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CareTeamMember": {
+            "type": "object",
+            "properties": {
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_by_user_id": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.UserRole"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CareTeamMemberRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Charge": {
+            "type": "object",
+            "properties": {
+                "charge_item_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_user_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "source": {
+                    "$ref": "#/definitions/model.ChargeSource"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "tax_rate_bps": {
+                    "type": "integer"
+                },
+                "unit_price_minor": {
+                    "type": "integer"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChargeCreateRequest": {
+            "type": "object",
+            "required": [
+                "charge_code",
+                "visit_id"
+            ],
+            "properties": {
+                "charge_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "quantity": {
+                    "description": "Defaults to 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "source": {
+                    "enum": [
+                        "procedure",
+                        "manual"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ChargeSource"
+                        }
+                    ]
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChargeItem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "tax_category_code": {
+                    "type": "string"
+                },
+                "tax_rate_bps": {
+                    "type": "integer"
+                },
+                "unit_price_minor": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChargeItemCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "description"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "tax_category_code": {
+                    "description": "Defaults to \"exempt\"",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "unit_price_minor": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.ChargeSource": {
+            "type": "string",
+            "enum": [
+                "visit",
+                "procedure",
+                "lab_order",
+                "manual"
+            ],
+            "x-enum-varnames": [
+                "ChargeSourceVisit",
+                "ChargeSourceProcedure",
+                "ChargeSourceLabOrder",
+                "ChargeSourceManual"
+            ]
+        },
+        "model.ClaimStatus": {
+            "type": "string",
+            "enum": [
+                "submitted",
+                "accepted",
+                "rejected",
+                "paid"
+            ],
+            "x-enum-varnames": [
+                "ClaimStatusSubmitted",
+                "ClaimStatusAccepted",
+                "ClaimStatusRejected",
+                "ClaimStatusPaid"
+            ]
+        },
+        "model.ClaimStatusHistory": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by_user_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ClaimStatus"
+                }
+            }
+        },
+        "model.ClaimStatusUpdateRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "paid_minor": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "payer_reference": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "rejection_reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "accepted",
+                        "rejected",
+                        "paid"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ClaimStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.ConsentCreateRequest": {
+            "type": "object",
+            "required": [
+                "consent_type",
+                "text_version"
+            ],
+            "properties": {
+                "consent_type": {
+                    "enum": [
+                        "treatment",
+                        "data_sharing",
+                        "research",
+                        "sms_contact"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ConsentType"
+                        }
+                    ]
+                },
+                "signed_at": {
+                    "description": "RFC3339, defaults to now",
+                    "type": "string"
+                },
+                "text_version": {
+                    "description": "Version of the consent text the patient signed",
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "model.ConsentRecord": {
+            "type": "object",
+            "properties": {
+                "consent_type": {
+                    "$ref": "#/definitions/model.ConsentType"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "signed_at": {
+                    "type": "string"
+                },
+                "text_version": {
+                    "type": "string"
+                },
+                "withdrawal_reason": {
+                    "type": "string"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                },
+                "withdrawn_by_user_id": {
+                    "type": "string"
+                },
+                "witnessed_by_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConsentType": {
+            "type": "string",
+            "enum": [
+                "treatment",
+                "data_sharing",
+                "research",
+                "sms_contact"
+            ],
+            "x-enum-comments": {
+                "ConsentDataSharing": "Releasing the patient's data outside the hospital",
+                "ConsentResearch": "Including the patient in research datasets",
+                "ConsentSMSContact": "Contacting the patient by SMS"
+            },
+            "x-enum-varnames": [
+                "ConsentTreatment",
+                "ConsentDataSharing",
+                "ConsentResearch",
+                "ConsentSMSContact"
+            ]
+        },
+        "model.ConsentTypeInfo": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ConsentType"
+                }
+            }
+        },
+        "model.ConsentWithdrawRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "model.CumulativeLabResult": {
+            "type": "object",
+            "properties": {
+                "analyte_code": {
+                    "type": "string"
+                },
+                "analyte_id": {
+                    "type": "string"
+                },
+                "analyte_name": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entered_by_user_id": {
+                    "type": "string"
+                },
+                "flag": {
+                    "$ref": "#/definitions/model.LabResultFlag"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "order_status": {
+                    "$ref": "#/definitions/model.LabOrderStatus"
+                },
+                "reference_high": {
+                    "type": "number"
+                },
+                "reference_low": {
+                    "type": "number"
+                },
+                "test_code": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value_numeric": {
+                    "type": "number"
+                },
+                "value_text": {
+                    "type": "string"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DispenseLine": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "quantity"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "model.DispenseRequest": {
+            "type": "object",
+            "required": [
+                "lines",
+                "location_id"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.DispenseLine"
+                    }
+                },
+                "location_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EligibilityCheck": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "checked_by_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "payer_reference": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.EligibilityStatus"
+                }
+            }
+        },
+        "model.EligibilityCheckRequest": {
+            "type": "object",
+            "properties": {
+                "date_of_service": {
+                    "description": "Defaults to today",
+                    "type": "string"
+                }
+            }
+        },
+        "model.EligibilityStatus": {
+            "type": "string",
+            "enum": [
+                "eligible",
+                "ineligible",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "EligibilityEligible",
+                "EligibilityIneligible",
+                "EligibilityUnknown"
+            ]
+        },
+        "model.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "model.Gender": {
+            "type": "string",
+            "enum": [
+                "male",
+                "female",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "GenderMale",
+                "GenderFemale",
+                "GenderOther"
+            ]
+        },
+        "model.GoodsReceiptLine": {
+            "type": "object",
+            "required": [
+                "expiry_date",
+                "item_id",
+                "lot_number",
+                "quantity"
+            ],
+            "properties": {
+                "expiry_date": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "model.GoodsReceiptRequest": {
+            "type": "object",
+            "required": [
+                "lines",
+                "location_id"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.GoodsReceiptLine"
+                    }
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Supplier invoice or delivery note number",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.InsuranceClaim": {
+            "type": "object",
+            "properties": {
+                "claimed_minor": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diagnosis_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ClaimStatusHistory"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "paid_minor": {
+                    "type": "integer"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "payer_reference": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "rejection_reason": {
+                    "type": "string"
+                },
+                "sent_to_payer_at": {
+                    "description": "Unset until the payer acknowledges the claim",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ClaimStatus"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "submitted_by_user_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.InsuranceClaimCreateRequest": {
+            "type": "object",
+            "required": [
+                "diagnosis_codes",
+                "invoice_id",
+                "policy_id"
+            ],
+            "properties": {
+                "diagnosis_codes": {
+                    "description": "ICD-10 codes, e.g. J45.909",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.InsurancePolicy": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "eligibility_checked_at": {
+                    "type": "string"
+                },
+                "eligibility_status": {
+                    "$ref": "#/definitions/model.EligibilityStatus"
+                },
+                "group_number": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "member_id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "payer_code": {
+                    "type": "string"
+                },
+                "payer_name": {
+                    "type": "string"
+                },
+                "plan_name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/model.PolicyPriority"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.InsurancePolicyCreateRequest": {
+            "type": "object",
+            "required": [
+                "member_id",
+                "payer_code",
+                "payer_name",
+                "valid_from"
+            ],
+            "properties": {
+                "group_number": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "member_id": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "payer_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "payer_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "plan_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "priority": {
+                    "description": "Defaults to primary",
+                    "enum": [
+                        "primary",
+                        "secondary"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PolicyPriority"
+                        }
+                    ]
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Invoice": {
+            "type": "object",
+            "properties": {
+                "balance_minor": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_minor": {
+                    "type": "integer"
+                },
+                "fiscal_year": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_number": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "issued_by_user_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InvoiceLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "paid_minor": {
+                    "description": "Payments less refunds",
+                    "type": "integer"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Payment"
+                    }
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Refund"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/model.InvoiceStatus"
+                },
+                "subtotal_minor": {
+                    "type": "integer"
+                },
+                "tax_minor": {
+                    "type": "integer"
+                },
+                "total_minor": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.InvoiceCreateRequest": {
+            "type": "object",
+            "required": [
+                "patient_id"
+            ],
+            "properties": {
+                "charge_ids": {
+                    "description": "Defaults to every unbilled charge",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "discount_bps": {
+                    "description": "Applied to each line before tax",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "charge_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_minor": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "line_total_minor": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "tax_minor": {
+                    "type": "integer"
+                },
+                "tax_rate_bps": {
+                    "type": "integer"
+                },
+                "unit_price_minor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "partially_paid",
+                "paid"
+            ],
+            "x-enum-varnames": [
+                "InvoiceStatusIssued",
+                "InvoiceStatusPartiallyPaid",
+                "InvoiceStatusPaid"
+            ]
+        },
+        "model.LabAnalyte": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "critical_high": {
+                    "type": "number"
+                },
+                "critical_low": {
+                    "type": "number"
+                },
+                "display_order": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reference_high": {
+                    "type": "number"
+                },
+                "reference_low": {
+                    "type": "number"
+                },
+                "test_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabAnalyteCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "critical_high": {
+                    "type": "number"
+                },
+                "critical_low": {
+                    "type": "number"
+                },
+                "display_order": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reference_high": {
+                    "type": "number"
+                },
+                "reference_low": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "model.LabOrder": {
+            "type": "object",
+            "properties": {
+                "clinical_notes": {
+                    "type": "string"
+                },
+                "collected_at": {
+                    "type": "string"
+                },
+                "collected_by_user_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ordered_at": {
+                    "type": "string"
+                },
+                "ordered_by_user_id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/model.LabOrderPriority"
+                },
+                "resulted_at": {
+                    "type": "string"
+                },
+                "resulted_by_user_id": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LabResult"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/model.LabOrderStatus"
+                },
+                "test_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                },
+                "verified_by_user_id": {
+                    "type": "string"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabOrderCreateRequest": {
+            "type": "object",
+            "required": [
+                "test_code",
+                "visit_id"
+            ],
+            "properties": {
+                "clinical_notes": {
+                    "type": "string"
+                },
+                "priority": {
+                    "enum": [
+                        "routine",
+                        "urgent",
+                        "stat"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LabOrderPriority"
+                        }
+                    ]
+                },
+                "test_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabOrderPriority": {
+            "type": "string",
+            "enum": [
+                "routine",
+                "urgent",
+                "stat"
+            ],
+            "x-enum-varnames": [
+                "LabPriorityRoutine",
+                "LabPriorityUrgent",
+                "LabPriorityStat"
+            ]
+        },
+        "model.LabOrderStatus": {
+            "type": "string",
+            "enum": [
+                "ordered",
+                "collected",
+                "resulted",
+                "verified"
+            ],
+            "x-enum-varnames": [
+                "LabStatusOrdered",
+                "LabStatusCollected",
+                "LabStatusResulted",
+                "LabStatusVerified"
+            ]
+        },
+        "model.LabResult": {
+            "type": "object",
+            "properties": {
+                "analyte_code": {
+                    "type": "string"
+                },
+                "analyte_id": {
+                    "type": "string"
+                },
+                "analyte_name": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entered_by_user_id": {
+                    "type": "string"
+                },
+                "flag": {
+                    "$ref": "#/definitions/model.LabResultFlag"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "reference_high": {
+                    "type": "number"
+                },
+                "reference_low": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value_numeric": {
+                    "type": "number"
+                },
+                "value_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabResultEntryRequest": {
+            "type": "object",
+            "required": [
+                "results"
+            ],
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.LabResultValue"
+                    }
+                }
+            }
+        },
+        "model.LabResultFlag": {
+            "type": "string",
+            "enum": [
+                "normal",
+                "low",
+                "high",
+                "critical_low",
+                "critical_high",
+                "abnormal"
+            ],
+            "x-enum-varnames": [
+                "LabFlagNormal",
+                "LabFlagLow",
+                "LabFlagHigh",
+                "LabFlagCriticalLow",
+                "LabFlagCriticalHigh",
+                "LabFlagAbnormal"
+            ]
+        },
+        "model.LabResultValue": {
+            "type": "object",
+            "required": [
+                "analyte_code"
+            ],
+            "properties": {
+                "analyte_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "flag": {
+                    "enum": [
+                        "normal",
+                        "abnormal"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LabResultFlag"
+                        }
+                    ]
+                },
+                "value_numeric": {
+                    "type": "number"
+                },
+                "value_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabSpecimenType": {
+            "type": "string",
+            "enum": [
+                "blood",
+                "serum",
+                "plasma",
+                "urine",
+                "stool",
+                "sputum",
+                "csf",
+                "swab",
+                "tissue",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "SpecimenBlood",
+                "SpecimenSerum",
+                "SpecimenPlasma",
+                "SpecimenUrine",
+                "SpecimenStool",
+                "SpecimenSputum",
+                "SpecimenCSF",
+                "SpecimenSwab",
+                "SpecimenTissue",
+                "SpecimenOther"
+            ]
+        },
+        "model.LabTest": {
+            "type": "object",
+            "properties": {
+                "analytes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LabAnalyte"
+                    }
+                },
+                "charge_code": {
+                    "description": "Charge master item billed when the test is ordered",
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "specimen_type": {
+                    "$ref": "#/definitions/model.LabSpecimenType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LabTestCreateRequest": {
+            "type": "object",
+            "required": [
+                "analytes",
+                "code",
+                "name",
+                "specimen_type"
+            ],
+            "properties": {
+                "analytes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.LabAnalyteCreateRequest"
+                    }
+                },
+                "charge_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "specimen_type": {
+                    "enum": [
+                        "blood",
+                        "serum",
+                        "plasma",
+                        "urine",
+                        "stool",
+                        "sputum",
+                        "csf",
+                        "swab",
+                        "tissue",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LabSpecimenType"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "required": [
                 "password",
                 "username"
             ],
             "properties": {
-                "password": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "This User struct is from our model package",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.NotificationReceipt": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Observation": {
+            "type": "object",
+            "properties": {
+                "alert": {
+                    "description": "Set when the observation raised a critical alert",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Alert"
+                        }
+                    ]
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "recorded_by_user_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ObservationCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "value"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "observed_at": {
+                    "description": "RFC3339, defaults to now",
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ObservationSource": {
+            "type": "string",
+            "enum": [
+                "vital_sign",
+                "lab_result"
+            ],
+            "x-enum-varnames": [
+                "ObservationSourceVitalSign",
+                "ObservationSourceLabResult"
+            ]
+        },
+        "model.PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "The actual list of items"
+                },
+                "limit": {
+                    "description": "The limit used for this page",
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "The offset used for this page",
+                    "type": "integer"
+                },
+                "page": {
+                    "description": "Current page number (calculated: offset/limit + 1)",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total number of items available across all pages",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "description": "Total pages (calculated: ceil(total/limit))",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Patient": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "contact_email": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date_of_birth": {
+                    "description": "Recommended to store as full timestamp, format for display",
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/model.Gender"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "medical_history": {
+                    "type": "string"
+                },
+                "pseudonymized_at": {
+                    "description": "Set once direct identifiers were replaced",
+                    "type": "string"
+                },
+                "registered_by_user_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Changes on every update; sent as the ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PatientBalance": {
+            "type": "object",
+            "properties": {
+                "balance_minor": {
+                    "description": "Outstanding on issued invoices",
+                    "type": "integer"
+                },
+                "invoiced_minor": {
+                    "type": "integer"
+                },
+                "paid_minor": {
+                    "type": "integer"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "unbilled_minor": {
+                    "description": "Captured charges not yet invoiced, including tax",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PatientCreateRequest": {
+            "type": "object",
+            "required": [
+                "date_of_birth",
+                "first_name",
+                "last_name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "contact_email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "gender": {
+                    "enum": [
+                        "male",
+                        "female",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Gender"
+                        }
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "medical_history": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PatientExport": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "Set while the archive can be downloaded",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "requested_at": {
+                    "type": "string"
+                },
+                "requested_by_user_id": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PatientExportStatus"
+                }
+            }
+        },
+        "model.PatientExportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "PatientExportCompleted": "The archive can be downloaded until expires_at",
+                "PatientExportExpired": "The archive has been deleted"
+            },
+            "x-enum-varnames": [
+                "PatientExportPending",
+                "PatientExportRunning",
+                "PatientExportCompleted",
+                "PatientExportFailed",
+                "PatientExportExpired"
+            ]
+        },
+        "model.PatientUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "contact_email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "gender": {
+                    "enum": [
+                        "male",
+                        "female",
+                        "other",
+                        "prefer_not_to_say"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Gender"
+                        }
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "medical_history": {
+                    "description": "Doctors can update this",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PatientVisit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "diagnosis": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "description": "The user ID of the doctor who conducted the visit",
+                    "type": "string"
+                },
+                "finalized_at": {
+                    "description": "FinalizedAt is set once the visit is signed; later changes are addenda with a reason.",
+                    "type": "string"
+                },
+                "finalized_by_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "description": "Additional notes by doctor or about the visit",
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "prescription": {
+                    "type": "string"
+                },
+                "symptoms": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Changes on every update; sent as the ETag",
+                    "type": "integer"
+                },
+                "visit_date": {
+                    "description": "Timestamp of the visit",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PatientVisitCreateRequest": {
+            "type": "object",
+            "required": [
+                "patient_id"
+            ],
+            "properties": {
+                "diagnosis": {
                     "type": "string"
                 },
-                "username": {
+                "notes": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "prescription": {
+                    "type": "string"
+                },
+                "symptoms": {
+                    "type": "string"
+                },
+                "visit_date": {
+                    "description": "Expect RFC3339. If empty, handler might default to time.Now()",
                     "type": "string"
                 }
             }
         },
-        "model.LoginResponse": {
+        "model.PatientVisitUpdateRequest": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "amendment_reason": {
+                    "description": "AmendmentReason is required once the visit is finalized, and recorded with the change.",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "minLength": 3
+                },
+                "diagnosis": {
                     "type": "string"
                 },
-                "refresh_token": {
+                "notes": {
                     "type": "string"
                 },
-                "user": {
-                    "description": "This User struct is from our model package",
+                "prescription": {
+                    "type": "string"
+                },
+                "symptoms": {
+                    "type": "string"
+                },
+                "visit_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Payment": {
+            "type": "object",
+            "properties": {
+                "amount_minor": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/model.PaymentMethod"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "received_by_user_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PaymentCreateRequest": {
+            "type": "object",
+            "required": [
+                "amount_minor",
+                "method"
+            ],
+            "properties": {
+                "amount_minor": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "method": {
+                    "enum": [
+                        "cash",
+                        "card",
+                        "insurance"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.PaymentMethod"
                         }
                     ]
+                },
+                "reference": {
+                    "description": "Card slip, claim number, etc.",
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
-        "model.PaginatedResponse": {
+        "model.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "cash",
+                "card",
+                "insurance"
+            ],
+            "x-enum-varnames": [
+                "PaymentMethodCash",
+                "PaymentMethodCard",
+                "PaymentMethodInsurance"
+            ]
+        },
+        "model.PharmacyItem": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "The actual list of items"
+                "code": {
+                    "type": "string"
                 },
-                "limit": {
-                    "description": "The limit used for this page",
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "offset": {
-                    "description": "The offset used for this page",
-                    "type": "integer"
+                "dosage_form": {
+                    "type": "string"
                 },
-                "page": {
-                    "description": "Current page number (calculated: offset/limit + 1)",
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reorder_level": {
                     "type": "integer"
                 },
-                "total": {
-                    "description": "Total number of items available across all pages",
-                    "type": "integer"
+                "strength": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PharmacyItemCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "dosage_form": {
+                    "description": "e.g. tablet, syrup",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reorder_level": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "strength": {
+                    "description": "e.g. 500 mg",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "unit": {
+                    "description": "Dispensing unit; defaults to \"unit\"",
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "model.PharmacyLocation": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PharmacyLocationCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.PolicyPriority": {
+            "type": "string",
+            "enum": [
+                "primary",
+                "secondary"
+            ],
+            "x-enum-varnames": [
+                "PolicyPriorityPrimary",
+                "PolicyPrioritySecondary"
+            ]
+        },
+        "model.PseudonymizationChange": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "description": "Omitted when the field is cleared",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PseudonymizationCreateRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "e.g. the reference of the patient's erasure request",
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "model.PseudonymizationDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "model.PseudonymizationPreview": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PseudonymizationChange"
+                    }
+                },
+                "patient_id": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "description": "Total pages (calculated: ceil(total/limit))",
-                    "type": "integer"
+                "retained": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.Patient": {
+        "model.PseudonymizationRequest": {
             "type": "object",
             "properties": {
-                "address": {
+                "created_at": {
                     "type": "string"
                 },
-                "contact_email": {
+                "decided_at": {
                     "type": "string"
                 },
-                "contact_phone": {
+                "decided_by_user_id": {
                     "type": "string"
                 },
-                "created_at": {
+                "decision_note": {
                     "type": "string"
                 },
-                "date_of_birth": {
-                    "description": "Recommended to store as full timestamp, format for display",
+                "id": {
                     "type": "string"
                 },
-                "first_name": {
+                "patient_id": {
                     "type": "string"
                 },
-                "gender": {
-                    "$ref": "#/definitions/model.Gender"
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by_user_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PseudonymizationStatus"
+                }
+            }
+        },
+        "model.PseudonymizationStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-comments": {
+                "PseudonymizationApproved": "The patient has been pseudonymized"
+            },
+            "x-enum-varnames": [
+                "PseudonymizationPending",
+                "PseudonymizationApproved",
+                "PseudonymizationRejected"
+            ]
+        },
+        "model.Refund": {
+            "type": "object",
+            "properties": {
+                "amount_minor": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "invoice_id": {
                     "type": "string"
                 },
-                "medical_history": {
+                "payment_id": {
                     "type": "string"
                 },
-                "registered_by_user_id": {
+                "reason": {
                     "type": "string"
                 },
-                "updated_at": {
+                "refunded_at": {
+                    "type": "string"
+                },
+                "refunded_by_user_id": {
                     "type": "string"
                 }
             }
         },
-        "model.PatientCreateRequest": {
+        "model.RefundCreateRequest": {
             "type": "object",
             "required": [
-                "date_of_birth",
-                "first_name",
-                "last_name"
+                "amount_minor",
+                "reason"
             ],
             "properties": {
-                "address": {
-                    "type": "string",
-                    "maxLength": 500
+                "amount_minor": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "contact_email": {
-                    "type": "string",
-                    "maxLength": 255
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ReorderAlert": {
+            "type": "object",
+            "properties": {
+                "item_code": {
+                    "type": "string"
                 },
-                "contact_phone": {
+                "item_id": {
                     "type": "string"
                 },
-                "date_of_birth": {
+                "item_name": {
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "location_code": {
+                    "type": "string"
                 },
-                "gender": {
-                    "enum": [
-                        "male",
-                        "female",
-                        "other"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Gender"
-                        }
-                    ]
+                "location_id": {
+                    "type": "string"
                 },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "on_hand": {
+                    "type": "integer"
                 },
-                "medical_history": {
-                    "type": "string"
+                "reorder_level": {
+                    "type": "integer"
                 }
             }
         },
-        "model.PatientUpdateRequest": {
+        "model.SMSNotificationRequest": {
             "type": "object",
+            "required": [
+                "body"
+            ],
             "properties": {
-                "address": {
+                "body": {
                     "type": "string",
-                    "maxLength": 500
-                },
-                "contact_email": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "contact_phone": {
+                    "maxLength": 480
+                }
+            }
+        },
+        "model.StockAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "batch_id",
+                "location_id",
+                "quantity",
+                "reason"
+            ],
+            "properties": {
+                "batch_id": {
                     "type": "string"
                 },
-                "date_of_birth": {
+                "location_id": {
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "gender": {
-                    "enum": [
-                        "male",
-                        "female",
-                        "other",
-                        "prefer_not_to_say"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Gender"
-                        }
-                    ]
-                },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "quantity": {
+                    "description": "Signed; negative removes stock",
+                    "type": "integer"
                 },
-                "medical_history": {
-                    "description": "Doctors can update this",
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "model.PatientVisit": {
+        "model.StockLevel": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "batch_id": {
                     "type": "string"
                 },
-                "diagnosis": {
+                "expired": {
+                    "type": "boolean"
+                },
+                "expiry_date": {
                     "type": "string"
                 },
-                "doctor_id": {
-                    "description": "The user ID of the doctor who conducted the visit",
+                "item_code": {
                     "type": "string"
                 },
-                "id": {
+                "item_id": {
                     "type": "string"
                 },
-                "notes": {
-                    "description": "Additional notes by doctor or about the visit",
+                "item_name": {
                     "type": "string"
                 },
-                "patient_id": {
+                "location_code": {
                     "type": "string"
                 },
-                "prescription": {
+                "location_id": {
                     "type": "string"
                 },
-                "symptoms": {
+                "lot_number": {
                     "type": "string"
                 },
-                "updated_at": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
                 },
-                "visit_date": {
-                    "description": "Timestamp of the visit",
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "model.PatientVisitCreateRequest": {
+        "model.StockMovement": {
             "type": "object",
-            "required": [
-                "patient_id"
-            ],
             "properties": {
-                "diagnosis": {
+                "batch_id": {
                     "type": "string"
                 },
-                "notes": {
+                "created_at": {
                     "type": "string"
                 },
-                "patient_id": {
+                "created_by_user_id": {
                     "type": "string"
                 },
-                "prescription": {
+                "id": {
                     "type": "string"
                 },
-                "symptoms": {
+                "item_code": {
                     "type": "string"
                 },
-                "visit_date": {
-                    "description": "Expect RFC3339. If empty, handler might default to time.Now()",
+                "item_id": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "movement_type": {
+                    "$ref": "#/definitions/model.StockMovementType"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "returned_movement_id": {
+                    "type": "string"
+                },
+                "visit_id": {
                     "type": "string"
                 }
             }
         },
-        "model.PatientVisitUpdateRequest": {
+        "model.StockMovementType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "dispense",
+                "return",
+                "adjustment"
+            ],
+            "x-enum-varnames": [
+                "StockMovementReceipt",
+                "StockMovementDispense",
+                "StockMovementReturn",
+                "StockMovementAdjustment"
+            ]
+        },
+        "model.StockReturnRequest": {
             "type": "object",
+            "required": [
+                "quantity"
+            ],
             "properties": {
-                "diagnosis": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "notes": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TaxCategory": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "prescription": {
+                "created_at": {
                     "type": "string"
                 },
-                "symptoms": {
+                "description": {
                     "type": "string"
                 },
-                "visit_date": {
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
+        "model.TaxCategoryCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "description"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "rate_bps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                }
+            }
+        },
         "model.User": {
             "type": "object",
             "properties": {
@@ -1052,7 +9382,9 @@ const docTemplate = `{
                 "role": {
                     "enum": [
                         "receptionist",
-                        "doctor"
+                        "doctor",
+                        "lab_technician",
+                        "pharmacist"
                     ],
                     "allOf": [
                         {
@@ -1071,11 +9403,93 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "receptionist",
-                "doctor"
+                "doctor",
+                "lab_technician",
+                "pharmacist",
+                "admin",
+                "privacy_officer"
             ],
+            "x-enum-comments": {
+                "RoleAdmin": "Provisioned in the database; cannot self-register",
+                "RolePrivacyOfficer": "Provisioned in the database; cannot self-register"
+            },
             "x-enum-varnames": [
                 "RoleReceptionist",
-                "RoleDoctor"
+                "RoleDoctor",
+                "RoleLabTechnician",
+                "RolePharmacist",
+                "RoleAdmin",
+                "RolePrivacyOfficer"
+            ]
+        },
+        "model.VisitHistory": {
+            "type": "object",
+            "properties": {
+                "current_version": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VisitHistoryEntry"
+                    }
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "visit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.VisitHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "author_user_id": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.FieldChange"
+                    }
+                },
+                "kind": {
+                    "$ref": "#/definitions/model.VisitRevisionKind"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.VisitRevisionKind": {
+            "type": "string",
+            "enum": [
+                "created",
+                "edit",
+                "finalize",
+                "addendum"
+            ],
+            "x-enum-comments": {
+                "VisitRevisionAddendum": "Changed after it was finalized, with a reason",
+                "VisitRevisionCreated": "The visit was recorded",
+                "VisitRevisionEdit": "Changed before it was finalized",
+                "VisitRevisionFinalize": "Signed; nothing changed"
+            },
+            "x-enum-varnames": [
+                "VisitRevisionCreated",
+                "VisitRevisionEdit",
+                "VisitRevisionFinalize",
+                "VisitRevisionAddendum"
             ]
         }
     },
@@ -0,0 +1,122 @@
+// Package alerting evaluates numeric observations against critical-value rules.
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// Direction reports which side of a rule an observation breached.
+type Direction string
+
+const (
+	DirectionLow  Direction = "low"
+	DirectionHigh Direction = "high"
+)
+
+// Rule is a critical threshold for one observation code, optionally narrowed to a sex and age band.
+type Rule struct {
+	ID                 uuid.UUID
+	Name               string
+	ObservationCode    string
+	Sex                *model.Gender
+	MinAgeYears        *int
+	MaxAgeYears        *int
+	CriticalLow        *float64
+	CriticalHigh       *float64
+	EscalateAfter      time.Duration
+	EscalationDoctorID *uuid.UUID
+}
+
+// Subject is the patient an observation was taken from.
+type Subject struct {
+	AgeYears int
+	Sex      *model.Gender
+}
+
+// Breach is the result of an observation crossing a rule's critical threshold.
+type Breach struct {
+	Rule      Rule
+	Direction Direction
+	Threshold float64
+	Value     float64
+}
+
+// Message is a short, PHI-free description suitable for an alert list.
+func (b Breach) Message() string {
+	return fmt.Sprintf("%s critically %s: %g (threshold %g)", b.Rule.ObservationCode, b.Direction, b.Value, b.Threshold)
+}
+
+// Applies reports whether the rule covers the given observation code and subject.
+func (r Rule) Applies(code string, s Subject) bool {
+	if r.ObservationCode != code {
+		return false
+	}
+	if r.Sex != nil && (s.Sex == nil || *s.Sex != *r.Sex) {
+		return false
+	}
+	if r.MinAgeYears != nil && s.AgeYears < *r.MinAgeYears {
+		return false
+	}
+	if r.MaxAgeYears != nil && s.AgeYears > *r.MaxAgeYears {
+		return false
+	}
+	return true
+}
+
+// specificity ranks rules so that narrower rules override general ones.
+func (r Rule) specificity() int {
+	n := 0
+	if r.Sex != nil {
+		n++
+	}
+	if r.MinAgeYears != nil {
+		n++
+	}
+	if r.MaxAgeYears != nil {
+		n++
+	}
+	return n
+}
+
+// Evaluate checks value against the most specific applicable rule and returns the breach, if any.
+// When several rules are equally specific the first one in rules wins, so callers should pass
+// rules in a stable order.
+func Evaluate(rules []Rule, code string, subject Subject, value float64) (Breach, bool) {
+	var selected *Rule
+	for i := range rules {
+		r := &rules[i]
+		if !r.Applies(code, subject) {
+			continue
+		}
+		if selected == nil || r.specificity() > selected.specificity() {
+			selected = r
+		}
+	}
+	if selected == nil {
+		return Breach{}, false
+	}
+
+	switch {
+	case selected.CriticalLow != nil && value <= *selected.CriticalLow:
+		return Breach{Rule: *selected, Direction: DirectionLow, Threshold: *selected.CriticalLow, Value: value}, true
+	case selected.CriticalHigh != nil && value >= *selected.CriticalHigh:
+		return Breach{Rule: *selected, Direction: DirectionHigh, Threshold: *selected.CriticalHigh, Value: value}, true
+	}
+	return Breach{}, false
+}
+
+// AgeInYears returns the completed years between dob and at.
+func AgeInYears(dob, at time.Time) int {
+	years := at.Year() - dob.Year()
+	if at.Month() < dob.Month() || (at.Month() == dob.Month() && at.Day() < dob.Day()) {
+		years--
+	}
+	if years < 0 {
+		return 0
+	}
+	return years
+}
@@ -0,0 +1,64 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func f(v float64) *float64 { return &v }
+func i(v int) *int         { return &v }
+
+func TestEvaluate_GeneralRule(t *testing.T) {
+	rules := []Rule{{ID: uuid.New(), ObservationCode: "K", CriticalLow: f(2.5), CriticalHigh: f(6.5)}}
+
+	b, ok := Evaluate(rules, "K", Subject{AgeYears: 40}, 7.1)
+	assert.True(t, ok)
+	assert.Equal(t, DirectionHigh, b.Direction)
+	assert.Equal(t, 6.5, b.Threshold)
+
+	b, ok = Evaluate(rules, "K", Subject{AgeYears: 40}, 2.5)
+	assert.True(t, ok)
+	assert.Equal(t, DirectionLow, b.Direction)
+
+	_, ok = Evaluate(rules, "K", Subject{AgeYears: 40}, 4.0)
+	assert.False(t, ok)
+
+	_, ok = Evaluate(rules, "NA", Subject{AgeYears: 40}, 100)
+	assert.False(t, ok)
+}
+
+func TestEvaluate_MostSpecificRuleWins(t *testing.T) {
+	female := model.GenderFemale
+	male := model.GenderMale
+	general := Rule{ID: uuid.New(), Name: "general", ObservationCode: "HGB", CriticalLow: f(7)}
+	neonate := Rule{ID: uuid.New(), Name: "neonate", ObservationCode: "HGB", MaxAgeYears: i(0), CriticalLow: f(9.5)}
+	adultFemale := Rule{ID: uuid.New(), Name: "adult female", ObservationCode: "HGB", Sex: &female, MinAgeYears: i(18), CriticalLow: f(7.5)}
+	rules := []Rule{general, neonate, adultFemale}
+
+	b, ok := Evaluate(rules, "HGB", Subject{AgeYears: 0}, 9.0)
+	assert.True(t, ok)
+	assert.Equal(t, "neonate", b.Rule.Name)
+
+	b, ok = Evaluate(rules, "HGB", Subject{AgeYears: 30, Sex: &female}, 7.4)
+	assert.True(t, ok)
+	assert.Equal(t, "adult female", b.Rule.Name)
+
+	// The male patient falls back to the general rule, which 7.4 does not breach.
+	_, ok = Evaluate(rules, "HGB", Subject{AgeYears: 30, Sex: &male}, 7.4)
+	assert.False(t, ok)
+
+	// Unknown sex never matches a sex-specific rule.
+	_, ok = Evaluate(rules, "HGB", Subject{AgeYears: 30}, 7.4)
+	assert.False(t, ok)
+}
+
+func TestAgeInYears(t *testing.T) {
+	dob := time.Date(2000, time.March, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 23, AgeInYears(dob, time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24, AgeInYears(dob, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, AgeInYears(dob, time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)))
+}
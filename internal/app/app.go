@@ -0,0 +1,243 @@
+// Package app wires the repositories and services the server and the command line share,
+// so both read the same environment and talk to the database the same way.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/himanshu-holmes/hms/db/migrations"
+	"github.com/himanshu-holmes/hms/internal/authorization"
+	"github.com/himanshu-holmes/hms/internal/config"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/himanshu-holmes/hms/internal/health"
+	"github.com/himanshu-holmes/hms/internal/insurance"
+	"github.com/himanshu-holmes/hms/internal/metrics"
+	"github.com/himanshu-holmes/hms/internal/migrate"
+	"github.com/himanshu-holmes/hms/internal/notify"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/research"
+	"github.com/himanshu-holmes/hms/internal/service"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/himanshu-holmes/hms/internal/worker"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// App holds the connection pool and everything built on it.
+type App struct {
+	Config *config.Config
+	Pool   *pgxpool.Pool
+	// Tokens issues and checks the JWTs the API is called with.
+	Tokens authorization.Authorization
+	// Logger is the structured logger every service and handler logs through.
+	Logger *slog.Logger
+
+	UserRepo         repository.UserRepository
+	PatientRepo      repository.PatientRepository
+	PatientVisitRepo repository.PatientVisitQuerier
+
+	Audit            service.AuditService
+	Users            service.AuthService
+	BreakGlass       service.BreakGlassService
+	CareTeam         service.CareTeamService
+	Patients         service.PatientService
+	Consent          service.ConsentService
+	Notifications    service.NotificationService
+	Billing          service.BillingService
+	Visits           service.PatientVisitService
+	Alerts           service.AlertService
+	Labs             service.LabService
+	Insurance        service.InsuranceService
+	Pharmacy         service.PharmacyService
+	Pseudonymization service.PseudonymizationService
+	PatientExports   service.PatientExportService
+	ResearchExports  service.ResearchExportService
+	FieldEncryption  service.FieldEncryptionService
+
+	// Health holds the checks behind /livez, /readyz and /startupz; subsystems add their own.
+	Health *health.Registry
+	// Workers tracks the background jobs for the readiness check.
+	Workers *worker.Monitor
+
+	// draining is set once the server is shutting down, so /readyz turns the replica
+	// out of the load balancer before it stops accepting connections.
+	draining atomic.Bool
+}
+
+// Connect opens a pool to the configured database, sized and timed out as configured,
+// and checks it answers.
+func Connect(ctx context.Context, cfg config.DB) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL.Value())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse DB_URL: %w", err)
+	}
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = multitracer.New(metrics.QueryTracer{}, tracing.QueryTracer{})
+	// The server sets statement_timeout on every connection, so a runaway query gives
+	// its connection back instead of holding it until the pool runs dry.
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	dbpool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+	if err := dbpool.Ping(ctx); err != nil {
+		dbpool.Close()
+		return nil, fmt.Errorf("unable to connect to database: %w", err)
+	}
+	return dbpool, nil
+}
+
+// PrepareSchema refuses to go on against a schema this binary was not built for. With
+// DB_AUTO_MIGRATE it applies pending migrations first; replicas starting together wait
+// for each other on the migration lock. It uses a connection of its own without the
+// statement timeout, which a long migration would otherwise hit.
+func PrepareSchema(ctx context.Context, cfg config.DB) error {
+	cfg.StatementTimeout = 0
+	dbpool, err := Connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dbpool.Close()
+
+	migrator, err := migrate.New(dbpool, migrations.FS, cfg.AutoMigrate)
+	if err != nil {
+		return fmt.Errorf("unable to load migrations: %w", err)
+	}
+	defer migrator.Close()
+
+	if cfg.AutoMigrate {
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("unable to apply migrations: %w", err)
+		}
+	}
+	if err := migrator.Check(ctx); err != nil {
+		if errors.Is(err, migrate.ErrSchemaBehind) {
+			return fmt.Errorf("%w; run \"hms migrate up\" or set DB_AUTO_MIGRATE=true", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// New builds the repositories and services over dbpool from a validated configuration,
+// logging to logger. The caller keeps ownership of the pool.
+func New(cfg *config.Config, dbpool *pgxpool.Pool, logger *slog.Logger) (*App, error) {
+	fieldCipher, err := fieldcrypt.NewCipherFromKeyFile(cfg.Encryption.FieldKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load field encryption keys: %w", err)
+	}
+
+	// Research exports stay disabled until a key for their pseudonymous IDs is set.
+	var researchIDs *research.Pseudonymizer
+	if cfg.Research.IDKey != "" {
+		key, err := research.ParseKey(cfg.Research.IDKey.Value())
+		if err != nil {
+			return nil, fmt.Errorf("RESEARCH_ID_KEY: %w", err)
+		}
+		if researchIDs, err = research.NewPseudonymizer(key); err != nil {
+			return nil, fmt.Errorf("RESEARCH_ID_KEY: %w", err)
+		}
+	} else {
+		logger.Warn("RESEARCH_ID_KEY is not set; research exports are disabled")
+	}
+
+	// Initialize the repositories
+	txManager := repository.NewTxManager(dbpool, fieldCipher, cfg.DB.TxOptions())
+	userRepo := repository.NewUserRepo(db.New(dbpool))
+	patientRepo := repository.NewPatientRepo(db.New(dbpool), fieldCipher)
+	patientVisitRepo := repository.NewPatientVisitRepo(db.New(dbpool), fieldCipher)
+	visitRevisionRepo := repository.NewVisitRevisionRepo(db.New(dbpool), fieldCipher)
+	labRepo := repository.NewLabRepo(db.New(dbpool))
+	alertRepo := repository.NewAlertRepo(dbpool)
+	billingRepo := repository.NewBillingRepo(db.New(dbpool))
+	insuranceRepo := repository.NewInsuranceRepo(db.New(dbpool))
+	pharmacyRepo := repository.NewPharmacyRepo(db.New(dbpool))
+	auditRepo := repository.NewAuditRepo(dbpool)
+	breakGlassRepo := repository.NewBreakGlassRepo(db.New(dbpool))
+	careTeamRepo := repository.NewCareTeamRepo(db.New(dbpool))
+	consentRepo := repository.NewConsentRepo(db.New(dbpool))
+	patientExportRepo := repository.NewPatientExportRepo(dbpool, fieldCipher)
+	pseudonymizationRepo := repository.NewPseudonymizationRepo(db.New(dbpool))
+	researchRepo := repository.NewResearchRepo(db.New(dbpool), fieldCipher)
+	fieldEncryptionRepo := repository.NewFieldEncryptionRepo(db.New(dbpool), fieldCipher)
+
+	// Initialize the services
+	a := &App{Config: cfg, Pool: dbpool, Logger: logger, UserRepo: userRepo, PatientRepo: patientRepo, PatientVisitRepo: patientVisitRepo}
+	a.Tokens = authorization.NewAuthorization([]byte(cfg.Auth.Secret.Value()), cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	a.Audit = service.NewAuditService(auditRepo, logger)
+	a.Users = service.NewAuthService(userRepo, txManager, a.Audit, a.Tokens, logger)
+	a.BreakGlass = service.NewBreakGlassService(breakGlassRepo, patientRepo, a.Audit, logger)
+	a.CareTeam = service.NewCareTeamService(careTeamRepo, txManager, patientRepo, userRepo, a.BreakGlass, a.Audit, logger)
+	a.Patients = service.NewPatientService(patientRepo, txManager, a.Audit, a.CareTeam, logger)
+	a.Consent = service.NewConsentService(consentRepo, txManager, patientRepo, a.Audit, a.CareTeam, logger)
+	a.Notifications = service.NewNotificationService(patientRepo, a.Consent, notify.NewLogSender(logger), a.Audit, a.CareTeam, logger)
+	a.Billing = service.NewBillingService(billingRepo, txManager, patientVisitRepo, patientRepo, time.Month(cfg.Billing.FiscalYearStartMonth), logger)
+	a.Visits = service.NewPatientVisitService(patientVisitRepo, visitRevisionRepo, txManager, patientRepo, a.Billing, a.Audit, a.CareTeam, logger)
+	a.Alerts = service.NewAlertService(alertRepo, patientVisitRepo, patientRepo, a.CareTeam, logger)
+	a.Labs = service.NewLabService(labRepo, txManager, patientVisitRepo, patientRepo, a.Alerts, a.Billing, a.CareTeam, logger)
+	a.Insurance = service.NewInsuranceService(insuranceRepo, patientRepo, a.Billing, a.Consent, txManager, payerGateway(cfg.Insurance, logger), logger)
+	a.Pharmacy = service.NewPharmacyService(pharmacyRepo, txManager, patientVisitRepo, a.CareTeam, logger)
+	a.Pseudonymization = service.NewPseudonymizationService(pseudonymizationRepo, txManager, patientRepo, a.Audit, logger)
+	a.PatientExports = service.NewPatientExportService(patientExportRepo, txManager, patientRepo, patientVisitRepo, labRepo, insuranceRepo, consentRepo, a.Consent, a.Audit, logger)
+	a.ResearchExports = service.NewResearchExportService(researchRepo, researchIDs, a.Audit, logger)
+	a.FieldEncryption = service.NewFieldEncryptionService(fieldEncryptionRepo, logger)
+
+	a.Workers = worker.NewMonitor(workerStuckAfter, logger)
+	a.Health = health.NewRegistry(cfg.Server.HealthCheckTimeout)
+	a.Health.Register("database", a.Pool.Ping, health.Ready, health.Startup)
+	a.Health.Register("schema", a.checkSchema, health.Ready, health.Startup)
+	a.Health.Register("workers", a.Workers.Check, health.Ready)
+	a.Health.Register("shutdown", a.checkNotDraining, health.Ready)
+	return a, nil
+}
+
+// workerStuckAfter is how long a background job run may take before the replica is
+// reported unready; patient exports are the slowest.
+const workerStuckAfter = 15 * time.Minute
+
+// checkSchema fails while migrations this binary relies on are missing, as when one was
+// rolled back under it. A newer schema passes: during a rolling update the new replicas
+// migrate while the old ones are still serving.
+func (a *App) checkSchema(ctx context.Context) error {
+	migrator, err := migrate.New(a.Pool, migrations.FS, false)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+	if err := migrator.Check(ctx); err != nil && !errors.Is(err, migrate.ErrSchemaAhead) {
+		return err
+	}
+	return nil
+}
+
+func (a *App) checkNotDraining(ctx context.Context) error {
+	if a.draining.Load() {
+		return errors.New("shutting down")
+	}
+	return nil
+}
+
+// payerGateway returns the configured payer integration, or nil when none is, which
+// turns off eligibility checks and claims.
+func payerGateway(cfg config.Insurance, logger *slog.Logger) insurance.PayerGateway {
+	switch cfg.Gateway {
+	case config.InsuranceGatewayFake:
+		logger.Warn("Using the fake payer gateway; claims are not sent to any payer")
+		return insurance.NewFakeGateway()
+	default:
+		return nil
+	}
+}
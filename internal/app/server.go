@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bugsnag/bugsnag-go-gin"
+	"github.com/bugsnag/bugsnag-go/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/docs"
+	"github.com/himanshu-holmes/hms/internal/handler"
+	"github.com/himanshu-holmes/hms/internal/health"
+	"github.com/himanshu-holmes/hms/internal/metrics"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// StartWorkers runs the background jobs until ctx is cancelled. The returned channel is
+// closed once every job has returned.
+func (a *App) StartWorkers(ctx context.Context) <-chan struct{} {
+	var wg sync.WaitGroup
+	run := func(name string, interval time.Duration, fn func(context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Workers.RunPeriodic(ctx, name, interval, fn)
+		}()
+	}
+	// Escalate unacknowledged critical alerts; safe to run on every replica.
+	run("alert-escalation", time.Minute, func(ctx context.Context) error {
+		_, err := a.Alerts.EscalateDueAlerts(ctx, time.Now())
+		return err
+	})
+	// Store yesterday's break-glass review once the UTC day is over; safe on every replica.
+	run("break-glass-review", time.Hour, func(ctx context.Context) error {
+		return a.BreakGlass.GenerateDueReports(ctx, time.Now())
+	})
+	// Build requested patient exports and delete expired ones; jobs are claimed with SKIP LOCKED.
+	run("patient-export", 30*time.Second, func(ctx context.Context) error {
+		if err := a.PatientExports.ProcessPendingExports(ctx, time.Now()); err != nil {
+			return err
+		}
+		return a.PatientExports.ExpireExports(ctx, time.Now())
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// Serve runs the API server and the background jobs until ctx is cancelled, then shuts
+// down in order: /readyz starts failing, and after SHUTDOWN_DELAY, long enough for the
+// load balancer to notice, the server stops accepting connections and waits for the
+// requests in flight; then the jobs are stopped. Draining the requests and stopping the
+// jobs share SHUTDOWN_TIMEOUT; whatever is still running then is cut off. The caller
+// closes the pool afterwards.
+func (a *App) Serve(ctx context.Context) error {
+	cfg := a.Config.Server
+	servers := []*http.Server{{
+		Addr:              ":" + strconv.Itoa(cfg.Port),
+		Handler:           a.Router(),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}}
+	if cfg.MetricsPort != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		servers = append(servers, &http.Server{
+			Addr:              ":" + strconv.Itoa(cfg.MetricsPort),
+			Handler:           mux,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		})
+	}
+
+	metrics.Registry.MustRegister(metrics.NewPoolCollector(a.Pool))
+
+	// The jobs get a context of their own so they keep running while requests drain.
+	workerCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+	workersDone := a.StartWorkers(workerCtx)
+
+	serveErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			serveErr <- srv.ListenAndServe()
+		}()
+		a.Logger.Info("Server listening", "addr", srv.Addr)
+	}
+
+	select {
+	case err := <-serveErr:
+		for _, srv := range servers {
+			srv.Close()
+		}
+		stopWorkers()
+		<-workersDone
+		return err
+	case <-ctx.Done():
+	}
+
+	a.draining.Store(true)
+	a.Logger.Info("Server shutting down; waiting before closing the listener", "delay", cfg.ShutdownDelay)
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			srv.Close()
+			errs = append(errs, fmt.Errorf("requests still in flight on %s were cut off: %w", srv.Addr, err))
+		}
+	}
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("background jobs did not stop before SHUTDOWN_TIMEOUT"))
+	}
+	for range servers {
+		if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	}
+	a.Logger.Info("Server stopped")
+	return errors.Join(errs...)
+}
+
+// Router builds the HTTP handlers and routes over the services.
+func (a *App) Router() *gin.Engine {
+	// Initialize the handlers
+	userHandler := handler.NewAuthHandler(a.Users, a.Logger)
+	patientHandler := handler.NewPatientHandler(a.Patients, a.Logger)
+	patientVisitHandler := handler.NewPatientVisitHandler(a.Visits, a.Logger)
+	labHandler := handler.NewLabHandler(a.Labs, a.Logger)
+	alertHandler := handler.NewAlertHandler(a.Alerts, a.Logger)
+	billingHandler := handler.NewBillingHandler(a.Billing, a.Logger)
+	insuranceHandler := handler.NewInsuranceHandler(a.Insurance, a.Logger)
+	pharmacyHandler := handler.NewPharmacyHandler(a.Pharmacy, a.Logger)
+	auditHandler := handler.NewAuditHandler(a.Audit, a.Logger)
+	breakGlassHandler := handler.NewBreakGlassHandler(a.BreakGlass, a.Logger)
+	careTeamHandler := handler.NewCareTeamHandler(a.CareTeam, a.Logger)
+	consentHandler := handler.NewConsentHandler(a.Consent, a.Logger)
+	notificationHandler := handler.NewNotificationHandler(a.Notifications, a.Logger)
+	patientExportHandler := handler.NewPatientExportHandler(a.PatientExports, a.Logger)
+	pseudonymizationHandler := handler.NewPseudonymizationHandler(a.Pseudonymization, a.Logger)
+	researchHandler := handler.NewResearchHandler(a.ResearchExports, a.Logger)
+
+	authenticated := middleware.AuthMiddleware(a.Tokens)
+
+	// Initialize the router
+	r := gin.New()
+	// Probes and scrapes are only logged when they fail, unless at debug level.
+	r.Use(middleware.AccessLog(a.Logger, "/livez", "/readyz", "/startupz", "/healthz", "/metrics"), middleware.Recovery(a.Logger))
+	r.Use(middleware.Tracing())
+	r.Use(bugsnaggin.AutoNotify(bugsnag.Configuration{
+		// Your Bugsnag project API key, from BUGSNAG_API_KEY
+		APIKey: a.Config.Bugsnag.APIKey.Value(),
+		// The import paths for the Go packages containing your source files
+		ProjectPackages: []string{"main", "github.com/org/myapp"},
+	}))
+	r.Use(middleware.Metrics())
+	r.Use(middleware.RequestContext())
+	if a.Config.Server.MetricsPort == 0 {
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
+	r.GET("/livez", a.Health.Handler(health.Live))
+	r.GET("/readyz", a.Health.Handler(health.Ready))
+	r.GET("/startupz", a.Health.Handler(health.Startup))
+	// Deprecated: kept for probes still pointing here; use /readyz.
+	r.GET("/healthz", a.Health.Handler(health.Ready))
+
+	api := r.Group("/api/v1")
+
+	// Swagger documentation
+	docs.SwaggerInfo.BasePath = "/api/v1"
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	{
+		//auth
+		api.POST("/auth/login", userHandler.Login)
+		api.POST("/auth/register", userHandler.CreateUser)
+		// patient
+		api.POST("/patients/create", authenticated, patientHandler.RegisterPatient)
+		api.GET("/patients/lookup", authenticated, patientHandler.LookupPatient)
+		api.GET("/patients/:id", authenticated, patientHandler.GetPatient)
+		api.GET("/patients", authenticated, patientHandler.ListPatients)
+		api.PATCH("/patients/:id", authenticated, patientHandler.UpdatePatient)
+		api.DELETE("/patients/:id", authenticated, patientHandler.DeletePatient)
+		api.POST("/patients/:id/care-team", authenticated, middleware.RoleMiddleware(model.RoleReceptionist, model.RoleDoctor), careTeamHandler.AddMember)
+		api.GET("/patients/:id/care-team", authenticated, careTeamHandler.ListMembers)
+		api.DELETE("/patients/:id/care-team/:userId", authenticated, middleware.RoleMiddleware(model.RoleReceptionist, model.RoleDoctor), careTeamHandler.RemoveMember)
+
+		api.GET("/consent-types", authenticated, consentHandler.ListConsentTypes)
+		api.POST("/patients/:id/consents", authenticated, middleware.RoleMiddleware(model.RoleReceptionist, model.RoleDoctor), consentHandler.RecordConsent)
+		api.GET("/patients/:id/consents", authenticated, consentHandler.ListConsents)
+		api.POST("/patients/:id/consents/:consentId/withdraw", authenticated, middleware.RoleMiddleware(model.RoleReceptionist, model.RoleDoctor), consentHandler.WithdrawConsent)
+		api.POST("/patients/:id/notifications/sms", authenticated, middleware.RoleMiddleware(model.RoleReceptionist, model.RoleDoctor), notificationHandler.SendSMS)
+		// visit
+		api.POST("/visits/create", authenticated, patientVisitHandler.RecordPatientVisit)
+		api.GET("/visits/:id", authenticated, patientVisitHandler.GetPatientVisitDetails)
+		api.GET("/visits/:id/list", authenticated, patientVisitHandler.ListPatientVisits)
+		api.PATCH("/visits/:id", authenticated, patientVisitHandler.UpdatePatientVisit)
+		api.POST("/visits/:id/finalize", authenticated, middleware.RoleMiddleware(model.RoleDoctor), patientVisitHandler.FinalizePatientVisit)
+		api.GET("/visits/:id/history", authenticated, patientVisitHandler.GetPatientVisitHistory)
+		// lab
+		api.POST("/lab/tests", authenticated, middleware.RoleMiddleware(model.RoleLabTechnician), labHandler.CreateLabTest)
+		api.GET("/lab/tests", authenticated, labHandler.ListLabTests)
+		api.POST("/lab/orders", authenticated, middleware.RoleMiddleware(model.RoleDoctor), labHandler.OrderLabTest)
+		api.GET("/lab/orders", authenticated, middleware.RoleMiddleware(model.RoleLabTechnician), labHandler.ListLabWorklist)
+		api.GET("/lab/orders/:id", authenticated, labHandler.GetLabOrder)
+		api.POST("/lab/orders/:id/collect", authenticated, middleware.RoleMiddleware(model.RoleLabTechnician), labHandler.MarkSpecimenCollected)
+		api.POST("/lab/orders/:id/results", authenticated, middleware.RoleMiddleware(model.RoleLabTechnician), labHandler.EnterLabResults)
+		api.POST("/lab/orders/:id/verify", authenticated, middleware.RoleMiddleware(model.RoleLabTechnician), labHandler.VerifyLabResults)
+		api.GET("/visits/:id/lab-orders", authenticated, labHandler.ListVisitLabOrders)
+		api.GET("/patients/:id/lab-results", authenticated, labHandler.GetCumulativeLabResults)
+		// alerts
+		api.POST("/visits/:id/observations", authenticated, middleware.RoleMiddleware(model.RoleDoctor), alertHandler.RecordObservation)
+		api.POST("/alerts/rules", authenticated, middleware.RoleMiddleware(model.RoleDoctor), alertHandler.CreateAlertRule)
+		api.GET("/alerts/rules", authenticated, alertHandler.ListAlertRules)
+		api.DELETE("/alerts/rules/:id", authenticated, middleware.RoleMiddleware(model.RoleDoctor), alertHandler.DeactivateAlertRule)
+		api.GET("/alerts", authenticated, middleware.RoleMiddleware(model.RoleDoctor), alertHandler.ListAlerts)
+		api.POST("/alerts/:id/acknowledge", authenticated, middleware.RoleMiddleware(model.RoleDoctor), alertHandler.AcknowledgeAlert)
+		// billing
+		api.POST("/billing/tax-categories", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.CreateTaxCategory)
+		api.GET("/billing/tax-categories", authenticated, billingHandler.ListTaxCategories)
+		api.POST("/billing/charge-items", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.CreateChargeItem)
+		api.GET("/billing/charge-items", authenticated, billingHandler.ListChargeItems)
+		api.POST("/billing/charges", authenticated, middleware.RoleMiddleware(model.RoleDoctor, model.RoleReceptionist), billingHandler.PostCharge)
+		api.POST("/billing/invoices", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.CreateInvoice)
+		api.GET("/billing/invoices/:id", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.GetInvoice)
+		api.POST("/billing/invoices/:id/payments", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.RecordPayment)
+		api.POST("/billing/payments/:id/refunds", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.RefundPayment)
+		api.GET("/patients/:id/charges", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.ListPatientCharges)
+		api.GET("/patients/:id/invoices", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.ListPatientInvoices)
+		api.GET("/patients/:id/balance", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), billingHandler.GetPatientBalance)
+		// insurance
+		api.POST("/patients/:id/insurance-policies", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.AddPolicy)
+		api.GET("/patients/:id/insurance-policies", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.ListPatientPolicies)
+		api.DELETE("/insurance-policies/:id", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.DeactivatePolicy)
+		api.POST("/insurance-policies/:id/eligibility", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.VerifyEligibility)
+		api.POST("/claims", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.CreateClaim)
+		api.GET("/claims", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.ListClaims)
+		api.GET("/claims/:id", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.GetClaim)
+		api.POST("/claims/:id/send", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.SendClaim)
+		api.POST("/claims/:id/status", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.UpdateClaimStatus)
+		api.GET("/billing/invoices/:id/claims", authenticated, middleware.RoleMiddleware(model.RoleReceptionist), insuranceHandler.ListInvoiceClaims)
+		// pharmacy
+		api.POST("/pharmacy/locations", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.CreateLocation)
+		api.GET("/pharmacy/locations", authenticated, pharmacyHandler.ListLocations)
+		api.POST("/pharmacy/items", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.CreateItem)
+		api.GET("/pharmacy/items", authenticated, pharmacyHandler.ListItems)
+		api.POST("/pharmacy/receipts", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.ReceiveStock)
+		api.POST("/pharmacy/adjustments", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.AdjustStock)
+		api.GET("/pharmacy/stock", authenticated, pharmacyHandler.ListStockLevels)
+		api.GET("/pharmacy/movements", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.ListMovements)
+		api.POST("/pharmacy/movements/:id/returns", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.ReturnDispensed)
+		api.GET("/pharmacy/reorder-alerts", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.ListReorderAlerts)
+		api.GET("/pharmacy/near-expiry", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.NearExpiryReport)
+		api.POST("/visits/:id/dispense", authenticated, middleware.RoleMiddleware(model.RolePharmacist), pharmacyHandler.DispenseForVisit)
+		api.GET("/visits/:id/dispensations", authenticated, pharmacyHandler.ListVisitDispensations)
+		// audit
+		api.GET("/audit/events", authenticated, middleware.RoleMiddleware(model.RoleAdmin), auditHandler.ListEvents)
+		api.GET("/audit/verify", authenticated, middleware.RoleMiddleware(model.RoleAdmin), auditHandler.VerifyChain)
+		api.GET("/patients/:id/access-log", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), auditHandler.PatientAccessLog)
+		// subject access exports
+		api.POST("/patients/:id/export", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), patientExportHandler.RequestExport)
+		api.GET("/patients/:id/export", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), patientExportHandler.ListExports)
+		api.GET("/patients/:id/export/:exportId", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), patientExportHandler.GetExport)
+		api.GET("/patients/:id/export/:exportId/download", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), patientExportHandler.DownloadExport)
+		// erasure
+		api.GET("/patients/:id/pseudonymization/preview", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), pseudonymizationHandler.PreviewPseudonymization)
+		api.POST("/patients/:id/pseudonymization", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), pseudonymizationHandler.RequestPseudonymization)
+		api.GET("/pseudonymization-requests", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), pseudonymizationHandler.ListRequests)
+		api.GET("/pseudonymization-requests/:id", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), pseudonymizationHandler.GetRequest)
+		api.POST("/pseudonymization-requests/:id/approve", authenticated, middleware.RoleMiddleware(model.RoleAdmin), pseudonymizationHandler.ApproveRequest)
+		api.POST("/pseudonymization-requests/:id/reject", authenticated, middleware.RoleMiddleware(model.RoleAdmin), pseudonymizationHandler.RejectRequest)
+		// research
+		api.GET("/research/dataset", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), researchHandler.ExportDataset)
+		// break glass
+		api.POST("/patients/:id/break-glass", authenticated, middleware.RoleMiddleware(model.RoleDoctor), breakGlassHandler.RequestAccess)
+		api.GET("/break-glass/reports", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), breakGlassHandler.ListReports)
+		api.GET("/break-glass/reports/:date", authenticated, middleware.RoleMiddleware(model.RoleAdmin, model.RolePrivacyOfficer), breakGlassHandler.GetReport)
+	}
+	return r
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chain(t *testing.T, n int) []model.AuditEvent {
+	t.Helper()
+	actor := uuid.New()
+	role := "doctor"
+	prev := GenesisHash
+	events := make([]model.AuditEvent, 0, n)
+	for i := 1; i <= n; i++ {
+		resource := uuid.New()
+		e := model.AuditEvent{
+			Seq:          int64(i),
+			OccurredAt:   time.Date(2026, time.May, 1, 10, 0, i, 123456789, time.UTC),
+			ActorUserID:  &actor,
+			ActorRole:    &role,
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &resource,
+			PatientID:    &resource,
+			Changes:      map[string]model.FieldChange{"address": {Old: "old street", New: "new street"}},
+			PrevHash:     prev,
+		}
+		hash, err := Hash(e)
+		require.NoError(t, err)
+		e.Hash = hash
+		prev = hash
+		events = append(events, e)
+	}
+	return events
+}
+
+func verify(events []model.AuditEvent) error {
+	v := NewVerifier()
+	for _, e := range events {
+		if err := v.Next(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	events := chain(t, 5)
+	v := NewVerifier()
+	for _, e := range events {
+		require.NoError(t, v.Next(e))
+	}
+	assert.Equal(t, int64(5), v.Checked())
+	assert.Equal(t, int64(5), v.LastSeq())
+}
+
+func TestVerifyDetectsEditedEvent(t *testing.T) {
+	events := chain(t, 3)
+	events[1].Changes["address"] = model.FieldChange{Old: "old street", New: "forged street"}
+
+	var chainErr *ChainError
+	require.ErrorAs(t, verify(events), &chainErr)
+	assert.Equal(t, int64(2), chainErr.Seq)
+}
+
+func TestVerifyDetectsDeletedEvent(t *testing.T) {
+	events := chain(t, 3)
+	events = append(events[:1], events[2:]...)
+
+	var chainErr *ChainError
+	require.ErrorAs(t, verify(events), &chainErr)
+	assert.Equal(t, int64(3), chainErr.Seq)
+}
+
+func TestVerifyDetectsRehashedEvent(t *testing.T) {
+	// Recomputing the edited event's own hash is not enough; the next link breaks.
+	events := chain(t, 3)
+	events[1].Action = model.AuditActionRead
+	hash, err := Hash(events[1])
+	require.NoError(t, err)
+	events[1].Hash = hash
+
+	var chainErr *ChainError
+	require.ErrorAs(t, verify(events), &chainErr)
+	assert.Equal(t, int64(3), chainErr.Seq)
+}
+
+func TestHashSurvivesStorageRoundTrip(t *testing.T) {
+	// Changes come back from JSONB as generic JSON values and timestamps at microsecond
+	// precision in the server's zone; the hash must not change.
+	e := chain(t, 1)[0]
+	raw, err := json.Marshal(e.Changes)
+	require.NoError(t, err)
+	var stored map[string]model.FieldChange
+	require.NoError(t, json.Unmarshal(raw, &stored))
+
+	loaded := e
+	loaded.Changes = stored
+	loaded.OccurredAt = e.OccurredAt.Truncate(Precision).In(time.FixedZone("IST", 19800))
+	hash, err := Hash(loaded)
+	require.NoError(t, err)
+	assert.Equal(t, e.Hash, hash)
+}
+
+func TestHashCoversBreakGlassGrant(t *testing.T) {
+	// Stripping the break-glass flag from an event to hide an emergency access must break
+	// the chain.
+	e := chain(t, 1)[0]
+	grant := uuid.New()
+	e.BreakGlassGrantID = &grant
+	flagged, err := Hash(e)
+	require.NoError(t, err)
+
+	e.BreakGlassGrantID = nil
+	unflagged, err := Hash(e)
+	require.NoError(t, err)
+	assert.NotEqual(t, flagged, unflagged)
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// Metadata is what the HTTP layer knows about who is making a request and from where.
+type Metadata struct {
+	ActorUserID *uuid.UUID
+	ActorRole   model.UserRole
+	ClientIP    string
+	UserAgent   string
+	RequestID   string
+	// BreakGlassGrantID is the emergency access grant the request relies on, if any.
+	BreakGlassGrantID *uuid.UUID
+}
+
+type contextKey struct{}
+
+// FromContext returns the request metadata stored in ctx, or the zero Metadata.
+func FromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(contextKey{}).(Metadata)
+	return md
+}
+
+// WithRequest stores the client and request identifiers in ctx.
+func WithRequest(ctx context.Context, clientIP, userAgent, requestID string) context.Context {
+	md := FromContext(ctx)
+	md.ClientIP, md.UserAgent, md.RequestID = clientIP, userAgent, requestID
+	return context.WithValue(ctx, contextKey{}, md)
+}
+
+// WithActor stores the authenticated user in ctx.
+func WithActor(ctx context.Context, userID uuid.UUID, role model.UserRole) context.Context {
+	md := FromContext(ctx)
+	md.ActorUserID, md.ActorRole = &userID, role
+	return context.WithValue(ctx, contextKey{}, md)
+}
+
+// WithBreakGlass marks ctx as relying on the given break-glass grant, so events recorded
+// under it are flagged.
+func WithBreakGlass(ctx context.Context, grantID uuid.UUID) context.Context {
+	md := FromContext(ctx)
+	md.BreakGlassGrantID = &grantID
+	return context.WithValue(ctx, contextKey{}, md)
+}
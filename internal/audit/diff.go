@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// ignoredFields change on every write and carry no information about what was done.
+var ignoredFields = map[string]bool{"updated_at": true, "version": true}
+
+// Redacted replaces the values of sensitiveFields in a diff, so the audit trail records
+// that they changed but not what they hold.
+const Redacted = "[redacted]"
+
+// sensitiveFields are identity fields, which the trail cannot keep because it cannot be
+// changed and pseudonymizing a patient must not leave their name behind in it, and the
+// columns encrypted at rest.
+var sensitiveFields = map[string]bool{
+	// Identity
+	"first_name":    true,
+	"last_name":     true,
+	"date_of_birth": true,
+	"address":       true,
+	"email":         true,
+	// Encrypted
+	"contact_phone":   true,
+	"contact_email":   true,
+	"medical_history": true,
+	"symptoms":        true,
+	"diagnosis":       true,
+	"prescription":    true,
+	"notes":           true,
+}
+
+// Diff compares the JSON encodings of before and after and returns the fields that
+// differ. Pass nil for before on create and for after on delete. Fields hidden from
+// JSON (json:"-") never appear in the diff, and sensitive fields appear with their
+// values redacted, also inside nested objects and lists.
+func Diff(before, after interface{}) (map[string]model.FieldChange, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]model.FieldChange)
+	for name, value := range old {
+		if ignoredFields[name] {
+			continue
+		}
+		if next, ok := updated[name]; !ok || !reflect.DeepEqual(value, next) {
+			changes[name] = model.FieldChange{Old: value, New: updated[name]}
+		}
+	}
+	for name, value := range updated {
+		if _, seen := old[name]; !seen && !ignoredFields[name] {
+			changes[name] = model.FieldChange{New: value}
+		}
+	}
+	for name, change := range changes {
+		if sensitiveFields[name] {
+			changes[name] = model.FieldChange{Old: redact(change.Old), New: redact(change.New)}
+		} else {
+			changes[name] = model.FieldChange{Old: redactNested(change.Old), New: redactNested(change.New)}
+		}
+	}
+	return changes, nil
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return Redacted
+}
+
+// redactNested redacts the sensitive fields of the objects within a decoded JSON value.
+func redactNested(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for name, value := range v {
+			if sensitiveFields[name] {
+				out[name] = redact(value)
+			} else {
+				out[name] = redactNested(value)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = redactNested(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// fields decodes v's JSON encoding into a map so values compare (and later hash) exactly
+// as they are stored in JSONB.
+func fields(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return map[string]interface{}{}, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode %T for diff: %w", v, err)
+	}
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("decode %T for diff: %w", v, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Name      string  `json:"name"`
+	Phone     *string `json:"phone,omitempty"`
+	Age       int     `json:"age"`
+	Secret    string  `json:"-"`
+	UpdatedAt string  `json:"updated_at"`
+	Version   int     `json:"version"`
+}
+
+func TestDiffUpdate(t *testing.T) {
+	phone := "555-0100"
+	before := record{Name: "Asha", Age: 40, Secret: "a", UpdatedAt: "t1", Version: 1}
+	after := record{Name: "Asha", Phone: &phone, Age: 41, Secret: "b", UpdatedAt: "t2", Version: 2}
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.FieldChange{
+		"phone": {New: "555-0100"},
+		"age":   {Old: float64(40), New: float64(41)},
+	}, changes)
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	r := &record{Name: "Asha", Age: 40}
+
+	created, err := Diff(nil, r)
+	require.NoError(t, err)
+	assert.Equal(t, model.FieldChange{New: "Asha"}, created["name"])
+
+	var none *record
+	deleted, err := Diff(r, none)
+	require.NoError(t, err)
+	assert.Equal(t, model.FieldChange{Old: "Asha"}, deleted["name"])
+	assert.NotContains(t, deleted, "updated_at")
+}
+
+func TestDiffRedactsSensitiveFields(t *testing.T) {
+	type visit struct {
+		Diagnosis *string `json:"diagnosis,omitempty"`
+		Notes     *string `json:"notes,omitempty"`
+	}
+	before, after := "J45", "J45.909"
+	changes, err := Diff(visit{Diagnosis: &before}, visit{Diagnosis: &after})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.FieldChange{"diagnosis": {Old: Redacted, New: Redacted}}, changes)
+
+	note := "follow up in a week"
+	changes, err = Diff(nil, visit{Notes: &note})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.FieldChange{"notes": {New: Redacted}}, changes)
+}
+
+func TestDiffRedactsPatientIdentifiers(t *testing.T) {
+	type patient struct {
+		FirstName   string  `json:"first_name"`
+		LastName    string  `json:"last_name"`
+		DateOfBirth string  `json:"date_of_birth"`
+		Address     *string `json:"address,omitempty"`
+		Gender      string  `json:"gender"`
+	}
+	address := "12 MG Road"
+	changes, err := Diff(nil, patient{FirstName: "Asha", LastName: "Kumar", DateOfBirth: "1984-03-02", Address: &address, Gender: "female"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.FieldChange{
+		"first_name":    {New: Redacted},
+		"last_name":     {New: Redacted},
+		"date_of_birth": {New: Redacted},
+		"address":       {New: Redacted},
+		"gender":        {New: "female"},
+	}, changes)
+}
+
+func TestDiffRedactsIdentityFieldsInNestedObjects(t *testing.T) {
+	type member struct {
+		Username  string  `json:"username"`
+		FirstName *string `json:"first_name,omitempty"`
+		Email     *string `json:"email,omitempty"`
+	}
+	type team struct {
+		Members []member `json:"members"`
+		Lead    member   `json:"lead"`
+	}
+	name, email := "Rao", "rao@example.com"
+	lead := member{Username: "dr.rao", FirstName: &name, Email: &email}
+
+	changes, err := Diff(nil, team{Members: []member{lead}, Lead: lead})
+	require.NoError(t, err)
+	redacted := map[string]interface{}{"username": "dr.rao", "first_name": Redacted, "email": Redacted}
+	assert.Equal(t, map[string]model.FieldChange{
+		"members": {New: []interface{}{redacted}},
+		"lead":    {New: redacted},
+	}, changes)
+
+	changes, err = Diff(nil, lead)
+	require.NoError(t, err)
+	assert.Equal(t, model.FieldChange{New: Redacted}, changes["email"])
+}
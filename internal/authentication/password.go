@@ -1,7 +1,7 @@
 package authentication
 
 import (
-	"log" // For logging errors
+	"log/slog"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -14,7 +14,7 @@ func HashPassword(password string) (string, error) {
 	// bcrypt.GenerateFromPassword returns []byte, so we convert to string.
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), DefaultCost)
 	if err != nil {
-		log.Printf("Error generating password hash: %v", err) 
+		slog.Error("Error generating password hash", "error", err)
 		return "", err                                       
 	}
 	return string(bytes), nil
@@ -26,7 +26,7 @@ func CheckPasswordHash(password, hash string) bool {
 	
 	if err != nil && err != bcrypt.ErrMismatchedHashAndPassword {
 		// Log unexpected errors during password comparison, as they might indicate issues.
-		log.Printf("Error comparing password hash (potential hash issue, not just mismatch): %v", err)
+		slog.Error("Error comparing password hash (potential hash issue, not just mismatch)", "error", err)
 	}
 	return err == nil
 }
@@ -0,0 +1,79 @@
+// Package billing holds the money arithmetic for invoices. All amounts are int64 minor
+// units (e.g. paise or cents) and all rates are basis points, so no floats are involved.
+package billing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// BasisPoints is the denominator for rates: 10000 bps = 100%.
+const BasisPoints = 10000
+
+// Line is one charge to be invoiced.
+type Line struct {
+	Quantity       int64
+	UnitPriceMinor int64
+	TaxRateBps     int64
+}
+
+// LineAmounts is the priced breakdown of a line.
+type LineAmounts struct {
+	Gross    int64 // quantity * unit price
+	Discount int64
+	Tax      int64 // charged on Gross - Discount
+	Total    int64 // Gross - Discount + Tax
+}
+
+// ApplyBps returns amount * bps / 10000, rounded half up. amount and bps must be non-negative.
+func ApplyBps(amount, bps int64) int64 {
+	return (amount*bps + BasisPoints/2) / BasisPoints
+}
+
+// PriceLine prices a line with a percentage discount (in bps) applied before tax.
+func PriceLine(l Line, discountBps int64) LineAmounts {
+	gross := l.Quantity * l.UnitPriceMinor
+	discount := ApplyBps(gross, discountBps)
+	tax := ApplyBps(gross-discount, l.TaxRateBps)
+	return LineAmounts{Gross: gross, Discount: discount, Tax: tax, Total: gross - discount + tax}
+}
+
+// Sum adds up the amounts of several lines.
+func Sum(lines []LineAmounts) LineAmounts {
+	var total LineAmounts
+	for _, l := range lines {
+		total.Gross += l.Gross
+		total.Discount += l.Discount
+		total.Tax += l.Tax
+		total.Total += l.Total
+	}
+	return total
+}
+
+// FiscalYear returns the fiscal year containing t, labelled by the calendar year it starts in.
+// With startMonth April, 2026-03-31 is in fiscal year 2025 and 2026-04-01 in 2026.
+func FiscalYear(t time.Time, startMonth time.Month) int {
+	if t.Month() < startMonth {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// InvoiceNumber formats a gapless per-year sequence number, e.g. INV-2026-000042.
+func InvoiceNumber(fiscalYear, sequence int) string {
+	return fmt.Sprintf("INV-%d-%06d", fiscalYear, sequence)
+}
+
+// InvoiceStatus derives the payment status of an invoice from its total and net paid amount.
+func InvoiceStatus(totalMinor, paidMinor int64) model.InvoiceStatus {
+	switch {
+	case paidMinor >= totalMinor:
+		return model.InvoiceStatusPaid
+	case paidMinor > 0:
+		return model.InvoiceStatusPartiallyPaid
+	default:
+		return model.InvoiceStatusIssued
+	}
+}
@@ -0,0 +1,37 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceLine(t *testing.T) {
+	// 3 x 333.33 with 10% discount and 18% tax.
+	got := PriceLine(Line{Quantity: 3, UnitPriceMinor: 33333, TaxRateBps: 1800}, 1000)
+	assert.Equal(t, LineAmounts{Gross: 99999, Discount: 10000, Tax: 16200, Total: 106199}, got)
+
+	got = PriceLine(Line{Quantity: 1, UnitPriceMinor: 50000}, 0)
+	assert.Equal(t, LineAmounts{Gross: 50000, Total: 50000}, got)
+}
+
+func TestApplyBpsRoundsHalfUp(t *testing.T) {
+	assert.Equal(t, int64(1), ApplyBps(5, 1000)) // 0.5 -> 1
+	assert.Equal(t, int64(0), ApplyBps(4, 1000)) // 0.4 -> 0
+	assert.Equal(t, int64(100), ApplyBps(100, BasisPoints))
+}
+
+func TestFiscalYear(t *testing.T) {
+	assert.Equal(t, 2025, FiscalYear(time.Date(2026, time.March, 31, 23, 0, 0, 0, time.UTC), time.April))
+	assert.Equal(t, 2026, FiscalYear(time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), time.April))
+	assert.Equal(t, 2026, FiscalYear(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.January))
+}
+
+func TestInvoiceStatus(t *testing.T) {
+	assert.Equal(t, model.InvoiceStatusIssued, InvoiceStatus(1000, 0))
+	assert.Equal(t, model.InvoiceStatusPartiallyPaid, InvoiceStatus(1000, 400))
+	assert.Equal(t, model.InvoiceStatusPaid, InvoiceStatus(1000, 1000))
+	assert.Equal(t, model.InvoiceStatusPaid, InvoiceStatus(0, 0))
+}
@@ -0,0 +1,78 @@
+// Package breakglass holds the rules for emergency access: how long a grant lasts and how
+// a day of grants and accesses is assembled into the compliance review report.
+package breakglass
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// DefaultDuration is how long a grant lasts when the request does not say.
+const DefaultDuration = time.Hour
+
+// DateLayout is the report date format.
+const DateLayout = "2006-01-02"
+
+// ExpiresAt returns when a grant issued at grantedAt for the requested number of minutes
+// ends. Bounds on minutes are enforced by request validation.
+func ExpiresAt(grantedAt time.Time, minutes *int) time.Time {
+	if minutes == nil {
+		return grantedAt.Add(DefaultDuration)
+	}
+	return grantedAt.Add(time.Duration(*minutes) * time.Minute)
+}
+
+// DayBounds returns the UTC day containing t as a half-open window [start, end).
+func DayBounds(t time.Time) (start, end time.Time) {
+	y, m, d := t.UTC().Date()
+	start = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// PreviousDay returns the start of the UTC day before the one containing now: the most
+// recent day that is complete and can be reported.
+func PreviousDay(now time.Time) time.Time {
+	start, _ := DayBounds(now)
+	return start.AddDate(0, 0, -1)
+}
+
+// Grant is a grant as loaded for review, with the requesting user's name.
+type Grant struct {
+	model.BreakGlassGrant
+	Username string
+}
+
+// BuildReport assembles the review of the UTC day starting at day. grants are those issued
+// that day or used that day; accesses are every flagged access that day. Each grant lists
+// its own accesses in order; accesses to grants missing from grants are still counted.
+func BuildReport(day time.Time, grants []Grant, accesses []model.BreakGlassAccess, generatedAt time.Time) model.BreakGlassReport {
+	start, end := DayBounds(day)
+	report := model.BreakGlassReport{
+		Date:        start.Format(DateLayout),
+		GeneratedAt: generatedAt,
+		AccessCount: len(accesses),
+		Grants:      make([]model.BreakGlassReviewItem, 0, len(grants)),
+	}
+
+	byGrant := make(map[uuid.UUID][]model.BreakGlassAccess)
+	for _, a := range accesses {
+		byGrant[a.GrantID] = append(byGrant[a.GrantID], a)
+	}
+	for _, g := range grants {
+		if !g.GrantedAt.Before(start) && g.GrantedAt.Before(end) {
+			report.GrantCount++
+		}
+		items := byGrant[g.ID]
+		if items == nil {
+			items = []model.BreakGlassAccess{}
+		}
+		report.Grants = append(report.Grants, model.BreakGlassReviewItem{
+			BreakGlassGrant: g.BreakGlassGrant,
+			Username:        g.Username,
+			Accesses:        items,
+		})
+	}
+	return report
+}
@@ -0,0 +1,54 @@
+package breakglass
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiresAt(t *testing.T) {
+	granted := time.Date(2026, time.May, 1, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, granted.Add(time.Hour), ExpiresAt(granted, nil))
+
+	minutes := 15
+	assert.Equal(t, granted.Add(15*time.Minute), ExpiresAt(granted, &minutes))
+}
+
+func TestDayBoundsUseUTC(t *testing.T) {
+	// 02:00 in India on 2 May is still 1 May in UTC.
+	ist := time.FixedZone("IST", 19800)
+	start, end := DayBounds(time.Date(2026, time.May, 2, 2, 0, 0, 0, ist))
+	assert.Equal(t, time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC), end)
+
+	assert.Equal(t, time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC), PreviousDay(time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestBuildReport(t *testing.T) {
+	day := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	issued := Grant{BreakGlassGrant: model.BreakGlassGrant{ID: uuid.New(), GrantedAt: day.Add(9 * time.Hour)}, Username: "dr.rao"}
+	carried := Grant{BreakGlassGrant: model.BreakGlassGrant{ID: uuid.New(), GrantedAt: day.Add(-30 * time.Minute)}, Username: "dr.mehta"}
+	idle := Grant{BreakGlassGrant: model.BreakGlassGrant{ID: uuid.New(), GrantedAt: day.Add(20 * time.Hour)}, Username: "dr.rao"}
+	accesses := []model.BreakGlassAccess{
+		{Seq: 1, GrantID: carried.ID, Action: model.AuditActionRead},
+		{Seq: 2, GrantID: issued.ID, Action: model.AuditActionRead},
+		{Seq: 3, GrantID: issued.ID, Action: model.AuditActionUpdate},
+	}
+	generated := day.AddDate(0, 0, 1).Add(time.Minute)
+
+	report := BuildReport(day, []Grant{carried, issued, idle}, accesses, generated)
+	assert.Equal(t, "2026-05-01", report.Date)
+	assert.Equal(t, generated, report.GeneratedAt)
+	assert.Equal(t, 2, report.GrantCount, "the grant carried over from the day before is not counted")
+	assert.Equal(t, 3, report.AccessCount)
+	require.Len(t, report.Grants, 3)
+	assert.Equal(t, "dr.mehta", report.Grants[0].Username)
+	assert.Len(t, report.Grants[0].Accesses, 1)
+	assert.Equal(t, []int64{2, 3}, []int64{report.Grants[1].Accesses[0].Seq, report.Grants[1].Accesses[1].Seq})
+	assert.NotNil(t, report.Grants[2].Accesses)
+	assert.Empty(t, report.Grants[2].Accesses)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/himanshu-holmes/hms/internal/logging"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/service"
+)
+
+func (c *command) audit(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] != "verify" {
+		fmt.Fprintln(c.stderr, "usage: hms audit verify [-json]")
+		return ExitUsage
+	}
+	fs := c.flags("audit verify")
+	if code, ok := c.parse(fs, args[1:]); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+
+	// Only the audit tables are read, so only the database settings are needed.
+	dbpool, err := c.openDB(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer dbpool.Close()
+	result, err := service.NewAuditService(repository.NewAuditRepo(dbpool), logging.New(c.stderr, slog.LevelWarn, logging.FormatText)).VerifyChain(ctx)
+	if err != nil {
+		return c.fail(ExitError, fmt.Errorf("verify audit trail: %w", err))
+	}
+
+	c.result(result, func(w io.Writer) {
+		if result.Valid {
+			fmt.Fprintf(w, "audit trail intact: %d events verified\n", result.EventsChecked)
+			return
+		}
+		fmt.Fprintf(w, "audit trail BROKEN at seq %d after %d intact events: %s\n", *result.BrokenAtSeq, result.EventsChecked, result.Reason)
+	})
+	if !result.Valid {
+		return ExitCheckFailed
+	}
+	return ExitOK
+}
@@ -0,0 +1,218 @@
+// Package cli implements the hms command: the server and the operator commands run
+// against the same configuration and services.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/app"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/config"
+	"github.com/himanshu-holmes/hms/internal/logging"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Exit codes. Scripts can tell a failed check from a command that could not run.
+const (
+	ExitOK          = 0
+	ExitError       = 1 // The command failed
+	ExitUsage       = 2 // Unknown command or invalid arguments
+	ExitCheckFailed = 3 // The command ran and found a problem, like a broken audit chain
+)
+
+const usage = `usage: hms [-config FILE] [command] [flags]
+
+-config names a YAML config file; the default is $CONFIG_FILE.
+
+commands:
+  serve                        run the API server (the default)
+  config                       print the effective configuration and check it
+  migrate up|down|redo|status  change or list the schema migrations
+  migrate create NAME          add an empty migration
+  user create                  create a user of any role
+  user deactivate USERNAME     stop a user from logging in
+  user reset-password USERNAME set a new password
+  patient import               register patients from a CSV or JSON lines file
+  patient export               write a patient's subject access export
+  audit verify                 check the audit trail hash chain
+  encryption reencrypt         rewrite encrypted columns under the active master key
+  research export              write a de-identified dataset of consenting patients
+  seed                         create a user per role and sample patients
+
+Commands that print results take -json for machine-readable output.
+Run "hms <command> -h" for its flags.`
+
+// command is the state every command shares.
+type command struct {
+	stdout, stderr io.Writer
+	json           bool
+	stdin          io.Reader
+	configFile     string
+}
+
+// Run runs the hms command line and returns its exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	cmd := &command{stdout: stdout, stderr: stderr, stdin: os.Stdin}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	global := flag.NewFlagSet("hms", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { fmt.Fprintln(stderr, usage) }
+	global.StringVar(&cmd.configFile, "config", "", "YAML config file")
+	if code, ok := cmd.parse(global, args); !ok {
+		return code
+	}
+	args = global.Args()
+	if len(args) == 0 {
+		return cmd.serve(ctx, nil)
+	}
+	name, rest := args[0], args[1:]
+	switch name {
+	case "serve":
+		return cmd.serve(ctx, rest)
+	case "config":
+		return cmd.config(rest)
+	case "migrate":
+		return cmd.migrate(ctx, rest)
+	case "user":
+		return cmd.user(ctx, rest)
+	case "patient":
+		return cmd.patient(ctx, rest)
+	case "audit":
+		return cmd.audit(ctx, rest)
+	case "encryption":
+		return cmd.encryption(ctx, rest)
+	case "research":
+		return cmd.research(ctx, rest)
+	case "seed":
+		return cmd.seed(ctx, rest)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprintln(stdout, usage)
+		return ExitOK
+	}
+	fmt.Fprintf(stderr, "hms: unknown command %q\n\n%s\n", name, usage)
+	return ExitUsage
+}
+
+// flags returns a flag set for the named command with the -json flag registered.
+func (c *command) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("hms "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.BoolVar(&c.json, "json", false, "print results as JSON")
+	return fs
+}
+
+// parse parses args into fs and reports the exit code to return when parsing fails.
+func (c *command) parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+	return ExitOK, true
+}
+
+// usageError prints msg and the command's flags.
+func (c *command) usageError(fs *flag.FlagSet, msg string) int {
+	fmt.Fprintf(c.stderr, "%s: %s\n", fs.Name(), msg)
+	fs.Usage()
+	return ExitUsage
+}
+
+// result prints v as JSON in -json mode and calls text otherwise.
+func (c *command) result(v any, text func(w io.Writer)) {
+	if c.json {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(v)
+		return
+	}
+	text(c.stdout)
+}
+
+// fail reports err, on stdout as {"error": ...} in -json mode so scripts read one stream,
+// and returns code.
+func (c *command) fail(code int, err error) int {
+	if c.json {
+		c.result(map[string]string{"error": err.Error()}, nil)
+	} else {
+		fmt.Fprintf(c.stderr, "hms: %v\n", err)
+	}
+	return code
+}
+
+// loadConfig loads the configuration without checking it.
+func (c *command) loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(c.configFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// dbConfig loads the configuration and checks only its database settings, for the
+// commands that need nothing else.
+func (c *command) dbConfig() (config.DB, error) {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return config.DB{}, err
+	}
+	if err := cfg.DB.Validate(); err != nil {
+		return config.DB{}, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg.DB, nil
+}
+
+// openDB connects to the database with a configuration whose database settings are
+// valid.
+func (c *command) openDB(ctx context.Context) (*pgxpool.Pool, error) {
+	cfg, err := c.dbConfig()
+	if err != nil {
+		return nil, err
+	}
+	return app.Connect(ctx, cfg)
+}
+
+// open checks the whole configuration, connects to the database, refuses a schema this
+// binary was not built for and builds the services, as the server does.
+func (c *command) open(ctx context.Context) (*app.App, error) {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	level, _ := cfg.Log.SlogLevel() // checked by Validate
+	logger := logging.New(c.stderr, level, cfg.Log.Format)
+	if err := app.PrepareSchema(ctx, cfg.DB); err != nil {
+		return nil, err
+	}
+	pool, err := app.Connect(ctx, cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+	a, err := app.New(cfg, pool, logger)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// auditContext marks what a command changes as coming from the command line, so the audit
+// trail can tell it from API traffic.
+func auditContext(ctx context.Context, name string) context.Context {
+	return audit.WithRequest(ctx, "", "hms-cli/"+name, uuid.NewString())
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunUsage(t *testing.T) {
+	code, stdout, _ := run("help")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "usage: hms")
+
+	tests := [][]string{
+		{"frobnicate"},
+		{"user"},
+		{"user", "promote"},
+		{"user", "deactivate"},
+		{"user", "create", "-bogus"},
+		{"patient", "import"},
+		{"patient", "export", "-id", "not-a-uuid", "-as", "admin"},
+		{"audit"},
+		{"encryption"},
+		{"encryption", "rotate"},
+		{"research"},
+		{"research", "export"},
+		{"research", "export", "-as", "admin", "-format", "xml"},
+		{"research", "export", "-as", "admin", "-dates", "exact"},
+		{"migrate"},
+		{"migrate", "sideways"},
+		{"migrate", "create"},
+		{"seed", "-password", "secret1", "-patients", "-1"},
+		{"seed"},
+		{"seed", "-password", "short"},
+		{"seed", "-password", "secret1", "-password-stdin"},
+	}
+	for _, args := range tests {
+		code, _, stderr := run(args...)
+		assert.Equal(t, ExitUsage, code, "hms %s", strings.Join(args, " "))
+		assert.NotEmpty(t, stderr, "hms %s", strings.Join(args, " "))
+	}
+}
+
+func TestUserCreateValidatesBeforeConnecting(t *testing.T) {
+	code, stdout, _ := run("user", "create", "-json", "-username", "x", "-role", "janitor")
+	assert.Equal(t, ExitUsage, code)
+	var out map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Contains(t, out["error"], "role")
+	assert.Contains(t, out["error"], "username")
+}
+
+func TestMigrateCreate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "004_existing.sql"), nil, 0o644))
+	t.Setenv("DB_MIGRATIONS_DIR", dir)
+
+	code, stdout, _ := run("migrate", "-json", "create", "ward beds")
+	require.Equal(t, ExitOK, code)
+	var out map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, filepath.Join(dir, "005_ward_beds.sql"), out["path"])
+	assert.FileExists(t, out["path"])
+}
+
+func TestReadPatients(t *testing.T) {
+	csvRows, err := readPatients(strings.NewReader(
+		"first_name,last_name,date_of_birth,gender,contact_email\n"+
+			"Asha,Kumar,1980-02-03,female,\n"+
+			"Ben,Okafor,1975-11-30,,ben@example.com\n"), "csv")
+	require.NoError(t, err)
+	require.Len(t, csvRows, 2)
+	assert.Equal(t, 2, csvRows[0].Line)
+	assert.Equal(t, model.GenderFemale, *csvRows[0].Request.Gender)
+	assert.Nil(t, csvRows[0].Request.ContactEmail)
+	assert.Nil(t, csvRows[1].Request.Gender)
+	assert.Equal(t, "ben@example.com", *csvRows[1].Request.ContactEmail)
+
+	jsonRows, err := readPatients(strings.NewReader(
+		`{"first_name":"Asha","last_name":"Kumar","date_of_birth":"1980-02-03"}`+"\n\n"+
+			`{"first_name":"Ben","last_name":"Okafor","date_of_birth":"1975-11-30"}`+"\n"), "jsonl")
+	require.NoError(t, err)
+	require.Len(t, jsonRows, 2)
+	assert.Equal(t, 3, jsonRows[1].Line)
+
+	_, err = readPatients(strings.NewReader("first_name,ssn\nAsha,123\n"), "csv")
+	assert.ErrorContains(t, err, "ssn")
+	_, err = readPatients(strings.NewReader(`{"first_name":"Asha","ssn":"123"}`), "jsonl")
+	assert.ErrorContains(t, err, "line 1")
+	_, err = readPatients(strings.NewReader(""), "xml")
+	assert.Error(t, err)
+}
+
+func TestValidatePatient(t *testing.T) {
+	parsed, err := validatePatient(model.PatientCreateRequest{FirstName: "Asha", LastName: "Kumar", DateOfBirth: "1980-02-03"})
+	require.NoError(t, err)
+	assert.Equal(t, 1980, parsed.DateOfBirth.Year())
+
+	_, err = validatePatient(model.PatientCreateRequest{FirstName: "Asha", DateOfBirth: "03/02/1980"})
+	assert.ErrorContains(t, err, "last_name")
+	assert.ErrorContains(t, err, "date_of_birth")
+}
+
+func TestImportDryRunReportsInvalidRows(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "patients.csv")
+	require.NoError(t, os.WriteFile(file, []byte("first_name,last_name,date_of_birth\nAsha,Kumar,1980-02-03\nBen,,1975-11-30\n"), 0o644))
+
+	code, stdout, _ := run("patient", "import", "-dry-run", "-json", "-file", file)
+	assert.Equal(t, ExitError, code)
+	var out importResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, 1, out.Failed)
+	require.Len(t, out.Rows, 1)
+	assert.Equal(t, 3, out.Rows[0].Line)
+}
+
+func TestConfigCommand(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "field-keys.json")
+	require.NoError(t, os.WriteFile(keyFile, []byte("{}"), 0o600))
+	t.Setenv("DB_URL", "postgres://hms:hunter2@db/hms")
+	t.Setenv("FIELD_KEY_FILE", keyFile)
+	t.Setenv("SECRET", "")
+
+	code, stdout, _ := run("config", "-json")
+	assert.Equal(t, ExitCheckFailed, code)
+	assert.NotContains(t, stdout, "hunter2")
+	var out struct {
+		Config map[string]map[string]any `json:"config"`
+		Valid  bool                      `json:"valid"`
+		Error  string                    `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.False(t, out.Valid)
+	assert.Contains(t, out.Error, "SECRET")
+	assert.Equal(t, "[redacted]", out.Config["db"]["url"])
+
+	t.Setenv("SECRET", "jwt-secret")
+	code, stdout, _ = run("config")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "port: 3000")
+	assert.NotContains(t, stdout, "jwt-secret")
+}
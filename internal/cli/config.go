@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (c *command) config(args []string) int {
+	fs := c.flags("config")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return c.fail(ExitCheckFailed, err)
+	}
+
+	// Secrets print as a placeholder, so the output is safe to share.
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return c.fail(ExitError, err)
+	}
+	out := buf.Bytes()
+	validationErr := cfg.Validate()
+	if c.json {
+		// Go through YAML so the keys are the config file's.
+		var doc map[string]any
+		if err := yaml.Unmarshal(out, &doc); err != nil {
+			return c.fail(ExitError, err)
+		}
+		result := map[string]any{"config": doc, "valid": validationErr == nil}
+		if validationErr != nil {
+			result["error"] = validationErr.Error()
+		}
+		c.result(result, nil)
+	} else {
+		c.result(nil, func(w io.Writer) { w.Write(out) })
+		if validationErr != nil {
+			fmt.Fprintf(c.stderr, "invalid configuration:\n%v\n", validationErr)
+		}
+	}
+	if validationErr != nil {
+		return ExitCheckFailed
+	}
+	return ExitOK
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/himanshu-holmes/hms/db/migrations"
+	"github.com/himanshu-holmes/hms/internal/app"
+	"github.com/himanshu-holmes/hms/internal/migrate"
+	"github.com/pressly/goose/v3"
+)
+
+// migrationStatus is one line of "hms migrate status".
+type migrationStatus struct {
+	Version   int64      `json:"version"`
+	Path      string     `json:"path"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// migrationResult is one migration "hms migrate up|down|redo" ran.
+type migrationResult struct {
+	Version   int64  `json:"version"`
+	Path      string `json:"path"`
+	Direction string `json:"direction"`
+	Duration  string `json:"duration"`
+}
+
+func (c *command) migrate(ctx context.Context, args []string) int {
+	fs := c.flags("migrate")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), `usage: hms migrate [-json] <command>
+
+commands:
+  up            apply every pending migration
+  down          roll back the latest migration
+  redo          roll back the latest migration and apply it again
+  status        list migrations and whether they are applied
+  create NAME   add an empty migration to DB_MIGRATIONS_DIR (default db/migrations)`)
+		fs.PrintDefaults()
+	}
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		return c.usageError(fs, "missing command")
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return c.usageError(fs, "create takes the migration name")
+		}
+		cfg, err := c.loadConfig()
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		path, err := migrate.Create(cfg.DB.MigrationsDir, args[1])
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		c.result(map[string]string{"path": path}, func(w io.Writer) { fmt.Fprintln(w, path) })
+		return ExitOK
+	}
+	switch args[0] {
+	case "up", "down", "redo", "status":
+	default:
+		return c.usageError(fs, fmt.Sprintf("unknown command %q", args[0]))
+	}
+	if len(args) != 1 {
+		return c.usageError(fs, args[0]+" takes no arguments")
+	}
+
+	cfg, err := c.dbConfig()
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	// A migration may run longer than any query the server should.
+	cfg.StatementTimeout = 0
+	dbpool, err := app.Connect(ctx, cfg)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer dbpool.Close()
+	// Goose logs each migration as it runs; keep stdout for the JSON document.
+	migrator, err := migrate.New(dbpool, migrations.FS, !c.json)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer migrator.Close()
+
+	var results []*goose.MigrationResult
+	switch args[0] {
+	case "up":
+		results, err = migrator.Up(ctx)
+	case "down":
+		var result *goose.MigrationResult
+		if result, err = migrator.Down(ctx); result != nil {
+			results = append(results, result)
+		}
+	case "redo":
+		results, err = migrator.Redo(ctx)
+	case "status":
+		var statuses []*goose.MigrationStatus
+		if statuses, err = migrator.Status(ctx); err != nil {
+			return c.fail(ExitError, fmt.Errorf("migrate status: %w", err))
+		}
+		c.printMigrationStatus(statuses)
+		return ExitOK
+	}
+	if err != nil {
+		return c.fail(ExitError, fmt.Errorf("migrate %s: %w", args[0], err))
+	}
+	if c.json {
+		out := make([]migrationResult, 0, len(results))
+		for _, r := range results {
+			out = append(out, migrationResult{Version: r.Source.Version, Path: r.Source.Path, Direction: r.Direction, Duration: r.Duration.String()})
+		}
+		c.result(out, nil)
+	}
+	return ExitOK
+}
+
+func (c *command) printMigrationStatus(statuses []*goose.MigrationStatus) {
+	out := make([]migrationStatus, 0, len(statuses))
+	for _, s := range statuses {
+		status := migrationStatus{Version: s.Source.Version, Path: s.Source.Path, Applied: s.State == goose.StateApplied}
+		if status.Applied {
+			appliedAt := s.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		out = append(out, status)
+	}
+	c.result(out, func(w io.Writer) {
+		for _, s := range out {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Local().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%-25s  %s\n", applied, s.Path)
+		}
+	})
+}
@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/app"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// patientRow is one patient read from an import file, with the line it came from.
+type patientRow struct {
+	Line    int
+	Request model.PatientCreateRequest
+}
+
+// importedPatient is the outcome of one row of "hms patient import".
+type importedPatient struct {
+	Line      int        `json:"line"`
+	PatientID *uuid.UUID `json:"patient_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type importResult struct {
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+	Rows     []importedPatient `json:"rows"`
+}
+
+func (c *command) patient(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(c.stderr, "usage: hms patient import|export [flags]")
+		return ExitUsage
+	}
+	switch args[0] {
+	case "import":
+		return c.patientImport(ctx, args[1:])
+	case "export":
+		return c.patientExport(ctx, args[1:])
+	}
+	fmt.Fprintf(c.stderr, "hms patient: unknown command %q\n", args[0])
+	return ExitUsage
+}
+
+func (c *command) patientImport(ctx context.Context, args []string) int {
+	fs := c.flags("patient import")
+	file := fs.String("file", "", "CSV or JSON lines file to read, - for stdin (required)")
+	format := fs.String("format", "", "csv or jsonl (default: from the file extension)")
+	as := fs.String("as", "", "username the patients are registered by (required)")
+	dryRun := fs.Bool("dry-run", false, "validate the file without registering anyone")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	if *file == "" {
+		return c.usageError(fs, "-file is required")
+	}
+	if *as == "" && !*dryRun {
+		return c.usageError(fs, "-as is required")
+	}
+	if *format == "" {
+		*format = importFormat(*file)
+	}
+
+	var in io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	rows, err := readPatients(in, *format)
+	if err != nil {
+		return c.fail(ExitUsage, err)
+	}
+
+	result := importResult{DryRun: *dryRun, Rows: make([]importedPatient, 0, len(rows))}
+	parsed := make([]model.ParsedPatientRequest, len(rows))
+	for i, row := range rows {
+		if parsed[i], err = validatePatient(row.Request); err != nil {
+			result.Rows = append(result.Rows, importedPatient{Line: row.Line, Error: err.Error()})
+			result.Failed++
+		}
+	}
+
+	if !*dryRun {
+		a, err := c.open(ctx)
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		defer a.Pool.Close()
+		ctx, actor, err := actAs(auditContext(ctx, "patient-import"), a, *as)
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		invalid := make(map[int]bool, len(result.Rows))
+		for _, r := range result.Rows {
+			invalid[r.Line] = true
+		}
+		for i, row := range rows {
+			if invalid[row.Line] {
+				continue
+			}
+			patient, err := a.Patients.RegisterPatient(ctx, parsed[i], actor.ID)
+			if err != nil {
+				result.Rows = append(result.Rows, importedPatient{Line: row.Line, Error: err.Error()})
+				result.Failed++
+				continue
+			}
+			result.Rows = append(result.Rows, importedPatient{Line: row.Line, PatientID: &patient.ID})
+			result.Imported++
+		}
+	}
+
+	c.result(result, func(w io.Writer) {
+		for _, r := range result.Rows {
+			if r.Error != "" {
+				fmt.Fprintf(w, "line %d: %s\n", r.Line, r.Error)
+			}
+		}
+		if result.DryRun {
+			fmt.Fprintf(w, "%d valid, %d invalid\n", len(rows)-result.Failed, result.Failed)
+			return
+		}
+		fmt.Fprintf(w, "%d imported, %d failed\n", result.Imported, result.Failed)
+	})
+	if result.Failed > 0 {
+		return ExitError
+	}
+	return ExitOK
+}
+
+// importFormat guesses the format of an import file from its extension.
+func importFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".jsonl", ".ndjson", ".json":
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// readPatients reads patients from CSV, with a header row of the JSON field names of
+// PatientCreateRequest, or from JSON lines of PatientCreateRequest. Empty CSV cells are
+// left unset.
+func readPatients(r io.Reader, format string) ([]patientRow, error) {
+	switch format {
+	case "csv":
+		return readPatientsCSV(r)
+	case "jsonl":
+		return readPatientsJSONL(r)
+	}
+	return nil, fmt.Errorf("unknown format %q, use csv or jsonl", format)
+}
+
+func readPatientsCSV(r io.Reader) ([]patientRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the file is empty")
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	known := map[string]bool{
+		"first_name": true, "last_name": true, "date_of_birth": true, "gender": true,
+		"contact_phone": true, "contact_email": true, "address": true, "medical_history": true,
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if !known[header[i]] {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+
+	var rows []patientRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		// Go through JSON so the columns map to fields the way API requests do.
+		fields := make(map[string]string, len(header))
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				fields[header[i]] = value
+			}
+		}
+		b, _ := json.Marshal(fields)
+		var req model.PatientCreateRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, patientRow{Line: line, Request: req})
+	}
+}
+
+func readPatientsJSONL(r io.Reader) ([]patientRow, error) {
+	var rows []patientRow
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var req model.PatientCreateRequest
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, patientRow{Line: line, Request: req})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// validatePatient applies the checks the registration endpoint does.
+func validatePatient(req model.PatientCreateRequest) (model.ParsedPatientRequest, error) {
+	if err := util.ValidateStruct(req); err != nil {
+		return model.ParsedPatientRequest{}, validationError(err)
+	}
+	return req.Parse()
+}
+
+// actAs looks up the active user called username and records them as the actor of what
+// is done with the returned context.
+func actAs(ctx context.Context, a *app.App, username string) (context.Context, model.User, error) {
+	dbUser, err := a.UserRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ctx, model.User{}, fmt.Errorf("user %q: %w", username, service.ErrUserNotFound)
+		}
+		return ctx, model.User{}, fmt.Errorf("look up user %q: %w", username, err)
+	}
+	user := mapper.ConvertDBUserToModel(dbUser)
+	if !user.IsActive {
+		return ctx, model.User{}, fmt.Errorf("user %q is deactivated", username)
+	}
+	return audit.WithActor(ctx, user.ID, user.Role), user, nil
+}
+
+func (c *command) patientExport(ctx context.Context, args []string) int {
+	fs := c.flags("patient export")
+	id := fs.String("id", "", "ID of the patient to export (required)")
+	out := fs.String("out", "", "ZIP file to write (default: patient-<id>.zip)")
+	as := fs.String("as", "", "admin or privacy officer the export is requested by (required)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long to wait for the archive to be built")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	patientID, err := uuid.Parse(*id)
+	if err != nil {
+		return c.usageError(fs, "-id must be a patient ID")
+	}
+	if *as == "" {
+		return c.usageError(fs, "-as is required")
+	}
+	if *out == "" {
+		*out = "patient-" + patientID.String() + ".zip"
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	ctx, actor, err := actAs(auditContext(ctx, "patient-export"), a, *as)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	// The API only lets these roles export; the command line is no back door.
+	if actor.Role != model.RoleAdmin && actor.Role != model.RolePrivacyOfficer {
+		return c.fail(ExitError, fmt.Errorf("user %q is a %s; exports need an admin or privacy officer", actor.Username, actor.Role))
+	}
+
+	export, err := a.PatientExports.RequestExport(ctx, patientID, actor.ID)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	export, archive, err := waitForExport(ctx, a, export, *timeout)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	if err := os.WriteFile(*out, archive, 0o600); err != nil {
+		return c.fail(ExitError, err)
+	}
+
+	c.result(map[string]any{"export": export, "path": *out}, func(w io.Writer) {
+		fmt.Fprintf(w, "wrote export %s of patient %s to %s (%d bytes)\n", export.ID, patientID, *out, len(archive))
+	})
+	return ExitOK
+}
+
+// waitForExport builds the export in this process, unless a server's worker has claimed
+// it already, and downloads it once it is complete.
+func waitForExport(ctx context.Context, a *app.App, export *model.PatientExport, timeout time.Duration) (*model.PatientExport, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		if err := a.PatientExports.ProcessPendingExports(ctx, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		current, err := a.PatientExports.GetExport(ctx, export.PatientID, export.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch current.Status {
+		case model.PatientExportCompleted:
+			return a.PatientExports.DownloadExport(ctx, export.PatientID, export.ID)
+		case model.PatientExportFailed:
+			reason := "unknown error"
+			if current.Error != nil {
+				reason = *current.Error
+			}
+			return nil, nil, fmt.Errorf("export %s failed: %s", export.ID, reason)
+		case model.PatientExportExpired:
+			return nil, nil, service.ErrPatientExportExpired
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("export %s is still %s: %w", export.ID, current.Status, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/research"
+)
+
+type researchExportResult struct {
+	Path       string                    `json:"path"`
+	Patients   int                       `json:"patients"`
+	Visits     int                       `json:"visits"`
+	KAnonymity research.KAnonymityReport `json:"k_anonymity"`
+}
+
+func (c *command) research(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(c.stderr, "usage: hms research export [flags]")
+		return ExitUsage
+	}
+	return c.researchExport(ctx, args[1:])
+}
+
+// researchExport writes a de-identified dataset of every patient with research consent in
+// force, and their visits, to a ZIP file: the patient and visit tables as CSV or Parquet,
+// a k-anonymity report on the quasi-identifiers and a manifest. It exits with
+// ExitCheckFailed when the k-anonymity check fails, so a scheduled job can hold the file
+// back for review.
+func (c *command) researchExport(ctx context.Context, args []string) int {
+	opts := research.DefaultOptions()
+	fs := c.flags("research export")
+	out := fs.String("out", "", "ZIP file to write (default: research-dataset-<time>.zip)")
+	format := fs.String("format", string(research.FormatCSV), "table format: csv or parquet")
+	dates := fs.String("dates", string(opts.Dates), "year keeps only the year, shift moves each patient's dates by a fixed random offset")
+	fs.IntVar(&opts.MaxShiftDays, "max-shift-days", opts.MaxShiftDays, "largest date shift in days with -dates=shift")
+	geography := fs.String("geography", string(opts.Geography), "postal3 keeps the first three digits of the postal code, none drops it")
+	fs.BoolVar(&opts.FreeText, "free-text", false, "include visit symptoms, diagnoses, prescriptions and notes")
+	fs.IntVar(&opts.K, "k", opts.K, "smallest group size for the k-anonymity check")
+	as := fs.String("as", "", "admin or privacy officer the export is made by (required)")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	opts.Dates = research.DateMode(*dates)
+	opts.Geography = research.GeographyMode(*geography)
+	if err := opts.Validate(); err != nil {
+		return c.usageError(fs, err.Error())
+	}
+	if !research.Format(*format).Valid() {
+		return c.usageError(fs, "-format must be csv or parquet")
+	}
+	if *as == "" {
+		return c.usageError(fs, "-as is required")
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	ctx, actor, err := actAs(auditContext(ctx, "research-export"), a, *as)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	// The API only lets these roles export; the command line is no back door.
+	if actor.Role != model.RoleAdmin && actor.Role != model.RolePrivacyOfficer {
+		return c.fail(ExitError, fmt.Errorf("user %q is a %s; research exports need an admin or privacy officer", actor.Username, actor.Role))
+	}
+
+	dataset, err := a.ResearchExports.Export(ctx, opts, time.Now())
+	if err != nil {
+		return c.fail(ExitError, fmt.Errorf("export research dataset: %w", err))
+	}
+	path := *out
+	if path == "" {
+		path = research.FileName(dataset.GeneratedAt)
+	}
+	if err := writeResearchDataset(path, *dataset, research.Format(*format)); err != nil {
+		return c.fail(ExitError, err)
+	}
+
+	result := researchExportResult{Path: path, Patients: len(dataset.Patients), Visits: len(dataset.Visits), KAnonymity: dataset.KAnonymity}
+	c.result(result, func(w io.Writer) {
+		report := result.KAnonymity
+		fmt.Fprintf(w, "%s: %d patients, %d visits\n", path, result.Patients, result.Visits)
+		fmt.Fprintf(w, "k-anonymity (k=%d): %d groups, smallest %d, %d patients in groups below k\n", report.K, report.EquivalenceClasses, report.SmallestClass, report.PatientsAtRisk)
+		if !report.Satisfied {
+			fmt.Fprintf(w, "k-anonymity NOT satisfied; see %s before releasing the dataset\n", research.KAnonymityFile)
+		}
+	})
+	if !dataset.KAnonymity.Satisfied {
+		return ExitCheckFailed
+	}
+	return ExitOK
+}
+
+// writeResearchDataset writes the dataset to a new file at path, removing it again if
+// writing fails.
+func writeResearchDataset(path string, dataset research.Dataset, format research.Format) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := research.WriteZip(f, dataset, format); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
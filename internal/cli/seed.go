@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	"github.com/jackc/pgx/v5"
+)
+
+// seedRoles are the users "hms seed" creates, one per role, named after the role.
+var seedRoles = []model.UserRole{
+	model.RoleReceptionist,
+	model.RoleDoctor,
+	model.RoleLabTechnician,
+	model.RolePharmacist,
+	model.RoleAdmin,
+	model.RolePrivacyOfficer,
+}
+
+type seedResult struct {
+	UsersCreated    []string    `json:"users_created"`
+	UsersExisting   []string    `json:"users_existing"`
+	PatientsCreated []uuid.UUID `json:"patients_created"`
+	PatientsExisted int         `json:"patients_existing"`
+}
+
+func (c *command) seed(ctx context.Context, args []string) int {
+	fs := c.flags("seed")
+	password := fs.String("password", "", "password of the seeded users; required unless -password-stdin")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin")
+	patients := fs.Int("patients", 0, "sample patients to register, numbered from 1")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	// There is no default password: seeded users, admins among them, would otherwise be
+	// reachable with one anybody can read here.
+	switch {
+	case *passwordStdin && *password != "":
+		return c.usageError(fs, "give -password or -password-stdin, not both")
+	case !*passwordStdin && *password == "":
+		return c.usageError(fs, "-password or -password-stdin is required")
+	case *passwordStdin:
+		var err error
+		if *password, err = readPassword(c.stdin); err != nil {
+			return c.fail(ExitError, err)
+		}
+	}
+	if len(*password) < 6 {
+		return c.usageError(fs, "the password must have at least 6 characters")
+	}
+	if *patients < 0 {
+		return c.usageError(fs, "-patients must not be negative")
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	ctx = auditContext(ctx, "seed")
+
+	// Existing users and patients are left alone, so seeding twice changes nothing.
+	result := seedResult{UsersCreated: []string{}, UsersExisting: []string{}, PatientsCreated: []uuid.UUID{}}
+	for _, role := range seedRoles {
+		username := string(role)
+		_, err := a.Users.CreateUser(ctx, model.UserCreateRequest{Username: username, Password: *password, Role: role})
+		switch {
+		case errors.Is(err, service.ErrUserAlreadyExists):
+			result.UsersExisting = append(result.UsersExisting, username)
+		case err != nil:
+			return c.fail(ExitError, fmt.Errorf("seed user %s: %w", username, err))
+		default:
+			result.UsersCreated = append(result.UsersCreated, username)
+		}
+	}
+
+	if *patients > 0 {
+		ctx, receptionist, err := actAs(ctx, a, string(model.RoleReceptionist))
+		if err != nil {
+			return c.fail(ExitError, err)
+		}
+		for i := 1; i <= *patients; i++ {
+			req := samplePatient(i)
+			// Sample patients are told apart by their email address.
+			_, err := a.PatientRepo.GetPatientByContactEmail(ctx, *req.ContactEmail)
+			if err == nil {
+				result.PatientsExisted++
+				continue
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return c.fail(ExitError, fmt.Errorf("seed patient %d: %w", i, err))
+			}
+			patient, err := a.Patients.RegisterPatient(ctx, req, receptionist.ID)
+			if err != nil {
+				return c.fail(ExitError, fmt.Errorf("seed patient %d: %w", i, err))
+			}
+			result.PatientsCreated = append(result.PatientsCreated, patient.ID)
+		}
+	}
+
+	c.result(result, func(w io.Writer) {
+		fmt.Fprintf(w, "users: %d created, %d already there\n", len(result.UsersCreated), len(result.UsersExisting))
+		if len(result.UsersCreated) > 0 {
+			fmt.Fprintln(w, "new users log in with their role as username and the password given")
+		}
+		if *patients > 0 {
+			fmt.Fprintf(w, "patients: %d created, %d already there\n", len(result.PatientsCreated), result.PatientsExisted)
+		}
+	})
+	return ExitOK
+}
+
+// samplePatient is the i-th seeded patient. Everything about it is made up.
+func samplePatient(i int) model.ParsedPatientRequest {
+	firstNames := []string{"Asha", "Ben", "Chen", "Dara", "Elif", "Farid", "Grace", "Hiro"}
+	lastNames := []string{"Kumar", "Okafor", "Smith", "Novak", "Silva", "Tanaka"}
+	genders := []model.Gender{model.GenderFemale, model.GenderMale, model.GenderOther}
+	gender := genders[i%len(genders)]
+	email := fmt.Sprintf("seed-patient-%d@example.com", i)
+	phone := fmt.Sprintf("+1555%07d", i)
+	return model.ParsedPatientRequest{
+		FirstName:    firstNames[i%len(firstNames)],
+		LastName:     lastNames[i%len(lastNames)],
+		DateOfBirth:  time.Date(1940+i%70, time.Month(1+i%12), 1+i%28, 0, 0, 0, 0, time.UTC),
+		Gender:       &gender,
+		ContactPhone: &phone,
+		ContactEmail: &email,
+	}
+}
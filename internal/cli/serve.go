@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/tracing"
+)
+
+func (c *command) serve(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("hms serve", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	// Libraries that log through the standard logger go through the redaction too.
+	slog.SetDefault(a.Logger)
+	a.Logger.Info("Connected to PostgreSQL")
+
+	shutdownTracing, err := tracing.Setup(ctx, a.Config.Tracing)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	// Flush the spans of the last requests; the pool is closed after this.
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			a.Logger.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
+	if err := a.Serve(ctx); err != nil {
+		return c.fail(ExitError, err)
+	}
+	return ExitOK
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+// userResult is what the user commands print. Password is set only when the command
+// generated it.
+type userResult struct {
+	User     *model.User `json:"user"`
+	Password string      `json:"password,omitempty"`
+}
+
+func (c *command) user(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(c.stderr, "usage: hms user create|deactivate|reset-password [flags]")
+		return ExitUsage
+	}
+	switch args[0] {
+	case "create":
+		return c.userCreate(ctx, args[1:])
+	case "deactivate":
+		return c.userDeactivate(ctx, args[1:])
+	case "reset-password":
+		return c.userResetPassword(ctx, args[1:])
+	}
+	fmt.Fprintf(c.stderr, "hms user: unknown command %q\n", args[0])
+	return ExitUsage
+}
+
+func (c *command) userCreate(ctx context.Context, args []string) int {
+	fs := c.flags("user create")
+	var req model.UserProvisionRequest
+	var role, firstName, lastName, email string
+	fs.StringVar(&req.Username, "username", "", "username to log in with (required)")
+	fs.StringVar(&role, "role", "", "receptionist, doctor, lab_technician, pharmacist, admin or privacy_officer (required)")
+	fs.StringVar(&firstName, "first-name", "", "first name")
+	fs.StringVar(&lastName, "last-name", "", "last name")
+	fs.StringVar(&email, "email", "", "email address")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin instead of generating one")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return c.usageError(fs, "takes no arguments")
+	}
+	req.Role = model.UserRole(role)
+	req.FirstName, req.LastName, req.Email = optional(firstName), optional(lastName), optional(email)
+
+	generated := !*passwordStdin
+	var err error
+	if generated {
+		req.Password, err = generatePassword()
+	} else {
+		req.Password, err = readPassword(c.stdin)
+	}
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		return c.fail(ExitUsage, validationError(err))
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	user, err := a.Users.CreateUser(auditContext(ctx, "user-create"), model.UserCreateRequest{
+		Username:  req.Username,
+		Password:  req.Password,
+		Role:      req.Role,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+	})
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+
+	result := userResult{User: user}
+	if generated {
+		result.Password = req.Password
+	}
+	c.result(result, func(w io.Writer) {
+		fmt.Fprintf(w, "created %s %s (%s)\n", user.Role, user.Username, user.ID)
+		if generated {
+			fmt.Fprintf(w, "password: %s\n", req.Password)
+		}
+	})
+	return ExitOK
+}
+
+func (c *command) userDeactivate(ctx context.Context, args []string) int {
+	fs := c.flags("user deactivate")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		return c.usageError(fs, "takes the username")
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	user, err := a.Users.DeactivateUser(auditContext(ctx, "user-deactivate"), fs.Arg(0))
+	if err != nil {
+		return c.fail(userExitCode(err), err)
+	}
+	c.result(userResult{User: user}, func(w io.Writer) {
+		fmt.Fprintf(w, "deactivated %s (%s)\n", user.Username, user.ID)
+	})
+	return ExitOK
+}
+
+func (c *command) userResetPassword(ctx context.Context, args []string) int {
+	fs := c.flags("user reset-password")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin instead of generating one")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		return c.usageError(fs, "takes the username")
+	}
+
+	generated := !*passwordStdin
+	var password string
+	var err error
+	if generated {
+		password, err = generatePassword()
+	} else {
+		password, err = readPassword(c.stdin)
+	}
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	if len(password) < 6 {
+		return c.fail(ExitUsage, errors.New("password must have at least 6 characters"))
+	}
+
+	a, err := c.open(ctx)
+	if err != nil {
+		return c.fail(ExitError, err)
+	}
+	defer a.Pool.Close()
+	user, err := a.Users.ResetPassword(auditContext(ctx, "user-reset-password"), fs.Arg(0), password)
+	if err != nil {
+		return c.fail(userExitCode(err), err)
+	}
+	result := userResult{User: user}
+	if generated {
+		result.Password = password
+	}
+	c.result(result, func(w io.Writer) {
+		fmt.Fprintf(w, "reset the password of %s (%s)\n", user.Username, user.ID)
+		if generated {
+			fmt.Fprintf(w, "password: %s\n", password)
+		}
+	})
+	return ExitOK
+}
+
+func userExitCode(err error) int {
+	if errors.Is(err, service.ErrUserNotFound) {
+		return ExitUsage
+	}
+	return ExitError
+}
+
+// generatePassword returns a random password of 24 URL-safe characters.
+func generatePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// readPassword reads the first line of r, so the password stays out of the shell history
+// and the process list.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// validationError turns validator errors into one line naming each invalid field.
+func validationError(err error) error {
+	fields := util.FormatValidationErrors(err)
+	if len(fields) == 0 {
+		return err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+fields[name])
+	}
+	return fmt.Errorf("invalid input: %s", strings.Join(parts, " "))
+}
@@ -1,7 +1,439 @@
+// Package config loads the settings hms runs with. Each setting has a default and can be
+// set in a YAML file, in .env or in the environment; later sources win, so the
+// environment overrides .env, which overrides the file.
 package config
 
-import "github.com/himanshu-holmes/hms/internal/db"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
 
-type ApiConfig struct {
-	DB db.Queries
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/research"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the YAML file to load when none is given explicitly.
+const FileEnv = "CONFIG_FILE"
+
+// Config is everything hms reads from its environment. The env tag names the variable
+// that sets a field; the yaml tag, its key in the config file.
+type Config struct {
+	Server     Server     `yaml:"server"`
+	DB         DB         `yaml:"db"`
+	Auth       Auth       `yaml:"auth"`
+	Encryption Encryption `yaml:"encryption"`
+	Billing    Billing    `yaml:"billing"`
+	Insurance  Insurance  `yaml:"insurance"`
+	Research   Research   `yaml:"research"`
+	Bugsnag    Bugsnag    `yaml:"bugsnag"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Log        Log        `yaml:"log"`
+}
+
+type Server struct {
+	Port int `yaml:"port" env:"PORT"`
+	// MetricsPort serves /metrics apart from the API, so it stays off the public load
+	// balancer; 0 serves it on Port instead.
+	MetricsPort       int           `yaml:"metrics_port" env:"METRICS_PORT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownDelay is how long the health check fails before the server stops accepting
+	// requests, so the load balancer stops routing to it first.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	// ShutdownTimeout bounds how long requests in flight and background jobs get to finish.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// HealthCheckTimeout is how long each check behind /readyz and the other probes gets.
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+}
+
+type DB struct {
+	URL               Secret        `yaml:"url" env:"DB_URL"` // Holds the database password
+	AutoMigrate       bool          `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE"`
+	MigrationsDir     string        `yaml:"migrations_dir" env:"DB_MIGRATIONS_DIR"` // Where "hms migrate create" writes
+	TxIsolation       string        `yaml:"tx_isolation" env:"DB_TX_ISOLATION"`
+	TxMaxAttempts     int           `yaml:"tx_max_attempts" env:"DB_TX_MAX_ATTEMPTS"`
+	MaxConns          int           `yaml:"max_conns" env:"DB_MAX_CONNS"`
+	MinConns          int           `yaml:"min_conns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"DB_HEALTH_CHECK_PERIOD"`
+	StatementTimeout  time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT"` // 0 for none; migrations never have one
+}
+
+type Auth struct {
+	Secret          Secret        `yaml:"secret" env:"SECRET"` // Signs the JWTs
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL"`
+}
+
+type Encryption struct {
+	FieldKeyFile string `yaml:"field_key_file" env:"FIELD_KEY_FILE"`
+}
+
+type Billing struct {
+	FiscalYearStartMonth int `yaml:"fiscal_year_start_month" env:"FISCAL_YEAR_START_MONTH"`
+}
+
+type Insurance struct {
+	// Gateway is the payer integration eligibility checks and claims go to: "fake", which
+	// accepts everything and is only for development, or empty to turn both off.
+	Gateway string `yaml:"gateway" env:"INSURANCE_GATEWAY"`
+}
+
+// InsuranceGatewayFake names the in-memory payer gateway.
+const InsuranceGatewayFake = "fake"
+
+type Research struct {
+	IDKey Secret `yaml:"id_key" env:"RESEARCH_ID_KEY"` // Research exports are disabled when empty
+}
+
+type Bugsnag struct {
+	APIKey Secret `yaml:"api_key" env:"BUGSNAG_API_KEY"`
+}
+
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`   // debug, info, warn or error
+	Format string `yaml:"format" env:"LOG_FORMAT"` // json or text
+}
+
+// Tracing uses the variable names of the OpenTelemetry SDKs.
+type Tracing struct {
+	// Endpoint is the OTLP/HTTP collector URL, like http://localhost:4318. Spans are not
+	// exported when it is empty, but trace context is still propagated.
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"` // Share of new traces kept; callers' decisions are followed
+}
+
+// Default is the configuration before any source is read.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:               3000,
+			MetricsPort:        9090,
+			ReadHeaderTimeout:  10 * time.Second,
+			ReadTimeout:        30 * time.Second,
+			WriteTimeout:       2 * time.Minute, // Exports are built while the client waits
+			IdleTimeout:        2 * time.Minute,
+			ShutdownDelay:      10 * time.Second,
+			ShutdownTimeout:    20 * time.Second,
+			HealthCheckTimeout: time.Second,
+		},
+		DB: DB{
+			MigrationsDir:     "db/migrations",
+			TxIsolation:       "read committed",
+			TxMaxAttempts:     repository.DefaultTxOptions().MaxAttempts,
+			MaxConns:          10,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			StatementTimeout:  30 * time.Second,
+		},
+		Auth:    Auth{AccessTokenTTL: 24 * time.Hour, RefreshTokenTTL: 7 * 24 * time.Hour},
+		Billing: Billing{FiscalYearStartMonth: int(time.January)},
+		Tracing: Tracing{ServiceName: "hms", SampleRatio: 1},
+		Log:     Log{Level: "info", Format: "json"},
+	}
+}
+
+// Secret is a setting that must not be shown: it prints, and marshals to JSON or YAML,
+// as a placeholder.
+type Secret string
+
+const redacted = "[redacted]"
+
+// Value is the secret itself.
+func (s Secret) Value() string { return string(s) }
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) { return strconv.AppendQuote(nil, s.String()), nil }
+
+func (s Secret) MarshalYAML() (any, error) { return s.String(), nil }
+
+// Load reads the configuration from file, if not empty, else from the file named by
+// CONFIG_FILE, if set; then from .env in the working directory and the environment. It
+// reports every setting it could not parse; check the values with Validate.
+func Load(file string) (*Config, error) {
+	dotenv, err := godotenv.Read(".env")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+	return load(file, dotenv, os.LookupEnv)
+}
+
+func load(file string, dotenv map[string]string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	if file == "" {
+		file, _ = lookupEnv(FileEnv)
+		if file == "" {
+			file = dotenv[FileEnv]
+		}
+	}
+	if file != "" {
+		if err := readFile(file, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	lookup := func(name string) (string, bool) {
+		if v, ok := lookupEnv(name); ok {
+			return v, true
+		}
+		v, ok := dotenv[name]
+		return v, ok
+	}
+	var errs []error
+	setFromEnv(reflect.ValueOf(&cfg).Elem(), lookup, &errs)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &cfg, nil
+}
+
+func readFile(file string, cfg *Config) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", file, err)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setFromEnv overwrites each field of v that has an env tag with the variable it names,
+// if that is set, recursing into nested sections.
+func setFromEnv(v reflect.Value, lookup func(string) (string, bool), errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		name := field.Tag.Get("env")
+		if name == "" {
+			if field.Type.Kind() == reflect.Struct {
+				setFromEnv(value, lookup, errs)
+			}
+			continue
+		}
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		switch {
+		case field.Type == durationType:
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not a duration like 15m or 24h", name, raw))
+				continue
+			}
+			value.SetInt(int64(d))
+		case field.Type.Kind() == reflect.String:
+			value.SetString(raw)
+		case field.Type.Kind() == reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not a whole number", name, raw))
+				continue
+			}
+			value.SetInt(int64(n))
+		case field.Type.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not true or false", name, raw))
+				continue
+			}
+			value.SetBool(b)
+		case field.Type.Kind() == reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not a number", name, raw))
+				continue
+			}
+			value.SetFloat(f)
+		default:
+			panic(fmt.Sprintf("config: %s has unsupported type %s", name, field.Type))
+		}
+	}
+}
+
+// Validate reports every invalid or missing setting the server needs.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.MetricsPort < 0 || c.Server.MetricsPort > 65535 || c.Server.MetricsPort == c.Server.Port {
+		errs = append(errs, fmt.Errorf("METRICS_PORT: must be 0 or a port between 1 and 65535 other than PORT, got %d", c.Server.MetricsPort))
+	}
+	errs = append(errs, checkDurations(true,
+		duration{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		duration{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		duration{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		duration{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		duration{"SHUTDOWN_DELAY", c.Server.ShutdownDelay},
+	)...)
+	errs = append(errs, checkDurations(false,
+		duration{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		duration{"HEALTH_CHECK_TIMEOUT", c.Server.HealthCheckTimeout},
+	)...)
+	if err := c.DB.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Auth.Secret == "" {
+		errs = append(errs, errors.New("SECRET: is required"))
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		errs = append(errs, errors.New("ACCESS_TOKEN_TTL: must be positive"))
+	}
+	if c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+		errs = append(errs, errors.New("REFRESH_TOKEN_TTL: must be at least ACCESS_TOKEN_TTL"))
+	}
+	if err := c.Encryption.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if m := c.Billing.FiscalYearStartMonth; m < 1 || m > 12 {
+		errs = append(errs, fmt.Errorf("FISCAL_YEAR_START_MONTH: must be a month number 1-12, got %d", m))
+	}
+	if g := c.Insurance.Gateway; g != "" && g != InsuranceGatewayFake {
+		errs = append(errs, fmt.Errorf("INSURANCE_GATEWAY: must be empty or %s, got %q", InsuranceGatewayFake, g))
+	}
+	if c.Research.IDKey != "" {
+		if _, err := research.ParseKey(c.Research.IDKey.Value()); err != nil {
+			errs = append(errs, fmt.Errorf("RESEARCH_ID_KEY: %w", err))
+		}
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Log.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid logging setting.
+func (l *Log) Validate() error {
+	var errs []error
+	if _, err := l.SlogLevel(); err != nil {
+		errs = append(errs, err)
+	}
+	if l.Format != "json" && l.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: must be json or text, got %q", l.Format))
+	}
+	return errors.Join(errs...)
+}
+
+// SlogLevel is Level as a slog.Level.
+func (l *Log) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return 0, fmt.Errorf("LOG_LEVEL: must be debug, info, warn or error, got %q", l.Level)
+	}
+	return level, nil
+}
+
+// Validate reports every invalid tracing setting.
+func (t *Tracing) Validate() error {
+	var errs []error
+	if t.Endpoint != "" {
+		u, err := url.Parse(t.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT: must be an http or https URL, got %q", t.Endpoint))
+		}
+	}
+	if t.ServiceName == "" {
+		errs = append(errs, errors.New("OTEL_SERVICE_NAME: is required"))
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: must be between 0 and 1, got %g", t.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid or missing database setting. Commands that only touch
+// the database, like migrations, check these alone.
+func (d DB) Validate() error {
+	var errs []error
+	if d.URL == "" {
+		errs = append(errs, errors.New("DB_URL: is required"))
+	}
+	if _, err := repository.ParseIsoLevel(d.TxIsolation); err != nil {
+		errs = append(errs, fmt.Errorf("DB_TX_ISOLATION: %w", err))
+	}
+	if d.TxMaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("DB_TX_MAX_ATTEMPTS: must be a positive number, got %d", d.TxMaxAttempts))
+	}
+	if d.MaxConns < 1 {
+		errs = append(errs, fmt.Errorf("DB_MAX_CONNS: must be a positive number, got %d", d.MaxConns))
+	}
+	if d.MinConns < 0 || d.MinConns > d.MaxConns {
+		errs = append(errs, fmt.Errorf("DB_MIN_CONNS: must be between 0 and DB_MAX_CONNS, got %d", d.MinConns))
+	}
+	errs = append(errs, checkDurations(false,
+		duration{"DB_MAX_CONN_LIFETIME", d.MaxConnLifetime},
+		duration{"DB_MAX_CONN_IDLE_TIME", d.MaxConnIdleTime},
+		duration{"DB_HEALTH_CHECK_PERIOD", d.HealthCheckPeriod},
+	)...)
+	errs = append(errs, checkDurations(true, duration{"DB_STATEMENT_TIMEOUT", d.StatementTimeout})...)
+	return errors.Join(errs...)
+}
+
+type duration struct {
+	name  string
+	value time.Duration
+}
+
+// checkDurations reports each duration that is negative, or zero unless zeroAllowed;
+// zero turns a timeout off.
+func checkDurations(zeroAllowed bool, durations ...duration) []error {
+	var errs []error
+	for _, d := range durations {
+		switch {
+		case d.value < 0:
+			errs = append(errs, fmt.Errorf("%s: must not be negative", d.name))
+		case d.value == 0 && !zeroAllowed:
+			errs = append(errs, fmt.Errorf("%s: must be positive", d.name))
+		}
+	}
+	return errs
+}
+
+// TxOptions are the options of the transactions multi-step writes run in. Validate first.
+func (d DB) TxOptions() repository.TxOptions {
+	opts := repository.DefaultTxOptions()
+	opts.IsoLevel, _ = repository.ParseIsoLevel(d.TxIsolation)
+	opts.MaxAttempts = d.TxMaxAttempts
+	return opts
+}
+
+// Validate reports whether the field key file is set and can be read.
+func (e Encryption) Validate() error {
+	if e.FieldKeyFile == "" {
+		return errors.New("FIELD_KEY_FILE: is required")
+	}
+	if _, err := os.Stat(e.FieldKeyFile); err != nil {
+		return fmt.Errorf("FIELD_KEY_FILE: %w", err)
+	}
+	return nil
 }
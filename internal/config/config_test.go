@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := load("", nil, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, Default(), *cfg)
+	assert.Equal(t, 3000, cfg.Server.Port)
+	assert.Equal(t, 24*time.Hour, cfg.Auth.AccessTokenTTL)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "hms.yaml", `
+server:
+  port: 8000
+db:
+  url: postgres://file
+  tx_max_attempts: 5
+auth:
+  access_token_ttl: 1h
+billing:
+  fiscal_year_start_month: 4
+`)
+	dotenv := map[string]string{"DB_URL": "postgres://dotenv", "DB_TX_MAX_ATTEMPTS": "7", "OTEL_TRACES_SAMPLER_ARG": "0.25"}
+	cfg, err := load(file, dotenv, env(map[string]string{"DB_URL": "postgres://env"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 8000, cfg.Server.Port, "file over default")
+	assert.Equal(t, time.Hour, cfg.Auth.AccessTokenTTL, "file over default")
+	assert.Equal(t, 4, cfg.Billing.FiscalYearStartMonth, "file over default")
+	assert.Equal(t, 7, cfg.DB.TxMaxAttempts, ".env over file")
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio, ".env over default")
+	assert.Equal(t, "postgres://env", cfg.DB.URL.Value(), "environment over .env")
+	assert.Equal(t, "read committed", cfg.DB.TxIsolation, "default when unset")
+}
+
+func TestLoadFileFromEnv(t *testing.T) {
+	file := writeFile(t, "hms.yaml", "server:\n  port: 9000\n")
+	cfg, err := load("", nil, env(map[string]string{FileEnv: file}))
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+}
+
+func TestLoadReportsEveryParseError(t *testing.T) {
+	_, err := load("", nil, env(map[string]string{
+		"PORT":                    "eighty",
+		"DB_AUTO_MIGRATE":         "sometimes",
+		"ACCESS_TOKEN_TTL":        "a day",
+		"OTEL_TRACES_SAMPLER_ARG": "most",
+	}))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "PORT")
+	assert.ErrorContains(t, err, "DB_AUTO_MIGRATE")
+	assert.ErrorContains(t, err, "ACCESS_TOKEN_TTL")
+	assert.ErrorContains(t, err, "OTEL_TRACES_SAMPLER_ARG")
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	file := writeFile(t, "hms.yaml", "server:\n  prot: 9000\n")
+	_, err := load(file, nil, env(nil))
+	assert.ErrorContains(t, err, "prot")
+}
+
+func validConfig(t *testing.T) Config {
+	cfg := Default()
+	cfg.DB.URL = "postgres://localhost/hms"
+	cfg.Auth.Secret = "jwt-secret"
+	cfg.Encryption.FieldKeyFile = writeFile(t, "field-keys.json", "{}")
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	cfg := validConfig(t)
+	require.NoError(t, cfg.Validate())
+
+	cfg = Default()
+	cfg.Server.Port = 70000
+	cfg.Server.MetricsPort = -1
+	cfg.DB.TxIsolation = "chaos"
+	cfg.Auth.RefreshTokenTTL = time.Minute
+	cfg.Billing.FiscalYearStartMonth = 13
+	cfg.Insurance.Gateway = "acme"
+	cfg.Research.IDKey = "short"
+	cfg.Tracing.Endpoint = "localhost:4318"
+	cfg.Tracing.SampleRatio = 2
+	cfg.Log.Level = "loud"
+	cfg.Log.Format = "xml"
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, name := range []string{"PORT", "METRICS_PORT", "DB_URL", "DB_TX_ISOLATION", "SECRET", "REFRESH_TOKEN_TTL", "FIELD_KEY_FILE", "FISCAL_YEAR_START_MONTH", "INSURANCE_GATEWAY", "RESEARCH_ID_KEY", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_TRACES_SAMPLER_ARG", "LOG_LEVEL", "LOG_FORMAT"} {
+		assert.ErrorContains(t, err, name)
+	}
+	assert.NotContains(t, err.Error(), "short", "secrets stay out of errors")
+}
+
+func TestValidateDurationsAndPool(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Server.ReadTimeout = 0
+	cfg.Server.ShutdownDelay = 0
+	cfg.DB.StatementTimeout = 0
+	require.NoError(t, cfg.Validate(), "zero turns a timeout off")
+
+	cfg.Server.WriteTimeout = -time.Second
+	cfg.Server.ShutdownTimeout = 0
+	cfg.Server.HealthCheckTimeout = 0
+	cfg.DB.MaxConns = 4
+	cfg.DB.MinConns = 5
+	cfg.DB.HealthCheckPeriod = 0
+	cfg.DB.StatementTimeout = -time.Second
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, name := range []string{"HTTP_WRITE_TIMEOUT", "SHUTDOWN_TIMEOUT", "HEALTH_CHECK_TIMEOUT", "DB_MIN_CONNS", "DB_HEALTH_CHECK_PERIOD", "DB_STATEMENT_TIMEOUT"} {
+		assert.ErrorContains(t, err, name)
+	}
+	assert.NotContains(t, err.Error(), "HTTP_READ_TIMEOUT")
+}
+
+func TestDBValidateIgnoresOtherSections(t *testing.T) {
+	cfg := Default()
+	cfg.DB.URL = "postgres://localhost/hms"
+	assert.NoError(t, cfg.DB.Validate())
+	assert.Error(t, cfg.Validate())
+}
+
+func TestSecretsAreRedacted(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Auth.Secret = "jwt-secret"
+	cfg.DB.URL = "postgres://hms:hunter2@db/hms"
+
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "hunter2")
+	assert.NotContains(t, string(out), "jwt-secret")
+	assert.Contains(t, string(out), "secret: '[redacted]'")
+	assert.Contains(t, string(out), "access_token_ttl: 24h0m0s")
+
+	b, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "hunter2")
+
+	assert.NotContains(t, fmt.Sprintf("%v %+v", cfg, cfg), "jwt-secret")
+	assert.Equal(t, "", Secret("").String(), "unset secrets show as unset")
+}
@@ -0,0 +1,58 @@
+// Package consent decides whether what a patient agreed to allows an action.
+package consent
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// ErrConsentRequired matches every refusal returned by Check.
+var ErrConsentRequired = errors.New("patient consent required")
+
+// ErrSignedInFuture rejects consents dated after they were recorded.
+var ErrSignedInFuture = errors.New("consent cannot be signed in the future")
+
+// clockSkew is how far ahead of the server clock a signature time may be.
+const clockSkew = 5 * time.Minute
+
+// RefusedError explains why an action was refused: the patient never consented, or
+// withdrew their consent.
+type RefusedError struct {
+	Type        model.ConsentType
+	WithdrawnAt *time.Time
+}
+
+func (e *RefusedError) Error() string {
+	if e.WithdrawnAt != nil {
+		return fmt.Sprintf("patient withdrew %s consent on %s", e.Type, e.WithdrawnAt.UTC().Format("2006-01-02"))
+	}
+	return fmt.Sprintf("patient has not given %s consent", e.Type)
+}
+
+func (e *RefusedError) Is(target error) bool {
+	return target == ErrConsentRequired
+}
+
+// Check allows an action needing consent of type t when the patient's latest record of
+// that type is in force. latest is nil when the patient never signed one.
+func Check(t model.ConsentType, latest *model.ConsentRecord) error {
+	if latest == nil {
+		return &RefusedError{Type: t}
+	}
+	if !latest.Active() {
+		return &RefusedError{Type: t, WithdrawnAt: latest.WithdrawnAt}
+	}
+	return nil
+}
+
+// ValidateSignedAt rejects signature times later than now, allowing for clock skew
+// between the recording device and the server.
+func ValidateSignedAt(signedAt, now time.Time) error {
+	if signedAt.After(now.Add(clockSkew)) {
+		return ErrSignedInFuture
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package consent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check(model.ConsentSMSContact, &model.ConsentRecord{Type: model.ConsentSMSContact}))
+
+	err := Check(model.ConsentSMSContact, nil)
+	assert.ErrorIs(t, err, ErrConsentRequired)
+	assert.Equal(t, "patient has not given sms_contact consent", err.Error())
+
+	withdrawn := time.Date(2026, time.May, 1, 22, 0, 0, 0, time.UTC)
+	err = Check(model.ConsentResearch, &model.ConsentRecord{Type: model.ConsentResearch, WithdrawnAt: &withdrawn})
+	assert.ErrorIs(t, err, ErrConsentRequired)
+	assert.Equal(t, "patient withdrew research consent on 2026-05-01", err.Error())
+
+	var refused *RefusedError
+	assert.True(t, errors.As(err, &refused))
+	assert.Equal(t, model.ConsentResearch, refused.Type)
+}
+
+func TestValidateSignedAt(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, ValidateSignedAt(now.Add(-24*time.Hour), now))
+	assert.NoError(t, ValidateSignedAt(now.Add(time.Minute), now))
+	assert.ErrorIs(t, ValidateSignedAt(now.Add(time.Hour), now), ErrSignedInFuture)
+}
@@ -0,0 +1,709 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: alerts.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const acknowledgeAlert = `-- name: AcknowledgeAlert :one
+UPDATE alerts
+SET
+    status = 'acknowledged',
+    acknowledged_at = NOW(),
+    acknowledged_by_user_id = $2,
+    escalate_at = NULL
+WHERE id = $1 AND status = 'open'
+RETURNING id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at
+`
+
+type AcknowledgeAlertParams struct {
+	ID                   pgtype.UUID
+	AcknowledgedByUserID pgtype.UUID
+}
+
+func (q *Queries) AcknowledgeAlert(ctx context.Context, arg AcknowledgeAlertParams) (Alert, error) {
+	row := q.db.QueryRow(ctx, acknowledgeAlert, arg.ID, arg.AcknowledgedByUserID)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.RuleID,
+		&i.VisitID,
+		&i.PatientID,
+		&i.Source,
+		&i.ObservationCode,
+		&i.ObservedValue,
+		&i.ThresholdValue,
+		&i.Direction,
+		&i.Message,
+		&i.Status,
+		&i.OriginalDoctorID,
+		&i.AssignedDoctorID,
+		&i.EscalationLevel,
+		&i.EscalateAt,
+		&i.EscalatedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countAlertsForDoctor = `-- name: CountAlertsForDoctor :one
+SELECT COUNT(*) FROM alerts
+WHERE assigned_doctor_id = $1
+  AND ($2::alert_status IS NULL OR status = $2::alert_status)
+`
+
+type CountAlertsForDoctorParams struct {
+	DoctorID pgtype.UUID
+	Status   NullAlertStatus
+}
+
+func (q *Queries) CountAlertsForDoctor(ctx context.Context, arg CountAlertsForDoctorParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAlertsForDoctor, arg.DoctorID, arg.Status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAlert = `-- name: CreateAlert :one
+INSERT INTO alerts (
+    rule_id, visit_id, patient_id, source, observation_code,
+    observed_value, threshold_value, direction, message,
+    original_doctor_id, assigned_doctor_id, escalate_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, $11
+)
+RETURNING id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at
+`
+
+type CreateAlertParams struct {
+	RuleID           pgtype.UUID
+	VisitID          pgtype.UUID
+	PatientID        pgtype.UUID
+	Source           ObservationSource
+	ObservationCode  string
+	ObservedValue    float64
+	ThresholdValue   float64
+	Direction        string
+	Message          string
+	OriginalDoctorID pgtype.UUID
+	EscalateAt       pgtype.Timestamptz
+}
+
+func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert, error) {
+	row := q.db.QueryRow(ctx, createAlert,
+		arg.RuleID,
+		arg.VisitID,
+		arg.PatientID,
+		arg.Source,
+		arg.ObservationCode,
+		arg.ObservedValue,
+		arg.ThresholdValue,
+		arg.Direction,
+		arg.Message,
+		arg.OriginalDoctorID,
+		arg.EscalateAt,
+	)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.RuleID,
+		&i.VisitID,
+		&i.PatientID,
+		&i.Source,
+		&i.ObservationCode,
+		&i.ObservedValue,
+		&i.ThresholdValue,
+		&i.Direction,
+		&i.Message,
+		&i.Status,
+		&i.OriginalDoctorID,
+		&i.AssignedDoctorID,
+		&i.EscalationLevel,
+		&i.EscalateAt,
+		&i.EscalatedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createAlertEscalation = `-- name: CreateAlertEscalation :one
+INSERT INTO alert_escalations (
+    alert_id, from_doctor_id, to_doctor_id, escalation_level
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, alert_id, from_doctor_id, to_doctor_id, escalation_level, escalated_at
+`
+
+type CreateAlertEscalationParams struct {
+	AlertID         pgtype.UUID
+	FromDoctorID    pgtype.UUID
+	ToDoctorID      pgtype.UUID
+	EscalationLevel int32
+}
+
+func (q *Queries) CreateAlertEscalation(ctx context.Context, arg CreateAlertEscalationParams) (AlertEscalation, error) {
+	row := q.db.QueryRow(ctx, createAlertEscalation,
+		arg.AlertID,
+		arg.FromDoctorID,
+		arg.ToDoctorID,
+		arg.EscalationLevel,
+	)
+	var i AlertEscalation
+	err := row.Scan(
+		&i.ID,
+		&i.AlertID,
+		&i.FromDoctorID,
+		&i.ToDoctorID,
+		&i.EscalationLevel,
+		&i.EscalatedAt,
+	)
+	return i, err
+}
+
+const createAlertRule = `-- name: CreateAlertRule :one
+INSERT INTO alert_rules (
+    observation_code, name, sex, min_age_years, max_age_years,
+    critical_low, critical_high, escalate_after_minutes,
+    escalation_doctor_id, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, observation_code, name, sex, min_age_years, max_age_years, critical_low, critical_high, escalate_after_minutes, escalation_doctor_id, is_active, created_by_user_id, created_at, updated_at
+`
+
+type CreateAlertRuleParams struct {
+	ObservationCode      string
+	Name                 string
+	Sex                  NullGenderEnum
+	MinAgeYears          pgtype.Int4
+	MaxAgeYears          pgtype.Int4
+	CriticalLow          pgtype.Float8
+	CriticalHigh         pgtype.Float8
+	EscalateAfterMinutes int32
+	EscalationDoctorID   pgtype.UUID
+	CreatedByUserID      pgtype.UUID
+}
+
+func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, createAlertRule,
+		arg.ObservationCode,
+		arg.Name,
+		arg.Sex,
+		arg.MinAgeYears,
+		arg.MaxAgeYears,
+		arg.CriticalLow,
+		arg.CriticalHigh,
+		arg.EscalateAfterMinutes,
+		arg.EscalationDoctorID,
+		arg.CreatedByUserID,
+	)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.ObservationCode,
+		&i.Name,
+		&i.Sex,
+		&i.MinAgeYears,
+		&i.MaxAgeYears,
+		&i.CriticalLow,
+		&i.CriticalHigh,
+		&i.EscalateAfterMinutes,
+		&i.EscalationDoctorID,
+		&i.IsActive,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createVisitObservation = `-- name: CreateVisitObservation :one
+INSERT INTO visit_observations (
+    visit_id, code, value, unit, observed_at, recorded_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, visit_id, code, value, unit, observed_at, recorded_by_user_id, created_at
+`
+
+type CreateVisitObservationParams struct {
+	VisitID          pgtype.UUID
+	Code             string
+	Value            float64
+	Unit             pgtype.Text
+	ObservedAt       pgtype.Timestamptz
+	RecordedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateVisitObservation(ctx context.Context, arg CreateVisitObservationParams) (VisitObservation, error) {
+	row := q.db.QueryRow(ctx, createVisitObservation,
+		arg.VisitID,
+		arg.Code,
+		arg.Value,
+		arg.Unit,
+		arg.ObservedAt,
+		arg.RecordedByUserID,
+	)
+	var i VisitObservation
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.Code,
+		&i.Value,
+		&i.Unit,
+		&i.ObservedAt,
+		&i.RecordedByUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deactivateAlertRule = `-- name: DeactivateAlertRule :one
+UPDATE alert_rules
+SET is_active = FALSE
+WHERE id = $1
+RETURNING id, observation_code, name, sex, min_age_years, max_age_years, critical_low, critical_high, escalate_after_minutes, escalation_doctor_id, is_active, created_by_user_id, created_at, updated_at
+`
+
+func (q *Queries) DeactivateAlertRule(ctx context.Context, id pgtype.UUID) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, deactivateAlertRule, id)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.ObservationCode,
+		&i.Name,
+		&i.Sex,
+		&i.MinAgeYears,
+		&i.MaxAgeYears,
+		&i.CriticalLow,
+		&i.CriticalHigh,
+		&i.EscalateAfterMinutes,
+		&i.EscalationDoctorID,
+		&i.IsActive,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const escalateAlert = `-- name: EscalateAlert :one
+UPDATE alerts
+SET
+    assigned_doctor_id = $2,
+    escalation_level = escalation_level + 1,
+    escalated_at = NOW(),
+    escalate_at = $3
+WHERE id = $1 AND status = 'open'
+RETURNING id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at
+`
+
+type EscalateAlertParams struct {
+	ID               pgtype.UUID
+	AssignedDoctorID pgtype.UUID
+	NextEscalateAt   pgtype.Timestamptz
+}
+
+func (q *Queries) EscalateAlert(ctx context.Context, arg EscalateAlertParams) (Alert, error) {
+	row := q.db.QueryRow(ctx, escalateAlert, arg.ID, arg.AssignedDoctorID, arg.NextEscalateAt)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.RuleID,
+		&i.VisitID,
+		&i.PatientID,
+		&i.Source,
+		&i.ObservationCode,
+		&i.ObservedValue,
+		&i.ThresholdValue,
+		&i.Direction,
+		&i.Message,
+		&i.Status,
+		&i.OriginalDoctorID,
+		&i.AssignedDoctorID,
+		&i.EscalationLevel,
+		&i.EscalateAt,
+		&i.EscalatedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAlertByID = `-- name: GetAlertByID :one
+SELECT id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at FROM alerts
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetAlertByID(ctx context.Context, id pgtype.UUID) (Alert, error) {
+	row := q.db.QueryRow(ctx, getAlertByID, id)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.RuleID,
+		&i.VisitID,
+		&i.PatientID,
+		&i.Source,
+		&i.ObservationCode,
+		&i.ObservedValue,
+		&i.ThresholdValue,
+		&i.Direction,
+		&i.Message,
+		&i.Status,
+		&i.OriginalDoctorID,
+		&i.AssignedDoctorID,
+		&i.EscalationLevel,
+		&i.EscalateAt,
+		&i.EscalatedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAlertRuleByID = `-- name: GetAlertRuleByID :one
+SELECT id, observation_code, name, sex, min_age_years, max_age_years, critical_low, critical_high, escalate_after_minutes, escalation_doctor_id, is_active, created_by_user_id, created_at, updated_at FROM alert_rules
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetAlertRuleByID(ctx context.Context, id pgtype.UUID) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, getAlertRuleByID, id)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.ObservationCode,
+		&i.Name,
+		&i.Sex,
+		&i.MinAgeYears,
+		&i.MaxAgeYears,
+		&i.CriticalLow,
+		&i.CriticalHigh,
+		&i.EscalateAfterMinutes,
+		&i.EscalationDoctorID,
+		&i.IsActive,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listActiveAlertRules = `-- name: ListActiveAlertRules :many
+SELECT id, observation_code, name, sex, min_age_years, max_age_years, critical_low, critical_high, escalate_after_minutes, escalation_doctor_id, is_active, created_by_user_id, created_at, updated_at FROM alert_rules
+WHERE is_active = TRUE
+ORDER BY observation_code, created_at
+`
+
+func (q *Queries) ListActiveAlertRules(ctx context.Context) ([]AlertRule, error) {
+	rows, err := q.db.Query(ctx, listActiveAlertRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertRule
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.ObservationCode,
+			&i.Name,
+			&i.Sex,
+			&i.MinAgeYears,
+			&i.MaxAgeYears,
+			&i.CriticalLow,
+			&i.CriticalHigh,
+			&i.EscalateAfterMinutes,
+			&i.EscalationDoctorID,
+			&i.IsActive,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveAlertRulesByCode = `-- name: ListActiveAlertRulesByCode :many
+SELECT id, observation_code, name, sex, min_age_years, max_age_years, critical_low, critical_high, escalate_after_minutes, escalation_doctor_id, is_active, created_by_user_id, created_at, updated_at FROM alert_rules
+WHERE is_active = TRUE AND observation_code = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListActiveAlertRulesByCode(ctx context.Context, observationCode string) ([]AlertRule, error) {
+	rows, err := q.db.Query(ctx, listActiveAlertRulesByCode, observationCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertRule
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.ObservationCode,
+			&i.Name,
+			&i.Sex,
+			&i.MinAgeYears,
+			&i.MaxAgeYears,
+			&i.CriticalLow,
+			&i.CriticalHigh,
+			&i.EscalateAfterMinutes,
+			&i.EscalationDoctorID,
+			&i.IsActive,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAlertsForDoctor = `-- name: ListAlertsForDoctor :many
+SELECT id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at FROM alerts
+WHERE assigned_doctor_id = $1
+  AND ($2::alert_status IS NULL OR status = $2::alert_status)
+ORDER BY created_at DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListAlertsForDoctorParams struct {
+	DoctorID  pgtype.UUID
+	Status    NullAlertStatus
+	RowOffset int32
+	RowLimit  int32
+}
+
+func (q *Queries) ListAlertsForDoctor(ctx context.Context, arg ListAlertsForDoctorParams) ([]Alert, error) {
+	rows, err := q.db.Query(ctx, listAlertsForDoctor,
+		arg.DoctorID,
+		arg.Status,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.RuleID,
+			&i.VisitID,
+			&i.PatientID,
+			&i.Source,
+			&i.ObservationCode,
+			&i.ObservedValue,
+			&i.ThresholdValue,
+			&i.Direction,
+			&i.Message,
+			&i.Status,
+			&i.OriginalDoctorID,
+			&i.AssignedDoctorID,
+			&i.EscalationLevel,
+			&i.EscalateAt,
+			&i.EscalatedAt,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisitObservations = `-- name: ListVisitObservations :many
+SELECT id, visit_id, code, value, unit, observed_at, recorded_by_user_id, created_at FROM visit_observations
+WHERE visit_id = $1
+ORDER BY observed_at DESC
+`
+
+func (q *Queries) ListVisitObservations(ctx context.Context, visitID pgtype.UUID) ([]VisitObservation, error) {
+	rows, err := q.db.Query(ctx, listVisitObservations, visitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VisitObservation
+	for rows.Next() {
+		var i VisitObservation
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitID,
+			&i.Code,
+			&i.Value,
+			&i.Unit,
+			&i.ObservedAt,
+			&i.RecordedByUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDueAlerts = `-- name: LockDueAlerts :many
+SELECT id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at FROM alerts
+WHERE status = 'open' AND escalate_at IS NOT NULL AND escalate_at <= $1
+ORDER BY escalate_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type LockDueAlertsParams struct {
+	EscalateAt pgtype.Timestamptz
+	Limit      int32
+}
+
+// Rows locked here are skipped by other replicas running the same query.
+func (q *Queries) LockDueAlerts(ctx context.Context, arg LockDueAlertsParams) ([]Alert, error) {
+	rows, err := q.db.Query(ctx, lockDueAlerts, arg.EscalateAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.RuleID,
+			&i.VisitID,
+			&i.PatientID,
+			&i.Source,
+			&i.ObservationCode,
+			&i.ObservedValue,
+			&i.ThresholdValue,
+			&i.Direction,
+			&i.Message,
+			&i.Status,
+			&i.OriginalDoctorID,
+			&i.AssignedDoctorID,
+			&i.EscalationLevel,
+			&i.EscalateAt,
+			&i.EscalatedAt,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pickEscalationDoctor = `-- name: PickEscalationDoctor :one
+SELECT u.id FROM users u
+LEFT JOIN alerts a ON a.assigned_doctor_id = u.id AND a.status = 'open'
+WHERE u.role = 'doctor' AND u.is_active = TRUE AND u.id <> $1
+GROUP BY u.id, u.username
+ORDER BY COUNT(a.id), u.username
+LIMIT 1
+`
+
+// Picks the active doctor, other than the current assignee, with the fewest open alerts.
+func (q *Queries) PickEscalationDoctor(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, pickEscalationDoctor, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const postponeAlertEscalation = `-- name: PostponeAlertEscalation :one
+UPDATE alerts
+SET escalate_at = $2
+WHERE id = $1 AND status = 'open'
+RETURNING id, rule_id, visit_id, patient_id, source, observation_code, observed_value, threshold_value, direction, message, status, original_doctor_id, assigned_doctor_id, escalation_level, escalate_at, escalated_at, acknowledged_at, acknowledged_by_user_id, created_at, updated_at
+`
+
+type PostponeAlertEscalationParams struct {
+	ID         pgtype.UUID
+	EscalateAt pgtype.Timestamptz
+}
+
+func (q *Queries) PostponeAlertEscalation(ctx context.Context, arg PostponeAlertEscalationParams) (Alert, error) {
+	row := q.db.QueryRow(ctx, postponeAlertEscalation, arg.ID, arg.EscalateAt)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.RuleID,
+		&i.VisitID,
+		&i.PatientID,
+		&i.Source,
+		&i.ObservationCode,
+		&i.ObservedValue,
+		&i.ThresholdValue,
+		&i.Direction,
+		&i.Message,
+		&i.Status,
+		&i.OriginalDoctorID,
+		&i.AssignedDoctorID,
+		&i.EscalationLevel,
+		&i.EscalateAt,
+		&i.EscalatedAt,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const tryAdvisoryXactLock = `-- name: TryAdvisoryXactLock :one
+SELECT pg_try_advisory_xact_lock($1)::boolean AS acquired
+`
+
+func (q *Queries) TryAdvisoryXactLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
+	row := q.db.QueryRow(ctx, tryAdvisoryXactLock, pgTryAdvisoryXactLock)
+	var acquired bool
+	err := row.Scan(&acquired)
+	return acquired, err
+}
@@ -0,0 +1,406 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: audit.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const acquireAdvisoryXactLock = `-- name: AcquireAdvisoryXactLock :exec
+SELECT pg_advisory_xact_lock($1)
+`
+
+// Blocks until the lock is free; released when the transaction ends.
+func (q *Queries) AcquireAdvisoryXactLock(ctx context.Context, pgAdvisoryXactLock int64) error {
+	_, err := q.db.Exec(ctx, acquireAdvisoryXactLock, pgAdvisoryXactLock)
+	return err
+}
+
+const countAuditEvents = `-- name: CountAuditEvents :one
+SELECT COUNT(*) FROM audit_events
+WHERE ($1::uuid IS NULL OR actor_user_id = $1)
+  AND ($2::uuid IS NULL OR patient_id = $2)
+  AND ($3::text IS NULL OR resource_type = $3)
+  AND ($4::uuid IS NULL OR resource_id = $4)
+  AND ($5::text IS NULL OR action = $5)
+  AND ($6::timestamptz IS NULL OR occurred_at >= $6)
+  AND ($7::timestamptz IS NULL OR occurred_at < $7)
+`
+
+type CountAuditEventsParams struct {
+	ActorUserID  pgtype.UUID
+	PatientID    pgtype.UUID
+	ResourceType pgtype.Text
+	ResourceID   pgtype.UUID
+	Action       pgtype.Text
+	OccurredFrom pgtype.Timestamptz
+	OccurredTo   pgtype.Timestamptz
+}
+
+func (q *Queries) CountAuditEvents(ctx context.Context, arg CountAuditEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAuditEvents,
+		arg.ActorUserID,
+		arg.PatientID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Action,
+		arg.OccurredFrom,
+		arg.OccurredTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPatientAccessLog = `-- name: CountPatientAccessLog :one
+SELECT COUNT(*) FROM audit_events
+WHERE patient_id = $1
+`
+
+func (q *Queries) CountPatientAccessLog(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPatientAccessLog, patientID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    seq, occurred_at, actor_user_id, actor_role, action, resource_type, resource_id,
+    patient_id, changes, client_ip, user_agent, request_id, prev_hash, hash,
+    break_glass_grant_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+RETURNING seq, occurred_at, actor_user_id, actor_role, action, resource_type, resource_id, patient_id, changes, client_ip, user_agent, request_id, prev_hash, hash, break_glass_grant_id
+`
+
+type CreateAuditEventParams struct {
+	Seq               int64
+	OccurredAt        pgtype.Timestamptz
+	ActorUserID       pgtype.UUID
+	ActorRole         pgtype.Text
+	Action            string
+	ResourceType      string
+	ResourceID        pgtype.UUID
+	PatientID         pgtype.UUID
+	Changes           []byte
+	ClientIp          pgtype.Text
+	UserAgent         pgtype.Text
+	RequestID         pgtype.Text
+	PrevHash          string
+	Hash              string
+	BreakGlassGrantID pgtype.UUID
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRow(ctx, createAuditEvent,
+		arg.Seq,
+		arg.OccurredAt,
+		arg.ActorUserID,
+		arg.ActorRole,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.PatientID,
+		arg.Changes,
+		arg.ClientIp,
+		arg.UserAgent,
+		arg.RequestID,
+		arg.PrevHash,
+		arg.Hash,
+		arg.BreakGlassGrantID,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.Seq,
+		&i.OccurredAt,
+		&i.ActorUserID,
+		&i.ActorRole,
+		&i.Action,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.PatientID,
+		&i.Changes,
+		&i.ClientIp,
+		&i.UserAgent,
+		&i.RequestID,
+		&i.PrevHash,
+		&i.Hash,
+		&i.BreakGlassGrantID,
+	)
+	return i, err
+}
+
+const getAuditChainHead = `-- name: GetAuditChainHead :one
+SELECT seq, hash FROM audit_events
+ORDER BY seq DESC
+LIMIT 1
+`
+
+type GetAuditChainHeadRow struct {
+	Seq  int64
+	Hash string
+}
+
+func (q *Queries) GetAuditChainHead(ctx context.Context) (GetAuditChainHeadRow, error) {
+	row := q.db.QueryRow(ctx, getAuditChainHead)
+	var i GetAuditChainHeadRow
+	err := row.Scan(&i.Seq, &i.Hash)
+	return i, err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT seq, occurred_at, actor_user_id, actor_role, action, resource_type, resource_id, patient_id, changes, client_ip, user_agent, request_id, prev_hash, hash, break_glass_grant_id FROM audit_events
+WHERE ($1::uuid IS NULL OR actor_user_id = $1)
+  AND ($2::uuid IS NULL OR patient_id = $2)
+  AND ($3::text IS NULL OR resource_type = $3)
+  AND ($4::uuid IS NULL OR resource_id = $4)
+  AND ($5::text IS NULL OR action = $5)
+  AND ($6::timestamptz IS NULL OR occurred_at >= $6)
+  AND ($7::timestamptz IS NULL OR occurred_at < $7)
+ORDER BY seq DESC
+LIMIT $9
+OFFSET $8
+`
+
+type ListAuditEventsParams struct {
+	ActorUserID  pgtype.UUID
+	PatientID    pgtype.UUID
+	ResourceType pgtype.Text
+	ResourceID   pgtype.UUID
+	Action       pgtype.Text
+	OccurredFrom pgtype.Timestamptz
+	OccurredTo   pgtype.Timestamptz
+	RowOffset    int32
+	RowLimit     int32
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEvents,
+		arg.ActorUserID,
+		arg.PatientID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Action,
+		arg.OccurredFrom,
+		arg.OccurredTo,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OccurredAt,
+			&i.ActorUserID,
+			&i.ActorRole,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.PatientID,
+			&i.Changes,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.RequestID,
+			&i.PrevHash,
+			&i.Hash,
+			&i.BreakGlassGrantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditEventsAfterSeq = `-- name: ListAuditEventsAfterSeq :many
+SELECT seq, occurred_at, actor_user_id, actor_role, action, resource_type, resource_id, patient_id, changes, client_ip, user_agent, request_id, prev_hash, hash, break_glass_grant_id FROM audit_events
+WHERE seq > $1
+ORDER BY seq
+LIMIT $2
+`
+
+type ListAuditEventsAfterSeqParams struct {
+	Seq   int64
+	Limit int32
+}
+
+// Pages through the chain in order for verification.
+func (q *Queries) ListAuditEventsAfterSeq(ctx context.Context, arg ListAuditEventsAfterSeqParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEventsAfterSeq, arg.Seq, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OccurredAt,
+			&i.ActorUserID,
+			&i.ActorRole,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.PatientID,
+			&i.Changes,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.RequestID,
+			&i.PrevHash,
+			&i.Hash,
+			&i.BreakGlassGrantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientAccessLog = `-- name: ListPatientAccessLog :many
+SELECT e.seq, e.occurred_at, e.actor_user_id, u.username AS actor_username, e.actor_role,
+       e.action, e.resource_type, e.resource_id, e.changes, e.client_ip, e.break_glass_grant_id
+FROM audit_events e
+LEFT JOIN users u ON u.id = e.actor_user_id
+WHERE e.patient_id = $1
+ORDER BY e.occurred_at DESC, e.seq DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListPatientAccessLogParams struct {
+	PatientID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+type ListPatientAccessLogRow struct {
+	Seq               int64
+	OccurredAt        pgtype.Timestamptz
+	ActorUserID       pgtype.UUID
+	ActorUsername     pgtype.Text
+	ActorRole         pgtype.Text
+	Action            string
+	ResourceType      string
+	ResourceID        pgtype.UUID
+	Changes           []byte
+	ClientIp          pgtype.Text
+	BreakGlassGrantID pgtype.UUID
+}
+
+// Every audited touch of a patient or their visits, newest first, with the actor's
+// current username. Users deleted since keep their ID but lose the name.
+func (q *Queries) ListPatientAccessLog(ctx context.Context, arg ListPatientAccessLogParams) ([]ListPatientAccessLogRow, error) {
+	rows, err := q.db.Query(ctx, listPatientAccessLog, arg.PatientID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPatientAccessLogRow
+	for rows.Next() {
+		var i ListPatientAccessLogRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OccurredAt,
+			&i.ActorUserID,
+			&i.ActorUsername,
+			&i.ActorRole,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Changes,
+			&i.ClientIp,
+			&i.BreakGlassGrantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientAccessLogBeforeSeq = `-- name: ListPatientAccessLogBeforeSeq :many
+SELECT e.seq, e.occurred_at, e.actor_user_id, u.username AS actor_username, e.actor_role,
+       e.action, e.resource_type, e.resource_id, e.changes, e.client_ip, e.break_glass_grant_id
+FROM audit_events e
+LEFT JOIN users u ON u.id = e.actor_user_id
+WHERE e.patient_id = $1 AND e.seq < $2
+ORDER BY e.seq DESC
+LIMIT $3
+`
+
+type ListPatientAccessLogBeforeSeqParams struct {
+	PatientID pgtype.UUID
+	BeforeSeq int64
+	RowLimit  int32
+}
+
+type ListPatientAccessLogBeforeSeqRow struct {
+	Seq               int64
+	OccurredAt        pgtype.Timestamptz
+	ActorUserID       pgtype.UUID
+	ActorUsername     pgtype.Text
+	ActorRole         pgtype.Text
+	Action            string
+	ResourceType      string
+	ResourceID        pgtype.UUID
+	Changes           []byte
+	ClientIp          pgtype.Text
+	BreakGlassGrantID pgtype.UUID
+}
+
+// Pages through a patient's access log newest first by seq, which new events cannot
+// shift, for exports.
+func (q *Queries) ListPatientAccessLogBeforeSeq(ctx context.Context, arg ListPatientAccessLogBeforeSeqParams) ([]ListPatientAccessLogBeforeSeqRow, error) {
+	rows, err := q.db.Query(ctx, listPatientAccessLogBeforeSeq, arg.PatientID, arg.BeforeSeq, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPatientAccessLogBeforeSeqRow
+	for rows.Next() {
+		var i ListPatientAccessLogBeforeSeqRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OccurredAt,
+			&i.ActorUserID,
+			&i.ActorUsername,
+			&i.ActorRole,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Changes,
+			&i.ClientIp,
+			&i.BreakGlassGrantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,971 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: billing.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const assignChargeToInvoice = `-- name: AssignChargeToInvoice :exec
+UPDATE charges
+SET invoice_id = $2
+WHERE id = $1 AND invoice_id IS NULL
+`
+
+type AssignChargeToInvoiceParams struct {
+	ID        pgtype.UUID
+	InvoiceID pgtype.UUID
+}
+
+func (q *Queries) AssignChargeToInvoice(ctx context.Context, arg AssignChargeToInvoiceParams) error {
+	_, err := q.db.Exec(ctx, assignChargeToInvoice, arg.ID, arg.InvoiceID)
+	return err
+}
+
+const countChargeItems = `-- name: CountChargeItems :one
+SELECT COUNT(*) FROM charge_items
+WHERE is_active = TRUE
+`
+
+func (q *Queries) CountChargeItems(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countChargeItems)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countChargesByPatientID = `-- name: CountChargesByPatientID :one
+SELECT COUNT(*) FROM charges
+WHERE patient_id = $1
+  AND (NOT $2::boolean OR invoice_id IS NULL)
+`
+
+type CountChargesByPatientIDParams struct {
+	PatientID    pgtype.UUID
+	UnbilledOnly bool
+}
+
+func (q *Queries) CountChargesByPatientID(ctx context.Context, arg CountChargesByPatientIDParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countChargesByPatientID, arg.PatientID, arg.UnbilledOnly)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countInvoicesByPatientID = `-- name: CountInvoicesByPatientID :one
+SELECT COUNT(*) FROM invoices
+WHERE patient_id = $1
+`
+
+func (q *Queries) CountInvoicesByPatientID(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countInvoicesByPatientID, patientID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCharge = `-- name: CreateCharge :one
+INSERT INTO charges (
+    patient_id, visit_id, charge_item_id, source, source_id,
+    description, quantity, unit_price_minor, tax_rate_bps, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (source, source_id, charge_item_id) WHERE source_id IS NOT NULL DO NOTHING
+RETURNING id, patient_id, visit_id, charge_item_id, source, source_id, description, quantity, unit_price_minor, tax_rate_bps, invoice_id, created_by_user_id, created_at
+`
+
+type CreateChargeParams struct {
+	PatientID       pgtype.UUID
+	VisitID         pgtype.UUID
+	ChargeItemID    pgtype.UUID
+	Source          ChargeSource
+	SourceID        pgtype.UUID
+	Description     string
+	Quantity        int32
+	UnitPriceMinor  int64
+	TaxRateBps      int32
+	CreatedByUserID pgtype.UUID
+}
+
+// Returns no row when the same source record was already charged for this item.
+func (q *Queries) CreateCharge(ctx context.Context, arg CreateChargeParams) (Charge, error) {
+	row := q.db.QueryRow(ctx, createCharge,
+		arg.PatientID,
+		arg.VisitID,
+		arg.ChargeItemID,
+		arg.Source,
+		arg.SourceID,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitPriceMinor,
+		arg.TaxRateBps,
+		arg.CreatedByUserID,
+	)
+	var i Charge
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.VisitID,
+		&i.ChargeItemID,
+		&i.Source,
+		&i.SourceID,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitPriceMinor,
+		&i.TaxRateBps,
+		&i.InvoiceID,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createChargeItem = `-- name: CreateChargeItem :one
+INSERT INTO charge_items (
+    code, description, unit_price_minor, tax_category_code
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, code, description, unit_price_minor, tax_category_code, is_active, created_at, updated_at
+`
+
+type CreateChargeItemParams struct {
+	Code            string
+	Description     string
+	UnitPriceMinor  int64
+	TaxCategoryCode string
+}
+
+func (q *Queries) CreateChargeItem(ctx context.Context, arg CreateChargeItemParams) (ChargeItem, error) {
+	row := q.db.QueryRow(ctx, createChargeItem,
+		arg.Code,
+		arg.Description,
+		arg.UnitPriceMinor,
+		arg.TaxCategoryCode,
+	)
+	var i ChargeItem
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.UnitPriceMinor,
+		&i.TaxCategoryCode,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createInvoice = `-- name: CreateInvoice :one
+INSERT INTO invoices (
+    invoice_number, fiscal_year, sequence_number, patient_id,
+    subtotal_minor, discount_minor, tax_minor, total_minor, notes, issued_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, invoice_number, fiscal_year, sequence_number, patient_id, status, subtotal_minor, discount_minor, tax_minor, total_minor, paid_minor, notes, issued_by_user_id, issued_at, created_at, updated_at
+`
+
+type CreateInvoiceParams struct {
+	InvoiceNumber  string
+	FiscalYear     int32
+	SequenceNumber int32
+	PatientID      pgtype.UUID
+	SubtotalMinor  int64
+	DiscountMinor  int64
+	TaxMinor       int64
+	TotalMinor     int64
+	Notes          pgtype.Text
+	IssuedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, createInvoice,
+		arg.InvoiceNumber,
+		arg.FiscalYear,
+		arg.SequenceNumber,
+		arg.PatientID,
+		arg.SubtotalMinor,
+		arg.DiscountMinor,
+		arg.TaxMinor,
+		arg.TotalMinor,
+		arg.Notes,
+		arg.IssuedByUserID,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceNumber,
+		&i.FiscalYear,
+		&i.SequenceNumber,
+		&i.PatientID,
+		&i.Status,
+		&i.SubtotalMinor,
+		&i.DiscountMinor,
+		&i.TaxMinor,
+		&i.TotalMinor,
+		&i.PaidMinor,
+		&i.Notes,
+		&i.IssuedByUserID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createInvoiceLine = `-- name: CreateInvoiceLine :one
+INSERT INTO invoice_lines (
+    invoice_id, charge_id, description, quantity, unit_price_minor,
+    discount_minor, tax_rate_bps, tax_minor, line_total_minor
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, invoice_id, charge_id, description, quantity, unit_price_minor, discount_minor, tax_rate_bps, tax_minor, line_total_minor
+`
+
+type CreateInvoiceLineParams struct {
+	InvoiceID      pgtype.UUID
+	ChargeID       pgtype.UUID
+	Description    string
+	Quantity       int32
+	UnitPriceMinor int64
+	DiscountMinor  int64
+	TaxRateBps     int32
+	TaxMinor       int64
+	LineTotalMinor int64
+}
+
+func (q *Queries) CreateInvoiceLine(ctx context.Context, arg CreateInvoiceLineParams) (InvoiceLine, error) {
+	row := q.db.QueryRow(ctx, createInvoiceLine,
+		arg.InvoiceID,
+		arg.ChargeID,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitPriceMinor,
+		arg.DiscountMinor,
+		arg.TaxRateBps,
+		arg.TaxMinor,
+		arg.LineTotalMinor,
+	)
+	var i InvoiceLine
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.ChargeID,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitPriceMinor,
+		&i.DiscountMinor,
+		&i.TaxRateBps,
+		&i.TaxMinor,
+		&i.LineTotalMinor,
+	)
+	return i, err
+}
+
+const createPayment = `-- name: CreatePayment :one
+INSERT INTO payments (
+    invoice_id, patient_id, method, amount_minor, reference, received_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, invoice_id, patient_id, method, amount_minor, reference, received_by_user_id, received_at
+`
+
+type CreatePaymentParams struct {
+	InvoiceID        pgtype.UUID
+	PatientID        pgtype.UUID
+	Method           PaymentMethod
+	AmountMinor      int64
+	Reference        pgtype.Text
+	ReceivedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, createPayment,
+		arg.InvoiceID,
+		arg.PatientID,
+		arg.Method,
+		arg.AmountMinor,
+		arg.Reference,
+		arg.ReceivedByUserID,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PatientID,
+		&i.Method,
+		&i.AmountMinor,
+		&i.Reference,
+		&i.ReceivedByUserID,
+		&i.ReceivedAt,
+	)
+	return i, err
+}
+
+const createRefund = `-- name: CreateRefund :one
+INSERT INTO refunds (
+    payment_id, invoice_id, amount_minor, reason, refunded_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, payment_id, invoice_id, amount_minor, reason, refunded_by_user_id, refunded_at
+`
+
+type CreateRefundParams struct {
+	PaymentID        pgtype.UUID
+	InvoiceID        pgtype.UUID
+	AmountMinor      int64
+	Reason           string
+	RefundedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error) {
+	row := q.db.QueryRow(ctx, createRefund,
+		arg.PaymentID,
+		arg.InvoiceID,
+		arg.AmountMinor,
+		arg.Reason,
+		arg.RefundedByUserID,
+	)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.InvoiceID,
+		&i.AmountMinor,
+		&i.Reason,
+		&i.RefundedByUserID,
+		&i.RefundedAt,
+	)
+	return i, err
+}
+
+const createTaxCategory = `-- name: CreateTaxCategory :one
+INSERT INTO tax_categories (
+    code, description, rate_bps
+) VALUES (
+    $1, $2, $3
+)
+RETURNING code, description, rate_bps, created_at, updated_at
+`
+
+type CreateTaxCategoryParams struct {
+	Code        string
+	Description string
+	RateBps     int32
+}
+
+func (q *Queries) CreateTaxCategory(ctx context.Context, arg CreateTaxCategoryParams) (TaxCategory, error) {
+	row := q.db.QueryRow(ctx, createTaxCategory, arg.Code, arg.Description, arg.RateBps)
+	var i TaxCategory
+	err := row.Scan(
+		&i.Code,
+		&i.Description,
+		&i.RateBps,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getChargeItemByCode = `-- name: GetChargeItemByCode :one
+SELECT ci.id, ci.code, ci.description, ci.unit_price_minor, ci.tax_category_code, ci.is_active, ci.created_at, ci.updated_at, tc.rate_bps AS tax_rate_bps
+FROM charge_items ci
+JOIN tax_categories tc ON tc.code = ci.tax_category_code
+WHERE ci.code = $1
+LIMIT 1
+`
+
+type GetChargeItemByCodeRow struct {
+	ID              pgtype.UUID
+	Code            string
+	Description     string
+	UnitPriceMinor  int64
+	TaxCategoryCode string
+	IsActive        bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	TaxRateBps      int32
+}
+
+func (q *Queries) GetChargeItemByCode(ctx context.Context, code string) (GetChargeItemByCodeRow, error) {
+	row := q.db.QueryRow(ctx, getChargeItemByCode, code)
+	var i GetChargeItemByCodeRow
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.UnitPriceMinor,
+		&i.TaxCategoryCode,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxRateBps,
+	)
+	return i, err
+}
+
+const getInvoiceByID = `-- name: GetInvoiceByID :one
+SELECT id, invoice_number, fiscal_year, sequence_number, patient_id, status, subtotal_minor, discount_minor, tax_minor, total_minor, paid_minor, notes, issued_by_user_id, issued_at, created_at, updated_at FROM invoices
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetInvoiceByID(ctx context.Context, id pgtype.UUID) (Invoice, error) {
+	row := q.db.QueryRow(ctx, getInvoiceByID, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceNumber,
+		&i.FiscalYear,
+		&i.SequenceNumber,
+		&i.PatientID,
+		&i.Status,
+		&i.SubtotalMinor,
+		&i.DiscountMinor,
+		&i.TaxMinor,
+		&i.TotalMinor,
+		&i.PaidMinor,
+		&i.Notes,
+		&i.IssuedByUserID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPatientInvoiceTotals = `-- name: GetPatientInvoiceTotals :one
+SELECT
+    COALESCE(SUM(total_minor), 0)::bigint AS invoiced_minor,
+    COALESCE(SUM(paid_minor), 0)::bigint AS paid_minor
+FROM invoices
+WHERE patient_id = $1
+`
+
+type GetPatientInvoiceTotalsRow struct {
+	InvoicedMinor int64
+	PaidMinor     int64
+}
+
+func (q *Queries) GetPatientInvoiceTotals(ctx context.Context, patientID pgtype.UUID) (GetPatientInvoiceTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getPatientInvoiceTotals, patientID)
+	var i GetPatientInvoiceTotalsRow
+	err := row.Scan(&i.InvoicedMinor, &i.PaidMinor)
+	return i, err
+}
+
+const listChargeItems = `-- name: ListChargeItems :many
+SELECT ci.id, ci.code, ci.description, ci.unit_price_minor, ci.tax_category_code, ci.is_active, ci.created_at, ci.updated_at, tc.rate_bps AS tax_rate_bps
+FROM charge_items ci
+JOIN tax_categories tc ON tc.code = ci.tax_category_code
+WHERE ci.is_active = TRUE
+ORDER BY ci.code
+LIMIT $1
+OFFSET $2
+`
+
+type ListChargeItemsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+type ListChargeItemsRow struct {
+	ID              pgtype.UUID
+	Code            string
+	Description     string
+	UnitPriceMinor  int64
+	TaxCategoryCode string
+	IsActive        bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	TaxRateBps      int32
+}
+
+func (q *Queries) ListChargeItems(ctx context.Context, arg ListChargeItemsParams) ([]ListChargeItemsRow, error) {
+	rows, err := q.db.Query(ctx, listChargeItems, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListChargeItemsRow
+	for rows.Next() {
+		var i ListChargeItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Description,
+			&i.UnitPriceMinor,
+			&i.TaxCategoryCode,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TaxRateBps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChargesByPatientID = `-- name: ListChargesByPatientID :many
+SELECT id, patient_id, visit_id, charge_item_id, source, source_id, description, quantity, unit_price_minor, tax_rate_bps, invoice_id, created_by_user_id, created_at FROM charges
+WHERE patient_id = $1
+  AND (NOT $2::boolean OR invoice_id IS NULL)
+ORDER BY created_at DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListChargesByPatientIDParams struct {
+	PatientID    pgtype.UUID
+	UnbilledOnly bool
+	RowOffset    int32
+	RowLimit     int32
+}
+
+func (q *Queries) ListChargesByPatientID(ctx context.Context, arg ListChargesByPatientIDParams) ([]Charge, error) {
+	rows, err := q.db.Query(ctx, listChargesByPatientID,
+		arg.PatientID,
+		arg.UnbilledOnly,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Charge
+	for rows.Next() {
+		var i Charge
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.VisitID,
+			&i.ChargeItemID,
+			&i.Source,
+			&i.SourceID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPriceMinor,
+			&i.TaxRateBps,
+			&i.InvoiceID,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoiceLines = `-- name: ListInvoiceLines :many
+SELECT id, invoice_id, charge_id, description, quantity, unit_price_minor, discount_minor, tax_rate_bps, tax_minor, line_total_minor FROM invoice_lines
+WHERE invoice_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListInvoiceLines(ctx context.Context, invoiceID pgtype.UUID) ([]InvoiceLine, error) {
+	rows, err := q.db.Query(ctx, listInvoiceLines, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoiceLine
+	for rows.Next() {
+		var i InvoiceLine
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.ChargeID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPriceMinor,
+			&i.DiscountMinor,
+			&i.TaxRateBps,
+			&i.TaxMinor,
+			&i.LineTotalMinor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoicesByPatientID = `-- name: ListInvoicesByPatientID :many
+SELECT id, invoice_number, fiscal_year, sequence_number, patient_id, status, subtotal_minor, discount_minor, tax_minor, total_minor, paid_minor, notes, issued_by_user_id, issued_at, created_at, updated_at FROM invoices
+WHERE patient_id = $1
+ORDER BY issued_at DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListInvoicesByPatientIDParams struct {
+	PatientID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListInvoicesByPatientID(ctx context.Context, arg ListInvoicesByPatientIDParams) ([]Invoice, error) {
+	rows, err := q.db.Query(ctx, listInvoicesByPatientID, arg.PatientID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Invoice
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceNumber,
+			&i.FiscalYear,
+			&i.SequenceNumber,
+			&i.PatientID,
+			&i.Status,
+			&i.SubtotalMinor,
+			&i.DiscountMinor,
+			&i.TaxMinor,
+			&i.TotalMinor,
+			&i.PaidMinor,
+			&i.Notes,
+			&i.IssuedByUserID,
+			&i.IssuedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentsByInvoiceID = `-- name: ListPaymentsByInvoiceID :many
+SELECT id, invoice_id, patient_id, method, amount_minor, reference, received_by_user_id, received_at FROM payments
+WHERE invoice_id = $1
+ORDER BY received_at
+`
+
+func (q *Queries) ListPaymentsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByInvoiceID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.PatientID,
+			&i.Method,
+			&i.AmountMinor,
+			&i.Reference,
+			&i.ReceivedByUserID,
+			&i.ReceivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRefundsByInvoiceID = `-- name: ListRefundsByInvoiceID :many
+SELECT id, payment_id, invoice_id, amount_minor, reason, refunded_by_user_id, refunded_at FROM refunds
+WHERE invoice_id = $1
+ORDER BY refunded_at
+`
+
+func (q *Queries) ListRefundsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]Refund, error) {
+	rows, err := q.db.Query(ctx, listRefundsByInvoiceID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Refund
+	for rows.Next() {
+		var i Refund
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.InvoiceID,
+			&i.AmountMinor,
+			&i.Reason,
+			&i.RefundedByUserID,
+			&i.RefundedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaxCategories = `-- name: ListTaxCategories :many
+SELECT code, description, rate_bps, created_at, updated_at FROM tax_categories
+ORDER BY code
+`
+
+func (q *Queries) ListTaxCategories(ctx context.Context) ([]TaxCategory, error) {
+	rows, err := q.db.Query(ctx, listTaxCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxCategory
+	for rows.Next() {
+		var i TaxCategory
+		if err := rows.Scan(
+			&i.Code,
+			&i.Description,
+			&i.RateBps,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbilledChargesByPatientID = `-- name: ListUnbilledChargesByPatientID :many
+SELECT id, patient_id, visit_id, charge_item_id, source, source_id, description, quantity, unit_price_minor, tax_rate_bps, invoice_id, created_by_user_id, created_at FROM charges
+WHERE patient_id = $1 AND invoice_id IS NULL
+ORDER BY created_at
+`
+
+func (q *Queries) ListUnbilledChargesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]Charge, error) {
+	rows, err := q.db.Query(ctx, listUnbilledChargesByPatientID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Charge
+	for rows.Next() {
+		var i Charge
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.VisitID,
+			&i.ChargeItemID,
+			&i.Source,
+			&i.SourceID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPriceMinor,
+			&i.TaxRateBps,
+			&i.InvoiceID,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockInvoice = `-- name: LockInvoice :one
+SELECT id, invoice_number, fiscal_year, sequence_number, patient_id, status, subtotal_minor, discount_minor, tax_minor, total_minor, paid_minor, notes, issued_by_user_id, issued_at, created_at, updated_at FROM invoices
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockInvoice(ctx context.Context, id pgtype.UUID) (Invoice, error) {
+	row := q.db.QueryRow(ctx, lockInvoice, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceNumber,
+		&i.FiscalYear,
+		&i.SequenceNumber,
+		&i.PatientID,
+		&i.Status,
+		&i.SubtotalMinor,
+		&i.DiscountMinor,
+		&i.TaxMinor,
+		&i.TotalMinor,
+		&i.PaidMinor,
+		&i.Notes,
+		&i.IssuedByUserID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const lockPayment = `-- name: LockPayment :one
+SELECT id, invoice_id, patient_id, method, amount_minor, reference, received_by_user_id, received_at FROM payments
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPayment(ctx context.Context, id pgtype.UUID) (Payment, error) {
+	row := q.db.QueryRow(ctx, lockPayment, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PatientID,
+		&i.Method,
+		&i.AmountMinor,
+		&i.Reference,
+		&i.ReceivedByUserID,
+		&i.ReceivedAt,
+	)
+	return i, err
+}
+
+const lockUnbilledCharges = `-- name: LockUnbilledCharges :many
+SELECT id, patient_id, visit_id, charge_item_id, source, source_id, description, quantity, unit_price_minor, tax_rate_bps, invoice_id, created_by_user_id, created_at FROM charges
+WHERE patient_id = $1
+  AND invoice_id IS NULL
+  AND (cardinality($2::uuid[]) = 0 OR id = ANY($2::uuid[]))
+ORDER BY created_at
+FOR UPDATE
+`
+
+type LockUnbilledChargesParams struct {
+	PatientID pgtype.UUID
+	ChargeIds []pgtype.UUID
+}
+
+// An empty charge_ids array selects every unbilled charge of the patient.
+func (q *Queries) LockUnbilledCharges(ctx context.Context, arg LockUnbilledChargesParams) ([]Charge, error) {
+	rows, err := q.db.Query(ctx, lockUnbilledCharges, arg.PatientID, arg.ChargeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Charge
+	for rows.Next() {
+		var i Charge
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.VisitID,
+			&i.ChargeItemID,
+			&i.Source,
+			&i.SourceID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPriceMinor,
+			&i.TaxRateBps,
+			&i.InvoiceID,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextInvoiceSequence = `-- name: NextInvoiceSequence :one
+INSERT INTO invoice_counters (fiscal_year, last_number)
+VALUES ($1, 1)
+ON CONFLICT (fiscal_year) DO UPDATE
+SET last_number = invoice_counters.last_number + 1
+RETURNING last_number
+`
+
+func (q *Queries) NextInvoiceSequence(ctx context.Context, fiscalYear int32) (int32, error) {
+	row := q.db.QueryRow(ctx, nextInvoiceSequence, fiscalYear)
+	var last_number int32
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const sumRefundsByPaymentID = `-- name: SumRefundsByPaymentID :one
+SELECT COALESCE(SUM(amount_minor), 0)::bigint FROM refunds
+WHERE payment_id = $1
+`
+
+func (q *Queries) SumRefundsByPaymentID(ctx context.Context, paymentID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, sumRefundsByPaymentID, paymentID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const updateInvoicePayment = `-- name: UpdateInvoicePayment :one
+UPDATE invoices
+SET paid_minor = $2, status = $3
+WHERE id = $1
+RETURNING id, invoice_number, fiscal_year, sequence_number, patient_id, status, subtotal_minor, discount_minor, tax_minor, total_minor, paid_minor, notes, issued_by_user_id, issued_at, created_at, updated_at
+`
+
+type UpdateInvoicePaymentParams struct {
+	ID        pgtype.UUID
+	PaidMinor int64
+	Status    InvoiceStatus
+}
+
+func (q *Queries) UpdateInvoicePayment(ctx context.Context, arg UpdateInvoicePaymentParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, updateInvoicePayment, arg.ID, arg.PaidMinor, arg.Status)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceNumber,
+		&i.FiscalYear,
+		&i.SequenceNumber,
+		&i.PatientID,
+		&i.Status,
+		&i.SubtotalMinor,
+		&i.DiscountMinor,
+		&i.TaxMinor,
+		&i.TotalMinor,
+		&i.PaidMinor,
+		&i.Notes,
+		&i.IssuedByUserID,
+		&i.IssuedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,305 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: break_glass.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countBreakGlassReports = `-- name: CountBreakGlassReports :one
+SELECT COUNT(*) FROM break_glass_reports
+`
+
+func (q *Queries) CountBreakGlassReports(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countBreakGlassReports)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createBreakGlassGrant = `-- name: CreateBreakGlassGrant :one
+INSERT INTO break_glass_grants (
+    id, user_id, patient_id, reason, granted_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, user_id, patient_id, reason, granted_at, expires_at, revoked_at
+`
+
+type CreateBreakGlassGrantParams struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	PatientID pgtype.UUID
+	Reason    string
+	GrantedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateBreakGlassGrant(ctx context.Context, arg CreateBreakGlassGrantParams) (BreakGlassGrant, error) {
+	row := q.db.QueryRow(ctx, createBreakGlassGrant,
+		arg.ID,
+		arg.UserID,
+		arg.PatientID,
+		arg.Reason,
+		arg.GrantedAt,
+		arg.ExpiresAt,
+	)
+	var i BreakGlassGrant
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PatientID,
+		&i.Reason,
+		&i.GrantedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const createBreakGlassReport = `-- name: CreateBreakGlassReport :one
+INSERT INTO break_glass_reports (
+    report_date, grant_count, access_count, report
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (report_date) DO NOTHING
+RETURNING report_date, generated_at, grant_count, access_count, report
+`
+
+type CreateBreakGlassReportParams struct {
+	ReportDate  pgtype.Date
+	GrantCount  int32
+	AccessCount int32
+	Report      []byte
+}
+
+// A report that already exists for the day is kept; the caller then gets no rows.
+func (q *Queries) CreateBreakGlassReport(ctx context.Context, arg CreateBreakGlassReportParams) (BreakGlassReport, error) {
+	row := q.db.QueryRow(ctx, createBreakGlassReport,
+		arg.ReportDate,
+		arg.GrantCount,
+		arg.AccessCount,
+		arg.Report,
+	)
+	var i BreakGlassReport
+	err := row.Scan(
+		&i.ReportDate,
+		&i.GeneratedAt,
+		&i.GrantCount,
+		&i.AccessCount,
+		&i.Report,
+	)
+	return i, err
+}
+
+const getActiveBreakGlassGrant = `-- name: GetActiveBreakGlassGrant :one
+SELECT id, user_id, patient_id, reason, granted_at, expires_at, revoked_at FROM break_glass_grants
+WHERE user_id = $1
+  AND patient_id = $2
+  AND revoked_at IS NULL
+  AND granted_at <= $3
+  AND expires_at > $3
+ORDER BY expires_at DESC
+LIMIT 1
+`
+
+type GetActiveBreakGlassGrantParams struct {
+	UserID    pgtype.UUID
+	PatientID pgtype.UUID
+	AsOf      pgtype.Timestamptz
+}
+
+// The grant with the most time left, if the user holds one for the patient at as_of.
+func (q *Queries) GetActiveBreakGlassGrant(ctx context.Context, arg GetActiveBreakGlassGrantParams) (BreakGlassGrant, error) {
+	row := q.db.QueryRow(ctx, getActiveBreakGlassGrant, arg.UserID, arg.PatientID, arg.AsOf)
+	var i BreakGlassGrant
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PatientID,
+		&i.Reason,
+		&i.GrantedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getBreakGlassReport = `-- name: GetBreakGlassReport :one
+SELECT report_date, generated_at, grant_count, access_count, report FROM break_glass_reports
+WHERE report_date = $1
+`
+
+func (q *Queries) GetBreakGlassReport(ctx context.Context, reportDate pgtype.Date) (BreakGlassReport, error) {
+	row := q.db.QueryRow(ctx, getBreakGlassReport, reportDate)
+	var i BreakGlassReport
+	err := row.Scan(
+		&i.ReportDate,
+		&i.GeneratedAt,
+		&i.GrantCount,
+		&i.AccessCount,
+		&i.Report,
+	)
+	return i, err
+}
+
+const listBreakGlassAccesses = `-- name: ListBreakGlassAccesses :many
+SELECT seq, occurred_at, break_glass_grant_id, action, resource_type, resource_id
+FROM audit_events
+WHERE break_glass_grant_id IS NOT NULL
+  AND occurred_at >= $1
+  AND occurred_at < $2
+ORDER BY seq
+`
+
+type ListBreakGlassAccessesParams struct {
+	WindowStart pgtype.Timestamptz
+	WindowEnd   pgtype.Timestamptz
+}
+
+type ListBreakGlassAccessesRow struct {
+	Seq               int64
+	OccurredAt        pgtype.Timestamptz
+	BreakGlassGrantID pgtype.UUID
+	Action            string
+	ResourceType      string
+	ResourceID        pgtype.UUID
+}
+
+func (q *Queries) ListBreakGlassAccesses(ctx context.Context, arg ListBreakGlassAccessesParams) ([]ListBreakGlassAccessesRow, error) {
+	rows, err := q.db.Query(ctx, listBreakGlassAccesses, arg.WindowStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBreakGlassAccessesRow
+	for rows.Next() {
+		var i ListBreakGlassAccessesRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OccurredAt,
+			&i.BreakGlassGrantID,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBreakGlassGrantsForReview = `-- name: ListBreakGlassGrantsForReview :many
+SELECT g.id, g.user_id, g.patient_id, g.reason, g.granted_at, g.expires_at, g.revoked_at, u.username
+FROM break_glass_grants g
+JOIN users u ON u.id = g.user_id
+WHERE (g.granted_at >= $1 AND g.granted_at < $2)
+   OR EXISTS (
+       SELECT 1 FROM audit_events e
+       WHERE e.break_glass_grant_id = g.id
+         AND e.occurred_at >= $1
+         AND e.occurred_at < $2
+   )
+ORDER BY g.granted_at
+`
+
+type ListBreakGlassGrantsForReviewParams struct {
+	WindowStart pgtype.Timestamptz
+	WindowEnd   pgtype.Timestamptz
+}
+
+type ListBreakGlassGrantsForReviewRow struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	PatientID pgtype.UUID
+	Reason    string
+	GrantedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	Username  string
+}
+
+// Grants issued in the window, plus older grants that were still being used in it.
+func (q *Queries) ListBreakGlassGrantsForReview(ctx context.Context, arg ListBreakGlassGrantsForReviewParams) ([]ListBreakGlassGrantsForReviewRow, error) {
+	rows, err := q.db.Query(ctx, listBreakGlassGrantsForReview, arg.WindowStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBreakGlassGrantsForReviewRow
+	for rows.Next() {
+		var i ListBreakGlassGrantsForReviewRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PatientID,
+			&i.Reason,
+			&i.GrantedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBreakGlassReports = `-- name: ListBreakGlassReports :many
+SELECT report_date, generated_at, grant_count, access_count
+FROM break_glass_reports
+ORDER BY report_date DESC
+LIMIT $1
+OFFSET $2
+`
+
+type ListBreakGlassReportsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+type ListBreakGlassReportsRow struct {
+	ReportDate  pgtype.Date
+	GeneratedAt pgtype.Timestamptz
+	GrantCount  int32
+	AccessCount int32
+}
+
+func (q *Queries) ListBreakGlassReports(ctx context.Context, arg ListBreakGlassReportsParams) ([]ListBreakGlassReportsRow, error) {
+	rows, err := q.db.Query(ctx, listBreakGlassReports, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBreakGlassReportsRow
+	for rows.Next() {
+		var i ListBreakGlassReportsRow
+		if err := rows.Scan(
+			&i.ReportDate,
+			&i.GeneratedAt,
+			&i.GrantCount,
+			&i.AccessCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
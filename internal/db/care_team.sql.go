@@ -0,0 +1,133 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: care_team.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addCareTeamMember = `-- name: AddCareTeamMember :one
+INSERT INTO care_team_members (
+    patient_id, user_id, assigned_by_user_id
+) VALUES (
+    $1, $2, $3
+)
+RETURNING patient_id, user_id, assigned_by_user_id, assigned_at
+`
+
+type AddCareTeamMemberParams struct {
+	PatientID        pgtype.UUID
+	UserID           pgtype.UUID
+	AssignedByUserID pgtype.UUID
+}
+
+func (q *Queries) AddCareTeamMember(ctx context.Context, arg AddCareTeamMemberParams) (CareTeamMember, error) {
+	row := q.db.QueryRow(ctx, addCareTeamMember, arg.PatientID, arg.UserID, arg.AssignedByUserID)
+	var i CareTeamMember
+	err := row.Scan(
+		&i.PatientID,
+		&i.UserID,
+		&i.AssignedByUserID,
+		&i.AssignedAt,
+	)
+	return i, err
+}
+
+const isInCareOf = `-- name: IsInCareOf :one
+SELECT EXISTS (
+    SELECT 1 FROM care_team_members ct
+    WHERE ct.patient_id = $1::uuid AND ct.user_id = $2::uuid
+    UNION ALL
+    SELECT 1 FROM patient_visits pv
+    WHERE pv.patient_id = $1::uuid AND pv.doctor_id = $2::uuid
+) AS in_care
+`
+
+type IsInCareOfParams struct {
+	PatientID pgtype.UUID
+	UserID    pgtype.UUID
+}
+
+// Whether the user is on the patient's care team or has recorded a visit for them.
+func (q *Queries) IsInCareOf(ctx context.Context, arg IsInCareOfParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isInCareOf, arg.PatientID, arg.UserID)
+	var in_care bool
+	err := row.Scan(&in_care)
+	return in_care, err
+}
+
+const listCareTeamMembers = `-- name: ListCareTeamMembers :many
+SELECT ct.patient_id, ct.user_id, ct.assigned_by_user_id, ct.assigned_at, u.username, u.first_name, u.last_name, u.role
+FROM care_team_members ct
+JOIN users u ON u.id = ct.user_id
+WHERE ct.patient_id = $1
+ORDER BY ct.assigned_at
+`
+
+type ListCareTeamMembersRow struct {
+	PatientID        pgtype.UUID
+	UserID           pgtype.UUID
+	AssignedByUserID pgtype.UUID
+	AssignedAt       pgtype.Timestamptz
+	Username         string
+	FirstName        pgtype.Text
+	LastName         pgtype.Text
+	Role             UserRole
+}
+
+func (q *Queries) ListCareTeamMembers(ctx context.Context, patientID pgtype.UUID) ([]ListCareTeamMembersRow, error) {
+	rows, err := q.db.Query(ctx, listCareTeamMembers, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCareTeamMembersRow
+	for rows.Next() {
+		var i ListCareTeamMembersRow
+		if err := rows.Scan(
+			&i.PatientID,
+			&i.UserID,
+			&i.AssignedByUserID,
+			&i.AssignedAt,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeCareTeamMember = `-- name: RemoveCareTeamMember :one
+DELETE FROM care_team_members
+WHERE patient_id = $1 AND user_id = $2
+RETURNING patient_id, user_id, assigned_by_user_id, assigned_at
+`
+
+type RemoveCareTeamMemberParams struct {
+	PatientID pgtype.UUID
+	UserID    pgtype.UUID
+}
+
+func (q *Queries) RemoveCareTeamMember(ctx context.Context, arg RemoveCareTeamMemberParams) (CareTeamMember, error) {
+	row := q.db.QueryRow(ctx, removeCareTeamMember, arg.PatientID, arg.UserID)
+	var i CareTeamMember
+	err := row.Scan(
+		&i.PatientID,
+		&i.UserID,
+		&i.AssignedByUserID,
+		&i.AssignedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: consent.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createConsentRecord = `-- name: CreateConsentRecord :one
+INSERT INTO consent_records (
+    patient_id, consent_type, text_version, signed_at, witnessed_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at
+`
+
+type CreateConsentRecordParams struct {
+	PatientID         pgtype.UUID
+	ConsentType       ConsentType
+	TextVersion       string
+	SignedAt          pgtype.Timestamptz
+	WitnessedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateConsentRecord(ctx context.Context, arg CreateConsentRecordParams) (ConsentRecord, error) {
+	row := q.db.QueryRow(ctx, createConsentRecord,
+		arg.PatientID,
+		arg.ConsentType,
+		arg.TextVersion,
+		arg.SignedAt,
+		arg.WitnessedByUserID,
+	)
+	var i ConsentRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.ConsentType,
+		&i.TextVersion,
+		&i.SignedAt,
+		&i.WitnessedByUserID,
+		&i.WithdrawnAt,
+		&i.WithdrawnByUserID,
+		&i.WithdrawalReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getConsentRecordByID = `-- name: GetConsentRecordByID :one
+SELECT id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at FROM consent_records
+WHERE id = $1 AND patient_id = $2
+`
+
+type GetConsentRecordByIDParams struct {
+	ID        pgtype.UUID
+	PatientID pgtype.UUID
+}
+
+func (q *Queries) GetConsentRecordByID(ctx context.Context, arg GetConsentRecordByIDParams) (ConsentRecord, error) {
+	row := q.db.QueryRow(ctx, getConsentRecordByID, arg.ID, arg.PatientID)
+	var i ConsentRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.ConsentType,
+		&i.TextVersion,
+		&i.SignedAt,
+		&i.WitnessedByUserID,
+		&i.WithdrawnAt,
+		&i.WithdrawnByUserID,
+		&i.WithdrawalReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestConsentRecord = `-- name: GetLatestConsentRecord :one
+SELECT id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at FROM consent_records
+WHERE patient_id = $1 AND consent_type = $2
+ORDER BY signed_at DESC, created_at DESC
+LIMIT 1
+`
+
+type GetLatestConsentRecordParams struct {
+	PatientID   pgtype.UUID
+	ConsentType ConsentType
+}
+
+// The most recent record of the type, in force or not.
+func (q *Queries) GetLatestConsentRecord(ctx context.Context, arg GetLatestConsentRecordParams) (ConsentRecord, error) {
+	row := q.db.QueryRow(ctx, getLatestConsentRecord, arg.PatientID, arg.ConsentType)
+	var i ConsentRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.ConsentType,
+		&i.TextVersion,
+		&i.SignedAt,
+		&i.WitnessedByUserID,
+		&i.WithdrawnAt,
+		&i.WithdrawnByUserID,
+		&i.WithdrawalReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listConsentRecordsByPatientID = `-- name: ListConsentRecordsByPatientID :many
+SELECT id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at FROM consent_records
+WHERE patient_id = $1
+ORDER BY signed_at DESC, created_at DESC
+`
+
+func (q *Queries) ListConsentRecordsByPatientID(ctx context.Context, patientID pgtype.UUID) ([]ConsentRecord, error) {
+	rows, err := q.db.Query(ctx, listConsentRecordsByPatientID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ConsentRecord
+	for rows.Next() {
+		var i ConsentRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.ConsentType,
+			&i.TextVersion,
+			&i.SignedAt,
+			&i.WitnessedByUserID,
+			&i.WithdrawnAt,
+			&i.WithdrawnByUserID,
+			&i.WithdrawalReason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const withdrawActiveConsent = `-- name: WithdrawActiveConsent :many
+UPDATE consent_records
+SET
+    withdrawn_at = $1,
+    withdrawn_by_user_id = $2,
+    withdrawal_reason = $3
+WHERE patient_id = $4 AND consent_type = $5 AND withdrawn_at IS NULL
+RETURNING id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at
+`
+
+type WithdrawActiveConsentParams struct {
+	WithdrawnAt       pgtype.Timestamptz
+	WithdrawnByUserID pgtype.UUID
+	WithdrawalReason  pgtype.Text
+	PatientID         pgtype.UUID
+	ConsentType       ConsentType
+}
+
+// Withdraws the record in force, if any, before a new one is signed.
+func (q *Queries) WithdrawActiveConsent(ctx context.Context, arg WithdrawActiveConsentParams) ([]ConsentRecord, error) {
+	rows, err := q.db.Query(ctx, withdrawActiveConsent,
+		arg.WithdrawnAt,
+		arg.WithdrawnByUserID,
+		arg.WithdrawalReason,
+		arg.PatientID,
+		arg.ConsentType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ConsentRecord
+	for rows.Next() {
+		var i ConsentRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.ConsentType,
+			&i.TextVersion,
+			&i.SignedAt,
+			&i.WitnessedByUserID,
+			&i.WithdrawnAt,
+			&i.WithdrawnByUserID,
+			&i.WithdrawalReason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const withdrawConsentRecord = `-- name: WithdrawConsentRecord :one
+UPDATE consent_records
+SET
+    withdrawn_at = $1,
+    withdrawn_by_user_id = $2,
+    withdrawal_reason = $3
+WHERE id = $4 AND withdrawn_at IS NULL
+RETURNING id, patient_id, consent_type, text_version, signed_at, witnessed_by_user_id, withdrawn_at, withdrawn_by_user_id, withdrawal_reason, created_at
+`
+
+type WithdrawConsentRecordParams struct {
+	WithdrawnAt       pgtype.Timestamptz
+	WithdrawnByUserID pgtype.UUID
+	WithdrawalReason  pgtype.Text
+	ID                pgtype.UUID
+}
+
+func (q *Queries) WithdrawConsentRecord(ctx context.Context, arg WithdrawConsentRecordParams) (ConsentRecord, error) {
+	row := q.db.QueryRow(ctx, withdrawConsentRecord,
+		arg.WithdrawnAt,
+		arg.WithdrawnByUserID,
+		arg.WithdrawalReason,
+		arg.ID,
+	)
+	var i ConsentRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.ConsentType,
+		&i.TextVersion,
+		&i.SignedAt,
+		&i.WitnessedByUserID,
+		&i.WithdrawnAt,
+		&i.WithdrawnByUserID,
+		&i.WithdrawalReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
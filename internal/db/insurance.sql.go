@@ -0,0 +1,587 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: insurance.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countInsuranceClaimsByStatus = `-- name: CountInsuranceClaimsByStatus :one
+SELECT COUNT(*) FROM insurance_claims
+WHERE status = $1
+`
+
+func (q *Queries) CountInsuranceClaimsByStatus(ctx context.Context, status ClaimStatus) (int64, error) {
+	row := q.db.QueryRow(ctx, countInsuranceClaimsByStatus, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createClaimStatusHistory = `-- name: CreateClaimStatusHistory :one
+INSERT INTO claim_status_history (
+    claim_id, status, note, changed_by_user_id
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, claim_id, status, note, changed_by_user_id, changed_at
+`
+
+type CreateClaimStatusHistoryParams struct {
+	ClaimID         pgtype.UUID
+	Status          ClaimStatus
+	Note            pgtype.Text
+	ChangedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateClaimStatusHistory(ctx context.Context, arg CreateClaimStatusHistoryParams) (ClaimStatusHistory, error) {
+	row := q.db.QueryRow(ctx, createClaimStatusHistory,
+		arg.ClaimID,
+		arg.Status,
+		arg.Note,
+		arg.ChangedByUserID,
+	)
+	var i ClaimStatusHistory
+	err := row.Scan(
+		&i.ID,
+		&i.ClaimID,
+		&i.Status,
+		&i.Note,
+		&i.ChangedByUserID,
+		&i.ChangedAt,
+	)
+	return i, err
+}
+
+const createEligibilityCheck = `-- name: CreateEligibilityCheck :one
+INSERT INTO eligibility_checks (
+    policy_id, status, message, payer_reference, checked_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, policy_id, status, message, payer_reference, checked_by_user_id, checked_at
+`
+
+type CreateEligibilityCheckParams struct {
+	PolicyID        pgtype.UUID
+	Status          EligibilityStatus
+	Message         pgtype.Text
+	PayerReference  pgtype.Text
+	CheckedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateEligibilityCheck(ctx context.Context, arg CreateEligibilityCheckParams) (EligibilityCheck, error) {
+	row := q.db.QueryRow(ctx, createEligibilityCheck,
+		arg.PolicyID,
+		arg.Status,
+		arg.Message,
+		arg.PayerReference,
+		arg.CheckedByUserID,
+	)
+	var i EligibilityCheck
+	err := row.Scan(
+		&i.ID,
+		&i.PolicyID,
+		&i.Status,
+		&i.Message,
+		&i.PayerReference,
+		&i.CheckedByUserID,
+		&i.CheckedAt,
+	)
+	return i, err
+}
+
+const createInsuranceClaim = `-- name: CreateInsuranceClaim :one
+INSERT INTO insurance_claims (
+    invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor,
+    payer_reference, submitted_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at
+`
+
+type CreateInsuranceClaimParams struct {
+	InvoiceID         pgtype.UUID
+	PolicyID          pgtype.UUID
+	PatientID         pgtype.UUID
+	DiagnosisCodes    []string
+	ClaimedMinor      int64
+	PayerReference    pgtype.Text
+	SubmittedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateInsuranceClaim(ctx context.Context, arg CreateInsuranceClaimParams) (InsuranceClaim, error) {
+	row := q.db.QueryRow(ctx, createInsuranceClaim,
+		arg.InvoiceID,
+		arg.PolicyID,
+		arg.PatientID,
+		arg.DiagnosisCodes,
+		arg.ClaimedMinor,
+		arg.PayerReference,
+		arg.SubmittedByUserID,
+	)
+	var i InsuranceClaim
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PolicyID,
+		&i.PatientID,
+		&i.DiagnosisCodes,
+		&i.ClaimedMinor,
+		&i.PaidMinor,
+		&i.Status,
+		&i.PayerReference,
+		&i.RejectionReason,
+		&i.SubmittedByUserID,
+		&i.SubmittedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SentToPayerAt,
+	)
+	return i, err
+}
+
+const createInsurancePolicy = `-- name: CreateInsurancePolicy :one
+INSERT INTO insurance_policies (
+    patient_id, payer_code, payer_name, plan_name, member_id,
+    group_number, valid_from, valid_to, priority, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, patient_id, payer_code, payer_name, plan_name, member_id, group_number, valid_from, valid_to, priority, is_active, eligibility_status, eligibility_checked_at, created_by_user_id, created_at, updated_at
+`
+
+type CreateInsurancePolicyParams struct {
+	PatientID       pgtype.UUID
+	PayerCode       string
+	PayerName       string
+	PlanName        pgtype.Text
+	MemberID        string
+	GroupNumber     pgtype.Text
+	ValidFrom       pgtype.Date
+	ValidTo         pgtype.Date
+	Priority        PolicyPriority
+	CreatedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreateInsurancePolicy(ctx context.Context, arg CreateInsurancePolicyParams) (InsurancePolicy, error) {
+	row := q.db.QueryRow(ctx, createInsurancePolicy,
+		arg.PatientID,
+		arg.PayerCode,
+		arg.PayerName,
+		arg.PlanName,
+		arg.MemberID,
+		arg.GroupNumber,
+		arg.ValidFrom,
+		arg.ValidTo,
+		arg.Priority,
+		arg.CreatedByUserID,
+	)
+	var i InsurancePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.PayerCode,
+		&i.PayerName,
+		&i.PlanName,
+		&i.MemberID,
+		&i.GroupNumber,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Priority,
+		&i.IsActive,
+		&i.EligibilityStatus,
+		&i.EligibilityCheckedAt,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deactivateInsurancePolicy = `-- name: DeactivateInsurancePolicy :one
+UPDATE insurance_policies
+SET is_active = FALSE
+WHERE id = $1
+RETURNING id, patient_id, payer_code, payer_name, plan_name, member_id, group_number, valid_from, valid_to, priority, is_active, eligibility_status, eligibility_checked_at, created_by_user_id, created_at, updated_at
+`
+
+func (q *Queries) DeactivateInsurancePolicy(ctx context.Context, id pgtype.UUID) (InsurancePolicy, error) {
+	row := q.db.QueryRow(ctx, deactivateInsurancePolicy, id)
+	var i InsurancePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.PayerCode,
+		&i.PayerName,
+		&i.PlanName,
+		&i.MemberID,
+		&i.GroupNumber,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Priority,
+		&i.IsActive,
+		&i.EligibilityStatus,
+		&i.EligibilityCheckedAt,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInsuranceClaimByID = `-- name: GetInsuranceClaimByID :one
+SELECT id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at FROM insurance_claims
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetInsuranceClaimByID(ctx context.Context, id pgtype.UUID) (InsuranceClaim, error) {
+	row := q.db.QueryRow(ctx, getInsuranceClaimByID, id)
+	var i InsuranceClaim
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PolicyID,
+		&i.PatientID,
+		&i.DiagnosisCodes,
+		&i.ClaimedMinor,
+		&i.PaidMinor,
+		&i.Status,
+		&i.PayerReference,
+		&i.RejectionReason,
+		&i.SubmittedByUserID,
+		&i.SubmittedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SentToPayerAt,
+	)
+	return i, err
+}
+
+const getInsurancePolicyByID = `-- name: GetInsurancePolicyByID :one
+SELECT id, patient_id, payer_code, payer_name, plan_name, member_id, group_number, valid_from, valid_to, priority, is_active, eligibility_status, eligibility_checked_at, created_by_user_id, created_at, updated_at FROM insurance_policies
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetInsurancePolicyByID(ctx context.Context, id pgtype.UUID) (InsurancePolicy, error) {
+	row := q.db.QueryRow(ctx, getInsurancePolicyByID, id)
+	var i InsurancePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.PayerCode,
+		&i.PayerName,
+		&i.PlanName,
+		&i.MemberID,
+		&i.GroupNumber,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Priority,
+		&i.IsActive,
+		&i.EligibilityStatus,
+		&i.EligibilityCheckedAt,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listClaimStatusHistory = `-- name: ListClaimStatusHistory :many
+SELECT id, claim_id, status, note, changed_by_user_id, changed_at FROM claim_status_history
+WHERE claim_id = $1
+ORDER BY changed_at
+`
+
+func (q *Queries) ListClaimStatusHistory(ctx context.Context, claimID pgtype.UUID) ([]ClaimStatusHistory, error) {
+	rows, err := q.db.Query(ctx, listClaimStatusHistory, claimID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimStatusHistory
+	for rows.Next() {
+		var i ClaimStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.ClaimID,
+			&i.Status,
+			&i.Note,
+			&i.ChangedByUserID,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInsuranceClaimsByInvoiceID = `-- name: ListInsuranceClaimsByInvoiceID :many
+SELECT id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at FROM insurance_claims
+WHERE invoice_id = $1
+ORDER BY submitted_at
+`
+
+func (q *Queries) ListInsuranceClaimsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]InsuranceClaim, error) {
+	rows, err := q.db.Query(ctx, listInsuranceClaimsByInvoiceID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsuranceClaim
+	for rows.Next() {
+		var i InsuranceClaim
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.PolicyID,
+			&i.PatientID,
+			&i.DiagnosisCodes,
+			&i.ClaimedMinor,
+			&i.PaidMinor,
+			&i.Status,
+			&i.PayerReference,
+			&i.RejectionReason,
+			&i.SubmittedByUserID,
+			&i.SubmittedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SentToPayerAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInsuranceClaimsByStatus = `-- name: ListInsuranceClaimsByStatus :many
+SELECT id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at FROM insurance_claims
+WHERE status = $1
+ORDER BY submitted_at
+LIMIT $2
+OFFSET $3
+`
+
+type ListInsuranceClaimsByStatusParams struct {
+	Status ClaimStatus
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListInsuranceClaimsByStatus(ctx context.Context, arg ListInsuranceClaimsByStatusParams) ([]InsuranceClaim, error) {
+	rows, err := q.db.Query(ctx, listInsuranceClaimsByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsuranceClaim
+	for rows.Next() {
+		var i InsuranceClaim
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.PolicyID,
+			&i.PatientID,
+			&i.DiagnosisCodes,
+			&i.ClaimedMinor,
+			&i.PaidMinor,
+			&i.Status,
+			&i.PayerReference,
+			&i.RejectionReason,
+			&i.SubmittedByUserID,
+			&i.SubmittedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SentToPayerAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInsurancePoliciesByPatientID = `-- name: ListInsurancePoliciesByPatientID :many
+SELECT id, patient_id, payer_code, payer_name, plan_name, member_id, group_number, valid_from, valid_to, priority, is_active, eligibility_status, eligibility_checked_at, created_by_user_id, created_at, updated_at FROM insurance_policies
+WHERE patient_id = $1
+ORDER BY is_active DESC, priority, valid_from DESC
+`
+
+func (q *Queries) ListInsurancePoliciesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]InsurancePolicy, error) {
+	rows, err := q.db.Query(ctx, listInsurancePoliciesByPatientID, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsurancePolicy
+	for rows.Next() {
+		var i InsurancePolicy
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.PayerCode,
+			&i.PayerName,
+			&i.PlanName,
+			&i.MemberID,
+			&i.GroupNumber,
+			&i.ValidFrom,
+			&i.ValidTo,
+			&i.Priority,
+			&i.IsActive,
+			&i.EligibilityStatus,
+			&i.EligibilityCheckedAt,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markInsuranceClaimSent = `-- name: MarkInsuranceClaimSent :one
+UPDATE insurance_claims
+SET sent_to_payer_at = NOW(),
+    payer_reference = COALESCE($1, payer_reference)
+WHERE id = $2 AND sent_to_payer_at IS NULL
+RETURNING id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at
+`
+
+type MarkInsuranceClaimSentParams struct {
+	PayerReference pgtype.Text
+	ID             pgtype.UUID
+}
+
+// Records the payer's acknowledgement; a claim keeps the first one.
+func (q *Queries) MarkInsuranceClaimSent(ctx context.Context, arg MarkInsuranceClaimSentParams) (InsuranceClaim, error) {
+	row := q.db.QueryRow(ctx, markInsuranceClaimSent, arg.PayerReference, arg.ID)
+	var i InsuranceClaim
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PolicyID,
+		&i.PatientID,
+		&i.DiagnosisCodes,
+		&i.ClaimedMinor,
+		&i.PaidMinor,
+		&i.Status,
+		&i.PayerReference,
+		&i.RejectionReason,
+		&i.SubmittedByUserID,
+		&i.SubmittedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SentToPayerAt,
+	)
+	return i, err
+}
+
+const updateInsuranceClaimStatus = `-- name: UpdateInsuranceClaimStatus :one
+UPDATE insurance_claims
+SET status = $1,
+    rejection_reason = $2,
+    paid_minor = COALESCE($3, paid_minor),
+    payer_reference = COALESCE($4, payer_reference)
+WHERE id = $5 AND status = $6
+RETURNING id, invoice_id, policy_id, patient_id, diagnosis_codes, claimed_minor, paid_minor, status, payer_reference, rejection_reason, submitted_by_user_id, submitted_at, created_at, updated_at, sent_to_payer_at
+`
+
+type UpdateInsuranceClaimStatusParams struct {
+	Status          ClaimStatus
+	RejectionReason pgtype.Text
+	PaidMinor       pgtype.Int8
+	PayerReference  pgtype.Text
+	ID              pgtype.UUID
+	FromStatus      ClaimStatus
+}
+
+// Only applies when the claim is still in from_status, so concurrent updates cannot both win.
+func (q *Queries) UpdateInsuranceClaimStatus(ctx context.Context, arg UpdateInsuranceClaimStatusParams) (InsuranceClaim, error) {
+	row := q.db.QueryRow(ctx, updateInsuranceClaimStatus,
+		arg.Status,
+		arg.RejectionReason,
+		arg.PaidMinor,
+		arg.PayerReference,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i InsuranceClaim
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.PolicyID,
+		&i.PatientID,
+		&i.DiagnosisCodes,
+		&i.ClaimedMinor,
+		&i.PaidMinor,
+		&i.Status,
+		&i.PayerReference,
+		&i.RejectionReason,
+		&i.SubmittedByUserID,
+		&i.SubmittedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SentToPayerAt,
+	)
+	return i, err
+}
+
+const updatePolicyEligibility = `-- name: UpdatePolicyEligibility :one
+UPDATE insurance_policies
+SET eligibility_status = $2, eligibility_checked_at = NOW()
+WHERE id = $1
+RETURNING id, patient_id, payer_code, payer_name, plan_name, member_id, group_number, valid_from, valid_to, priority, is_active, eligibility_status, eligibility_checked_at, created_by_user_id, created_at, updated_at
+`
+
+type UpdatePolicyEligibilityParams struct {
+	ID                pgtype.UUID
+	EligibilityStatus EligibilityStatus
+}
+
+func (q *Queries) UpdatePolicyEligibility(ctx context.Context, arg UpdatePolicyEligibilityParams) (InsurancePolicy, error) {
+	row := q.db.QueryRow(ctx, updatePolicyEligibility, arg.ID, arg.EligibilityStatus)
+	var i InsurancePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.PayerCode,
+		&i.PayerName,
+		&i.PlanName,
+		&i.MemberID,
+		&i.GroupNumber,
+		&i.ValidFrom,
+		&i.ValidTo,
+		&i.Priority,
+		&i.IsActive,
+		&i.EligibilityStatus,
+		&i.EligibilityCheckedAt,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
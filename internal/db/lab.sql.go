@@ -0,0 +1,752 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: lab.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countLabTests = `-- name: CountLabTests :one
+SELECT COUNT(*) FROM lab_tests
+WHERE is_active = TRUE
+`
+
+func (q *Queries) CountLabTests(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countLabTests)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLabAnalyte = `-- name: CreateLabAnalyte :one
+INSERT INTO lab_analytes (
+    test_id, code, name, unit, reference_low, reference_high,
+    critical_low, critical_high, display_order
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, test_id, code, name, unit, reference_low, reference_high, critical_low, critical_high, display_order, created_at, updated_at
+`
+
+type CreateLabAnalyteParams struct {
+	TestID        pgtype.UUID
+	Code          string
+	Name          string
+	Unit          pgtype.Text
+	ReferenceLow  pgtype.Float8
+	ReferenceHigh pgtype.Float8
+	CriticalLow   pgtype.Float8
+	CriticalHigh  pgtype.Float8
+	DisplayOrder  int32
+}
+
+func (q *Queries) CreateLabAnalyte(ctx context.Context, arg CreateLabAnalyteParams) (LabAnalyte, error) {
+	row := q.db.QueryRow(ctx, createLabAnalyte,
+		arg.TestID,
+		arg.Code,
+		arg.Name,
+		arg.Unit,
+		arg.ReferenceLow,
+		arg.ReferenceHigh,
+		arg.CriticalLow,
+		arg.CriticalHigh,
+		arg.DisplayOrder,
+	)
+	var i LabAnalyte
+	err := row.Scan(
+		&i.ID,
+		&i.TestID,
+		&i.Code,
+		&i.Name,
+		&i.Unit,
+		&i.ReferenceLow,
+		&i.ReferenceHigh,
+		&i.CriticalLow,
+		&i.CriticalHigh,
+		&i.DisplayOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createLabOrder = `-- name: CreateLabOrder :one
+INSERT INTO lab_orders (
+    visit_id, test_id, ordered_by_user_id, priority, clinical_notes
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at
+`
+
+type CreateLabOrderParams struct {
+	VisitID         pgtype.UUID
+	TestID          pgtype.UUID
+	OrderedByUserID pgtype.UUID
+	Priority        LabOrderPriority
+	ClinicalNotes   pgtype.Text
+}
+
+func (q *Queries) CreateLabOrder(ctx context.Context, arg CreateLabOrderParams) (LabOrder, error) {
+	row := q.db.QueryRow(ctx, createLabOrder,
+		arg.VisitID,
+		arg.TestID,
+		arg.OrderedByUserID,
+		arg.Priority,
+		arg.ClinicalNotes,
+	)
+	var i LabOrder
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.TestID,
+		&i.OrderedByUserID,
+		&i.Priority,
+		&i.Status,
+		&i.ClinicalNotes,
+		&i.OrderedAt,
+		&i.CollectedAt,
+		&i.CollectedByUserID,
+		&i.ResultedAt,
+		&i.ResultedByUserID,
+		&i.VerifiedAt,
+		&i.VerifiedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createLabTest = `-- name: CreateLabTest :one
+INSERT INTO lab_tests (
+    code, name, specimen_type, description, charge_code
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, code, name, specimen_type, description, is_active, created_at, updated_at, charge_code
+`
+
+type CreateLabTestParams struct {
+	Code         string
+	Name         string
+	SpecimenType LabSpecimenType
+	Description  pgtype.Text
+	ChargeCode   pgtype.Text
+}
+
+func (q *Queries) CreateLabTest(ctx context.Context, arg CreateLabTestParams) (LabTest, error) {
+	row := q.db.QueryRow(ctx, createLabTest,
+		arg.Code,
+		arg.Name,
+		arg.SpecimenType,
+		arg.Description,
+		arg.ChargeCode,
+	)
+	var i LabTest
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.SpecimenType,
+		&i.Description,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChargeCode,
+	)
+	return i, err
+}
+
+const getLabOrderByID = `-- name: GetLabOrderByID :one
+SELECT id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at FROM lab_orders
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetLabOrderByID(ctx context.Context, id pgtype.UUID) (LabOrder, error) {
+	row := q.db.QueryRow(ctx, getLabOrderByID, id)
+	var i LabOrder
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.TestID,
+		&i.OrderedByUserID,
+		&i.Priority,
+		&i.Status,
+		&i.ClinicalNotes,
+		&i.OrderedAt,
+		&i.CollectedAt,
+		&i.CollectedByUserID,
+		&i.ResultedAt,
+		&i.ResultedByUserID,
+		&i.VerifiedAt,
+		&i.VerifiedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLabTestByCode = `-- name: GetLabTestByCode :one
+SELECT id, code, name, specimen_type, description, is_active, created_at, updated_at, charge_code FROM lab_tests
+WHERE code = $1
+LIMIT 1
+`
+
+func (q *Queries) GetLabTestByCode(ctx context.Context, code string) (LabTest, error) {
+	row := q.db.QueryRow(ctx, getLabTestByCode, code)
+	var i LabTest
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.SpecimenType,
+		&i.Description,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChargeCode,
+	)
+	return i, err
+}
+
+const getLabTestByID = `-- name: GetLabTestByID :one
+SELECT id, code, name, specimen_type, description, is_active, created_at, updated_at, charge_code FROM lab_tests
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetLabTestByID(ctx context.Context, id pgtype.UUID) (LabTest, error) {
+	row := q.db.QueryRow(ctx, getLabTestByID, id)
+	var i LabTest
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.SpecimenType,
+		&i.Description,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChargeCode,
+	)
+	return i, err
+}
+
+const listCumulativeLabResultsByPatientID = `-- name: ListCumulativeLabResultsByPatientID :many
+SELECT lr.id, lr.order_id, lr.analyte_id, lr.value_numeric, lr.value_text, lr.unit, lr.reference_low, lr.reference_high, lr.flag, lr.comment, lr.entered_by_user_id, lr.created_at, lr.updated_at, la.code AS analyte_code, la.name AS analyte_name,
+    lt.code AS test_code, lo.visit_id, lo.status AS order_status,
+    COALESCE(lo.collected_at, lo.ordered_at)::timestamptz AS observed_at
+FROM lab_results lr
+JOIN lab_orders lo ON lr.order_id = lo.id
+JOIN lab_analytes la ON lr.analyte_id = la.id
+JOIN lab_tests lt ON lo.test_id = lt.id
+JOIN patient_visits pv ON lo.visit_id = pv.id
+WHERE pv.patient_id = $1
+  AND ($2::text IS NULL OR la.code = $2::text)
+ORDER BY la.code, observed_at
+LIMIT $4
+OFFSET $3
+`
+
+type ListCumulativeLabResultsByPatientIDParams struct {
+	PatientID   pgtype.UUID
+	AnalyteCode pgtype.Text
+	RowOffset   int32
+	RowLimit    int32
+}
+
+type ListCumulativeLabResultsByPatientIDRow struct {
+	ID              pgtype.UUID
+	OrderID         pgtype.UUID
+	AnalyteID       pgtype.UUID
+	ValueNumeric    pgtype.Float8
+	ValueText       pgtype.Text
+	Unit            pgtype.Text
+	ReferenceLow    pgtype.Float8
+	ReferenceHigh   pgtype.Float8
+	Flag            NullLabResultFlag
+	Comment         pgtype.Text
+	EnteredByUserID pgtype.UUID
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	AnalyteCode     string
+	AnalyteName     string
+	TestCode        string
+	VisitID         pgtype.UUID
+	OrderStatus     LabOrderStatus
+	ObservedAt      pgtype.Timestamptz
+}
+
+// Cumulative view across all visits of a patient, oldest first for trending.
+func (q *Queries) ListCumulativeLabResultsByPatientID(ctx context.Context, arg ListCumulativeLabResultsByPatientIDParams) ([]ListCumulativeLabResultsByPatientIDRow, error) {
+	rows, err := q.db.Query(ctx, listCumulativeLabResultsByPatientID,
+		arg.PatientID,
+		arg.AnalyteCode,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCumulativeLabResultsByPatientIDRow
+	for rows.Next() {
+		var i ListCumulativeLabResultsByPatientIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.AnalyteID,
+			&i.ValueNumeric,
+			&i.ValueText,
+			&i.Unit,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Flag,
+			&i.Comment,
+			&i.EnteredByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AnalyteCode,
+			&i.AnalyteName,
+			&i.TestCode,
+			&i.VisitID,
+			&i.OrderStatus,
+			&i.ObservedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabAnalytesByTestID = `-- name: ListLabAnalytesByTestID :many
+SELECT id, test_id, code, name, unit, reference_low, reference_high, critical_low, critical_high, display_order, created_at, updated_at FROM lab_analytes
+WHERE test_id = $1
+ORDER BY display_order, code
+`
+
+func (q *Queries) ListLabAnalytesByTestID(ctx context.Context, testID pgtype.UUID) ([]LabAnalyte, error) {
+	rows, err := q.db.Query(ctx, listLabAnalytesByTestID, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabAnalyte
+	for rows.Next() {
+		var i LabAnalyte
+		if err := rows.Scan(
+			&i.ID,
+			&i.TestID,
+			&i.Code,
+			&i.Name,
+			&i.Unit,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.CriticalLow,
+			&i.CriticalHigh,
+			&i.DisplayOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabOrdersByStatus = `-- name: ListLabOrdersByStatus :many
+SELECT id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at FROM lab_orders
+WHERE status = $1
+ORDER BY
+    CASE priority WHEN 'stat' THEN 0 WHEN 'urgent' THEN 1 ELSE 2 END,
+    ordered_at
+LIMIT $2
+OFFSET $3
+`
+
+type ListLabOrdersByStatusParams struct {
+	Status LabOrderStatus
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListLabOrdersByStatus(ctx context.Context, arg ListLabOrdersByStatusParams) ([]LabOrder, error) {
+	rows, err := q.db.Query(ctx, listLabOrdersByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabOrder
+	for rows.Next() {
+		var i LabOrder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitID,
+			&i.TestID,
+			&i.OrderedByUserID,
+			&i.Priority,
+			&i.Status,
+			&i.ClinicalNotes,
+			&i.OrderedAt,
+			&i.CollectedAt,
+			&i.CollectedByUserID,
+			&i.ResultedAt,
+			&i.ResultedByUserID,
+			&i.VerifiedAt,
+			&i.VerifiedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabOrdersByVisitID = `-- name: ListLabOrdersByVisitID :many
+SELECT id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at FROM lab_orders
+WHERE visit_id = $1
+ORDER BY ordered_at DESC
+`
+
+func (q *Queries) ListLabOrdersByVisitID(ctx context.Context, visitID pgtype.UUID) ([]LabOrder, error) {
+	rows, err := q.db.Query(ctx, listLabOrdersByVisitID, visitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabOrder
+	for rows.Next() {
+		var i LabOrder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitID,
+			&i.TestID,
+			&i.OrderedByUserID,
+			&i.Priority,
+			&i.Status,
+			&i.ClinicalNotes,
+			&i.OrderedAt,
+			&i.CollectedAt,
+			&i.CollectedByUserID,
+			&i.ResultedAt,
+			&i.ResultedByUserID,
+			&i.VerifiedAt,
+			&i.VerifiedByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabResultsByOrderID = `-- name: ListLabResultsByOrderID :many
+SELECT lr.id, lr.order_id, lr.analyte_id, lr.value_numeric, lr.value_text, lr.unit, lr.reference_low, lr.reference_high, lr.flag, lr.comment, lr.entered_by_user_id, lr.created_at, lr.updated_at, la.code AS analyte_code, la.name AS analyte_name
+FROM lab_results lr
+JOIN lab_analytes la ON lr.analyte_id = la.id
+WHERE lr.order_id = $1
+ORDER BY la.display_order, la.code
+`
+
+type ListLabResultsByOrderIDRow struct {
+	ID              pgtype.UUID
+	OrderID         pgtype.UUID
+	AnalyteID       pgtype.UUID
+	ValueNumeric    pgtype.Float8
+	ValueText       pgtype.Text
+	Unit            pgtype.Text
+	ReferenceLow    pgtype.Float8
+	ReferenceHigh   pgtype.Float8
+	Flag            NullLabResultFlag
+	Comment         pgtype.Text
+	EnteredByUserID pgtype.UUID
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	AnalyteCode     string
+	AnalyteName     string
+}
+
+func (q *Queries) ListLabResultsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]ListLabResultsByOrderIDRow, error) {
+	rows, err := q.db.Query(ctx, listLabResultsByOrderID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLabResultsByOrderIDRow
+	for rows.Next() {
+		var i ListLabResultsByOrderIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.AnalyteID,
+			&i.ValueNumeric,
+			&i.ValueText,
+			&i.Unit,
+			&i.ReferenceLow,
+			&i.ReferenceHigh,
+			&i.Flag,
+			&i.Comment,
+			&i.EnteredByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AnalyteCode,
+			&i.AnalyteName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLabTests = `-- name: ListLabTests :many
+SELECT id, code, name, specimen_type, description, is_active, created_at, updated_at, charge_code FROM lab_tests
+WHERE is_active = TRUE
+ORDER BY code
+LIMIT $1
+OFFSET $2
+`
+
+type ListLabTestsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListLabTests(ctx context.Context, arg ListLabTestsParams) ([]LabTest, error) {
+	rows, err := q.db.Query(ctx, listLabTests, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabTest
+	for rows.Next() {
+		var i LabTest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.SpecimenType,
+			&i.Description,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ChargeCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markLabOrderCollected = `-- name: MarkLabOrderCollected :one
+UPDATE lab_orders
+SET
+    status = 'collected',
+    collected_at = NOW(),
+    collected_by_user_id = $2
+WHERE id = $1 AND status = 'ordered'
+RETURNING id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at
+`
+
+type MarkLabOrderCollectedParams struct {
+	ID                pgtype.UUID
+	CollectedByUserID pgtype.UUID
+}
+
+func (q *Queries) MarkLabOrderCollected(ctx context.Context, arg MarkLabOrderCollectedParams) (LabOrder, error) {
+	row := q.db.QueryRow(ctx, markLabOrderCollected, arg.ID, arg.CollectedByUserID)
+	var i LabOrder
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.TestID,
+		&i.OrderedByUserID,
+		&i.Priority,
+		&i.Status,
+		&i.ClinicalNotes,
+		&i.OrderedAt,
+		&i.CollectedAt,
+		&i.CollectedByUserID,
+		&i.ResultedAt,
+		&i.ResultedByUserID,
+		&i.VerifiedAt,
+		&i.VerifiedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markLabOrderResulted = `-- name: MarkLabOrderResulted :one
+UPDATE lab_orders
+SET
+    status = 'resulted',
+    resulted_at = NOW(),
+    resulted_by_user_id = $2
+WHERE id = $1 AND status IN ('collected', 'resulted')
+RETURNING id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at
+`
+
+type MarkLabOrderResultedParams struct {
+	ID               pgtype.UUID
+	ResultedByUserID pgtype.UUID
+}
+
+func (q *Queries) MarkLabOrderResulted(ctx context.Context, arg MarkLabOrderResultedParams) (LabOrder, error) {
+	row := q.db.QueryRow(ctx, markLabOrderResulted, arg.ID, arg.ResultedByUserID)
+	var i LabOrder
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.TestID,
+		&i.OrderedByUserID,
+		&i.Priority,
+		&i.Status,
+		&i.ClinicalNotes,
+		&i.OrderedAt,
+		&i.CollectedAt,
+		&i.CollectedByUserID,
+		&i.ResultedAt,
+		&i.ResultedByUserID,
+		&i.VerifiedAt,
+		&i.VerifiedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markLabOrderVerified = `-- name: MarkLabOrderVerified :one
+UPDATE lab_orders
+SET
+    status = 'verified',
+    verified_at = NOW(),
+    verified_by_user_id = $2
+WHERE id = $1 AND status = 'resulted'
+RETURNING id, visit_id, test_id, ordered_by_user_id, priority, status, clinical_notes, ordered_at, collected_at, collected_by_user_id, resulted_at, resulted_by_user_id, verified_at, verified_by_user_id, created_at, updated_at
+`
+
+type MarkLabOrderVerifiedParams struct {
+	ID               pgtype.UUID
+	VerifiedByUserID pgtype.UUID
+}
+
+func (q *Queries) MarkLabOrderVerified(ctx context.Context, arg MarkLabOrderVerifiedParams) (LabOrder, error) {
+	row := q.db.QueryRow(ctx, markLabOrderVerified, arg.ID, arg.VerifiedByUserID)
+	var i LabOrder
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.TestID,
+		&i.OrderedByUserID,
+		&i.Priority,
+		&i.Status,
+		&i.ClinicalNotes,
+		&i.OrderedAt,
+		&i.CollectedAt,
+		&i.CollectedByUserID,
+		&i.ResultedAt,
+		&i.ResultedByUserID,
+		&i.VerifiedAt,
+		&i.VerifiedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertLabResult = `-- name: UpsertLabResult :one
+INSERT INTO lab_results (
+    order_id, analyte_id, value_numeric, value_text, unit,
+    reference_low, reference_high, flag, comment, entered_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (order_id, analyte_id) DO UPDATE
+SET
+    value_numeric = EXCLUDED.value_numeric,
+    value_text = EXCLUDED.value_text,
+    unit = EXCLUDED.unit,
+    reference_low = EXCLUDED.reference_low,
+    reference_high = EXCLUDED.reference_high,
+    flag = EXCLUDED.flag,
+    comment = EXCLUDED.comment,
+    entered_by_user_id = EXCLUDED.entered_by_user_id
+RETURNING id, order_id, analyte_id, value_numeric, value_text, unit, reference_low, reference_high, flag, comment, entered_by_user_id, created_at, updated_at
+`
+
+type UpsertLabResultParams struct {
+	OrderID         pgtype.UUID
+	AnalyteID       pgtype.UUID
+	ValueNumeric    pgtype.Float8
+	ValueText       pgtype.Text
+	Unit            pgtype.Text
+	ReferenceLow    pgtype.Float8
+	ReferenceHigh   pgtype.Float8
+	Flag            NullLabResultFlag
+	Comment         pgtype.Text
+	EnteredByUserID pgtype.UUID
+}
+
+func (q *Queries) UpsertLabResult(ctx context.Context, arg UpsertLabResultParams) (LabResult, error) {
+	row := q.db.QueryRow(ctx, upsertLabResult,
+		arg.OrderID,
+		arg.AnalyteID,
+		arg.ValueNumeric,
+		arg.ValueText,
+		arg.Unit,
+		arg.ReferenceLow,
+		arg.ReferenceHigh,
+		arg.Flag,
+		arg.Comment,
+		arg.EnteredByUserID,
+	)
+	var i LabResult
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.AnalyteID,
+		&i.ValueNumeric,
+		&i.ValueText,
+		&i.Unit,
+		&i.ReferenceLow,
+		&i.ReferenceHigh,
+		&i.Flag,
+		&i.Comment,
+		&i.EnteredByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -11,55 +11,761 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AlertStatus string
+
+const (
+	AlertStatusOpen         AlertStatus = "open"
+	AlertStatusAcknowledged AlertStatus = "acknowledged"
+)
+
+func (e *AlertStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AlertStatus(s)
+	case string:
+		*e = AlertStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AlertStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAlertStatus struct {
+	AlertStatus AlertStatus
+	Valid       bool // Valid is true if AlertStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAlertStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AlertStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AlertStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAlertStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AlertStatus), nil
+}
+
+type ChargeSource string
+
+const (
+	ChargeSourceVisit     ChargeSource = "visit"
+	ChargeSourceProcedure ChargeSource = "procedure"
+	ChargeSourceLabOrder  ChargeSource = "lab_order"
+	ChargeSourceManual    ChargeSource = "manual"
+)
+
+func (e *ChargeSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ChargeSource(s)
+	case string:
+		*e = ChargeSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ChargeSource: %T", src)
+	}
+	return nil
+}
+
+type NullChargeSource struct {
+	ChargeSource ChargeSource
+	Valid        bool // Valid is true if ChargeSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullChargeSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.ChargeSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ChargeSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullChargeSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ChargeSource), nil
+}
+
+type ClaimStatus string
+
+const (
+	ClaimStatusSubmitted ClaimStatus = "submitted"
+	ClaimStatusAccepted  ClaimStatus = "accepted"
+	ClaimStatusRejected  ClaimStatus = "rejected"
+	ClaimStatusPaid      ClaimStatus = "paid"
+)
+
+func (e *ClaimStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ClaimStatus(s)
+	case string:
+		*e = ClaimStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ClaimStatus: %T", src)
+	}
+	return nil
+}
+
+type NullClaimStatus struct {
+	ClaimStatus ClaimStatus
+	Valid       bool // Valid is true if ClaimStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullClaimStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ClaimStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ClaimStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullClaimStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ClaimStatus), nil
+}
+
+type ConsentType string
+
+const (
+	ConsentTypeTreatment   ConsentType = "treatment"
+	ConsentTypeDataSharing ConsentType = "data_sharing"
+	ConsentTypeResearch    ConsentType = "research"
+	ConsentTypeSmsContact  ConsentType = "sms_contact"
+)
+
+func (e *ConsentType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ConsentType(s)
+	case string:
+		*e = ConsentType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ConsentType: %T", src)
+	}
+	return nil
+}
+
+type NullConsentType struct {
+	ConsentType ConsentType
+	Valid       bool // Valid is true if ConsentType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullConsentType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ConsentType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ConsentType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullConsentType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ConsentType), nil
+}
+
+type EligibilityStatus string
+
+const (
+	EligibilityStatusEligible   EligibilityStatus = "eligible"
+	EligibilityStatusIneligible EligibilityStatus = "ineligible"
+	EligibilityStatusUnknown    EligibilityStatus = "unknown"
+)
+
+func (e *EligibilityStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EligibilityStatus(s)
+	case string:
+		*e = EligibilityStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EligibilityStatus: %T", src)
+	}
+	return nil
+}
+
+type NullEligibilityStatus struct {
+	EligibilityStatus EligibilityStatus
+	Valid             bool // Valid is true if EligibilityStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEligibilityStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.EligibilityStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EligibilityStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEligibilityStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EligibilityStatus), nil
+}
+
 type GenderEnum string
 
 const (
-	GenderEnumMale           GenderEnum = "male"
-	GenderEnumFemale         GenderEnum = "female"
-	GenderEnumOther          GenderEnum = "other"
-	GenderEnumPreferNotToSay GenderEnum = "prefer_not_to_say"
+	GenderEnumMale           GenderEnum = "male"
+	GenderEnumFemale         GenderEnum = "female"
+	GenderEnumOther          GenderEnum = "other"
+	GenderEnumPreferNotToSay GenderEnum = "prefer_not_to_say"
+)
+
+func (e *GenderEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GenderEnum(s)
+	case string:
+		*e = GenderEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GenderEnum: %T", src)
+	}
+	return nil
+}
+
+type NullGenderEnum struct {
+	GenderEnum GenderEnum
+	Valid      bool // Valid is true if GenderEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGenderEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.GenderEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GenderEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGenderEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GenderEnum), nil
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusIssued        InvoiceStatus = "issued"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+)
+
+func (e *InvoiceStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvoiceStatus(s)
+	case string:
+		*e = InvoiceStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvoiceStatus: %T", src)
+	}
+	return nil
+}
+
+type NullInvoiceStatus struct {
+	InvoiceStatus InvoiceStatus
+	Valid         bool // Valid is true if InvoiceStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvoiceStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvoiceStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvoiceStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvoiceStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvoiceStatus), nil
+}
+
+type LabOrderPriority string
+
+const (
+	LabOrderPriorityRoutine LabOrderPriority = "routine"
+	LabOrderPriorityUrgent  LabOrderPriority = "urgent"
+	LabOrderPriorityStat    LabOrderPriority = "stat"
+)
+
+func (e *LabOrderPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LabOrderPriority(s)
+	case string:
+		*e = LabOrderPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LabOrderPriority: %T", src)
+	}
+	return nil
+}
+
+type NullLabOrderPriority struct {
+	LabOrderPriority LabOrderPriority
+	Valid            bool // Valid is true if LabOrderPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLabOrderPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.LabOrderPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LabOrderPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLabOrderPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LabOrderPriority), nil
+}
+
+type LabOrderStatus string
+
+const (
+	LabOrderStatusOrdered   LabOrderStatus = "ordered"
+	LabOrderStatusCollected LabOrderStatus = "collected"
+	LabOrderStatusResulted  LabOrderStatus = "resulted"
+	LabOrderStatusVerified  LabOrderStatus = "verified"
+)
+
+func (e *LabOrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LabOrderStatus(s)
+	case string:
+		*e = LabOrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LabOrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullLabOrderStatus struct {
+	LabOrderStatus LabOrderStatus
+	Valid          bool // Valid is true if LabOrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLabOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.LabOrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LabOrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLabOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LabOrderStatus), nil
+}
+
+type LabResultFlag string
+
+const (
+	LabResultFlagNormal       LabResultFlag = "normal"
+	LabResultFlagLow          LabResultFlag = "low"
+	LabResultFlagHigh         LabResultFlag = "high"
+	LabResultFlagCriticalLow  LabResultFlag = "critical_low"
+	LabResultFlagCriticalHigh LabResultFlag = "critical_high"
+	LabResultFlagAbnormal     LabResultFlag = "abnormal"
+)
+
+func (e *LabResultFlag) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LabResultFlag(s)
+	case string:
+		*e = LabResultFlag(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LabResultFlag: %T", src)
+	}
+	return nil
+}
+
+type NullLabResultFlag struct {
+	LabResultFlag LabResultFlag
+	Valid         bool // Valid is true if LabResultFlag is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLabResultFlag) Scan(value interface{}) error {
+	if value == nil {
+		ns.LabResultFlag, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LabResultFlag.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLabResultFlag) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LabResultFlag), nil
+}
+
+type LabSpecimenType string
+
+const (
+	LabSpecimenTypeBlood  LabSpecimenType = "blood"
+	LabSpecimenTypeSerum  LabSpecimenType = "serum"
+	LabSpecimenTypePlasma LabSpecimenType = "plasma"
+	LabSpecimenTypeUrine  LabSpecimenType = "urine"
+	LabSpecimenTypeStool  LabSpecimenType = "stool"
+	LabSpecimenTypeSputum LabSpecimenType = "sputum"
+	LabSpecimenTypeCsf    LabSpecimenType = "csf"
+	LabSpecimenTypeSwab   LabSpecimenType = "swab"
+	LabSpecimenTypeTissue LabSpecimenType = "tissue"
+	LabSpecimenTypeOther  LabSpecimenType = "other"
+)
+
+func (e *LabSpecimenType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LabSpecimenType(s)
+	case string:
+		*e = LabSpecimenType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LabSpecimenType: %T", src)
+	}
+	return nil
+}
+
+type NullLabSpecimenType struct {
+	LabSpecimenType LabSpecimenType
+	Valid           bool // Valid is true if LabSpecimenType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLabSpecimenType) Scan(value interface{}) error {
+	if value == nil {
+		ns.LabSpecimenType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LabSpecimenType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLabSpecimenType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LabSpecimenType), nil
+}
+
+type ObservationSource string
+
+const (
+	ObservationSourceVitalSign ObservationSource = "vital_sign"
+	ObservationSourceLabResult ObservationSource = "lab_result"
 )
 
-func (e *GenderEnum) Scan(src interface{}) error {
+func (e *ObservationSource) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = GenderEnum(s)
+		*e = ObservationSource(s)
 	case string:
-		*e = GenderEnum(s)
+		*e = ObservationSource(s)
 	default:
-		return fmt.Errorf("unsupported scan type for GenderEnum: %T", src)
+		return fmt.Errorf("unsupported scan type for ObservationSource: %T", src)
 	}
 	return nil
 }
 
-type NullGenderEnum struct {
-	GenderEnum GenderEnum
-	Valid      bool // Valid is true if GenderEnum is not NULL
+type NullObservationSource struct {
+	ObservationSource ObservationSource
+	Valid             bool // Valid is true if ObservationSource is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullGenderEnum) Scan(value interface{}) error {
+func (ns *NullObservationSource) Scan(value interface{}) error {
 	if value == nil {
-		ns.GenderEnum, ns.Valid = "", false
+		ns.ObservationSource, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.GenderEnum.Scan(value)
+	return ns.ObservationSource.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullGenderEnum) Value() (driver.Value, error) {
+func (ns NullObservationSource) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.GenderEnum), nil
+	return string(ns.ObservationSource), nil
+}
+
+type PatientExportStatus string
+
+const (
+	PatientExportStatusPending   PatientExportStatus = "pending"
+	PatientExportStatusRunning   PatientExportStatus = "running"
+	PatientExportStatusCompleted PatientExportStatus = "completed"
+	PatientExportStatusFailed    PatientExportStatus = "failed"
+	PatientExportStatusExpired   PatientExportStatus = "expired"
+)
+
+func (e *PatientExportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PatientExportStatus(s)
+	case string:
+		*e = PatientExportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PatientExportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPatientExportStatus struct {
+	PatientExportStatus PatientExportStatus
+	Valid               bool // Valid is true if PatientExportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPatientExportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PatientExportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PatientExportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPatientExportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PatientExportStatus), nil
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash      PaymentMethod = "cash"
+	PaymentMethodCard      PaymentMethod = "card"
+	PaymentMethodInsurance PaymentMethod = "insurance"
+)
+
+func (e *PaymentMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PaymentMethod(s)
+	case string:
+		*e = PaymentMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PaymentMethod: %T", src)
+	}
+	return nil
+}
+
+type NullPaymentMethod struct {
+	PaymentMethod PaymentMethod
+	Valid         bool // Valid is true if PaymentMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentMethod), nil
+}
+
+type PolicyPriority string
+
+const (
+	PolicyPriorityPrimary   PolicyPriority = "primary"
+	PolicyPrioritySecondary PolicyPriority = "secondary"
+)
+
+func (e *PolicyPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PolicyPriority(s)
+	case string:
+		*e = PolicyPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PolicyPriority: %T", src)
+	}
+	return nil
+}
+
+type NullPolicyPriority struct {
+	PolicyPriority PolicyPriority
+	Valid          bool // Valid is true if PolicyPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPolicyPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.PolicyPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PolicyPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPolicyPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PolicyPriority), nil
+}
+
+type PseudonymizationStatus string
+
+const (
+	PseudonymizationStatusPending  PseudonymizationStatus = "pending"
+	PseudonymizationStatusApproved PseudonymizationStatus = "approved"
+	PseudonymizationStatusRejected PseudonymizationStatus = "rejected"
+)
+
+func (e *PseudonymizationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PseudonymizationStatus(s)
+	case string:
+		*e = PseudonymizationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PseudonymizationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPseudonymizationStatus struct {
+	PseudonymizationStatus PseudonymizationStatus
+	Valid                  bool // Valid is true if PseudonymizationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPseudonymizationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PseudonymizationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PseudonymizationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPseudonymizationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PseudonymizationStatus), nil
+}
+
+type StockMovementType string
+
+const (
+	StockMovementTypeReceipt    StockMovementType = "receipt"
+	StockMovementTypeDispense   StockMovementType = "dispense"
+	StockMovementTypeReturn     StockMovementType = "return"
+	StockMovementTypeAdjustment StockMovementType = "adjustment"
+)
+
+func (e *StockMovementType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StockMovementType(s)
+	case string:
+		*e = StockMovementType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StockMovementType: %T", src)
+	}
+	return nil
+}
+
+type NullStockMovementType struct {
+	StockMovementType StockMovementType
+	Valid             bool // Valid is true if StockMovementType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStockMovementType) Scan(value interface{}) error {
+	if value == nil {
+		ns.StockMovementType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StockMovementType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStockMovementType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StockMovementType), nil
 }
 
 type UserRole string
 
 const (
-	UserRoleReceptionist UserRole = "receptionist"
-	UserRoleDoctor       UserRole = "doctor"
+	UserRoleReceptionist   UserRole = "receptionist"
+	UserRoleDoctor         UserRole = "doctor"
+	UserRoleLabTechnician  UserRole = "lab_technician"
+	UserRolePharmacist     UserRole = "pharmacist"
+	UserRoleAdmin          UserRole = "admin"
+	UserRolePrivacyOfficer UserRole = "privacy_officer"
 )
 
 func (e *UserRole) Scan(src interface{}) error {
@@ -97,6 +803,336 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type VisitRevisionKind string
+
+const (
+	VisitRevisionKindEdit     VisitRevisionKind = "edit"
+	VisitRevisionKindFinalize VisitRevisionKind = "finalize"
+	VisitRevisionKindAddendum VisitRevisionKind = "addendum"
+)
+
+func (e *VisitRevisionKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = VisitRevisionKind(s)
+	case string:
+		*e = VisitRevisionKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for VisitRevisionKind: %T", src)
+	}
+	return nil
+}
+
+type NullVisitRevisionKind struct {
+	VisitRevisionKind VisitRevisionKind
+	Valid             bool // Valid is true if VisitRevisionKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullVisitRevisionKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.VisitRevisionKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.VisitRevisionKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullVisitRevisionKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.VisitRevisionKind), nil
+}
+
+type Alert struct {
+	ID                   pgtype.UUID
+	RuleID               pgtype.UUID
+	VisitID              pgtype.UUID
+	PatientID            pgtype.UUID
+	Source               ObservationSource
+	ObservationCode      string
+	ObservedValue        float64
+	ThresholdValue       float64
+	Direction            string
+	Message              string
+	Status               AlertStatus
+	OriginalDoctorID     pgtype.UUID
+	AssignedDoctorID     pgtype.UUID
+	EscalationLevel      int32
+	EscalateAt           pgtype.Timestamptz
+	EscalatedAt          pgtype.Timestamptz
+	AcknowledgedAt       pgtype.Timestamptz
+	AcknowledgedByUserID pgtype.UUID
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+}
+
+type AlertEscalation struct {
+	ID              pgtype.UUID
+	AlertID         pgtype.UUID
+	FromDoctorID    pgtype.UUID
+	ToDoctorID      pgtype.UUID
+	EscalationLevel int32
+	EscalatedAt     pgtype.Timestamptz
+}
+
+type AlertRule struct {
+	ID                   pgtype.UUID
+	ObservationCode      string
+	Name                 string
+	Sex                  NullGenderEnum
+	MinAgeYears          pgtype.Int4
+	MaxAgeYears          pgtype.Int4
+	CriticalLow          pgtype.Float8
+	CriticalHigh         pgtype.Float8
+	EscalateAfterMinutes int32
+	EscalationDoctorID   pgtype.UUID
+	IsActive             bool
+	CreatedByUserID      pgtype.UUID
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+}
+
+type AuditEvent struct {
+	Seq               int64
+	OccurredAt        pgtype.Timestamptz
+	ActorUserID       pgtype.UUID
+	ActorRole         pgtype.Text
+	Action            string
+	ResourceType      string
+	ResourceID        pgtype.UUID
+	PatientID         pgtype.UUID
+	Changes           []byte
+	ClientIp          pgtype.Text
+	UserAgent         pgtype.Text
+	RequestID         pgtype.Text
+	PrevHash          string
+	Hash              string
+	BreakGlassGrantID pgtype.UUID
+}
+
+type BreakGlassGrant struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	PatientID pgtype.UUID
+	Reason    string
+	GrantedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+type BreakGlassReport struct {
+	ReportDate  pgtype.Date
+	GeneratedAt pgtype.Timestamptz
+	GrantCount  int32
+	AccessCount int32
+	Report      []byte
+}
+
+type CareTeamMember struct {
+	PatientID        pgtype.UUID
+	UserID           pgtype.UUID
+	AssignedByUserID pgtype.UUID
+	AssignedAt       pgtype.Timestamptz
+}
+
+type Charge struct {
+	ID              pgtype.UUID
+	PatientID       pgtype.UUID
+	VisitID         pgtype.UUID
+	ChargeItemID    pgtype.UUID
+	Source          ChargeSource
+	SourceID        pgtype.UUID
+	Description     string
+	Quantity        int32
+	UnitPriceMinor  int64
+	TaxRateBps      int32
+	InvoiceID       pgtype.UUID
+	CreatedByUserID pgtype.UUID
+	CreatedAt       pgtype.Timestamptz
+}
+
+type ChargeItem struct {
+	ID              pgtype.UUID
+	Code            string
+	Description     string
+	UnitPriceMinor  int64
+	TaxCategoryCode string
+	IsActive        bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+type ClaimStatusHistory struct {
+	ID              pgtype.UUID
+	ClaimID         pgtype.UUID
+	Status          ClaimStatus
+	Note            pgtype.Text
+	ChangedByUserID pgtype.UUID
+	ChangedAt       pgtype.Timestamptz
+}
+
+type ConsentRecord struct {
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	ConsentType       ConsentType
+	TextVersion       string
+	SignedAt          pgtype.Timestamptz
+	WitnessedByUserID pgtype.UUID
+	WithdrawnAt       pgtype.Timestamptz
+	WithdrawnByUserID pgtype.UUID
+	WithdrawalReason  pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+}
+
+type EligibilityCheck struct {
+	ID              pgtype.UUID
+	PolicyID        pgtype.UUID
+	Status          EligibilityStatus
+	Message         pgtype.Text
+	PayerReference  pgtype.Text
+	CheckedByUserID pgtype.UUID
+	CheckedAt       pgtype.Timestamptz
+}
+
+type InsuranceClaim struct {
+	ID                pgtype.UUID
+	InvoiceID         pgtype.UUID
+	PolicyID          pgtype.UUID
+	PatientID         pgtype.UUID
+	DiagnosisCodes    []string
+	ClaimedMinor      int64
+	PaidMinor         pgtype.Int8
+	Status            ClaimStatus
+	PayerReference    pgtype.Text
+	RejectionReason   pgtype.Text
+	SubmittedByUserID pgtype.UUID
+	SubmittedAt       pgtype.Timestamptz
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	SentToPayerAt     pgtype.Timestamptz
+}
+
+type InsurancePolicy struct {
+	ID                   pgtype.UUID
+	PatientID            pgtype.UUID
+	PayerCode            string
+	PayerName            string
+	PlanName             pgtype.Text
+	MemberID             string
+	GroupNumber          pgtype.Text
+	ValidFrom            pgtype.Date
+	ValidTo              pgtype.Date
+	Priority             PolicyPriority
+	IsActive             bool
+	EligibilityStatus    EligibilityStatus
+	EligibilityCheckedAt pgtype.Timestamptz
+	CreatedByUserID      pgtype.UUID
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+}
+
+type Invoice struct {
+	ID             pgtype.UUID
+	InvoiceNumber  string
+	FiscalYear     int32
+	SequenceNumber int32
+	PatientID      pgtype.UUID
+	Status         InvoiceStatus
+	SubtotalMinor  int64
+	DiscountMinor  int64
+	TaxMinor       int64
+	TotalMinor     int64
+	PaidMinor      int64
+	Notes          pgtype.Text
+	IssuedByUserID pgtype.UUID
+	IssuedAt       pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+}
+
+type InvoiceCounter struct {
+	FiscalYear int32
+	LastNumber int32
+}
+
+type InvoiceLine struct {
+	ID             pgtype.UUID
+	InvoiceID      pgtype.UUID
+	ChargeID       pgtype.UUID
+	Description    string
+	Quantity       int32
+	UnitPriceMinor int64
+	DiscountMinor  int64
+	TaxRateBps     int32
+	TaxMinor       int64
+	LineTotalMinor int64
+}
+
+type LabAnalyte struct {
+	ID            pgtype.UUID
+	TestID        pgtype.UUID
+	Code          string
+	Name          string
+	Unit          pgtype.Text
+	ReferenceLow  pgtype.Float8
+	ReferenceHigh pgtype.Float8
+	CriticalLow   pgtype.Float8
+	CriticalHigh  pgtype.Float8
+	DisplayOrder  int32
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+}
+
+type LabOrder struct {
+	ID                pgtype.UUID
+	VisitID           pgtype.UUID
+	TestID            pgtype.UUID
+	OrderedByUserID   pgtype.UUID
+	Priority          LabOrderPriority
+	Status            LabOrderStatus
+	ClinicalNotes     pgtype.Text
+	OrderedAt         pgtype.Timestamptz
+	CollectedAt       pgtype.Timestamptz
+	CollectedByUserID pgtype.UUID
+	ResultedAt        pgtype.Timestamptz
+	ResultedByUserID  pgtype.UUID
+	VerifiedAt        pgtype.Timestamptz
+	VerifiedByUserID  pgtype.UUID
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type LabResult struct {
+	ID              pgtype.UUID
+	OrderID         pgtype.UUID
+	AnalyteID       pgtype.UUID
+	ValueNumeric    pgtype.Float8
+	ValueText       pgtype.Text
+	Unit            pgtype.Text
+	ReferenceLow    pgtype.Float8
+	ReferenceHigh   pgtype.Float8
+	Flag            NullLabResultFlag
+	Comment         pgtype.Text
+	EnteredByUserID pgtype.UUID
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+type LabTest struct {
+	ID           pgtype.UUID
+	Code         string
+	Name         string
+	SpecimenType LabSpecimenType
+	Description  pgtype.Text
+	IsActive     bool
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+	ChargeCode   pgtype.Text
+}
+
 type Patient struct {
 	ID                 pgtype.UUID
 	FirstName          string
@@ -111,21 +1147,154 @@ type Patient struct {
 	CreatedAt          pgtype.Timestamptz
 	UpdatedAt          pgtype.Timestamptz
 	DeletedAt          pgtype.Timestamptz
+	ContactPhoneBidx   pgtype.Text
+	ContactEmailBidx   pgtype.Text
+	PseudonymizedAt    pgtype.Timestamptz
+	Version            int32
+}
+
+type PatientExportArchive struct {
+	JobID pgtype.UUID
+	Data  []byte
+}
+
+type PatientExportJob struct {
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	RequestedByUserID pgtype.UUID
+	Status            PatientExportStatus
+	Attempts          int32
+	Error             pgtype.Text
+	SizeBytes         pgtype.Int8
+	RequestedAt       pgtype.Timestamptz
+	StartedAt         pgtype.Timestamptz
+	CompletedAt       pgtype.Timestamptz
+	ExpiresAt         pgtype.Timestamptz
 }
 
 type PatientVisit struct {
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	DoctorID          pgtype.UUID
+	VisitDate         pgtype.Timestamptz
+	Symptoms          pgtype.Text
+	Diagnosis         pgtype.Text
+	Prescription      pgtype.Text
+	Notes             pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	Version           int32
+	FinalizedAt       pgtype.Timestamptz
+	FinalizedByUserID pgtype.UUID
+}
+
+type PatientVisitRevision struct {
+	ID                   pgtype.UUID
+	VisitID              pgtype.UUID
+	Version              int32
+	Kind                 VisitRevisionKind
+	Reason               pgtype.Text
+	AuthorUserID         pgtype.UUID
+	RevisedAt            pgtype.Timestamptz
+	PreviousVisitDate    pgtype.Timestamptz
+	PreviousSymptoms     pgtype.Text
+	PreviousDiagnosis    pgtype.Text
+	PreviousPrescription pgtype.Text
+	PreviousNotes        pgtype.Text
+}
+
+type Payment struct {
+	ID               pgtype.UUID
+	InvoiceID        pgtype.UUID
+	PatientID        pgtype.UUID
+	Method           PaymentMethod
+	AmountMinor      int64
+	Reference        pgtype.Text
+	ReceivedByUserID pgtype.UUID
+	ReceivedAt       pgtype.Timestamptz
+}
+
+type PharmacyBatch struct {
+	ID         pgtype.UUID
+	ItemID     pgtype.UUID
+	LotNumber  string
+	ExpiryDate pgtype.Date
+	CreatedAt  pgtype.Timestamptz
+}
+
+type PharmacyItem struct {
 	ID           pgtype.UUID
-	PatientID    pgtype.UUID
-	DoctorID     pgtype.UUID
-	VisitDate    pgtype.Timestamptz
-	Symptoms     pgtype.Text
-	Diagnosis    pgtype.Text
-	Prescription pgtype.Text
-	Notes        pgtype.Text
+	Code         string
+	Name         string
+	DosageForm   pgtype.Text
+	Strength     pgtype.Text
+	Unit         string
+	ReorderLevel int32
+	IsActive     bool
 	CreatedAt    pgtype.Timestamptz
 	UpdatedAt    pgtype.Timestamptz
 }
 
+type PharmacyLocation struct {
+	ID        pgtype.UUID
+	Code      string
+	Name      string
+	IsActive  bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type PseudonymizationRequest struct {
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	RequestedByUserID pgtype.UUID
+	Reason            string
+	Status            PseudonymizationStatus
+	DecidedByUserID   pgtype.UUID
+	DecidedAt         pgtype.Timestamptz
+	DecisionNote      pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+}
+
+type Refund struct {
+	ID               pgtype.UUID
+	PaymentID        pgtype.UUID
+	InvoiceID        pgtype.UUID
+	AmountMinor      int64
+	Reason           string
+	RefundedByUserID pgtype.UUID
+	RefundedAt       pgtype.Timestamptz
+}
+
+type StockLevel struct {
+	LocationID pgtype.UUID
+	BatchID    pgtype.UUID
+	Quantity   int32
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type StockMovement struct {
+	ID                 pgtype.UUID
+	MovementType       StockMovementType
+	LocationID         pgtype.UUID
+	BatchID            pgtype.UUID
+	Quantity           int32
+	VisitID            pgtype.UUID
+	ReturnedMovementID pgtype.UUID
+	Reference          pgtype.Text
+	Reason             pgtype.Text
+	CreatedByUserID    pgtype.UUID
+	CreatedAt          pgtype.Timestamptz
+}
+
+type TaxCategory struct {
+	Code        string
+	Description string
+	RateBps     int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
 type User struct {
 	ID           pgtype.UUID
 	Username     string
@@ -138,3 +1307,14 @@ type User struct {
 	CreatedAt    pgtype.Timestamptz
 	UpdatedAt    pgtype.Timestamptz
 }
+
+type VisitObservation struct {
+	ID               pgtype.UUID
+	VisitID          pgtype.UUID
+	Code             string
+	Value            float64
+	Unit             pgtype.Text
+	ObservedAt       pgtype.Timestamptz
+	RecordedByUserID pgtype.UUID
+	CreatedAt        pgtype.Timestamptz
+}
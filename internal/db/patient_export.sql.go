@@ -0,0 +1,346 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: patient_export.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelPatientExports = `-- name: CancelPatientExports :execrows
+UPDATE patient_export_jobs
+SET
+    status = CASE WHEN status = 'completed' THEN 'expired'::patient_export_status ELSE 'failed'::patient_export_status END,
+    error = CASE WHEN status = 'completed' THEN error ELSE $1::text END,
+    completed_at = COALESCE(completed_at, NOW())
+WHERE patient_id = $2 AND status IN ('pending', 'running', 'completed')
+`
+
+type CancelPatientExportsParams struct {
+	Reason    string
+	PatientID pgtype.UUID
+}
+
+// Withdraws a patient's exports, e.g. once they are pseudonymized: archives already
+// built expire and queued jobs fail.
+func (q *Queries) CancelPatientExports(ctx context.Context, arg CancelPatientExportsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelPatientExports, arg.Reason, arg.PatientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimPatientExportJob = `-- name: ClaimPatientExportJob :one
+UPDATE patient_export_jobs
+SET status = 'running', started_at = NOW(), attempts = attempts + 1
+WHERE id = (
+    SELECT queued.id FROM patient_export_jobs queued
+    WHERE queued.status = 'pending' OR (queued.status = 'running' AND queued.started_at < $1::timestamptz)
+    ORDER BY queued.requested_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at
+`
+
+// Takes the oldest pending job, or a running one whose worker has not finished by
+// stale_before (e.g. it crashed), without blocking other workers.
+func (q *Queries) ClaimPatientExportJob(ctx context.Context, staleBefore pgtype.Timestamptz) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, claimPatientExportJob, staleBefore)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const completePatientExportJob = `-- name: CompletePatientExportJob :one
+UPDATE patient_export_jobs
+SET status = 'completed', completed_at = NOW(), size_bytes = $2, expires_at = $3, error = NULL
+WHERE id = $1 AND status = 'running'
+RETURNING id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at
+`
+
+type CompletePatientExportJobParams struct {
+	ID        pgtype.UUID
+	SizeBytes pgtype.Int8
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CompletePatientExportJob(ctx context.Context, arg CompletePatientExportJobParams) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, completePatientExportJob, arg.ID, arg.SizeBytes, arg.ExpiresAt)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const countPatientExportJobs = `-- name: CountPatientExportJobs :one
+SELECT COUNT(*) FROM patient_export_jobs
+WHERE patient_id = $1
+`
+
+func (q *Queries) CountPatientExportJobs(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPatientExportJobs, patientID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPatientExportJob = `-- name: CreatePatientExportJob :one
+INSERT INTO patient_export_jobs (
+    patient_id, requested_by_user_id
+) VALUES (
+    $1, $2
+)
+RETURNING id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at
+`
+
+type CreatePatientExportJobParams struct {
+	PatientID         pgtype.UUID
+	RequestedByUserID pgtype.UUID
+}
+
+func (q *Queries) CreatePatientExportJob(ctx context.Context, arg CreatePatientExportJobParams) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, createPatientExportJob, arg.PatientID, arg.RequestedByUserID)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteExpiredPatientExportArchives = `-- name: DeleteExpiredPatientExportArchives :execrows
+DELETE FROM patient_export_archives a
+USING patient_export_jobs j
+WHERE a.job_id = j.id AND j.status IN ('expired', 'failed')
+`
+
+func (q *Queries) DeleteExpiredPatientExportArchives(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredPatientExportArchives)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePatientExportArchives = `-- name: DeletePatientExportArchives :execrows
+DELETE FROM patient_export_archives a
+USING patient_export_jobs j
+WHERE a.job_id = j.id AND j.patient_id = $1
+`
+
+func (q *Queries) DeletePatientExportArchives(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePatientExportArchives, patientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const expirePatientExportJobs = `-- name: ExpirePatientExportJobs :execrows
+UPDATE patient_export_jobs
+SET status = 'expired'
+WHERE status = 'completed' AND expires_at <= $1
+`
+
+func (q *Queries) ExpirePatientExportJobs(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, expirePatientExportJobs, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failPatientExportJob = `-- name: FailPatientExportJob :one
+UPDATE patient_export_jobs
+SET status = 'failed', completed_at = NOW(), error = $2
+WHERE id = $1 AND status = 'running'
+RETURNING id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at
+`
+
+type FailPatientExportJobParams struct {
+	ID    pgtype.UUID
+	Error pgtype.Text
+}
+
+func (q *Queries) FailPatientExportJob(ctx context.Context, arg FailPatientExportJobParams) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, failPatientExportJob, arg.ID, arg.Error)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getOpenPatientExportJob = `-- name: GetOpenPatientExportJob :one
+SELECT id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at FROM patient_export_jobs
+WHERE patient_id = $1 AND status IN ('pending', 'running')
+`
+
+func (q *Queries) GetOpenPatientExportJob(ctx context.Context, patientID pgtype.UUID) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, getOpenPatientExportJob, patientID)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getPatientExportArchive = `-- name: GetPatientExportArchive :one
+SELECT data FROM patient_export_archives
+WHERE job_id = $1
+`
+
+func (q *Queries) GetPatientExportArchive(ctx context.Context, jobID pgtype.UUID) ([]byte, error) {
+	row := q.db.QueryRow(ctx, getPatientExportArchive, jobID)
+	var data []byte
+	err := row.Scan(&data)
+	return data, err
+}
+
+const getPatientExportJob = `-- name: GetPatientExportJob :one
+SELECT id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at FROM patient_export_jobs
+WHERE id = $1 AND patient_id = $2
+`
+
+type GetPatientExportJobParams struct {
+	ID        pgtype.UUID
+	PatientID pgtype.UUID
+}
+
+func (q *Queries) GetPatientExportJob(ctx context.Context, arg GetPatientExportJobParams) (PatientExportJob, error) {
+	row := q.db.QueryRow(ctx, getPatientExportJob, arg.ID, arg.PatientID)
+	var i PatientExportJob
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Status,
+		&i.Attempts,
+		&i.Error,
+		&i.SizeBytes,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const listPatientExportJobs = `-- name: ListPatientExportJobs :many
+SELECT id, patient_id, requested_by_user_id, status, attempts, error, size_bytes, requested_at, started_at, completed_at, expires_at FROM patient_export_jobs
+WHERE patient_id = $1
+ORDER BY requested_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListPatientExportJobsParams struct {
+	PatientID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListPatientExportJobs(ctx context.Context, arg ListPatientExportJobsParams) ([]PatientExportJob, error) {
+	rows, err := q.db.Query(ctx, listPatientExportJobs, arg.PatientID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PatientExportJob
+	for rows.Next() {
+		var i PatientExportJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.RequestedByUserID,
+			&i.Status,
+			&i.Attempts,
+			&i.Error,
+			&i.SizeBytes,
+			&i.RequestedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const savePatientExportArchive = `-- name: SavePatientExportArchive :exec
+INSERT INTO patient_export_archives (job_id, data)
+VALUES ($1, $2)
+ON CONFLICT (job_id) DO UPDATE SET data = EXCLUDED.data
+`
+
+type SavePatientExportArchiveParams struct {
+	JobID pgtype.UUID
+	Data  []byte
+}
+
+func (q *Queries) SavePatientExportArchive(ctx context.Context, arg SavePatientExportArchiveParams) error {
+	_, err := q.db.Exec(ctx, savePatientExportArchive, arg.JobID, arg.Data)
+	return err
+}
@@ -17,7 +17,7 @@ INSERT INTO patient_visits (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at
+RETURNING id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id
 `
 
 type CreatePatientVisitParams struct {
@@ -52,6 +52,9 @@ func (q *Queries) CreatePatientVisit(ctx context.Context, arg CreatePatientVisit
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.FinalizedAt,
+		&i.FinalizedByUserID,
 	)
 	return i, err
 }
@@ -67,7 +70,7 @@ func (q *Queries) DeletePatientVisit(ctx context.Context, id pgtype.UUID) error
 }
 
 const getPatientVisitByID = `-- name: GetPatientVisitByID :one
-SELECT id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at FROM patient_visits
+SELECT id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id FROM patient_visits
 WHERE id = $1
 LIMIT 1
 `
@@ -86,12 +89,15 @@ func (q *Queries) GetPatientVisitByID(ctx context.Context, id pgtype.UUID) (Pati
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.FinalizedAt,
+		&i.FinalizedByUserID,
 	)
 	return i, err
 }
 
 const listPatientVisitsByDoctorID = `-- name: ListPatientVisitsByDoctorID :many
-SELECT pv.id, pv.patient_id, pv.doctor_id, pv.visit_date, pv.symptoms, pv.diagnosis, pv.prescription, pv.notes, pv.created_at, pv.updated_at, p.first_name as patient_first_name, p.last_name as patient_last_name
+SELECT pv.id, pv.patient_id, pv.doctor_id, pv.visit_date, pv.symptoms, pv.diagnosis, pv.prescription, pv.notes, pv.created_at, pv.updated_at, pv.version, pv.finalized_at, pv.finalized_by_user_id, p.first_name as patient_first_name, p.last_name as patient_last_name
 FROM patient_visits pv
 JOIN patients p ON pv.patient_id = p.id -- Join to get patient's name
 WHERE pv.doctor_id = $1
@@ -107,18 +113,21 @@ type ListPatientVisitsByDoctorIDParams struct {
 }
 
 type ListPatientVisitsByDoctorIDRow struct {
-	ID               pgtype.UUID
-	PatientID        pgtype.UUID
-	DoctorID         pgtype.UUID
-	VisitDate        pgtype.Timestamptz
-	Symptoms         pgtype.Text
-	Diagnosis        pgtype.Text
-	Prescription     pgtype.Text
-	Notes            pgtype.Text
-	CreatedAt        pgtype.Timestamptz
-	UpdatedAt        pgtype.Timestamptz
-	PatientFirstName string
-	PatientLastName  string
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	DoctorID          pgtype.UUID
+	VisitDate         pgtype.Timestamptz
+	Symptoms          pgtype.Text
+	Diagnosis         pgtype.Text
+	Prescription      pgtype.Text
+	Notes             pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	Version           int32
+	FinalizedAt       pgtype.Timestamptz
+	FinalizedByUserID pgtype.UUID
+	PatientFirstName  string
+	PatientLastName   string
 }
 
 func (q *Queries) ListPatientVisitsByDoctorID(ctx context.Context, arg ListPatientVisitsByDoctorIDParams) ([]ListPatientVisitsByDoctorIDRow, error) {
@@ -141,6 +150,9 @@ func (q *Queries) ListPatientVisitsByDoctorID(ctx context.Context, arg ListPatie
 			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.FinalizedAt,
+			&i.FinalizedByUserID,
 			&i.PatientFirstName,
 			&i.PatientLastName,
 		); err != nil {
@@ -155,7 +167,7 @@ func (q *Queries) ListPatientVisitsByDoctorID(ctx context.Context, arg ListPatie
 }
 
 const listPatientVisitsByPatientID = `-- name: ListPatientVisitsByPatientID :many
-SELECT pv.id, pv.patient_id, pv.doctor_id, pv.visit_date, pv.symptoms, pv.diagnosis, pv.prescription, pv.notes, pv.created_at, pv.updated_at, u.first_name as doctor_first_name, u.last_name as doctor_last_name
+SELECT pv.id, pv.patient_id, pv.doctor_id, pv.visit_date, pv.symptoms, pv.diagnosis, pv.prescription, pv.notes, pv.created_at, pv.updated_at, pv.version, pv.finalized_at, pv.finalized_by_user_id, u.first_name as doctor_first_name, u.last_name as doctor_last_name
 FROM patient_visits pv
 JOIN users u ON pv.doctor_id = u.id -- Join to get doctor's name
 WHERE pv.patient_id = $1
@@ -171,18 +183,21 @@ type ListPatientVisitsByPatientIDParams struct {
 }
 
 type ListPatientVisitsByPatientIDRow struct {
-	ID              pgtype.UUID
-	PatientID       pgtype.UUID
-	DoctorID        pgtype.UUID
-	VisitDate       pgtype.Timestamptz
-	Symptoms        pgtype.Text
-	Diagnosis       pgtype.Text
-	Prescription    pgtype.Text
-	Notes           pgtype.Text
-	CreatedAt       pgtype.Timestamptz
-	UpdatedAt       pgtype.Timestamptz
-	DoctorFirstName pgtype.Text
-	DoctorLastName  pgtype.Text
+	ID                pgtype.UUID
+	PatientID         pgtype.UUID
+	DoctorID          pgtype.UUID
+	VisitDate         pgtype.Timestamptz
+	Symptoms          pgtype.Text
+	Diagnosis         pgtype.Text
+	Prescription      pgtype.Text
+	Notes             pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	Version           int32
+	FinalizedAt       pgtype.Timestamptz
+	FinalizedByUserID pgtype.UUID
+	DoctorFirstName   pgtype.Text
+	DoctorLastName    pgtype.Text
 }
 
 func (q *Queries) ListPatientVisitsByPatientID(ctx context.Context, arg ListPatientVisitsByPatientIDParams) ([]ListPatientVisitsByPatientIDRow, error) {
@@ -205,6 +220,9 @@ func (q *Queries) ListPatientVisitsByPatientID(ctx context.Context, arg ListPati
 			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.FinalizedAt,
+			&i.FinalizedByUserID,
 			&i.DoctorFirstName,
 			&i.DoctorLastName,
 		); err != nil {
@@ -218,6 +236,90 @@ func (q *Queries) ListPatientVisitsByPatientID(ctx context.Context, arg ListPati
 	return items, nil
 }
 
+const listPatientVisitsForReencryption = `-- name: ListPatientVisitsForReencryption :many
+SELECT id, symptoms, diagnosis, prescription, notes, updated_at
+FROM patient_visits
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListPatientVisitsForReencryptionParams struct {
+	AfterID  pgtype.UUID
+	RowLimit int32
+}
+
+type ListPatientVisitsForReencryptionRow struct {
+	ID           pgtype.UUID
+	Symptoms     pgtype.Text
+	Diagnosis    pgtype.Text
+	Prescription pgtype.Text
+	Notes        pgtype.Text
+	UpdatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) ListPatientVisitsForReencryption(ctx context.Context, arg ListPatientVisitsForReencryptionParams) ([]ListPatientVisitsForReencryptionRow, error) {
+	rows, err := q.db.Query(ctx, listPatientVisitsForReencryption, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPatientVisitsForReencryptionRow
+	for rows.Next() {
+		var i ListPatientVisitsForReencryptionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symptoms,
+			&i.Diagnosis,
+			&i.Prescription,
+			&i.Notes,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reencryptPatientVisit = `-- name: ReencryptPatientVisit :execrows
+UPDATE patient_visits
+SET
+    symptoms = $1,
+    diagnosis = $2,
+    prescription = $3,
+    notes = $4
+WHERE id = $5 AND updated_at = $6
+`
+
+type ReencryptPatientVisitParams struct {
+	Symptoms      pgtype.Text
+	Diagnosis     pgtype.Text
+	Prescription  pgtype.Text
+	Notes         pgtype.Text
+	ID            pgtype.UUID
+	ReadUpdatedAt pgtype.Timestamptz
+}
+
+// Leaves updated_at alone and skips the row if it changed since it was read.
+func (q *Queries) ReencryptPatientVisit(ctx context.Context, arg ReencryptPatientVisitParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reencryptPatientVisit,
+		arg.Symptoms,
+		arg.Diagnosis,
+		arg.Prescription,
+		arg.Notes,
+		arg.ID,
+		arg.ReadUpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePatientVisit = `-- name: UpdatePatientVisit :one
 UPDATE patient_visits
 SET
@@ -226,18 +328,20 @@ SET
     diagnosis = COALESCE($3, diagnosis),
     prescription = COALESCE($4, prescription),
     notes = COALESCE($5, notes),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $6
-RETURNING id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at
+WHERE id = $6 AND version = $7
+RETURNING id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id
 `
 
 type UpdatePatientVisitParams struct {
-	VisitDate    pgtype.Timestamptz
-	Symptoms     pgtype.Text
-	Diagnosis    pgtype.Text
-	Prescription pgtype.Text
-	Notes        pgtype.Text
-	ID           pgtype.UUID
+	VisitDate       pgtype.Timestamptz
+	Symptoms        pgtype.Text
+	Diagnosis       pgtype.Text
+	Prescription    pgtype.Text
+	Notes           pgtype.Text
+	ID              pgtype.UUID
+	ExpectedVersion int32
 }
 
 func (q *Queries) UpdatePatientVisit(ctx context.Context, arg UpdatePatientVisitParams) (PatientVisit, error) {
@@ -248,6 +352,7 @@ func (q *Queries) UpdatePatientVisit(ctx context.Context, arg UpdatePatientVisit
 		arg.Prescription,
 		arg.Notes,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i PatientVisit
 	err := row.Scan(
@@ -261,6 +366,9 @@ func (q *Queries) UpdatePatientVisit(ctx context.Context, arg UpdatePatientVisit
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.FinalizedAt,
+		&i.FinalizedByUserID,
 	)
 	return i, err
 }
@@ -23,15 +23,31 @@ func (q *Queries) CountPatients(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countPatientsInCareOf = `-- name: CountPatientsInCareOf :one
+SELECT COUNT(*) FROM patients p
+WHERE p.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM care_team_members ct WHERE ct.patient_id = p.id AND ct.user_id = $1)
+    OR EXISTS (SELECT 1 FROM patient_visits pv WHERE pv.patient_id = p.id AND pv.doctor_id = $1)
+  )
+`
+
+func (q *Queries) CountPatientsInCareOf(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPatientsInCareOf, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPatient = `-- name: CreatePatient :one
 INSERT INTO patients (
     first_name, last_name, date_of_birth, gender,
     contact_phone, contact_email, address, medical_history,
-    registered_by_user_id
+    registered_by_user_id, contact_phone_bidx, contact_email_bidx
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at
+RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version
 `
 
 type CreatePatientParams struct {
@@ -44,6 +60,8 @@ type CreatePatientParams struct {
 	Address            pgtype.Text
 	MedicalHistory     pgtype.Text
 	RegisteredByUserID pgtype.UUID
+	ContactPhoneBidx   pgtype.Text
+	ContactEmailBidx   pgtype.Text
 }
 
 func (q *Queries) CreatePatient(ctx context.Context, arg CreatePatientParams) (Patient, error) {
@@ -57,7 +75,40 @@ func (q *Queries) CreatePatient(ctx context.Context, arg CreatePatientParams) (P
 		arg.Address,
 		arg.MedicalHistory,
 		arg.RegisteredByUserID,
+		arg.ContactPhoneBidx,
+		arg.ContactEmailBidx,
+	)
+	var i Patient
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.DateOfBirth,
+		&i.Gender,
+		&i.ContactPhone,
+		&i.ContactEmail,
+		&i.Address,
+		&i.MedicalHistory,
+		&i.RegisteredByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
+	return i, err
+}
+
+const getPatientByContactEmailIndex = `-- name: GetPatientByContactEmailIndex :one
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients
+WHERE contact_email_bidx = $1 AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetPatientByContactEmailIndex(ctx context.Context, contactEmailBidx pgtype.Text) (Patient, error) {
+	row := q.db.QueryRow(ctx, getPatientByContactEmailIndex, contactEmailBidx)
 	var i Patient
 	err := row.Scan(
 		&i.ID,
@@ -73,12 +124,47 @@ func (q *Queries) CreatePatient(ctx context.Context, arg CreatePatientParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getPatientByContactPhoneIndex = `-- name: GetPatientByContactPhoneIndex :one
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients
+WHERE contact_phone_bidx = $1 AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetPatientByContactPhoneIndex(ctx context.Context, contactPhoneBidx pgtype.Text) (Patient, error) {
+	row := q.db.QueryRow(ctx, getPatientByContactPhoneIndex, contactPhoneBidx)
+	var i Patient
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.DateOfBirth,
+		&i.Gender,
+		&i.ContactPhone,
+		&i.ContactEmail,
+		&i.Address,
+		&i.MedicalHistory,
+		&i.RegisteredByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getPatientByID = `-- name: GetPatientByID :one
-SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at FROM patients
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -100,6 +186,10 @@ func (q *Queries) GetPatientByID(ctx context.Context, id pgtype.UUID) (Patient,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -115,7 +205,7 @@ func (q *Queries) HardDeletePatient(ctx context.Context, id pgtype.UUID) error {
 }
 
 const listPatients = `-- name: ListPatients :many
-SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at FROM patients
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients
 WHERE deleted_at IS NULL
 ORDER BY last_name, first_name
 LIMIT $1
@@ -150,6 +240,10 @@ func (q *Queries) ListPatients(ctx context.Context, arg ListPatientsParams) ([]P
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ContactPhoneBidx,
+			&i.ContactEmailBidx,
+			&i.PseudonymizedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -161,11 +255,158 @@ func (q *Queries) ListPatients(ctx context.Context, arg ListPatientsParams) ([]P
 	return items, nil
 }
 
+const listPatientsForReencryption = `-- name: ListPatientsForReencryption :many
+SELECT id, contact_phone, contact_email, medical_history, contact_phone_bidx, contact_email_bidx, updated_at
+FROM patients
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListPatientsForReencryptionParams struct {
+	AfterID  pgtype.UUID
+	RowLimit int32
+}
+
+type ListPatientsForReencryptionRow struct {
+	ID               pgtype.UUID
+	ContactPhone     pgtype.Text
+	ContactEmail     pgtype.Text
+	MedicalHistory   pgtype.Text
+	ContactPhoneBidx pgtype.Text
+	ContactEmailBidx pgtype.Text
+	UpdatedAt        pgtype.Timestamptz
+}
+
+// Includes soft-deleted patients: their data is still at rest.
+func (q *Queries) ListPatientsForReencryption(ctx context.Context, arg ListPatientsForReencryptionParams) ([]ListPatientsForReencryptionRow, error) {
+	rows, err := q.db.Query(ctx, listPatientsForReencryption, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPatientsForReencryptionRow
+	for rows.Next() {
+		var i ListPatientsForReencryptionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContactPhone,
+			&i.ContactEmail,
+			&i.MedicalHistory,
+			&i.ContactPhoneBidx,
+			&i.ContactEmailBidx,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientsInCareOf = `-- name: ListPatientsInCareOf :many
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients p
+WHERE p.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM care_team_members ct WHERE ct.patient_id = p.id AND ct.user_id = $1)
+    OR EXISTS (SELECT 1 FROM patient_visits pv WHERE pv.patient_id = p.id AND pv.doctor_id = $1)
+  )
+ORDER BY p.last_name, p.first_name
+LIMIT $3
+OFFSET $2
+`
+
+type ListPatientsInCareOfParams struct {
+	UserID    pgtype.UUID
+	RowOffset int32
+	RowLimit  int32
+}
+
+// Patients the user is on the care team of or has recorded a visit for.
+func (q *Queries) ListPatientsInCareOf(ctx context.Context, arg ListPatientsInCareOfParams) ([]Patient, error) {
+	rows, err := q.db.Query(ctx, listPatientsInCareOf, arg.UserID, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Patient
+	for rows.Next() {
+		var i Patient
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.DateOfBirth,
+			&i.Gender,
+			&i.ContactPhone,
+			&i.ContactEmail,
+			&i.Address,
+			&i.MedicalHistory,
+			&i.RegisteredByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ContactPhoneBidx,
+			&i.ContactEmailBidx,
+			&i.PseudonymizedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reencryptPatient = `-- name: ReencryptPatient :execrows
+UPDATE patients
+SET
+    contact_phone = $1,
+    contact_email = $2,
+    medical_history = $3,
+    contact_phone_bidx = $4,
+    contact_email_bidx = $5
+WHERE id = $6 AND updated_at = $7
+`
+
+type ReencryptPatientParams struct {
+	ContactPhone     pgtype.Text
+	ContactEmail     pgtype.Text
+	MedicalHistory   pgtype.Text
+	ContactPhoneBidx pgtype.Text
+	ContactEmailBidx pgtype.Text
+	ID               pgtype.UUID
+	ReadUpdatedAt    pgtype.Timestamptz
+}
+
+// Leaves updated_at alone and skips the row if it changed since it was read.
+func (q *Queries) ReencryptPatient(ctx context.Context, arg ReencryptPatientParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reencryptPatient,
+		arg.ContactPhone,
+		arg.ContactEmail,
+		arg.MedicalHistory,
+		arg.ContactPhoneBidx,
+		arg.ContactEmailBidx,
+		arg.ID,
+		arg.ReadUpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const softDeletePatient = `-- name: SoftDeletePatient :one
 UPDATE patients
-SET deleted_at = NOW(), updated_at = NOW()
+SET deleted_at = NOW(), version = version + 1, updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at
+RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version
 `
 
 func (q *Queries) SoftDeletePatient(ctx context.Context, id pgtype.UUID) (Patient, error) {
@@ -185,6 +426,10 @@ func (q *Queries) SoftDeletePatient(ctx context.Context, id pgtype.UUID) (Patien
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -198,23 +443,29 @@ SET
     gender = COALESCE($4, gender),
     contact_phone = COALESCE($5, contact_phone),
     contact_email = COALESCE($6, contact_email),
-    address = COALESCE($7, address),
-    medical_history = COALESCE($8, medical_history),
+    contact_phone_bidx = COALESCE($7, contact_phone_bidx),
+    contact_email_bidx = COALESCE($8, contact_email_bidx),
+    address = COALESCE($9, address),
+    medical_history = COALESCE($10, medical_history),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $9 AND deleted_at IS NULL
-RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at
+WHERE id = $11 AND deleted_at IS NULL AND version = $12
+RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version
 `
 
 type UpdatePatientParams struct {
-	FirstName      pgtype.Text
-	LastName       pgtype.Text
-	DateOfBirth    pgtype.Date
-	Gender         NullGenderEnum
-	ContactPhone   pgtype.Text
-	ContactEmail   pgtype.Text
-	Address        pgtype.Text
-	MedicalHistory pgtype.Text
-	ID             pgtype.UUID
+	FirstName        pgtype.Text
+	LastName         pgtype.Text
+	DateOfBirth      pgtype.Date
+	Gender           NullGenderEnum
+	ContactPhone     pgtype.Text
+	ContactEmail     pgtype.Text
+	ContactPhoneBidx pgtype.Text
+	ContactEmailBidx pgtype.Text
+	Address          pgtype.Text
+	MedicalHistory   pgtype.Text
+	ID               pgtype.UUID
+	ExpectedVersion  int32
 }
 
 func (q *Queries) UpdatePatient(ctx context.Context, arg UpdatePatientParams) (Patient, error) {
@@ -225,9 +476,12 @@ func (q *Queries) UpdatePatient(ctx context.Context, arg UpdatePatientParams) (P
 		arg.Gender,
 		arg.ContactPhone,
 		arg.ContactEmail,
+		arg.ContactPhoneBidx,
+		arg.ContactEmailBidx,
 		arg.Address,
 		arg.MedicalHistory,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i Patient
 	err := row.Scan(
@@ -244,6 +498,10 @@ func (q *Queries) UpdatePatient(ctx context.Context, arg UpdatePatientParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -252,9 +510,10 @@ const updatePatientMedicalInfo = `-- name: UpdatePatientMedicalInfo :one
 UPDATE patients
 SET
     medical_history = $2,
+    version = version + 1,
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at
+RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version
 `
 
 type UpdatePatientMedicalInfoParams struct {
@@ -280,6 +539,10 @@ func (q *Queries) UpdatePatientMedicalInfo(ctx context.Context, arg UpdatePatien
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,808 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: pharmacy.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const applyStockDelta = `-- name: ApplyStockDelta :one
+INSERT INTO stock_levels (
+    location_id, batch_id, quantity
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (location_id, batch_id) DO UPDATE
+SET quantity = stock_levels.quantity + EXCLUDED.quantity,
+    updated_at = NOW()
+RETURNING location_id, batch_id, quantity, updated_at
+`
+
+type ApplyStockDeltaParams struct {
+	LocationID pgtype.UUID
+	BatchID    pgtype.UUID
+	Delta      int32
+}
+
+// The CHECK on quantity rejects a delta that would take the level below zero.
+func (q *Queries) ApplyStockDelta(ctx context.Context, arg ApplyStockDeltaParams) (StockLevel, error) {
+	row := q.db.QueryRow(ctx, applyStockDelta, arg.LocationID, arg.BatchID, arg.Delta)
+	var i StockLevel
+	err := row.Scan(
+		&i.LocationID,
+		&i.BatchID,
+		&i.Quantity,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countPharmacyItems = `-- name: CountPharmacyItems :one
+SELECT COUNT(*) FROM pharmacy_items
+WHERE is_active = TRUE
+`
+
+func (q *Queries) CountPharmacyItems(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countPharmacyItems)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countStockMovements = `-- name: CountStockMovements :one
+SELECT COUNT(*)
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+WHERE ($1::uuid IS NULL OR sm.location_id = $1)
+  AND ($2::uuid IS NULL OR b.item_id = $2)
+  AND ($3::uuid IS NULL OR sm.batch_id = $3)
+`
+
+type CountStockMovementsParams struct {
+	LocationID pgtype.UUID
+	ItemID     pgtype.UUID
+	BatchID    pgtype.UUID
+}
+
+func (q *Queries) CountStockMovements(ctx context.Context, arg CountStockMovementsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countStockMovements, arg.LocationID, arg.ItemID, arg.BatchID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPharmacyItem = `-- name: CreatePharmacyItem :one
+INSERT INTO pharmacy_items (
+    code, name, dosage_form, strength, unit, reorder_level
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, code, name, dosage_form, strength, unit, reorder_level, is_active, created_at, updated_at
+`
+
+type CreatePharmacyItemParams struct {
+	Code         string
+	Name         string
+	DosageForm   pgtype.Text
+	Strength     pgtype.Text
+	Unit         string
+	ReorderLevel int32
+}
+
+func (q *Queries) CreatePharmacyItem(ctx context.Context, arg CreatePharmacyItemParams) (PharmacyItem, error) {
+	row := q.db.QueryRow(ctx, createPharmacyItem,
+		arg.Code,
+		arg.Name,
+		arg.DosageForm,
+		arg.Strength,
+		arg.Unit,
+		arg.ReorderLevel,
+	)
+	var i PharmacyItem
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.DosageForm,
+		&i.Strength,
+		&i.Unit,
+		&i.ReorderLevel,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPharmacyLocation = `-- name: CreatePharmacyLocation :one
+INSERT INTO pharmacy_locations (
+    code, name
+) VALUES (
+    $1, $2
+)
+RETURNING id, code, name, is_active, created_at, updated_at
+`
+
+type CreatePharmacyLocationParams struct {
+	Code string
+	Name string
+}
+
+func (q *Queries) CreatePharmacyLocation(ctx context.Context, arg CreatePharmacyLocationParams) (PharmacyLocation, error) {
+	row := q.db.QueryRow(ctx, createPharmacyLocation, arg.Code, arg.Name)
+	var i PharmacyLocation
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStockMovement = `-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+    movement_type, location_id, batch_id, quantity, visit_id,
+    returned_movement_id, reference, reason, created_by_user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, movement_type, location_id, batch_id, quantity, visit_id, returned_movement_id, reference, reason, created_by_user_id, created_at
+`
+
+type CreateStockMovementParams struct {
+	MovementType       StockMovementType
+	LocationID         pgtype.UUID
+	BatchID            pgtype.UUID
+	Quantity           int32
+	VisitID            pgtype.UUID
+	ReturnedMovementID pgtype.UUID
+	Reference          pgtype.Text
+	Reason             pgtype.Text
+	CreatedByUserID    pgtype.UUID
+}
+
+func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error) {
+	row := q.db.QueryRow(ctx, createStockMovement,
+		arg.MovementType,
+		arg.LocationID,
+		arg.BatchID,
+		arg.Quantity,
+		arg.VisitID,
+		arg.ReturnedMovementID,
+		arg.Reference,
+		arg.Reason,
+		arg.CreatedByUserID,
+	)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.MovementType,
+		&i.LocationID,
+		&i.BatchID,
+		&i.Quantity,
+		&i.VisitID,
+		&i.ReturnedMovementID,
+		&i.Reference,
+		&i.Reason,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getItemStockOnHand = `-- name: GetItemStockOnHand :one
+SELECT COALESCE(SUM(sl.quantity), 0)::bigint AS on_hand
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+WHERE sl.location_id = $1
+  AND b.item_id = $2
+  AND b.expiry_date >= $3::date
+`
+
+type GetItemStockOnHandParams struct {
+	LocationID pgtype.UUID
+	ItemID     pgtype.UUID
+	AsOf       pgtype.Date
+}
+
+// Unexpired quantity of an item at a location.
+func (q *Queries) GetItemStockOnHand(ctx context.Context, arg GetItemStockOnHandParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getItemStockOnHand, arg.LocationID, arg.ItemID, arg.AsOf)
+	var on_hand int64
+	err := row.Scan(&on_hand)
+	return on_hand, err
+}
+
+const getPharmacyBatchByID = `-- name: GetPharmacyBatchByID :one
+SELECT id, item_id, lot_number, expiry_date, created_at FROM pharmacy_batches
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPharmacyBatchByID(ctx context.Context, id pgtype.UUID) (PharmacyBatch, error) {
+	row := q.db.QueryRow(ctx, getPharmacyBatchByID, id)
+	var i PharmacyBatch
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.LotNumber,
+		&i.ExpiryDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPharmacyItemByID = `-- name: GetPharmacyItemByID :one
+SELECT id, code, name, dosage_form, strength, unit, reorder_level, is_active, created_at, updated_at FROM pharmacy_items
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPharmacyItemByID(ctx context.Context, id pgtype.UUID) (PharmacyItem, error) {
+	row := q.db.QueryRow(ctx, getPharmacyItemByID, id)
+	var i PharmacyItem
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.DosageForm,
+		&i.Strength,
+		&i.Unit,
+		&i.ReorderLevel,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPharmacyLocationByID = `-- name: GetPharmacyLocationByID :one
+SELECT id, code, name, is_active, created_at, updated_at FROM pharmacy_locations
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPharmacyLocationByID(ctx context.Context, id pgtype.UUID) (PharmacyLocation, error) {
+	row := q.db.QueryRow(ctx, getPharmacyLocationByID, id)
+	var i PharmacyLocation
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listItemsBelowReorderLevel = `-- name: ListItemsBelowReorderLevel :many
+SELECT i.id AS item_id, i.code AS item_code, i.name AS item_name, i.reorder_level,
+       pl.id AS location_id, pl.code AS location_code,
+       COALESCE(SUM(sl.quantity) FILTER (WHERE b.expiry_date >= $1::date), 0)::bigint AS on_hand
+FROM pharmacy_items i
+CROSS JOIN pharmacy_locations pl
+LEFT JOIN pharmacy_batches b ON b.item_id = i.id
+LEFT JOIN stock_levels sl ON sl.batch_id = b.id AND sl.location_id = pl.id
+WHERE i.is_active = TRUE
+  AND pl.is_active = TRUE
+  AND i.reorder_level > 0
+  AND ($2::uuid IS NULL OR pl.id = $2)
+GROUP BY i.id, pl.id
+HAVING COALESCE(SUM(sl.quantity) FILTER (WHERE b.expiry_date >= $1::date), 0) <= i.reorder_level
+ORDER BY pl.code, i.name
+`
+
+type ListItemsBelowReorderLevelParams struct {
+	AsOf       pgtype.Date
+	LocationID pgtype.UUID
+}
+
+type ListItemsBelowReorderLevelRow struct {
+	ItemID       pgtype.UUID
+	ItemCode     string
+	ItemName     string
+	ReorderLevel int32
+	LocationID   pgtype.UUID
+	LocationCode string
+	OnHand       int64
+}
+
+// Active items whose unexpired stock at an active location is at or below the reorder level.
+func (q *Queries) ListItemsBelowReorderLevel(ctx context.Context, arg ListItemsBelowReorderLevelParams) ([]ListItemsBelowReorderLevelRow, error) {
+	rows, err := q.db.Query(ctx, listItemsBelowReorderLevel, arg.AsOf, arg.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemsBelowReorderLevelRow
+	for rows.Next() {
+		var i ListItemsBelowReorderLevelRow
+		if err := rows.Scan(
+			&i.ItemID,
+			&i.ItemCode,
+			&i.ItemName,
+			&i.ReorderLevel,
+			&i.LocationID,
+			&i.LocationCode,
+			&i.OnHand,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNearExpiryStock = `-- name: ListNearExpiryStock :many
+SELECT sl.location_id, pl.code AS location_code, sl.batch_id, b.lot_number, b.expiry_date,
+       i.id AS item_id, i.code AS item_code, i.name AS item_name, i.unit, sl.quantity, sl.updated_at
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+JOIN pharmacy_locations pl ON pl.id = sl.location_id
+WHERE sl.quantity > 0
+  AND b.expiry_date <= $1::date
+  AND ($2::uuid IS NULL OR sl.location_id = $2)
+ORDER BY b.expiry_date, i.name, pl.code
+`
+
+type ListNearExpiryStockParams struct {
+	Cutoff     pgtype.Date
+	LocationID pgtype.UUID
+}
+
+type ListNearExpiryStockRow struct {
+	LocationID   pgtype.UUID
+	LocationCode string
+	BatchID      pgtype.UUID
+	LotNumber    string
+	ExpiryDate   pgtype.Date
+	ItemID       pgtype.UUID
+	ItemCode     string
+	ItemName     string
+	Unit         string
+	Quantity     int32
+	UpdatedAt    pgtype.Timestamptz
+}
+
+// Stock on hand expiring on or before the cutoff, including lots already expired.
+func (q *Queries) ListNearExpiryStock(ctx context.Context, arg ListNearExpiryStockParams) ([]ListNearExpiryStockRow, error) {
+	rows, err := q.db.Query(ctx, listNearExpiryStock, arg.Cutoff, arg.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNearExpiryStockRow
+	for rows.Next() {
+		var i ListNearExpiryStockRow
+		if err := rows.Scan(
+			&i.LocationID,
+			&i.LocationCode,
+			&i.BatchID,
+			&i.LotNumber,
+			&i.ExpiryDate,
+			&i.ItemID,
+			&i.ItemCode,
+			&i.ItemName,
+			&i.Unit,
+			&i.Quantity,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPharmacyItems = `-- name: ListPharmacyItems :many
+SELECT id, code, name, dosage_form, strength, unit, reorder_level, is_active, created_at, updated_at FROM pharmacy_items
+WHERE is_active = TRUE
+ORDER BY name
+LIMIT $1
+OFFSET $2
+`
+
+type ListPharmacyItemsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListPharmacyItems(ctx context.Context, arg ListPharmacyItemsParams) ([]PharmacyItem, error) {
+	rows, err := q.db.Query(ctx, listPharmacyItems, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PharmacyItem
+	for rows.Next() {
+		var i PharmacyItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.DosageForm,
+			&i.Strength,
+			&i.Unit,
+			&i.ReorderLevel,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPharmacyLocations = `-- name: ListPharmacyLocations :many
+SELECT id, code, name, is_active, created_at, updated_at FROM pharmacy_locations
+WHERE is_active = TRUE
+ORDER BY code
+`
+
+func (q *Queries) ListPharmacyLocations(ctx context.Context) ([]PharmacyLocation, error) {
+	rows, err := q.db.Query(ctx, listPharmacyLocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PharmacyLocation
+	for rows.Next() {
+		var i PharmacyLocation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStockLevels = `-- name: ListStockLevels :many
+SELECT sl.location_id, pl.code AS location_code, sl.batch_id, b.lot_number, b.expiry_date,
+       i.id AS item_id, i.code AS item_code, i.name AS item_name, i.unit, sl.quantity, sl.updated_at
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+JOIN pharmacy_locations pl ON pl.id = sl.location_id
+WHERE sl.quantity > 0
+  AND ($1::uuid IS NULL OR sl.location_id = $1)
+  AND ($2::uuid IS NULL OR i.id = $2)
+ORDER BY i.name, pl.code, b.expiry_date
+`
+
+type ListStockLevelsParams struct {
+	LocationID pgtype.UUID
+	ItemID     pgtype.UUID
+}
+
+type ListStockLevelsRow struct {
+	LocationID   pgtype.UUID
+	LocationCode string
+	BatchID      pgtype.UUID
+	LotNumber    string
+	ExpiryDate   pgtype.Date
+	ItemID       pgtype.UUID
+	ItemCode     string
+	ItemName     string
+	Unit         string
+	Quantity     int32
+	UpdatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) ListStockLevels(ctx context.Context, arg ListStockLevelsParams) ([]ListStockLevelsRow, error) {
+	rows, err := q.db.Query(ctx, listStockLevels, arg.LocationID, arg.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStockLevelsRow
+	for rows.Next() {
+		var i ListStockLevelsRow
+		if err := rows.Scan(
+			&i.LocationID,
+			&i.LocationCode,
+			&i.BatchID,
+			&i.LotNumber,
+			&i.ExpiryDate,
+			&i.ItemID,
+			&i.ItemCode,
+			&i.ItemName,
+			&i.Unit,
+			&i.Quantity,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStockMovements = `-- name: ListStockMovements :many
+SELECT sm.id, sm.movement_type, sm.location_id, sm.batch_id, sm.quantity, sm.visit_id, sm.returned_movement_id, sm.reference, sm.reason, sm.created_by_user_id, sm.created_at, b.lot_number, i.id AS item_id, i.code AS item_code
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+WHERE ($1::uuid IS NULL OR sm.location_id = $1)
+  AND ($2::uuid IS NULL OR i.id = $2)
+  AND ($3::uuid IS NULL OR sm.batch_id = $3)
+ORDER BY sm.created_at DESC, sm.id
+LIMIT $5
+OFFSET $4
+`
+
+type ListStockMovementsParams struct {
+	LocationID pgtype.UUID
+	ItemID     pgtype.UUID
+	BatchID    pgtype.UUID
+	RowOffset  int32
+	RowLimit   int32
+}
+
+type ListStockMovementsRow struct {
+	ID                 pgtype.UUID
+	MovementType       StockMovementType
+	LocationID         pgtype.UUID
+	BatchID            pgtype.UUID
+	Quantity           int32
+	VisitID            pgtype.UUID
+	ReturnedMovementID pgtype.UUID
+	Reference          pgtype.Text
+	Reason             pgtype.Text
+	CreatedByUserID    pgtype.UUID
+	CreatedAt          pgtype.Timestamptz
+	LotNumber          string
+	ItemID             pgtype.UUID
+	ItemCode           string
+}
+
+func (q *Queries) ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]ListStockMovementsRow, error) {
+	rows, err := q.db.Query(ctx, listStockMovements,
+		arg.LocationID,
+		arg.ItemID,
+		arg.BatchID,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStockMovementsRow
+	for rows.Next() {
+		var i ListStockMovementsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovementType,
+			&i.LocationID,
+			&i.BatchID,
+			&i.Quantity,
+			&i.VisitID,
+			&i.ReturnedMovementID,
+			&i.Reference,
+			&i.Reason,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+			&i.LotNumber,
+			&i.ItemID,
+			&i.ItemCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisitStockMovements = `-- name: ListVisitStockMovements :many
+SELECT sm.id, sm.movement_type, sm.location_id, sm.batch_id, sm.quantity, sm.visit_id, sm.returned_movement_id, sm.reference, sm.reason, sm.created_by_user_id, sm.created_at, b.lot_number, i.id AS item_id, i.code AS item_code
+FROM stock_movements sm
+JOIN pharmacy_batches b ON b.id = sm.batch_id
+JOIN pharmacy_items i ON i.id = b.item_id
+WHERE sm.visit_id = $1
+ORDER BY sm.created_at, sm.id
+`
+
+type ListVisitStockMovementsRow struct {
+	ID                 pgtype.UUID
+	MovementType       StockMovementType
+	LocationID         pgtype.UUID
+	BatchID            pgtype.UUID
+	Quantity           int32
+	VisitID            pgtype.UUID
+	ReturnedMovementID pgtype.UUID
+	Reference          pgtype.Text
+	Reason             pgtype.Text
+	CreatedByUserID    pgtype.UUID
+	CreatedAt          pgtype.Timestamptz
+	LotNumber          string
+	ItemID             pgtype.UUID
+	ItemCode           string
+}
+
+// Dispenses against a visit and the returns made against them.
+func (q *Queries) ListVisitStockMovements(ctx context.Context, visitID pgtype.UUID) ([]ListVisitStockMovementsRow, error) {
+	rows, err := q.db.Query(ctx, listVisitStockMovements, visitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVisitStockMovementsRow
+	for rows.Next() {
+		var i ListVisitStockMovementsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovementType,
+			&i.LocationID,
+			&i.BatchID,
+			&i.Quantity,
+			&i.VisitID,
+			&i.ReturnedMovementID,
+			&i.Reference,
+			&i.Reason,
+			&i.CreatedByUserID,
+			&i.CreatedAt,
+			&i.LotNumber,
+			&i.ItemID,
+			&i.ItemCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockItemStockAtLocation = `-- name: LockItemStockAtLocation :many
+SELECT sl.batch_id, sl.quantity, b.lot_number, b.expiry_date
+FROM stock_levels sl
+JOIN pharmacy_batches b ON b.id = sl.batch_id
+WHERE sl.location_id = $1
+  AND b.item_id = $2
+  AND sl.quantity > 0
+ORDER BY b.expiry_date, b.created_at
+FOR UPDATE OF sl
+`
+
+type LockItemStockAtLocationParams struct {
+	LocationID pgtype.UUID
+	ItemID     pgtype.UUID
+}
+
+type LockItemStockAtLocationRow struct {
+	BatchID    pgtype.UUID
+	Quantity   int32
+	LotNumber  string
+	ExpiryDate pgtype.Date
+}
+
+// Candidate batches for FEFO selection, earliest expiry first.
+func (q *Queries) LockItemStockAtLocation(ctx context.Context, arg LockItemStockAtLocationParams) ([]LockItemStockAtLocationRow, error) {
+	rows, err := q.db.Query(ctx, lockItemStockAtLocation, arg.LocationID, arg.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockItemStockAtLocationRow
+	for rows.Next() {
+		var i LockItemStockAtLocationRow
+		if err := rows.Scan(
+			&i.BatchID,
+			&i.Quantity,
+			&i.LotNumber,
+			&i.ExpiryDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockStockMovement = `-- name: LockStockMovement :one
+SELECT id, movement_type, location_id, batch_id, quantity, visit_id, returned_movement_id, reference, reason, created_by_user_id, created_at FROM stock_movements
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+// Serialises returns against the same dispense.
+func (q *Queries) LockStockMovement(ctx context.Context, id pgtype.UUID) (StockMovement, error) {
+	row := q.db.QueryRow(ctx, lockStockMovement, id)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.MovementType,
+		&i.LocationID,
+		&i.BatchID,
+		&i.Quantity,
+		&i.VisitID,
+		&i.ReturnedMovementID,
+		&i.Reference,
+		&i.Reason,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const sumReturnedQuantity = `-- name: SumReturnedQuantity :one
+SELECT COALESCE(SUM(quantity), 0)::bigint AS returned
+FROM stock_movements
+WHERE returned_movement_id = $1
+`
+
+func (q *Queries) SumReturnedQuantity(ctx context.Context, returnedMovementID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, sumReturnedQuantity, returnedMovementID)
+	var returned int64
+	err := row.Scan(&returned)
+	return returned, err
+}
+
+const upsertPharmacyBatch = `-- name: UpsertPharmacyBatch :one
+INSERT INTO pharmacy_batches (
+    item_id, lot_number, expiry_date
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (item_id, lot_number) DO UPDATE SET lot_number = EXCLUDED.lot_number
+RETURNING id, item_id, lot_number, expiry_date, created_at
+`
+
+type UpsertPharmacyBatchParams struct {
+	ItemID     pgtype.UUID
+	LotNumber  string
+	ExpiryDate pgtype.Date
+}
+
+// Receiving a lot that is already known returns the existing batch unchanged.
+func (q *Queries) UpsertPharmacyBatch(ctx context.Context, arg UpsertPharmacyBatchParams) (PharmacyBatch, error) {
+	row := q.db.QueryRow(ctx, upsertPharmacyBatch, arg.ItemID, arg.LotNumber, arg.ExpiryDate)
+	var i PharmacyBatch
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.LotNumber,
+		&i.ExpiryDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
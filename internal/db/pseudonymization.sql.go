@@ -0,0 +1,293 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: pseudonymization.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countPseudonymizationRequests = `-- name: CountPseudonymizationRequests :one
+SELECT COUNT(*) FROM pseudonymization_requests
+WHERE $1::pseudonymization_status IS NULL OR status = $1::pseudonymization_status
+`
+
+func (q *Queries) CountPseudonymizationRequests(ctx context.Context, status NullPseudonymizationStatus) (int64, error) {
+	row := q.db.QueryRow(ctx, countPseudonymizationRequests, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPseudonymizationRequest = `-- name: CreatePseudonymizationRequest :one
+INSERT INTO pseudonymization_requests (
+    patient_id, requested_by_user_id, reason
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, patient_id, requested_by_user_id, reason, status, decided_by_user_id, decided_at, decision_note, created_at
+`
+
+type CreatePseudonymizationRequestParams struct {
+	PatientID         pgtype.UUID
+	RequestedByUserID pgtype.UUID
+	Reason            string
+}
+
+func (q *Queries) CreatePseudonymizationRequest(ctx context.Context, arg CreatePseudonymizationRequestParams) (PseudonymizationRequest, error) {
+	row := q.db.QueryRow(ctx, createPseudonymizationRequest, arg.PatientID, arg.RequestedByUserID, arg.Reason)
+	var i PseudonymizationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedByUserID,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const decidePseudonymizationRequest = `-- name: DecidePseudonymizationRequest :one
+UPDATE pseudonymization_requests
+SET
+    status = $1,
+    decided_by_user_id = $2,
+    decided_at = NOW(),
+    decision_note = $3
+WHERE id = $4 AND status = 'pending'
+RETURNING id, patient_id, requested_by_user_id, reason, status, decided_by_user_id, decided_at, decision_note, created_at
+`
+
+type DecidePseudonymizationRequestParams struct {
+	Status          PseudonymizationStatus
+	DecidedByUserID pgtype.UUID
+	DecisionNote    pgtype.Text
+	ID              pgtype.UUID
+}
+
+func (q *Queries) DecidePseudonymizationRequest(ctx context.Context, arg DecidePseudonymizationRequestParams) (PseudonymizationRequest, error) {
+	row := q.db.QueryRow(ctx, decidePseudonymizationRequest,
+		arg.Status,
+		arg.DecidedByUserID,
+		arg.DecisionNote,
+		arg.ID,
+	)
+	var i PseudonymizationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedByUserID,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPseudonymizationRequest = `-- name: GetPseudonymizationRequest :one
+SELECT id, patient_id, requested_by_user_id, reason, status, decided_by_user_id, decided_at, decision_note, created_at FROM pseudonymization_requests
+WHERE id = $1
+`
+
+func (q *Queries) GetPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (PseudonymizationRequest, error) {
+	row := q.db.QueryRow(ctx, getPseudonymizationRequest, id)
+	var i PseudonymizationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedByUserID,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPseudonymizationRequests = `-- name: ListPseudonymizationRequests :many
+SELECT id, patient_id, requested_by_user_id, reason, status, decided_by_user_id, decided_at, decision_note, created_at FROM pseudonymization_requests
+WHERE $1::pseudonymization_status IS NULL OR status = $1::pseudonymization_status
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListPseudonymizationRequestsParams struct {
+	Status    NullPseudonymizationStatus
+	RowOffset int32
+	RowLimit  int32
+}
+
+func (q *Queries) ListPseudonymizationRequests(ctx context.Context, arg ListPseudonymizationRequestsParams) ([]PseudonymizationRequest, error) {
+	rows, err := q.db.Query(ctx, listPseudonymizationRequests, arg.Status, arg.RowOffset, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PseudonymizationRequest
+	for rows.Next() {
+		var i PseudonymizationRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.RequestedByUserID,
+			&i.Reason,
+			&i.Status,
+			&i.DecidedByUserID,
+			&i.DecidedAt,
+			&i.DecisionNote,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPatient = `-- name: LockPatient :one
+SELECT id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version FROM patients
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPatient(ctx context.Context, id pgtype.UUID) (Patient, error) {
+	row := q.db.QueryRow(ctx, lockPatient, id)
+	var i Patient
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.DateOfBirth,
+		&i.Gender,
+		&i.ContactPhone,
+		&i.ContactEmail,
+		&i.Address,
+		&i.MedicalHistory,
+		&i.RegisteredByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const lockPseudonymizationRequest = `-- name: LockPseudonymizationRequest :one
+SELECT id, patient_id, requested_by_user_id, reason, status, decided_by_user_id, decided_at, decision_note, created_at FROM pseudonymization_requests
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (PseudonymizationRequest, error) {
+	row := q.db.QueryRow(ctx, lockPseudonymizationRequest, id)
+	var i PseudonymizationRequest
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.RequestedByUserID,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedByUserID,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const pseudonymizePatient = `-- name: PseudonymizePatient :one
+UPDATE patients
+SET
+    first_name = $1,
+    last_name = $2,
+    date_of_birth = $3,
+    contact_phone = NULL,
+    contact_phone_bidx = NULL,
+    contact_email = NULL,
+    contact_email_bidx = NULL,
+    address = NULL,
+    pseudonymized_at = NOW(),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $4 AND pseudonymized_at IS NULL
+RETURNING id, first_name, last_name, date_of_birth, gender, contact_phone, contact_email, address, medical_history, registered_by_user_id, created_at, updated_at, deleted_at, contact_phone_bidx, contact_email_bidx, pseudonymized_at, version
+`
+
+type PseudonymizePatientParams struct {
+	FirstName   string
+	LastName    string
+	DateOfBirth pgtype.Date
+	ID          pgtype.UUID
+}
+
+// Replaces the direct identifiers. Clinical fields and the row ID are kept.
+func (q *Queries) PseudonymizePatient(ctx context.Context, arg PseudonymizePatientParams) (Patient, error) {
+	row := q.db.QueryRow(ctx, pseudonymizePatient,
+		arg.FirstName,
+		arg.LastName,
+		arg.DateOfBirth,
+		arg.ID,
+	)
+	var i Patient
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.DateOfBirth,
+		&i.Gender,
+		&i.ContactPhone,
+		&i.ContactEmail,
+		&i.Address,
+		&i.MedicalHistory,
+		&i.RegisteredByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ContactPhoneBidx,
+		&i.ContactEmailBidx,
+		&i.PseudonymizedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const pseudonymizePatientPolicies = `-- name: PseudonymizePatientPolicies :execrows
+UPDATE insurance_policies
+SET
+    member_id = $1,
+    group_number = NULL
+WHERE patient_id = $2
+`
+
+type PseudonymizePatientPoliciesParams struct {
+	MemberID  string
+	PatientID pgtype.UUID
+}
+
+// Member IDs are required, so they take the patient's token; group numbers are cleared.
+func (q *Queries) PseudonymizePatientPolicies(ctx context.Context, arg PseudonymizePatientPoliciesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pseudonymizePatientPolicies, arg.MemberID, arg.PatientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
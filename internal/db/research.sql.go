@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: research.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listResearchPatients = `-- name: ListResearchPatients :many
+SELECT p.id, p.first_name, p.last_name, p.date_of_birth, p.gender, p.contact_phone, p.contact_email, p.address, p.medical_history, p.registered_by_user_id, p.created_at, p.updated_at, p.deleted_at, p.contact_phone_bidx, p.contact_email_bidx, p.pseudonymized_at, p.version FROM patients p
+WHERE p.deleted_at IS NULL
+  AND p.id > $1::uuid
+  AND EXISTS (
+    SELECT 1 FROM consent_records cr
+    WHERE cr.patient_id = p.id AND cr.consent_type = 'research' AND cr.withdrawn_at IS NULL
+  )
+ORDER BY p.id
+LIMIT $2
+`
+
+type ListResearchPatientsParams struct {
+	AfterID  pgtype.UUID
+	RowLimit int32
+}
+
+// Patients with research consent in force, in ID order for keyset paging.
+func (q *Queries) ListResearchPatients(ctx context.Context, arg ListResearchPatientsParams) ([]Patient, error) {
+	rows, err := q.db.Query(ctx, listResearchPatients, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Patient
+	for rows.Next() {
+		var i Patient
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.DateOfBirth,
+			&i.Gender,
+			&i.ContactPhone,
+			&i.ContactEmail,
+			&i.Address,
+			&i.MedicalHistory,
+			&i.RegisteredByUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ContactPhoneBidx,
+			&i.ContactEmailBidx,
+			&i.PseudonymizedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisitsForPatients = `-- name: ListVisitsForPatients :many
+SELECT id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id FROM patient_visits
+WHERE patient_id = ANY($1::uuid[])
+ORDER BY patient_id, visit_date, id
+`
+
+func (q *Queries) ListVisitsForPatients(ctx context.Context, patientIds []pgtype.UUID) ([]PatientVisit, error) {
+	rows, err := q.db.Query(ctx, listVisitsForPatients, patientIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PatientVisit
+	for rows.Next() {
+		var i PatientVisit
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientID,
+			&i.DoctorID,
+			&i.VisitDate,
+			&i.Symptoms,
+			&i.Diagnosis,
+			&i.Prescription,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.FinalizedAt,
+			&i.FinalizedByUserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
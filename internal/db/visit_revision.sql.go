@@ -0,0 +1,263 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: visit_revision.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPatientVisitRevision = `-- name: CreatePatientVisitRevision :one
+INSERT INTO patient_visit_revisions (
+    visit_id, version, kind, reason, author_user_id,
+    previous_visit_date, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, visit_id, version, kind, reason, author_user_id, revised_at, previous_visit_date, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes
+`
+
+type CreatePatientVisitRevisionParams struct {
+	VisitID              pgtype.UUID
+	Version              int32
+	Kind                 VisitRevisionKind
+	Reason               pgtype.Text
+	AuthorUserID         pgtype.UUID
+	PreviousVisitDate    pgtype.Timestamptz
+	PreviousSymptoms     pgtype.Text
+	PreviousDiagnosis    pgtype.Text
+	PreviousPrescription pgtype.Text
+	PreviousNotes        pgtype.Text
+}
+
+func (q *Queries) CreatePatientVisitRevision(ctx context.Context, arg CreatePatientVisitRevisionParams) (PatientVisitRevision, error) {
+	row := q.db.QueryRow(ctx, createPatientVisitRevision,
+		arg.VisitID,
+		arg.Version,
+		arg.Kind,
+		arg.Reason,
+		arg.AuthorUserID,
+		arg.PreviousVisitDate,
+		arg.PreviousSymptoms,
+		arg.PreviousDiagnosis,
+		arg.PreviousPrescription,
+		arg.PreviousNotes,
+	)
+	var i PatientVisitRevision
+	err := row.Scan(
+		&i.ID,
+		&i.VisitID,
+		&i.Version,
+		&i.Kind,
+		&i.Reason,
+		&i.AuthorUserID,
+		&i.RevisedAt,
+		&i.PreviousVisitDate,
+		&i.PreviousSymptoms,
+		&i.PreviousDiagnosis,
+		&i.PreviousPrescription,
+		&i.PreviousNotes,
+	)
+	return i, err
+}
+
+const finalizePatientVisit = `-- name: FinalizePatientVisit :one
+UPDATE patient_visits
+SET
+    finalized_at = NOW(),
+    finalized_by_user_id = $1,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $2 AND finalized_at IS NULL AND version = $3
+RETURNING id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id
+`
+
+type FinalizePatientVisitParams struct {
+	FinalizedByUserID pgtype.UUID
+	ID                pgtype.UUID
+	ExpectedVersion   int32
+}
+
+func (q *Queries) FinalizePatientVisit(ctx context.Context, arg FinalizePatientVisitParams) (PatientVisit, error) {
+	row := q.db.QueryRow(ctx, finalizePatientVisit, arg.FinalizedByUserID, arg.ID, arg.ExpectedVersion)
+	var i PatientVisit
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.DoctorID,
+		&i.VisitDate,
+		&i.Symptoms,
+		&i.Diagnosis,
+		&i.Prescription,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.FinalizedAt,
+		&i.FinalizedByUserID,
+	)
+	return i, err
+}
+
+const listPatientVisitRevisions = `-- name: ListPatientVisitRevisions :many
+SELECT id, visit_id, version, kind, reason, author_user_id, revised_at, previous_visit_date, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes FROM patient_visit_revisions
+WHERE visit_id = $1
+ORDER BY version
+`
+
+func (q *Queries) ListPatientVisitRevisions(ctx context.Context, visitID pgtype.UUID) ([]PatientVisitRevision, error) {
+	rows, err := q.db.Query(ctx, listPatientVisitRevisions, visitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PatientVisitRevision
+	for rows.Next() {
+		var i PatientVisitRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitID,
+			&i.Version,
+			&i.Kind,
+			&i.Reason,
+			&i.AuthorUserID,
+			&i.RevisedAt,
+			&i.PreviousVisitDate,
+			&i.PreviousSymptoms,
+			&i.PreviousDiagnosis,
+			&i.PreviousPrescription,
+			&i.PreviousNotes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientVisitRevisionsForReencryption = `-- name: ListPatientVisitRevisionsForReencryption :many
+SELECT id, previous_symptoms, previous_diagnosis, previous_prescription, previous_notes
+FROM patient_visit_revisions
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListPatientVisitRevisionsForReencryptionParams struct {
+	AfterID  pgtype.UUID
+	RowLimit int32
+}
+
+type ListPatientVisitRevisionsForReencryptionRow struct {
+	ID                   pgtype.UUID
+	PreviousSymptoms     pgtype.Text
+	PreviousDiagnosis    pgtype.Text
+	PreviousPrescription pgtype.Text
+	PreviousNotes        pgtype.Text
+}
+
+func (q *Queries) ListPatientVisitRevisionsForReencryption(ctx context.Context, arg ListPatientVisitRevisionsForReencryptionParams) ([]ListPatientVisitRevisionsForReencryptionRow, error) {
+	rows, err := q.db.Query(ctx, listPatientVisitRevisionsForReencryption, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPatientVisitRevisionsForReencryptionRow
+	for rows.Next() {
+		var i ListPatientVisitRevisionsForReencryptionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PreviousSymptoms,
+			&i.PreviousDiagnosis,
+			&i.PreviousPrescription,
+			&i.PreviousNotes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPatientVisit = `-- name: LockPatientVisit :one
+SELECT id, patient_id, doctor_id, visit_date, symptoms, diagnosis, prescription, notes, created_at, updated_at, version, finalized_at, finalized_by_user_id FROM patient_visits
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPatientVisit(ctx context.Context, id pgtype.UUID) (PatientVisit, error) {
+	row := q.db.QueryRow(ctx, lockPatientVisit, id)
+	var i PatientVisit
+	err := row.Scan(
+		&i.ID,
+		&i.PatientID,
+		&i.DoctorID,
+		&i.VisitDate,
+		&i.Symptoms,
+		&i.Diagnosis,
+		&i.Prescription,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.FinalizedAt,
+		&i.FinalizedByUserID,
+	)
+	return i, err
+}
+
+const reencryptPatientVisitRevision = `-- name: ReencryptPatientVisitRevision :execrows
+UPDATE patient_visit_revisions
+SET
+    previous_symptoms = $1,
+    previous_diagnosis = $2,
+    previous_prescription = $3,
+    previous_notes = $4
+WHERE id = $5
+  AND previous_symptoms IS NOT DISTINCT FROM $6
+  AND previous_diagnosis IS NOT DISTINCT FROM $7
+  AND previous_prescription IS NOT DISTINCT FROM $8
+  AND previous_notes IS NOT DISTINCT FROM $9
+`
+
+type ReencryptPatientVisitRevisionParams struct {
+	PreviousSymptoms     pgtype.Text
+	PreviousDiagnosis    pgtype.Text
+	PreviousPrescription pgtype.Text
+	PreviousNotes        pgtype.Text
+	ID                   pgtype.UUID
+	ReadSymptoms         pgtype.Text
+	ReadDiagnosis        pgtype.Text
+	ReadPrescription     pgtype.Text
+	ReadNotes            pgtype.Text
+}
+
+// Revisions never change otherwise, so the row is rewritten only if the ciphertext read
+// is still there.
+func (q *Queries) ReencryptPatientVisitRevision(ctx context.Context, arg ReencryptPatientVisitRevisionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reencryptPatientVisitRevision,
+		arg.PreviousSymptoms,
+		arg.PreviousDiagnosis,
+		arg.PreviousPrescription,
+		arg.PreviousNotes,
+		arg.ID,
+		arg.ReadSymptoms,
+		arg.ReadDiagnosis,
+		arg.ReadPrescription,
+		arg.ReadNotes,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
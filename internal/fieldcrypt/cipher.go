@@ -0,0 +1,236 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Prefix marks an encrypted value. Values without it are legacy plaintext, returned as
+// is by Decrypt until the re-encryption command has rewritten them.
+const Prefix = "enc:v1:"
+
+// dataKeyMaxUses bounds how many values one data key seals, well inside the random
+// nonce limit of AES-GCM.
+const dataKeyMaxUses = 1 << 24
+
+// maxCachedDataKeys bounds the unwrapped data key cache. Each process start creates a
+// data key, so old rows can carry many.
+const maxCachedDataKeys = 4096
+
+var ErrMalformedCiphertext = errors.New("malformed encrypted value")
+
+type dataKey struct {
+	version uint32
+	aead    cipher.AEAD
+	wrapped []byte
+	uses    int
+}
+
+// Cipher encrypts and decrypts column values. It is safe for concurrent use.
+type Cipher struct {
+	keys     KeyProvider
+	indexKey []byte
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped map[string]cipher.AEAD
+}
+
+func NewCipher(keys KeyProvider, blindIndexKey []byte) (*Cipher, error) {
+	if len(blindIndexKey) != KeySize {
+		return nil, fmt.Errorf("blind index key must be %d bytes, got %d", KeySize, len(blindIndexKey))
+	}
+	return &Cipher{keys: keys, indexKey: blindIndexKey, unwrapped: map[string]cipher.AEAD{}}, nil
+}
+
+// Encrypt seals plaintext for the named column, e.g. "patients.medical_history". The
+// column name is authenticated, so a value copied into another column will not decrypt.
+func (c *Cipher) Encrypt(ctx context.Context, field, plaintext string) (string, error) {
+	sealed, err := c.EncryptBytes(ctx, field, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt for the same column. Plaintext values are
+// returned unchanged.
+func (c *Cipher) Decrypt(ctx context.Context, field, value string) (string, error) {
+	if !strings.HasPrefix(value, Prefix) {
+		return value, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", ErrMalformedCiphertext
+	}
+	plaintext, err := c.DecryptBytes(ctx, field, raw)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// EncryptBytes is Encrypt for binary values such as files. The result has no prefix and
+// is not text.
+func (c *Cipher) EncryptBytes(ctx context.Context, field string, plaintext []byte) ([]byte, error) {
+	key, err := c.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(key.aead, plaintext, []byte(field))
+	if err != nil {
+		return nil, err
+	}
+	out := binary.BigEndian.AppendUint32(nil, key.version)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(key.wrapped)))
+	out = append(out, key.wrapped...)
+	return append(out, sealed...), nil
+}
+
+// DecryptBytes opens a value sealed by EncryptBytes for the same field.
+func (c *Cipher) DecryptBytes(ctx context.Context, field string, data []byte) ([]byte, error) {
+	version, wrapped, sealed, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.unwrap(ctx, version, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(aead, sealed, []byte(field))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", field, err)
+	}
+	return plaintext, nil
+}
+
+// NeedsReencryption reports whether value is plaintext or wrapped with a master key
+// other than the active one.
+func (c *Cipher) NeedsReencryption(value string) bool {
+	if !strings.HasPrefix(value, Prefix) {
+		return true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return true
+	}
+	version, _, _, err := parse(raw)
+	return err != nil || version != c.keys.ActiveVersion()
+}
+
+// parse splits a sealed value into its master key version, wrapped data key and
+// nonce-prefixed ciphertext.
+func parse(raw []byte) (version uint32, wrapped, sealed []byte, err error) {
+	if len(raw) < 6 {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	version = binary.BigEndian.Uint32(raw)
+	n := int(binary.BigEndian.Uint16(raw[4:]))
+	if len(raw) < 6+n {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	return version, raw[6 : 6+n], raw[6+n:], nil
+}
+
+// dataKey returns the data key to seal with, creating one under the active master key
+// when there is none yet, the master key changed, or the current one is used up.
+func (c *Cipher) dataKey(ctx context.Context) (*dataKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.keys.ActiveVersion()
+	if c.current == nil || c.current.version != active || c.current.uses >= dataKeyMaxUses {
+		plain := make([]byte, KeySize)
+		if _, err := rand.Read(plain); err != nil {
+			return nil, fmt.Errorf("generate data key: %w", err)
+		}
+		wrapped, err := c.keys.WrapKey(ctx, active, plain)
+		if err != nil {
+			return nil, fmt.Errorf("wrap data key: %w", err)
+		}
+		aead, err := newAEAD(plain)
+		if err != nil {
+			return nil, err
+		}
+		c.current = &dataKey{version: active, aead: aead, wrapped: wrapped}
+	}
+	c.current.uses++
+	return c.current, nil
+}
+
+func (c *Cipher) unwrap(ctx context.Context, version uint32, wrapped []byte) (cipher.AEAD, error) {
+	cacheKey := string(binary.BigEndian.AppendUint32(nil, version)) + string(wrapped)
+	c.mu.Lock()
+	aead, ok := c.unwrapped[cacheKey]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	plain, err := c.keys.UnwrapKey(ctx, version, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	if aead, err = newAEAD(plain); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if len(c.unwrapped) >= maxCachedDataKeys {
+		c.unwrapped = map[string]cipher.AEAD{}
+	}
+	c.unwrapped[cacheKey] = aead
+	c.mu.Unlock()
+	return aead, nil
+}
+
+// IndexKind selects how a value is normalised before it is blind indexed.
+type IndexKind string
+
+const (
+	IndexPhone IndexKind = "phone"
+	IndexEmail IndexKind = "email"
+)
+
+// BlindIndex returns a keyed hash of the normalised value, stored next to the encrypted
+// column so equality lookups and uniqueness still work without decrypting. It returns
+// "" when nothing is left to index after normalising.
+func (c *Cipher) BlindIndex(kind IndexKind, value string) string {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Normalize maps values that should match to the same string: emails are trimmed and
+// lower-cased, phone numbers keep only their digits and a leading "+".
+func Normalize(kind IndexKind, value string) string {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case IndexEmail:
+		return strings.ToLower(value)
+	case IndexPhone:
+		var b strings.Builder
+		for i, r := range value {
+			if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	default:
+		return value
+	}
+}
@@ -0,0 +1,115 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func testCipher(t *testing.T, active uint32, masters map[string]string) *Cipher {
+	t.Helper()
+	keys, err := newLocalKeys(keyFile{ActiveVersion: active, MasterKeys: masters, BlindIndexKey: testKey(9)})
+	require.NoError(t, err)
+	c, err := NewCipher(keys, keys.BlindIndexKey())
+	require.NoError(t, err)
+	return c
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, 1, map[string]string{"1": testKey(1)})
+
+	sealed, err := c.Encrypt(ctx, "patients.medical_history", "asthma since 2019")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, Prefix))
+	assert.NotContains(t, sealed, "asthma")
+
+	again, err := c.Encrypt(ctx, "patients.medical_history", "asthma since 2019")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "nonces must differ")
+
+	plain, err := c.Decrypt(ctx, "patients.medical_history", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "asthma since 2019", plain)
+}
+
+func TestEncryptBytesRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, 1, map[string]string{"1": testKey(1)})
+
+	data := []byte{0x50, 0x4b, 0x03, 0x04, 0x00, 0xff}
+	sealed, err := c.EncryptBytes(ctx, "exports.archive", data)
+	require.NoError(t, err)
+	plain, err := c.DecryptBytes(ctx, "exports.archive", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, data, plain)
+
+	_, err = c.DecryptBytes(ctx, "exports.archive", sealed[:3])
+	assert.ErrorIs(t, err, ErrMalformedCiphertext)
+}
+
+func TestDecryptRejectsOtherColumn(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, 1, map[string]string{"1": testKey(1)})
+
+	sealed, err := c.Encrypt(ctx, "patient_visits.diagnosis", "J45")
+	require.NoError(t, err)
+	_, err = c.Decrypt(ctx, "patient_visits.notes", sealed)
+	assert.Error(t, err)
+}
+
+func TestDecryptPassesPlaintextThrough(t *testing.T) {
+	c := testCipher(t, 1, map[string]string{"1": testKey(1)})
+	plain, err := c.Decrypt(context.Background(), "patient_visits.notes", "legacy note")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy note", plain)
+	assert.True(t, c.NeedsReencryption("legacy note"))
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	old := testCipher(t, 1, map[string]string{"1": testKey(1)})
+	sealed, err := old.Encrypt(ctx, "patients.contact_email", "a@example.com")
+	require.NoError(t, err)
+	assert.False(t, old.NeedsReencryption(sealed))
+
+	rotated := testCipher(t, 2, map[string]string{"1": testKey(1), "2": testKey(2)})
+	assert.True(t, rotated.NeedsReencryption(sealed))
+	plain, err := rotated.Decrypt(ctx, "patients.contact_email", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "a@example.com", plain)
+
+	resealed, err := rotated.Encrypt(ctx, "patients.contact_email", plain)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsReencryption(resealed))
+
+	retired := testCipher(t, 2, map[string]string{"2": testKey(2)})
+	_, err = retired.Decrypt(ctx, "patients.contact_email", sealed)
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+}
+
+func TestLoadKeysRejectsMissingActiveVersion(t *testing.T) {
+	_, err := newLocalKeys(keyFile{ActiveVersion: 2, MasterKeys: map[string]string{"1": testKey(1)}, BlindIndexKey: testKey(9)})
+	assert.Error(t, err)
+
+	_, err = newLocalKeys(keyFile{ActiveVersion: 1, MasterKeys: map[string]string{"1": "c2hvcnQ="}, BlindIndexKey: testKey(9)})
+	assert.Error(t, err)
+}
+
+func TestBlindIndexNormalises(t *testing.T) {
+	c := testCipher(t, 1, map[string]string{"1": testKey(1)})
+
+	assert.Equal(t, c.BlindIndex(IndexEmail, "Asha@Example.com "), c.BlindIndex(IndexEmail, "asha@example.com"))
+	assert.Equal(t, c.BlindIndex(IndexPhone, "+91 98765-43210"), c.BlindIndex(IndexPhone, "+919876543210"))
+	assert.NotEqual(t, c.BlindIndex(IndexPhone, "12345"), c.BlindIndex(IndexEmail, "12345"))
+	assert.Empty(t, c.BlindIndex(IndexPhone, " - "))
+}
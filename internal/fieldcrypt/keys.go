@@ -0,0 +1,168 @@
+// Package fieldcrypt encrypts individual column values at rest. Each value is sealed
+// with AES-256-GCM under a data key, and the data key is wrapped by a versioned master
+// key held by a KeyProvider. Rotating the master key only needs old values re-sealed,
+// which can happen while the application keeps serving.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// KeySize is the length in bytes of master, data and blind index keys.
+const KeySize = 32
+
+var ErrUnknownKeyVersion = errors.New("unknown master key version")
+
+// KeyProvider wraps and unwraps data keys under a master key. LocalKeys keeps the master
+// keys in a file; a KMS client can implement the same interface.
+type KeyProvider interface {
+	// ActiveVersion is the master key version new data keys are wrapped with.
+	ActiveVersion() uint32
+	WrapKey(ctx context.Context, version uint32, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, version uint32, wrapped []byte) ([]byte, error)
+}
+
+// keyFile is the JSON layout of a local key file. Master keys and the blind index key
+// are base64 encoded 32 byte keys.
+type keyFile struct {
+	ActiveVersion uint32            `json:"active_version"`
+	MasterKeys    map[string]string `json:"master_keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// LocalKeys is a KeyProvider backed by master keys loaded from a file.
+type LocalKeys struct {
+	active        uint32
+	masters       map[uint32]cipher.AEAD
+	blindIndexKey []byte
+}
+
+// LoadKeyFile reads a key file such as
+//
+//	{"active_version": 2, "master_keys": {"1": "...", "2": "..."}, "blind_index_key": "..."}
+//
+// Old master key versions must stay in the file until every value wrapped with them has
+// been re-encrypted.
+func LoadKeyFile(path string) (*LocalKeys, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	var f keyFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse key file: %w", err)
+	}
+	return newLocalKeys(f)
+}
+
+func newLocalKeys(f keyFile) (*LocalKeys, error) {
+	keys := &LocalKeys{active: f.ActiveVersion, masters: make(map[uint32]cipher.AEAD, len(f.MasterKeys))}
+	for name, encoded := range f.MasterKeys {
+		version, err := strconv.ParseUint(name, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("master key version %q must be a positive integer", name)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %d: %w", version, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		keys.masters[uint32(version)] = aead
+	}
+	if _, ok := keys.masters[keys.active]; !ok {
+		return nil, fmt.Errorf("active master key version %d is not in the key file", keys.active)
+	}
+	indexKey, err := decodeKey(f.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	keys.blindIndexKey = indexKey
+	return keys, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+func (k *LocalKeys) ActiveVersion() uint32 { return k.active }
+
+// BlindIndexKey is the HMAC key for blind indexes. It is not versioned: changing it
+// means recomputing every index.
+func (k *LocalKeys) BlindIndexKey() []byte { return k.blindIndexKey }
+
+func (k *LocalKeys) WrapKey(_ context.Context, version uint32, dataKey []byte) ([]byte, error) {
+	aead, ok := k.masters[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, version)
+	}
+	return seal(aead, dataKey, wrapAAD(version))
+}
+
+func (k *LocalKeys) UnwrapKey(_ context.Context, version uint32, wrapped []byte) ([]byte, error) {
+	aead, ok := k.masters[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, version)
+	}
+	return open(aead, wrapped, wrapAAD(version))
+}
+
+// wrapAAD binds a wrapped data key to the master key version it claims.
+func wrapAAD(version uint32) []byte {
+	aad := []byte("hms-data-key:")
+	return binary.BigEndian.AppendUint32(aad, version)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, body := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, body, aad)
+}
+
+// NewCipherFromKeyFile loads a local key file and returns a Cipher using its master keys
+// and blind index key.
+func NewCipherFromKeyFile(path string) (*Cipher, error) {
+	keys, err := LoadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(keys, keys.BlindIndexKey())
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type AlertHandler struct {
+	alertService service.AlertService
+	logger       *slog.Logger
+}
+
+func NewAlertHandler(alertService service.AlertService, logger *slog.Logger) *AlertHandler {
+	return &AlertHandler{alertService: alertService, logger: logger.With("handler", "AlertHandler")}
+}
+
+// writeAlertError maps alert service errors to HTTP responses.
+func writeAlertError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrAlertNotFound),
+		errors.Is(err, service.ErrAlertRuleNotFound),
+		errors.Is(err, service.ErrVisitNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrAlertAcknowledgeForbidden):
+		writeError(c, http.StatusForbidden, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrAlertAlreadyAcknowledged):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// RecordObservation godoc
+// @Summary Record a vital sign on a visit
+// @Description Stores a numeric observation and evaluates it against the critical alert rules. A breach raises an alert for the visit's doctor, returned in the `alert` field. Doctors record on visits of patients in their care; others get 404.
+// @Tags Alerts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Param request body model.ObservationCreateRequest true "Observation"
+// @Success 201 {object} model.Observation
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /visits/{id}/observations [post]
+func (h *AlertHandler) RecordObservation(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	var req model.ObservationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	observation, err := h.alertService.RecordObservation(c.Request.Context(), visitID, req, userID)
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to record observation")
+		return
+	}
+	c.JSON(http.StatusCreated, observation)
+}
+
+// CreateAlertRule godoc
+// @Summary Configure a critical alert rule
+// @Description Doctors can add a critical low/high threshold for an observation or analyte code, optionally narrowed by sex and age. The most specific matching rule is used.
+// @Tags Alerts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.AlertRuleCreateRequest true "Alert rule"
+// @Success 201 {object} model.AlertRule
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /alerts/rules [post]
+func (h *AlertHandler) CreateAlertRule(c *gin.Context) {
+	var req model.AlertRuleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	if req.MinAgeYears != nil && req.MaxAgeYears != nil && *req.MinAgeYears > *req.MaxAgeYears {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "min_age_years must not exceed max_age_years"})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	rule, err := h.alertService.CreateAlertRule(c.Request.Context(), req, userID)
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to create alert rule")
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// ListAlertRules godoc
+// @Summary List active critical alert rules
+// @Tags Alerts
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.AlertRule
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /alerts/rules [get]
+func (h *AlertHandler) ListAlertRules(c *gin.Context) {
+	rules, err := h.alertService.ListAlertRules(c.Request.Context())
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to list alert rules")
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// DeactivateAlertRule godoc
+// @Summary Deactivate a critical alert rule
+// @Tags Alerts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Alert rule ID (UUID)" Format(uuid)
+// @Success 200 {object} model.AlertRule
+// @Failure 400 {object} model.APIError "Invalid alert rule ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Alert rule not found"
+// @Router /alerts/rules/{id} [delete]
+func (h *AlertHandler) DeactivateAlertRule(c *gin.Context) {
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid alert rule ID format"})
+		return
+	}
+	rule, err := h.alertService.DeactivateAlertRule(c.Request.Context(), ruleID)
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to deactivate alert rule")
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// ListAlerts godoc
+// @Summary List alerts assigned to the current doctor
+// @Description Returns critical alerts currently assigned to the authenticated doctor, newest first.
+// @Tags Alerts
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Alert status" Enums(open, acknowledged)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.Alert}
+// @Failure 400 {object} model.APIError "Invalid status or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /alerts [get]
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	var status *model.AlertStatus
+	if raw := c.Query("status"); raw != "" {
+		s := model.AlertStatus(raw)
+		if s != model.AlertStatusOpen && s != model.AlertStatusAcknowledged {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid alert status"})
+			return
+		}
+		status = &s
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	alerts, total, err := h.alertService.ListAlerts(c.Request.Context(), userID, status, params)
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to list alerts")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: alerts, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// AcknowledgeAlert godoc
+// @Summary Acknowledge a critical alert
+// @Description The assigned doctor or the visit's original doctor acknowledges the alert, which stops escalation.
+// @Tags Alerts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Alert ID (UUID)" Format(uuid)
+// @Success 200 {object} model.Alert
+// @Failure 400 {object} model.APIError "Invalid alert ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Not assigned to this alert"
+// @Failure 404 {object} model.APIError "Alert not found"
+// @Failure 409 {object} model.APIError "Alert already acknowledged"
+// @Router /alerts/{id}/acknowledge [post]
+func (h *AlertHandler) AcknowledgeAlert(c *gin.Context) {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid alert ID format"})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	alert, err := h.alertService.AcknowledgeAlert(c.Request.Context(), alertID, userID)
+	if err != nil {
+		writeAlertError(c, h.logger, err, "Failed to acknowledge alert")
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+	logger       *slog.Logger
+}
+
+func NewAuditHandler(auditService service.AuditService, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{auditService: auditService, logger: logger.With("handler", "AuditHandler")}
+}
+
+// optionalTimeQuery parses an RFC3339 query parameter. It writes a 400 response and
+// returns false when the value is malformed.
+func optionalTimeQuery(c *gin.Context, name string) (*time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid " + name + " format, expected RFC3339"})
+		return nil, false
+	}
+	return &t, true
+}
+
+// ListEvents godoc
+// @Summary Search the audit trail
+// @Description Admins only. Events are returned newest first.
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Param actor_user_id query string false "Acting user ID (UUID)" Format(uuid)
+// @Param patient_id query string false "Patient ID (UUID)" Format(uuid)
+// @Param resource_type query string false "Resource type" Enums(patient, visit, user)
+// @Param resource_id query string false "Resource ID (UUID)" Format(uuid)
+// @Param action query string false "Action" Enums(create, read, list, update, delete, login, login_failed)
+// @Param from query string false "Earliest occurrence (RFC3339)"
+// @Param to query string false "Latest occurrence (RFC3339)"
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.AuditEvent}
+// @Failure 400 {object} model.APIError "Invalid filter or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /audit/events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	var filter model.AuditEventFilter
+	if filter.ActorUserID, ok = optionalUUIDQuery(c, "actor_user_id"); !ok {
+		return
+	}
+	if filter.PatientID, ok = optionalUUIDQuery(c, "patient_id"); !ok {
+		return
+	}
+	if filter.ResourceID, ok = optionalUUIDQuery(c, "resource_id"); !ok {
+		return
+	}
+	if filter.From, ok = optionalTimeQuery(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = optionalTimeQuery(c, "to"); !ok {
+		return
+	}
+	if v := c.Query("resource_type"); v != "" {
+		filter.ResourceType = &v
+	}
+	if v := c.Query("action"); v != "" {
+		action := model.AuditAction(v)
+		filter.Action = &action
+	}
+
+	events, total, err := h.auditService.ListEvents(c.Request.Context(), filter, params)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "List audit events failed", "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to list audit events"})
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: events, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// VerifyChain godoc
+// @Summary Verify the audit trail has not been tampered with
+// @Description Admins only. Re-computes every event hash from the first event and reports the first broken link, if any.
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.AuditChainVerification
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /audit/verify [get]
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	result, err := h.auditService.VerifyChain(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Verify audit chain failed", "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to verify audit trail"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// accessLogExportPageSize is how many entries the CSV export loads at a time.
+const accessLogExportPageSize = 500
+
+var accessLogCSVHeader = []string{"occurred_at", "actor_user_id", "actor_username", "actor_role", "action", "resource_type", "resource_id", "fields", "client_ip", "break_glass_grant_id"}
+
+// csvCell neutralises values a spreadsheet would otherwise evaluate as a formula.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func accessLogCSVRecord(e model.AccessLogEntry) []string {
+	var actorID, resourceID, grantID string
+	if e.ActorUserID != nil {
+		actorID = e.ActorUserID.String()
+	}
+	if e.ResourceID != nil {
+		resourceID = e.ResourceID.String()
+	}
+	if e.BreakGlassGrantID != nil {
+		grantID = e.BreakGlassGrantID.String()
+	}
+	return []string{
+		e.OccurredAt.UTC().Format(time.RFC3339),
+		actorID,
+		csvCell(derefText(e.ActorUsername)),
+		derefText(e.ActorRole),
+		string(e.Action),
+		e.ResourceType,
+		resourceID,
+		strings.Join(e.Fields, ";"),
+		derefText(e.ClientIP),
+		grantID,
+	}
+}
+
+func derefText(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// PatientAccessLog godoc
+// @Summary List who accessed a patient's chart
+// @Description Admins and privacy officers only. Lists every audited read or change of the patient and their visits, newest first, with the fields written. With `format=csv` the whole log is downloaded as CSV and limit/offset are ignored.
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param format query string false "Response format" Enums(json, csv)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.AccessLogEntry}
+// @Failure 400 {object} model.APIError "Invalid patient ID, format or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/access-log [get]
+func (h *AuditHandler) PatientAccessLog(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+	case "csv":
+		h.exportPatientAccessLog(c, patientID)
+		return
+	default:
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid format, expected json or csv"})
+		return
+	}
+
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	entries, total, err := h.auditService.ListPatientAccessLog(c.Request.Context(), patientID, params)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "List access log failed", "patient_id", patientID, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to list access log"})
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: entries, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// exportPatientAccessLog streams the whole access log as CSV. Once the first page has
+// been written an error can only truncate the download, so it is logged.
+func (h *AuditHandler) exportPatientAccessLog(c *gin.Context, patientID uuid.UUID) {
+	var w *csv.Writer
+	err := h.auditService.ExportPatientAccessLog(c.Request.Context(), patientID, accessLogExportPageSize, func(entries []model.AccessLogEntry) error {
+		if w == nil {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Header("Content-Disposition", `attachment; filename="access-log-`+patientID.String()+`.csv"`)
+			c.Status(http.StatusOK)
+			w = csv.NewWriter(c.Writer)
+			w.Write(accessLogCSVHeader)
+		}
+		for _, e := range entries {
+			w.Write(accessLogCSVRecord(e))
+		}
+		w.Flush()
+		return w.Error()
+	})
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Export access log failed", "patient_id", patientID, "error", err)
+		if w == nil {
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to export access log"})
+		}
+	}
+}
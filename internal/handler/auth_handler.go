@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"strings" // Added for error checking
 
@@ -13,10 +13,11 @@ import (
 
 type AuthHandler struct {
 	authService service.AuthService
+	logger      *slog.Logger
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService service.AuthService, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{authService: authService, logger: logger.With("handler", "AuthHandler")}
 }
 
 
@@ -32,27 +33,31 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Failure 400 {object} model.APIError "Validation error or invalid input"
 // @Failure 409 {object} model.APIError "User already exists"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /auth/register [post]
 func (h *AuthHandler) CreateUser(c *gin.Context) {
 	var req model.UserCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
 	user, err := h.authService.CreateUser(c.Request.Context(), req)
 	if err != nil {
+		if writeAuditUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "already exists") ||
 			strings.Contains(strings.ToLower(err.Error()), "unique constraint") {
-			c.JSON(http.StatusConflict, model.APIError{Message: err.Error()})
+			writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
 		} else {
-			log.Printf("Create user error: %v", err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to create user"})
+			h.logger.ErrorContext(c.Request.Context(), "Create user failed", "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to create user"})
 		}
 		return
 	}
@@ -75,12 +80,12 @@ func (h *AuthHandler) CreateUser(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req model.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
@@ -88,10 +93,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if err != nil {
 		// Distinguish between bad credentials and server errors
 		if err.Error() == "invalid username or password" { // Specific error check
-			c.JSON(http.StatusUnauthorized, model.APIError{Message: err.Error()})
+			writeError(c, http.StatusUnauthorized, model.APIError{Message: err.Error()})
 		} else {
-			log.Printf("Login error: %v", err) // Log internal errors
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Login failed due to an internal error"})
+			h.logger.ErrorContext(c.Request.Context(), "Login failed", "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Login failed due to an internal error"})
 		}
 		return
 	}
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type BillingHandler struct {
+	billingService service.BillingService
+	logger         *slog.Logger
+}
+
+func NewBillingHandler(billingService service.BillingService, logger *slog.Logger) *BillingHandler {
+	return &BillingHandler{billingService: billingService, logger: logger.With("handler", "BillingHandler")}
+}
+
+// writeBillingError maps billing service errors to HTTP responses.
+func writeBillingError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrInvoiceNotFound),
+		errors.Is(err, service.ErrPaymentNotFound),
+		errors.Is(err, service.ErrVisitNotFound),
+		errors.Is(err, service.ErrPatientNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrChargeItemNotFound),
+		errors.Is(err, service.ErrTaxCategoryNotFound),
+		errors.Is(err, service.ErrChargeNotBillable):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrChargeItemAlreadyExists),
+		errors.Is(err, service.ErrTaxCategoryAlreadyExists),
+		errors.Is(err, service.ErrNoBillableCharges),
+		errors.Is(err, service.ErrPaymentExceedsBalance),
+		errors.Is(err, service.ErrRefundExceedsPayment):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// CreateTaxCategory godoc
+// @Summary Add a tax category
+// @Description Rates are in basis points (1800 = 18%).
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.TaxCategoryCreateRequest true "Tax category"
+// @Success 201 {object} model.TaxCategory
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 409 {object} model.APIError "Tax category already exists"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/tax-categories [post]
+func (h *BillingHandler) CreateTaxCategory(c *gin.Context) {
+	var req model.TaxCategoryCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	category, err := h.billingService.CreateTaxCategory(c.Request.Context(), req)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to create tax category")
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// ListTaxCategories godoc
+// @Summary List tax categories
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.TaxCategory
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/tax-categories [get]
+func (h *BillingHandler) ListTaxCategories(c *gin.Context) {
+	categories, err := h.billingService.ListTaxCategories(c.Request.Context())
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to list tax categories")
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateChargeItem godoc
+// @Summary Add an item to the charge master
+// @Description Prices are integer minor units (e.g. paise). Use code CONSULT to bill every recorded visit automatically.
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.ChargeItemCreateRequest true "Charge item"
+// @Success 201 {object} model.ChargeItem
+// @Failure 400 {object} model.APIError "Validation error or unknown tax category"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 409 {object} model.APIError "Charge item already exists"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/charge-items [post]
+func (h *BillingHandler) CreateChargeItem(c *gin.Context) {
+	var req model.ChargeItemCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	item, err := h.billingService.CreateChargeItem(c.Request.Context(), req)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to create charge item")
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// ListChargeItems godoc
+// @Summary List the charge master
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.ChargeItem}
+// @Failure 400 {object} model.APIError "Invalid pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/charge-items [get]
+func (h *BillingHandler) ListChargeItems(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	items, total, err := h.billingService.ListChargeItems(c.Request.Context(), params)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to list charge items")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: items, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// PostCharge godoc
+// @Summary Post a procedure or manual charge to a visit
+// @Description Visits and lab orders are charged automatically; use this for procedures and other billable services.
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.ChargeCreateRequest true "Charge"
+// @Success 201 {object} model.Charge
+// @Failure 400 {object} model.APIError "Validation error or unknown charge code"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/charges [post]
+func (h *BillingHandler) PostCharge(c *gin.Context) {
+	var req model.ChargeCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	charge, err := h.billingService.PostCharge(c.Request.Context(), req, userID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to post charge")
+		return
+	}
+	c.JSON(http.StatusCreated, charge)
+}
+
+// ListPatientCharges godoc
+// @Summary List a patient's charges
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param unbilled query bool false "Only charges not yet invoiced"
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.Charge}
+// @Failure 400 {object} model.APIError "Invalid patient ID or query parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/charges [get]
+func (h *BillingHandler) ListPatientCharges(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	unbilledOnly, err := strconv.ParseBool(c.DefaultQuery("unbilled", "false"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid unbilled parameter"})
+		return
+	}
+
+	charges, total, err := h.billingService.ListPatientCharges(c.Request.Context(), patientID, unbilledOnly, params)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to list charges")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: charges, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// CreateInvoice godoc
+// @Summary Issue an invoice
+// @Description Bills the given unbilled charges (default: all of them) with an optional percentage discount. Invoice numbers are gapless per fiscal year.
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.InvoiceCreateRequest true "Invoice"
+// @Success 201 {object} model.Invoice
+// @Failure 400 {object} model.APIError "Validation error or charge not billable"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "No unbilled charges"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/invoices [post]
+func (h *BillingHandler) CreateInvoice(c *gin.Context) {
+	var req model.InvoiceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	invoice, err := h.billingService.CreateInvoice(c.Request.Context(), req, userID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to create invoice")
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// GetInvoice godoc
+// @Summary Get an invoice with its lines, payments and refunds
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invoice ID (UUID)" Format(uuid)
+// @Success 200 {object} model.Invoice
+// @Failure 400 {object} model.APIError "Invalid invoice ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Invoice not found"
+// @Router /billing/invoices/{id} [get]
+func (h *BillingHandler) GetInvoice(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid invoice ID format"})
+		return
+	}
+	invoice, err := h.billingService.GetInvoice(c.Request.Context(), invoiceID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to get invoice")
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// ListPatientInvoices godoc
+// @Summary List a patient's invoices
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.Invoice}
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/invoices [get]
+func (h *BillingHandler) ListPatientInvoices(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	invoices, total, err := h.billingService.ListPatientInvoices(c.Request.Context(), patientID, params)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to list invoices")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: invoices, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// RecordPayment godoc
+// @Summary Record a payment against an invoice
+// @Description Accepts full or partial cash, card or insurance payments up to the outstanding balance.
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID (UUID)" Format(uuid)
+// @Param request body model.PaymentCreateRequest true "Payment"
+// @Success 201 {object} model.Invoice
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Invoice not found"
+// @Failure 409 {object} model.APIError "Payment exceeds balance"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/invoices/{id}/payments [post]
+func (h *BillingHandler) RecordPayment(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid invoice ID format"})
+		return
+	}
+	var req model.PaymentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	invoice, err := h.billingService.RecordPayment(c.Request.Context(), invoiceID, req, userID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to record payment")
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// RefundPayment godoc
+// @Summary Refund a payment
+// @Description Refunds part or all of a payment; the invoice balance is reopened by the refunded amount.
+// @Tags Billing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID (UUID)" Format(uuid)
+// @Param request body model.RefundCreateRequest true "Refund"
+// @Success 201 {object} model.Invoice
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Payment not found"
+// @Failure 409 {object} model.APIError "Refund exceeds payment"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/payments/{id}/refunds [post]
+func (h *BillingHandler) RefundPayment(c *gin.Context) {
+	paymentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid payment ID format"})
+		return
+	}
+	var req model.RefundCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	invoice, err := h.billingService.RefundPayment(c.Request.Context(), paymentID, req, userID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to refund payment")
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// GetPatientBalance godoc
+// @Summary Get a patient's running balance
+// @Description Outstanding balance on issued invoices plus the value of charges not yet invoiced.
+// @Tags Billing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 200 {object} model.PatientBalance
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Router /patients/{id}/balance [get]
+func (h *BillingHandler) GetPatientBalance(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	balance, err := h.billingService.GetPatientBalance(c.Request.Context(), patientID)
+	if err != nil {
+		writeBillingError(c, h.logger, err, "Failed to get patient balance")
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/breakglass"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type BreakGlassHandler struct {
+	breakGlassService service.BreakGlassService
+	logger            *slog.Logger
+}
+
+func NewBreakGlassHandler(breakGlassService service.BreakGlassService, logger *slog.Logger) *BreakGlassHandler {
+	return &BreakGlassHandler{breakGlassService: breakGlassService, logger: logger.With("handler", "BreakGlassHandler")}
+}
+
+// writeBreakGlassError maps break-glass service errors to HTTP responses.
+func writeBreakGlassError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeAuditUnavailable(c, err):
+	case errors.Is(err, service.ErrPatientNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrBreakGlassReasonRequired):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrBreakGlassReportNotReady):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// RequestAccess godoc
+// @Summary Break the glass: get emergency access to a patient's chart
+// @Description Doctors only. Issues a time-boxed grant (60 minutes unless asked otherwise, at most 240) to the calling doctor. The justification is recorded in the audit trail, every access made under the grant is flagged, and the grant appears in the daily compliance review.
+// @Tags Break Glass
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.BreakGlassRequest true "Justification"
+// @Success 201 {object} model.BreakGlassGrant
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/break-glass [post]
+func (h *BreakGlassHandler) RequestAccess(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.BreakGlassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	grant, err := h.breakGlassService.RequestAccess(c.Request.Context(), patientID, req, userID)
+	if err != nil {
+		writeBreakGlassError(c, h.logger, err, "Failed to grant emergency access")
+		return
+	}
+	c.JSON(http.StatusCreated, grant)
+}
+
+// ListReports godoc
+// @Summary List daily break-glass review reports
+// @Description Admins and privacy officers only. Newest first.
+// @Tags Break Glass
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.BreakGlassReportSummary}
+// @Failure 400 {object} model.APIError "Invalid pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /break-glass/reports [get]
+func (h *BreakGlassHandler) ListReports(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	reports, total, err := h.breakGlassService.ListReports(c.Request.Context(), params)
+	if err != nil {
+		writeBreakGlassError(c, h.logger, err, "Failed to list break-glass reports")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: reports, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// GetReport godoc
+// @Summary Get the break-glass review for one day
+// @Description Admins and privacy officers only. Lists the grants issued or used on the UTC day with the justification and every access made under each. Reports are generated shortly after midnight UTC, or on first request for an earlier day.
+// @Tags Break Glass
+// @Security BearerAuth
+// @Produce json
+// @Param date path string true "Day (YYYY-MM-DD, UTC)"
+// @Success 200 {object} model.BreakGlassReport
+// @Failure 400 {object} model.APIError "Invalid date"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 409 {object} model.APIError "The day is not over yet"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /break-glass/reports/{date} [get]
+func (h *BreakGlassHandler) GetReport(c *gin.Context) {
+	date, err := time.Parse(breakglass.DateLayout, c.Param("date"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid date format, expected YYYY-MM-DD"})
+		return
+	}
+	report, err := h.breakGlassService.GetReport(c.Request.Context(), date)
+	if err != nil {
+		writeBreakGlassError(c, h.logger, err, "Failed to get break-glass report")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type CareTeamHandler struct {
+	careTeamService service.CareTeamService
+	logger          *slog.Logger
+}
+
+func NewCareTeamHandler(careTeamService service.CareTeamService, logger *slog.Logger) *CareTeamHandler {
+	return &CareTeamHandler{careTeamService: careTeamService, logger: logger.With("handler", "CareTeamHandler")}
+}
+
+// writeCareTeamError maps care team service errors to HTTP responses.
+func writeCareTeamError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeAuditUnavailable(c, err):
+	case errors.Is(err, service.ErrPatientNotFound),
+		errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrCareTeamMemberNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrCareTeamMemberNotDoctor):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrCareTeamMemberExists):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// AddMember godoc
+// @Summary Assign a doctor to a patient's care team
+// @Description Receptionists, or doctors for patients already in their care. Care team doctors can see the patient's full record.
+// @Tags Care Team
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.CareTeamMemberRequest true "Doctor to assign"
+// @Success 201 {object} model.CareTeamMember
+// @Failure 400 {object} model.APIError "Validation error or the user is not a doctor"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient or user not found"
+// @Failure 409 {object} model.APIError "Already on the care team"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/care-team [post]
+func (h *CareTeamHandler) AddMember(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.CareTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	member, err := h.careTeamService.AddMember(c.Request.Context(), patientID, req, userID)
+	if err != nil {
+		writeCareTeamError(c, h.logger, err, "Failed to add care team member")
+		return
+	}
+	c.JSON(http.StatusCreated, member)
+}
+
+// ListMembers godoc
+// @Summary List a patient's care team
+// @Tags Care Team
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 200 {array} model.CareTeamMember
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/care-team [get]
+func (h *CareTeamHandler) ListMembers(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	members, err := h.careTeamService.ListMembers(c.Request.Context(), patientID)
+	if err != nil {
+		writeCareTeamError(c, h.logger, err, "Failed to list care team")
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// RemoveMember godoc
+// @Summary Remove a doctor from a patient's care team
+// @Description The doctor keeps access to patients they have recorded visits for.
+// @Tags Care Team
+// @Security BearerAuth
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param userId path string true "User ID (UUID)" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} model.APIError "Invalid ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient not found or user not on the care team"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/care-team/{userId} [delete]
+func (h *CareTeamHandler) RemoveMember(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid user ID format"})
+		return
+	}
+	if err := h.careTeamService.RemoveMember(c.Request.Context(), patientID, userID); err != nil {
+		writeCareTeamError(c, h.logger, err, "Failed to remove care team member")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
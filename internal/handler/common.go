@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/consent"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+)
+
+// writeError writes apiErr with the request's trace ID, so the failure can be found in
+// the traces.
+func writeError(c *gin.Context, status int, apiErr model.APIError) {
+	apiErr.TraceID = tracing.TraceID(c.Request.Context())
+	c.JSON(status, apiErr)
+}
+
+// bindPagination binds limit/offset query parameters and clamps them to the API defaults.
+// It writes a 400 response and returns false when the parameters cannot be bound.
+func bindPagination(c *gin.Context) (model.PaginationParams, bool) {
+	var params model.PaginationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid pagination parameters", Details: err.Error()})
+		return params, false
+	}
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+	return params, true
+}
+
+// writeAuditUnavailable writes a 503 when err means the access could not be audited.
+// PHI is never returned unaudited, so the caller should stop when it returns true.
+func writeAuditUnavailable(c *gin.Context, err error) bool {
+	if !errors.Is(err, service.ErrAuditUnavailable) {
+		return false
+	}
+	writeError(c, http.StatusServiceUnavailable, model.APIError{Message: "Audit trail unavailable, try again later"})
+	return true
+}
+
+// writeConsentRefused writes a 403 explaining which consent is missing or withdrawn when
+// err is a consent refusal. The caller should stop when it returns true.
+func writeConsentRefused(c *gin.Context, err error) bool {
+	if !errors.Is(err, consent.ErrConsentRequired) {
+		return false
+	}
+	writeError(c, http.StatusForbidden, model.APIError{Message: "Refused: " + err.Error()})
+	return true
+}
+
+// etag is the entity tag of a patient or visit at the given version.
+func etag(version int32) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// bindIfMatch reads the version an update was made against from the If-Match header. "*"
+// applies the update to whatever version is current and yields nil. A tag we did not issue
+// yields a version no row has, so the update fails its precondition. Without the header it
+// writes a 428 and returns false.
+func bindIfMatch(c *gin.Context) (*int32, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		writeError(c, http.StatusPreconditionRequired, model.APIError{Message: "If-Match header required; send the ETag from the last GET"})
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+	// Proxies that compress responses may weaken the tag; the version is the same.
+	tag := strings.TrimPrefix(header, "W/")
+	version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 32)
+	if err != nil || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		version = -1
+	}
+	v := int32(version)
+	return &v, true
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/consent"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type ConsentHandler struct {
+	consentService service.ConsentService
+	logger         *slog.Logger
+}
+
+func NewConsentHandler(consentService service.ConsentService, logger *slog.Logger) *ConsentHandler {
+	return &ConsentHandler{consentService: consentService, logger: logger.With("handler", "ConsentHandler")}
+}
+
+// writeConsentError maps consent service errors to HTTP responses.
+func writeConsentError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeAuditUnavailable(c, err):
+	case errors.Is(err, service.ErrPatientNotFound), errors.Is(err, service.ErrConsentRecordNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, consent.ErrSignedInFuture):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrConsentAlreadyWithdrawn), errors.Is(err, service.ErrConsentConflict):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// ListConsentTypes godoc
+// @Summary List the consent types a patient can give
+// @Tags Consent
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.ConsentTypeInfo
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Router /consent-types [get]
+func (h *ConsentHandler) ListConsentTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.consentService.ListConsentTypes())
+}
+
+// RecordConsent godoc
+// @Summary Record a consent signed by a patient
+// @Description Receptionists and doctors. The calling user is recorded as the witness. A consent of the same type already in force is withdrawn as superseded.
+// @Tags Consent
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.ConsentCreateRequest true "Signed consent"
+// @Success 201 {object} model.ConsentRecord
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "Consent changed concurrently"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/consents [post]
+func (h *ConsentHandler) RecordConsent(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.ConsentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	record, err := h.consentService.RecordConsent(c.Request.Context(), patientID, req, userID)
+	if err != nil {
+		writeConsentError(c, h.logger, err, "Failed to record consent")
+		return
+	}
+	c.JSON(http.StatusCreated, record)
+}
+
+// ListConsents godoc
+// @Summary List a patient's consents
+// @Description Every consent the patient signed, newest first, including withdrawn ones.
+// @Tags Consent
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 200 {array} model.ConsentRecord
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/consents [get]
+func (h *ConsentHandler) ListConsents(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	records, err := h.consentService.ListConsents(c.Request.Context(), patientID)
+	if err != nil {
+		writeConsentError(c, h.logger, err, "Failed to list consents")
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// WithdrawConsent godoc
+// @Summary Record that a patient withdrew a consent
+// @Description Receptionists and doctors. The calling user is recorded as having taken the withdrawal.
+// @Tags Consent
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param consentId path string true "Consent record ID (UUID)" Format(uuid)
+// @Param request body model.ConsentWithdrawRequest false "Reason"
+// @Success 200 {object} model.ConsentRecord
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient or consent not found"
+// @Failure 409 {object} model.APIError "Already withdrawn"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/consents/{consentId}/withdraw [post]
+func (h *ConsentHandler) WithdrawConsent(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	consentID, err := uuid.Parse(c.Param("consentId"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid consent ID format"})
+		return
+	}
+	var req model.ConsentWithdrawRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+			return
+		}
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	record, err := h.consentService.WithdrawConsent(c.Request.Context(), patientID, consentID, req, userID)
+	if err != nil {
+		writeConsentError(c, h.logger, err, "Failed to withdraw consent")
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type InsuranceHandler struct {
+	insuranceService service.InsuranceService
+	logger           *slog.Logger
+}
+
+func NewInsuranceHandler(insuranceService service.InsuranceService, logger *slog.Logger) *InsuranceHandler {
+	return &InsuranceHandler{insuranceService: insuranceService, logger: logger.With("handler", "InsuranceHandler")}
+}
+
+// writeInsuranceError maps insurance service errors to HTTP responses.
+func writeInsuranceError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeConsentRefused(c, err):
+	case errors.Is(err, service.ErrPolicyNotFound),
+		errors.Is(err, service.ErrClaimNotFound),
+		errors.Is(err, service.ErrInvoiceNotFound),
+		errors.Is(err, service.ErrPatientNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidPolicyDates),
+		errors.Is(err, service.ErrInvalidDiagnosisCode),
+		errors.Is(err, service.ErrPolicyPatientMismatch),
+		errors.Is(err, service.ErrPolicyNotValid),
+		errors.Is(err, service.ErrClaimPaidExceedsClaimed):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPolicyPriorityTaken),
+		errors.Is(err, service.ErrNothingToClaim),
+		errors.Is(err, service.ErrClaimAlreadyOpen),
+		errors.Is(err, service.ErrClaimInvalidTransition),
+		errors.Is(err, service.ErrClaimAlreadySent),
+		errors.Is(err, service.ErrPaymentExceedsBalance):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPayerUnavailable):
+		writeError(c, http.StatusBadGateway, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPayerGatewayNotConfigured):
+		writeError(c, http.StatusServiceUnavailable, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// AddPolicy godoc
+// @Summary Add an insurance policy to a patient
+// @Description A patient may have one active primary and one active secondary policy.
+// @Tags Insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.InsurancePolicyCreateRequest true "Policy"
+// @Success 201 {object} model.InsurancePolicy
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "Priority already taken"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/insurance-policies [post]
+func (h *InsuranceHandler) AddPolicy(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.InsurancePolicyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	policy, err := h.insuranceService.AddPolicy(c.Request.Context(), patientID, req, userID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to add insurance policy")
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// ListPatientPolicies godoc
+// @Summary List a patient's insurance policies
+// @Description Receptionists can list the policies they add and claim against.
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 200 {array} model.InsurancePolicy
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/insurance-policies [get]
+func (h *InsuranceHandler) ListPatientPolicies(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	policies, err := h.insuranceService.ListPatientPolicies(c.Request.Context(), patientID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to list insurance policies")
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// DeactivatePolicy godoc
+// @Summary Deactivate an insurance policy
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Policy ID (UUID)" Format(uuid)
+// @Success 200 {object} model.InsurancePolicy
+// @Failure 400 {object} model.APIError "Invalid policy ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Policy not found"
+// @Router /insurance-policies/{id} [delete]
+func (h *InsuranceHandler) DeactivatePolicy(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid policy ID format"})
+		return
+	}
+	policy, err := h.insuranceService.DeactivatePolicy(c.Request.Context(), policyID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to deactivate insurance policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// VerifyEligibility godoc
+// @Summary Verify a policy's eligibility with the payer
+// @Description Records the payer's answer on the policy. An unreachable payer yields status "unknown". Refused with 403 unless the patient's data_sharing consent is in force.
+// @Tags Insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID (UUID)" Format(uuid)
+// @Param request body model.EligibilityCheckRequest false "Date of service"
+// @Success 200 {object} model.EligibilityCheck
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Policy not found"
+// @Failure 503 {object} model.APIError "No payer gateway configured"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /insurance-policies/{id}/eligibility [post]
+func (h *InsuranceHandler) VerifyEligibility(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid policy ID format"})
+		return
+	}
+	var req model.EligibilityCheckRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+			return
+		}
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	dateOfService := time.Now()
+	if req.DateOfService != nil {
+		dateOfService, _ = time.Parse("2006-01-02", *req.DateOfService) // format checked by the validator
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	check, err := h.insuranceService.VerifyEligibility(c.Request.Context(), policyID, dateOfService, userID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to verify eligibility")
+		return
+	}
+	c.JSON(http.StatusOK, check)
+}
+
+// CreateClaim godoc
+// @Summary Generate and submit a claim from an invoice
+// @Description Claims the invoice's outstanding balance from the policy's payer with the given ICD-10 diagnosis codes. The claim is saved before it is sent; when the payer cannot be reached the 502 names the claim, which can be sent again with POST /claims/{id}/send. Refused with 403 unless the patient's data_sharing consent is in force.
+// @Tags Insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.InsuranceClaimCreateRequest true "Claim"
+// @Success 201 {object} model.InsuranceClaim
+// @Failure 400 {object} model.APIError "Validation error, invalid diagnosis code or policy not applicable"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Invoice or policy not found"
+// @Failure 409 {object} model.APIError "Open claim exists or nothing to claim"
+// @Failure 502 {object} model.APIError "Payer unavailable"
+// @Failure 503 {object} model.APIError "No payer gateway configured"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /claims [post]
+func (h *InsuranceHandler) CreateClaim(c *gin.Context) {
+	var req model.InsuranceClaimCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	claim, err := h.insuranceService.CreateClaim(c.Request.Context(), req, userID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to create claim")
+		return
+	}
+	c.JSON(http.StatusCreated, claim)
+}
+
+// SendClaim godoc
+// @Summary Send a claim the payer has not acknowledged again
+// @Description The claim keeps its ID, which the payer uses to recognise a claim it already has. Refused with 403 unless the patient's data_sharing consent is in force.
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Claim ID (UUID)" Format(uuid)
+// @Success 200 {object} model.InsuranceClaim
+// @Failure 400 {object} model.APIError "Invalid claim ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Claim not found"
+// @Failure 409 {object} model.APIError "Claim already acknowledged by the payer"
+// @Failure 502 {object} model.APIError "Payer unavailable"
+// @Failure 503 {object} model.APIError "No payer gateway configured"
+// @Router /claims/{id}/send [post]
+func (h *InsuranceHandler) SendClaim(c *gin.Context) {
+	claimID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid claim ID format"})
+		return
+	}
+	claim, err := h.insuranceService.SendClaim(c.Request.Context(), claimID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to send claim")
+		return
+	}
+	c.JSON(http.StatusOK, claim)
+}
+
+// GetClaim godoc
+// @Summary Get a claim with its status history
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Claim ID (UUID)" Format(uuid)
+// @Success 200 {object} model.InsuranceClaim
+// @Failure 400 {object} model.APIError "Invalid claim ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Claim not found"
+// @Router /claims/{id} [get]
+func (h *InsuranceHandler) GetClaim(c *gin.Context) {
+	claimID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid claim ID format"})
+		return
+	}
+	claim, err := h.insuranceService.GetClaim(c.Request.Context(), claimID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to get claim")
+		return
+	}
+	c.JSON(http.StatusOK, claim)
+}
+
+// ListClaims godoc
+// @Summary List claims by status
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Claim status (default: submitted)" Enums(submitted, accepted, rejected, paid)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.InsuranceClaim}
+// @Failure 400 {object} model.APIError "Invalid status or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /claims [get]
+func (h *InsuranceHandler) ListClaims(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	status := model.ClaimStatus(c.DefaultQuery("status", string(model.ClaimStatusSubmitted)))
+	switch status {
+	case model.ClaimStatusSubmitted, model.ClaimStatusAccepted, model.ClaimStatusRejected, model.ClaimStatusPaid:
+	default:
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid claim status"})
+		return
+	}
+
+	claims, total, err := h.insuranceService.ListClaimsByStatus(c.Request.Context(), status, params)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to list claims")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: claims, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// ListInvoiceClaims godoc
+// @Summary List claims raised from an invoice
+// @Tags Insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invoice ID (UUID)" Format(uuid)
+// @Success 200 {array} model.InsuranceClaim
+// @Failure 400 {object} model.APIError "Invalid invoice ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /billing/invoices/{id}/claims [get]
+func (h *InsuranceHandler) ListInvoiceClaims(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid invoice ID format"})
+		return
+	}
+	claims, err := h.insuranceService.ListInvoiceClaims(c.Request.Context(), invoiceID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to list claims")
+		return
+	}
+	c.JSON(http.StatusOK, claims)
+}
+
+// UpdateClaimStatus godoc
+// @Summary Record the payer's response to a claim
+// @Description Moves a claim to accepted, rejected (with reason) or paid. Paid claims post an insurance payment to the invoice.
+// @Tags Insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Claim ID (UUID)" Format(uuid)
+// @Param request body model.ClaimStatusUpdateRequest true "Status update"
+// @Success 200 {object} model.InsuranceClaim
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Claim not found"
+// @Failure 409 {object} model.APIError "Invalid status transition"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /claims/{id}/status [post]
+func (h *InsuranceHandler) UpdateClaimStatus(c *gin.Context) {
+	claimID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid claim ID format"})
+		return
+	}
+	var req model.ClaimStatusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	claim, err := h.insuranceService.UpdateClaimStatus(c.Request.Context(), claimID, req, userID)
+	if err != nil {
+		writeInsuranceError(c, h.logger, err, "Failed to update claim status")
+		return
+	}
+	c.JSON(http.StatusOK, claim)
+}
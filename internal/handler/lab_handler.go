@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type LabHandler struct {
+	labService service.LabService
+	logger     *slog.Logger
+}
+
+func NewLabHandler(labService service.LabService, logger *slog.Logger) *LabHandler {
+	return &LabHandler{labService: labService, logger: logger.With("handler", "LabHandler")}
+}
+
+// writeLabError maps lab service errors to HTTP responses.
+func writeLabError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrLabOrderNotFound),
+		errors.Is(err, service.ErrLabTestNotFound),
+		errors.Is(err, service.ErrVisitForLabOrderNotFound),
+		errors.Is(err, service.ErrVisitNotFound),
+		errors.Is(err, service.ErrPatientNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrLabAnalyteNotFound),
+		errors.Is(err, service.ErrChargeItemNotFound):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrLabOrderInvalidTransition),
+		errors.Is(err, service.ErrLabOrderHasNoResults),
+		errors.Is(err, service.ErrLabTestAlreadyExists):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// CreateLabTest godoc
+// @Summary Add a test to the lab catalog
+// @Description Lab technicians can add a test with its analytes, units and reference/critical ranges.
+// @Tags Lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.LabTestCreateRequest true "Lab test definition"
+// @Success 201 {object} model.LabTest
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 409 {object} model.APIError "Test code already exists"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /lab/tests [post]
+func (h *LabHandler) CreateLabTest(c *gin.Context) {
+	var req model.LabTestCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	test, err := h.labService.CreateLabTest(c.Request.Context(), req)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to create lab test")
+		return
+	}
+	c.JSON(http.StatusCreated, test)
+}
+
+// ListLabTests godoc
+// @Summary List the lab test catalog
+// @Description Authenticated users can list active lab tests with their analytes.
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.LabTest}
+// @Failure 400 {object} model.APIError "Invalid pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /lab/tests [get]
+func (h *LabHandler) ListLabTests(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	tests, total, err := h.labService.ListLabTests(c.Request.Context(), params)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to list lab tests")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: tests, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// OrderLabTest godoc
+// @Summary Order a lab test for a visit
+// @Description Doctors can order a lab test from the catalog against a visit of a patient in their care; others get 404.
+// @Tags Lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.LabOrderCreateRequest true "Lab order"
+// @Success 201 {object} model.LabOrder
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit or test not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /lab/orders [post]
+func (h *LabHandler) OrderLabTest(c *gin.Context) {
+	var req model.LabOrderCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	order, err := h.labService.OrderLabTest(c.Request.Context(), req, userID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to create lab order")
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListLabWorklist godoc
+// @Summary List lab orders by status
+// @Description Lab technicians can list orders in a given status (default: ordered), STAT first.
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Order status" Enums(ordered, collected, resulted, verified)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.LabOrder}
+// @Failure 400 {object} model.APIError "Invalid status or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /lab/orders [get]
+func (h *LabHandler) ListLabWorklist(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	status := model.LabOrderStatus(c.DefaultQuery("status", string(model.LabStatusOrdered)))
+	switch status {
+	case model.LabStatusOrdered, model.LabStatusCollected, model.LabStatusResulted, model.LabStatusVerified:
+	default:
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid lab order status"})
+		return
+	}
+
+	orders, err := h.labService.ListLabWorklist(c.Request.Context(), status, params)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to list lab orders")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: orders, Total: int64(len(orders)), Limit: params.Limit, Offset: params.Offset})
+}
+
+// GetLabOrder godoc
+// @Summary Get a lab order with its results
+// @Description Doctors get orders of patients in their care; others get 404. Roles other than lab technician see the order without clinical notes or results.
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Lab order ID (UUID)" Format(uuid)
+// @Success 200 {object} model.LabOrder
+// @Failure 400 {object} model.APIError "Invalid lab order ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Lab order not found"
+// @Router /lab/orders/{id} [get]
+func (h *LabHandler) GetLabOrder(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid lab order ID format"})
+		return
+	}
+	order, err := h.labService.GetLabOrder(c.Request.Context(), orderID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to get lab order")
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// MarkSpecimenCollected godoc
+// @Summary Mark a lab order's specimen as collected
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Lab order ID (UUID)" Format(uuid)
+// @Success 200 {object} model.LabOrder
+// @Failure 400 {object} model.APIError "Invalid lab order ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Lab order not found"
+// @Failure 409 {object} model.APIError "Order is not awaiting collection"
+// @Router /lab/orders/{id}/collect [post]
+func (h *LabHandler) MarkSpecimenCollected(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid lab order ID format"})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	order, err := h.labService.MarkSpecimenCollected(c.Request.Context(), orderID, userID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to mark specimen collected")
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// EnterLabResults godoc
+// @Summary Enter results for a lab order
+// @Description Lab technicians record one value per analyte; results are flagged against reference and critical ranges.
+// @Tags Lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Lab order ID (UUID)" Format(uuid)
+// @Param request body model.LabResultEntryRequest true "Analyte results"
+// @Success 200 {object} model.LabOrder
+// @Failure 400 {object} model.APIError "Validation error or unknown analyte"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Lab order not found"
+// @Failure 409 {object} model.APIError "Order is not awaiting results"
+// @Router /lab/orders/{id}/results [post]
+func (h *LabHandler) EnterLabResults(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid lab order ID format"})
+		return
+	}
+	var req model.LabResultEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	order, err := h.labService.EnterLabResults(c.Request.Context(), orderID, req, userID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to enter lab results")
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// VerifyLabResults godoc
+// @Summary Verify the results of a lab order
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Lab order ID (UUID)" Format(uuid)
+// @Success 200 {object} model.LabOrder
+// @Failure 400 {object} model.APIError "Invalid lab order ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Lab order not found"
+// @Failure 409 {object} model.APIError "Order has no results or is not awaiting verification"
+// @Router /lab/orders/{id}/verify [post]
+func (h *LabHandler) VerifyLabResults(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid lab order ID format"})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	order, err := h.labService.VerifyLabResults(c.Request.Context(), orderID, userID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to verify lab results")
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ListVisitLabOrders godoc
+// @Summary List lab orders for a visit
+// @Description Doctors list lab orders of visits of patients in their care; others get 404. Roles other than lab technician see the orders without clinical notes or results.
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Success 200 {array} model.LabOrder
+// @Failure 400 {object} model.APIError "Invalid visit ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Router /visits/{id}/lab-orders [get]
+func (h *LabHandler) ListVisitLabOrders(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	orders, err := h.labService.ListVisitLabOrders(c.Request.Context(), visitID)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to list lab orders")
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetCumulativeLabResults godoc
+// @Summary Cumulative lab results for a patient
+// @Description Lists all results across the patient's visits, grouped by analyte and ordered by time for trending. Doctors list patients in their care; others get 404. Roles other than lab technician get an empty list.
+// @Tags Lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param analyte query string false "Restrict to one analyte code"
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.CumulativeLabResult}
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Router /patients/{id}/lab-results [get]
+func (h *LabHandler) GetCumulativeLabResults(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	var analyteCode *string
+	if code := c.Query("analyte"); code != "" {
+		analyteCode = &code
+	}
+
+	results, err := h.labService.GetCumulativeLabResults(c.Request.Context(), patientID, analyteCode, params)
+	if err != nil {
+		writeLabError(c, h.logger, err, "Failed to list lab results")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: results, Total: int64(len(results)), Limit: params.Limit, Offset: params.Offset})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+	logger              *slog.Logger
+}
+
+func NewNotificationHandler(notificationService service.NotificationService, logger *slog.Logger) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, logger: logger.With("handler", "NotificationHandler")}
+}
+
+// SendSMS godoc
+// @Summary Send an SMS to a patient
+// @Description Receptionists and doctors. Refused with 403 unless the patient's sms_contact consent is in force.
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.SMSNotificationRequest true "Message"
+// @Success 202 {object} model.NotificationReceipt
+// @Failure 400 {object} model.APIError "Validation error or the patient has no contact phone"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden, or consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/notifications/sms [post]
+func (h *NotificationHandler) SendSMS(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.SMSNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	receipt, err := h.notificationService.SendSMS(c.Request.Context(), patientID, req)
+	if err != nil {
+		switch {
+		case writeAuditUnavailable(c, err), writeConsentRefused(c, err):
+		case errors.Is(err, service.ErrPatientNotFound):
+			writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+		case errors.Is(err, service.ErrPatientNoContactPhone):
+			writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "Send SMS failed", "patient_id", patientID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to send SMS"})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, receipt)
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/sar"
+	"github.com/himanshu-holmes/hms/internal/service"
+)
+
+type PatientExportHandler struct {
+	exportService service.PatientExportService
+	logger        *slog.Logger
+}
+
+func NewPatientExportHandler(exportService service.PatientExportService, logger *slog.Logger) *PatientExportHandler {
+	return &PatientExportHandler{exportService: exportService, logger: logger.With("handler", "PatientExportHandler")}
+}
+
+// writePatientExportError maps patient export service errors to HTTP responses.
+func writePatientExportError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeAuditUnavailable(c, err), writeConsentRefused(c, err):
+	case errors.Is(err, service.ErrPatientNotFound), errors.Is(err, service.ErrPatientExportNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPatientExportNotReady):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPatientExportExpired):
+		writeError(c, http.StatusGone, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// withDownloadURL sets the download link of an export that can still be downloaded.
+func withDownloadURL(e *model.PatientExport) {
+	if e.Downloadable(time.Now()) {
+		url := fmt.Sprintf("/api/v1/patients/%s/export/%s/download", e.PatientID, e.ID)
+		e.DownloadURL = &url
+	}
+}
+
+// parseExportPath reads the patient and export IDs from the path.
+func parseExportPath(c *gin.Context) (patientID, exportID uuid.UUID, ok bool) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	exportID, err = uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid export ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return patientID, exportID, true
+}
+
+// RequestExport godoc
+// @Summary Request an export of everything held about a patient
+// @Description Admins and privacy officers, to answer a subject access request. The archive is built in the background; poll the export until it is completed, then follow download_url. While an export of the patient is pending or running, that export is returned. Refused with 403 unless the patient's data_sharing consent is in force.
+// @Tags Patient Export
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 202 {object} model.PatientExport
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden, or consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/export [post]
+func (h *PatientExportHandler) RequestExport(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	export, err := h.exportService.RequestExport(c.Request.Context(), patientID, userID)
+	if err != nil {
+		writePatientExportError(c, h.logger, err, "Failed to request export")
+		return
+	}
+	withDownloadURL(export)
+	c.JSON(http.StatusAccepted, export)
+}
+
+// ListExports godoc
+// @Summary List a patient's data exports
+// @Description Admins and privacy officers. Newest first.
+// @Tags Patient Export
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.PatientExport}
+// @Failure 400 {object} model.APIError "Invalid patient ID or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/export [get]
+func (h *PatientExportHandler) ListExports(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	exports, total, err := h.exportService.ListExports(c.Request.Context(), patientID, params)
+	if err != nil {
+		writePatientExportError(c, h.logger, err, "Failed to list exports")
+		return
+	}
+	for i := range exports {
+		withDownloadURL(&exports[i])
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: exports, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// GetExport godoc
+// @Summary Get the status of a patient data export
+// @Description Admins and privacy officers.
+// @Tags Patient Export
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param exportId path string true "Export ID (UUID)" Format(uuid)
+// @Success 200 {object} model.PatientExport
+// @Failure 400 {object} model.APIError "Invalid ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Export not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /patients/{id}/export/{exportId} [get]
+func (h *PatientExportHandler) GetExport(c *gin.Context) {
+	patientID, exportID, ok := parseExportPath(c)
+	if !ok {
+		return
+	}
+	export, err := h.exportService.GetExport(c.Request.Context(), patientID, exportID)
+	if err != nil {
+		writePatientExportError(c, h.logger, err, "Failed to get export")
+		return
+	}
+	withDownloadURL(export)
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadExport godoc
+// @Summary Download a patient data export
+// @Description Admins and privacy officers. A ZIP of JSON files with everything held about the patient and report.html, a readable version for the patient. Available until the export's expires_at, while the patient's data_sharing consent is in force.
+// @Tags Patient Export
+// @Security BearerAuth
+// @Produce application/zip
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param exportId path string true "Export ID (UUID)" Format(uuid)
+// @Success 200 {file} file
+// @Failure 400 {object} model.APIError "Invalid ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden, or consent missing or withdrawn"
+// @Failure 404 {object} model.APIError "Export not found"
+// @Failure 409 {object} model.APIError "Export not completed yet"
+// @Failure 410 {object} model.APIError "Export expired"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/export/{exportId}/download [get]
+func (h *PatientExportHandler) DownloadExport(c *gin.Context) {
+	patientID, exportID, ok := parseExportPath(c)
+	if !ok {
+		return
+	}
+	export, archive, err := h.exportService.DownloadExport(c.Request.Context(), patientID, exportID)
+	if err != nil {
+		writePatientExportError(c, h.logger, err, "Failed to download export")
+		return
+	}
+	generatedAt := export.RequestedAt
+	if export.CompletedAt != nil {
+		generatedAt = *export.CompletedAt
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, sar.FileName(patientID, generatedAt)))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
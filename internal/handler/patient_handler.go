@@ -1,8 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt" // For error formatting
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -18,10 +19,11 @@ import (
 
 type PatientHandler struct {
 	patientService service.PatientService
+	logger         *slog.Logger
 }
 
-func NewPatientHandler(patientService service.PatientService) *PatientHandler {
-	return &PatientHandler{patientService: patientService}
+func NewPatientHandler(patientService service.PatientService, logger *slog.Logger) *PatientHandler {
+	return &PatientHandler{patientService: patientService, logger: logger.With("handler", "PatientHandler")}
 }
 // 
 
@@ -35,20 +37,9 @@ func parsePatientRequest(c *gin.Context, req interface{}) (*model.ParsedPatientR
 
 	switch r := req.(type) {
 	case model.PatientCreateRequest:
-		if r.DateOfBirth != "" {
-			dob, err = time.Parse("2006-01-02", r.DateOfBirth)
-			if err != nil {
-				return nil, fmt.Errorf("invalid date_of_birth format: %w. Expected YYYY-MM-DD", err)
-			}
+		if *parsedReq, err = r.Parse(); err != nil {
+			return nil, err
 		}
-		parsedReq.FirstName = r.FirstName
-		parsedReq.LastName = r.LastName
-		parsedReq.DateOfBirth = dob
-		parsedReq.Gender = r.Gender
-		parsedReq.ContactPhone = r.ContactPhone
-		parsedReq.ContactEmail = r.ContactEmail
-		parsedReq.Address = r.Address
-		parsedReq.MedicalHistory = r.MedicalHistory
 	case model.PatientUpdateRequest:
 		if r.DateOfBirthStr != nil && *r.DateOfBirthStr != "" {
 			dob, err = time.Parse("2006-01-02", *r.DateOfBirthStr)
@@ -91,42 +82,46 @@ func parsePatientRequest(c *gin.Context, req interface{}) (*model.ParsedPatientR
 // @Failure 400 {object} model.APIError "Validation error or invalid input"
 // @Failure 401 {object} model.APIError "Unauthorized"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /patients/create [post]
 func (h *PatientHandler) RegisterPatient(c *gin.Context) {
 	var req model.PatientCreateRequest
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
 	parsedReq, err := parsePatientRequest(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: err.Error()}) // Error from parsePatientRequest
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()}) // Error from parsePatientRequest
 		return
 	}
 
 	userID, ok := middleware.GetUserIDFromContext(c)
 	if !ok {
 		// This should ideally not happen if AuthMiddleware is working
-		log.Printf("CRITICAL: UserID not found in context for an authenticated route in RegisterPatient")
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		h.logger.ErrorContext(c.Request.Context(), "UserID not found in context for an authenticated route", "route", c.FullPath())
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		return
 	}
 
 	patient, err := h.patientService.RegisterPatient(c.Request.Context(), *parsedReq, userID)
 	if err != nil {
-		log.Printf("Register patient error: %v by user %s", err, userID)
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "Register patient failed", "user_id", userID, "error", err)
 		// Check for specific errors, e.g., duplicate contact info if your DB has unique constraints
 		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") || strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
-			c.JSON(http.StatusConflict, model.APIError{Message: "Failed to register patient due to conflicting data (e.g., phone or email already exists)."})
+			writeError(c, http.StatusConflict, model.APIError{Message: "Failed to register patient due to conflicting data (e.g., phone or email already exists)."})
 		} else {
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to register patient"})
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to register patient"})
 		}
 		return
 	}
@@ -136,39 +131,90 @@ func (h *PatientHandler) RegisterPatient(c *gin.Context) {
 
 // GetPatient godoc
 // @Summary Get details of a specific patient
-// @Description Receptionists and Doctors can get details of a specific patient.
+// @Description Doctors see patients in their care (care team, own visits or break-glass grant); others get 404. Other roles see demographics only, without medical_history.
 // @Tags Patients
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Patient ID"
 // @Success 200 {object} model.Patient
+// @Header 200 {string} ETag "Version of the patient; send it as If-Match when updating"
 // @Failure 400 {object} model.APIError "Invalid patient ID format"
 // @Failure 404 {object} model.APIError "Patient not found"
 // @Failure 401 {object} model.APIError "Unauthorized"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /patients/{id} [get]
 func (h *PatientHandler) GetPatient(c *gin.Context) {
 	patientIDStr := c.Param("id")
 	patientID,err := uuid.Parse(patientIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
 		return
 	}
 
 	
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
 		return
 	}
 
+	h.writePatient(c, patientID, http.StatusOK)
+}
+
+// writePatient writes the patient as currently stored, with its ETag, under the given
+// status: 200 for a read, 412 when an update was made against an older version.
+func (h *PatientHandler) writePatient(c *gin.Context, patientID uuid.UUID, status int) {
 	patient, err := h.patientService.GetPatientDetails(c.Request.Context(), patientID)
 	if err != nil {
+		if writeAuditUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: "Patient not found"})
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Patient not found"})
+		} else {
+			h.logger.ErrorContext(c.Request.Context(), "Get patient failed", "patient_id", patientID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to get patient details"})
+		}
+		return
+	}
+
+	c.Header("ETag", etag(patient.Version))
+	c.JSON(status, patient)
+}
+
+// LookupPatient godoc
+// @Summary Find a patient by phone number or email address
+// @Description Exact match after normalising: email case and surrounding spaces are ignored, and phone numbers are compared on their digits and leading "+". Give exactly one of phone or email. The same scoping and redaction as getting a patient by ID apply.
+// @Tags Patients
+// @Security BearerAuth
+// @Produce json
+// @Param phone query string false "Contact phone number"
+// @Param email query string false "Contact email address"
+// @Success 200 {object} model.Patient
+// @Failure 400 {object} model.APIError "Neither or both of phone and email given"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/lookup [get]
+func (h *PatientHandler) LookupPatient(c *gin.Context) {
+	phone, email := strings.TrimSpace(c.Query("phone")), strings.TrimSpace(c.Query("email"))
+	if (phone == "") == (email == "") {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Give exactly one of phone or email"})
+		return
+	}
+
+	patient, err := h.patientService.FindPatientByContact(c.Request.Context(), phone, email)
+	if err != nil {
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrPatientNotFound) {
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Patient not found"})
 		} else {
-			log.Printf("Get patient error for ID %s: %v", patientIDStr, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to get patient details"})
+			h.logger.ErrorContext(c.Request.Context(), "Lookup patient failed", "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to look up patient"})
 		}
 		return
 	}
@@ -178,7 +224,7 @@ func (h *PatientHandler) GetPatient(c *gin.Context) {
 
 // ListPatients godoc
 // @Summary List all registered patients
-// @Description Receptionists and Doctors can list all registered patients.
+// @Description Doctors list the patients in their care. Other roles list all patients, demographics only.
 // @Tags Patients
 // @Security BearerAuth
 // @Accept json
@@ -189,11 +235,12 @@ func (h *PatientHandler) GetPatient(c *gin.Context) {
 // @Failure 400 {object} model.APIError "Invalid pagination parameters"
 // @Failure 401 {object} model.APIError "Unauthorized"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /patients [get]
 func (h *PatientHandler) ListPatients(c *gin.Context) {
 	var params model.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid pagination parameters", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid pagination parameters", Details: err.Error()})
 		return
 	}
 	if params.Limit <= 0 {
@@ -208,8 +255,11 @@ func (h *PatientHandler) ListPatients(c *gin.Context) {
 
 	patients, total, err := h.patientService.ListPatients(c.Request.Context(), params)
 	if err != nil {
-		log.Printf("List patients error: %v", err)
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to list patients"})
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "List patients failed", "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to list patients"})
 		return
 	}
 
@@ -225,53 +275,62 @@ func (h *PatientHandler) ListPatients(c *gin.Context) {
 
 // UpdatePatient godoc
 // @Summary Update patient details
-// @Description Receptionists can update most patient details. Doctors can update patient details, especially medical history.
+// @Description Receptionists can update most patient details. Doctors can update patient details, especially medical history. Send the ETag of the patient as If-Match; if someone else updated the patient since, nothing is changed and the current patient is returned with 412.
 // @Tags Patients
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param If-Match header string true "ETag from the last GET, or * to update whatever version is current"
 // @Param patientRequest body model.PatientUpdateRequest true "Patient Update Data (fields to update)"
 // @Success 200 {object} model.Patient
+// @Header 200 {string} ETag "New version of the patient"
 // @Failure 400 {object} model.APIError "Validation error, invalid input, or invalid patient ID"
 // @Failure 401 {object} model.APIError "Unauthorized"
 // @Failure 403 {object} model.APIError "Forbidden (e.g., if trying to update restricted fields)"
 // @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "Phone or email taken, or the patient has been pseudonymized"
+// @Failure 412 {object} model.Patient "Patient changed since the If-Match version; the current patient, with its ETag"
+// @Failure 428 {object} model.APIError "If-Match header missing"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /patients/{id} [patch]
 func (h *PatientHandler) UpdatePatient(c *gin.Context) {
 	patientIDStr := c.Param("id")
 	patientID := uuid.MustParse(patientIDStr)
-	
+	ifMatch, ok := bindIfMatch(c)
+	if !ok {
+		return
+	}
 
 	var req model.PatientUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
 	parsedReq, err := parsePatientRequest(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
 		return
 	}
 
 	user, ok := c.Get("info")
 	if !ok {
-		log.Printf("CRITICAL: User context error")
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		h.logger.ErrorContext(c.Request.Context(), "User context missing for an authenticated route", "route", c.FullPath())
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		return
 	}
 	_,ok = user.(authorization.Info)
 
 	if !ok {
-		log.Printf("CRITICAL: User context error")
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		h.logger.ErrorContext(c.Request.Context(), "User context missing for an authenticated route", "route", c.FullPath())
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		return
 	}
 
@@ -279,21 +338,29 @@ func (h *PatientHandler) UpdatePatient(c *gin.Context) {
 	userRole := model.UserRole(role)
 
 
-	patient, err := h.patientService.UpdatePatientDetails(c.Request.Context(), patientID, *parsedReq, userRole, user.(authorization.Info).ID.Bytes)
+	patient, err := h.patientService.UpdatePatientDetails(c.Request.Context(), patientID, *parsedReq, userRole, user.(authorization.Info).ID.Bytes, ifMatch)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: "Patient not found"})
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrPatientVersionConflict) {
+			h.writePatient(c, patientID, http.StatusPreconditionFailed)
+		} else if errors.Is(err, service.ErrPatientPseudonymized) {
+			writeError(c, http.StatusConflict, model.APIError{Message: "Patient has been pseudonymized; identifiers can no longer be changed"})
+		} else if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Patient not found"})
 		} else if strings.Contains(strings.ToLower(err.Error()), "restricted") || strings.Contains(strings.ToLower(err.Error()), "authorized to update") {
-			c.JSON(http.StatusForbidden, model.APIError{Message: err.Error()})
+			writeError(c, http.StatusForbidden, model.APIError{Message: err.Error()})
 		} else if strings.Contains(strings.ToLower(err.Error()), "unique constraint") || strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
-			c.JSON(http.StatusConflict, model.APIError{Message: "Update failed due to conflicting data (e.g., phone or email already exists for another patient)."})
+			writeError(c, http.StatusConflict, model.APIError{Message: "Update failed due to conflicting data (e.g., phone or email already exists for another patient)."})
 		} else {
-			log.Printf("Update patient error for ID %s: %v", patientIDStr, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to update patient details"})
+			h.logger.ErrorContext(c.Request.Context(), "Update patient failed", "patient_id", patientIDStr, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to update patient details"})
 		}
 		return
 	}
 
+	c.Header("ETag", etag(patient.Version))
 	c.JSON(http.StatusOK, patient)
 }
 
@@ -309,6 +376,7 @@ func (h *PatientHandler) UpdatePatient(c *gin.Context) {
 // @Failure 403 {object} model.APIError "Forbidden (e.g., if trying to update restricted fields)"
 // @Failure 404 {object} model.APIError "Patient not found"
 // @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /patients/{id} [delete]
 func (h *PatientHandler) DeletePatient(c *gin.Context) {
 	patientIDStr := c.Param("id")
@@ -316,18 +384,21 @@ func (h *PatientHandler) DeletePatient(c *gin.Context) {
 
 	userID, ok := middleware.GetUserIDFromContext(c)
 	if !ok {
-		log.Printf("CRITICAL: UserID not found in context for an authenticated route in DeletePatient")
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		h.logger.ErrorContext(c.Request.Context(), "UserID not found in context for an authenticated route", "route", c.FullPath())
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		return
 	}
 
 	err := h.patientService.DeletePatientRecord(c.Request.Context(), patientID, userID) // userID for audit
 	if err != nil {
+		if writeAuditUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "not found") || strings.Contains(strings.ToLower(err.Error()), "already deleted") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: "Patient not found or already deleted"})
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Patient not found or already deleted"})
 		} else {
-			log.Printf("Delete patient error for ID %s: %v", patientIDStr, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to delete patient"})
+			h.logger.ErrorContext(c.Request.Context(), "Delete patient failed", "patient_id", patientIDStr, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to delete patient"})
 		}
 		return
 	}
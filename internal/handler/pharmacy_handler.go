@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type PharmacyHandler struct {
+	pharmacyService service.PharmacyService
+	logger          *slog.Logger
+}
+
+func NewPharmacyHandler(pharmacyService service.PharmacyService, logger *slog.Logger) *PharmacyHandler {
+	return &PharmacyHandler{pharmacyService: pharmacyService, logger: logger.With("handler", "PharmacyHandler")}
+}
+
+// writePharmacyError maps pharmacy service errors to HTTP responses.
+func writePharmacyError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrPharmacyLocationNotFound),
+		errors.Is(err, service.ErrPharmacyItemNotFound),
+		errors.Is(err, service.ErrBatchNotFound),
+		errors.Is(err, service.ErrStockMovementNotFound),
+		errors.Is(err, service.ErrVisitNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrBatchExpired),
+		errors.Is(err, service.ErrNotADispense),
+		errors.Is(err, service.ErrNoPrescription):
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPharmacyLocationAlreadyExists),
+		errors.Is(err, service.ErrPharmacyItemAlreadyExists),
+		errors.Is(err, service.ErrBatchExpiryMismatch),
+		errors.Is(err, service.ErrInsufficientStock),
+		errors.Is(err, service.ErrReturnExceedsDispensed):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// optionalUUIDQuery parses an optional UUID query parameter, writing a 400 when it is malformed.
+func optionalUUIDQuery(c *gin.Context, name string) (*uuid.UUID, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid " + name + " format"})
+		return nil, false
+	}
+	return &id, true
+}
+
+// CreateLocation godoc
+// @Summary Add a pharmacy store location
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.PharmacyLocationCreateRequest true "Location"
+// @Success 201 {object} model.PharmacyLocation
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 409 {object} model.APIError "Code already exists"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/locations [post]
+func (h *PharmacyHandler) CreateLocation(c *gin.Context) {
+	var req model.PharmacyLocationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	location, err := h.pharmacyService.CreateLocation(c.Request.Context(), req)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to create pharmacy location")
+		return
+	}
+	c.JSON(http.StatusCreated, location)
+}
+
+// ListLocations godoc
+// @Summary List active pharmacy store locations
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.PharmacyLocation
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/locations [get]
+func (h *PharmacyHandler) ListLocations(c *gin.Context) {
+	locations, err := h.pharmacyService.ListLocations(c.Request.Context())
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list pharmacy locations")
+		return
+	}
+	c.JSON(http.StatusOK, locations)
+}
+
+// CreateItem godoc
+// @Summary Add a drug to the formulary
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.PharmacyItemCreateRequest true "Item"
+// @Success 201 {object} model.PharmacyItem
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 409 {object} model.APIError "Code already exists"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/items [post]
+func (h *PharmacyHandler) CreateItem(c *gin.Context) {
+	var req model.PharmacyItemCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	item, err := h.pharmacyService.CreateItem(c.Request.Context(), req)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to create pharmacy item")
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// ListItems godoc
+// @Summary List the formulary
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.PharmacyItem}
+// @Failure 400 {object} model.APIError "Invalid pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/items [get]
+func (h *PharmacyHandler) ListItems(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	items, total, err := h.pharmacyService.ListItems(c.Request.Context(), params)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list pharmacy items")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: items, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// ReceiveStock godoc
+// @Summary Record a goods receipt
+// @Description Books delivered lots into a store location. Lots already known for an item must carry the same expiry date.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.GoodsReceiptRequest true "Goods receipt"
+// @Success 201 {array} model.StockMovement
+// @Failure 400 {object} model.APIError "Validation error, invalid input or expired lot"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Location or item not found"
+// @Failure 409 {object} model.APIError "Lot received with a different expiry date"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/receipts [post]
+func (h *PharmacyHandler) ReceiveStock(c *gin.Context) {
+	var req model.GoodsReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	movements, err := h.pharmacyService.ReceiveStock(c.Request.Context(), req, userID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to receive stock")
+		return
+	}
+	c.JSON(http.StatusCreated, movements)
+}
+
+// DispenseForVisit godoc
+// @Summary Dispense a visit's prescription
+// @Description Each line is drawn from the earliest-expiring unexpired batches at the location (FEFO).
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Param request body model.DispenseRequest true "Items to dispense"
+// @Success 201 {array} model.StockMovement
+// @Failure 400 {object} model.APIError "Validation error, invalid input or no prescription"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit, location or item not found"
+// @Failure 409 {object} model.APIError "Insufficient stock"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /visits/{id}/dispense [post]
+func (h *PharmacyHandler) DispenseForVisit(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	var req model.DispenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	movements, err := h.pharmacyService.DispenseForVisit(c.Request.Context(), visitID, req, userID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to dispense")
+		return
+	}
+	c.JSON(http.StatusCreated, movements)
+}
+
+// ListVisitDispensations godoc
+// @Summary List stock dispensed and returned for a visit
+// @Description Doctors list dispensations of visits of patients in their care; others get 404. Roles other than pharmacist see the movements without the item, batch or reason.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Success 200 {array} model.StockMovement
+// @Failure 400 {object} model.APIError "Invalid visit ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /visits/{id}/dispensations [get]
+func (h *PharmacyHandler) ListVisitDispensations(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	movements, err := h.pharmacyService.ListVisitDispensations(c.Request.Context(), visitID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list dispensations")
+		return
+	}
+	c.JSON(http.StatusOK, movements)
+}
+
+// ReturnDispensed godoc
+// @Summary Return dispensed stock
+// @Description Puts part or all of a dispense back into the batch and location it came from.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispense movement ID (UUID)" Format(uuid)
+// @Param request body model.StockReturnRequest true "Return"
+// @Success 201 {object} model.StockMovement
+// @Failure 400 {object} model.APIError "Validation error or movement is not a dispense"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Movement not found"
+// @Failure 409 {object} model.APIError "Return exceeds the quantity dispensed"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/movements/{id}/returns [post]
+func (h *PharmacyHandler) ReturnDispensed(c *gin.Context) {
+	movementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid movement ID format"})
+		return
+	}
+	var req model.StockReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	movement, err := h.pharmacyService.ReturnDispensed(c.Request.Context(), movementID, req, userID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to return stock")
+		return
+	}
+	c.JSON(http.StatusCreated, movement)
+}
+
+// AdjustStock godoc
+// @Summary Adjust a batch's stock
+// @Description Records a counted correction, breakage or write-off. A negative quantity removes stock.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body model.StockAdjustmentRequest true "Adjustment"
+// @Success 201 {object} model.StockMovement
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Location or batch not found"
+// @Failure 409 {object} model.APIError "Adjustment would take stock below zero"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/adjustments [post]
+func (h *PharmacyHandler) AdjustStock(c *gin.Context) {
+	var req model.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	movement, err := h.pharmacyService.AdjustStock(c.Request.Context(), req, userID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to adjust stock")
+		return
+	}
+	c.JSON(http.StatusCreated, movement)
+}
+
+// ListStockLevels godoc
+// @Summary List stock on hand by batch
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param location_id query string false "Location ID (UUID)" Format(uuid)
+// @Param item_id query string false "Item ID (UUID)" Format(uuid)
+// @Success 200 {array} model.StockLevel
+// @Failure 400 {object} model.APIError "Invalid filter"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/stock [get]
+func (h *PharmacyHandler) ListStockLevels(c *gin.Context) {
+	locationID, ok := optionalUUIDQuery(c, "location_id")
+	if !ok {
+		return
+	}
+	itemID, ok := optionalUUIDQuery(c, "item_id")
+	if !ok {
+		return
+	}
+	levels, err := h.pharmacyService.ListStockLevels(c.Request.Context(), locationID, itemID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list stock levels")
+		return
+	}
+	c.JSON(http.StatusOK, levels)
+}
+
+// ListMovements godoc
+// @Summary List the stock movement ledger
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param location_id query string false "Location ID (UUID)" Format(uuid)
+// @Param item_id query string false "Item ID (UUID)" Format(uuid)
+// @Param batch_id query string false "Batch ID (UUID)" Format(uuid)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.StockMovement}
+// @Failure 400 {object} model.APIError "Invalid filter or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/movements [get]
+func (h *PharmacyHandler) ListMovements(c *gin.Context) {
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	var filter model.StockMovementFilter
+	if filter.LocationID, ok = optionalUUIDQuery(c, "location_id"); !ok {
+		return
+	}
+	if filter.ItemID, ok = optionalUUIDQuery(c, "item_id"); !ok {
+		return
+	}
+	if filter.BatchID, ok = optionalUUIDQuery(c, "batch_id"); !ok {
+		return
+	}
+	movements, total, err := h.pharmacyService.ListMovements(c.Request.Context(), filter, params)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list stock movements")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: movements, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// ListReorderAlerts godoc
+// @Summary List items at or below their reorder level
+// @Description Only unexpired stock counts towards the on-hand quantity.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param location_id query string false "Location ID (UUID)" Format(uuid)
+// @Success 200 {array} model.ReorderAlert
+// @Failure 400 {object} model.APIError "Invalid filter"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/reorder-alerts [get]
+func (h *PharmacyHandler) ListReorderAlerts(c *gin.Context) {
+	locationID, ok := optionalUUIDQuery(c, "location_id")
+	if !ok {
+		return
+	}
+	alerts, err := h.pharmacyService.ListReorderAlerts(c.Request.Context(), locationID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to list reorder alerts")
+		return
+	}
+	c.JSON(http.StatusOK, alerts)
+}
+
+// NearExpiryReport godoc
+// @Summary Report stock nearing expiry
+// @Description Lists stock on hand expiring within the given number of days, including lots already expired.
+// @Tags Pharmacy
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Window in days (default: 90)" minimum(0) maximum(730)
+// @Param location_id query string false "Location ID (UUID)" Format(uuid)
+// @Success 200 {array} model.StockLevel
+// @Failure 400 {object} model.APIError "Invalid filter"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pharmacy/near-expiry [get]
+func (h *PharmacyHandler) NearExpiryReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days < 0 || days > 730 {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "days must be an integer between 0 and 730"})
+		return
+	}
+	locationID, ok := optionalUUIDQuery(c, "location_id")
+	if !ok {
+		return
+	}
+	levels, err := h.pharmacyService.NearExpiryReport(c.Request.Context(), days, locationID)
+	if err != nil {
+		writePharmacyError(c, h.logger, err, "Failed to build near-expiry report")
+		return
+	}
+	c.JSON(http.StatusOK, levels)
+}
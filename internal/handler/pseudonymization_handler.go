@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/middleware"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/service"
+	util "github.com/himanshu-holmes/hms/internal/utils"
+)
+
+type PseudonymizationHandler struct {
+	pseudonymizationService service.PseudonymizationService
+	logger                  *slog.Logger
+}
+
+func NewPseudonymizationHandler(pseudonymizationService service.PseudonymizationService, logger *slog.Logger) *PseudonymizationHandler {
+	return &PseudonymizationHandler{pseudonymizationService: pseudonymizationService, logger: logger.With("handler", "PseudonymizationHandler")}
+}
+
+// writePseudonymizationError maps pseudonymization service errors to HTTP responses.
+func writePseudonymizationError(c *gin.Context, logger *slog.Logger, err error, fallback string) {
+	switch {
+	case writeAuditUnavailable(c, err):
+	case errors.Is(err, service.ErrPatientNotFound), errors.Is(err, service.ErrPseudonymizationRequestNotFound):
+		writeError(c, http.StatusNotFound, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPseudonymizationSelfApproval):
+		writeError(c, http.StatusForbidden, model.APIError{Message: err.Error()})
+	case errors.Is(err, service.ErrPseudonymizationPending), errors.Is(err, service.ErrPseudonymizationDecided), errors.Is(err, service.ErrPatientPseudonymized):
+		writeError(c, http.StatusConflict, model.APIError{Message: err.Error()})
+	default:
+		logger.ErrorContext(c.Request.Context(), fallback, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: fallback})
+	}
+}
+
+// bindDecision parses the optional body of an approval or rejection.
+func bindDecision(c *gin.Context) (uuid.UUID, model.PseudonymizationDecisionRequest, bool) {
+	var req model.PseudonymizationDecisionRequest
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request ID format"})
+		return uuid.Nil, req, false
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+			return uuid.Nil, req, false
+		}
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return uuid.Nil, req, false
+	}
+	return requestID, req, true
+}
+
+// PreviewPseudonymization godoc
+// @Summary Preview pseudonymizing a patient
+// @Description Admins and privacy officers. A dry run listing the identifiers that would be replaced or cleared and what stays linked to the patient. Nothing is changed.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Success 200 {object} model.PseudonymizationPreview
+// @Failure 400 {object} model.APIError "Invalid patient ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "Patient already pseudonymized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/pseudonymization/preview [get]
+func (h *PseudonymizationHandler) PreviewPseudonymization(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	preview, err := h.pseudonymizationService.Preview(c.Request.Context(), patientID)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to preview pseudonymization")
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// RequestPseudonymization godoc
+// @Summary Request that a patient be pseudonymized
+// @Description Admins and privacy officers. Records an erasure request; nothing changes until an admin other than the requester approves it.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Patient ID (UUID)" Format(uuid)
+// @Param request body model.PseudonymizationCreateRequest true "Reason"
+// @Success 201 {object} model.PseudonymizationRequest
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 409 {object} model.APIError "Already pseudonymized or a request is waiting"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /patients/{id}/pseudonymization [post]
+func (h *PseudonymizationHandler) RequestPseudonymization(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		return
+	}
+	var req model.PseudonymizationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(req); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	request, err := h.pseudonymizationService.RequestPseudonymization(c.Request.Context(), patientID, req, userID)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to request pseudonymization")
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// ListRequests godoc
+// @Summary List pseudonymization requests
+// @Description Admins and privacy officers. Newest first.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Param limit query int false "Limit (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} model.PaginatedResponse{data=[]model.PseudonymizationRequest}
+// @Failure 400 {object} model.APIError "Invalid status or pagination parameters"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pseudonymization-requests [get]
+func (h *PseudonymizationHandler) ListRequests(c *gin.Context) {
+	var status *model.PseudonymizationStatus
+	if raw := c.Query("status"); raw != "" {
+		s := model.PseudonymizationStatus(raw)
+		if s != model.PseudonymizationPending && s != model.PseudonymizationApproved && s != model.PseudonymizationRejected {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid pseudonymization request status"})
+			return
+		}
+		status = &s
+	}
+	params, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+	requests, total, err := h.pseudonymizationService.ListRequests(c.Request.Context(), status, params)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to list pseudonymization requests")
+		return
+	}
+	c.JSON(http.StatusOK, model.PaginatedResponse{Data: requests, Total: total, Limit: params.Limit, Offset: params.Offset})
+}
+
+// GetRequest godoc
+// @Summary Get a pseudonymization request
+// @Description Admins and privacy officers.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Request ID (UUID)" Format(uuid)
+// @Success 200 {object} model.PseudonymizationRequest
+// @Failure 400 {object} model.APIError "Invalid request ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 404 {object} model.APIError "Request not found"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Router /pseudonymization-requests/{id} [get]
+func (h *PseudonymizationHandler) GetRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request ID format"})
+		return
+	}
+	request, err := h.pseudonymizationService.GetRequest(c.Request.Context(), requestID)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to get pseudonymization request")
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// ApproveRequest godoc
+// @Summary Approve a pseudonymization request
+// @Description Admins other than the requester. Replaces the patient's name with a random token, truncates the date of birth to the year and clears contact details and address. Insurance member IDs take the same token and group numbers are cleared. Clinical records stay linked. The patient's data exports are cancelled. This cannot be undone.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID (UUID)" Format(uuid)
+// @Param request body model.PseudonymizationDecisionRequest false "Note"
+// @Success 200 {object} model.PseudonymizationRequest
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden, or the caller made the request"
+// @Failure 404 {object} model.APIError "Request not found"
+// @Failure 409 {object} model.APIError "Already decided, or the patient is already pseudonymized"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /pseudonymization-requests/{id}/approve [post]
+func (h *PseudonymizationHandler) ApproveRequest(c *gin.Context) {
+	requestID, req, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	request, err := h.pseudonymizationService.ApproveRequest(c.Request.Context(), requestID, req, userID)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to approve pseudonymization request")
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// RejectRequest godoc
+// @Summary Reject a pseudonymization request
+// @Description Admins other than the requester. The patient is not changed.
+// @Tags Pseudonymization
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID (UUID)" Format(uuid)
+// @Param request body model.PseudonymizationDecisionRequest false "Note"
+// @Success 200 {object} model.PseudonymizationRequest
+// @Failure 400 {object} model.APIError "Validation error or invalid input"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden, or the caller made the request"
+// @Failure 404 {object} model.APIError "Request not found"
+// @Failure 409 {object} model.APIError "Already decided"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /pseudonymization-requests/{id}/reject [post]
+func (h *PseudonymizationHandler) RejectRequest(c *gin.Context) {
+	requestID, req, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	request, err := h.pseudonymizationService.RejectRequest(c.Request.Context(), requestID, req, userID)
+	if err != nil {
+		writePseudonymizationError(c, h.logger, err, "Failed to reject pseudonymization request")
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/research"
+	"github.com/himanshu-holmes/hms/internal/service"
+)
+
+type ResearchHandler struct {
+	researchService service.ResearchExportService
+	logger          *slog.Logger
+}
+
+func NewResearchHandler(researchService service.ResearchExportService, logger *slog.Logger) *ResearchHandler {
+	return &ResearchHandler{researchService: researchService, logger: logger.With("handler", "ResearchHandler")}
+}
+
+// bindResearchOptions reads the de-identification options from the query string, starting
+// from research.DefaultOptions.
+func bindResearchOptions(c *gin.Context) (research.Options, research.Format, bool) {
+	opts := research.DefaultOptions()
+	format := research.Format(c.DefaultQuery("format", string(research.FormatCSV)))
+	if !format.Valid() {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid format, use csv or parquet"})
+		return opts, format, false
+	}
+	if raw := c.Query("dates"); raw != "" {
+		opts.Dates = research.DateMode(raw)
+	}
+	if raw := c.Query("geography"); raw != "" {
+		opts.Geography = research.GeographyMode(raw)
+	}
+	for name, target := range map[string]*int{"max_shift_days": &opts.MaxShiftDays, "k": &opts.K} {
+		if raw := c.Query(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				writeError(c, http.StatusBadRequest, model.APIError{Message: fmt.Sprintf("Invalid %s", name)})
+				return opts, format, false
+			}
+			*target = n
+		}
+	}
+	if raw := c.Query("free_text"); raw != "" {
+		freeText, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid free_text"})
+			return opts, format, false
+		}
+		opts.FreeText = freeText
+	}
+	if err := opts.Validate(); err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid de-identification options", Details: err.Error()})
+		return opts, format, false
+	}
+	return opts, format, true
+}
+
+// ExportDataset godoc
+// @Summary Export a de-identified research dataset
+// @Description Admins and privacy officers. A ZIP with patients and visits of every patient whose research consent is in force, de-identified Safe Harbor style, plus k_anonymity.json checking the quasi-identifiers (birth year, age, gender, region) and manifest.json. Names, contact details and the address are dropped; patients and visits get pseudonymous IDs that stay the same across exports. Dates are cut to the year or shifted by a per-patient offset, ages over 89 are reported as "90+" and the address becomes a three-digit postal area. Symptoms, diagnoses, prescriptions and notes are only included with free_text=true.
+// @Tags Research
+// @Security BearerAuth
+// @Produce application/zip
+// @Param format query string false "Table format (default: csv)" Enums(csv, parquet)
+// @Param dates query string false "year keeps only the year, shift moves each patient's dates by a fixed random offset (default: year)" Enums(year, shift)
+// @Param max_shift_days query int false "Largest date shift in days with dates=shift (default: 365)" minimum(1) maximum(3650)
+// @Param geography query string false "postal3 keeps the first three digits of the postal code, none drops it (default: postal3)" Enums(postal3, none)
+// @Param free_text query bool false "Include visit symptoms, diagnoses, prescriptions and notes (default: false)"
+// @Param k query int false "Smallest group size for the k-anonymity check (default: 5)" minimum(2)
+// @Success 200 {file} file
+// @Failure 400 {object} model.APIError "Invalid options"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Forbidden"
+// @Failure 500 {object} model.APIError "Internal server error"
+// @Failure 503 {object} model.APIError "Research exports not configured, or audit trail unavailable"
+// @Router /research/dataset [get]
+func (h *ResearchHandler) ExportDataset(c *gin.Context) {
+	opts, format, ok := bindResearchOptions(c)
+	if !ok {
+		return
+	}
+	dataset, err := h.researchService.Export(c.Request.Context(), opts, time.Now())
+	switch {
+	case err == nil:
+	case writeAuditUnavailable(c, err):
+		return
+	case errors.Is(err, service.ErrResearchExportDisabled):
+		writeError(c, http.StatusServiceUnavailable, model.APIError{Message: err.Error()})
+		return
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "Export research dataset failed", "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to export research dataset"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := research.WriteZip(&buf, *dataset, format); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Writing research dataset failed", "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to export research dataset"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, research.FileName(dataset.GeneratedAt)))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
@@ -1,8 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/himanshu-holmes/hms/internal/authorization"
+	"github.com/himanshu-holmes/hms/internal/middleware"
 	"github.com/himanshu-holmes/hms/internal/model"
 	"github.com/himanshu-holmes/hms/internal/service"
 	util "github.com/himanshu-holmes/hms/internal/utils"
@@ -18,10 +20,11 @@ import (
 
 type PatientVisitHandler struct {
 	visitService service.PatientVisitService
+	logger       *slog.Logger
 }
 
-func NewPatientVisitHandler(visitService service.PatientVisitService) *PatientVisitHandler {
-	return &PatientVisitHandler{visitService: visitService}
+func NewPatientVisitHandler(visitService service.PatientVisitService, logger *slog.Logger) *PatientVisitHandler {
+	return &PatientVisitHandler{visitService: visitService, logger: logger.With("handler", "PatientVisitHandler")}
 }
 
 // parseVisitRequest handles parsing for both create and update visit requests.
@@ -83,6 +86,7 @@ func parseVisitRequest(c *gin.Context, req interface{}) (*model.ParsedPatientVis
 		parsedReq.Diagnosis = r.Diagnosis
 		parsedReq.Prescription = r.Prescription
 		parsedReq.Notes = r.Notes
+		parsedReq.AmendmentReason = r.AmendmentReason
 	default:
 		return nil, fmt.Errorf("unsupported request type for patient visit parsing")
 	}
@@ -91,7 +95,7 @@ func parseVisitRequest(c *gin.Context, req interface{}) (*model.ParsedPatientVis
 
 // RecordPatientVisit godoc
 // @Summary Record a visit for a patient
-// @Description Doctors can record a visit for a patient.
+// @Description Doctors can record a visit for a patient in their care, or one they hold a break-glass grant for. Recording a visit does not add the doctor to the care team.
 // @Tags Visits
 // @Security BearerAuth
 // @Produce json
@@ -99,43 +103,50 @@ func parseVisitRequest(c *gin.Context, req interface{}) (*model.ParsedPatientVis
 // @Success 201 {object} model.PatientVisit
 // @Failure 400 {object} model.APIError "Invalid request body"
 // @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Not allowed to record a visit for this patient"
 // @Failure 404 {object} model.APIError "Patient not found"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Failure 500 {object} model.APIError "Failed to record patient visit"
 // @Router /visits/create [post]
 func (h *PatientVisitHandler) RecordPatientVisit(c *gin.Context) {
 	var req model.PatientVisitCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
 	parsedReq, err := parseVisitRequest(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
 		return
 	}
 	// Ensure PatientID was part of the create request
 	if parsedReq.PatientID == uuid.Nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "patient_id is required for creating a visit"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "patient_id is required for creating a visit"})
 		return
 	}
 
 
 	visit, err := h.visitService.RecordPatientVisit(c.Request.Context(), *parsedReq)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "patient with id") && strings.Contains(strings.ToLower(err.Error()), "not found") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: fmt.Sprintf("Patient with ID %s not found", parsedReq.PatientID)})
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrPatientForVisitNotFound) {
+			writeError(c, http.StatusNotFound, model.APIError{Message: fmt.Sprintf("Patient with ID %s not found", parsedReq.PatientID)})
+		} else if errors.Is(err, service.ErrVisitRecordForbidden) {
+			writeError(c, http.StatusForbidden, model.APIError{Message: err.Error()})
 		} else if strings.Contains(strings.ToLower(err.Error()), "doctor id not found") { // from parseVisitRequest
-		    log.Printf("CRITICAL: DoctorID not found in context for RecordPatientVisit")
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		    h.logger.ErrorContext(c.Request.Context(), "DoctorID not found in context for an authenticated route", "route", c.FullPath())
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		} else {
-			log.Printf("Record patient visit error for patient %s by doctor %s: %v", parsedReq.PatientID, parsedReq.DoctorID, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to record patient visit"})
+			h.logger.ErrorContext(c.Request.Context(), "Record patient visit failed", "patient_id", parsedReq.PatientID, "doctor_id", parsedReq.DoctorID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to record patient visit"})
 		}
 		return
 	}
@@ -145,41 +156,53 @@ func (h *PatientVisitHandler) RecordPatientVisit(c *gin.Context) {
 
 // GetPatientVisitDetails godoc
 // @Summary Get details of a specific patient visit
-// @Description Doctors and Receptionists can get details of a specific patient visit.
+// @Description Doctors see visits of patients in their care; others get 404. Other roles see the visit without symptoms, diagnosis, prescription or notes.
 // @Tags Visits
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Visit ID (UUID) for which to get details" Format(uuid)
 // @Success 200 {object} model.PatientVisit
+// @Header 200 {string} ETag "Version of the visit; send it as If-Match when updating"
 // @Failure 400 {object} model.APIError "Invalid visit ID format"
 // @Failure 404 {object} model.APIError "Visit not found"
 // @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /visits/{id} [get]
 func (h *PatientVisitHandler) GetPatientVisitDetails(c *gin.Context) {
 	visitIDStr := c.Param("id")
 	visitID, err := util.GetUserIDFromString(visitIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
 		return
 	}
 
-	visit, err := h.visitService.GetPatientVisitDetails(c.Request.Context(), visitID.Bytes)
+	h.writeVisit(c, visitID.Bytes, http.StatusOK)
+}
+
+// writeVisit writes the visit as currently stored, with its ETag, under the given status:
+// 200 for a read, 412 when an update was made against an older version.
+func (h *PatientVisitHandler) writeVisit(c *gin.Context, visitID uuid.UUID, status int) {
+	visit, err := h.visitService.GetPatientVisitDetails(c.Request.Context(), visitID)
 	if err != nil {
+		if writeAuditUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: "Visit not found"})
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Visit not found"})
 		} else {
-			log.Printf("Get visit details error for ID %s: %v", visitIDStr, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to get visit details"})
+			h.logger.ErrorContext(c.Request.Context(), "Get visit details failed", "visit_id", visitID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to get visit details"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, visit)
+	c.Header("ETag", etag(visit.Version))
+	c.JSON(status, visit)
 }
 
 // ListPatientVisits godoc
 // @Summary List patient visits for a specific patient
-// @Description Doctors and Receptionists can list patient visits for a specific patient.
+// @Description Doctors list visits of patients in their care. Other roles see the visits without symptoms, diagnosis, prescription or notes.
 // @Tags Visits
 // @Security BearerAuth
 // @Produce json
@@ -188,18 +211,20 @@ func (h *PatientVisitHandler) GetPatientVisitDetails(c *gin.Context) {
 // @Success 200 {object} model.PaginatedResponse
 // @Failure 400 {object} model.APIError "Invalid patient ID format"
 // @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Patient not found or not in the caller's care"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /visits/{id}/list [get]
 func (h *PatientVisitHandler) ListPatientVisits(c *gin.Context) {
 	patientIDStr := c.Param("id")
 	// patientID, err := util.GetUserIDFromString(patientIDStr)
 	// if err != nil {
-	// 	c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+	// 	writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
 	// 	return
 	// }
 
 	var params model.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid pagination parameters", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid pagination parameters", Details: err.Error()})
 		return
 	}
 	if params.Limit <= 0 {
@@ -214,13 +239,20 @@ func (h *PatientVisitHandler) ListPatientVisits(c *gin.Context) {
 
 	patientID, err := uuid.Parse(patientIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid patient ID format"})
 		return
 	}
 	visits, total, err := h.visitService.ListPatientVisits(c.Request.Context(), patientID, params)
 	if err != nil {
-		log.Printf("List patient visits error for patient %s: %v", patientIDStr, err)
-		c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to list patient visits"})
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrPatientForVisitNotFound) {
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Patient not found"})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "List patient visits failed", "patient_id", patientIDStr, "error", err)
+		writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to list patient visits"})
 		return
 	}
 
@@ -235,61 +267,168 @@ func (h *PatientVisitHandler) ListPatientVisits(c *gin.Context) {
 
 // UpdatePatientVisit godoc
 // @Summary Update a specific patient visit
-// @Description Doctors can update patient visit details they recorded. Doctor ID is taken from authenticated user.
+// @Description Doctors can update patient visit details they recorded. Doctor ID is taken from authenticated user. Send the ETag of the visit as If-Match; if it was updated since, nothing is changed and the current visit is returned with 412. The version replaced is kept in the visit's history. Once the visit is finalized, changes are addenda and amendment_reason is required.
 // @Tags Visits
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Visit ID (UUID) for which to update details" Format(uuid)
+// @Param If-Match header string true "ETag from the last GET, or * to update whatever version is current"
 // @Param visit body model.PatientVisitUpdateRequest true "Patient visit details to update"
 // @Success 200 {object} model.PatientVisit
+// @Header 200 {string} ETag "New version of the visit"
 // @Failure 400 {object} model.APIError "Invalid visit ID format"
 // @Failure 400 {object} model.APIError "Invalid request body"
 // @Failure 400 {object} model.APIError "Validation failed"
+// @Failure 400 {object} model.APIError "Visit is finalized and amendment_reason is missing"
 // @Failure 404 {object} model.APIError "Visit not found"
 // @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 412 {object} model.PatientVisit "Visit changed since the If-Match version; the current visit, with its ETag"
+// @Failure 428 {object} model.APIError "If-Match header missing"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
 // @Router /visits/{id} [patch]
 func (h *PatientVisitHandler) UpdatePatientVisit(c *gin.Context) {
 	visitIDStr := c.Param("id")
 	visitID, err := uuid.Parse(visitIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	ifMatch, ok := bindIfMatch(c)
+	if !ok {
 		return
 	}
 
 	var req model.PatientVisitUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid request body", Details: err.Error()})
 		return
 	}
 
 	if err := util.ValidateStruct(req); err != nil { // Validate the update request struct
-		c.JSON(http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Validation failed", Details: util.FormatValidationErrors(err)})
 		return
 	}
 
 	parsedReq, err := parseVisitRequest(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.APIError{Message: err.Error()})
+		writeError(c, http.StatusBadRequest, model.APIError{Message: err.Error()})
 		return
 	}
 	// PatientID is not set from request for update, it's tied to the visitID
 	// DoctorID is set from context in parseVisitRequest
 
-	updatedVisit, err := h.visitService.UpdatePatientVisit(c.Request.Context(), visitID, *parsedReq)
+	updatedVisit, err := h.visitService.UpdatePatientVisit(c.Request.Context(), visitID, *parsedReq, ifMatch)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			c.JSON(http.StatusNotFound, model.APIError{Message: "Visit not found"})
+		if writeAuditUnavailable(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrVisitVersionConflict) {
+			h.writeVisit(c, visitID, http.StatusPreconditionFailed)
+		} else if errors.Is(err, service.ErrVisitAmendmentReasonRequired) {
+			writeError(c, http.StatusBadRequest, model.APIError{Message: "Visit is finalized; amendment_reason is required"})
+		} else if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Visit not found"})
 		} else if strings.Contains(strings.ToLower(err.Error()), "not authorized to update") {
-			c.JSON(http.StatusForbidden, model.APIError{Message: "Not authorized to update this visit record"})
+			writeError(c, http.StatusForbidden, model.APIError{Message: "Not authorized to update this visit record"})
 		} else if strings.Contains(strings.ToLower(err.Error()), "doctor id not found") { // from parseVisitRequest
-		    log.Printf("CRITICAL: DoctorID not found in context for UpdatePatientVisit")
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "User context error"})
+		    h.logger.ErrorContext(c.Request.Context(), "DoctorID not found in context for an authenticated route", "route", c.FullPath())
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "User context error"})
 		} else {
-			log.Printf("Update patient visit error for visit %s by doctor %s: %v", visitIDStr, parsedReq.DoctorID, err)
-			c.JSON(http.StatusInternalServerError, model.APIError{Message: "Failed to update patient visit"})
+			h.logger.ErrorContext(c.Request.Context(), "Update patient visit failed", "visit_id", visitIDStr, "doctor_id", parsedReq.DoctorID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to update patient visit"})
 		}
 		return
 	}
 
+	c.Header("ETag", etag(updatedVisit.Version))
 	c.JSON(http.StatusOK, updatedVisit)
-}
\ No newline at end of file
+}
+
+// FinalizePatientVisit godoc
+// @Summary Finalize a patient visit
+// @Description The doctor who recorded the visit signs it. Later changes are addenda that need an amendment_reason. Send the ETag of the visit as If-Match.
+// @Tags Visits
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Param If-Match header string true "ETag from the last GET, or * to finalize whatever version is current"
+// @Success 200 {object} model.PatientVisit
+// @Header 200 {string} ETag "New version of the visit"
+// @Failure 400 {object} model.APIError "Invalid visit ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 403 {object} model.APIError "Only the doctor who recorded the visit may finalize it"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Failure 409 {object} model.APIError "Visit is already finalized"
+// @Failure 412 {object} model.PatientVisit "Visit changed since the If-Match version; the current visit, with its ETag"
+// @Failure 428 {object} model.APIError "If-Match header missing"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /visits/{id}/finalize [post]
+func (h *PatientVisitHandler) FinalizePatientVisit(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+	ifMatch, ok := bindIfMatch(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	visit, err := h.visitService.FinalizeVisit(c.Request.Context(), visitID, userID, ifMatch)
+	if err != nil {
+		switch {
+		case writeAuditUnavailable(c, err):
+		case errors.Is(err, service.ErrVisitVersionConflict):
+			h.writeVisit(c, visitID, http.StatusPreconditionFailed)
+		case errors.Is(err, service.ErrVisitNotFound):
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Visit not found"})
+		case errors.Is(err, service.ErrVisitUpdateForbidden):
+			writeError(c, http.StatusForbidden, model.APIError{Message: "Only the doctor who recorded the visit may finalize it"})
+		case errors.Is(err, service.ErrVisitAlreadyFinalized):
+			writeError(c, http.StatusConflict, model.APIError{Message: "Visit is already finalized"})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "Finalize visit failed", "visit_id", visitID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to finalize patient visit"})
+		}
+		return
+	}
+
+	c.Header("ETag", etag(visit.Version))
+	c.JSON(http.StatusOK, visit)
+}
+
+// GetPatientVisitHistory godoc
+// @Summary Get the version history of a patient visit
+// @Description Every version of the visit, oldest first, with the fields each change made and its author. Addenda to a finalized visit carry their reason. Callers without clinical access see which clinical fields changed but not their values.
+// @Tags Visits
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Visit ID (UUID)" Format(uuid)
+// @Success 200 {object} model.VisitHistory
+// @Failure 400 {object} model.APIError "Invalid visit ID format"
+// @Failure 401 {object} model.APIError "Unauthorized"
+// @Failure 404 {object} model.APIError "Visit not found"
+// @Failure 503 {object} model.APIError "Audit trail unavailable"
+// @Router /visits/{id}/history [get]
+func (h *PatientVisitHandler) GetPatientVisitHistory(c *gin.Context) {
+	visitID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, model.APIError{Message: "Invalid visit ID format"})
+		return
+	}
+
+	history, err := h.visitService.GetVisitHistory(c.Request.Context(), visitID)
+	if err != nil {
+		switch {
+		case writeAuditUnavailable(c, err):
+		case errors.Is(err, service.ErrVisitNotFound):
+			writeError(c, http.StatusNotFound, model.APIError{Message: "Visit not found"})
+		default:
+			h.logger.ErrorContext(c.Request.Context(), "Get visit history failed", "visit_id", visitID, "error", err)
+			writeError(c, http.StatusInternalServerError, model.APIError{Message: "Failed to get visit history"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
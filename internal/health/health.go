@@ -0,0 +1,170 @@
+// Package health runs the checks behind the liveness, readiness and startup probes.
+// Subsystems register their own checks with a Registry, naming the probes each one
+// counts for.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Probe is one of the questions an orchestrator asks about the process.
+type Probe string
+
+const (
+	// Live asks whether the process should be restarted.
+	Live Probe = "live"
+	// Ready asks whether the process should be sent traffic.
+	Ready Probe = "ready"
+	// Startup asks whether the process has finished starting; the other probes wait for it.
+	Startup Probe = "startup"
+)
+
+// Check reports whether one dependency is healthy; nil means it is. It must return
+// once ctx is done.
+type Check func(ctx context.Context) error
+
+// Status of a check or of a whole probe.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every check of a probe. The probe passes when every check does.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK reports whether every check passed.
+func (r Report) OK() bool { return r.Status == StatusOK }
+
+type registered struct {
+	name   string
+	check  Check
+	probes []Probe
+}
+
+// Registry holds the checks of a process. It is safe for concurrent use.
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []registered
+}
+
+// NewRegistry returns an empty Registry whose checks each get timeout to answer.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a check that counts for the given probes. Names are unique.
+func (r *Registry) Register(name string, check Check, probes ...Probe) {
+	if len(probes) == 0 {
+		panic(fmt.Sprintf("health: check %q counts for no probe", name))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.checks {
+		if c.name == name {
+			panic(fmt.Sprintf("health: check %q registered twice", name))
+		}
+	}
+	r.checks = append(r.checks, registered{name: name, check: check, probes: probes})
+}
+
+// Run runs the checks of probe concurrently, each with the registry's timeout. A probe
+// with no checks passes.
+func (r *Registry) Run(ctx context.Context, probe Probe) Report {
+	r.mu.RLock()
+	var checks []registered
+	for _, c := range r.checks {
+		for _, p := range c.probes {
+			if p == probe {
+				checks = append(checks, c)
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.runOne(ctx, c.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// runOne runs check, giving up on it once the timeout passes even if it does not return.
+func (r *Registry) runOne(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("no answer within %s", r.timeout)
+	}
+
+	result := Result{Status: StatusOK, Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler answers probe with its report as JSON: 200 when it passes and 503 when it
+// does not.
+func (r *Registry) Handler(probe Probe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := r.Run(c.Request.Context(), probe)
+		status := http.StatusOK
+		if !report.OK() {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	}
+}
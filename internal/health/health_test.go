@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(context.Context) error { return nil }
+
+func TestRunOnlyRunsTheProbesChecks(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register("database", ok, Ready, Startup)
+	r.Register("shutdown", func(context.Context) error { return errors.New("shutting down") }, Ready)
+
+	ready := r.Run(context.Background(), Ready)
+	assert.False(t, ready.OK())
+	assert.Equal(t, StatusOK, ready.Checks["database"].Status)
+	assert.Equal(t, Result{Status: StatusFail, Error: "shutting down", Duration: ready.Checks["shutdown"].Duration}, ready.Checks["shutdown"])
+
+	startup := r.Run(context.Background(), Startup)
+	assert.True(t, startup.OK())
+	assert.Len(t, startup.Checks, 1)
+
+	live := r.Run(context.Background(), Live)
+	assert.True(t, live.OK(), "a probe with no checks passes")
+	assert.Empty(t, live.Checks)
+}
+
+func TestRunTimesOutHungChecks(t *testing.T) {
+	r := NewRegistry(20 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	r.Register("hung", func(context.Context) error { <-release; return nil }, Ready)
+	r.Register("panics", func(context.Context) error { panic("boom") }, Ready)
+
+	start := time.Now()
+	report := r.Run(context.Background(), Ready)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "no answer within 20ms", report.Checks["hung"].Error)
+	assert.Contains(t, report.Checks["panics"].Error, "boom")
+}
+
+func TestRegisterRejectsDuplicates(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register("database", ok, Ready)
+	assert.Panics(t, func() { r.Register("database", ok, Live) })
+	assert.Panics(t, func() { r.Register("nothing", ok) })
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewRegistry(time.Second)
+	healthy := true
+	r.Register("database", func(context.Context) error {
+		if !healthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, Ready)
+	router := gin.New()
+	router.GET("/readyz", r.Handler(Ready))
+
+	get := func() (int, Report) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, report := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+
+	healthy = false
+	code, report = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+}
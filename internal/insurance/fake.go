@@ -0,0 +1,77 @@
+package insurance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// FakeGateway is an in-memory PayerGateway for local development and tests. Every member is
+// eligible unless marked otherwise, and every claim is acknowledged with a sequential reference;
+// a claim sent again gets its first acknowledgement back.
+type FakeGateway struct {
+	mu         sync.Mutex
+	ineligible map[string]string // member ID -> reason
+	down       bool
+	claims     []ClaimSubmission
+	acks       map[uuid.UUID]ClaimAck
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{ineligible: make(map[string]string), acks: make(map[uuid.UUID]ClaimAck)}
+}
+
+// MarkIneligible makes eligibility checks for memberID fail with reason.
+func (g *FakeGateway) MarkIneligible(memberID, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ineligible[memberID] = reason
+}
+
+// SetUnavailable makes every call fail with ErrPayerUnavailable while down is true.
+func (g *FakeGateway) SetUnavailable(down bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.down = down
+}
+
+// SubmittedClaims returns the claims received so far.
+func (g *FakeGateway) SubmittedClaims() []ClaimSubmission {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]ClaimSubmission(nil), g.claims...)
+}
+
+func (g *FakeGateway) CheckEligibility(ctx context.Context, req EligibilityRequest) (EligibilityResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.down {
+		return EligibilityResponse{}, ErrPayerUnavailable
+	}
+	if reason, ok := g.ineligible[req.MemberID]; ok {
+		return EligibilityResponse{Status: model.EligibilityIneligible, Message: reason}, nil
+	}
+	return EligibilityResponse{
+		Status:    model.EligibilityEligible,
+		Message:   fmt.Sprintf("member %s active with %s", req.MemberID, req.PayerCode),
+		Reference: fmt.Sprintf("FAKE-ELIG-%s", req.DateOfService.Format("20060102")),
+	}, nil
+}
+
+func (g *FakeGateway) SubmitClaim(ctx context.Context, claim ClaimSubmission) (ClaimAck, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.down {
+		return ClaimAck{}, ErrPayerUnavailable
+	}
+	if ack, ok := g.acks[claim.ClaimID]; ok {
+		return ack, nil
+	}
+	g.claims = append(g.claims, claim)
+	ack := ClaimAck{Reference: fmt.Sprintf("FAKE-CLM-%06d", len(g.claims))}
+	g.acks[claim.ClaimID] = ack
+	return ack, nil
+}
@@ -0,0 +1,61 @@
+package insurance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ PayerGateway = (*FakeGateway)(nil)
+
+func TestFakeGateway_CheckEligibility(t *testing.T) {
+	g := NewFakeGateway()
+	g.MarkIneligible("M-2", "coverage terminated")
+	ctx := context.Background()
+
+	resp, err := g.CheckEligibility(ctx, EligibilityRequest{PayerCode: "ACME", MemberID: "M-1", DateOfService: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, model.EligibilityEligible, resp.Status)
+
+	resp, err = g.CheckEligibility(ctx, EligibilityRequest{PayerCode: "ACME", MemberID: "M-2", DateOfService: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, model.EligibilityIneligible, resp.Status)
+	assert.Equal(t, "coverage terminated", resp.Message)
+}
+
+func TestFakeGateway_SubmitClaim(t *testing.T) {
+	g := NewFakeGateway()
+	ctx := context.Background()
+
+	ack, err := g.SubmitClaim(ctx, ClaimSubmission{MemberID: "M-1", DiagnosisCodes: []string{"J45.909"}, ClaimedMinor: 50000})
+	require.NoError(t, err)
+	assert.Equal(t, "FAKE-CLM-000001", ack.Reference)
+	assert.Len(t, g.SubmittedClaims(), 1)
+
+	g.SetUnavailable(true)
+	_, err = g.SubmitClaim(ctx, ClaimSubmission{MemberID: "M-1"})
+	assert.ErrorIs(t, err, ErrPayerUnavailable)
+	assert.Len(t, g.SubmittedClaims(), 1)
+}
+
+func TestFakeGateway_SubmitClaimIsIdempotent(t *testing.T) {
+	g := NewFakeGateway()
+	ctx := context.Background()
+	claim := ClaimSubmission{ClaimID: uuid.New(), MemberID: "M-1", ClaimedMinor: 50000}
+
+	first, err := g.SubmitClaim(ctx, claim)
+	require.NoError(t, err)
+	again, err := g.SubmitClaim(ctx, claim)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Len(t, g.SubmittedClaims(), 1)
+
+	other, err := g.SubmitClaim(ctx, ClaimSubmission{ClaimID: uuid.New(), MemberID: "M-1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Reference, other.Reference)
+}
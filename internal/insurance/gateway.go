@@ -0,0 +1,63 @@
+// Package insurance defines how HMS talks to insurance payers.
+package insurance
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// ErrPayerUnavailable is returned by a gateway when the payer cannot be reached.
+var ErrPayerUnavailable = errors.New("payer unavailable")
+
+// EligibilityRequest asks a payer whether a member is covered on a date of service.
+type EligibilityRequest struct {
+	PayerCode     string
+	MemberID      string
+	GroupNumber   string
+	DateOfService time.Time
+}
+
+// EligibilityResponse is the payer's answer to an EligibilityRequest.
+type EligibilityResponse struct {
+	Status    model.EligibilityStatus
+	Message   string
+	Reference string // Payer's transaction reference, if any
+}
+
+// ClaimSubmission is a claim sent to a payer.
+type ClaimSubmission struct {
+	// ClaimID is the idempotency key: a claim sent again after a timeout carries the same
+	// ID, and the gateway must answer it with the original acknowledgement.
+	ClaimID        uuid.UUID
+	PayerCode      string
+	MemberID       string
+	GroupNumber    string
+	InvoiceNumber  string
+	DiagnosisCodes []string
+	ClaimedMinor   int64
+}
+
+// ClaimAck is the payer's acknowledgement of a submitted claim.
+type ClaimAck struct {
+	Reference string
+}
+
+// PayerGateway is implemented by payer or clearinghouse integrations.
+type PayerGateway interface {
+	CheckEligibility(ctx context.Context, req EligibilityRequest) (EligibilityResponse, error)
+	SubmitClaim(ctx context.Context, claim ClaimSubmission) (ClaimAck, error)
+}
+
+var diagnosisCodePattern = regexp.MustCompile(`^[A-TV-Z][0-9][0-9A-Z](\.[0-9A-Z]{1,4})?$`)
+
+// NormalizeDiagnosisCode upper-cases an ICD-10 code and reports whether it is well formed.
+func NormalizeDiagnosisCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, diagnosisCodePattern.MatchString(code)
+}
@@ -0,0 +1,19 @@
+package insurance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDiagnosisCode(t *testing.T) {
+	for in, want := range map[string]string{"j45.909": "J45.909", " E11 ": "E11", "S72.001A": "S72.001A"} {
+		got, ok := NormalizeDiagnosisCode(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got)
+	}
+	for _, in := range []string{"", "U07", "45.9", "J4", "J45.", "J45.90912"} {
+		_, ok := NormalizeDiagnosisCode(in)
+		assert.False(t, ok, in)
+	}
+}
@@ -0,0 +1,66 @@
+// Package logging builds the structured logger of the process. Every line is tagged with
+// the request and trace it belongs to, and passes through a redaction layer that keeps
+// credentials and patient data out of the logs however a caller logs them.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+)
+
+// Formats the logger writes.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing lines of format at level and above to w.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var h slog.Handler
+	if strings.EqualFold(format, FormatText) {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+// Discard is a logger that writes nothing, for tests and tools that stay quiet.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// contextHandler adds the request ID, trace ID and caller's role in the context to each line.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		md := audit.FromContext(ctx)
+		if md.RequestID != "" {
+			r.AddAttrs(slog.String("request_id", md.RequestID))
+		}
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			r.AddAttrs(slog.String("trace_id", traceID))
+		}
+		if md.ActorRole != "" {
+			r.AddAttrs(slog.String("actor_role", string(md.ActorRole)))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
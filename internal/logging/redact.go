@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Redacted replaces whatever the redaction layer keeps out of the logs.
+const Redacted = "[redacted]"
+
+// sensitiveKeys are attribute keys whose values never reach the logs: credentials, and
+// the fields of patients and visits that identify a patient or describe their care.
+// Internal IDs, like patient_id, are kept; they mean nothing outside the database.
+var sensitiveKeys = map[string]bool{
+	// Credentials
+	"password": true, "password_hash": true, "token": true, "access_token": true,
+	"refresh_token": true, "authorization": true, "secret": true, "api_key": true,
+	"cookie": true,
+	// Patient identifiers
+	"name": true, "first_name": true, "last_name": true, "email": true,
+	"contact_email": true, "phone": true, "contact_phone": true, "address": true,
+	"date_of_birth": true, "dob": true, "gender": true,
+	// Clinical fields and free text
+	"medical_history": true, "symptoms": true, "diagnosis": true, "prescription": true,
+	"notes": true, "reason": true, "amendment_reason": true, "body": true, "result": true,
+	"results": true, "value": true,
+}
+
+// secretPatterns match credentials and contact details inside free text: messages,
+// error strings and other string values.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bbearer\s+\S+`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), // JWT
+	regexp.MustCompile(`(?i)\b(password|secret|token)(\s*[=:]\s*)\S+`),
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), // Email address
+	regexp.MustCompile(`\+\d[\d ()-]{7,}\d`),                             // International phone number
+}
+
+// Scrub replaces the credentials and contact details in s.
+func Scrub(s string) string {
+	for _, p := range secretPatterns {
+		s = p.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// redactAttr is the ReplaceAttr of every handler New builds. Values under sensitive keys
+// are dropped, strings and errors are scrubbed, and values of any other type are dropped
+// unless they are IDs, times, numbers or strings: a struct may hold any field, and its
+// JSON encoding would bypass the key check.
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Scrub(v.String()))
+	case slog.KindAny:
+		return slog.Attr{Key: a.Key, Value: redactAny(v.Any())}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+func redactAny(v any) slog.Value {
+	switch v := v.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case error:
+		return slog.StringValue(Scrub(v.Error()))
+	case uuid.UUID, *uuid.UUID, time.Time, time.Duration, slog.Level:
+		return slog.AnyValue(v)
+	case pgtype.UUID:
+		if !v.Valid {
+			return slog.AnyValue(nil)
+		}
+		return slog.AnyValue(uuid.UUID(v.Bytes))
+	case []string:
+		out := make([]string, len(v))
+		for i, s := range v {
+			out[i] = Scrub(s)
+		}
+		return slog.AnyValue(out)
+	case fmt.Stringer:
+		return slog.StringValue(Scrub(v.String()))
+	}
+	// Named strings and numbers, like model.AuditAction, are as safe as their kind.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return slog.StringValue(Scrub(rv.String()))
+	case reflect.Bool:
+		return slog.BoolValue(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return slog.Uint64Value(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(rv.Float())
+	}
+	return slog.StringValue(Redacted)
+}
@@ -0,0 +1,102 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/alerting"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func int4Ptr(i pgtype.Int4) *int {
+	if !i.Valid {
+		return nil
+	}
+	v := int(i.Int32)
+	return &v
+}
+
+func genderPtr(g db.NullGenderEnum) *model.Gender {
+	if !g.Valid {
+		return nil
+	}
+	v := model.Gender(g.GenderEnum)
+	return &v
+}
+
+// MapObservation maps a db.VisitObservation to model.Observation.
+func MapObservation(o db.VisitObservation) model.Observation {
+	return model.Observation{
+		ID:               o.ID.Bytes,
+		VisitID:          o.VisitID.Bytes,
+		Code:             o.Code,
+		Value:            o.Value,
+		Unit:             textPtr(o.Unit),
+		ObservedAt:       o.ObservedAt.Time,
+		RecordedByUserID: o.RecordedByUserID.Bytes,
+		CreatedAt:        o.CreatedAt.Time,
+	}
+}
+
+// MapAlertRule maps a db.AlertRule to model.AlertRule.
+func MapAlertRule(r db.AlertRule) model.AlertRule {
+	return model.AlertRule{
+		ID:                   r.ID.Bytes,
+		ObservationCode:      r.ObservationCode,
+		Name:                 r.Name,
+		Sex:                  genderPtr(r.Sex),
+		MinAgeYears:          int4Ptr(r.MinAgeYears),
+		MaxAgeYears:          int4Ptr(r.MaxAgeYears),
+		CriticalLow:          float8Ptr(r.CriticalLow),
+		CriticalHigh:         float8Ptr(r.CriticalHigh),
+		EscalateAfterMinutes: int(r.EscalateAfterMinutes),
+		EscalationDoctorID:   uuidPtr(r.EscalationDoctorID),
+		IsActive:             r.IsActive,
+		CreatedAt:            r.CreatedAt.Time,
+		UpdatedAt:            r.UpdatedAt.Time,
+	}
+}
+
+// MapAlertingRule converts a stored rule into the rule engine's representation.
+func MapAlertingRule(r db.AlertRule) alerting.Rule {
+	return alerting.Rule{
+		ID:                 uuid.UUID(r.ID.Bytes),
+		Name:               r.Name,
+		ObservationCode:    r.ObservationCode,
+		Sex:                genderPtr(r.Sex),
+		MinAgeYears:        int4Ptr(r.MinAgeYears),
+		MaxAgeYears:        int4Ptr(r.MaxAgeYears),
+		CriticalLow:        float8Ptr(r.CriticalLow),
+		CriticalHigh:       float8Ptr(r.CriticalHigh),
+		EscalateAfter:      time.Duration(r.EscalateAfterMinutes) * time.Minute,
+		EscalationDoctorID: uuidPtr(r.EscalationDoctorID),
+	}
+}
+
+// MapAlert maps a db.Alert to model.Alert.
+func MapAlert(a db.Alert) model.Alert {
+	return model.Alert{
+		ID:                   a.ID.Bytes,
+		RuleID:               a.RuleID.Bytes,
+		VisitID:              a.VisitID.Bytes,
+		PatientID:            a.PatientID.Bytes,
+		Source:               model.ObservationSource(a.Source),
+		ObservationCode:      a.ObservationCode,
+		ObservedValue:        a.ObservedValue,
+		ThresholdValue:       a.ThresholdValue,
+		Direction:            a.Direction,
+		Message:              a.Message,
+		Status:               model.AlertStatus(a.Status),
+		OriginalDoctorID:     a.OriginalDoctorID.Bytes,
+		AssignedDoctorID:     a.AssignedDoctorID.Bytes,
+		EscalationLevel:      int(a.EscalationLevel),
+		EscalateAt:           timePtr(a.EscalateAt),
+		EscalatedAt:          timePtr(a.EscalatedAt),
+		AcknowledgedAt:       timePtr(a.AcknowledgedAt),
+		AcknowledgedByUserID: uuidPtr(a.AcknowledgedByUserID),
+		CreatedAt:            a.CreatedAt.Time,
+		UpdatedAt:            a.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,64 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// MapAuditEvent maps a stored audit row. It fails only when the changes column is not
+// valid JSON, which means the row was tampered with outside the application.
+func MapAuditEvent(e db.AuditEvent) (model.AuditEvent, error) {
+	event := model.AuditEvent{
+		Seq:               e.Seq,
+		OccurredAt:        e.OccurredAt.Time,
+		ActorUserID:       uuidPtr(e.ActorUserID),
+		ActorRole:         textPtr(e.ActorRole),
+		Action:            model.AuditAction(e.Action),
+		ResourceType:      e.ResourceType,
+		ResourceID:        uuidPtr(e.ResourceID),
+		PatientID:         uuidPtr(e.PatientID),
+		ClientIP:          textPtr(e.ClientIp),
+		UserAgent:         textPtr(e.UserAgent),
+		RequestID:         textPtr(e.RequestID),
+		PrevHash:          e.PrevHash,
+		Hash:              e.Hash,
+		BreakGlassGrantID: uuidPtr(e.BreakGlassGrantID),
+	}
+	if len(e.Changes) > 0 {
+		if err := json.Unmarshal(e.Changes, &event.Changes); err != nil {
+			return event, fmt.Errorf("decode changes of audit event %d: %w", e.Seq, err)
+		}
+	}
+	return event, nil
+}
+
+// MapAccessLogEntry maps a patient access log row, reducing the stored field changes to
+// the names of the fields touched.
+func MapAccessLogEntry(r db.ListPatientAccessLogRow) (model.AccessLogEntry, error) {
+	entry := model.AccessLogEntry{
+		OccurredAt:        r.OccurredAt.Time,
+		ActorUserID:       uuidPtr(r.ActorUserID),
+		ActorUsername:     textPtr(r.ActorUsername),
+		ActorRole:         textPtr(r.ActorRole),
+		Action:            model.AuditAction(r.Action),
+		ResourceType:      r.ResourceType,
+		ResourceID:        uuidPtr(r.ResourceID),
+		ClientIP:          textPtr(r.ClientIp),
+		BreakGlassGrantID: uuidPtr(r.BreakGlassGrantID),
+	}
+	if len(r.Changes) > 0 {
+		var changes map[string]json.RawMessage
+		if err := json.Unmarshal(r.Changes, &changes); err != nil {
+			return entry, fmt.Errorf("decode changes of audit event %d: %w", r.Seq, err)
+		}
+		for field := range changes {
+			entry.Fields = append(entry.Fields, field)
+		}
+		sort.Strings(entry.Fields)
+	}
+	return entry, nil
+}
@@ -0,0 +1,115 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// MapTaxCategory maps a db.TaxCategory to model.TaxCategory.
+func MapTaxCategory(t db.TaxCategory) model.TaxCategory {
+	return model.TaxCategory{
+		Code:        t.Code,
+		Description: t.Description,
+		RateBps:     int(t.RateBps),
+		CreatedAt:   t.CreatedAt.Time,
+		UpdatedAt:   t.UpdatedAt.Time,
+	}
+}
+
+// MapChargeItem maps a charge master row joined with its tax rate to model.ChargeItem.
+func MapChargeItem(c db.GetChargeItemByCodeRow) model.ChargeItem {
+	return model.ChargeItem{
+		ID:              c.ID.Bytes,
+		Code:            c.Code,
+		Description:     c.Description,
+		UnitPriceMinor:  c.UnitPriceMinor,
+		TaxCategoryCode: c.TaxCategoryCode,
+		TaxRateBps:      int(c.TaxRateBps),
+		IsActive:        c.IsActive,
+		CreatedAt:       c.CreatedAt.Time,
+		UpdatedAt:       c.UpdatedAt.Time,
+	}
+}
+
+// MapCharge maps a db.Charge to model.Charge.
+func MapCharge(c db.Charge) model.Charge {
+	return model.Charge{
+		ID:              c.ID.Bytes,
+		PatientID:       c.PatientID.Bytes,
+		VisitID:         uuidPtr(c.VisitID),
+		ChargeItemID:    c.ChargeItemID.Bytes,
+		Source:          model.ChargeSource(c.Source),
+		SourceID:        uuidPtr(c.SourceID),
+		Description:     c.Description,
+		Quantity:        int(c.Quantity),
+		UnitPriceMinor:  c.UnitPriceMinor,
+		TaxRateBps:      int(c.TaxRateBps),
+		InvoiceID:       uuidPtr(c.InvoiceID),
+		CreatedByUserID: c.CreatedByUserID.Bytes,
+		CreatedAt:       c.CreatedAt.Time,
+	}
+}
+
+// MapInvoice maps a db.Invoice to model.Invoice without lines or payments.
+func MapInvoice(i db.Invoice) model.Invoice {
+	return model.Invoice{
+		ID:             i.ID.Bytes,
+		InvoiceNumber:  i.InvoiceNumber,
+		FiscalYear:     int(i.FiscalYear),
+		PatientID:      i.PatientID.Bytes,
+		Status:         model.InvoiceStatus(i.Status),
+		SubtotalMinor:  i.SubtotalMinor,
+		DiscountMinor:  i.DiscountMinor,
+		TaxMinor:       i.TaxMinor,
+		TotalMinor:     i.TotalMinor,
+		PaidMinor:      i.PaidMinor,
+		BalanceMinor:   i.TotalMinor - i.PaidMinor,
+		Notes:          textPtr(i.Notes),
+		IssuedByUserID: i.IssuedByUserID.Bytes,
+		IssuedAt:       i.IssuedAt.Time,
+		CreatedAt:      i.CreatedAt.Time,
+		UpdatedAt:      i.UpdatedAt.Time,
+	}
+}
+
+// MapInvoiceLine maps a db.InvoiceLine to model.InvoiceLine.
+func MapInvoiceLine(l db.InvoiceLine) model.InvoiceLine {
+	return model.InvoiceLine{
+		ID:             l.ID.Bytes,
+		ChargeID:       l.ChargeID.Bytes,
+		Description:    l.Description,
+		Quantity:       int(l.Quantity),
+		UnitPriceMinor: l.UnitPriceMinor,
+		DiscountMinor:  l.DiscountMinor,
+		TaxRateBps:     int(l.TaxRateBps),
+		TaxMinor:       l.TaxMinor,
+		LineTotalMinor: l.LineTotalMinor,
+	}
+}
+
+// MapPayment maps a db.Payment to model.Payment.
+func MapPayment(p db.Payment) model.Payment {
+	return model.Payment{
+		ID:               p.ID.Bytes,
+		InvoiceID:        p.InvoiceID.Bytes,
+		PatientID:        p.PatientID.Bytes,
+		Method:           model.PaymentMethod(p.Method),
+		AmountMinor:      p.AmountMinor,
+		Reference:        textPtr(p.Reference),
+		ReceivedByUserID: p.ReceivedByUserID.Bytes,
+		ReceivedAt:       p.ReceivedAt.Time,
+	}
+}
+
+// MapRefund maps a db.Refund to model.Refund.
+func MapRefund(r db.Refund) model.Refund {
+	return model.Refund{
+		ID:               r.ID.Bytes,
+		PaymentID:        r.PaymentID.Bytes,
+		InvoiceID:        r.InvoiceID.Bytes,
+		AmountMinor:      r.AmountMinor,
+		Reason:           r.Reason,
+		RefundedByUserID: r.RefundedByUserID.Bytes,
+		RefundedAt:       r.RefundedAt.Time,
+	}
+}
@@ -0,0 +1,58 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/breakglass"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// MapBreakGlassGrant maps a db.BreakGlassGrant to model.BreakGlassGrant.
+func MapBreakGlassGrant(g db.BreakGlassGrant) model.BreakGlassGrant {
+	return model.BreakGlassGrant{
+		ID:        g.ID.Bytes,
+		UserID:    g.UserID.Bytes,
+		PatientID: g.PatientID.Bytes,
+		Reason:    g.Reason,
+		GrantedAt: g.GrantedAt.Time,
+		ExpiresAt: g.ExpiresAt.Time,
+		RevokedAt: timePtr(g.RevokedAt),
+	}
+}
+
+// MapBreakGlassReviewGrant maps a grant loaded for the daily review.
+func MapBreakGlassReviewGrant(g db.ListBreakGlassGrantsForReviewRow) breakglass.Grant {
+	return breakglass.Grant{
+		BreakGlassGrant: model.BreakGlassGrant{
+			ID:        g.ID.Bytes,
+			UserID:    g.UserID.Bytes,
+			PatientID: g.PatientID.Bytes,
+			Reason:    g.Reason,
+			GrantedAt: g.GrantedAt.Time,
+			ExpiresAt: g.ExpiresAt.Time,
+			RevokedAt: timePtr(g.RevokedAt),
+		},
+		Username: g.Username,
+	}
+}
+
+// MapBreakGlassAccess maps an audited access made under a grant.
+func MapBreakGlassAccess(a db.ListBreakGlassAccessesRow) model.BreakGlassAccess {
+	return model.BreakGlassAccess{
+		Seq:          a.Seq,
+		OccurredAt:   a.OccurredAt.Time,
+		GrantID:      a.BreakGlassGrantID.Bytes,
+		Action:       model.AuditAction(a.Action),
+		ResourceType: a.ResourceType,
+		ResourceID:   uuidPtr(a.ResourceID),
+	}
+}
+
+// MapBreakGlassReportSummary maps a stored report listing row.
+func MapBreakGlassReportSummary(r db.ListBreakGlassReportsRow) model.BreakGlassReportSummary {
+	return model.BreakGlassReportSummary{
+		Date:        r.ReportDate.Time.Format(breakglass.DateLayout),
+		GeneratedAt: r.GeneratedAt.Time,
+		GrantCount:  int(r.GrantCount),
+		AccessCount: int(r.AccessCount),
+	}
+}
@@ -0,0 +1,20 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// MapCareTeamMember maps a care team row joined with the member's user record.
+func MapCareTeamMember(m db.ListCareTeamMembersRow) model.CareTeamMember {
+	return model.CareTeamMember{
+		PatientID:        m.PatientID.Bytes,
+		UserID:           m.UserID.Bytes,
+		Username:         m.Username,
+		FirstName:        textPtr(m.FirstName),
+		LastName:         textPtr(m.LastName),
+		Role:             model.UserRole(m.Role),
+		AssignedByUserID: m.AssignedByUserID.Bytes,
+		AssignedAt:       m.AssignedAt.Time,
+	}
+}
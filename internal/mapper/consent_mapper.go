@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+func MapConsentRecord(r db.ConsentRecord) model.ConsentRecord {
+	return model.ConsentRecord{
+		ID:                r.ID.Bytes,
+		PatientID:         r.PatientID.Bytes,
+		Type:              model.ConsentType(r.ConsentType),
+		TextVersion:       r.TextVersion,
+		SignedAt:          r.SignedAt.Time,
+		WitnessedByUserID: r.WitnessedByUserID.Bytes,
+		WithdrawnAt:       timePtr(r.WithdrawnAt),
+		WithdrawnByUserID: uuidPtr(r.WithdrawnByUserID),
+		WithdrawalReason:  textPtr(r.WithdrawalReason),
+		CreatedAt:         r.CreatedAt.Time,
+	}
+}
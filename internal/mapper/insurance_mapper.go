@@ -0,0 +1,90 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func datePtr(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	v := d.Time
+	return &v
+}
+
+func int8Ptr(i pgtype.Int8) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	v := i.Int64
+	return &v
+}
+
+// MapInsurancePolicy maps a db.InsurancePolicy to model.InsurancePolicy.
+func MapInsurancePolicy(p db.InsurancePolicy) model.InsurancePolicy {
+	return model.InsurancePolicy{
+		ID:                   p.ID.Bytes,
+		PatientID:            p.PatientID.Bytes,
+		PayerCode:            p.PayerCode,
+		PayerName:            p.PayerName,
+		PlanName:             textPtr(p.PlanName),
+		MemberID:             p.MemberID,
+		GroupNumber:          textPtr(p.GroupNumber),
+		ValidFrom:            p.ValidFrom.Time,
+		ValidTo:              datePtr(p.ValidTo),
+		Priority:             model.PolicyPriority(p.Priority),
+		IsActive:             p.IsActive,
+		EligibilityStatus:    model.EligibilityStatus(p.EligibilityStatus),
+		EligibilityCheckedAt: timePtr(p.EligibilityCheckedAt),
+		CreatedAt:            p.CreatedAt.Time,
+		UpdatedAt:            p.UpdatedAt.Time,
+	}
+}
+
+// MapEligibilityCheck maps a db.EligibilityCheck to model.EligibilityCheck.
+func MapEligibilityCheck(c db.EligibilityCheck) model.EligibilityCheck {
+	return model.EligibilityCheck{
+		ID:              c.ID.Bytes,
+		PolicyID:        c.PolicyID.Bytes,
+		Status:          model.EligibilityStatus(c.Status),
+		Message:         textPtr(c.Message),
+		PayerReference:  textPtr(c.PayerReference),
+		CheckedByUserID: c.CheckedByUserID.Bytes,
+		CheckedAt:       c.CheckedAt.Time,
+	}
+}
+
+// MapInsuranceClaim maps a db.InsuranceClaim to model.InsuranceClaim without its history.
+func MapInsuranceClaim(c db.InsuranceClaim) model.InsuranceClaim {
+	return model.InsuranceClaim{
+		ID:                c.ID.Bytes,
+		InvoiceID:         c.InvoiceID.Bytes,
+		PolicyID:          c.PolicyID.Bytes,
+		PatientID:         c.PatientID.Bytes,
+		DiagnosisCodes:    c.DiagnosisCodes,
+		ClaimedMinor:      c.ClaimedMinor,
+		PaidMinor:         int8Ptr(c.PaidMinor),
+		Status:            model.ClaimStatus(c.Status),
+		PayerReference:    textPtr(c.PayerReference),
+		RejectionReason:   textPtr(c.RejectionReason),
+		SubmittedByUserID: c.SubmittedByUserID.Bytes,
+		SubmittedAt:       c.SubmittedAt.Time,
+		SentToPayerAt:     timePtr(c.SentToPayerAt),
+		CreatedAt:         c.CreatedAt.Time,
+		UpdatedAt:         c.UpdatedAt.Time,
+	}
+}
+
+// MapClaimStatusHistory maps a db.ClaimStatusHistory to model.ClaimStatusHistory.
+func MapClaimStatusHistory(h db.ClaimStatusHistory) model.ClaimStatusHistory {
+	return model.ClaimStatusHistory{
+		Status:          model.ClaimStatus(h.Status),
+		Note:            textPtr(h.Note),
+		ChangedByUserID: h.ChangedByUserID.Bytes,
+		ChangedAt:       h.ChangedAt.Time,
+	}
+}
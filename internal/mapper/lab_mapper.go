@@ -0,0 +1,157 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func textPtr(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	s := t.String
+	return &s
+}
+
+func float8Ptr(f pgtype.Float8) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	v := f.Float64
+	return &v
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+func uuidPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	v := uuid.UUID(id.Bytes)
+	return &v
+}
+
+// MapLabTest maps a catalog row and its analytes to model.LabTest.
+func MapLabTest(t db.LabTest, analytes []db.LabAnalyte) model.LabTest {
+	test := model.LabTest{
+		ID:           t.ID.Bytes,
+		Code:         t.Code,
+		Name:         t.Name,
+		SpecimenType: model.LabSpecimenType(t.SpecimenType),
+		Description:  textPtr(t.Description),
+		ChargeCode:   textPtr(t.ChargeCode),
+		IsActive:     t.IsActive,
+		CreatedAt:    t.CreatedAt.Time,
+		UpdatedAt:    t.UpdatedAt.Time,
+	}
+	for _, a := range analytes {
+		test.Analytes = append(test.Analytes, MapLabAnalyte(a))
+	}
+	return test
+}
+
+// MapLabAnalyte maps a db.LabAnalyte to model.LabAnalyte.
+func MapLabAnalyte(a db.LabAnalyte) model.LabAnalyte {
+	return model.LabAnalyte{
+		ID:           a.ID.Bytes,
+		TestID:       a.TestID.Bytes,
+		Code:         a.Code,
+		Name:         a.Name,
+		Unit:         textPtr(a.Unit),
+		DisplayOrder: int(a.DisplayOrder),
+		LabReferenceRange: model.LabReferenceRange{
+			Low:          float8Ptr(a.ReferenceLow),
+			High:         float8Ptr(a.ReferenceHigh),
+			CriticalLow:  float8Ptr(a.CriticalLow),
+			CriticalHigh: float8Ptr(a.CriticalHigh),
+		},
+	}
+}
+
+// MapLabOrder maps a db.LabOrder to model.LabOrder without results.
+func MapLabOrder(o db.LabOrder) model.LabOrder {
+	return model.LabOrder{
+		ID:                o.ID.Bytes,
+		VisitID:           o.VisitID.Bytes,
+		TestID:            o.TestID.Bytes,
+		OrderedByUserID:   o.OrderedByUserID.Bytes,
+		Priority:          model.LabOrderPriority(o.Priority),
+		Status:            model.LabOrderStatus(o.Status),
+		ClinicalNotes:     textPtr(o.ClinicalNotes),
+		OrderedAt:         o.OrderedAt.Time,
+		CollectedAt:       timePtr(o.CollectedAt),
+		CollectedByUserID: uuidPtr(o.CollectedByUserID),
+		ResultedAt:        timePtr(o.ResultedAt),
+		ResultedByUserID:  uuidPtr(o.ResultedByUserID),
+		VerifiedAt:        timePtr(o.VerifiedAt),
+		VerifiedByUserID:  uuidPtr(o.VerifiedByUserID),
+		CreatedAt:         o.CreatedAt.Time,
+		UpdatedAt:         o.UpdatedAt.Time,
+	}
+}
+
+func labFlagPtr(f db.NullLabResultFlag) *model.LabResultFlag {
+	if !f.Valid {
+		return nil
+	}
+	v := model.LabResultFlag(f.LabResultFlag)
+	return &v
+}
+
+// MapLabResult maps a result row joined with its analyte to model.LabResult.
+func MapLabResult(r db.ListLabResultsByOrderIDRow) model.LabResult {
+	return model.LabResult{
+		ID:              r.ID.Bytes,
+		OrderID:         r.OrderID.Bytes,
+		AnalyteID:       r.AnalyteID.Bytes,
+		AnalyteCode:     r.AnalyteCode,
+		AnalyteName:     r.AnalyteName,
+		ValueNumeric:    float8Ptr(r.ValueNumeric),
+		ValueText:       textPtr(r.ValueText),
+		Unit:            textPtr(r.Unit),
+		ReferenceLow:    float8Ptr(r.ReferenceLow),
+		ReferenceHigh:   float8Ptr(r.ReferenceHigh),
+		Flag:            labFlagPtr(r.Flag),
+		Comment:         textPtr(r.Comment),
+		EnteredByUserID: r.EnteredByUserID.Bytes,
+		CreatedAt:       r.CreatedAt.Time,
+		UpdatedAt:       r.UpdatedAt.Time,
+	}
+}
+
+// MapCumulativeLabResult maps a row of a patient's cumulative results view.
+func MapCumulativeLabResult(r db.ListCumulativeLabResultsByPatientIDRow) model.CumulativeLabResult {
+	return model.CumulativeLabResult{
+		LabResult: model.LabResult{
+			ID:              r.ID.Bytes,
+			OrderID:         r.OrderID.Bytes,
+			AnalyteID:       r.AnalyteID.Bytes,
+			AnalyteCode:     r.AnalyteCode,
+			AnalyteName:     r.AnalyteName,
+			ValueNumeric:    float8Ptr(r.ValueNumeric),
+			ValueText:       textPtr(r.ValueText),
+			Unit:            textPtr(r.Unit),
+			ReferenceLow:    float8Ptr(r.ReferenceLow),
+			ReferenceHigh:   float8Ptr(r.ReferenceHigh),
+			Flag:            labFlagPtr(r.Flag),
+			Comment:         textPtr(r.Comment),
+			EnteredByUserID: r.EnteredByUserID.Bytes,
+			CreatedAt:       r.CreatedAt.Time,
+			UpdatedAt:       r.UpdatedAt.Time,
+		},
+		TestCode:    r.TestCode,
+		VisitID:     r.VisitID.Bytes,
+		OrderStatus: model.LabOrderStatus(r.OrderStatus),
+		ObservedAt:  r.ObservedAt.Time,
+	}
+}
@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+func MapPatientExport(j db.PatientExportJob) model.PatientExport {
+	return model.PatientExport{
+		ID:                j.ID.Bytes,
+		PatientID:         j.PatientID.Bytes,
+		RequestedByUserID: j.RequestedByUserID.Bytes,
+		Status:            model.PatientExportStatus(j.Status),
+		Error:             textPtr(j.Error),
+		SizeBytes:         int8Ptr(j.SizeBytes),
+		RequestedAt:       j.RequestedAt.Time,
+		StartedAt:         timePtr(j.StartedAt),
+		CompletedAt:       timePtr(j.CompletedAt),
+		ExpiresAt:         timePtr(j.ExpiresAt),
+	}
+}
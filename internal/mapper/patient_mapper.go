@@ -48,6 +48,8 @@ func ConvertDBPatientToModel(p *db.Patient) model.Patient {
 		RegisteredByUserID: uuid.UUID(p.RegisteredByUserID.Bytes),
 		CreatedAt:          p.CreatedAt.Time,
 		UpdatedAt:          p.UpdatedAt.Time,
+		PseudonymizedAt:    timePtr(p.PseudonymizedAt),
+		Version:            p.Version,
 		DeletedAt:          nil,
 	}
 }
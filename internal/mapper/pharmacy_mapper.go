@@ -0,0 +1,81 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+func MapPharmacyLocation(l db.PharmacyLocation) model.PharmacyLocation {
+	return model.PharmacyLocation{
+		ID:        l.ID.Bytes,
+		Code:      l.Code,
+		Name:      l.Name,
+		IsActive:  l.IsActive,
+		CreatedAt: l.CreatedAt.Time,
+		UpdatedAt: l.UpdatedAt.Time,
+	}
+}
+
+func MapPharmacyItem(i db.PharmacyItem) model.PharmacyItem {
+	return model.PharmacyItem{
+		ID:           i.ID.Bytes,
+		Code:         i.Code,
+		Name:         i.Name,
+		DosageForm:   textPtr(i.DosageForm),
+		Strength:     textPtr(i.Strength),
+		Unit:         i.Unit,
+		ReorderLevel: int(i.ReorderLevel),
+		IsActive:     i.IsActive,
+		CreatedAt:    i.CreatedAt.Time,
+		UpdatedAt:    i.UpdatedAt.Time,
+	}
+}
+
+// MapStockMovement maps a ledger row joined with its batch and item.
+func MapStockMovement(m db.ListStockMovementsRow) model.StockMovement {
+	return model.StockMovement{
+		ID:                 m.ID.Bytes,
+		MovementType:       model.StockMovementType(m.MovementType),
+		LocationID:         m.LocationID.Bytes,
+		ItemID:             m.ItemID.Bytes,
+		ItemCode:           m.ItemCode,
+		BatchID:            m.BatchID.Bytes,
+		LotNumber:          m.LotNumber,
+		Quantity:           int(m.Quantity),
+		VisitID:            uuidPtr(m.VisitID),
+		ReturnedMovementID: uuidPtr(m.ReturnedMovementID),
+		Reference:          textPtr(m.Reference),
+		Reason:             textPtr(m.Reason),
+		CreatedByUserID:    m.CreatedByUserID.Bytes,
+		CreatedAt:          m.CreatedAt.Time,
+	}
+}
+
+// MapStockLevel maps a stock level row; Expired is left for the caller, which knows today's date.
+func MapStockLevel(s db.ListStockLevelsRow) model.StockLevel {
+	return model.StockLevel{
+		LocationID:   s.LocationID.Bytes,
+		LocationCode: s.LocationCode,
+		ItemID:       s.ItemID.Bytes,
+		ItemCode:     s.ItemCode,
+		ItemName:     s.ItemName,
+		Unit:         s.Unit,
+		BatchID:      s.BatchID.Bytes,
+		LotNumber:    s.LotNumber,
+		ExpiryDate:   s.ExpiryDate.Time,
+		Quantity:     int(s.Quantity),
+		UpdatedAt:    s.UpdatedAt.Time,
+	}
+}
+
+func MapReorderAlert(r db.ListItemsBelowReorderLevelRow) model.ReorderAlert {
+	return model.ReorderAlert{
+		ItemID:       r.ItemID.Bytes,
+		ItemCode:     r.ItemCode,
+		ItemName:     r.ItemName,
+		LocationID:   r.LocationID.Bytes,
+		LocationCode: r.LocationCode,
+		OnHand:       r.OnHand,
+		ReorderLevel: int(r.ReorderLevel),
+	}
+}
@@ -0,0 +1,20 @@
+package mapper
+
+import (
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+func MapPseudonymizationRequest(r db.PseudonymizationRequest) model.PseudonymizationRequest {
+	return model.PseudonymizationRequest{
+		ID:                r.ID.Bytes,
+		PatientID:         r.PatientID.Bytes,
+		RequestedByUserID: r.RequestedByUserID.Bytes,
+		Reason:            r.Reason,
+		Status:            model.PseudonymizationStatus(r.Status),
+		DecidedByUserID:   uuidPtr(r.DecidedByUserID),
+		DecidedAt:         timePtr(r.DecidedAt),
+		DecisionNote:      textPtr(r.DecisionNote),
+		CreatedAt:         r.CreatedAt.Time,
+	}
+}
@@ -39,15 +39,18 @@ func MapPatientVisit(pv *db.PatientVisit) (*model.PatientVisit, error) {
     }
 
     return &model.PatientVisit{
-        ID:           uuid.UUID(pv.ID.Bytes),
-        PatientID:    uuid.UUID(pv.PatientID.Bytes),
-        DoctorID:     uuid.UUID(pv.DoctorID.Bytes),
-        VisitDate:    visitDate,
-        Symptoms:     symptoms,
-        Diagnosis:    diagnosis,
-        Prescription: prescription,
-        Notes:        notes,
-        CreatedAt:    createdAt,
-        UpdatedAt:    updatedAt,
+        ID:                uuid.UUID(pv.ID.Bytes),
+        PatientID:         uuid.UUID(pv.PatientID.Bytes),
+        DoctorID:          uuid.UUID(pv.DoctorID.Bytes),
+        VisitDate:         visitDate,
+        Symptoms:          symptoms,
+        Diagnosis:         diagnosis,
+        Prescription:      prescription,
+        Notes:             notes,
+        CreatedAt:         createdAt,
+        UpdatedAt:         updatedAt,
+        Version:           pv.Version,
+        FinalizedAt:       timePtr(pv.FinalizedAt),
+        FinalizedByUserID: uuidPtr(pv.FinalizedByUserID),
     }, nil
 }
\ No newline at end of file
@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/visithistory"
+)
+
+// MapVisitRevision maps a stored revision to the form the timeline is built from.
+func MapVisitRevision(r db.PatientVisitRevision) visithistory.Revision {
+	return visithistory.Revision{
+		Version:      r.Version,
+		Kind:         model.VisitRevisionKind(r.Kind),
+		Reason:       textPtr(r.Reason),
+		AuthorUserID: uuid.UUID(r.AuthorUserID.Bytes),
+		RevisedAt:    r.RevisedAt.Time,
+		Previous: visithistory.Snapshot{
+			VisitDate:    r.PreviousVisitDate.Time,
+			Symptoms:     textPtr(r.PreviousSymptoms),
+			Diagnosis:    textPtr(r.PreviousDiagnosis),
+			Prescription: textPtr(r.PreviousPrescription),
+			Notes:        textPtr(r.PreviousNotes),
+		},
+	}
+}
@@ -0,0 +1,103 @@
+// Package metrics holds the Prometheus collectors of the process and serves them on
+// /metrics. Collectors are package variables, registered on Registry, so any layer can
+// count what it does without the collectors being passed down to it.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "hms"
+
+// Registry holds every collector /metrics serves.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration is labelled by route template, like /api/v1/patients/:id, so
+	// IDs in the path do not each get a series.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to answer HTTP requests.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"method", "route", "status"})
+	HTTPRequestSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_size_bytes",
+		Help:      "Size of HTTP request bodies.",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"method", "route", "status"})
+	HTTPResponseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "response_size_bytes",
+		Help:      "Size of HTTP response bodies.",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration is labelled by the sqlc query name; queries written by hand are
+	// labelled "unnamed".
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by database queries.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"query", "status"})
+
+	PatientsRegistered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "patients_registered_total",
+		Help:      "Patients registered.",
+	})
+	VisitsRecorded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "visits_recorded_total",
+		Help:      "Patient visits recorded.",
+	})
+	// Logins is labelled by result, success or failure, and for failures the reason.
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result and failure reason.",
+	}, []string{"result", "reason"})
+)
+
+// Login failure reasons.
+const (
+	LoginUnknownUser   = "unknown_user" // No active user has the username
+	LoginWrongPassword = "wrong_password"
+	LoginError         = "error" // The attempt could not be checked
+)
+
+// LoginSucceeded counts a successful login.
+func LoginSucceeded() { Logins.WithLabelValues("success", "").Inc() }
+
+// LoginFailed counts a failed login with one of the Login reasons.
+func LoginFailed(reason string) { Logins.WithLabelValues("failure", reason).Inc() }
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration, HTTPRequestSize, HTTPResponseSize,
+		DBQueryDuration,
+		PatientsRegistered, VisitsRecorded, Logins,
+	)
+	// Start the login series at zero, so rates work from the first failure.
+	Logins.WithLabelValues("success", "")
+	for _, reason := range []string{LoginUnknownUser, LoginWrongPassword, LoginError} {
+		Logins.WithLabelValues("failure", reason)
+	}
+}
+
+// Handler serves the metrics in Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	assert.Equal(t, "GetUserByUsername", QueryName("-- name: GetUserByUsername :one\nSELECT 1"))
+	assert.Equal(t, "unnamed", QueryName("SELECT 1"))
+	assert.Equal(t, "unnamed", QueryName("SELECT 1 -- name: NotAtTheStart :one"))
+}
+
+func TestLoginSeriesStartAtZero(t *testing.T) {
+	before := testutil.ToFloat64(Logins.WithLabelValues("failure", LoginWrongPassword))
+	LoginFailed(LoginWrongPassword)
+	assert.Equal(t, before+1, testutil.ToFloat64(Logins.WithLabelValues("failure", LoginWrongPassword)))
+	assert.Equal(t, 4, testutil.CollectAndCount(Logins), "success and every failure reason are exported")
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector reads a connection pool's statistics when scraped.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired, idle, total, max    *prometheus.Desc
+	acquires, emptyAcquires       *prometheus.Desc
+	canceledAcquires, acquireWait *prometheus.Desc
+}
+
+// NewPoolCollector returns a collector for pool's statistics: connections in use and
+// idle, and how often and how long acquiring a connection had to wait.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:             pool,
+		acquired:         desc("acquired_connections", "Connections in use."),
+		idle:             desc("idle_connections", "Connections open and unused."),
+		total:            desc("total_connections", "Connections open, including ones being opened."),
+		max:              desc("max_connections", "Most connections the pool opens."),
+		acquires:         desc("acquires_total", "Connections acquired from the pool."),
+		emptyAcquires:    desc("empty_acquires_total", "Acquires that waited because no connection was idle."),
+		canceledAcquires: desc("canceled_acquires_total", "Acquires given up before a connection was free."),
+		acquireWait:      desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.acquired, c.idle, c.total, c.max, c.acquires, c.emptyAcquires, c.canceledAcquires, c.acquireWait} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireWait, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// sqlcName matches the comment sqlc puts at the start of every generated query.
+var sqlcName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// QueryName is the sqlc name of the query sql, or "unnamed".
+func QueryName(sql string) string {
+	if m := sqlcName.FindStringSubmatch(sql); m != nil {
+		return m[1]
+	}
+	return "unnamed"
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name  string
+	start time.Time
+}
+
+// QueryTracer records how long each query takes in DBQueryDuration. Set it as the
+// pgx.ConnConfig Tracer.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: QueryName(data.SQL), start: time.Now()})
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if data.Err != nil {
+		status = "error"
+	}
+	DBQueryDuration.WithLabelValues(q.name, status).Observe(time.Since(q.start).Seconds())
+}
@@ -1,22 +1,18 @@
 package middleware
 
 import (
-	"fmt"
-	"log"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
 	"github.com/himanshu-holmes/hms/internal/authorization"
 	"github.com/himanshu-holmes/hms/internal/model"
 )
-var (
-	secret = []byte(os.Getenv("SECRET"))
-)
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware lets requests through that carry a bearer token tokens accepts.
+func AuthMiddleware(tokens authorization.Authorization) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.Request.Header.Get("Authorization")
 		if authHeader == "" {
@@ -25,8 +21,6 @@ func AuthMiddleware() gin.HandlerFunc {
 			})
 			return
 		}
-		// print authHeader
-		fmt.Println("authHeader",authHeader)
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -35,21 +29,23 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		tokenString := parts[1]
-		auth := authorization.AuthWithOutDuration(secret)
-		info,err := auth.Authorize(c.Request.Context(),tokenString)
+		info,err := tokens.Authorize(c.Request.Context(),tokenString)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		log.Println("info",info)
 		c.Set("info",info)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), info.ID.Bytes, info.Role))
+		traceRole(c, string(info.Role))
 
 	}
 }
 
-func RoleMiddleware(requiredRole model.UserRole)gin.HandlerFunc{
+// RoleMiddleware allows the request through when the authenticated user has any of allowedRoles.
+// It must be registered after AuthMiddleware.
+func RoleMiddleware(allowedRoles ...model.UserRole)gin.HandlerFunc{
 	return func(c *gin.Context){
 		info := c.MustGet("info").(authorization.Info)
 		if info.Type != authorization.AccessToken {
@@ -58,13 +54,15 @@ func RoleMiddleware(requiredRole model.UserRole)gin.HandlerFunc{
 			})
 			return
 		}
-		if info.Role != requiredRole {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized",
-			})
-			return
+		for _, role := range allowedRoles {
+			if info.Role == role {
+				c.Next()
+				return
+			}
 		}
-	    c.Next()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Forbidden",
+		})
 	}
 }
 
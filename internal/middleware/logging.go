@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+)
+
+// AccessLog logs one line per request: method, route template, status, duration and
+// response size. Like the metrics and spans it names the route template rather than the
+// path, which holds IDs, and never the query string, which can hold names and phone
+// numbers. Successful requests to quiet routes, such as the probes, are logged at debug.
+// It should be the first middleware, so the line carries the request and trace IDs the
+// later ones add.
+func AccessLog(logger *slog.Logger, quiet ...string) gin.HandlerFunc {
+	quietRoutes := make(map[string]bool, len(quiet))
+	for _, route := range quiet {
+		quietRoutes[route] = true
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quietRoutes[route]:
+			level = slog.LevelDebug
+		}
+		logger.Log(c.Request.Context(), level, "HTTP request",
+			"method", c.Request.Method,
+			"route", route,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"bytes", max(c.Writer.Size(), 0),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// Recovery turns a panic in a handler into a 500 and logs it, with its stack, through
+// logger. gin's own recovery writes to stderr and, in debug mode, dumps the request
+// headers.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		ctx := c.Request.Context()
+		logger.ErrorContext(ctx, "Panic serving request",
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"panic", fmt.Sprint(recovered),
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, model.APIError{
+			Message: "Internal server error",
+			TraceID: tracing.TraceID(ctx),
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogNamesTheRouteNotThePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(AccessLog(logging.New(&buf, slog.LevelInfo, logging.FormatJSON), "/livez"), RequestContext())
+	r.GET("/patients/:id", func(c *gin.Context) { c.String(http.StatusNotFound, "not found") })
+	r.GET("/livez", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/patients/3f1c2a4e-0000-0000-0000-000000000000?email=jane@example.com", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.NotContains(t, buf.String(), "3f1c2a4e")
+	assert.NotContains(t, buf.String(), "jane")
+	var line map[string]any
+	require.NoError(t, json.NewDecoder(&buf).Decode(&line))
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "GET", line["method"])
+	assert.Equal(t, "/patients/:id", line["route"])
+	assert.EqualValues(t, http.StatusNotFound, line["status"])
+	assert.EqualValues(t, len("not found"), line["bytes"])
+	assert.Equal(t, "req-123", line["request_id"])
+	assert.False(t, json.NewDecoder(&buf).More(), "successful probes are logged at debug")
+}
+
+func TestRecoveryLogsThePanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo, logging.FormatJSON)
+	r := gin.New()
+	r.Use(Recovery(logger))
+	r.GET("/visits/:id", func(c *gin.Context) { panic("nil visit") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visits/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"message": "Internal server error"}`, w.Body.String())
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "nil visit", line["panic"])
+	assert.Equal(t, "/visits/:id", line["route"])
+}
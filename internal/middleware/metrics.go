@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/metrics"
+)
+
+// unmatchedRoute labels requests no route matched, so scanners probing random paths
+// share one series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the duration and sizes of each request by method, route template and
+// status.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		labels := []string{c.Request.Method, route, strconv.Itoa(c.Writer.Status())}
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		if c.Request.ContentLength >= 0 {
+			metrics.HTTPRequestSize.WithLabelValues(labels...).Observe(float64(c.Request.ContentLength))
+		}
+		metrics.HTTPResponseSize.WithLabelValues(labels...).Observe(float64(max(c.Writer.Size(), 0)))
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsLabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/patients/:id", func(c *gin.Context) { c.String(http.StatusOK, "patient") })
+
+	for _, path := range []string{"/patients/1", "/patients/2", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.HTTPRequestDuration), "one series per route, not per ID")
+	assert.Equal(t, uint64(2), requestCount(t, "/patients/:id", "200"))
+	assert.Equal(t, uint64(1), requestCount(t, unmatchedRoute, "404"))
+}
+
+func requestCount(t *testing.T, route, status string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, metrics.HTTPRequestDuration.WithLabelValues("GET", route, status).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+)
+
+// RequestIDHeader carries the request ID in both directions so callers can correlate
+// their request with audit events and logs.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds a caller-supplied request ID; longer ones are replaced.
+const maxRequestIDLength = 128
+
+// RequestContext stores the client address, user agent and request ID in the request
+// context for the audit trail. It must be registered before AuthMiddleware.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(audit.WithRequest(c.Request.Context(), c.ClientIP(), c.Request.UserAgent(), requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader returns the trace ID of every response, so a caller can quote it when
+// reporting a problem.
+const TraceIDHeader = "X-Trace-ID"
+
+// Tracing starts a server span for each request, continuing the trace in its W3C
+// traceparent header if there is one. The span is named after the route template; the
+// path itself, which holds IDs, and the client's address are left out.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(c.Request.Method), semconv.HTTPRoute(route)),
+		)
+		defer span.End()
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			c.Header(TraceIDHeader, traceID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// traceRole records the caller's role on the request's span.
+func traceRole(c *gin.Context, role string) {
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("enduser.role", role))
+}
@@ -0,0 +1,169 @@
+// Package migrate applies the schema migrations embedded in the binary. Every change to the
+// schema takes a Postgres advisory lock first, so replicas starting together apply each
+// migration once.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrSchemaAhead means the database has migrations this binary does not know: it was
+// migrated by a newer release, and this one may not understand the schema.
+var ErrSchemaAhead = errors.New("database schema is newer than this binary")
+
+// ErrSchemaBehind means migrations this binary relies on have not been applied.
+var ErrSchemaBehind = errors.New("database schema is older than this binary")
+
+// Migrator runs the migrations in a file system against one database.
+type Migrator struct {
+	db       *sql.DB
+	provider *goose.Provider
+}
+
+// New returns a Migrator for the migrations in fsys, run over connections from pool.
+// verbose logs each migration as it runs.
+func New(pool *pgxpool.Pool, fsys fs.FS, verbose bool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create migration lock: %w", err)
+	}
+	db := stdlib.OpenDBFromPool(pool)
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, fsys,
+		goose.WithSessionLocker(locker),
+		goose.WithVerbose(verbose),
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return &Migrator{db: db, provider: provider}, nil
+}
+
+// Close releases the connection the Migrator holds. The pool stays open.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the latest migration.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls back the latest migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, err
+	}
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+// Status lists every migration with whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Check reports whether the database is at exactly the version this binary expects.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, latest, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if current > latest {
+		return fmt.Errorf("%w: database is at version %d, this binary knows up to %d", ErrSchemaAhead, current, latest)
+	}
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("check pending migrations: %w", err)
+	}
+	if pending {
+		return fmt.Errorf("%w: database is at version %d, this binary needs %d", ErrSchemaBehind, current, latest)
+	}
+	return nil
+}
+
+var migrationName = regexp.MustCompile(`^(\d+)_.+\.sql$`)
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// template matches the layout of the existing migrations.
+const template = `-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+`
+
+// Create writes an empty migration called name to dir, numbered after the latest one
+// there, and returns its path.
+func Create(dir, name string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("read migrations: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	file, err := NextFileName(names, name)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, file)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("create migration: %w", err)
+	}
+	if _, err := f.WriteString(template); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write migration: %w", err)
+	}
+	return path, f.Close()
+}
+
+// NextFileName is the file name of a migration called name that follows the migrations
+// in existing: the next version, zero-padded to three digits, and the name in snake case.
+func NextFileName(existing []string, name string) (string, error) {
+	slug := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", fmt.Errorf("migration name %q has no letters or digits", name)
+	}
+	var latest int64
+	for _, file := range existing {
+		match := migrationName.FindStringSubmatch(file)
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("migration %s: %w", file, err)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return fmt.Sprintf("%03d_%s.sql", latest+1, slug), nil
+}
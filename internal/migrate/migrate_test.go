@@ -0,0 +1,53 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/himanshu-holmes/hms/db/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextFileName(t *testing.T) {
+	existing := []string{"001_initial_schema.sql", "016_visit_revisions.sql", "002_lab_orders.sql", "embed.go", "README"}
+
+	name, err := NextFileName(existing, "Add Ward Beds")
+	require.NoError(t, err)
+	assert.Equal(t, "017_add_ward_beds.sql", name)
+
+	name, err = NextFileName(nil, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "001_first.sql", name)
+
+	_, err = NextFileName(existing, " -- ")
+	assert.Error(t, err)
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "009_existing.sql"), nil, 0o644))
+
+	path, err := Create(dir, "audit-index")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "010_audit_index.sql"), path)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "-- +goose Up")
+	assert.Contains(t, string(content), "-- +goose Down")
+}
+
+func TestEmbeddedMigrationsAreNumberedInOrder(t *testing.T) {
+	entries, err := migrations.FS.ReadDir(".")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		match := migrationName.FindStringSubmatch(e.Name())
+		require.NotNil(t, match, e.Name())
+		version, err := strconv.Atoi(match[1])
+		require.NoError(t, err)
+		assert.Equal(t, i+1, version, "%s is out of sequence", e.Name())
+	}
+}
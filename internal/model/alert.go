@@ -0,0 +1,98 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObservationSource identifies where an evaluated numeric observation came from.
+type ObservationSource string
+
+const (
+	ObservationSourceVitalSign ObservationSource = "vital_sign"
+	ObservationSourceLabResult ObservationSource = "lab_result"
+)
+
+// AlertStatus is the acknowledgement state of a critical alert.
+type AlertStatus string
+
+const (
+	AlertStatusOpen         AlertStatus = "open"
+	AlertStatusAcknowledged AlertStatus = "acknowledged"
+)
+
+// Observation is a numeric vital sign recorded on a visit.
+type Observation struct {
+	ID               uuid.UUID `json:"id"`
+	VisitID          uuid.UUID `json:"visit_id"`
+	Code             string    `json:"code"`
+	Value            float64   `json:"value"`
+	Unit             *string   `json:"unit,omitempty"`
+	ObservedAt       time.Time `json:"observed_at"`
+	RecordedByUserID uuid.UUID `json:"recorded_by_user_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	Alert            *Alert    `json:"alert,omitempty"` // Set when the observation raised a critical alert
+}
+
+// ObservationCreateRequest is used to record a vital sign on a visit.
+type ObservationCreateRequest struct {
+	Code          string   `json:"code" validate:"required,max=50"`
+	Value         *float64 `json:"value" validate:"required"`
+	Unit          *string  `json:"unit,omitempty" validate:"omitempty,max=50"`
+	ObservedAtStr string   `json:"observed_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // RFC3339, defaults to now
+}
+
+// AlertRule is a configurable critical threshold for an observation code.
+type AlertRule struct {
+	ID                   uuid.UUID  `json:"id"`
+	ObservationCode      string     `json:"observation_code"`
+	Name                 string     `json:"name"`
+	Sex                  *Gender    `json:"sex,omitempty"`
+	MinAgeYears          *int       `json:"min_age_years,omitempty"`
+	MaxAgeYears          *int       `json:"max_age_years,omitempty"`
+	CriticalLow          *float64   `json:"critical_low,omitempty"`
+	CriticalHigh         *float64   `json:"critical_high,omitempty"`
+	EscalateAfterMinutes int        `json:"escalate_after_minutes"`
+	EscalationDoctorID   *uuid.UUID `json:"escalation_doctor_id,omitempty"`
+	IsActive             bool       `json:"is_active"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// AlertRuleCreateRequest is used to configure a new critical threshold.
+type AlertRuleCreateRequest struct {
+	ObservationCode      string     `json:"observation_code" validate:"required,max=50"`
+	Name                 string     `json:"name" validate:"required,max=255"`
+	Sex                  *Gender    `json:"sex,omitempty" validate:"omitempty,oneof=male female other"`
+	MinAgeYears          *int       `json:"min_age_years,omitempty" validate:"omitempty,min=0"`
+	MaxAgeYears          *int       `json:"max_age_years,omitempty" validate:"omitempty,min=0"`
+	CriticalLow          *float64   `json:"critical_low,omitempty" validate:"required_without=CriticalHigh"`
+	CriticalHigh         *float64   `json:"critical_high,omitempty" validate:"required_without=CriticalLow"`
+	EscalateAfterMinutes int        `json:"escalate_after_minutes,omitempty" validate:"omitempty,min=1,max=1440"`
+	EscalationDoctorID   *uuid.UUID `json:"escalation_doctor_id,omitempty"`
+}
+
+// Alert is a critical observation assigned to a doctor for acknowledgement.
+type Alert struct {
+	ID                   uuid.UUID         `json:"id"`
+	RuleID               uuid.UUID         `json:"rule_id"`
+	VisitID              uuid.UUID         `json:"visit_id"`
+	PatientID            uuid.UUID         `json:"patient_id"`
+	Source               ObservationSource `json:"source"`
+	ObservationCode      string            `json:"observation_code"`
+	ObservedValue        float64           `json:"observed_value"`
+	ThresholdValue       float64           `json:"threshold_value"`
+	Direction            string            `json:"direction"`
+	Message              string            `json:"message"`
+	Status               AlertStatus       `json:"status"`
+	OriginalDoctorID     uuid.UUID         `json:"original_doctor_id"`
+	AssignedDoctorID     uuid.UUID         `json:"assigned_doctor_id"`
+	EscalationLevel      int               `json:"escalation_level"`
+	EscalateAt           *time.Time        `json:"escalate_at,omitempty"`
+	EscalatedAt          *time.Time        `json:"escalated_at,omitempty"`
+	AcknowledgedAt       *time.Time        `json:"acknowledged_at,omitempty"`
+	AcknowledgedByUserID *uuid.UUID        `json:"acknowledged_by_user_id,omitempty"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
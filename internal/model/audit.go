@@ -0,0 +1,109 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is what an actor did to a resource.
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "create"
+	AuditActionRead         AuditAction = "read"
+	AuditActionList         AuditAction = "list"
+	AuditActionUpdate       AuditAction = "update"
+	AuditActionDelete       AuditAction = "delete"
+	AuditActionLogin        AuditAction = "login"
+	AuditActionLoginFailed  AuditAction = "login_failed"
+	AuditActionBreakGlass   AuditAction = "break_glass"  // Emergency access was granted
+	AuditActionNotify       AuditAction = "notify"       // A message was sent to the patient
+	AuditActionPseudonymize AuditAction = "pseudonymize" // Direct identifiers were replaced
+	AuditActionExport       AuditAction = "export"       // A de-identified dataset was exported
+)
+
+// Audited resource types.
+const (
+	AuditResourcePatient                 = "patient"
+	AuditResourceVisit                   = "visit"
+	AuditResourceUser                    = "user"
+	AuditResourceBreakGlassGrant         = "break_glass_grant"
+	AuditResourceConsent                 = "consent"
+	AuditResourceNotification            = "notification"
+	AuditResourcePatientExport           = "patient_export"
+	AuditResourcePseudonymizationRequest = "pseudonymization_request"
+	AuditResourceResearchDataset         = "research_dataset"
+)
+
+// FieldChange is the before and after value of one field. Old is null on create, New on delete.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditEntry is what a service reports about an operation. The actor, client IP, user agent
+// and request ID are taken from the request context unless ActorUserID is set.
+type AuditEntry struct {
+	Action       AuditAction
+	ResourceType string
+	ResourceID   *uuid.UUID
+	PatientID    *uuid.UUID // Whose PHI was touched, when any
+	Changes      map[string]FieldChange
+	ActorUserID  *uuid.UUID // Overrides the context actor, e.g. for logins
+	ActorRole    UserRole
+}
+
+// AuditEvent is one link in the hash-chained audit trail.
+type AuditEvent struct {
+	Seq          int64                  `json:"seq"`
+	OccurredAt   time.Time              `json:"occurred_at"`
+	ActorUserID  *uuid.UUID             `json:"actor_user_id,omitempty"`
+	ActorRole    *string                `json:"actor_role,omitempty"`
+	Action       AuditAction            `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   *uuid.UUID             `json:"resource_id,omitempty"`
+	PatientID    *uuid.UUID             `json:"patient_id,omitempty"`
+	Changes      map[string]FieldChange `json:"changes,omitempty"`
+	ClientIP     *string                `json:"client_ip,omitempty"`
+	UserAgent    *string                `json:"user_agent,omitempty"`
+	RequestID    *string                `json:"request_id,omitempty"`
+	// BreakGlassGrantID is set when the access relied on emergency break-glass access.
+	BreakGlassGrantID *uuid.UUID `json:"break_glass_grant_id,omitempty"`
+	PrevHash          string     `json:"prev_hash"`
+	Hash              string     `json:"hash"`
+}
+
+// AuditEventFilter narrows the audit trail query. Nil fields match everything.
+type AuditEventFilter struct {
+	ActorUserID  *uuid.UUID
+	PatientID    *uuid.UUID
+	ResourceType *string
+	ResourceID   *uuid.UUID
+	Action       *AuditAction
+	From         *time.Time
+	To           *time.Time
+}
+
+// AuditChainVerification is the result of re-walking the audit chain.
+type AuditChainVerification struct {
+	Valid         bool   `json:"valid"`
+	EventsChecked int64  `json:"events_checked"`
+	BrokenAtSeq   *int64 `json:"broken_at_seq,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// AccessLogEntry is one row of a patient's access log: who touched the chart, when and how.
+type AccessLogEntry struct {
+	OccurredAt    time.Time   `json:"occurred_at"`
+	ActorUserID   *uuid.UUID  `json:"actor_user_id,omitempty"`
+	ActorUsername *string     `json:"actor_username,omitempty"`
+	ActorRole     *string     `json:"actor_role,omitempty"`
+	Action        AuditAction `json:"action"`
+	ResourceType  string      `json:"resource_type"`
+	ResourceID    *uuid.UUID  `json:"resource_id,omitempty"`
+	Fields        []string    `json:"fields,omitempty"` // Fields written, sorted; empty for reads
+	ClientIP      *string     `json:"client_ip,omitempty"`
+	// BreakGlassGrantID is set when the access relied on emergency break-glass access.
+	BreakGlassGrantID *uuid.UUID `json:"break_glass_grant_id,omitempty"`
+}
@@ -0,0 +1,203 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Money fields ending in "_minor" are integer minor units (e.g. paise); rates ending in "_bps" are basis points.
+
+// ChargeSource records what produced a charge.
+type ChargeSource string
+
+const (
+	ChargeSourceVisit     ChargeSource = "visit"
+	ChargeSourceProcedure ChargeSource = "procedure"
+	ChargeSourceLabOrder  ChargeSource = "lab_order"
+	ChargeSourceManual    ChargeSource = "manual"
+)
+
+// VisitChargeCode is the charge master code captured automatically for every recorded visit.
+const VisitChargeCode = "CONSULT"
+
+// InvoiceStatus is the payment state of an invoice.
+type InvoiceStatus string
+
+const (
+	InvoiceStatusIssued        InvoiceStatus = "issued"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+)
+
+// PaymentMethod is how a payment was made.
+type PaymentMethod string
+
+const (
+	PaymentMethodCash      PaymentMethod = "cash"
+	PaymentMethodCard      PaymentMethod = "card"
+	PaymentMethodInsurance PaymentMethod = "insurance"
+)
+
+// TaxCategory is a named tax rate applied to charge master items.
+type TaxCategory struct {
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	RateBps     int       `json:"rate_bps"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TaxCategoryCreateRequest is used to add a tax category.
+type TaxCategoryCreateRequest struct {
+	Code        string `json:"code" validate:"required,max=50"`
+	Description string `json:"description" validate:"required,max=255"`
+	RateBps     int    `json:"rate_bps" validate:"min=0,max=10000"`
+}
+
+// ChargeItem is an entry in the service/charge master.
+type ChargeItem struct {
+	ID              uuid.UUID `json:"id"`
+	Code            string    `json:"code"`
+	Description     string    `json:"description"`
+	UnitPriceMinor  int64     `json:"unit_price_minor"`
+	TaxCategoryCode string    `json:"tax_category_code"`
+	TaxRateBps      int       `json:"tax_rate_bps"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ChargeItemCreateRequest is used to add an item to the charge master.
+type ChargeItemCreateRequest struct {
+	Code            string `json:"code" validate:"required,max=50"`
+	Description     string `json:"description" validate:"required,max=255"`
+	UnitPriceMinor  int64  `json:"unit_price_minor" validate:"min=0"`
+	TaxCategoryCode string `json:"tax_category_code,omitempty" validate:"omitempty,max=50"` // Defaults to "exempt"
+}
+
+// Charge is a billable item captured against a patient, invoiced later.
+type Charge struct {
+	ID              uuid.UUID    `json:"id"`
+	PatientID       uuid.UUID    `json:"patient_id"`
+	VisitID         *uuid.UUID   `json:"visit_id,omitempty"`
+	ChargeItemID    uuid.UUID    `json:"charge_item_id"`
+	Source          ChargeSource `json:"source"`
+	SourceID        *uuid.UUID   `json:"source_id,omitempty"`
+	Description     string       `json:"description"`
+	Quantity        int          `json:"quantity"`
+	UnitPriceMinor  int64        `json:"unit_price_minor"`
+	TaxRateBps      int          `json:"tax_rate_bps"`
+	InvoiceID       *uuid.UUID   `json:"invoice_id,omitempty"`
+	CreatedByUserID uuid.UUID    `json:"created_by_user_id"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+// ChargeCreateRequest is used to post a procedure or manual charge to a visit.
+type ChargeCreateRequest struct {
+	VisitID    uuid.UUID    `json:"visit_id" validate:"required"`
+	ChargeCode string       `json:"charge_code" validate:"required,max=50"`
+	Quantity   int          `json:"quantity,omitempty" validate:"omitempty,min=1"` // Defaults to 1
+	Source     ChargeSource `json:"source,omitempty" validate:"omitempty,oneof=procedure manual"`
+}
+
+// ChargeCapture describes a charge raised automatically by a clinical event.
+// SourceID makes capture idempotent: the same source is never charged twice for one item.
+type ChargeCapture struct {
+	PatientID       uuid.UUID
+	VisitID         *uuid.UUID
+	Source          ChargeSource
+	SourceID        *uuid.UUID
+	ChargeCode      string
+	Quantity        int
+	CreatedByUserID uuid.UUID
+}
+
+// InvoiceLine is a priced charge on an invoice.
+type InvoiceLine struct {
+	ID             uuid.UUID `json:"id"`
+	ChargeID       uuid.UUID `json:"charge_id"`
+	Description    string    `json:"description"`
+	Quantity       int       `json:"quantity"`
+	UnitPriceMinor int64     `json:"unit_price_minor"`
+	DiscountMinor  int64     `json:"discount_minor"`
+	TaxRateBps     int       `json:"tax_rate_bps"`
+	TaxMinor       int64     `json:"tax_minor"`
+	LineTotalMinor int64     `json:"line_total_minor"`
+}
+
+// Invoice is a numbered bill issued to a patient.
+type Invoice struct {
+	ID             uuid.UUID     `json:"id"`
+	InvoiceNumber  string        `json:"invoice_number"`
+	FiscalYear     int           `json:"fiscal_year"`
+	PatientID      uuid.UUID     `json:"patient_id"`
+	Status         InvoiceStatus `json:"status"`
+	SubtotalMinor  int64         `json:"subtotal_minor"`
+	DiscountMinor  int64         `json:"discount_minor"`
+	TaxMinor       int64         `json:"tax_minor"`
+	TotalMinor     int64         `json:"total_minor"`
+	PaidMinor      int64         `json:"paid_minor"` // Payments less refunds
+	BalanceMinor   int64         `json:"balance_minor"`
+	Notes          *string       `json:"notes,omitempty"`
+	IssuedByUserID uuid.UUID     `json:"issued_by_user_id"`
+	IssuedAt       time.Time     `json:"issued_at"`
+	Lines          []InvoiceLine `json:"lines,omitempty"`
+	Payments       []Payment     `json:"payments,omitempty"`
+	Refunds        []Refund      `json:"refunds,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// InvoiceCreateRequest issues an invoice for a patient's unbilled charges.
+type InvoiceCreateRequest struct {
+	PatientID   uuid.UUID   `json:"patient_id" validate:"required"`
+	ChargeIDs   []uuid.UUID `json:"charge_ids,omitempty"`                                        // Defaults to every unbilled charge
+	DiscountBps int         `json:"discount_bps,omitempty" validate:"omitempty,min=0,max=10000"` // Applied to each line before tax
+	Notes       *string     `json:"notes,omitempty"`
+}
+
+// Payment is money received against an invoice.
+type Payment struct {
+	ID               uuid.UUID     `json:"id"`
+	InvoiceID        uuid.UUID     `json:"invoice_id"`
+	PatientID        uuid.UUID     `json:"patient_id"`
+	Method           PaymentMethod `json:"method"`
+	AmountMinor      int64         `json:"amount_minor"`
+	Reference        *string       `json:"reference,omitempty"`
+	ReceivedByUserID uuid.UUID     `json:"received_by_user_id"`
+	ReceivedAt       time.Time     `json:"received_at"`
+}
+
+// PaymentCreateRequest records a full or partial payment.
+type PaymentCreateRequest struct {
+	Method      PaymentMethod `json:"method" validate:"required,oneof=cash card insurance"`
+	AmountMinor int64         `json:"amount_minor" validate:"required,min=1"`
+	Reference   *string       `json:"reference,omitempty" validate:"omitempty,max=255"` // Card slip, claim number, etc.
+}
+
+// Refund returns part or all of a payment.
+type Refund struct {
+	ID               uuid.UUID `json:"id"`
+	PaymentID        uuid.UUID `json:"payment_id"`
+	InvoiceID        uuid.UUID `json:"invoice_id"`
+	AmountMinor      int64     `json:"amount_minor"`
+	Reason           string    `json:"reason"`
+	RefundedByUserID uuid.UUID `json:"refunded_by_user_id"`
+	RefundedAt       time.Time `json:"refunded_at"`
+}
+
+// RefundCreateRequest is used to refund a payment.
+type RefundCreateRequest struct {
+	AmountMinor int64  `json:"amount_minor" validate:"required,min=1"`
+	Reason      string `json:"reason" validate:"required"`
+}
+
+// PatientBalance summarises what a patient owes.
+type PatientBalance struct {
+	PatientID     uuid.UUID `json:"patient_id"`
+	InvoicedMinor int64     `json:"invoiced_minor"`
+	PaidMinor     int64     `json:"paid_minor"`
+	BalanceMinor  int64     `json:"balance_minor"`  // Outstanding on issued invoices
+	UnbilledMinor int64     `json:"unbilled_minor"` // Captured charges not yet invoiced, including tax
+}
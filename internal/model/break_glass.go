@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BreakGlassRequest asks for emergency access to a patient's chart.
+type BreakGlassRequest struct {
+	Reason          string `json:"reason" validate:"required,min=10,max=2000"`
+	DurationMinutes *int   `json:"duration_minutes,omitempty" validate:"omitempty,min=5,max=240"` // Defaults to 60
+}
+
+// BreakGlassGrant is time-boxed emergency access for one user to one patient.
+type BreakGlassGrant struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	PatientID uuid.UUID  `json:"patient_id"`
+	Reason    string     `json:"reason"`
+	GrantedAt time.Time  `json:"granted_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// BreakGlassAccess is one audited access made under a grant.
+type BreakGlassAccess struct {
+	Seq          int64       `json:"seq"`
+	OccurredAt   time.Time   `json:"occurred_at"`
+	GrantID      uuid.UUID   `json:"grant_id"`
+	Action       AuditAction `json:"action"`
+	ResourceType string      `json:"resource_type"`
+	ResourceID   *uuid.UUID  `json:"resource_id,omitempty"`
+}
+
+// BreakGlassReviewItem is a grant in the daily review with its accesses on that day.
+type BreakGlassReviewItem struct {
+	BreakGlassGrant
+	Username string             `json:"username"`
+	Accesses []BreakGlassAccess `json:"accesses"`
+}
+
+// BreakGlassReport is the compliance review of one UTC day of break-glass activity.
+type BreakGlassReport struct {
+	Date        string                 `json:"date"` // YYYY-MM-DD, UTC
+	GeneratedAt time.Time              `json:"generated_at"`
+	GrantCount  int                    `json:"grant_count"`  // Grants issued that day
+	AccessCount int                    `json:"access_count"` // Accesses under any grant that day
+	Grants      []BreakGlassReviewItem `json:"grants"`
+}
+
+// BreakGlassReportSummary lists a stored report without its body.
+type BreakGlassReportSummary struct {
+	Date        string    `json:"date"`
+	GeneratedAt time.Time `json:"generated_at"`
+	GrantCount  int       `json:"grant_count"`
+	AccessCount int       `json:"access_count"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CareTeamMemberRequest assigns a doctor to a patient's care team.
+type CareTeamMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
+
+// CareTeamMember is a doctor assigned to look after a patient.
+type CareTeamMember struct {
+	PatientID        uuid.UUID `json:"patient_id"`
+	UserID           uuid.UUID `json:"user_id"`
+	Username         string    `json:"username"`
+	FirstName        *string   `json:"first_name,omitempty"`
+	LastName         *string   `json:"last_name,omitempty"`
+	Role             UserRole  `json:"role"`
+	AssignedByUserID uuid.UUID `json:"assigned_by_user_id"`
+	AssignedAt       time.Time `json:"assigned_at"`
+}
@@ -4,6 +4,7 @@ package model
 type APIError struct {
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"` // Can be map[string]string for validation errors, or a simple string
+	TraceID string      `json:"trace_id,omitempty"` // Quote it when reporting the error
 }
 
 // PaginationParams defines common query parameters for paginated API endpoints.
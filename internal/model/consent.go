@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentType is something a patient can agree to.
+type ConsentType string
+
+const (
+	ConsentTreatment   ConsentType = "treatment"
+	ConsentDataSharing ConsentType = "data_sharing" // Releasing the patient's data outside the hospital
+	ConsentResearch    ConsentType = "research"     // Including the patient in research datasets
+	ConsentSMSContact  ConsentType = "sms_contact"  // Contacting the patient by SMS
+)
+
+// ConsentTypes lists every consent type with what it covers.
+var ConsentTypes = []ConsentTypeInfo{
+	{Type: ConsentTreatment, Description: "Treatment by the hospital's clinical staff"},
+	{Type: ConsentDataSharing, Description: "Sharing the patient's records outside the hospital, including data exports"},
+	{Type: ConsentResearch, Description: "Use of the patient's de-identified records in research"},
+	{Type: ConsentSMSContact, Description: "Contacting the patient by SMS"},
+}
+
+// ConsentTypeInfo describes a consent type.
+type ConsentTypeInfo struct {
+	Type        ConsentType `json:"type"`
+	Description string      `json:"description"`
+}
+
+// ConsentRecord is one consent given by a patient. It is in force until withdrawn.
+type ConsentRecord struct {
+	ID                uuid.UUID   `json:"id"`
+	PatientID         uuid.UUID   `json:"patient_id"`
+	Type              ConsentType `json:"consent_type"`
+	TextVersion       string      `json:"text_version"`
+	SignedAt          time.Time   `json:"signed_at"`
+	WitnessedByUserID uuid.UUID   `json:"witnessed_by_user_id"`
+	WithdrawnAt       *time.Time  `json:"withdrawn_at,omitempty"`
+	WithdrawnByUserID *uuid.UUID  `json:"withdrawn_by_user_id,omitempty"`
+	WithdrawalReason  *string     `json:"withdrawal_reason,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+// Active reports whether the consent is in force.
+func (r ConsentRecord) Active() bool {
+	return r.WithdrawnAt == nil
+}
+
+// ConsentCreateRequest records a consent the patient has signed. The calling user is
+// recorded as the witness. Signing a type that is already in force replaces it.
+type ConsentCreateRequest struct {
+	Type        ConsentType `json:"consent_type" validate:"required,oneof=treatment data_sharing research sms_contact"`
+	TextVersion string      `json:"text_version" validate:"required,max=50"`                                     // Version of the consent text the patient signed
+	SignedAtStr string      `json:"signed_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // RFC3339, defaults to now
+}
+
+// ConsentWithdrawRequest records that the patient withdrew a consent.
+type ConsentWithdrawRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=2000"`
+}
+
+// SMSNotificationRequest sends an SMS to the patient's contact phone.
+type SMSNotificationRequest struct {
+	Body string `json:"body" validate:"required,max=480"`
+}
+
+// NotificationReceipt acknowledges a notification handed to the provider.
+type NotificationReceipt struct {
+	PatientID uuid.UUID `json:"patient_id"`
+	Channel   string    `json:"channel"`
+	Reference string    `json:"reference"`
+	SentAt    time.Time `json:"sent_at"`
+}
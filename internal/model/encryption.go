@@ -0,0 +1,13 @@
+package model
+
+// ReencryptionResult summarises a pass rewriting encrypted columns under the active
+// master key. Skipped rows changed during the pass; running it again picks them up.
+type ReencryptionResult struct {
+	PatientsScanned    int `json:"patients_scanned"`
+	PatientsRewritten  int `json:"patients_rewritten"`
+	VisitsScanned      int `json:"visits_scanned"`
+	VisitsRewritten    int `json:"visits_rewritten"`
+	RevisionsScanned   int `json:"revisions_scanned"`
+	RevisionsRewritten int `json:"revisions_rewritten"`
+	Skipped            int `json:"skipped"`
+}
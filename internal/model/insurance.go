@@ -0,0 +1,147 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PolicyPriority orders a patient's policies for coordination of benefits.
+type PolicyPriority string
+
+const (
+	PolicyPriorityPrimary   PolicyPriority = "primary"
+	PolicyPrioritySecondary PolicyPriority = "secondary"
+)
+
+// EligibilityStatus is the result of the most recent eligibility verification.
+type EligibilityStatus string
+
+const (
+	EligibilityEligible   EligibilityStatus = "eligible"
+	EligibilityIneligible EligibilityStatus = "ineligible"
+	EligibilityUnknown    EligibilityStatus = "unknown"
+)
+
+// ClaimStatus tracks a claim through submitted -> accepted -> paid, or rejected.
+type ClaimStatus string
+
+const (
+	ClaimStatusSubmitted ClaimStatus = "submitted"
+	ClaimStatusAccepted  ClaimStatus = "accepted"
+	ClaimStatusRejected  ClaimStatus = "rejected"
+	ClaimStatusPaid      ClaimStatus = "paid"
+)
+
+// CanTransitionTo reports whether a claim in status s may move to next.
+// Payers may reject or pay a claim straight from submitted without an explicit acceptance.
+func (s ClaimStatus) CanTransitionTo(next ClaimStatus) bool {
+	switch s {
+	case ClaimStatusSubmitted:
+		return next == ClaimStatusAccepted || next == ClaimStatusRejected || next == ClaimStatusPaid
+	case ClaimStatusAccepted:
+		return next == ClaimStatusRejected || next == ClaimStatusPaid
+	default:
+		return false
+	}
+}
+
+// InsurancePolicy is a patient's coverage with a payer.
+type InsurancePolicy struct {
+	ID                   uuid.UUID         `json:"id"`
+	PatientID            uuid.UUID         `json:"patient_id"`
+	PayerCode            string            `json:"payer_code"`
+	PayerName            string            `json:"payer_name"`
+	PlanName             *string           `json:"plan_name,omitempty"`
+	MemberID             string            `json:"member_id"`
+	GroupNumber          *string           `json:"group_number,omitempty"`
+	ValidFrom            time.Time         `json:"valid_from"`
+	ValidTo              *time.Time        `json:"valid_to,omitempty"`
+	Priority             PolicyPriority    `json:"priority"`
+	IsActive             bool              `json:"is_active"`
+	EligibilityStatus    EligibilityStatus `json:"eligibility_status"`
+	EligibilityCheckedAt *time.Time        `json:"eligibility_checked_at,omitempty"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
+
+// CoversDate reports whether the policy is active and valid on the given day.
+func (p InsurancePolicy) CoversDate(day time.Time) bool {
+	if !p.IsActive || day.Before(p.ValidFrom) {
+		return false
+	}
+	// valid_to is inclusive, so coverage ends at the end of that day.
+	return p.ValidTo == nil || day.Before(p.ValidTo.AddDate(0, 0, 1))
+}
+
+// InsurancePolicyCreateRequest is used to add a policy to a patient.
+type InsurancePolicyCreateRequest struct {
+	PayerCode   string         `json:"payer_code" validate:"required,max=50"`
+	PayerName   string         `json:"payer_name" validate:"required,max=255"`
+	PlanName    *string        `json:"plan_name,omitempty" validate:"omitempty,max=255"`
+	MemberID    string         `json:"member_id" validate:"required,max=100"`
+	GroupNumber *string        `json:"group_number,omitempty" validate:"omitempty,max=100"`
+	ValidFrom   string         `json:"valid_from" validate:"required,datetime=2006-01-02"`
+	ValidTo     *string        `json:"valid_to,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Priority    PolicyPriority `json:"priority,omitempty" validate:"omitempty,oneof=primary secondary"` // Defaults to primary
+}
+
+// EligibilityCheck is one eligibility verification against the payer.
+type EligibilityCheck struct {
+	ID              uuid.UUID         `json:"id"`
+	PolicyID        uuid.UUID         `json:"policy_id"`
+	Status          EligibilityStatus `json:"status"`
+	Message         *string           `json:"message,omitempty"`
+	PayerReference  *string           `json:"payer_reference,omitempty"`
+	CheckedByUserID uuid.UUID         `json:"checked_by_user_id"`
+	CheckedAt       time.Time         `json:"checked_at"`
+}
+
+// InsuranceClaim is a claim raised from an invoice against a policy.
+type InsuranceClaim struct {
+	ID                uuid.UUID            `json:"id"`
+	InvoiceID         uuid.UUID            `json:"invoice_id"`
+	PolicyID          uuid.UUID            `json:"policy_id"`
+	PatientID         uuid.UUID            `json:"patient_id"`
+	DiagnosisCodes    []string             `json:"diagnosis_codes"`
+	ClaimedMinor      int64                `json:"claimed_minor"`
+	PaidMinor         *int64               `json:"paid_minor,omitempty"`
+	Status            ClaimStatus          `json:"status"`
+	PayerReference    *string              `json:"payer_reference,omitempty"`
+	RejectionReason   *string              `json:"rejection_reason,omitempty"`
+	SubmittedByUserID uuid.UUID            `json:"submitted_by_user_id"`
+	SubmittedAt       time.Time            `json:"submitted_at"`
+	SentToPayerAt     *time.Time           `json:"sent_to_payer_at,omitempty"` // Unset until the payer acknowledges the claim
+	History           []ClaimStatusHistory `json:"history,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// ClaimStatusHistory is one recorded status of a claim.
+type ClaimStatusHistory struct {
+	Status          ClaimStatus `json:"status"`
+	Note            *string     `json:"note,omitempty"`
+	ChangedByUserID uuid.UUID   `json:"changed_by_user_id"`
+	ChangedAt       time.Time   `json:"changed_at"`
+}
+
+// InsuranceClaimCreateRequest generates a claim for an invoice's outstanding balance.
+type InsuranceClaimCreateRequest struct {
+	InvoiceID      uuid.UUID `json:"invoice_id" validate:"required"`
+	PolicyID       uuid.UUID `json:"policy_id" validate:"required"`
+	DiagnosisCodes []string  `json:"diagnosis_codes" validate:"required,min=1,dive,required,max=20"` // ICD-10 codes, e.g. J45.909
+}
+
+// ClaimStatusUpdateRequest records the payer's response to a claim.
+type ClaimStatusUpdateRequest struct {
+	Status          ClaimStatus `json:"status" validate:"required,oneof=accepted rejected paid"`
+	RejectionReason *string     `json:"rejection_reason,omitempty" validate:"required_if=Status rejected"`
+	PaidMinor       *int64      `json:"paid_minor,omitempty" validate:"required_if=Status paid,omitempty,min=1"`
+	PayerReference  *string     `json:"payer_reference,omitempty" validate:"omitempty,max=255"`
+	Note            *string     `json:"note,omitempty"`
+}
+
+// EligibilityCheckRequest asks the payer to verify a policy for a date of service.
+type EligibilityCheckRequest struct {
+	DateOfService *string `json:"date_of_service,omitempty" validate:"omitempty,datetime=2006-01-02"` // Defaults to today
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, ClaimStatusSubmitted.CanTransitionTo(ClaimStatusAccepted))
+	assert.True(t, ClaimStatusSubmitted.CanTransitionTo(ClaimStatusRejected))
+	assert.True(t, ClaimStatusAccepted.CanTransitionTo(ClaimStatusPaid))
+
+	assert.False(t, ClaimStatusRejected.CanTransitionTo(ClaimStatusAccepted))
+	assert.False(t, ClaimStatusPaid.CanTransitionTo(ClaimStatusRejected))
+	assert.False(t, ClaimStatusAccepted.CanTransitionTo(ClaimStatusSubmitted))
+}
+
+func TestInsurancePolicy_CoversDate(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	end := day("2026-12-31")
+	p := InsurancePolicy{IsActive: true, ValidFrom: day("2026-01-01"), ValidTo: &end}
+
+	assert.True(t, p.CoversDate(day("2026-01-01")))
+	assert.True(t, p.CoversDate(day("2026-12-31").Add(23*time.Hour)))
+	assert.False(t, p.CoversDate(day("2025-12-31")))
+	assert.False(t, p.CoversDate(day("2027-01-01")))
+
+	p.IsActive = false
+	assert.False(t, p.CoversDate(day("2026-06-01")))
+}
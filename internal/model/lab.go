@@ -0,0 +1,218 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LabSpecimenType is the kind of specimen a lab test is run on.
+type LabSpecimenType string
+
+const (
+	SpecimenBlood  LabSpecimenType = "blood"
+	SpecimenSerum  LabSpecimenType = "serum"
+	SpecimenPlasma LabSpecimenType = "plasma"
+	SpecimenUrine  LabSpecimenType = "urine"
+	SpecimenStool  LabSpecimenType = "stool"
+	SpecimenSputum LabSpecimenType = "sputum"
+	SpecimenCSF    LabSpecimenType = "csf"
+	SpecimenSwab   LabSpecimenType = "swab"
+	SpecimenTissue LabSpecimenType = "tissue"
+	SpecimenOther  LabSpecimenType = "other"
+)
+
+// LabOrderPriority defines how quickly an order must be processed.
+type LabOrderPriority string
+
+const (
+	LabPriorityRoutine LabOrderPriority = "routine"
+	LabPriorityUrgent  LabOrderPriority = "urgent"
+	LabPriorityStat    LabOrderPriority = "stat"
+)
+
+// LabOrderStatus tracks an order through ordered -> collected -> resulted -> verified.
+type LabOrderStatus string
+
+const (
+	LabStatusOrdered   LabOrderStatus = "ordered"
+	LabStatusCollected LabOrderStatus = "collected"
+	LabStatusResulted  LabOrderStatus = "resulted"
+	LabStatusVerified  LabOrderStatus = "verified"
+)
+
+// CanTransitionTo reports whether an order in status s may move to next.
+// Re-entering "resulted" is allowed so results can be corrected before verification.
+func (s LabOrderStatus) CanTransitionTo(next LabOrderStatus) bool {
+	switch s {
+	case LabStatusOrdered:
+		return next == LabStatusCollected
+	case LabStatusCollected:
+		return next == LabStatusResulted
+	case LabStatusResulted:
+		return next == LabStatusResulted || next == LabStatusVerified
+	default:
+		return false
+	}
+}
+
+// LabResultFlag marks a result relative to its reference and critical ranges.
+type LabResultFlag string
+
+const (
+	LabFlagNormal       LabResultFlag = "normal"
+	LabFlagLow          LabResultFlag = "low"
+	LabFlagHigh         LabResultFlag = "high"
+	LabFlagCriticalLow  LabResultFlag = "critical_low"
+	LabFlagCriticalHigh LabResultFlag = "critical_high"
+	LabFlagAbnormal     LabResultFlag = "abnormal"
+)
+
+// IsCritical reports whether the flag requires immediate clinical attention.
+func (f LabResultFlag) IsCritical() bool {
+	return f == LabFlagCriticalLow || f == LabFlagCriticalHigh
+}
+
+// LabReferenceRange holds the optional bounds used to flag a numeric result.
+type LabReferenceRange struct {
+	Low          *float64 `json:"reference_low,omitempty"`
+	High         *float64 `json:"reference_high,omitempty"`
+	CriticalLow  *float64 `json:"critical_low,omitempty"`
+	CriticalHigh *float64 `json:"critical_high,omitempty"`
+}
+
+// Flag classifies value against the range. Critical bounds win over reference bounds.
+func (r LabReferenceRange) Flag(value float64) LabResultFlag {
+	switch {
+	case r.CriticalLow != nil && value <= *r.CriticalLow:
+		return LabFlagCriticalLow
+	case r.CriticalHigh != nil && value >= *r.CriticalHigh:
+		return LabFlagCriticalHigh
+	case r.Low != nil && value < *r.Low:
+		return LabFlagLow
+	case r.High != nil && value > *r.High:
+		return LabFlagHigh
+	default:
+		return LabFlagNormal
+	}
+}
+
+// LabAnalyte is a single measured component of a lab test (e.g. hemoglobin in a CBC).
+type LabAnalyte struct {
+	ID           uuid.UUID `json:"id"`
+	TestID       uuid.UUID `json:"test_id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Unit         *string   `json:"unit,omitempty"`
+	DisplayOrder int       `json:"display_order"`
+	LabReferenceRange
+}
+
+// LabTest is an entry in the lab test catalog.
+type LabTest struct {
+	ID           uuid.UUID       `json:"id"`
+	Code         string          `json:"code"`
+	Name         string          `json:"name"`
+	SpecimenType LabSpecimenType `json:"specimen_type"`
+	Description  *string         `json:"description,omitempty"`
+	ChargeCode   *string         `json:"charge_code,omitempty"` // Charge master item billed when the test is ordered
+	IsActive     bool            `json:"is_active"`
+	Analytes     []LabAnalyte    `json:"analytes,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// LabOrder is a lab test ordered against a patient visit.
+type LabOrder struct {
+	ID                uuid.UUID        `json:"id"`
+	VisitID           uuid.UUID        `json:"visit_id"`
+	TestID            uuid.UUID        `json:"test_id"`
+	OrderedByUserID   uuid.UUID        `json:"ordered_by_user_id"`
+	Priority          LabOrderPriority `json:"priority"`
+	Status            LabOrderStatus   `json:"status"`
+	ClinicalNotes     *string          `json:"clinical_notes,omitempty"`
+	OrderedAt         time.Time        `json:"ordered_at"`
+	CollectedAt       *time.Time       `json:"collected_at,omitempty"`
+	CollectedByUserID *uuid.UUID       `json:"collected_by_user_id,omitempty"`
+	ResultedAt        *time.Time       `json:"resulted_at,omitempty"`
+	ResultedByUserID  *uuid.UUID       `json:"resulted_by_user_id,omitempty"`
+	VerifiedAt        *time.Time       `json:"verified_at,omitempty"`
+	VerifiedByUserID  *uuid.UUID       `json:"verified_by_user_id,omitempty"`
+	Results           []LabResult      `json:"results,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// LabResult is the result for one analyte of a lab order.
+type LabResult struct {
+	ID              uuid.UUID      `json:"id"`
+	OrderID         uuid.UUID      `json:"order_id"`
+	AnalyteID       uuid.UUID      `json:"analyte_id"`
+	AnalyteCode     string         `json:"analyte_code"`
+	AnalyteName     string         `json:"analyte_name"`
+	ValueNumeric    *float64       `json:"value_numeric,omitempty"`
+	ValueText       *string        `json:"value_text,omitempty"`
+	Unit            *string        `json:"unit,omitempty"`
+	ReferenceLow    *float64       `json:"reference_low,omitempty"`
+	ReferenceHigh   *float64       `json:"reference_high,omitempty"`
+	Flag            *LabResultFlag `json:"flag,omitempty"`
+	Comment         *string        `json:"comment,omitempty"`
+	EnteredByUserID uuid.UUID      `json:"entered_by_user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// CumulativeLabResult is a result row in a patient's cumulative (trending) view.
+type CumulativeLabResult struct {
+	LabResult
+	TestCode    string         `json:"test_code"`
+	VisitID     uuid.UUID      `json:"visit_id"`
+	OrderStatus LabOrderStatus `json:"order_status"`
+	ObservedAt  time.Time      `json:"observed_at"`
+}
+
+// LabAnalyteCreateRequest describes an analyte when adding a test to the catalog.
+type LabAnalyteCreateRequest struct {
+	Code          string   `json:"code" validate:"required,max=50"`
+	Name          string   `json:"name" validate:"required,max=255"`
+	Unit          *string  `json:"unit,omitempty" validate:"omitempty,max=50"`
+	ReferenceLow  *float64 `json:"reference_low,omitempty"`
+	ReferenceHigh *float64 `json:"reference_high,omitempty"`
+	CriticalLow   *float64 `json:"critical_low,omitempty"`
+	CriticalHigh  *float64 `json:"critical_high,omitempty"`
+	DisplayOrder  int      `json:"display_order,omitempty" validate:"omitempty,min=0"`
+}
+
+// LabTestCreateRequest is used to add a test to the catalog.
+type LabTestCreateRequest struct {
+	Code         string                    `json:"code" validate:"required,max=50"`
+	Name         string                    `json:"name" validate:"required,max=255"`
+	SpecimenType LabSpecimenType           `json:"specimen_type" validate:"required,oneof=blood serum plasma urine stool sputum csf swab tissue other"`
+	Description  *string                   `json:"description,omitempty"`
+	ChargeCode   *string                   `json:"charge_code,omitempty" validate:"omitempty,max=50"`
+	Analytes     []LabAnalyteCreateRequest `json:"analytes" validate:"required,min=1,dive"`
+}
+
+// LabOrderCreateRequest is used by doctors to order a test for a visit.
+// The ordering doctor is taken from the authenticated user.
+type LabOrderCreateRequest struct {
+	VisitID       uuid.UUID        `json:"visit_id" validate:"required"`
+	TestCode      string           `json:"test_code" validate:"required,max=50"`
+	Priority      LabOrderPriority `json:"priority,omitempty" validate:"omitempty,oneof=routine urgent stat"`
+	ClinicalNotes *string          `json:"clinical_notes,omitempty"`
+}
+
+// LabResultValue is a single analyte value in a result entry. A numeric value is flagged
+// against the analyte's ranges; a text value carries the flag the technician gives it, if any.
+type LabResultValue struct {
+	AnalyteCode  string         `json:"analyte_code" validate:"required,max=50"`
+	ValueNumeric *float64       `json:"value_numeric,omitempty" validate:"required_without=ValueText"`
+	ValueText    *string        `json:"value_text,omitempty" validate:"required_without=ValueNumeric"`
+	Flag         *LabResultFlag `json:"flag,omitempty" validate:"omitempty,excluded_with=ValueNumeric,oneof=normal abnormal"`
+	Comment      *string        `json:"comment,omitempty"`
+}
+
+// LabResultEntryRequest is used by lab technicians to record results for an order.
+type LabResultEntryRequest struct {
+	Results []LabResultValue `json:"results" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestLabReferenceRange_Flag(t *testing.T) {
+	potassium := LabReferenceRange{Low: ptr(3.5), High: ptr(5.1), CriticalLow: ptr(2.5), CriticalHigh: ptr(6.5)}
+
+	assert.Equal(t, LabFlagNormal, potassium.Flag(4.2))
+	assert.Equal(t, LabFlagLow, potassium.Flag(3.0))
+	assert.Equal(t, LabFlagHigh, potassium.Flag(5.8))
+	assert.Equal(t, LabFlagCriticalLow, potassium.Flag(2.5))
+	assert.Equal(t, LabFlagCriticalHigh, potassium.Flag(7.0))
+
+	// Missing bounds never flag.
+	assert.Equal(t, LabFlagNormal, LabReferenceRange{}.Flag(1000))
+	assert.Equal(t, LabFlagHigh, LabReferenceRange{High: ptr(10)}.Flag(11))
+}
+
+func TestLabOrderStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, LabStatusOrdered.CanTransitionTo(LabStatusCollected))
+	assert.True(t, LabStatusCollected.CanTransitionTo(LabStatusResulted))
+	assert.True(t, LabStatusResulted.CanTransitionTo(LabStatusResulted))
+	assert.True(t, LabStatusResulted.CanTransitionTo(LabStatusVerified))
+
+	assert.False(t, LabStatusOrdered.CanTransitionTo(LabStatusResulted))
+	assert.False(t, LabStatusCollected.CanTransitionTo(LabStatusVerified))
+	assert.False(t, LabStatusVerified.CanTransitionTo(LabStatusResulted))
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +30,8 @@ type Patient struct {
 	RegisteredByUserID  uuid.UUID  `json:"registered_by_user_id"`
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
+	PseudonymizedAt     *time.Time `json:"pseudonymized_at,omitempty"` // Set once direct identifiers were replaced
+	Version             int32      `json:"version"`                    // Changes on every update; sent as the ETag
 	DeletedAt           *time.Time `json:"-"` // For soft delete, typically excluded from normal JSON responses.
 }
 
@@ -43,6 +46,27 @@ type PatientCreateRequest struct {
 	MedicalHistory *string `json:"medical_history,omitempty"` 
 }
 
+// Parse converts the request to the form the patient service takes.
+func (r PatientCreateRequest) Parse() (ParsedPatientRequest, error) {
+	var dob time.Time
+	if r.DateOfBirth != "" {
+		var err error
+		if dob, err = time.Parse("2006-01-02", r.DateOfBirth); err != nil {
+			return ParsedPatientRequest{}, fmt.Errorf("invalid date_of_birth format: %w. Expected YYYY-MM-DD", err)
+		}
+	}
+	return ParsedPatientRequest{
+		FirstName:      r.FirstName,
+		LastName:       r.LastName,
+		DateOfBirth:    dob,
+		Gender:         r.Gender,
+		ContactPhone:   r.ContactPhone,
+		ContactEmail:   r.ContactEmail,
+		Address:        r.Address,
+		MedicalHistory: r.MedicalHistory,
+	}, nil
+}
+
 // PatientUpdateRequest is used for updating an existing patient's details.
 
 type PatientUpdateRequest struct {
@@ -67,4 +91,11 @@ type ParsedPatientRequest struct {
 	ContactEmail   *string
 	Address        *string
 	MedicalHistory *string
-}
\ No newline at end of file
+}
+
+// IdentifiersSet reports whether the request changes any direct identifier: name, date of
+// birth, contact details or address.
+func (r ParsedPatientRequest) IdentifiersSet() bool {
+	return r.FirstName != "" || r.LastName != "" || !r.DateOfBirth.IsZero() ||
+		r.ContactPhone != nil || r.ContactEmail != nil || r.Address != nil
+}
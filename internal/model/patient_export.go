@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PatientExportStatus is where a patient data export is in its lifecycle.
+type PatientExportStatus string
+
+const (
+	PatientExportPending   PatientExportStatus = "pending"
+	PatientExportRunning   PatientExportStatus = "running"
+	PatientExportCompleted PatientExportStatus = "completed" // The archive can be downloaded until expires_at
+	PatientExportFailed    PatientExportStatus = "failed"
+	PatientExportExpired   PatientExportStatus = "expired" // The archive has been deleted
+)
+
+// PatientExport is a subject access request export of everything held about a patient.
+type PatientExport struct {
+	ID                uuid.UUID           `json:"id"`
+	PatientID         uuid.UUID           `json:"patient_id"`
+	RequestedByUserID uuid.UUID           `json:"requested_by_user_id"`
+	Status            PatientExportStatus `json:"status"`
+	Error             *string             `json:"error,omitempty"`
+	SizeBytes         *int64              `json:"size_bytes,omitempty"`
+	RequestedAt       time.Time           `json:"requested_at"`
+	StartedAt         *time.Time          `json:"started_at,omitempty"`
+	CompletedAt       *time.Time          `json:"completed_at,omitempty"`
+	ExpiresAt         *time.Time          `json:"expires_at,omitempty"`
+	DownloadURL       *string             `json:"download_url,omitempty"` // Set while the archive can be downloaded
+}
+
+// Downloadable reports whether the archive can still be downloaded at now.
+func (e PatientExport) Downloadable(now time.Time) bool {
+	return e.Status == PatientExportCompleted && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockMovementType classifies an entry in the stock ledger.
+type StockMovementType string
+
+const (
+	StockMovementReceipt    StockMovementType = "receipt"
+	StockMovementDispense   StockMovementType = "dispense"
+	StockMovementReturn     StockMovementType = "return"
+	StockMovementAdjustment StockMovementType = "adjustment"
+)
+
+// PharmacyLocation is a store that holds stock.
+type PharmacyLocation struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PharmacyLocationCreateRequest is used to add a store location.
+type PharmacyLocationCreateRequest struct {
+	Code string `json:"code" validate:"required,max=50"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// PharmacyItem is a drug in the formulary.
+type PharmacyItem struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	DosageForm   *string   `json:"dosage_form,omitempty"`
+	Strength     *string   `json:"strength,omitempty"`
+	Unit         string    `json:"unit"`
+	ReorderLevel int       `json:"reorder_level"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// PharmacyItemCreateRequest is used to add a drug to the formulary.
+type PharmacyItemCreateRequest struct {
+	Code         string  `json:"code" validate:"required,max=50"`
+	Name         string  `json:"name" validate:"required,max=255"`
+	DosageForm   *string `json:"dosage_form,omitempty" validate:"omitempty,max=50"` // e.g. tablet, syrup
+	Strength     *string `json:"strength,omitempty" validate:"omitempty,max=50"`    // e.g. 500 mg
+	Unit         string  `json:"unit,omitempty" validate:"omitempty,max=20"`        // Dispensing unit; defaults to "unit"
+	ReorderLevel int     `json:"reorder_level" validate:"min=0"`
+}
+
+// StockMovement is one entry in the append-only stock ledger. Quantity is signed:
+// receipts and returns are positive, dispenses negative.
+type StockMovement struct {
+	ID                 uuid.UUID         `json:"id"`
+	MovementType       StockMovementType `json:"movement_type"`
+	LocationID         uuid.UUID         `json:"location_id"`
+	ItemID             uuid.UUID         `json:"item_id"`
+	ItemCode           string            `json:"item_code"`
+	BatchID            uuid.UUID         `json:"batch_id"`
+	LotNumber          string            `json:"lot_number"`
+	Quantity           int               `json:"quantity"`
+	VisitID            *uuid.UUID        `json:"visit_id,omitempty"`
+	ReturnedMovementID *uuid.UUID        `json:"returned_movement_id,omitempty"`
+	Reference          *string           `json:"reference,omitempty"`
+	Reason             *string           `json:"reason,omitempty"`
+	CreatedByUserID    uuid.UUID         `json:"created_by_user_id"`
+	CreatedAt          time.Time         `json:"created_at"`
+}
+
+// StockLevel is the quantity of one batch held at a location.
+type StockLevel struct {
+	LocationID   uuid.UUID `json:"location_id"`
+	LocationCode string    `json:"location_code"`
+	ItemID       uuid.UUID `json:"item_id"`
+	ItemCode     string    `json:"item_code"`
+	ItemName     string    `json:"item_name"`
+	Unit         string    `json:"unit"`
+	BatchID      uuid.UUID `json:"batch_id"`
+	LotNumber    string    `json:"lot_number"`
+	ExpiryDate   time.Time `json:"expiry_date"`
+	Quantity     int       `json:"quantity"`
+	Expired      bool      `json:"expired"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ReorderAlert flags an item whose unexpired stock at a location is at or below its reorder level.
+type ReorderAlert struct {
+	ItemID       uuid.UUID `json:"item_id"`
+	ItemCode     string    `json:"item_code"`
+	ItemName     string    `json:"item_name"`
+	LocationID   uuid.UUID `json:"location_id"`
+	LocationCode string    `json:"location_code"`
+	OnHand       int64     `json:"on_hand"`
+	ReorderLevel int       `json:"reorder_level"`
+}
+
+// GoodsReceiptLine is one lot received into stock.
+type GoodsReceiptLine struct {
+	ItemID     uuid.UUID `json:"item_id" validate:"required"`
+	LotNumber  string    `json:"lot_number" validate:"required,max=100"`
+	ExpiryDate string    `json:"expiry_date" validate:"required,datetime=2006-01-02"`
+	Quantity   int       `json:"quantity" validate:"required,min=1"`
+}
+
+// GoodsReceiptRequest records stock delivered to a location.
+type GoodsReceiptRequest struct {
+	LocationID uuid.UUID          `json:"location_id" validate:"required"`
+	Reference  *string            `json:"reference,omitempty" validate:"omitempty,max=255"` // Supplier invoice or delivery note number
+	Lines      []GoodsReceiptLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+// DispenseLine is one prescribed item to dispense. The batches are chosen first-expiry-first-out.
+type DispenseLine struct {
+	ItemID   uuid.UUID `json:"item_id" validate:"required"`
+	Quantity int       `json:"quantity" validate:"required,min=1"`
+}
+
+// DispenseRequest dispenses a visit's prescription from a location.
+type DispenseRequest struct {
+	LocationID uuid.UUID      `json:"location_id" validate:"required"`
+	Lines      []DispenseLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+// StockReturnRequest puts dispensed stock back into the batch it came from.
+type StockReturnRequest struct {
+	Quantity int     `json:"quantity" validate:"required,min=1"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+// StockAdjustmentRequest corrects a batch's quantity after a count, breakage or write-off.
+type StockAdjustmentRequest struct {
+	LocationID uuid.UUID `json:"location_id" validate:"required"`
+	BatchID    uuid.UUID `json:"batch_id" validate:"required"`
+	Quantity   int       `json:"quantity" validate:"required,ne=0"` // Signed; negative removes stock
+	Reason     string    `json:"reason" validate:"required"`
+}
+
+// StockMovementFilter narrows the stock ledger listing.
+type StockMovementFilter struct {
+	LocationID *uuid.UUID
+	ItemID     *uuid.UUID
+	BatchID    *uuid.UUID
+}
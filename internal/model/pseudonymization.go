@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PseudonymizationStatus is where an erasure request is in its approval.
+type PseudonymizationStatus string
+
+const (
+	PseudonymizationPending  PseudonymizationStatus = "pending"
+	PseudonymizationApproved PseudonymizationStatus = "approved" // The patient has been pseudonymized
+	PseudonymizationRejected PseudonymizationStatus = "rejected"
+)
+
+// PseudonymizationRequest asks for a patient's direct identifiers to be replaced. It
+// takes effect when an admin other than the requester approves it.
+type PseudonymizationRequest struct {
+	ID                uuid.UUID              `json:"id"`
+	PatientID         uuid.UUID              `json:"patient_id"`
+	RequestedByUserID uuid.UUID              `json:"requested_by_user_id"`
+	Reason            string                 `json:"reason"`
+	Status            PseudonymizationStatus `json:"status"`
+	DecidedByUserID   *uuid.UUID             `json:"decided_by_user_id,omitempty"`
+	DecidedAt         *time.Time             `json:"decided_at,omitempty"`
+	DecisionNote      *string                `json:"decision_note,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+}
+
+// PseudonymizationChange is what pseudonymizing does to one patient field.
+type PseudonymizationChange struct {
+	Field       string  `json:"field"`
+	Current     *string `json:"current,omitempty"`
+	Replacement *string `json:"replacement,omitempty"` // Omitted when the field is cleared
+}
+
+// PseudonymizationPreview is a dry run: what approving a request would change, and what
+// is kept linked to the patient.
+type PseudonymizationPreview struct {
+	PatientID uuid.UUID                `json:"patient_id"`
+	Changes   []PseudonymizationChange `json:"changes"`
+	Retained  []string                 `json:"retained"`
+}
+
+// PseudonymizationCreateRequest asks for a patient to be pseudonymized.
+type PseudonymizationCreateRequest struct {
+	Reason string `json:"reason" validate:"required,max=2000"` // e.g. the reference of the patient's erasure request
+}
+
+// PseudonymizationDecisionRequest approves or rejects a request.
+type PseudonymizationDecisionRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,max=2000"`
+}
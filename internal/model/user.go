@@ -10,8 +10,12 @@ import (
 type UserRole string
 
 const (
-	RoleReceptionist UserRole = "receptionist"
-	RoleDoctor       UserRole = "doctor"
+	RoleReceptionist   UserRole = "receptionist"
+	RoleDoctor         UserRole = "doctor"
+	RoleLabTechnician  UserRole = "lab_technician"
+	RolePharmacist     UserRole = "pharmacist"
+	RoleAdmin          UserRole = "admin" // Provisioned in the database; cannot self-register
+	RolePrivacyOfficer UserRole = "privacy_officer" // Provisioned in the database; cannot self-register
 )
 
 type User struct {
@@ -31,7 +35,18 @@ type User struct {
 type UserCreateRequest struct {
 	Username  string   `json:"username" validate:"required,min=3,max=100"`
 	Password  string   `json:"password" validate:"required,min=6"`
-	Role      UserRole `json:"role" validate:"required,oneof=receptionist doctor"`
+	Role      UserRole `json:"role" validate:"required,oneof=receptionist doctor lab_technician pharmacist"`
+	FirstName *string  `json:"first_name,omitempty" validate:"omitempty,max=100"`
+	LastName  *string  `json:"last_name,omitempty" validate:"omitempty,max=100"`
+	Email     *string  `json:"email,omitempty" validate:"omitempty,email,max=255"`
+}
+
+// UserProvisionRequest creates a user from the command line, where the roles that cannot
+// self-register may be given too.
+type UserProvisionRequest struct {
+	Username  string   `json:"username" validate:"required,min=3,max=100"`
+	Password  string   `json:"password" validate:"required,min=6"`
+	Role      UserRole `json:"role" validate:"required,oneof=receptionist doctor lab_technician pharmacist admin privacy_officer"`
 	FirstName *string  `json:"first_name,omitempty" validate:"omitempty,max=100"`
 	LastName  *string  `json:"last_name,omitempty" validate:"omitempty,max=100"`
 	Email     *string  `json:"email,omitempty" validate:"omitempty,email,max=255"`
@@ -19,6 +19,10 @@ type PatientVisit struct {
 	Notes        *string   `json:"notes,omitempty"` // Additional notes by doctor or about the visit
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int32     `json:"version"` // Changes on every update; sent as the ETag
+	// FinalizedAt is set once the visit is signed; later changes are addenda with a reason.
+	FinalizedAt       *time.Time `json:"finalized_at,omitempty"`
+	FinalizedByUserID *uuid.UUID `json:"finalized_by_user_id,omitempty"`
 }
 
 // PatientVisitCreateRequest is used for recording a new patient visit.
@@ -40,6 +44,8 @@ type PatientVisitUpdateRequest struct {
 	Diagnosis    *string `json:"diagnosis,omitempty" validate:"omitempty"`
 	Prescription *string `json:"prescription,omitempty" validate:"omitempty"`
 	Notes        *string `json:"notes,omitempty" validate:"omitempty"`
+	// AmendmentReason is required once the visit is finalized, and recorded with the change.
+	AmendmentReason *string `json:"amendment_reason,omitempty" validate:"omitempty,min=3,max=1000"`
 }
 
 // ParsedPatientVisitRequest is an intermediate struct for services after parsing dates
@@ -52,4 +58,5 @@ type ParsedPatientVisitRequest struct {
 	Diagnosis    *string
 	Prescription *string
 	Notes        *string
+	AmendmentReason *string // Update only
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VisitRevisionKind is what a change to a visit was.
+type VisitRevisionKind string
+
+const (
+	VisitRevisionCreated  VisitRevisionKind = "created"  // The visit was recorded
+	VisitRevisionEdit     VisitRevisionKind = "edit"     // Changed before it was finalized
+	VisitRevisionFinalize VisitRevisionKind = "finalize" // Signed; nothing changed
+	VisitRevisionAddendum VisitRevisionKind = "addendum" // Changed after it was finalized, with a reason
+)
+
+// VisitHistoryEntry is one version of a visit: who produced it, when, and how it differs
+// from the version before.
+type VisitHistoryEntry struct {
+	Version      int32                  `json:"version"`
+	Kind         VisitRevisionKind      `json:"kind"`
+	AuthorUserID uuid.UUID              `json:"author_user_id"`
+	At           time.Time              `json:"at"`
+	Reason       *string                `json:"reason,omitempty"`
+	Changes      map[string]FieldChange `json:"changes"`
+}
+
+// VisitHistory is the timeline of a visit, oldest version first.
+type VisitHistory struct {
+	VisitID        uuid.UUID           `json:"visit_id"`
+	PatientID      uuid.UUID           `json:"patient_id"`
+	CurrentVersion int32               `json:"current_version"`
+	FinalizedAt    *time.Time          `json:"finalized_at,omitempty"`
+	Entries        []VisitHistoryEntry `json:"entries"`
+}
@@ -0,0 +1,38 @@
+// Package notify sends messages to patients outside the application.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// SMS is a text message to one phone number.
+type SMS struct {
+	To   string
+	Body string
+}
+
+// SMSSender is implemented by SMS provider integrations. It returns the provider's
+// message reference.
+type SMSSender interface {
+	SendSMS(ctx context.Context, msg SMS) (string, error)
+}
+
+// LogSender is an SMSSender for local development: it logs that a message was sent,
+// without its content, and never fails.
+type LogSender struct {
+	seq    atomic.Int64
+	logger *slog.Logger
+}
+
+func NewLogSender(logger *slog.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) SendSMS(ctx context.Context, msg SMS) (string, error) {
+	ref := fmt.Sprintf("LOG-%06d", s.seq.Add(1))
+	s.logger.InfoContext(ctx, "SMS sent", "ref", ref, "characters", len(msg.Body))
+	return ref, nil
+}
@@ -0,0 +1,79 @@
+// Package pharmacy holds the stock rules for the pharmacy: which batches a dispense draws
+// from and when a batch counts as expired. Quantities are whole dispensing units.
+package pharmacy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInsufficientStock is returned when unexpired stock cannot cover a request.
+var ErrInsufficientStock = errors.New("insufficient unexpired stock")
+
+// BatchStock is the quantity of one batch available at a location.
+type BatchStock struct {
+	BatchID    uuid.UUID
+	ExpiryDate time.Time
+	Quantity   int
+}
+
+// Allocation is the quantity to take from one batch.
+type Allocation struct {
+	BatchID  uuid.UUID
+	Quantity int
+}
+
+// IsExpired reports whether a batch expiring on expiry may no longer be used on today.
+// The expiry date itself is the last usable day.
+func IsExpired(expiry, today time.Time) bool {
+	return dateOf(expiry).Before(dateOf(today))
+}
+
+// AllocateFEFO takes quantity from the batches in stock, first-expiry-first-out.
+// Expired batches are skipped; batches with the same expiry keep their input order.
+func AllocateFEFO(stock []BatchStock, quantity int, today time.Time) ([]Allocation, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive, got %d", quantity)
+	}
+	usable := make([]BatchStock, 0, len(stock))
+	available := 0
+	for _, b := range stock {
+		if b.Quantity > 0 && !IsExpired(b.ExpiryDate, today) {
+			usable = append(usable, b)
+			available += b.Quantity
+		}
+	}
+	if available < quantity {
+		return nil, fmt.Errorf("%w: requested %d, available %d", ErrInsufficientStock, quantity, available)
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		return dateOf(usable[i].ExpiryDate).Before(dateOf(usable[j].ExpiryDate))
+	})
+
+	allocations := make([]Allocation, 0, 1)
+	remaining := quantity
+	for _, b := range usable {
+		take := min(b.Quantity, remaining)
+		allocations = append(allocations, Allocation{BatchID: b.BatchID, Quantity: take})
+		remaining -= take
+		if remaining == 0 {
+			break
+		}
+	}
+	return allocations, nil
+}
+
+// NeedsReorder reports whether on-hand stock has fallen to the reorder level.
+// A reorder level of zero disables the check.
+func NeedsReorder(onHand int64, reorderLevel int) bool {
+	return reorderLevel > 0 && onHand <= int64(reorderLevel)
+}
+
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
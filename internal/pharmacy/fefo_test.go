@@ -0,0 +1,57 @@
+package pharmacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAllocateFEFO(t *testing.T) {
+	today := day(2026, time.March, 10)
+	late, early, expired := uuid.New(), uuid.New(), uuid.New()
+	stock := []BatchStock{
+		{BatchID: late, ExpiryDate: day(2027, time.January, 1), Quantity: 50},
+		{BatchID: expired, ExpiryDate: day(2026, time.March, 9), Quantity: 100},
+		{BatchID: early, ExpiryDate: day(2026, time.June, 30), Quantity: 20},
+	}
+
+	got, err := AllocateFEFO(stock, 30, today)
+	require.NoError(t, err)
+	assert.Equal(t, []Allocation{{BatchID: early, Quantity: 20}, {BatchID: late, Quantity: 10}}, got)
+
+	got, err = AllocateFEFO(stock, 5, today)
+	require.NoError(t, err)
+	assert.Equal(t, []Allocation{{BatchID: early, Quantity: 5}}, got)
+}
+
+func TestAllocateFEFOInsufficientStock(t *testing.T) {
+	today := day(2026, time.March, 10)
+	stock := []BatchStock{
+		{BatchID: uuid.New(), ExpiryDate: day(2026, time.March, 1), Quantity: 100},
+		{BatchID: uuid.New(), ExpiryDate: day(2026, time.December, 1), Quantity: 10},
+	}
+	_, err := AllocateFEFO(stock, 11, today)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	_, err = AllocateFEFO(stock, 0, today)
+	assert.Error(t, err)
+}
+
+func TestIsExpired(t *testing.T) {
+	expiry := day(2026, time.March, 10)
+	assert.False(t, IsExpired(expiry, time.Date(2026, time.March, 10, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, IsExpired(expiry, day(2026, time.March, 11)))
+}
+
+func TestNeedsReorder(t *testing.T) {
+	assert.True(t, NeedsReorder(10, 10))
+	assert.False(t, NeedsReorder(11, 10))
+	assert.False(t, NeedsReorder(0, 0))
+}
@@ -0,0 +1,93 @@
+// Package pseudonym decides how a patient's direct identifiers are replaced when they ask
+// for their data to be erased. The patient row and its clinical records stay linked under
+// the same ID, so statistics and records kept by law are unaffected. The name, contact
+// details, address and insurance member and group numbers are replaced or cleared, and
+// the date of birth keeps only its year. Free text such as medical history, diagnoses and
+// lab notes is kept as written.
+package pseudonym
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// FirstName replaces the first name of every pseudonymized patient.
+const FirstName = "Pseudonymized"
+
+// tokenBytes of randomness make a token. Tokens are not derived from the patient, so
+// they cannot be reversed.
+const tokenBytes = 10
+
+// TokenPlaceholder stands in for the token in a preview; the real one is drawn on approval.
+const TokenPlaceholder = "P-<random token>"
+
+// Retained lists what pseudonymizing keeps, as shown in a preview.
+var Retained = []string{
+	"id",
+	"gender",
+	"medical_history",
+	"visits",
+	"lab_results",
+	"charges_and_invoices",
+	"insurance_policies",
+	"consents",
+	"audit_trail",
+}
+
+// Identifiers are the replacement values written to the patient row. Contact details and
+// the address are cleared. The token also replaces the member ID of each insurance policy.
+type Identifiers struct {
+	FirstName   string
+	LastName    string
+	DateOfBirth time.Time
+}
+
+// NewToken returns a random token such as "P-7KQ2M9XD4RJ5TW3A".
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return "P-" + base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// Replace returns the identifiers of a patient born on dob under the given token.
+func Replace(dob time.Time, token string) Identifiers {
+	return Identifiers{FirstName: FirstName, LastName: token, DateOfBirth: TruncateDOB(dob)}
+}
+
+// TruncateDOB keeps only the year of birth, as 1 January of that year.
+func TruncateDOB(dob time.Time) time.Time {
+	return time.Date(dob.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Preview lists what pseudonymizing p would change.
+func Preview(p model.Patient) model.PseudonymizationPreview {
+	ids := Replace(p.DateOfBirth, TokenPlaceholder)
+	dob := p.DateOfBirth.Format("2006-01-02")
+	newDOB := ids.DateOfBirth.Format("2006-01-02")
+	changes := []model.PseudonymizationChange{
+		{Field: "first_name", Current: &p.FirstName, Replacement: &ids.FirstName},
+		{Field: "last_name", Current: &p.LastName, Replacement: &ids.LastName},
+		{Field: "date_of_birth", Current: &dob, Replacement: &newDOB},
+		{Field: "contact_phone", Current: p.ContactPhone},
+		{Field: "contact_email", Current: p.ContactEmail},
+		{Field: "address", Current: p.Address},
+		// A patient may hold several policies, so their current values are not shown.
+		{Field: "insurance_member_id", Replacement: &ids.LastName},
+		{Field: "insurance_group_number"},
+	}
+	return model.PseudonymizationPreview{PatientID: p.ID, Changes: changes, Retained: Retained}
+}
+
+// ChangedFields are the fields pseudonymizing writes, for the audit trail.
+func ChangedFields() []string {
+	return []string{
+		"first_name", "last_name", "date_of_birth", "contact_phone", "contact_email", "address",
+		"insurance_member_id", "insurance_group_number",
+	}
+}
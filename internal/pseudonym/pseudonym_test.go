@@ -0,0 +1,64 @@
+package pseudonym
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenIsRandom(t *testing.T) {
+	a, err := NewToken()
+	require.NoError(t, err)
+	b, err := NewToken()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(a, "P-"))
+	assert.Len(t, a, 18)
+	assert.NotEqual(t, a, b)
+}
+
+func TestReplaceTruncatesDOBToYear(t *testing.T) {
+	ids := Replace(time.Date(1984, time.September, 17, 0, 0, 0, 0, time.UTC), "P-TOKEN")
+	assert.Equal(t, FirstName, ids.FirstName)
+	assert.Equal(t, "P-TOKEN", ids.LastName)
+	assert.Equal(t, time.Date(1984, time.January, 1, 0, 0, 0, 0, time.UTC), ids.DateOfBirth)
+}
+
+func TestPreview(t *testing.T) {
+	phone := "+919876543210"
+	p := model.Patient{
+		ID:           uuid.New(),
+		FirstName:    "Asha",
+		LastName:     "Rao",
+		DateOfBirth:  time.Date(1984, time.September, 17, 0, 0, 0, 0, time.UTC),
+		ContactPhone: &phone,
+	}
+	preview := Preview(p)
+	assert.Equal(t, p.ID, preview.PatientID)
+
+	byField := map[string]model.PseudonymizationChange{}
+	for _, c := range preview.Changes {
+		byField[c.Field] = c
+	}
+	assert.ElementsMatch(t, ChangedFields(), keys(byField))
+	assert.Equal(t, "Asha", *byField["first_name"].Current)
+	assert.Equal(t, TokenPlaceholder, *byField["last_name"].Replacement)
+	assert.Equal(t, "1984-01-01", *byField["date_of_birth"].Replacement)
+	assert.Equal(t, phone, *byField["contact_phone"].Current)
+	assert.Nil(t, byField["contact_phone"].Replacement, "contact details are cleared")
+	assert.Equal(t, TokenPlaceholder, *byField["insurance_member_id"].Replacement)
+	assert.Nil(t, byField["insurance_group_number"].Replacement)
+	assert.Contains(t, preview.Retained, "visits")
+}
+
+func keys(m map[string]model.PseudonymizationChange) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type alertRepo struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewAlertRepo(pool *pgxpool.Pool) AlertRepository {
+	return &alertRepo{pool: pool, queries: db.New(pool)}
+}
+
+func (r *alertRepo) CreateVisitObservation(ctx context.Context, arg db.CreateVisitObservationParams) (db.VisitObservation, error) {
+	return r.queries.CreateVisitObservation(ctx, arg)
+}
+
+func (r *alertRepo) ListVisitObservations(ctx context.Context, visitID pgtype.UUID) ([]db.VisitObservation, error) {
+	return r.queries.ListVisitObservations(ctx, visitID)
+}
+
+func (r *alertRepo) CreateAlertRule(ctx context.Context, arg db.CreateAlertRuleParams) (db.AlertRule, error) {
+	return r.queries.CreateAlertRule(ctx, arg)
+}
+
+func (r *alertRepo) GetAlertRuleByID(ctx context.Context, id pgtype.UUID) (db.AlertRule, error) {
+	return r.queries.GetAlertRuleByID(ctx, id)
+}
+
+func (r *alertRepo) ListActiveAlertRules(ctx context.Context) ([]db.AlertRule, error) {
+	return r.queries.ListActiveAlertRules(ctx)
+}
+
+func (r *alertRepo) ListActiveAlertRulesByCode(ctx context.Context, observationCode string) ([]db.AlertRule, error) {
+	return r.queries.ListActiveAlertRulesByCode(ctx, observationCode)
+}
+
+func (r *alertRepo) DeactivateAlertRule(ctx context.Context, id pgtype.UUID) (db.AlertRule, error) {
+	return r.queries.DeactivateAlertRule(ctx, id)
+}
+
+func (r *alertRepo) CreateAlert(ctx context.Context, arg db.CreateAlertParams) (db.Alert, error) {
+	return r.queries.CreateAlert(ctx, arg)
+}
+
+func (r *alertRepo) GetAlertByID(ctx context.Context, id pgtype.UUID) (db.Alert, error) {
+	return r.queries.GetAlertByID(ctx, id)
+}
+
+func (r *alertRepo) ListAlertsForDoctor(ctx context.Context, arg db.ListAlertsForDoctorParams) ([]db.Alert, error) {
+	return r.queries.ListAlertsForDoctor(ctx, arg)
+}
+
+func (r *alertRepo) CountAlertsForDoctor(ctx context.Context, arg db.CountAlertsForDoctorParams) (int64, error) {
+	return r.queries.CountAlertsForDoctor(ctx, arg)
+}
+
+func (r *alertRepo) AcknowledgeAlert(ctx context.Context, arg db.AcknowledgeAlertParams) (db.Alert, error) {
+	return r.queries.AcknowledgeAlert(ctx, arg)
+}
+
+func (r *alertRepo) LockDueAlerts(ctx context.Context, arg db.LockDueAlertsParams) ([]db.Alert, error) {
+	return r.queries.LockDueAlerts(ctx, arg)
+}
+
+func (r *alertRepo) EscalateAlert(ctx context.Context, arg db.EscalateAlertParams) (db.Alert, error) {
+	return r.queries.EscalateAlert(ctx, arg)
+}
+
+func (r *alertRepo) PostponeAlertEscalation(ctx context.Context, arg db.PostponeAlertEscalationParams) (db.Alert, error) {
+	return r.queries.PostponeAlertEscalation(ctx, arg)
+}
+
+func (r *alertRepo) CreateAlertEscalation(ctx context.Context, arg db.CreateAlertEscalationParams) (db.AlertEscalation, error) {
+	return r.queries.CreateAlertEscalation(ctx, arg)
+}
+
+func (r *alertRepo) PickEscalationDoctor(ctx context.Context, excludeDoctorID pgtype.UUID) (pgtype.UUID, error) {
+	return r.queries.PickEscalationDoctor(ctx, excludeDoctorID)
+}
+
+func (r *alertRepo) WithinAdvisoryLock(ctx context.Context, key int64, fn func(AlertRepository) error) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after Commit
+
+	qtx := r.queries.WithTx(tx)
+	acquired, err := qtx.TryAdvisoryXactLock(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("acquire advisory lock: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	if err := fn(&alertRepo{pool: r.pool, queries: qtx}); err != nil {
+		return true, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return true, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
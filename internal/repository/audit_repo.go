@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type auditRepo struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	inTx    bool // Bound to a TxManager transaction
+}
+
+func NewAuditRepo(pool *pgxpool.Pool) AuditRepository {
+	return &auditRepo{pool: pool, queries: db.New(pool)}
+}
+
+func (r *auditRepo) GetAuditChainHead(ctx context.Context) (db.GetAuditChainHeadRow, error) {
+	return r.queries.GetAuditChainHead(ctx)
+}
+
+func (r *auditRepo) CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+	return r.queries.CreateAuditEvent(ctx, arg)
+}
+
+func (r *auditRepo) ListAuditEvents(ctx context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	return r.queries.ListAuditEvents(ctx, arg)
+}
+
+func (r *auditRepo) CountAuditEvents(ctx context.Context, arg db.CountAuditEventsParams) (int64, error) {
+	return r.queries.CountAuditEvents(ctx, arg)
+}
+
+func (r *auditRepo) ListAuditEventsAfterSeq(ctx context.Context, arg db.ListAuditEventsAfterSeqParams) ([]db.AuditEvent, error) {
+	return r.queries.ListAuditEventsAfterSeq(ctx, arg)
+}
+
+func (r *auditRepo) ListPatientAccessLog(ctx context.Context, arg db.ListPatientAccessLogParams) ([]db.ListPatientAccessLogRow, error) {
+	return r.queries.ListPatientAccessLog(ctx, arg)
+}
+
+func (r *auditRepo) ListPatientAccessLogBeforeSeq(ctx context.Context, arg db.ListPatientAccessLogBeforeSeqParams) ([]db.ListPatientAccessLogBeforeSeqRow, error) {
+	return r.queries.ListPatientAccessLogBeforeSeq(ctx, arg)
+}
+
+func (r *auditRepo) CountPatientAccessLog(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	return r.queries.CountPatientAccessLog(ctx, patientID)
+}
+
+func (r *auditRepo) WithinLock(ctx context.Context, key int64, fn func(AuditRepository) error) error {
+	if r.inTx {
+		if err := r.queries.AcquireAdvisoryXactLock(ctx, key); err != nil {
+			return fmt.Errorf("acquire advisory lock: %w", err)
+		}
+		return fn(r)
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after Commit
+
+	qtx := r.queries.WithTx(tx)
+	if err := qtx.AcquireAdvisoryXactLock(ctx, key); err != nil {
+		return fmt.Errorf("acquire advisory lock: %w", err)
+	}
+	if err := fn(&auditRepo{pool: r.pool, queries: qtx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
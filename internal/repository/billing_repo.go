@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type billingRepo struct {
+	queries *db.Queries
+}
+
+func NewBillingRepo(queries *db.Queries) BillingRepository {
+	return &billingRepo{queries: queries}
+}
+
+func (r *billingRepo) CreateTaxCategory(ctx context.Context, arg db.CreateTaxCategoryParams) (db.TaxCategory, error) {
+	return r.queries.CreateTaxCategory(ctx, arg)
+}
+
+func (r *billingRepo) ListTaxCategories(ctx context.Context) ([]db.TaxCategory, error) {
+	return r.queries.ListTaxCategories(ctx)
+}
+
+func (r *billingRepo) CreateChargeItem(ctx context.Context, arg db.CreateChargeItemParams) (db.ChargeItem, error) {
+	return r.queries.CreateChargeItem(ctx, arg)
+}
+
+func (r *billingRepo) GetChargeItemByCode(ctx context.Context, code string) (db.GetChargeItemByCodeRow, error) {
+	return r.queries.GetChargeItemByCode(ctx, code)
+}
+
+func (r *billingRepo) ListChargeItems(ctx context.Context, arg db.ListChargeItemsParams) ([]db.ListChargeItemsRow, error) {
+	return r.queries.ListChargeItems(ctx, arg)
+}
+
+func (r *billingRepo) CountChargeItems(ctx context.Context) (int64, error) {
+	return r.queries.CountChargeItems(ctx)
+}
+
+func (r *billingRepo) CreateCharge(ctx context.Context, arg db.CreateChargeParams) (db.Charge, error) {
+	return r.queries.CreateCharge(ctx, arg)
+}
+
+func (r *billingRepo) ListChargesByPatientID(ctx context.Context, arg db.ListChargesByPatientIDParams) ([]db.Charge, error) {
+	return r.queries.ListChargesByPatientID(ctx, arg)
+}
+
+func (r *billingRepo) CountChargesByPatientID(ctx context.Context, arg db.CountChargesByPatientIDParams) (int64, error) {
+	return r.queries.CountChargesByPatientID(ctx, arg)
+}
+
+func (r *billingRepo) ListUnbilledChargesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.Charge, error) {
+	return r.queries.ListUnbilledChargesByPatientID(ctx, patientID)
+}
+
+func (r *billingRepo) LockUnbilledCharges(ctx context.Context, arg db.LockUnbilledChargesParams) ([]db.Charge, error) {
+	return r.queries.LockUnbilledCharges(ctx, arg)
+}
+
+func (r *billingRepo) AssignChargeToInvoice(ctx context.Context, arg db.AssignChargeToInvoiceParams) error {
+	return r.queries.AssignChargeToInvoice(ctx, arg)
+}
+
+func (r *billingRepo) NextInvoiceSequence(ctx context.Context, fiscalYear int32) (int32, error) {
+	return r.queries.NextInvoiceSequence(ctx, fiscalYear)
+}
+
+func (r *billingRepo) CreateInvoice(ctx context.Context, arg db.CreateInvoiceParams) (db.Invoice, error) {
+	return r.queries.CreateInvoice(ctx, arg)
+}
+
+func (r *billingRepo) CreateInvoiceLine(ctx context.Context, arg db.CreateInvoiceLineParams) (db.InvoiceLine, error) {
+	return r.queries.CreateInvoiceLine(ctx, arg)
+}
+
+func (r *billingRepo) GetInvoiceByID(ctx context.Context, id pgtype.UUID) (db.Invoice, error) {
+	return r.queries.GetInvoiceByID(ctx, id)
+}
+
+func (r *billingRepo) LockInvoice(ctx context.Context, id pgtype.UUID) (db.Invoice, error) {
+	return r.queries.LockInvoice(ctx, id)
+}
+
+func (r *billingRepo) ListInvoicesByPatientID(ctx context.Context, arg db.ListInvoicesByPatientIDParams) ([]db.Invoice, error) {
+	return r.queries.ListInvoicesByPatientID(ctx, arg)
+}
+
+func (r *billingRepo) CountInvoicesByPatientID(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	return r.queries.CountInvoicesByPatientID(ctx, patientID)
+}
+
+func (r *billingRepo) ListInvoiceLines(ctx context.Context, invoiceID pgtype.UUID) ([]db.InvoiceLine, error) {
+	return r.queries.ListInvoiceLines(ctx, invoiceID)
+}
+
+func (r *billingRepo) UpdateInvoicePayment(ctx context.Context, arg db.UpdateInvoicePaymentParams) (db.Invoice, error) {
+	return r.queries.UpdateInvoicePayment(ctx, arg)
+}
+
+func (r *billingRepo) GetPatientInvoiceTotals(ctx context.Context, patientID pgtype.UUID) (db.GetPatientInvoiceTotalsRow, error) {
+	return r.queries.GetPatientInvoiceTotals(ctx, patientID)
+}
+
+func (r *billingRepo) CreatePayment(ctx context.Context, arg db.CreatePaymentParams) (db.Payment, error) {
+	return r.queries.CreatePayment(ctx, arg)
+}
+
+func (r *billingRepo) LockPayment(ctx context.Context, id pgtype.UUID) (db.Payment, error) {
+	return r.queries.LockPayment(ctx, id)
+}
+
+func (r *billingRepo) ListPaymentsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.Payment, error) {
+	return r.queries.ListPaymentsByInvoiceID(ctx, invoiceID)
+}
+
+func (r *billingRepo) CreateRefund(ctx context.Context, arg db.CreateRefundParams) (db.Refund, error) {
+	return r.queries.CreateRefund(ctx, arg)
+}
+
+func (r *billingRepo) SumRefundsByPaymentID(ctx context.Context, paymentID pgtype.UUID) (int64, error) {
+	return r.queries.SumRefundsByPaymentID(ctx, paymentID)
+}
+
+func (r *billingRepo) ListRefundsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.Refund, error) {
+	return r.queries.ListRefundsByInvoiceID(ctx, invoiceID)
+}
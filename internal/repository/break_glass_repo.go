@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type breakGlassRepo struct {
+	queries *db.Queries
+}
+
+func NewBreakGlassRepo(queries *db.Queries) BreakGlassRepository {
+	return &breakGlassRepo{queries: queries}
+}
+
+func (r *breakGlassRepo) CreateBreakGlassGrant(ctx context.Context, arg db.CreateBreakGlassGrantParams) (db.BreakGlassGrant, error) {
+	return r.queries.CreateBreakGlassGrant(ctx, arg)
+}
+
+func (r *breakGlassRepo) GetActiveBreakGlassGrant(ctx context.Context, arg db.GetActiveBreakGlassGrantParams) (db.BreakGlassGrant, error) {
+	return r.queries.GetActiveBreakGlassGrant(ctx, arg)
+}
+
+func (r *breakGlassRepo) ListBreakGlassGrantsForReview(ctx context.Context, arg db.ListBreakGlassGrantsForReviewParams) ([]db.ListBreakGlassGrantsForReviewRow, error) {
+	return r.queries.ListBreakGlassGrantsForReview(ctx, arg)
+}
+
+func (r *breakGlassRepo) ListBreakGlassAccesses(ctx context.Context, arg db.ListBreakGlassAccessesParams) ([]db.ListBreakGlassAccessesRow, error) {
+	return r.queries.ListBreakGlassAccesses(ctx, arg)
+}
+
+func (r *breakGlassRepo) CreateBreakGlassReport(ctx context.Context, arg db.CreateBreakGlassReportParams) (db.BreakGlassReport, error) {
+	return r.queries.CreateBreakGlassReport(ctx, arg)
+}
+
+func (r *breakGlassRepo) GetBreakGlassReport(ctx context.Context, reportDate pgtype.Date) (db.BreakGlassReport, error) {
+	return r.queries.GetBreakGlassReport(ctx, reportDate)
+}
+
+func (r *breakGlassRepo) ListBreakGlassReports(ctx context.Context, arg db.ListBreakGlassReportsParams) ([]db.ListBreakGlassReportsRow, error) {
+	return r.queries.ListBreakGlassReports(ctx, arg)
+}
+
+func (r *breakGlassRepo) CountBreakGlassReports(ctx context.Context) (int64, error) {
+	return r.queries.CountBreakGlassReports(ctx)
+}
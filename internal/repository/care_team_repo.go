@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type careTeamRepo struct {
+	queries *db.Queries
+}
+
+func NewCareTeamRepo(queries *db.Queries) CareTeamRepository {
+	return &careTeamRepo{queries: queries}
+}
+
+func (r *careTeamRepo) AddCareTeamMember(ctx context.Context, arg db.AddCareTeamMemberParams) (db.CareTeamMember, error) {
+	return r.queries.AddCareTeamMember(ctx, arg)
+}
+
+func (r *careTeamRepo) RemoveCareTeamMember(ctx context.Context, arg db.RemoveCareTeamMemberParams) (db.CareTeamMember, error) {
+	return r.queries.RemoveCareTeamMember(ctx, arg)
+}
+
+func (r *careTeamRepo) ListCareTeamMembers(ctx context.Context, patientID pgtype.UUID) ([]db.ListCareTeamMembersRow, error) {
+	return r.queries.ListCareTeamMembers(ctx, patientID)
+}
+
+func (r *careTeamRepo) IsInCareOf(ctx context.Context, arg db.IsInCareOfParams) (bool, error) {
+	return r.queries.IsInCareOf(ctx, arg)
+}
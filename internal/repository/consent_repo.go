@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type consentRepo struct {
+	queries *db.Queries
+}
+
+func NewConsentRepo(queries *db.Queries) ConsentRepository {
+	return &consentRepo{queries: queries}
+}
+
+func (r *consentRepo) CreateConsentRecord(ctx context.Context, arg db.CreateConsentRecordParams) (db.ConsentRecord, error) {
+	return r.queries.CreateConsentRecord(ctx, arg)
+}
+
+func (r *consentRepo) GetConsentRecordByID(ctx context.Context, arg db.GetConsentRecordByIDParams) (db.ConsentRecord, error) {
+	return r.queries.GetConsentRecordByID(ctx, arg)
+}
+
+func (r *consentRepo) GetLatestConsentRecord(ctx context.Context, arg db.GetLatestConsentRecordParams) (db.ConsentRecord, error) {
+	return r.queries.GetLatestConsentRecord(ctx, arg)
+}
+
+func (r *consentRepo) ListConsentRecordsByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.ConsentRecord, error) {
+	return r.queries.ListConsentRecordsByPatientID(ctx, patientID)
+}
+
+func (r *consentRepo) WithdrawConsentRecord(ctx context.Context, arg db.WithdrawConsentRecordParams) (db.ConsentRecord, error) {
+	return r.queries.WithdrawConsentRecord(ctx, arg)
+}
+
+func (r *consentRepo) WithdrawActiveConsent(ctx context.Context, arg db.WithdrawActiveConsentParams) ([]db.ConsentRecord, error) {
+	return r.queries.WithdrawActiveConsent(ctx, arg)
+}
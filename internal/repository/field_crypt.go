@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Encrypted columns. The names are bound into each ciphertext, so they must not change.
+const (
+	fieldPatientContactPhone   = "patients.contact_phone"
+	fieldPatientContactEmail   = "patients.contact_email"
+	fieldPatientMedicalHistory = "patients.medical_history"
+	fieldVisitSymptoms         = "patient_visits.symptoms"
+	fieldVisitDiagnosis        = "patient_visits.diagnosis"
+	fieldVisitPrescription     = "patient_visits.prescription"
+	fieldVisitNotes            = "patient_visits.notes"
+	fieldExportArchive         = "patient_export_archives.data"
+)
+
+func encryptText(ctx context.Context, c *fieldcrypt.Cipher, field string, t pgtype.Text) (pgtype.Text, error) {
+	if !t.Valid {
+		return t, nil
+	}
+	sealed, err := c.Encrypt(ctx, field, t.String)
+	if err != nil {
+		return t, fmt.Errorf("encrypt %s: %w", field, err)
+	}
+	return pgtype.Text{String: sealed, Valid: true}, nil
+}
+
+func decryptText(ctx context.Context, c *fieldcrypt.Cipher, field string, t *pgtype.Text) error {
+	if !t.Valid {
+		return nil
+	}
+	plain, err := c.Decrypt(ctx, field, t.String)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field, err)
+	}
+	t.String = plain
+	return nil
+}
+
+// blindIndex returns the lookup hash of a plaintext value, or NULL for NULL.
+func blindIndex(c *fieldcrypt.Cipher, kind fieldcrypt.IndexKind, t pgtype.Text) pgtype.Text {
+	if !t.Valid {
+		return pgtype.Text{}
+	}
+	index := c.BlindIndex(kind, t.String)
+	return pgtype.Text{String: index, Valid: index != ""}
+}
+
+func decryptPatient(ctx context.Context, c *fieldcrypt.Cipher, p *db.Patient) error {
+	if err := decryptText(ctx, c, fieldPatientContactPhone, &p.ContactPhone); err != nil {
+		return err
+	}
+	if err := decryptText(ctx, c, fieldPatientContactEmail, &p.ContactEmail); err != nil {
+		return err
+	}
+	return decryptText(ctx, c, fieldPatientMedicalHistory, &p.MedicalHistory)
+}
+
+func decryptPatients(ctx context.Context, c *fieldcrypt.Cipher, patients []db.Patient) error {
+	for i := range patients {
+		if err := decryptPatient(ctx, c, &patients[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptVisitFields decrypts the clinical text columns shared by every visit row type.
+func decryptVisitFields(ctx context.Context, c *fieldcrypt.Cipher, symptoms, diagnosis, prescription, notes *pgtype.Text) error {
+	if err := decryptText(ctx, c, fieldVisitSymptoms, symptoms); err != nil {
+		return err
+	}
+	if err := decryptText(ctx, c, fieldVisitDiagnosis, diagnosis); err != nil {
+		return err
+	}
+	if err := decryptText(ctx, c, fieldVisitPrescription, prescription); err != nil {
+		return err
+	}
+	return decryptText(ctx, c, fieldVisitNotes, notes)
+}
+
+func encryptVisitFields(ctx context.Context, c *fieldcrypt.Cipher, symptoms, diagnosis, prescription, notes *pgtype.Text) error {
+	var err error
+	if *symptoms, err = encryptText(ctx, c, fieldVisitSymptoms, *symptoms); err != nil {
+		return err
+	}
+	if *diagnosis, err = encryptText(ctx, c, fieldVisitDiagnosis, *diagnosis); err != nil {
+		return err
+	}
+	if *prescription, err = encryptText(ctx, c, fieldVisitPrescription, *prescription); err != nil {
+		return err
+	}
+	*notes, err = encryptText(ctx, c, fieldVisitNotes, *notes)
+	return err
+}
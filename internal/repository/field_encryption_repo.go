@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type fieldEncryptionRepo struct {
+	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
+}
+
+func NewFieldEncryptionRepo(queries *db.Queries, cipher *fieldcrypt.Cipher) FieldEncryptionRepository {
+	return &fieldEncryptionRepo{queries: queries, cipher: cipher}
+}
+
+// stale reports whether any of the values is plaintext or sealed under an old master key.
+func (r *fieldEncryptionRepo) stale(values ...pgtype.Text) bool {
+	for _, v := range values {
+		if v.Valid && r.cipher.NeedsReencryption(v.String) {
+			return true
+		}
+	}
+	return false
+}
+
+// reseal decrypts t (plaintext passes through) and encrypts it under the active key.
+func (r *fieldEncryptionRepo) reseal(ctx context.Context, field string, t *pgtype.Text) error {
+	if err := decryptText(ctx, r.cipher, field, t); err != nil {
+		return err
+	}
+	sealed, err := encryptText(ctx, r.cipher, field, *t)
+	if err != nil {
+		return err
+	}
+	*t = sealed
+	return nil
+}
+
+// ReencryptPatients rewrites the patients after afterID whose contact details or medical
+// history are not sealed under the active key, or whose blind indexes are missing.
+func (r *fieldEncryptionRepo) ReencryptPatients(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error) {
+	rows, err := r.queries.ListPatientsForReencryption(ctx, db.ListPatientsForReencryptionParams{AfterID: afterID, RowLimit: limit})
+	if err != nil {
+		return ReencryptionBatch{}, err
+	}
+	batch := ReencryptionBatch{LastID: afterID, Scanned: len(rows)}
+	for _, row := range rows {
+		batch.LastID = row.ID
+		phone, email := row.ContactPhone, row.ContactEmail
+		if err := decryptText(ctx, r.cipher, fieldPatientContactPhone, &phone); err != nil {
+			return batch, err
+		}
+		if err := decryptText(ctx, r.cipher, fieldPatientContactEmail, &email); err != nil {
+			return batch, err
+		}
+		phoneIndex := blindIndex(r.cipher, fieldcrypt.IndexPhone, phone)
+		emailIndex := blindIndex(r.cipher, fieldcrypt.IndexEmail, email)
+		if !r.stale(row.ContactPhone, row.ContactEmail, row.MedicalHistory) && phoneIndex == row.ContactPhoneBidx && emailIndex == row.ContactEmailBidx {
+			continue
+		}
+
+		arg := db.ReencryptPatientParams{
+			ContactPhone:     row.ContactPhone,
+			ContactEmail:     row.ContactEmail,
+			MedicalHistory:   row.MedicalHistory,
+			ContactPhoneBidx: phoneIndex,
+			ContactEmailBidx: emailIndex,
+			ID:               row.ID,
+			ReadUpdatedAt:    row.UpdatedAt,
+		}
+		if err := r.reseal(ctx, fieldPatientContactPhone, &arg.ContactPhone); err != nil {
+			return batch, err
+		}
+		if err := r.reseal(ctx, fieldPatientContactEmail, &arg.ContactEmail); err != nil {
+			return batch, err
+		}
+		if err := r.reseal(ctx, fieldPatientMedicalHistory, &arg.MedicalHistory); err != nil {
+			return batch, err
+		}
+		n, err := r.queries.ReencryptPatient(ctx, arg)
+		if err != nil {
+			return batch, err
+		}
+		if n == 0 {
+			batch.Skipped++
+			continue
+		}
+		batch.Rewritten++
+	}
+	return batch, nil
+}
+
+// ReencryptPatientVisits rewrites the visits after afterID whose clinical text is not
+// sealed under the active key.
+func (r *fieldEncryptionRepo) ReencryptPatientVisits(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error) {
+	rows, err := r.queries.ListPatientVisitsForReencryption(ctx, db.ListPatientVisitsForReencryptionParams{AfterID: afterID, RowLimit: limit})
+	if err != nil {
+		return ReencryptionBatch{}, err
+	}
+	batch := ReencryptionBatch{LastID: afterID, Scanned: len(rows)}
+	for _, row := range rows {
+		batch.LastID = row.ID
+		if !r.stale(row.Symptoms, row.Diagnosis, row.Prescription, row.Notes) {
+			continue
+		}
+
+		arg := db.ReencryptPatientVisitParams{
+			Symptoms:      row.Symptoms,
+			Diagnosis:     row.Diagnosis,
+			Prescription:  row.Prescription,
+			Notes:         row.Notes,
+			ID:            row.ID,
+			ReadUpdatedAt: row.UpdatedAt,
+		}
+		if err := decryptVisitFields(ctx, r.cipher, &arg.Symptoms, &arg.Diagnosis, &arg.Prescription, &arg.Notes); err != nil {
+			return batch, err
+		}
+		if err := encryptVisitFields(ctx, r.cipher, &arg.Symptoms, &arg.Diagnosis, &arg.Prescription, &arg.Notes); err != nil {
+			return batch, err
+		}
+		n, err := r.queries.ReencryptPatientVisit(ctx, arg)
+		if err != nil {
+			return batch, err
+		}
+		if n == 0 {
+			batch.Skipped++
+			continue
+		}
+		batch.Rewritten++
+	}
+	return batch, nil
+}
+
+// ReencryptPatientVisitRevisions rewrites the stored previous versions of visits after
+// afterID whose clinical text is not sealed under the active key.
+func (r *fieldEncryptionRepo) ReencryptPatientVisitRevisions(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error) {
+	rows, err := r.queries.ListPatientVisitRevisionsForReencryption(ctx, db.ListPatientVisitRevisionsForReencryptionParams{AfterID: afterID, RowLimit: limit})
+	if err != nil {
+		return ReencryptionBatch{}, err
+	}
+	batch := ReencryptionBatch{LastID: afterID, Scanned: len(rows)}
+	for _, row := range rows {
+		batch.LastID = row.ID
+		if !r.stale(row.PreviousSymptoms, row.PreviousDiagnosis, row.PreviousPrescription, row.PreviousNotes) {
+			continue
+		}
+
+		arg := db.ReencryptPatientVisitRevisionParams{
+			PreviousSymptoms:     row.PreviousSymptoms,
+			PreviousDiagnosis:    row.PreviousDiagnosis,
+			PreviousPrescription: row.PreviousPrescription,
+			PreviousNotes:        row.PreviousNotes,
+			ID:                   row.ID,
+			ReadSymptoms:         row.PreviousSymptoms,
+			ReadDiagnosis:        row.PreviousDiagnosis,
+			ReadPrescription:     row.PreviousPrescription,
+			ReadNotes:            row.PreviousNotes,
+		}
+		if err := decryptVisitFields(ctx, r.cipher, &arg.PreviousSymptoms, &arg.PreviousDiagnosis, &arg.PreviousPrescription, &arg.PreviousNotes); err != nil {
+			return batch, err
+		}
+		if err := encryptVisitFields(ctx, r.cipher, &arg.PreviousSymptoms, &arg.PreviousDiagnosis, &arg.PreviousPrescription, &arg.PreviousNotes); err != nil {
+			return batch, err
+		}
+		n, err := r.queries.ReencryptPatientVisitRevision(ctx, arg)
+		if err != nil {
+			return batch, err
+		}
+		if n == 0 {
+			batch.Skipped++
+			continue
+		}
+		batch.Rewritten++
+	}
+	return batch, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type insuranceRepo struct {
+	queries *db.Queries
+}
+
+func NewInsuranceRepo(queries *db.Queries) InsuranceRepository {
+	return &insuranceRepo{queries: queries}
+}
+
+func (r *insuranceRepo) CreateInsurancePolicy(ctx context.Context, arg db.CreateInsurancePolicyParams) (db.InsurancePolicy, error) {
+	return r.queries.CreateInsurancePolicy(ctx, arg)
+}
+
+func (r *insuranceRepo) GetInsurancePolicyByID(ctx context.Context, id pgtype.UUID) (db.InsurancePolicy, error) {
+	return r.queries.GetInsurancePolicyByID(ctx, id)
+}
+
+func (r *insuranceRepo) ListInsurancePoliciesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.InsurancePolicy, error) {
+	return r.queries.ListInsurancePoliciesByPatientID(ctx, patientID)
+}
+
+func (r *insuranceRepo) DeactivateInsurancePolicy(ctx context.Context, id pgtype.UUID) (db.InsurancePolicy, error) {
+	return r.queries.DeactivateInsurancePolicy(ctx, id)
+}
+
+func (r *insuranceRepo) UpdatePolicyEligibility(ctx context.Context, arg db.UpdatePolicyEligibilityParams) (db.InsurancePolicy, error) {
+	return r.queries.UpdatePolicyEligibility(ctx, arg)
+}
+
+func (r *insuranceRepo) CreateEligibilityCheck(ctx context.Context, arg db.CreateEligibilityCheckParams) (db.EligibilityCheck, error) {
+	return r.queries.CreateEligibilityCheck(ctx, arg)
+}
+
+func (r *insuranceRepo) CreateInsuranceClaim(ctx context.Context, arg db.CreateInsuranceClaimParams) (db.InsuranceClaim, error) {
+	return r.queries.CreateInsuranceClaim(ctx, arg)
+}
+
+func (r *insuranceRepo) GetInsuranceClaimByID(ctx context.Context, id pgtype.UUID) (db.InsuranceClaim, error) {
+	return r.queries.GetInsuranceClaimByID(ctx, id)
+}
+
+func (r *insuranceRepo) ListInsuranceClaimsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.InsuranceClaim, error) {
+	return r.queries.ListInsuranceClaimsByInvoiceID(ctx, invoiceID)
+}
+
+func (r *insuranceRepo) ListInsuranceClaimsByStatus(ctx context.Context, arg db.ListInsuranceClaimsByStatusParams) ([]db.InsuranceClaim, error) {
+	return r.queries.ListInsuranceClaimsByStatus(ctx, arg)
+}
+
+func (r *insuranceRepo) CountInsuranceClaimsByStatus(ctx context.Context, status db.ClaimStatus) (int64, error) {
+	return r.queries.CountInsuranceClaimsByStatus(ctx, status)
+}
+
+func (r *insuranceRepo) UpdateInsuranceClaimStatus(ctx context.Context, arg db.UpdateInsuranceClaimStatusParams) (db.InsuranceClaim, error) {
+	return r.queries.UpdateInsuranceClaimStatus(ctx, arg)
+}
+
+func (r *insuranceRepo) MarkInsuranceClaimSent(ctx context.Context, arg db.MarkInsuranceClaimSentParams) (db.InsuranceClaim, error) {
+	return r.queries.MarkInsuranceClaimSent(ctx, arg)
+}
+
+func (r *insuranceRepo) CreateClaimStatusHistory(ctx context.Context, arg db.CreateClaimStatusHistoryParams) (db.ClaimStatusHistory, error) {
+	return r.queries.CreateClaimStatusHistory(ctx, arg)
+}
+
+func (r *insuranceRepo) ListClaimStatusHistory(ctx context.Context, claimID pgtype.UUID) ([]db.ClaimStatusHistory, error) {
+	return r.queries.ListClaimStatusHistory(ctx, claimID)
+}
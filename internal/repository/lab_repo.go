@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type labRepo struct {
+	queries *db.Queries
+}
+
+func NewLabRepo(queries *db.Queries) LabRepository {
+	return &labRepo{queries: queries}
+}
+
+func (r *labRepo) CreateLabTest(ctx context.Context, arg db.CreateLabTestParams) (db.LabTest, error) {
+	return r.queries.CreateLabTest(ctx, arg)
+}
+
+func (r *labRepo) GetLabTestByID(ctx context.Context, id pgtype.UUID) (db.LabTest, error) {
+	return r.queries.GetLabTestByID(ctx, id)
+}
+
+func (r *labRepo) GetLabTestByCode(ctx context.Context, code string) (db.LabTest, error) {
+	return r.queries.GetLabTestByCode(ctx, code)
+}
+
+func (r *labRepo) ListLabTests(ctx context.Context, arg db.ListLabTestsParams) ([]db.LabTest, error) {
+	return r.queries.ListLabTests(ctx, arg)
+}
+
+func (r *labRepo) CountLabTests(ctx context.Context) (int64, error) {
+	return r.queries.CountLabTests(ctx)
+}
+
+func (r *labRepo) CreateLabAnalyte(ctx context.Context, arg db.CreateLabAnalyteParams) (db.LabAnalyte, error) {
+	return r.queries.CreateLabAnalyte(ctx, arg)
+}
+
+func (r *labRepo) ListLabAnalytesByTestID(ctx context.Context, testID pgtype.UUID) ([]db.LabAnalyte, error) {
+	return r.queries.ListLabAnalytesByTestID(ctx, testID)
+}
+
+func (r *labRepo) CreateLabOrder(ctx context.Context, arg db.CreateLabOrderParams) (db.LabOrder, error) {
+	return r.queries.CreateLabOrder(ctx, arg)
+}
+
+func (r *labRepo) GetLabOrderByID(ctx context.Context, id pgtype.UUID) (db.LabOrder, error) {
+	return r.queries.GetLabOrderByID(ctx, id)
+}
+
+func (r *labRepo) ListLabOrdersByVisitID(ctx context.Context, visitID pgtype.UUID) ([]db.LabOrder, error) {
+	return r.queries.ListLabOrdersByVisitID(ctx, visitID)
+}
+
+func (r *labRepo) ListLabOrdersByStatus(ctx context.Context, arg db.ListLabOrdersByStatusParams) ([]db.LabOrder, error) {
+	return r.queries.ListLabOrdersByStatus(ctx, arg)
+}
+
+func (r *labRepo) MarkLabOrderCollected(ctx context.Context, arg db.MarkLabOrderCollectedParams) (db.LabOrder, error) {
+	return r.queries.MarkLabOrderCollected(ctx, arg)
+}
+
+func (r *labRepo) MarkLabOrderResulted(ctx context.Context, arg db.MarkLabOrderResultedParams) (db.LabOrder, error) {
+	return r.queries.MarkLabOrderResulted(ctx, arg)
+}
+
+func (r *labRepo) MarkLabOrderVerified(ctx context.Context, arg db.MarkLabOrderVerifiedParams) (db.LabOrder, error) {
+	return r.queries.MarkLabOrderVerified(ctx, arg)
+}
+
+func (r *labRepo) UpsertLabResult(ctx context.Context, arg db.UpsertLabResultParams) (db.LabResult, error) {
+	return r.queries.UpsertLabResult(ctx, arg)
+}
+
+func (r *labRepo) ListLabResultsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]db.ListLabResultsByOrderIDRow, error) {
+	return r.queries.ListLabResultsByOrderID(ctx, orderID)
+}
+
+func (r *labRepo) ListCumulativeLabResultsByPatientID(ctx context.Context, arg db.ListCumulativeLabResultsByPatientIDParams) ([]db.ListCumulativeLabResultsByPatientIDRow, error) {
+	return r.queries.ListCumulativeLabResultsByPatientID(ctx, arg)
+}
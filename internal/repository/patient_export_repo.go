@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type patientExportRepo struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
+}
+
+func NewPatientExportRepo(pool *pgxpool.Pool, cipher *fieldcrypt.Cipher) PatientExportRepository {
+	return &patientExportRepo{pool: pool, queries: db.New(pool), cipher: cipher}
+}
+
+// archiveField binds an archive to its job, so an archive copied to another job does not decrypt.
+func archiveField(jobID pgtype.UUID) string {
+	return fieldExportArchive + "/" + uuid.UUID(jobID.Bytes).String()
+}
+
+func (r *patientExportRepo) CreatePatientExportJob(ctx context.Context, arg db.CreatePatientExportJobParams) (db.PatientExportJob, error) {
+	return r.queries.CreatePatientExportJob(ctx, arg)
+}
+
+func (r *patientExportRepo) GetPatientExportJob(ctx context.Context, arg db.GetPatientExportJobParams) (db.PatientExportJob, error) {
+	return r.queries.GetPatientExportJob(ctx, arg)
+}
+
+func (r *patientExportRepo) GetOpenPatientExportJob(ctx context.Context, patientID pgtype.UUID) (db.PatientExportJob, error) {
+	return r.queries.GetOpenPatientExportJob(ctx, patientID)
+}
+
+func (r *patientExportRepo) ListPatientExportJobs(ctx context.Context, arg db.ListPatientExportJobsParams) ([]db.PatientExportJob, error) {
+	return r.queries.ListPatientExportJobs(ctx, arg)
+}
+
+func (r *patientExportRepo) CountPatientExportJobs(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	return r.queries.CountPatientExportJobs(ctx, patientID)
+}
+
+func (r *patientExportRepo) ClaimPatientExportJob(ctx context.Context, staleBefore pgtype.Timestamptz) (db.PatientExportJob, error) {
+	return r.queries.ClaimPatientExportJob(ctx, staleBefore)
+}
+
+func (r *patientExportRepo) FailPatientExportJob(ctx context.Context, arg db.FailPatientExportJobParams) (db.PatientExportJob, error) {
+	return r.queries.FailPatientExportJob(ctx, arg)
+}
+
+func (r *patientExportRepo) CompletePatientExportJob(ctx context.Context, jobID pgtype.UUID, archive []byte, expiresAt pgtype.Timestamptz) (db.PatientExportJob, error) {
+	sealed, err := r.cipher.EncryptBytes(ctx, archiveField(jobID), archive)
+	if err != nil {
+		return db.PatientExportJob{}, fmt.Errorf("encrypt archive: %w", err)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return db.PatientExportJob{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after Commit
+
+	q := r.queries.WithTx(tx)
+	if err := q.SavePatientExportArchive(ctx, db.SavePatientExportArchiveParams{JobID: jobID, Data: sealed}); err != nil {
+		return db.PatientExportJob{}, err
+	}
+	job, err := q.CompletePatientExportJob(ctx, db.CompletePatientExportJobParams{
+		ID:        jobID,
+		SizeBytes: pgtype.Int8{Int64: int64(len(archive)), Valid: true},
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return db.PatientExportJob{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.PatientExportJob{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return job, nil
+}
+
+func (r *patientExportRepo) GetPatientExportArchive(ctx context.Context, jobID pgtype.UUID) ([]byte, error) {
+	sealed, err := r.queries.GetPatientExportArchive(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := r.cipher.DecryptBytes(ctx, archiveField(jobID), sealed)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt archive: %w", err)
+	}
+	return archive, nil
+}
+
+func (r *patientExportRepo) ExpirePatientExports(ctx context.Context, now pgtype.Timestamptz) (int64, error) {
+	expired, err := r.queries.ExpirePatientExportJobs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.queries.DeleteExpiredPatientExportArchives(ctx); err != nil {
+		return expired, err
+	}
+	return expired, nil
+}
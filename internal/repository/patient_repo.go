@@ -4,39 +4,104 @@ import (
 	"context"
 
 	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// patientRepo encrypts contact details and medical history on the way in and decrypts
+// them on the way out, so callers only ever see plaintext.
 type patientRepo struct {
 	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
 }
 
-func NewPatientRepo(queries *db.Queries) PatientRepository {
-	return &patientRepo{queries: queries}
+func NewPatientRepo(queries *db.Queries, cipher *fieldcrypt.Cipher) PatientRepository {
+	return &patientRepo{queries: queries, cipher: cipher}
 }
 
 func (r *patientRepo) CreatePatient(ctx context.Context, arg db.CreatePatientParams) (db.Patient, error) {
-	return r.queries.CreatePatient(ctx, arg)
+	var err error
+	arg.ContactPhoneBidx = blindIndex(r.cipher, fieldcrypt.IndexPhone, arg.ContactPhone)
+	arg.ContactEmailBidx = blindIndex(r.cipher, fieldcrypt.IndexEmail, arg.ContactEmail)
+	if arg.ContactPhone, err = encryptText(ctx, r.cipher, fieldPatientContactPhone, arg.ContactPhone); err != nil {
+		return db.Patient{}, err
+	}
+	if arg.ContactEmail, err = encryptText(ctx, r.cipher, fieldPatientContactEmail, arg.ContactEmail); err != nil {
+		return db.Patient{}, err
+	}
+	if arg.MedicalHistory, err = encryptText(ctx, r.cipher, fieldPatientMedicalHistory, arg.MedicalHistory); err != nil {
+		return db.Patient{}, err
+	}
+	patient, err := r.queries.CreatePatient(ctx, arg)
+	return r.decrypt(ctx, patient, err)
 }
 
 func (r *patientRepo) GetPatientByID(ctx context.Context, id pgtype.UUID) (db.Patient, error) {
-	return r.queries.GetPatientByID(ctx, id)
+	patient, err := r.queries.GetPatientByID(ctx, id)
+	return r.decrypt(ctx, patient, err)
+}
+
+// GetPatientByContactPhone finds a patient by phone number through its blind index.
+func (r *patientRepo) GetPatientByContactPhone(ctx context.Context, phone string) (db.Patient, error) {
+	index := blindIndex(r.cipher, fieldcrypt.IndexPhone, pgtype.Text{String: phone, Valid: true})
+	if !index.Valid {
+		return db.Patient{}, pgx.ErrNoRows
+	}
+	patient, err := r.queries.GetPatientByContactPhoneIndex(ctx, index)
+	return r.decrypt(ctx, patient, err)
+}
+
+// GetPatientByContactEmail finds a patient by email address through its blind index.
+func (r *patientRepo) GetPatientByContactEmail(ctx context.Context, email string) (db.Patient, error) {
+	index := blindIndex(r.cipher, fieldcrypt.IndexEmail, pgtype.Text{String: email, Valid: true})
+	if !index.Valid {
+		return db.Patient{}, pgx.ErrNoRows
+	}
+	patient, err := r.queries.GetPatientByContactEmailIndex(ctx, index)
+	return r.decrypt(ctx, patient, err)
 }
 
 func (r *patientRepo) ListPatients(ctx context.Context, arg db.ListPatientsParams) ([]db.Patient, error) {
-	return r.queries.ListPatients(ctx, arg)
+	patients, err := r.queries.ListPatients(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	if err := decryptPatients(ctx, r.cipher, patients); err != nil {
+		return nil, err
+	}
+	return patients, nil
 }
 
 func (r *patientRepo) UpdatePatient(ctx context.Context, arg db.UpdatePatientParams) (db.Patient, error) {
-	return r.queries.UpdatePatient(ctx, arg)
+	var err error
+	arg.ContactPhoneBidx = blindIndex(r.cipher, fieldcrypt.IndexPhone, arg.ContactPhone)
+	arg.ContactEmailBidx = blindIndex(r.cipher, fieldcrypt.IndexEmail, arg.ContactEmail)
+	if arg.ContactPhone, err = encryptText(ctx, r.cipher, fieldPatientContactPhone, arg.ContactPhone); err != nil {
+		return db.Patient{}, err
+	}
+	if arg.ContactEmail, err = encryptText(ctx, r.cipher, fieldPatientContactEmail, arg.ContactEmail); err != nil {
+		return db.Patient{}, err
+	}
+	if arg.MedicalHistory, err = encryptText(ctx, r.cipher, fieldPatientMedicalHistory, arg.MedicalHistory); err != nil {
+		return db.Patient{}, err
+	}
+	patient, err := r.queries.UpdatePatient(ctx, arg)
+	return r.decrypt(ctx, patient, err)
 }
 
 func (r *patientRepo) UpdatePatientMedicalInfo(ctx context.Context, arg db.UpdatePatientMedicalInfoParams) (db.Patient, error) {
-	return r.queries.UpdatePatientMedicalInfo(ctx, arg)
+	var err error
+	if arg.MedicalHistory, err = encryptText(ctx, r.cipher, fieldPatientMedicalHistory, arg.MedicalHistory); err != nil {
+		return db.Patient{}, err
+	}
+	patient, err := r.queries.UpdatePatientMedicalInfo(ctx, arg)
+	return r.decrypt(ctx, patient, err)
 }
 
 func (r *patientRepo) SoftDeletePatient(ctx context.Context, id pgtype.UUID) (db.Patient, error) {
-	return r.queries.SoftDeletePatient(ctx, id)
+	patient, err := r.queries.SoftDeletePatient(ctx, id)
+	return r.decrypt(ctx, patient, err)
 }
 
 func (r *patientRepo) HardDeletePatient(ctx context.Context, id pgtype.UUID) error {
@@ -47,3 +112,28 @@ func (r *patientRepo) CountPatients(ctx context.Context) (int64, error) {
 	return r.queries.CountPatients(ctx)
 }
 
+
+func (r *patientRepo) ListPatientsInCareOf(ctx context.Context, arg db.ListPatientsInCareOfParams) ([]db.Patient, error) {
+	patients, err := r.queries.ListPatientsInCareOf(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	if err := decryptPatients(ctx, r.cipher, patients); err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
+
+func (r *patientRepo) CountPatientsInCareOf(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	return r.queries.CountPatientsInCareOf(ctx, userID)
+}
+
+func (r *patientRepo) decrypt(ctx context.Context, p db.Patient, err error) (db.Patient, error) {
+	if err != nil {
+		return p, err
+	}
+	if err := decryptPatient(ctx, r.cipher, &p); err != nil {
+		return db.Patient{}, err
+	}
+	return p, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type pharmacyRepo struct {
+	queries *db.Queries
+}
+
+func NewPharmacyRepo(queries *db.Queries) PharmacyRepository {
+	return &pharmacyRepo{queries: queries}
+}
+
+func (r *pharmacyRepo) CreatePharmacyLocation(ctx context.Context, arg db.CreatePharmacyLocationParams) (db.PharmacyLocation, error) {
+	return r.queries.CreatePharmacyLocation(ctx, arg)
+}
+
+func (r *pharmacyRepo) GetPharmacyLocationByID(ctx context.Context, id pgtype.UUID) (db.PharmacyLocation, error) {
+	return r.queries.GetPharmacyLocationByID(ctx, id)
+}
+
+func (r *pharmacyRepo) ListPharmacyLocations(ctx context.Context) ([]db.PharmacyLocation, error) {
+	return r.queries.ListPharmacyLocations(ctx)
+}
+
+func (r *pharmacyRepo) CreatePharmacyItem(ctx context.Context, arg db.CreatePharmacyItemParams) (db.PharmacyItem, error) {
+	return r.queries.CreatePharmacyItem(ctx, arg)
+}
+
+func (r *pharmacyRepo) GetPharmacyItemByID(ctx context.Context, id pgtype.UUID) (db.PharmacyItem, error) {
+	return r.queries.GetPharmacyItemByID(ctx, id)
+}
+
+func (r *pharmacyRepo) ListPharmacyItems(ctx context.Context, arg db.ListPharmacyItemsParams) ([]db.PharmacyItem, error) {
+	return r.queries.ListPharmacyItems(ctx, arg)
+}
+
+func (r *pharmacyRepo) CountPharmacyItems(ctx context.Context) (int64, error) {
+	return r.queries.CountPharmacyItems(ctx)
+}
+
+func (r *pharmacyRepo) UpsertPharmacyBatch(ctx context.Context, arg db.UpsertPharmacyBatchParams) (db.PharmacyBatch, error) {
+	return r.queries.UpsertPharmacyBatch(ctx, arg)
+}
+
+func (r *pharmacyRepo) GetPharmacyBatchByID(ctx context.Context, id pgtype.UUID) (db.PharmacyBatch, error) {
+	return r.queries.GetPharmacyBatchByID(ctx, id)
+}
+
+func (r *pharmacyRepo) ApplyStockDelta(ctx context.Context, arg db.ApplyStockDeltaParams) (db.StockLevel, error) {
+	return r.queries.ApplyStockDelta(ctx, arg)
+}
+
+func (r *pharmacyRepo) LockItemStockAtLocation(ctx context.Context, arg db.LockItemStockAtLocationParams) ([]db.LockItemStockAtLocationRow, error) {
+	return r.queries.LockItemStockAtLocation(ctx, arg)
+}
+
+func (r *pharmacyRepo) GetItemStockOnHand(ctx context.Context, arg db.GetItemStockOnHandParams) (int64, error) {
+	return r.queries.GetItemStockOnHand(ctx, arg)
+}
+
+func (r *pharmacyRepo) ListStockLevels(ctx context.Context, arg db.ListStockLevelsParams) ([]db.ListStockLevelsRow, error) {
+	return r.queries.ListStockLevels(ctx, arg)
+}
+
+func (r *pharmacyRepo) ListNearExpiryStock(ctx context.Context, arg db.ListNearExpiryStockParams) ([]db.ListNearExpiryStockRow, error) {
+	return r.queries.ListNearExpiryStock(ctx, arg)
+}
+
+func (r *pharmacyRepo) ListItemsBelowReorderLevel(ctx context.Context, arg db.ListItemsBelowReorderLevelParams) ([]db.ListItemsBelowReorderLevelRow, error) {
+	return r.queries.ListItemsBelowReorderLevel(ctx, arg)
+}
+
+func (r *pharmacyRepo) CreateStockMovement(ctx context.Context, arg db.CreateStockMovementParams) (db.StockMovement, error) {
+	return r.queries.CreateStockMovement(ctx, arg)
+}
+
+func (r *pharmacyRepo) LockStockMovement(ctx context.Context, id pgtype.UUID) (db.StockMovement, error) {
+	return r.queries.LockStockMovement(ctx, id)
+}
+
+func (r *pharmacyRepo) SumReturnedQuantity(ctx context.Context, movementID pgtype.UUID) (int64, error) {
+	return r.queries.SumReturnedQuantity(ctx, movementID)
+}
+
+func (r *pharmacyRepo) ListStockMovements(ctx context.Context, arg db.ListStockMovementsParams) ([]db.ListStockMovementsRow, error) {
+	return r.queries.ListStockMovements(ctx, arg)
+}
+
+func (r *pharmacyRepo) CountStockMovements(ctx context.Context, arg db.CountStockMovementsParams) (int64, error) {
+	return r.queries.CountStockMovements(ctx, arg)
+}
+
+func (r *pharmacyRepo) ListVisitStockMovements(ctx context.Context, visitID pgtype.UUID) ([]db.ListVisitStockMovementsRow, error) {
+	return r.queries.ListVisitStockMovements(ctx, visitID)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type pseudonymizationRepo struct {
+	queries *db.Queries
+}
+
+func NewPseudonymizationRepo(queries *db.Queries) PseudonymizationRepository {
+	return &pseudonymizationRepo{queries: queries}
+}
+
+func (r *pseudonymizationRepo) CreatePseudonymizationRequest(ctx context.Context, arg db.CreatePseudonymizationRequestParams) (db.PseudonymizationRequest, error) {
+	return r.queries.CreatePseudonymizationRequest(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) GetPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (db.PseudonymizationRequest, error) {
+	return r.queries.GetPseudonymizationRequest(ctx, id)
+}
+
+func (r *pseudonymizationRepo) LockPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (db.PseudonymizationRequest, error) {
+	return r.queries.LockPseudonymizationRequest(ctx, id)
+}
+
+func (r *pseudonymizationRepo) ListPseudonymizationRequests(ctx context.Context, arg db.ListPseudonymizationRequestsParams) ([]db.PseudonymizationRequest, error) {
+	return r.queries.ListPseudonymizationRequests(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) CountPseudonymizationRequests(ctx context.Context, status db.NullPseudonymizationStatus) (int64, error) {
+	return r.queries.CountPseudonymizationRequests(ctx, status)
+}
+
+func (r *pseudonymizationRepo) DecidePseudonymizationRequest(ctx context.Context, arg db.DecidePseudonymizationRequestParams) (db.PseudonymizationRequest, error) {
+	return r.queries.DecidePseudonymizationRequest(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) LockPatient(ctx context.Context, id pgtype.UUID) (db.Patient, error) {
+	return r.queries.LockPatient(ctx, id)
+}
+
+func (r *pseudonymizationRepo) PseudonymizePatient(ctx context.Context, arg db.PseudonymizePatientParams) (db.Patient, error) {
+	return r.queries.PseudonymizePatient(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) PseudonymizePatientPolicies(ctx context.Context, arg db.PseudonymizePatientPoliciesParams) (int64, error) {
+	return r.queries.PseudonymizePatientPolicies(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) CancelPatientExports(ctx context.Context, arg db.CancelPatientExportsParams) (int64, error) {
+	return r.queries.CancelPatientExports(ctx, arg)
+}
+
+func (r *pseudonymizationRepo) DeletePatientExportArchives(ctx context.Context, patientID pgtype.UUID) (int64, error) {
+	return r.queries.DeletePatientExportArchives(ctx, patientID)
+}
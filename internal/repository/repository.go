@@ -23,12 +23,16 @@ type UserRepository interface {
 type PatientRepository interface {
 	CreatePatient(ctx context.Context, arg db.CreatePatientParams) (db.Patient, error)
 	GetPatientByID(ctx context.Context, id pgtype.UUID) (db.Patient, error)
+	GetPatientByContactPhone(ctx context.Context, phone string) (db.Patient, error)
+	GetPatientByContactEmail(ctx context.Context, email string) (db.Patient, error)
 	ListPatients(ctx context.Context,arg db.ListPatientsParams) ([]db.Patient, error)
 	UpdatePatient(ctx context.Context, arg db.UpdatePatientParams) (db.Patient, error)
 	UpdatePatientMedicalInfo(ctx context.Context, arg db.UpdatePatientMedicalInfoParams) (db.Patient, error)
 	SoftDeletePatient(ctx context.Context, id pgtype.UUID) (db.Patient, error)
 	HardDeletePatient(ctx context.Context, id pgtype.UUID) error
 	CountPatients(ctx context.Context) (int64, error)
+	ListPatientsInCareOf(ctx context.Context, arg db.ListPatientsInCareOfParams) ([]db.Patient, error)
+	CountPatientsInCareOf(ctx context.Context, userID pgtype.UUID) (int64, error)
 }
 
 // PatientVisitRepository defines the interface for patient visit data persistence.
@@ -39,4 +43,242 @@ type PatientVisitQuerier interface {
 	ListPatientVisitsByDoctorID(ctx context.Context, arg db.ListPatientVisitsByDoctorIDParams) ([]db.ListPatientVisitsByDoctorIDRow, error)
 	ListPatientVisitsByPatientID(ctx context.Context, arg db.ListPatientVisitsByPatientIDParams) ([]db.ListPatientVisitsByPatientIDRow, error)
 	UpdatePatientVisit(ctx context.Context, arg db.UpdatePatientVisitParams) (db.PatientVisit, error)
-}
\ No newline at end of file
+}
+
+// VisitRevisionRepository changes visits together with the revision that keeps their
+// previous version, inside a TxManager transaction. Clinical text is encrypted on the way
+// in and decrypted on the way out.
+type VisitRevisionRepository interface {
+	LockPatientVisit(ctx context.Context, id pgtype.UUID) (db.PatientVisit, error)
+	UpdatePatientVisit(ctx context.Context, arg db.UpdatePatientVisitParams) (db.PatientVisit, error)
+	FinalizePatientVisit(ctx context.Context, arg db.FinalizePatientVisitParams) (db.PatientVisit, error)
+	CreatePatientVisitRevision(ctx context.Context, arg db.CreatePatientVisitRevisionParams) (db.PatientVisitRevision, error)
+	ListPatientVisitRevisions(ctx context.Context, visitID pgtype.UUID) ([]db.PatientVisitRevision, error)
+}
+// LabRepository defines the interface for lab catalog, order and result persistence.
+type LabRepository interface {
+	CreateLabTest(ctx context.Context, arg db.CreateLabTestParams) (db.LabTest, error)
+	GetLabTestByID(ctx context.Context, id pgtype.UUID) (db.LabTest, error)
+	GetLabTestByCode(ctx context.Context, code string) (db.LabTest, error)
+	ListLabTests(ctx context.Context, arg db.ListLabTestsParams) ([]db.LabTest, error)
+	CountLabTests(ctx context.Context) (int64, error)
+	CreateLabAnalyte(ctx context.Context, arg db.CreateLabAnalyteParams) (db.LabAnalyte, error)
+	ListLabAnalytesByTestID(ctx context.Context, testID pgtype.UUID) ([]db.LabAnalyte, error)
+	CreateLabOrder(ctx context.Context, arg db.CreateLabOrderParams) (db.LabOrder, error)
+	GetLabOrderByID(ctx context.Context, id pgtype.UUID) (db.LabOrder, error)
+	ListLabOrdersByVisitID(ctx context.Context, visitID pgtype.UUID) ([]db.LabOrder, error)
+	ListLabOrdersByStatus(ctx context.Context, arg db.ListLabOrdersByStatusParams) ([]db.LabOrder, error)
+	MarkLabOrderCollected(ctx context.Context, arg db.MarkLabOrderCollectedParams) (db.LabOrder, error)
+	MarkLabOrderResulted(ctx context.Context, arg db.MarkLabOrderResultedParams) (db.LabOrder, error)
+	MarkLabOrderVerified(ctx context.Context, arg db.MarkLabOrderVerifiedParams) (db.LabOrder, error)
+	UpsertLabResult(ctx context.Context, arg db.UpsertLabResultParams) (db.LabResult, error)
+	ListLabResultsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]db.ListLabResultsByOrderIDRow, error)
+	ListCumulativeLabResultsByPatientID(ctx context.Context, arg db.ListCumulativeLabResultsByPatientIDParams) ([]db.ListCumulativeLabResultsByPatientIDRow, error)
+}
+
+// AlertRepository defines the interface for observation, alert rule and alert persistence.
+type AlertRepository interface {
+	CreateVisitObservation(ctx context.Context, arg db.CreateVisitObservationParams) (db.VisitObservation, error)
+	ListVisitObservations(ctx context.Context, visitID pgtype.UUID) ([]db.VisitObservation, error)
+	CreateAlertRule(ctx context.Context, arg db.CreateAlertRuleParams) (db.AlertRule, error)
+	GetAlertRuleByID(ctx context.Context, id pgtype.UUID) (db.AlertRule, error)
+	ListActiveAlertRules(ctx context.Context) ([]db.AlertRule, error)
+	ListActiveAlertRulesByCode(ctx context.Context, observationCode string) ([]db.AlertRule, error)
+	DeactivateAlertRule(ctx context.Context, id pgtype.UUID) (db.AlertRule, error)
+	CreateAlert(ctx context.Context, arg db.CreateAlertParams) (db.Alert, error)
+	GetAlertByID(ctx context.Context, id pgtype.UUID) (db.Alert, error)
+	ListAlertsForDoctor(ctx context.Context, arg db.ListAlertsForDoctorParams) ([]db.Alert, error)
+	CountAlertsForDoctor(ctx context.Context, arg db.CountAlertsForDoctorParams) (int64, error)
+	AcknowledgeAlert(ctx context.Context, arg db.AcknowledgeAlertParams) (db.Alert, error)
+	LockDueAlerts(ctx context.Context, arg db.LockDueAlertsParams) ([]db.Alert, error)
+	EscalateAlert(ctx context.Context, arg db.EscalateAlertParams) (db.Alert, error)
+	PostponeAlertEscalation(ctx context.Context, arg db.PostponeAlertEscalationParams) (db.Alert, error)
+	CreateAlertEscalation(ctx context.Context, arg db.CreateAlertEscalationParams) (db.AlertEscalation, error)
+	PickEscalationDoctor(ctx context.Context, excludeDoctorID pgtype.UUID) (pgtype.UUID, error)
+	// WithinAdvisoryLock runs fn in a transaction holding the given transaction-level advisory lock.
+	// It returns false without calling fn when another session holds the lock.
+	WithinAdvisoryLock(ctx context.Context, key int64, fn func(AlertRepository) error) (bool, error)
+}
+
+// BillingRepository defines the interface for charge master, charge, invoice and payment persistence.
+type BillingRepository interface {
+	CreateTaxCategory(ctx context.Context, arg db.CreateTaxCategoryParams) (db.TaxCategory, error)
+	ListTaxCategories(ctx context.Context) ([]db.TaxCategory, error)
+	CreateChargeItem(ctx context.Context, arg db.CreateChargeItemParams) (db.ChargeItem, error)
+	GetChargeItemByCode(ctx context.Context, code string) (db.GetChargeItemByCodeRow, error)
+	ListChargeItems(ctx context.Context, arg db.ListChargeItemsParams) ([]db.ListChargeItemsRow, error)
+	CountChargeItems(ctx context.Context) (int64, error)
+	CreateCharge(ctx context.Context, arg db.CreateChargeParams) (db.Charge, error)
+	ListChargesByPatientID(ctx context.Context, arg db.ListChargesByPatientIDParams) ([]db.Charge, error)
+	CountChargesByPatientID(ctx context.Context, arg db.CountChargesByPatientIDParams) (int64, error)
+	ListUnbilledChargesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.Charge, error)
+	LockUnbilledCharges(ctx context.Context, arg db.LockUnbilledChargesParams) ([]db.Charge, error)
+	AssignChargeToInvoice(ctx context.Context, arg db.AssignChargeToInvoiceParams) error
+	NextInvoiceSequence(ctx context.Context, fiscalYear int32) (int32, error)
+	CreateInvoice(ctx context.Context, arg db.CreateInvoiceParams) (db.Invoice, error)
+	CreateInvoiceLine(ctx context.Context, arg db.CreateInvoiceLineParams) (db.InvoiceLine, error)
+	GetInvoiceByID(ctx context.Context, id pgtype.UUID) (db.Invoice, error)
+	LockInvoice(ctx context.Context, id pgtype.UUID) (db.Invoice, error)
+	ListInvoicesByPatientID(ctx context.Context, arg db.ListInvoicesByPatientIDParams) ([]db.Invoice, error)
+	CountInvoicesByPatientID(ctx context.Context, patientID pgtype.UUID) (int64, error)
+	ListInvoiceLines(ctx context.Context, invoiceID pgtype.UUID) ([]db.InvoiceLine, error)
+	UpdateInvoicePayment(ctx context.Context, arg db.UpdateInvoicePaymentParams) (db.Invoice, error)
+	GetPatientInvoiceTotals(ctx context.Context, patientID pgtype.UUID) (db.GetPatientInvoiceTotalsRow, error)
+	CreatePayment(ctx context.Context, arg db.CreatePaymentParams) (db.Payment, error)
+	LockPayment(ctx context.Context, id pgtype.UUID) (db.Payment, error)
+	ListPaymentsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.Payment, error)
+	CreateRefund(ctx context.Context, arg db.CreateRefundParams) (db.Refund, error)
+	SumRefundsByPaymentID(ctx context.Context, paymentID pgtype.UUID) (int64, error)
+	ListRefundsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.Refund, error)
+}
+
+// InsuranceRepository defines the interface for insurance policy, eligibility and claim persistence.
+type InsuranceRepository interface {
+	CreateInsurancePolicy(ctx context.Context, arg db.CreateInsurancePolicyParams) (db.InsurancePolicy, error)
+	GetInsurancePolicyByID(ctx context.Context, id pgtype.UUID) (db.InsurancePolicy, error)
+	ListInsurancePoliciesByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.InsurancePolicy, error)
+	DeactivateInsurancePolicy(ctx context.Context, id pgtype.UUID) (db.InsurancePolicy, error)
+	UpdatePolicyEligibility(ctx context.Context, arg db.UpdatePolicyEligibilityParams) (db.InsurancePolicy, error)
+	CreateEligibilityCheck(ctx context.Context, arg db.CreateEligibilityCheckParams) (db.EligibilityCheck, error)
+	CreateInsuranceClaim(ctx context.Context, arg db.CreateInsuranceClaimParams) (db.InsuranceClaim, error)
+	GetInsuranceClaimByID(ctx context.Context, id pgtype.UUID) (db.InsuranceClaim, error)
+	ListInsuranceClaimsByInvoiceID(ctx context.Context, invoiceID pgtype.UUID) ([]db.InsuranceClaim, error)
+	ListInsuranceClaimsByStatus(ctx context.Context, arg db.ListInsuranceClaimsByStatusParams) ([]db.InsuranceClaim, error)
+	CountInsuranceClaimsByStatus(ctx context.Context, status db.ClaimStatus) (int64, error)
+	UpdateInsuranceClaimStatus(ctx context.Context, arg db.UpdateInsuranceClaimStatusParams) (db.InsuranceClaim, error)
+	MarkInsuranceClaimSent(ctx context.Context, arg db.MarkInsuranceClaimSentParams) (db.InsuranceClaim, error)
+	CreateClaimStatusHistory(ctx context.Context, arg db.CreateClaimStatusHistoryParams) (db.ClaimStatusHistory, error)
+	ListClaimStatusHistory(ctx context.Context, claimID pgtype.UUID) ([]db.ClaimStatusHistory, error)
+}
+
+// PharmacyRepository defines the interface for pharmacy catalog, stock and ledger persistence.
+type PharmacyRepository interface {
+	CreatePharmacyLocation(ctx context.Context, arg db.CreatePharmacyLocationParams) (db.PharmacyLocation, error)
+	GetPharmacyLocationByID(ctx context.Context, id pgtype.UUID) (db.PharmacyLocation, error)
+	ListPharmacyLocations(ctx context.Context) ([]db.PharmacyLocation, error)
+	CreatePharmacyItem(ctx context.Context, arg db.CreatePharmacyItemParams) (db.PharmacyItem, error)
+	GetPharmacyItemByID(ctx context.Context, id pgtype.UUID) (db.PharmacyItem, error)
+	ListPharmacyItems(ctx context.Context, arg db.ListPharmacyItemsParams) ([]db.PharmacyItem, error)
+	CountPharmacyItems(ctx context.Context) (int64, error)
+	UpsertPharmacyBatch(ctx context.Context, arg db.UpsertPharmacyBatchParams) (db.PharmacyBatch, error)
+	GetPharmacyBatchByID(ctx context.Context, id pgtype.UUID) (db.PharmacyBatch, error)
+	ApplyStockDelta(ctx context.Context, arg db.ApplyStockDeltaParams) (db.StockLevel, error)
+	LockItemStockAtLocation(ctx context.Context, arg db.LockItemStockAtLocationParams) ([]db.LockItemStockAtLocationRow, error)
+	GetItemStockOnHand(ctx context.Context, arg db.GetItemStockOnHandParams) (int64, error)
+	ListStockLevels(ctx context.Context, arg db.ListStockLevelsParams) ([]db.ListStockLevelsRow, error)
+	ListNearExpiryStock(ctx context.Context, arg db.ListNearExpiryStockParams) ([]db.ListNearExpiryStockRow, error)
+	ListItemsBelowReorderLevel(ctx context.Context, arg db.ListItemsBelowReorderLevelParams) ([]db.ListItemsBelowReorderLevelRow, error)
+	CreateStockMovement(ctx context.Context, arg db.CreateStockMovementParams) (db.StockMovement, error)
+	LockStockMovement(ctx context.Context, id pgtype.UUID) (db.StockMovement, error)
+	SumReturnedQuantity(ctx context.Context, movementID pgtype.UUID) (int64, error)
+	ListStockMovements(ctx context.Context, arg db.ListStockMovementsParams) ([]db.ListStockMovementsRow, error)
+	CountStockMovements(ctx context.Context, arg db.CountStockMovementsParams) (int64, error)
+	ListVisitStockMovements(ctx context.Context, visitID pgtype.UUID) ([]db.ListVisitStockMovementsRow, error)
+}
+
+// AuditRepository defines the interface for the append-only audit trail.
+type AuditRepository interface {
+	GetAuditChainHead(ctx context.Context) (db.GetAuditChainHeadRow, error)
+	CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error)
+	ListAuditEvents(ctx context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error)
+	CountAuditEvents(ctx context.Context, arg db.CountAuditEventsParams) (int64, error)
+	ListAuditEventsAfterSeq(ctx context.Context, arg db.ListAuditEventsAfterSeqParams) ([]db.AuditEvent, error)
+	ListPatientAccessLog(ctx context.Context, arg db.ListPatientAccessLogParams) ([]db.ListPatientAccessLogRow, error)
+	ListPatientAccessLogBeforeSeq(ctx context.Context, arg db.ListPatientAccessLogBeforeSeqParams) ([]db.ListPatientAccessLogBeforeSeqRow, error)
+	CountPatientAccessLog(ctx context.Context, patientID pgtype.UUID) (int64, error)
+	// WithinLock runs fn in a transaction holding the given advisory lock, waiting for it if needed.
+	// From Repos it uses the TxManager transaction, and the lock is held until that commits.
+	WithinLock(ctx context.Context, key int64, fn func(AuditRepository) error) error
+}
+
+// BreakGlassRepository defines the interface for emergency access grants and their daily reviews.
+type BreakGlassRepository interface {
+	CreateBreakGlassGrant(ctx context.Context, arg db.CreateBreakGlassGrantParams) (db.BreakGlassGrant, error)
+	GetActiveBreakGlassGrant(ctx context.Context, arg db.GetActiveBreakGlassGrantParams) (db.BreakGlassGrant, error)
+	ListBreakGlassGrantsForReview(ctx context.Context, arg db.ListBreakGlassGrantsForReviewParams) ([]db.ListBreakGlassGrantsForReviewRow, error)
+	ListBreakGlassAccesses(ctx context.Context, arg db.ListBreakGlassAccessesParams) ([]db.ListBreakGlassAccessesRow, error)
+	CreateBreakGlassReport(ctx context.Context, arg db.CreateBreakGlassReportParams) (db.BreakGlassReport, error)
+	GetBreakGlassReport(ctx context.Context, reportDate pgtype.Date) (db.BreakGlassReport, error)
+	ListBreakGlassReports(ctx context.Context, arg db.ListBreakGlassReportsParams) ([]db.ListBreakGlassReportsRow, error)
+	CountBreakGlassReports(ctx context.Context) (int64, error)
+}
+
+// CareTeamRepository defines the interface for care team assignments and the
+// relationship checks built on them.
+type CareTeamRepository interface {
+	AddCareTeamMember(ctx context.Context, arg db.AddCareTeamMemberParams) (db.CareTeamMember, error)
+	RemoveCareTeamMember(ctx context.Context, arg db.RemoveCareTeamMemberParams) (db.CareTeamMember, error)
+	ListCareTeamMembers(ctx context.Context, patientID pgtype.UUID) ([]db.ListCareTeamMembersRow, error)
+	IsInCareOf(ctx context.Context, arg db.IsInCareOfParams) (bool, error)
+}
+
+// ConsentRepository defines the interface for patient consent records.
+type ConsentRepository interface {
+	CreateConsentRecord(ctx context.Context, arg db.CreateConsentRecordParams) (db.ConsentRecord, error)
+	GetConsentRecordByID(ctx context.Context, arg db.GetConsentRecordByIDParams) (db.ConsentRecord, error)
+	GetLatestConsentRecord(ctx context.Context, arg db.GetLatestConsentRecordParams) (db.ConsentRecord, error)
+	ListConsentRecordsByPatientID(ctx context.Context, patientID pgtype.UUID) ([]db.ConsentRecord, error)
+	WithdrawConsentRecord(ctx context.Context, arg db.WithdrawConsentRecordParams) (db.ConsentRecord, error)
+	WithdrawActiveConsent(ctx context.Context, arg db.WithdrawActiveConsentParams) ([]db.ConsentRecord, error)
+}
+
+// PatientExportRepository defines the interface for subject access export jobs and
+// their archives, which are stored encrypted.
+type PatientExportRepository interface {
+	CreatePatientExportJob(ctx context.Context, arg db.CreatePatientExportJobParams) (db.PatientExportJob, error)
+	GetPatientExportJob(ctx context.Context, arg db.GetPatientExportJobParams) (db.PatientExportJob, error)
+	GetOpenPatientExportJob(ctx context.Context, patientID pgtype.UUID) (db.PatientExportJob, error)
+	ListPatientExportJobs(ctx context.Context, arg db.ListPatientExportJobsParams) ([]db.PatientExportJob, error)
+	CountPatientExportJobs(ctx context.Context, patientID pgtype.UUID) (int64, error)
+	ClaimPatientExportJob(ctx context.Context, staleBefore pgtype.Timestamptz) (db.PatientExportJob, error)
+	FailPatientExportJob(ctx context.Context, arg db.FailPatientExportJobParams) (db.PatientExportJob, error)
+	// CompletePatientExportJob stores the archive and marks the job completed in one transaction.
+	CompletePatientExportJob(ctx context.Context, jobID pgtype.UUID, archive []byte, expiresAt pgtype.Timestamptz) (db.PatientExportJob, error)
+	GetPatientExportArchive(ctx context.Context, jobID pgtype.UUID) ([]byte, error)
+	// ExpirePatientExports marks jobs past their expiry as expired and deletes the archives
+	// of expired and failed jobs, returning how many jobs expired.
+	ExpirePatientExports(ctx context.Context, now pgtype.Timestamptz) (int64, error)
+}
+
+// PseudonymizationRepository defines the interface for erasure requests and the patient
+// updates that carry them out.
+type PseudonymizationRepository interface {
+	CreatePseudonymizationRequest(ctx context.Context, arg db.CreatePseudonymizationRequestParams) (db.PseudonymizationRequest, error)
+	GetPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (db.PseudonymizationRequest, error)
+	LockPseudonymizationRequest(ctx context.Context, id pgtype.UUID) (db.PseudonymizationRequest, error)
+	ListPseudonymizationRequests(ctx context.Context, arg db.ListPseudonymizationRequestsParams) ([]db.PseudonymizationRequest, error)
+	CountPseudonymizationRequests(ctx context.Context, status db.NullPseudonymizationStatus) (int64, error)
+	DecidePseudonymizationRequest(ctx context.Context, arg db.DecidePseudonymizationRequestParams) (db.PseudonymizationRequest, error)
+	// LockPatient and PseudonymizePatient return the row as stored, with encrypted columns
+	// still encrypted.
+	LockPatient(ctx context.Context, id pgtype.UUID) (db.Patient, error)
+	PseudonymizePatient(ctx context.Context, arg db.PseudonymizePatientParams) (db.Patient, error)
+	PseudonymizePatientPolicies(ctx context.Context, arg db.PseudonymizePatientPoliciesParams) (int64, error)
+	CancelPatientExports(ctx context.Context, arg db.CancelPatientExportsParams) (int64, error)
+	DeletePatientExportArchives(ctx context.Context, patientID pgtype.UUID) (int64, error)
+}
+
+// ReencryptionBatch reports one page of a re-encryption pass. LastID is where the next
+// page starts; Skipped rows changed while being rewritten and are left for the next run.
+type ReencryptionBatch struct {
+	LastID    pgtype.UUID
+	Scanned   int
+	Rewritten int
+	Skipped   int
+}
+
+// FieldEncryptionRepository rewrites encrypted columns under the active master key.
+type FieldEncryptionRepository interface {
+	ReencryptPatients(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error)
+	ReencryptPatientVisits(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error)
+	ReencryptPatientVisitRevisions(ctx context.Context, afterID pgtype.UUID, limit int32) (ReencryptionBatch, error)
+}
+
+// ResearchRepository reads the patients and visits that go into de-identified research
+// datasets, decrypted.
+type ResearchRepository interface {
+	// ListResearchPatients returns patients with research consent in force, in ID order
+	// after afterID.
+	ListResearchPatients(ctx context.Context, afterID pgtype.UUID, limit int32) ([]db.Patient, error)
+	ListVisitsForPatients(ctx context.Context, patientIDs []pgtype.UUID) ([]db.PatientVisit, error)
+}
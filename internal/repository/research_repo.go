@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type researchRepo struct {
+	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
+}
+
+func NewResearchRepo(queries *db.Queries, cipher *fieldcrypt.Cipher) ResearchRepository {
+	return &researchRepo{queries: queries, cipher: cipher}
+}
+
+func (r *researchRepo) ListResearchPatients(ctx context.Context, afterID pgtype.UUID, limit int32) ([]db.Patient, error) {
+	patients, err := r.queries.ListResearchPatients(ctx, db.ListResearchPatientsParams{AfterID: afterID, RowLimit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return patients, decryptPatients(ctx, r.cipher, patients)
+}
+
+func (r *researchRepo) ListVisitsForPatients(ctx context.Context, patientIDs []pgtype.UUID) ([]db.PatientVisit, error) {
+	visits, err := r.queries.ListVisitsForPatients(ctx, patientIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range visits {
+		v := &visits[i]
+		if err := decryptVisitFields(ctx, r.cipher, &v.Symptoms, &v.Diagnosis, &v.Prescription, &v.Notes); err != nil {
+			return nil, err
+		}
+	}
+	return visits, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repos are the repositories bound to one transaction.
+type Repos struct {
+	Users            UserRepository
+	Patients         PatientRepository
+	Visits           PatientVisitQuerier
+	VisitRevisions   VisitRevisionRepository
+	Labs             LabRepository
+	Billing          BillingRepository
+	Insurance        InsuranceRepository
+	Pharmacy         PharmacyRepository
+	Consent          ConsentRepository
+	Pseudonymization PseudonymizationRepository
+	CareTeam         CareTeamRepository
+	PatientExports   PatientExportRepository
+	Audit            AuditRepository // Events appended here commit or roll back with the write
+}
+
+// TxManager runs a unit of work across repositories in a single transaction.
+type TxManager interface {
+	// WithinTx runs fn in a transaction and commits if it returns nil. fn is run again,
+	// in a new transaction, when the transaction fails with a serialization failure or
+	// a deadlock, so it must not have effects outside the database.
+	WithinTx(ctx context.Context, fn func(Repos) error) error
+}
+
+// TxOptions configure the transactions a TxManager starts.
+type TxOptions struct {
+	IsoLevel     pgx.TxIsoLevel
+	MaxAttempts  int           // Including the first; at least 1
+	RetryBackoff time.Duration // Before the second attempt, doubling after each retry
+}
+
+// DefaultTxOptions use read committed, which never fails with a serialization error but
+// can still deadlock, and retry up to twice.
+func DefaultTxOptions() TxOptions {
+	return TxOptions{IsoLevel: pgx.ReadCommitted, MaxAttempts: 3, RetryBackoff: 20 * time.Millisecond}
+}
+
+// ParseIsoLevel reads an isolation level as written in SQL, case-insensitively, with
+// spaces or underscores ("repeatable read", "SERIALIZABLE").
+func ParseIsoLevel(s string) (pgx.TxIsoLevel, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "_", " ")) {
+	case "read committed":
+		return pgx.ReadCommitted, nil
+	case "repeatable read":
+		return pgx.RepeatableRead, nil
+	case "serializable":
+		return pgx.Serializable, nil
+	}
+	return "", fmt.Errorf("unknown isolation level %q: use read committed, repeatable read or serializable", s)
+}
+
+type txManager struct {
+	pool   *pgxpool.Pool
+	cipher *fieldcrypt.Cipher
+	opts   TxOptions
+}
+
+func NewTxManager(pool *pgxpool.Pool, cipher *fieldcrypt.Cipher, opts TxOptions) TxManager {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	return &txManager{pool: pool, cipher: cipher, opts: opts}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(Repos) error) error {
+	return withRetry(ctx, m.opts, sleep, func() error { return m.runOnce(ctx, fn) })
+}
+
+// withRetry calls run until it succeeds, fails with an error that is not retryable or
+// has been called opts.MaxAttempts times, calling sleep between attempts. It gives up
+// with the last error when sleep does.
+func withRetry(ctx context.Context, opts TxOptions, sleep func(context.Context, time.Duration) error, run func() error) error {
+	backoff := opts.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || attempt >= opts.MaxAttempts || !retryable(err) {
+			return err
+		}
+		if sleep(ctx, jitter(backoff)) != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// jitter picks a wait between half and one and a half times backoff, which keeps the
+// transactions that collided from colliding again.
+func jitter(backoff time.Duration) time.Duration {
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff)+1))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func (m *txManager) runOnce(ctx context.Context, fn func(Repos) error) error {
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: m.opts.IsoLevel})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after Commit
+
+	queries := db.New(tx)
+	if err := fn(Repos{
+		Users:            &userRepo{queries: queries},
+		Patients:         &patientRepo{queries: queries, cipher: m.cipher},
+		Visits:           &patientVisitQuerierRepo{queries: queries, cipher: m.cipher},
+		VisitRevisions:   &visitRevisionRepo{queries: queries, cipher: m.cipher},
+		Labs:             &labRepo{queries: queries},
+		Billing:          &billingRepo{queries: queries},
+		Insurance:        &insuranceRepo{queries: queries},
+		Pharmacy:         &pharmacyRepo{queries: queries},
+		Consent:          &consentRepo{queries: queries},
+		Pseudonymization: &pseudonymizationRepo{queries: queries},
+		CareTeam:         &careTeamRepo{queries: queries},
+		PatientExports:   &patientExportRepo{pool: m.pool, queries: queries, cipher: m.cipher},
+		Audit:            &auditRepo{pool: m.pool, queries: queries, inTx: true},
+	}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// retryable reports whether err is a serialization failure or a deadlock, after which
+// the whole transaction can be run again.
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
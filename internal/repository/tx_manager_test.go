@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errSerialization = &pgconn.PgError{Code: "40001"}
+	errDeadlock      = &pgconn.PgError{Code: "40P01"}
+	errUnique        = &pgconn.PgError{Code: "23505"}
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "serialization failure", err: errSerialization, want: true},
+		{name: "deadlock", err: errDeadlock, want: true},
+		{name: "wrapped deadlock", err: fmt.Errorf("commit transaction: %w", errDeadlock), want: true},
+		{name: "unique violation", err: errUnique},
+		{name: "not a postgres error", err: errors.New("connection reset")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryable(tt.err))
+		})
+	}
+}
+
+// recordSleeps returns a sleep that records its waits instead of waiting.
+func recordSleeps(waits *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+}
+
+// failing returns a run that fails with errs in turn, then succeeds, and counts its calls.
+func failing(calls *int, errs ...error) func() error {
+	return func() error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	opts := TxOptions{MaxAttempts: 3, RetryBackoff: 20 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "succeeds first time", wantCalls: 1},
+		{name: "succeeds after a serialization failure", errs: []error{errSerialization}, wantCalls: 2},
+		{name: "succeeds after two deadlocks", errs: []error{errDeadlock, errDeadlock}, wantCalls: 3},
+		{name: "gives up after max attempts", errs: []error{errDeadlock, errSerialization, errDeadlock}, wantErr: errDeadlock, wantCalls: 3},
+		{name: "does not retry other errors", errs: []error{errUnique}, wantErr: errUnique, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var waits []time.Duration
+			err := withRetry(context.Background(), opts, recordSleeps(&waits), failing(&calls, tt.errs...))
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Len(t, waits, tt.wantCalls-1)
+		})
+	}
+}
+
+func TestWithRetryBacksOffWithJitter(t *testing.T) {
+	opts := TxOptions{MaxAttempts: 5, RetryBackoff: 20 * time.Millisecond}
+	var calls int
+	var waits []time.Duration
+	err := withRetry(context.Background(), opts, recordSleeps(&waits), failing(&calls, errDeadlock, errDeadlock, errDeadlock, errDeadlock))
+	assert.NoError(t, err)
+	assert.Len(t, waits, 4)
+	backoff := opts.RetryBackoff
+	for i, wait := range waits {
+		assert.GreaterOrEqual(t, wait, backoff/2, "wait %d", i)
+		assert.LessOrEqual(t, wait, backoff*3/2, "wait %d", i)
+		backoff *= 2
+	}
+}
+
+func TestWithRetryStopsWhenTheContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int
+	err := withRetry(ctx, TxOptions{MaxAttempts: 3, RetryBackoff: time.Hour}, sleep, failing(&calls, errDeadlock, errDeadlock))
+	assert.Equal(t, errDeadlock, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		wait := jitter(20 * time.Millisecond)
+		assert.GreaterOrEqual(t, wait, 10*time.Millisecond)
+		assert.LessOrEqual(t, wait, 30*time.Millisecond)
+	}
+	assert.Zero(t, jitter(0))
+}
@@ -4,39 +4,79 @@ import (
 	"context"
 
 	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// patientVisitQuerierRepo encrypts the clinical text of a visit on the way in and
+// decrypts it on the way out.
 type patientVisitQuerierRepo struct {
 	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
 }
 
-func NewPatientVisitRepo(queries *db.Queries) *patientVisitQuerierRepo {
-	return &patientVisitQuerierRepo{queries: queries}
+func NewPatientVisitRepo(queries *db.Queries, cipher *fieldcrypt.Cipher) *patientVisitQuerierRepo {
+	return &patientVisitQuerierRepo{queries: queries, cipher: cipher}
 }
 
 func (r *patientVisitQuerierRepo) CreatePatientVisit(ctx context.Context, arg db.CreatePatientVisitParams) (db.PatientVisit, error) {
-	return r.queries.CreatePatientVisit(ctx, arg)
+	if err := encryptVisitFields(ctx, r.cipher, &arg.Symptoms, &arg.Diagnosis, &arg.Prescription, &arg.Notes); err != nil {
+		return db.PatientVisit{}, err
+	}
+	visit, err := r.queries.CreatePatientVisit(ctx, arg)
+	return r.decrypt(ctx, visit, err)
 }
 
 func (r *patientVisitQuerierRepo) GetPatientVisitByID(ctx context.Context, id pgtype.UUID) (db.PatientVisit, error) {
-	return r.queries.GetPatientVisitByID(ctx, id)
+	visit, err := r.queries.GetPatientVisitByID(ctx, id)
+	return r.decrypt(ctx, visit, err)
 }
 
 func (r *patientVisitQuerierRepo) ListPatientVisitsByPatientID(ctx context.Context, arg db.ListPatientVisitsByPatientIDParams) ([]db.ListPatientVisitsByPatientIDRow, error) {
-	return r.queries.ListPatientVisitsByPatientID(ctx, arg)
+	visits, err := r.queries.ListPatientVisitsByPatientID(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	for i := range visits {
+		v := &visits[i]
+		if err := decryptVisitFields(ctx, r.cipher, &v.Symptoms, &v.Diagnosis, &v.Prescription, &v.Notes); err != nil {
+			return nil, err
+		}
+	}
+	return visits, nil
 }
 
 func (r *patientVisitQuerierRepo) ListPatientVisitsByDoctorID(ctx context.Context, arg db.ListPatientVisitsByDoctorIDParams) ([]db.ListPatientVisitsByDoctorIDRow, error) {
-	return r.queries.ListPatientVisitsByDoctorID(ctx, arg)
+	visits, err := r.queries.ListPatientVisitsByDoctorID(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	for i := range visits {
+		v := &visits[i]
+		if err := decryptVisitFields(ctx, r.cipher, &v.Symptoms, &v.Diagnosis, &v.Prescription, &v.Notes); err != nil {
+			return nil, err
+		}
+	}
+	return visits, nil
 }
 
 func (r *patientVisitQuerierRepo) UpdatePatientVisit(ctx context.Context, arg db.UpdatePatientVisitParams) (db.PatientVisit, error) {
-	return r.queries.UpdatePatientVisit(ctx, arg)
+	if err := encryptVisitFields(ctx, r.cipher, &arg.Symptoms, &arg.Diagnosis, &arg.Prescription, &arg.Notes); err != nil {
+		return db.PatientVisit{}, err
+	}
+	visit, err := r.queries.UpdatePatientVisit(ctx, arg)
+	return r.decrypt(ctx, visit, err)
 }
 func (r *patientVisitQuerierRepo) DeletePatientVisit(ctx context.Context, id pgtype.UUID) error {
 	return r.queries.DeletePatientVisit(ctx, id)
 }
 
-
-
+func (r *patientVisitQuerierRepo) decrypt(ctx context.Context, v db.PatientVisit, err error) (db.PatientVisit, error) {
+	if err != nil {
+		return v, err
+	}
+	if err := decryptVisitFields(ctx, r.cipher, &v.Symptoms, &v.Diagnosis, &v.Prescription, &v.Notes); err != nil {
+		return db.PatientVisit{}, err
+	}
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/fieldcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// visitRevisionRepo seals the previous versions of a visit under the same field names as
+// the visit itself, so they are read and re-encrypted the same way. Changes that write a
+// revision run through a TxManager.
+type visitRevisionRepo struct {
+	queries *db.Queries
+	cipher  *fieldcrypt.Cipher
+}
+
+func NewVisitRevisionRepo(queries *db.Queries, cipher *fieldcrypt.Cipher) VisitRevisionRepository {
+	return &visitRevisionRepo{queries: queries, cipher: cipher}
+}
+
+func (r *visitRevisionRepo) LockPatientVisit(ctx context.Context, id pgtype.UUID) (db.PatientVisit, error) {
+	visit, err := r.queries.LockPatientVisit(ctx, id)
+	return r.decryptVisit(ctx, visit, err)
+}
+
+func (r *visitRevisionRepo) UpdatePatientVisit(ctx context.Context, arg db.UpdatePatientVisitParams) (db.PatientVisit, error) {
+	if err := encryptVisitFields(ctx, r.cipher, &arg.Symptoms, &arg.Diagnosis, &arg.Prescription, &arg.Notes); err != nil {
+		return db.PatientVisit{}, err
+	}
+	visit, err := r.queries.UpdatePatientVisit(ctx, arg)
+	return r.decryptVisit(ctx, visit, err)
+}
+
+func (r *visitRevisionRepo) FinalizePatientVisit(ctx context.Context, arg db.FinalizePatientVisitParams) (db.PatientVisit, error) {
+	visit, err := r.queries.FinalizePatientVisit(ctx, arg)
+	return r.decryptVisit(ctx, visit, err)
+}
+
+func (r *visitRevisionRepo) CreatePatientVisitRevision(ctx context.Context, arg db.CreatePatientVisitRevisionParams) (db.PatientVisitRevision, error) {
+	if err := encryptVisitFields(ctx, r.cipher, &arg.PreviousSymptoms, &arg.PreviousDiagnosis, &arg.PreviousPrescription, &arg.PreviousNotes); err != nil {
+		return db.PatientVisitRevision{}, err
+	}
+	rev, err := r.queries.CreatePatientVisitRevision(ctx, arg)
+	if err != nil {
+		return rev, err
+	}
+	if err := decryptVisitFields(ctx, r.cipher, &rev.PreviousSymptoms, &rev.PreviousDiagnosis, &rev.PreviousPrescription, &rev.PreviousNotes); err != nil {
+		return db.PatientVisitRevision{}, err
+	}
+	return rev, nil
+}
+
+func (r *visitRevisionRepo) ListPatientVisitRevisions(ctx context.Context, visitID pgtype.UUID) ([]db.PatientVisitRevision, error) {
+	revs, err := r.queries.ListPatientVisitRevisions(ctx, visitID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revs {
+		rev := &revs[i]
+		if err := decryptVisitFields(ctx, r.cipher, &rev.PreviousSymptoms, &rev.PreviousDiagnosis, &rev.PreviousPrescription, &rev.PreviousNotes); err != nil {
+			return nil, err
+		}
+	}
+	return revs, nil
+}
+
+func (r *visitRevisionRepo) decryptVisit(ctx context.Context, v db.PatientVisit, err error) (db.PatientVisit, error) {
+	if err != nil {
+		return v, err
+	}
+	if err := decryptVisitFields(ctx, r.cipher, &v.Symptoms, &v.Diagnosis, &v.Prescription, &v.Notes); err != nil {
+		return db.PatientVisit{}, err
+	}
+	return v, nil
+}
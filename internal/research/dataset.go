@@ -0,0 +1,160 @@
+package research
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// FormatVersion is recorded in the manifest and bumped when the archive layout changes.
+const FormatVersion = 1
+
+// Format is the file format of the tables.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// Valid reports whether f is a known format.
+func (f Format) Valid() bool {
+	return f == FormatCSV || f == FormatParquet
+}
+
+// Archive file names; the tables take the extension of the format.
+const (
+	ManifestFile   = "manifest.json"
+	KAnonymityFile = "k_anonymity.json"
+	patientsTable  = "patients"
+	visitsTable    = "visits"
+)
+
+// Dataset is a de-identified export.
+type Dataset struct {
+	GeneratedAt time.Time
+	Options     Options
+	Patients    []PatientRow
+	Visits      []VisitRow
+	KAnonymity  KAnonymityReport
+}
+
+// Manifest describes the archive contents.
+type Manifest struct {
+	FormatVersion int            `json:"format_version"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	Dates         DateMode       `json:"dates"`
+	MaxShiftDays  int            `json:"max_shift_days,omitempty"`
+	Geography     GeographyMode  `json:"geography"`
+	FreeText      bool           `json:"free_text"`
+	Files         map[string]int `json:"files"` // Table file name to number of rows
+}
+
+// FileName is the suggested name of a dataset archive.
+func FileName(t time.Time) string {
+	return fmt.Sprintf("research-dataset-%s.zip", t.UTC().Format("20060102-150405"))
+}
+
+// WriteZip writes the dataset as a ZIP holding the patient and visit tables in the given
+// format, the k-anonymity report and a manifest.
+func WriteZip(w io.Writer, d Dataset, format Format) error {
+	if !format.Valid() {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	patientsFile := patientsTable + "." + string(format)
+	visitsFile := visitsTable + "." + string(format)
+
+	zw := zip.NewWriter(w)
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		GeneratedAt:   d.GeneratedAt,
+		Dates:         d.Options.Dates,
+		Geography:     d.Options.Geography,
+		FreeText:      d.Options.FreeText,
+		Files:         map[string]int{patientsFile: len(d.Patients), visitsFile: len(d.Visits)},
+	}
+	if d.Options.Dates == DatesShift {
+		manifest.MaxShiftDays = d.Options.MaxShiftDays
+	}
+	if err := writeJSON(zw, ManifestFile, manifest); err != nil {
+		return err
+	}
+	if err := writeJSON(zw, KAnonymityFile, d.KAnonymity); err != nil {
+		return err
+	}
+
+	f, err := zw.Create(patientsFile)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", patientsFile, err)
+	}
+	if err := writeTable(f, format, d.Patients); err != nil {
+		return fmt.Errorf("write %s: %w", patientsFile, err)
+	}
+	f, err = zw.Create(visitsFile)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", visitsFile, err)
+	}
+	if err := writeTable(f, format, d.Visits); err != nil {
+		return fmt.Errorf("write %s: %w", visitsFile, err)
+	}
+	return zw.Close()
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// row is a table row that can also be written as CSV.
+type row interface {
+	PatientRow | VisitRow
+	csvHeader() []string
+	csvRecord() []string
+}
+
+func writeTable[T row](w io.Writer, format Format, rows []T) error {
+	if format == FormatParquet {
+		return parquet.Write(w, rows)
+	}
+	cw := csv.NewWriter(w)
+	var zero T
+	if err := cw.Write(zero.csvHeader()); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.csvRecord()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (PatientRow) csvHeader() []string {
+	return []string{"research_id", "birth_year", "age", "gender", "region"}
+}
+
+func (r PatientRow) csvRecord() []string {
+	return []string{r.ResearchID, r.BirthYear, r.Age, r.Gender, r.Region}
+}
+
+func (VisitRow) csvHeader() []string {
+	return []string{"research_visit_id", "research_id", "visit_date", "age_at_visit", "diagnosis", "prescription", "symptoms", "notes"}
+}
+
+func (r VisitRow) csvRecord() []string {
+	return []string{r.ResearchVisitID, r.ResearchID, r.VisitDate, r.AgeAtVisit, r.Diagnosis, r.Prescription, r.Symptoms, r.Notes}
+}
@@ -0,0 +1,95 @@
+package research
+
+import (
+	"sort"
+	"strings"
+)
+
+// QuasiIdentifiers are the patient columns that, combined, could single someone out when
+// linked with outside data.
+var QuasiIdentifiers = []string{"birth_year", "age", "gender", "region"}
+
+// maxReportedClasses caps how many undersized groups a report lists.
+const maxReportedClasses = 100
+
+// EquivalenceClass is a group of patients sharing the same quasi-identifiers.
+type EquivalenceClass struct {
+	Values map[string]string `json:"values"`
+	Size   int               `json:"size"`
+}
+
+// KAnonymityReport says whether every patient shares their quasi-identifiers with at least
+// K-1 others.
+type KAnonymityReport struct {
+	K                  int      `json:"k"`
+	QuasiIdentifiers   []string `json:"quasi_identifiers"`
+	Patients           int      `json:"patients"`
+	EquivalenceClasses int      `json:"equivalence_classes"`
+	SmallestClass      int      `json:"smallest_class"`
+	Satisfied          bool     `json:"satisfied"`
+	// PatientsAtRisk are in groups smaller than K.
+	PatientsAtRisk int `json:"patients_at_risk"`
+	// SmallClasses lists the groups smaller than K, smallest first, up to 100.
+	SmallClasses []EquivalenceClass `json:"small_classes"`
+}
+
+func (r PatientRow) quasiIdentifiers() []string {
+	return []string{r.BirthYear, r.Age, r.Gender, r.Region}
+}
+
+// CheckKAnonymity groups patients by their quasi-identifiers and reports the groups
+// smaller than k.
+func CheckKAnonymity(patients []PatientRow, k int) KAnonymityReport {
+	counts := map[string]int{}
+	values := map[string][]string{}
+	for _, p := range patients {
+		qi := p.quasiIdentifiers()
+		key := strings.Join(qi, "\x00")
+		counts[key]++
+		values[key] = qi
+	}
+
+	report := KAnonymityReport{
+		K:                  k,
+		QuasiIdentifiers:   QuasiIdentifiers,
+		Patients:           len(patients),
+		EquivalenceClasses: len(counts),
+		SmallClasses:       []EquivalenceClass{},
+	}
+	var small []EquivalenceClass
+	for key, size := range counts {
+		if report.SmallestClass == 0 || size < report.SmallestClass {
+			report.SmallestClass = size
+		}
+		if size >= k {
+			continue
+		}
+		report.PatientsAtRisk += size
+		class := EquivalenceClass{Values: map[string]string{}, Size: size}
+		for i, name := range QuasiIdentifiers {
+			class.Values[name] = values[key][i]
+		}
+		small = append(small, class)
+	}
+	report.Satisfied = report.PatientsAtRisk == 0
+
+	sort.Slice(small, func(i, j int) bool {
+		if small[i].Size != small[j].Size {
+			return small[i].Size < small[j].Size
+		}
+		return classKey(small[i]) < classKey(small[j])
+	})
+	if len(small) > maxReportedClasses {
+		small = small[:maxReportedClasses]
+	}
+	report.SmallClasses = append(report.SmallClasses, small...)
+	return report
+}
+
+func classKey(c EquivalenceClass) string {
+	parts := make([]string, len(QuasiIdentifiers))
+	for i, name := range QuasiIdentifiers {
+		parts[i] = c.Values[name]
+	}
+	return strings.Join(parts, "\x00")
+}
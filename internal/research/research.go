@@ -0,0 +1,285 @@
+// Package research de-identifies patients and their visits for the research department,
+// following the HIPAA Safe Harbor method: direct identifiers are dropped, dates are cut to
+// the year or shifted by a per-patient offset, ages over 89 are reported as "90+" and the
+// address is reduced to the first three digits of its postal code. Patients and visits get
+// pseudonymous IDs derived from a secret key, so the same patient has the same ID in every
+// export made with that key and researchers can link exports without learning who it is.
+package research
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// MinKeyBytes is the shortest secret accepted for deriving pseudonymous IDs.
+const MinKeyBytes = 32
+
+// TopCodedAge replaces every age over 89.
+const TopCodedAge = "90+"
+
+// maxAge is the highest age reported as a number.
+const maxAge = 89
+
+// idBytes of the keyed hash make a pseudonymous ID.
+const idBytes = 10
+
+// ErrInvalidKey rejects research keys that are missing, malformed or too short.
+var ErrInvalidKey = errors.New("research key must be at least 32 bytes, base64 encoded")
+
+// DateMode is how dates are de-identified.
+type DateMode string
+
+const (
+	// DatesYear keeps only the year of each date.
+	DatesYear DateMode = "year"
+	// DatesShift moves every date of a patient by the same random number of days, keeping
+	// the intervals between visits.
+	DatesShift DateMode = "shift"
+)
+
+// GeographyMode is how the address is generalized.
+type GeographyMode string
+
+const (
+	// GeographyPostal3 keeps the first three digits of the postal code.
+	GeographyPostal3 GeographyMode = "postal3"
+	// GeographyNone drops the address entirely.
+	GeographyNone GeographyMode = "none"
+)
+
+// Options configure a de-identified export. The zero value is not valid; start from
+// DefaultOptions.
+type Options struct {
+	Dates DateMode
+	// MaxShiftDays bounds the date shift in DatesShift mode, in either direction.
+	MaxShiftDays int
+	Geography    GeographyMode
+	// FreeText includes visit symptoms, diagnoses, prescriptions and notes. They are
+	// written by hand and may name people or places, so they are left out unless asked for.
+	FreeText bool
+	// K is the smallest group of patients that may share the same quasi-identifiers.
+	K int
+}
+
+// DefaultOptions cut dates to the year, keep three-digit postal areas, leave out free text
+// and check for 5-anonymity.
+func DefaultOptions() Options {
+	return Options{Dates: DatesYear, MaxShiftDays: 365, Geography: GeographyPostal3, K: 5}
+}
+
+// Validate rejects options that are unknown or out of range.
+func (o Options) Validate() error {
+	if o.Dates != DatesYear && o.Dates != DatesShift {
+		return fmt.Errorf("unknown date mode %q", o.Dates)
+	}
+	if o.Dates == DatesShift && (o.MaxShiftDays < 1 || o.MaxShiftDays > 3650) {
+		return fmt.Errorf("max shift must be between 1 and 3650 days")
+	}
+	if o.Geography != GeographyPostal3 && o.Geography != GeographyNone {
+		return fmt.Errorf("unknown geography mode %q", o.Geography)
+	}
+	if o.K < 2 {
+		return fmt.Errorf("k must be at least 2")
+	}
+	return nil
+}
+
+// ParseKey decodes a base64 research key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) < MinKeyBytes {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Pseudonymizer derives stable pseudonymous IDs and date shifts from a secret key. Without
+// the key they cannot be linked back to patients, so it must not be given to researchers.
+type Pseudonymizer struct {
+	key []byte
+}
+
+// NewPseudonymizer returns a Pseudonymizer for the key.
+func NewPseudonymizer(key []byte) (*Pseudonymizer, error) {
+	if len(key) < MinKeyBytes {
+		return nil, ErrInvalidKey
+	}
+	return &Pseudonymizer{key: append([]byte(nil), key...)}, nil
+}
+
+// sum is the keyed hash of an ID under a purpose label, so the same ID hashes differently
+// for each purpose.
+func (p *Pseudonymizer) sum(purpose string, id uuid.UUID) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write(id[:])
+	return mac.Sum(nil)
+}
+
+var idEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// PatientID returns the pseudonymous ID of a patient, such as "RP-7KQ2M9XD4RJ5TW3A".
+func (p *Pseudonymizer) PatientID(id uuid.UUID) string {
+	return "RP-" + idEncoding.EncodeToString(p.sum("patient", id)[:idBytes])
+}
+
+// VisitID returns the pseudonymous ID of a visit.
+func (p *Pseudonymizer) VisitID(id uuid.UUID) string {
+	return "RV-" + idEncoding.EncodeToString(p.sum("visit", id)[:idBytes])
+}
+
+// ShiftDays returns the patient's date shift: a non-zero number of days between -max and
+// max that is the same in every export made with the key.
+func (p *Pseudonymizer) ShiftDays(patientID uuid.UUID, max int) int {
+	n := binary.BigEndian.Uint64(p.sum("date-shift", patientID)[:8])
+	days := int(n%uint64(max)) + 1
+	if n&(1<<63) != 0 {
+		days = -days
+	}
+	return days
+}
+
+// Age returns the age in whole years on the given day.
+func Age(dob, on time.Time) int {
+	age := on.Year() - dob.Year()
+	if on.Month() < dob.Month() || (on.Month() == dob.Month() && on.Day() < dob.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+// TopCode reports ages over 89 as "90+".
+func TopCode(age int) string {
+	if age > maxAge {
+		return TopCodedAge
+	}
+	return strconv.Itoa(age)
+}
+
+var postalCode = regexp.MustCompile(`\b\d{5,6}\b`)
+
+// restrictedZIP3 are the three-digit US ZIP areas with 20,000 people or fewer, which Safe
+// Harbor requires be reported as "000".
+var restrictedZIP3 = map[string]bool{
+	"036": true, "059": true, "063": true, "102": true, "203": true, "556": true,
+	"692": true, "790": true, "821": true, "823": true, "830": true, "831": true,
+	"878": true, "879": true, "884": true, "890": true, "893": true,
+}
+
+// GeneralizeAddress returns the first three digits of the last postal code in a free-text
+// address, or "" when it has none. Sparsely populated US ZIP areas become "000".
+func GeneralizeAddress(address string) string {
+	codes := postalCode.FindAllString(address, -1)
+	if len(codes) == 0 {
+		return ""
+	}
+	code := codes[len(codes)-1]
+	area := code[:3]
+	if len(code) == 5 && restrictedZIP3[area] {
+		return "000"
+	}
+	return area
+}
+
+// PatientRow is a de-identified patient.
+type PatientRow struct {
+	ResearchID string `json:"research_id" parquet:"research_id"`
+	// BirthYear is empty for patients over 89 and when dates are shifted.
+	BirthYear string `json:"birth_year" parquet:"birth_year"`
+	Age       string `json:"age" parquet:"age"` // On the day of the export
+	Gender    string `json:"gender" parquet:"gender"`
+	Region    string `json:"region" parquet:"region"` // Three-digit postal area
+}
+
+// VisitRow is a de-identified visit.
+type VisitRow struct {
+	ResearchVisitID string `json:"research_visit_id" parquet:"research_visit_id"`
+	ResearchID      string `json:"research_id" parquet:"research_id"`
+	// VisitDate is the year, or the shifted date as YYYY-MM-DD.
+	VisitDate    string `json:"visit_date" parquet:"visit_date"`
+	AgeAtVisit   string `json:"age_at_visit" parquet:"age_at_visit"`
+	Diagnosis    string `json:"diagnosis" parquet:"diagnosis"`       // Only with Options.FreeText
+	Prescription string `json:"prescription" parquet:"prescription"` // Only with Options.FreeText
+	Symptoms     string `json:"symptoms" parquet:"symptoms"`         // Only with Options.FreeText
+	Notes        string `json:"notes" parquet:"notes"`               // Only with Options.FreeText
+}
+
+// Deidentifier turns patients and visits into rows under a set of options.
+type Deidentifier struct {
+	opts Options
+	ids  *Pseudonymizer
+	now  time.Time
+}
+
+// NewDeidentifier returns a Deidentifier computing ages as of now.
+func NewDeidentifier(opts Options, ids *Pseudonymizer, now time.Time) (*Deidentifier, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &Deidentifier{opts: opts, ids: ids, now: now.UTC()}, nil
+}
+
+// Patient de-identifies a patient.
+func (d *Deidentifier) Patient(p model.Patient) PatientRow {
+	age := Age(p.DateOfBirth, d.now)
+	row := PatientRow{ResearchID: d.ids.PatientID(p.ID), Age: TopCode(age)}
+	if d.opts.Dates == DatesYear && age <= maxAge {
+		row.BirthYear = strconv.Itoa(p.DateOfBirth.Year())
+	}
+	if p.Gender != nil {
+		row.Gender = string(*p.Gender)
+	}
+	if d.opts.Geography == GeographyPostal3 && p.Address != nil {
+		row.Region = GeneralizeAddress(*p.Address)
+	}
+	return row
+}
+
+// Visit de-identifies a visit of patient p. In year mode a patient now over 89 is "90+"
+// at every visit too, as the visit year and age would give away their birth year.
+func (d *Deidentifier) Visit(p model.Patient, v model.PatientVisit) VisitRow {
+	row := VisitRow{
+		ResearchVisitID: d.ids.VisitID(v.ID),
+		ResearchID:      d.ids.PatientID(p.ID),
+		AgeAtVisit:      TopCode(Age(p.DateOfBirth, v.VisitDate)),
+	}
+	visitDate := v.VisitDate.UTC()
+	switch d.opts.Dates {
+	case DatesShift:
+		row.VisitDate = visitDate.AddDate(0, 0, d.ids.ShiftDays(p.ID, d.opts.MaxShiftDays)).Format("2006-01-02")
+	default:
+		row.VisitDate = strconv.Itoa(visitDate.Year())
+		if Age(p.DateOfBirth, d.now) > maxAge {
+			row.AgeAtVisit = TopCodedAge
+		}
+	}
+	if d.opts.FreeText {
+		row.Diagnosis = deref(v.Diagnosis)
+		row.Prescription = deref(v.Prescription)
+		row.Symptoms = deref(v.Symptoms)
+		row.Notes = deref(v.Notes)
+	}
+	return row
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
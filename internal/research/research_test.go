@@ -0,0 +1,227 @@
+package research
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte{7}, MinKeyBytes)
+
+func newTestPseudonymizer(t *testing.T, key []byte) *Pseudonymizer {
+	t.Helper()
+	p, err := NewPseudonymizer(key)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPseudonymousIDsAreStableAndKeyed(t *testing.T) {
+	id := uuid.New()
+	a := newTestPseudonymizer(t, testKey)
+	b := newTestPseudonymizer(t, testKey)
+	other := newTestPseudonymizer(t, bytes.Repeat([]byte{8}, MinKeyBytes))
+
+	assert.Equal(t, a.PatientID(id), b.PatientID(id))
+	assert.NotEqual(t, a.PatientID(id), other.PatientID(id))
+	assert.NotEqual(t, a.PatientID(id), a.PatientID(uuid.New()))
+	assert.Regexp(t, `^RP-[A-Z2-7]{16}$`, a.PatientID(id))
+	assert.Regexp(t, `^RV-[A-Z2-7]{16}$`, a.VisitID(id))
+
+	_, err := NewPseudonymizer([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey("BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc=")
+	require.NoError(t, err)
+	assert.Equal(t, testKey, key)
+
+	_, err = ParseKey("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ParseKey("c2hvcnQ=")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestShiftDays(t *testing.T) {
+	p := newTestPseudonymizer(t, testKey)
+	seen := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		id := uuid.New()
+		days := p.ShiftDays(id, 30)
+		assert.NotZero(t, days)
+		assert.LessOrEqual(t, days, 30)
+		assert.GreaterOrEqual(t, days, -30)
+		assert.Equal(t, days, p.ShiftDays(id, 30))
+		seen[days] = true
+	}
+	assert.Greater(t, len(seen), 20)
+}
+
+func TestAgeAndTopCode(t *testing.T) {
+	dob := time.Date(1990, time.June, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 35, Age(dob, time.Date(2026, time.June, 14, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 36, Age(dob, time.Date(2026, time.June, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Age(dob, time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "89", TopCode(89))
+	assert.Equal(t, "90+", TopCode(90))
+	assert.Equal(t, "90+", TopCode(104))
+}
+
+func TestGeneralizeAddress(t *testing.T) {
+	assert.Equal(t, "560", GeneralizeAddress("12 MG Road, Bengaluru 560001"))
+	assert.Equal(t, "021", GeneralizeAddress("1 Main St, Boston, MA 02108"))
+	assert.Equal(t, "000", GeneralizeAddress("PO Box 4, Hanover, NH 03640"))
+	assert.Equal(t, "945", GeneralizeAddress("Flat 10001, Oakland 94501"))
+	assert.Equal(t, "", GeneralizeAddress("Somewhere without a code"))
+}
+
+func TestDeidentifier(t *testing.T) {
+	p := newTestPseudonymizer(t, testKey)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	gender := model.Gender("female")
+	address := "42 Lake View, Pune 411001"
+	diagnosis := "J45 asthma"
+	notes := "Seen with her husband Ravi"
+	patient := model.Patient{
+		ID:          uuid.New(),
+		FirstName:   "Asha",
+		LastName:    "Rao",
+		DateOfBirth: time.Date(1980, time.March, 2, 0, 0, 0, 0, time.UTC),
+		Gender:      &gender,
+		Address:     &address,
+	}
+	visit := model.PatientVisit{ID: uuid.New(), PatientID: patient.ID, VisitDate: time.Date(2025, time.July, 1, 9, 30, 0, 0, time.UTC), Diagnosis: &diagnosis, Notes: &notes}
+
+	d, err := NewDeidentifier(DefaultOptions(), p, now)
+	require.NoError(t, err)
+	assert.Equal(t, PatientRow{ResearchID: p.PatientID(patient.ID), BirthYear: "1980", Age: "46", Gender: "female", Region: "411"}, d.Patient(patient))
+	row := d.Visit(patient, visit)
+	assert.Equal(t, "2025", row.VisitDate)
+	assert.Equal(t, "45", row.AgeAtVisit)
+	assert.Empty(t, row.Diagnosis)
+	assert.Empty(t, row.Notes)
+
+	old := patient
+	old.DateOfBirth = time.Date(1930, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Empty(t, d.Patient(old).BirthYear)
+	assert.Equal(t, TopCodedAge, d.Patient(old).Age)
+	// 60 at a visit in 1990 would give away the birth year.
+	oldVisit := visit
+	oldVisit.VisitDate = time.Date(1990, time.June, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, TopCodedAge, d.Visit(old, oldVisit).AgeAtVisit)
+
+	opts := DefaultOptions()
+	opts.Dates = DatesShift
+	opts.MaxShiftDays = 30
+	opts.Geography = GeographyNone
+	opts.FreeText = true
+	d, err = NewDeidentifier(opts, p, now)
+	require.NoError(t, err)
+	shifted := visit.VisitDate.AddDate(0, 0, p.ShiftDays(patient.ID, 30)).Format("2006-01-02")
+	row = d.Visit(patient, visit)
+	assert.Equal(t, shifted, row.VisitDate)
+	assert.Equal(t, notes, row.Notes)
+	assert.Equal(t, diagnosis, row.Diagnosis)
+	assert.Empty(t, d.Patient(patient).BirthYear)
+	assert.Empty(t, d.Patient(patient).Region)
+
+	opts.Dates = "month"
+	_, err = NewDeidentifier(opts, p, now)
+	assert.Error(t, err)
+}
+
+func TestCheckKAnonymity(t *testing.T) {
+	rows := []PatientRow{
+		{ResearchID: "a", BirthYear: "1980", Age: "46", Gender: "female", Region: "411"},
+		{ResearchID: "b", BirthYear: "1980", Age: "46", Gender: "female", Region: "411"},
+		{ResearchID: "c", BirthYear: "1980", Age: "46", Gender: "female", Region: "411"},
+		{ResearchID: "d", BirthYear: "1955", Age: "71", Gender: "male", Region: "560"},
+	}
+	report := CheckKAnonymity(rows, 3)
+	assert.False(t, report.Satisfied)
+	assert.Equal(t, 4, report.Patients)
+	assert.Equal(t, 2, report.EquivalenceClasses)
+	assert.Equal(t, 1, report.SmallestClass)
+	assert.Equal(t, 1, report.PatientsAtRisk)
+	require.Len(t, report.SmallClasses, 1)
+	assert.Equal(t, map[string]string{"birth_year": "1955", "age": "71", "gender": "male", "region": "560"}, report.SmallClasses[0].Values)
+
+	report = CheckKAnonymity(rows[:3], 3)
+	assert.True(t, report.Satisfied)
+	assert.Empty(t, report.SmallClasses)
+}
+
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = body
+	}
+	return files
+}
+
+func testDataset() Dataset {
+	patients := []PatientRow{{ResearchID: "RP-A", BirthYear: "1980", Age: "46", Gender: "female", Region: "411"}}
+	return Dataset{
+		GeneratedAt: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC),
+		Options:     DefaultOptions(),
+		Patients:    patients,
+		Visits:      []VisitRow{{ResearchVisitID: "RV-A", ResearchID: "RP-A", VisitDate: "2025", AgeAtVisit: "45", Diagnosis: "J45, asthma"}},
+		KAnonymity:  CheckKAnonymity(patients, 5),
+	}
+}
+
+func TestWriteZipCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(&buf, testDataset(), FormatCSV))
+	files := readZip(t, buf.Bytes())
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(files[ManifestFile], &manifest))
+	assert.Equal(t, map[string]int{"patients.csv": 1, "visits.csv": 1}, manifest.Files)
+	assert.Equal(t, DatesYear, manifest.Dates)
+
+	var report KAnonymityReport
+	require.NoError(t, json.Unmarshal(files[KAnonymityFile], &report))
+	assert.False(t, report.Satisfied)
+
+	records, err := csv.NewReader(bytes.NewReader(files["visits.csv"])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "research_visit_id", records[0][0])
+	assert.Equal(t, "J45, asthma", records[1][4])
+}
+
+func TestWriteZipParquet(t *testing.T) {
+	var buf bytes.Buffer
+	d := testDataset()
+	require.NoError(t, WriteZip(&buf, d, FormatParquet))
+	files := readZip(t, buf.Bytes())
+
+	patients, err := parquet.Read[PatientRow](bytes.NewReader(files["patients.parquet"]), int64(len(files["patients.parquet"])))
+	require.NoError(t, err)
+	assert.Equal(t, d.Patients, patients)
+	visits, err := parquet.Read[VisitRow](bytes.NewReader(files["visits.parquet"]), int64(len(files["visits.parquet"])))
+	require.NoError(t, err)
+	assert.Equal(t, d.Visits, visits)
+
+	assert.Error(t, WriteZip(&buf, d, "xlsx"))
+}
@@ -0,0 +1,166 @@
+// Package sar assembles the archive answering a subject access request: everything held
+// about a patient as JSON files, plus an HTML report the patient can read.
+package sar
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// DownloadTTL is how long a finished archive can be downloaded before it is deleted.
+const DownloadTTL = 72 * time.Hour
+
+// StaleAfter is how long a job may run before another worker assumes it crashed and
+// takes it over.
+const StaleAfter = 30 * time.Minute
+
+// MaxAttempts is how many times a job is started before it is marked failed.
+const MaxAttempts = 3
+
+// FormatVersion is recorded in the manifest and bumped when the archive layout changes.
+const FormatVersion = 1
+
+// Bundle is everything held about one patient.
+type Bundle struct {
+	ExportID          uuid.UUID
+	GeneratedAt       time.Time
+	Patient           model.Patient
+	Visits            []model.PatientVisit
+	LabResults        []model.CumulativeLabResult
+	InsurancePolicies []model.InsurancePolicy
+	Consents          []model.ConsentRecord
+	AccessLog         []model.AccessLogEntry
+}
+
+// Manifest describes the archive contents.
+type Manifest struct {
+	FormatVersion int            `json:"format_version"`
+	ExportID      uuid.UUID      `json:"export_id"`
+	PatientID     uuid.UUID      `json:"patient_id"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	Files         map[string]int `json:"files"` // JSON file name to number of records
+	// Attachments are not stored by this system, so none are included.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Archive file names.
+const (
+	ManifestFile    = "manifest.json"
+	PatientFile     = "patient.json"
+	VisitsFile      = "visits.json"
+	LabResultsFile  = "lab_results.json"
+	InsuranceFile   = "insurance_policies.json"
+	ConsentsFile    = "consents.json"
+	AccessLogFile   = "access_log.json"
+	ReportFile      = "report.html"
+	attachmentsNote = "No document attachments are held for this patient; the system does not store files."
+)
+
+//go:embed report.html.tmpl
+var templates embed.FS
+
+var reportTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+	"datetime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+	"text": func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	},
+	"number": func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return fmt.Sprintf("%g", *f)
+	},
+}).ParseFS(templates, "report.html.tmpl"))
+
+// WriteZip writes the bundle to w as a ZIP archive.
+func WriteZip(w io.Writer, b Bundle) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name  string
+		data  interface{}
+		count int
+	}{
+		{PatientFile, b.Patient, 1},
+		{VisitsFile, nonNil(b.Visits), len(b.Visits)},
+		{LabResultsFile, nonNil(b.LabResults), len(b.LabResults)},
+		{InsuranceFile, nonNil(b.InsurancePolicies), len(b.InsurancePolicies)},
+		{ConsentsFile, nonNil(b.Consents), len(b.Consents)},
+		{AccessLogFile, nonNil(b.AccessLog), len(b.AccessLog)},
+	}
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		ExportID:      b.ExportID,
+		PatientID:     b.Patient.ID,
+		GeneratedAt:   b.GeneratedAt.UTC(),
+		Files:         make(map[string]int, len(files)),
+		Notes:         []string{attachmentsNote},
+	}
+	for _, f := range files {
+		manifest.Files[f.name] = f.count
+	}
+	if err := writeJSON(zw, ManifestFile, b.GeneratedAt, manifest); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := writeJSON(zw, f.name, b.GeneratedAt, f.data); err != nil {
+			return err
+		}
+	}
+
+	var report bytes.Buffer
+	if err := reportTemplate.Execute(&report, struct {
+		Bundle
+		AttachmentsNote string
+	}{b, attachmentsNote}); err != nil {
+		return fmt.Errorf("render report: %w", err)
+	}
+	if err := writeFile(zw, ReportFile, b.GeneratedAt, report.Bytes()); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// FileName is the download name of a patient's archive.
+func FileName(patientID uuid.UUID, generatedAt time.Time) string {
+	return fmt.Sprintf("patient-%s-%s.zip", patientID, generatedAt.UTC().Format("20060102"))
+}
+
+func writeJSON(zw *zip.Writer, name string, modified time.Time, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	return writeFile(zw, name, modified, data)
+}
+
+func writeFile(zw *zip.Writer, name string, modified time.Time, data []byte) error {
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified.UTC()})
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// nonNil makes empty sections encode as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
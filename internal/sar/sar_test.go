@@ -0,0 +1,77 @@
+package sar
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = body
+	}
+	return files
+}
+
+func TestWriteZip(t *testing.T) {
+	history := "<script>alert(1)</script>"
+	diagnosis := "J45 asthma"
+	b := Bundle{
+		ExportID:    uuid.New(),
+		GeneratedAt: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC),
+		Patient: model.Patient{
+			ID:             uuid.New(),
+			FirstName:      "Asha",
+			LastName:       "Rao",
+			MedicalHistory: &history,
+		},
+		Visits: []model.PatientVisit{{ID: uuid.New(), Diagnosis: &diagnosis}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(&buf, b))
+	files := readZip(t, buf.Bytes())
+
+	for _, name := range []string{ManifestFile, PatientFile, VisitsFile, LabResultsFile, InsuranceFile, ConsentsFile, AccessLogFile, ReportFile} {
+		assert.Contains(t, files, name)
+	}
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(files[ManifestFile], &manifest))
+	assert.Equal(t, b.Patient.ID, manifest.PatientID)
+	assert.Equal(t, 1, manifest.Files[VisitsFile])
+	assert.Equal(t, 0, manifest.Files[LabResultsFile])
+
+	var patient model.Patient
+	require.NoError(t, json.Unmarshal(files[PatientFile], &patient))
+	assert.Equal(t, "Asha", patient.FirstName)
+	assert.JSONEq(t, "[]", string(files[ConsentsFile]), "empty sections are empty lists")
+
+	report := string(files[ReportFile])
+	assert.Contains(t, report, "J45 asthma")
+	assert.NotContains(t, report, "<script>", "report escapes record contents")
+	assert.Contains(t, report, "No lab results recorded.")
+}
+
+func TestFileName(t *testing.T) {
+	id := uuid.MustParse("6f1c0c3e-8f5a-4d7e-9a61-2b8f3c9d1e20")
+	assert.Equal(t, "patient-6f1c0c3e-8f5a-4d7e-9a61-2b8f3c9d1e20-20260304.zip",
+		FileName(id, time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC)))
+}
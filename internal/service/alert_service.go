@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/alerting"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrAlertNotFound = errors.New("alert not found")
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+var ErrAlertAlreadyAcknowledged = errors.New("alert already acknowledged")
+var ErrAlertAcknowledgeForbidden = errors.New("not authorized to acknowledge this alert")
+
+const (
+	// alertEscalationLockKey is the Postgres advisory lock held while escalating,
+	// so only one replica runs an escalation pass at a time.
+	alertEscalationLockKey int64 = 0x484d5301
+	// maxEscalationLevel stops re-escalating an alert after it has been handed on this many times.
+	maxEscalationLevel = 3
+	// escalationBatchSize bounds the number of alerts handled per pass.
+	escalationBatchSize = 100
+	// defaultEscalateAfterMinutes applies when a rule does not specify its own delay.
+	defaultEscalateAfterMinutes = 30
+)
+
+type alertService struct {
+	alertRepo   repository.AlertRepository
+	visitRepo   repository.PatientVisitQuerier
+	patientRepo repository.PatientRepository
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
+}
+
+func NewAlertService(alertRepo repository.AlertRepository, visitRepo repository.PatientVisitQuerier, patientRepo repository.PatientRepository, access PatientAccessAuthorizer, logger *slog.Logger) AlertService {
+	return &alertService{alertRepo: alertRepo, visitRepo: visitRepo, patientRepo: patientRepo, access: access, logger: logger.With("service", "AlertService")}
+}
+
+func (s *alertService) RecordObservation(ctx context.Context, visitID uuid.UUID, req model.ObservationCreateRequest, recordedByUserID uuid.UUID) (*model.Observation, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.RecordObservation")
+	defer span.End()
+	observedAt := time.Now()
+	if req.ObservedAtStr != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ObservedAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid observed_at format: %w. Expected RFC3339", err)
+		}
+		observedAt = parsed
+	}
+
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking visit for new observation", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("error verifying visit for observation: %w", err)
+	}
+	ctx, _, err = s.access.AuthorizePatientAccess(ctx, uuid.UUID(visit.PatientID.Bytes))
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+
+	observation, err := s.alertRepo.CreateVisitObservation(ctx, db.CreateVisitObservationParams{
+		VisitID:          pgtype.UUID{Bytes: visitID, Valid: true},
+		Code:             req.Code,
+		Value:            *req.Value,
+		Unit:             pgtype.Text{String: derefString(req.Unit), Valid: req.Unit != nil},
+		ObservedAt:       pgtype.Timestamptz{Time: observedAt, Valid: true},
+		RecordedByUserID: pgtype.UUID{Bytes: recordedByUserID, Valid: true},
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key constraint") {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to record observation", "code", req.Code, "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to record observation: %w", err)
+	}
+
+	mapped := mapper.MapObservation(observation)
+	alert, err := s.EvaluateObservation(ctx, visitID, model.ObservationSourceVitalSign, req.Code, *req.Value)
+	if err != nil {
+		// The observation is stored; a failed evaluation must not lose it.
+		s.logger.ErrorContext(ctx, "Failed to evaluate observation", "observation_id", observation.ID, "visit_id", visitID, "error", err)
+	}
+	mapped.Alert = alert
+	return &mapped, nil
+}
+
+func (s *alertService) EvaluateObservation(ctx context.Context, visitID uuid.UUID, source model.ObservationSource, code string, value float64) (*model.Alert, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.EvaluateObservation")
+	defer span.End()
+	rules, err := s.alertRepo.ListActiveAlertRulesByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, fmt.Errorf("failed to load visit for alert evaluation: %w", err)
+	}
+	patient, err := s.patientRepo.GetPatientByID(ctx, visit.PatientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load patient for alert evaluation: %w", err)
+	}
+
+	subject := alerting.Subject{AgeYears: alerting.AgeInYears(patient.DateOfBirth.Time, time.Now())}
+	if patient.Gender.Valid {
+		g := model.Gender(patient.Gender.GenderEnum)
+		subject.Sex = &g
+	}
+	engineRules := make([]alerting.Rule, 0, len(rules))
+	for _, r := range rules {
+		engineRules = append(engineRules, mapper.MapAlertingRule(r))
+	}
+
+	breach, ok := alerting.Evaluate(engineRules, code, subject, value)
+	if !ok {
+		return nil, nil
+	}
+
+	escalateAfter := breach.Rule.EscalateAfter
+	if escalateAfter <= 0 {
+		escalateAfter = defaultEscalateAfterMinutes * time.Minute
+	}
+	alert, err := s.alertRepo.CreateAlert(ctx, db.CreateAlertParams{
+		RuleID:           pgtype.UUID{Bytes: breach.Rule.ID, Valid: true},
+		VisitID:          visit.ID,
+		PatientID:        visit.PatientID,
+		Source:           db.ObservationSource(source),
+		ObservationCode:  code,
+		ObservedValue:    value,
+		ThresholdValue:   breach.Threshold,
+		Direction:        string(breach.Direction),
+		Message:          breach.Message(),
+		OriginalDoctorID: visit.DoctorID,
+		EscalateAt:       pgtype.Timestamptz{Time: time.Now().Add(escalateAfter), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+	s.logger.WarnContext(ctx, "Critical alert raised", "code", code, "alert_id", alert.ID, "visit_id", visitID, "doctor_id", visit.DoctorID)
+
+	mapped := mapper.MapAlert(alert)
+	return &mapped, nil
+}
+
+func (s *alertService) CreateAlertRule(ctx context.Context, req model.AlertRuleCreateRequest, createdByUserID uuid.UUID) (*model.AlertRule, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.CreateAlertRule")
+	defer span.End()
+	escalateAfter := req.EscalateAfterMinutes
+	if escalateAfter == 0 {
+		escalateAfter = defaultEscalateAfterMinutes
+	}
+	params := db.CreateAlertRuleParams{
+		ObservationCode:      req.ObservationCode,
+		Name:                 req.Name,
+		CriticalLow:          float8FromPtr(req.CriticalLow),
+		CriticalHigh:         float8FromPtr(req.CriticalHigh),
+		EscalateAfterMinutes: int32(escalateAfter),
+		CreatedByUserID:      pgtype.UUID{Bytes: createdByUserID, Valid: true},
+	}
+	if req.Sex != nil {
+		params.Sex = db.NullGenderEnum{GenderEnum: db.GenderEnum(*req.Sex), Valid: true}
+	}
+	if req.MinAgeYears != nil {
+		params.MinAgeYears = pgtype.Int4{Int32: int32(*req.MinAgeYears), Valid: true}
+	}
+	if req.MaxAgeYears != nil {
+		params.MaxAgeYears = pgtype.Int4{Int32: int32(*req.MaxAgeYears), Valid: true}
+	}
+	if req.EscalationDoctorID != nil {
+		params.EscalationDoctorID = pgtype.UUID{Bytes: *req.EscalationDoctorID, Valid: true}
+	}
+
+	rule, err := s.alertRepo.CreateAlertRule(ctx, params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create alert rule", "observation_code", req.ObservationCode, "error", err)
+		return nil, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	mapped := mapper.MapAlertRule(rule)
+	return &mapped, nil
+}
+
+func (s *alertService) ListAlertRules(ctx context.Context) ([]model.AlertRule, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.ListAlertRules")
+	defer span.End()
+	rules, err := s.alertRepo.ListActiveAlertRules(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list alert rules", "error", err)
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	mapped := make([]model.AlertRule, 0, len(rules))
+	for _, r := range rules {
+		mapped = append(mapped, mapper.MapAlertRule(r))
+	}
+	return mapped, nil
+}
+
+func (s *alertService) DeactivateAlertRule(ctx context.Context, ruleID uuid.UUID) (*model.AlertRule, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.DeactivateAlertRule")
+	defer span.End()
+	rule, err := s.alertRepo.DeactivateAlertRule(ctx, pgtype.UUID{Bytes: ruleID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertRuleNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to deactivate alert rule", "rule_id", ruleID, "error", err)
+		return nil, fmt.Errorf("failed to deactivate alert rule: %w", err)
+	}
+	mapped := mapper.MapAlertRule(rule)
+	return &mapped, nil
+}
+
+func (s *alertService) ListAlerts(ctx context.Context, doctorID uuid.UUID, status *model.AlertStatus, params model.PaginationParams) ([]model.Alert, int64, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.ListAlerts")
+	defer span.End()
+	statusFilter := db.NullAlertStatus{}
+	if status != nil {
+		statusFilter = db.NullAlertStatus{AlertStatus: db.AlertStatus(*status), Valid: true}
+	}
+
+	alerts, err := s.alertRepo.ListAlertsForDoctor(ctx, db.ListAlertsForDoctorParams{
+		DoctorID:  pgtype.UUID{Bytes: doctorID, Valid: true},
+		Status:    statusFilter,
+		RowLimit:  int32(params.Limit),
+		RowOffset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list alerts for doctor", "doctor_id", doctorID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	total, err := s.alertRepo.CountAlertsForDoctor(ctx, db.CountAlertsForDoctorParams{
+		DoctorID: pgtype.UUID{Bytes: doctorID, Valid: true},
+		Status:   statusFilter,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count alerts for doctor", "doctor_id", doctorID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+
+	mapped := make([]model.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		mapped = append(mapped, mapper.MapAlert(a))
+	}
+	return mapped, total, nil
+}
+
+func (s *alertService) AcknowledgeAlert(ctx context.Context, alertID uuid.UUID, userID uuid.UUID) (*model.Alert, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.AcknowledgeAlert")
+	defer span.End()
+	existing, err := s.alertRepo.GetAlertByID(ctx, pgtype.UUID{Bytes: alertID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to fetch alert", "alert_id", alertID, "error", err)
+		return nil, fmt.Errorf("failed to fetch alert: %w", err)
+	}
+	// Either the doctor currently holding the alert or the visit's own doctor may acknowledge it.
+	if existing.AssignedDoctorID.Bytes != userID && existing.OriginalDoctorID.Bytes != userID {
+		return nil, ErrAlertAcknowledgeForbidden
+	}
+
+	alert, err := s.alertRepo.AcknowledgeAlert(ctx, db.AcknowledgeAlertParams{
+		ID:                   existing.ID,
+		AcknowledgedByUserID: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertAlreadyAcknowledged
+		}
+		s.logger.ErrorContext(ctx, "Failed to acknowledge alert", "alert_id", alertID, "error", err)
+		return nil, fmt.Errorf("failed to acknowledge alert: %w", err)
+	}
+	mapped := mapper.MapAlert(alert)
+	return &mapped, nil
+}
+
+// EscalateDueAlerts hands every unacknowledged alert whose deadline has passed to another doctor.
+// Deadlines live in the database, so escalation resumes after a restart, and the advisory lock
+// plus SKIP LOCKED row locks keep concurrent replicas from escalating the same alert twice.
+func (s *alertService) EscalateDueAlerts(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.EscalateDueAlerts")
+	defer span.End()
+	escalated := 0
+	acquired, err := s.alertRepo.WithinAdvisoryLock(ctx, alertEscalationLockKey, func(repo repository.AlertRepository) error {
+		due, err := repo.LockDueAlerts(ctx, db.LockDueAlertsParams{
+			EscalateAt: pgtype.Timestamptz{Time: now, Valid: true},
+			Limit:      escalationBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load due alerts: %w", err)
+		}
+
+		for _, alert := range due {
+			rule, err := repo.GetAlertRuleByID(ctx, alert.RuleID)
+			if err != nil {
+				return fmt.Errorf("failed to load rule for alert %s: %w", alert.ID, err)
+			}
+			escalateAfter := time.Duration(rule.EscalateAfterMinutes) * time.Minute
+
+			target := rule.EscalationDoctorID
+			if !target.Valid || target.Bytes == alert.AssignedDoctorID.Bytes {
+				target, err = repo.PickEscalationDoctor(ctx, alert.AssignedDoctorID)
+				if errors.Is(err, pgx.ErrNoRows) {
+					s.logger.WarnContext(ctx, "No doctor available to escalate alert; retrying", "alert_id", alert.ID, "retry_in", escalateAfter)
+					if _, err := repo.PostponeAlertEscalation(ctx, db.PostponeAlertEscalationParams{
+						ID:         alert.ID,
+						EscalateAt: pgtype.Timestamptz{Time: now.Add(escalateAfter), Valid: true},
+					}); err != nil {
+						return fmt.Errorf("failed to postpone alert %s: %w", alert.ID, err)
+					}
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to pick escalation doctor for alert %s: %w", alert.ID, err)
+				}
+			}
+
+			next := pgtype.Timestamptz{}
+			if int(alert.EscalationLevel)+1 < maxEscalationLevel {
+				next = pgtype.Timestamptz{Time: now.Add(escalateAfter), Valid: true}
+			}
+			updated, err := repo.EscalateAlert(ctx, db.EscalateAlertParams{
+				ID:               alert.ID,
+				AssignedDoctorID: target,
+				NextEscalateAt:   next,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to escalate alert %s: %w", alert.ID, err)
+			}
+			if _, err := repo.CreateAlertEscalation(ctx, db.CreateAlertEscalationParams{
+				AlertID:         alert.ID,
+				FromDoctorID:    alert.AssignedDoctorID,
+				ToDoctorID:      target,
+				EscalationLevel: updated.EscalationLevel,
+			}); err != nil {
+				return fmt.Errorf("failed to record escalation of alert %s: %w", alert.ID, err)
+			}
+			s.logger.InfoContext(ctx, "Escalated alert", "alert_id", alert.ID, "from_doctor_id", alert.AssignedDoctorID, "to_doctor_id", target, "escalation_level", updated.EscalationLevel)
+			escalated++
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Escalation pass failed", "error", err)
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	return escalated, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAlertRepo stores observations and has no alert rules.
+type fakeAlertRepo struct {
+	repository.AlertRepository
+	observations []db.CreateVisitObservationParams
+}
+
+func (f *fakeAlertRepo) CreateVisitObservation(_ context.Context, arg db.CreateVisitObservationParams) (db.VisitObservation, error) {
+	f.observations = append(f.observations, arg)
+	return db.VisitObservation{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, VisitID: arg.VisitID, Code: arg.Code, Value: arg.Value}, nil
+}
+
+func (f *fakeAlertRepo) ListActiveAlertRulesByCode(context.Context, string) ([]db.AlertRule, error) {
+	return nil, nil
+}
+
+func TestRecordObservationScopesToCareTeam(t *testing.T) {
+	f := newCareTeamFixture()
+	inCareVisit := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	outOfCareVisit := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	visits := fakeVisitRepo{visits: map[pgtype.UUID]db.PatientVisit{
+		inCareVisit:    {ID: inCareVisit, PatientID: pgtype.UUID{Bytes: f.inCare, Valid: true}},
+		outOfCareVisit: {ID: outOfCareVisit, PatientID: pgtype.UUID{Bytes: f.outOfCare, Valid: true}},
+	}}
+	alerts := &fakeAlertRepo{}
+	service := NewAlertService(alerts, visits, f.patients, f.access, discardLogger)
+	doctor := audit.WithActor(context.Background(), f.doctor, model.RoleDoctor)
+	req := model.ObservationCreateRequest{Code: "HR", Value: ptr(72.0)}
+
+	_, err := service.RecordObservation(doctor, outOfCareVisit.Bytes, req, f.doctor)
+	assert.ErrorIs(t, err, ErrVisitNotFound)
+	assert.Empty(t, alerts.observations)
+
+	observation, err := service.RecordObservation(doctor, inCareVisit.Bytes, req, f.doctor)
+	require.NoError(t, err)
+	assert.Equal(t, "HR", observation.Code)
+	assert.Len(t, alerts.observations, 1)
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrAuditUnavailable = errors.New("audit trail unavailable")
+
+const (
+	// auditChainLockKey is the Postgres advisory lock held while appending to the chain,
+	// so every event links to the one before it.
+	auditChainLockKey int64 = 0x484d5302
+	// auditVerifyPageSize is how many events VerifyChain loads at a time.
+	auditVerifyPageSize = 1000
+)
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, logger *slog.Logger) AuditService {
+	return &auditService{auditRepo: auditRepo, logger: logger.With("service", "AuditService")}
+}
+
+func optionalText(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func textParam(s *string) pgtype.Text {
+	return pgtype.Text{String: derefString(s), Valid: s != nil}
+}
+
+// Record appends entry to the hash chain. The actor and request details come from ctx
+// unless the entry names its own actor.
+func (s *auditService) Record(ctx context.Context, entry model.AuditEntry) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+	return s.append(ctx, s.auditRepo, entry)
+}
+
+func (s *auditService) RecordTx(ctx context.Context, r repository.Repos, entry model.AuditEntry) error {
+	ctx, span := tracing.Start(ctx, "AuditService.RecordTx")
+	defer span.End()
+	return s.append(ctx, r.Audit, entry)
+}
+
+func (s *auditService) append(ctx context.Context, auditRepo repository.AuditRepository, entry model.AuditEntry) error {
+	md := audit.FromContext(ctx)
+	actorID, actorRole := md.ActorUserID, md.ActorRole
+	if entry.ActorUserID != nil {
+		actorID, actorRole = entry.ActorUserID, entry.ActorRole
+	}
+	event := model.AuditEvent{
+		OccurredAt:        time.Now().UTC().Truncate(audit.Precision),
+		ActorUserID:       actorID,
+		ActorRole:         optionalText(string(actorRole)),
+		Action:            entry.Action,
+		ResourceType:      entry.ResourceType,
+		ResourceID:        entry.ResourceID,
+		PatientID:         entry.PatientID,
+		Changes:           entry.Changes,
+		ClientIP:          optionalText(md.ClientIP),
+		UserAgent:         optionalText(md.UserAgent),
+		RequestID:         optionalText(md.RequestID),
+		BreakGlassGrantID: md.BreakGlassGrantID,
+	}
+	var changes []byte
+	if len(event.Changes) > 0 {
+		var err error
+		if changes, err = json.Marshal(event.Changes); err != nil {
+			return fmt.Errorf("%w: encode changes: %v", ErrAuditUnavailable, err)
+		}
+	}
+
+	err := auditRepo.WithinLock(ctx, auditChainLockKey, func(repo repository.AuditRepository) error {
+		head, err := repo.GetAuditChainHead(ctx)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			head = db.GetAuditChainHeadRow{Seq: 0, Hash: audit.GenesisHash}
+		case err != nil:
+			return fmt.Errorf("read chain head: %w", err)
+		}
+		event.Seq = head.Seq + 1
+		event.PrevHash = head.Hash
+		if event.Hash, err = audit.Hash(event); err != nil {
+			return err
+		}
+
+		_, err = repo.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+			Seq:               event.Seq,
+			OccurredAt:        pgtype.Timestamptz{Time: event.OccurredAt, Valid: true},
+			ActorUserID:       optionalUUID(event.ActorUserID),
+			ActorRole:         textParam(event.ActorRole),
+			Action:            string(event.Action),
+			ResourceType:      event.ResourceType,
+			ResourceID:        optionalUUID(event.ResourceID),
+			PatientID:         optionalUUID(event.PatientID),
+			Changes:           changes,
+			ClientIp:          textParam(event.ClientIP),
+			UserAgent:         textParam(event.UserAgent),
+			RequestID:         textParam(event.RequestID),
+			PrevHash:          event.PrevHash,
+			Hash:              event.Hash,
+			BreakGlassGrantID: optionalUUID(event.BreakGlassGrantID),
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to record audit event", "action", event.Action, "resource_type", event.ResourceType, "resource_id", derefUUID(event.ResourceID), "error", err)
+		return fmt.Errorf("%w: %v", ErrAuditUnavailable, err)
+	}
+	return nil
+}
+
+// recordAudit records an event for something outside the database, like a login or an
+// SMS already sent. A failure cannot undo it, so it is logged rather than returned.
+// Database writes record their event with RecordTx instead.
+func recordAudit(ctx context.Context, logger *slog.Logger, auditor AuditRecorder, entry model.AuditEntry) {
+	if err := auditor.Record(ctx, entry); err != nil {
+		logger.ErrorContext(ctx, "UNAUDITED write", "action", entry.Action, "resource_type", entry.ResourceType, "resource_id", derefUUID(entry.ResourceID), "error", err)
+	}
+}
+
+// auditDiff is audit.Diff for callers that would rather record an event without field
+// changes than not record it at all.
+func auditDiff(ctx context.Context, logger *slog.Logger, before, after interface{}) map[string]model.FieldChange {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to diff for audit", "type", fmt.Sprintf("%T", after), "error", err)
+		return nil
+	}
+	return changes
+}
+
+func (s *auditService) ListEvents(ctx context.Context, filter model.AuditEventFilter, params model.PaginationParams) ([]model.AuditEvent, int64, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.ListEvents")
+	defer span.End()
+	var resourceType, action pgtype.Text
+	if filter.ResourceType != nil {
+		resourceType = pgtype.Text{String: *filter.ResourceType, Valid: true}
+	}
+	if filter.Action != nil {
+		action = pgtype.Text{String: string(*filter.Action), Valid: true}
+	}
+	var from, to pgtype.Timestamptz
+	if filter.From != nil {
+		from = pgtype.Timestamptz{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		to = pgtype.Timestamptz{Time: *filter.To, Valid: true}
+	}
+
+	rows, err := s.auditRepo.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		ActorUserID:  optionalUUID(filter.ActorUserID),
+		PatientID:    optionalUUID(filter.PatientID),
+		ResourceType: resourceType,
+		ResourceID:   optionalUUID(filter.ResourceID),
+		Action:       action,
+		OccurredFrom: from,
+		OccurredTo:   to,
+		RowLimit:     int32(params.Limit),
+		RowOffset:    int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list audit events", "error", err)
+		return nil, 0, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	total, err := s.auditRepo.CountAuditEvents(ctx, db.CountAuditEventsParams{
+		ActorUserID:  optionalUUID(filter.ActorUserID),
+		PatientID:    optionalUUID(filter.PatientID),
+		ResourceType: resourceType,
+		ResourceID:   optionalUUID(filter.ResourceID),
+		Action:       action,
+		OccurredFrom: from,
+		OccurredTo:   to,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count audit events", "error", err)
+		return nil, 0, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	events := make([]model.AuditEvent, 0, len(rows))
+	for _, r := range rows {
+		event, err := mapper.MapAuditEvent(r)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to map audit event", "seq", r.Seq, "error", err)
+		}
+		events = append(events, event)
+	}
+	return events, total, nil
+}
+
+// VerifyChain re-walks the whole chain from the first event and reports the first break.
+func (s *auditService) VerifyChain(ctx context.Context) (*model.AuditChainVerification, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.VerifyChain")
+	defer span.End()
+	verifier := audit.NewVerifier()
+	for {
+		rows, err := s.auditRepo.ListAuditEventsAfterSeq(ctx, db.ListAuditEventsAfterSeqParams{
+			Seq:   verifier.LastSeq(),
+			Limit: auditVerifyPageSize,
+		})
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to load audit events", "after_seq", verifier.LastSeq(), "error", err)
+			return nil, fmt.Errorf("failed to load audit events: %w", err)
+		}
+		for _, r := range rows {
+			event, err := mapper.MapAuditEvent(r)
+			if err == nil {
+				err = verifier.Next(event)
+			}
+			if err != nil {
+				seq := r.Seq
+				return &model.AuditChainVerification{EventsChecked: verifier.Checked(), BrokenAtSeq: &seq, Reason: err.Error()}, nil
+			}
+		}
+		if len(rows) < auditVerifyPageSize {
+			return &model.AuditChainVerification{Valid: true, EventsChecked: verifier.Checked()}, nil
+		}
+	}
+}
+
+// ListPatientAccessLog lists who read or changed the patient and their visits, newest first.
+func (s *auditService) ListPatientAccessLog(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.AccessLogEntry, int64, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.ListPatientAccessLog")
+	defer span.End()
+	pid := pgtype.UUID{Bytes: patientID, Valid: true}
+	rows, err := s.auditRepo.ListPatientAccessLog(ctx, db.ListPatientAccessLogParams{
+		PatientID: pid,
+		Limit:     int32(params.Limit),
+		Offset:    int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list access log", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list access log: %w", err)
+	}
+	total, err := s.auditRepo.CountPatientAccessLog(ctx, pid)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count access log", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count access log: %w", err)
+	}
+
+	entries := make([]model.AccessLogEntry, 0, len(rows))
+	for _, r := range rows {
+		entry, err := mapper.MapAccessLogEntry(r)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to map access log entry", "patient_id", patientID, "seq", r.Seq, "error", err)
+			return nil, 0, fmt.Errorf("failed to list access log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, nil
+}
+
+// ExportPatientAccessLog pages through the events up to the newest one when the export
+// starts, by seq, so events recorded meanwhile, such as the export's own, neither appear
+// nor shift the pages to repeat or skip rows.
+func (s *auditService) ExportPatientAccessLog(ctx context.Context, patientID uuid.UUID, pageSize int, write func([]model.AccessLogEntry) error) error {
+	ctx, span := tracing.Start(ctx, "AuditService.ExportPatientAccessLog")
+	defer span.End()
+	head, err := s.auditRepo.GetAuditChainHead(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.ErrorContext(ctx, "Failed to read audit chain head", "patient_id", patientID, "error", err)
+		return fmt.Errorf("failed to export access log: %w", err)
+	}
+	params := db.ListPatientAccessLogBeforeSeqParams{
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		BeforeSeq: head.Seq + 1,
+		RowLimit:  int32(pageSize),
+	}
+	for {
+		rows, err := s.auditRepo.ListPatientAccessLogBeforeSeq(ctx, params)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to list access log", "patient_id", patientID, "before_seq", params.BeforeSeq, "error", err)
+			return fmt.Errorf("failed to export access log: %w", err)
+		}
+		entries := make([]model.AccessLogEntry, 0, len(rows))
+		for _, r := range rows {
+			entry, err := mapper.MapAccessLogEntry(db.ListPatientAccessLogRow(r))
+			if err != nil {
+				s.logger.ErrorContext(ctx, "Failed to map access log entry", "patient_id", patientID, "seq", r.Seq, "error", err)
+				return fmt.Errorf("failed to export access log: %w", err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := write(entries); err != nil {
+			return err
+		}
+		if len(rows) < pageSize {
+			return nil
+		}
+		params.BeforeSeq = rows[len(rows)-1].Seq
+	}
+}
+
+func uuidPtrOf(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	v := uuid.UUID(id.Bytes)
+	return &v
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditRepo holds audit events in seq order.
+type fakeAuditRepo struct {
+	repository.AuditRepository
+	events []db.AuditEvent
+}
+
+// add appends an event about the patient, or about no patient when it is uuid.Nil.
+func (f *fakeAuditRepo) add(patientID uuid.UUID) int64 {
+	seq := int64(len(f.events) + 1)
+	f.events = append(f.events, db.AuditEvent{
+		Seq:          seq,
+		PatientID:    pgtype.UUID{Bytes: patientID, Valid: patientID != uuid.Nil},
+		Action:       string(model.AuditActionRead),
+		ResourceType: model.AuditResourcePatient,
+		ResourceID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
+	})
+	return seq
+}
+
+func (f *fakeAuditRepo) GetAuditChainHead(context.Context) (db.GetAuditChainHeadRow, error) {
+	if len(f.events) == 0 {
+		return db.GetAuditChainHeadRow{}, pgx.ErrNoRows
+	}
+	return db.GetAuditChainHeadRow{Seq: f.events[len(f.events)-1].Seq}, nil
+}
+
+func (f *fakeAuditRepo) ListPatientAccessLogBeforeSeq(_ context.Context, arg db.ListPatientAccessLogBeforeSeqParams) ([]db.ListPatientAccessLogBeforeSeqRow, error) {
+	var rows []db.ListPatientAccessLogBeforeSeqRow
+	for i := len(f.events) - 1; i >= 0 && len(rows) < int(arg.RowLimit); i-- {
+		e := f.events[i]
+		if e.PatientID == arg.PatientID && e.Seq < arg.BeforeSeq {
+			rows = append(rows, db.ListPatientAccessLogBeforeSeqRow{Seq: e.Seq, Action: e.Action, ResourceType: e.ResourceType, ResourceID: e.ResourceID, Changes: e.Changes})
+		}
+	}
+	return rows, nil
+}
+
+func TestExportPatientAccessLog(t *testing.T) {
+	patientID := uuid.New()
+	repo := &fakeAuditRepo{}
+	var want []uuid.UUID // resource IDs, newest first
+	for i := 0; i < 7; i++ {
+		repo.add(patientID)
+		repo.add(uuid.New())
+		repo.add(uuid.Nil)
+		want = append([]uuid.UUID{repo.events[len(repo.events)-3].ResourceID.Bytes}, want...)
+	}
+	audits := NewAuditService(repo, discardLogger)
+
+	var pages [][]model.AccessLogEntry
+	err := audits.ExportPatientAccessLog(context.Background(), patientID, 3, func(entries []model.AccessLogEntry) error {
+		pages = append(pages, entries)
+		// Events recorded during the export, like its own, are not exported.
+		repo.add(patientID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var got []uuid.UUID
+	for _, page := range pages {
+		assert.LessOrEqual(t, len(page), 3)
+		for _, e := range page {
+			got = append(got, *e.ResourceID)
+		}
+	}
+	assert.Equal(t, want, got)
+	assert.Len(t, pages, 3)
+}
+
+func TestExportPatientAccessLogWritesAnEmptyPage(t *testing.T) {
+	audits := NewAuditService(&fakeAuditRepo{}, discardLogger)
+	calls := 0
+	err := audits.ExportPatientAccessLog(context.Background(), uuid.New(), 10, func(entries []model.AccessLogEntry) error {
+		calls++
+		assert.Empty(t, entries)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExportPatientAccessLogFailsOnAnUnreadableEntry(t *testing.T) {
+	patientID := uuid.New()
+	repo := &fakeAuditRepo{}
+	repo.add(patientID)
+	repo.add(patientID)
+	repo.events[0].Changes = []byte("{not json")
+	audits := NewAuditService(repo, discardLogger)
+
+	var exported []model.AccessLogEntry
+	err := audits.ExportPatientAccessLog(context.Background(), patientID, 10, func(entries []model.AccessLogEntry) error {
+		exported = append(exported, entries...)
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Empty(t, exported)
+}
@@ -4,57 +4,71 @@ import (
 	"context"
 	"errors"       // For errors.Is
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"strings" // For error message checking
-	"time"
 
-	 // Not directly used here but good to have if User model had UUID
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
 	"github.com/himanshu-holmes/hms/internal/authentication"
 	"github.com/himanshu-holmes/hms/internal/authorization"	
 	"github.com/himanshu-holmes/hms/internal/db"
 	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/metrics"
 	"github.com/himanshu-holmes/hms/internal/model"
 	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-var secret = []byte(os.Getenv("SECRET"))
-
 var ErrUserNotFound = errors.New("user not found")
 var ErrInvalidCredentials = errors.New("invalid username or password")
 var ErrUserAlreadyExists = errors.New("username already exists")
 
 type authService struct {
 	userRepo repository.UserRepository
+	tx       repository.TxManager
+	auditor  AuditRecorder
+	tokens   authorization.Authorization
+	logger   *slog.Logger
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepo,}
+func NewAuthService(userRepo repository.UserRepository, tx repository.TxManager, auditor AuditRecorder, tokens authorization.Authorization, logger *slog.Logger) AuthService {
+	return &authService{userRepo: userRepo, tx: tx, auditor: auditor, tokens: tokens, logger: logger.With("service", "AuthService")}
 }
 
 func (s *authService) Login(ctx context.Context ,req model.LoginRequest) (*model.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
 	user, err := s.userRepo.GetUserByUsername(ctx ,req.Username)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			metrics.LoginFailed(metrics.LoginUnknownUser)
 			return nil, ErrInvalidCredentials // More generic error for security
 		}
-		log.Printf("AuthService: Error fetching user by username '%s': %v", req.Username, err)
+		metrics.LoginFailed(metrics.LoginError)
+		s.logger.ErrorContext(ctx, "Error fetching user", "username", req.Username, "error", err)
 		return nil, fmt.Errorf("internal server error during login: %w", err)
 	}
 	
 
 
+	userID := uuid.UUID(user.ID.Bytes)
 	if !authentication.CheckPasswordHash(req.Password, user.PasswordHash) {
+		recordAudit(ctx, s.logger, s.auditor, model.AuditEntry{
+			Action:       model.AuditActionLoginFailed,
+			ResourceType: model.AuditResourceUser,
+			ResourceID:   &userID,
+		})
+		metrics.LoginFailed(metrics.LoginWrongPassword)
 		return nil, ErrInvalidCredentials
 	}
 //  generate token
       
-      auth := authorization.NewAuthorization(secret, 24*time.Hour, 7*24*time.Hour)
-	  accessToken,refreshToken, err := auth.Tokenize(ctx,user.ID.String(),user.Username,string(user.Role))
+	  accessToken,refreshToken, err := s.tokens.Tokenize(ctx,user.ID.String(),user.Username,string(user.Role))
 	if err != nil {
-		log.Printf("AuthService: Error generating token for user ID %s: %v", user.ID, err)
+		metrics.LoginFailed(metrics.LoginError)
+		s.logger.ErrorContext(ctx, "Error generating token", "user_id", user.ID, "error", err)
 		return nil, fmt.Errorf("error generating token: %w", err)
 	}
 
@@ -62,23 +76,24 @@ func (s *authService) Login(ctx context.Context ,req model.LoginRequest) (*model
 	user.PasswordHash = ""
 
 	formatUser := mapper.ConvertDBUserToModel(user)
+	recordAudit(ctx, s.logger, s.auditor, model.AuditEntry{
+		Action:       model.AuditActionLogin,
+		ResourceType: model.AuditResourceUser,
+		ResourceID:   &userID,
+		ActorUserID:  &userID,
+		ActorRole:    formatUser.Role,
+	})
+	metrics.LoginSucceeded()
 
 	return &model.LoginResponse{AccessToken: accessToken, RefreshToken: refreshToken, User: formatUser}, nil
 }
 
 func (s *authService) CreateUser(ctx context.Context, req model.UserCreateRequest) (*model.User, error) {
-	// Check if user already exists
-	existingUser, err := s.userRepo.GetUserByUsername(ctx, req.Username)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) { // If error is something other than "not found"
-		log.Printf("AuthService: Error checking existing user '%s': %v", req.Username, err)
-		return nil, fmt.Errorf("error checking existing user: %w", err)
-	}
-	if existingUser.Username == req.Username {
-		return nil, ErrUserAlreadyExists
-	}
+	ctx, span := tracing.Start(ctx, "AuthService.CreateUser")
+	defer span.End()
 	hashedPassword, err := authentication.HashPassword(req.Password)
 	if err != nil {
-		log.Printf("AuthService: Error hashing password for user '%s': %v", req.Username, err)
+		s.logger.ErrorContext(ctx, "Error hashing password", "username", req.Username, "error", err)
 		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
@@ -87,37 +102,134 @@ func (s *authService) CreateUser(ctx context.Context, req model.UserCreateReques
 		Username:     req.Username,
 		PasswordHash: hashedPassword,
 	    Role:       db.UserRole(req.Role),
-		FirstName: pgtype.Text{String: *req.FirstName, Valid: req.FirstName != nil},
-		LastName:  pgtype.Text{String: *req.LastName, Valid: req.LastName != nil},
-		Email:     pgtype.Text{String: *req.Email, Valid: req.Email != nil},
+		FirstName: pgtype.Text{String: derefString(req.FirstName), Valid: req.FirstName != nil},
+		LastName:  pgtype.Text{String: derefString(req.LastName), Valid: req.LastName != nil},
+		Email:     pgtype.Text{String: derefString(req.Email), Valid: req.Email != nil},
 		IsActive:  pgtype.Bool{Bool: true, Valid: true},
 	
 
 	}
 
-	user, err := s.userRepo.CreateUser(ctx,*newUser)
+	// The username check, the insert, the read of DB-generated fields like CreatedAt and
+	// the audit event run in one transaction.
+	var user db.User
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		// Check if user already exists
+		existingUser, err := r.Users.GetUserByUsername(ctx, req.Username)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) { // If error is something other than "not found"
+			s.logger.ErrorContext(ctx, "Error checking existing user", "username", req.Username, "error", err)
+			return fmt.Errorf("error checking existing user: %w", err)
+		}
+		if existingUser.Username == req.Username {
+			return ErrUserAlreadyExists
+		}
+
+		created, err := r.Users.CreateUser(ctx, *newUser)
+		if err != nil {
+			return err
+		}
+		if user, err = r.Users.GetUserByID(ctx, created.ID); err != nil {
+			return err
+		}
+		mappedUser := mapper.ConvertDBUserToModel(user)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourceUser,
+			ResourceID:   &mappedUser.ID,
+			Changes:      auditDiff(ctx, s.logger, nil, mappedUser),
+		})
+	})
 	if err != nil {
+		if errors.Is(err, ErrUserAlreadyExists) || errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
 		// Check for unique constraint violation errors from the database
 		// This might be DB-specific, e.g., "pq: duplicate key value violates unique constraint"
 		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") ||
 			strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
 			return nil, ErrUserAlreadyExists
 		}
-		log.Printf("AuthService: Error creating user '%s' in repository: %v", req.Username, err)
+		s.logger.ErrorContext(ctx, "Error creating user in repository", "username", req.Username, "error", err)
 		return nil, fmt.Errorf("error creating user: %w", err)
 	}
-	userID := user.ID // Set the ID returned by the repository
+	user.PasswordHash = "" // Don't return hash
+    mappedUser := mapper.ConvertDBUserToModel(user)
+	return &mappedUser, nil
+}
 
-	// Fetch the full user record to get DB-generated fields like CreatedAt, UpdatedAt
-	// (This assumes CreateUser in repo might not return all fields or you want to be sure)
-	createdUser, err := s.userRepo.GetUserByID(ctx, userID)
+func (s *authService) DeactivateUser(ctx context.Context, username string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.DeactivateUser")
+	defer span.End()
+	existing, err := s.userRepo.GetUserByUsername(ctx, username)
 	if err != nil {
-		log.Printf("AuthService: Error fetching newly created user '%s' by ID %s: %v. Returning partially populated user.", req.Username, userID, err)
-		// Still return the user object we have, but log the error
-		partialUser := mapper.ConvertDBUserToModel(user)
-		return &partialUser, nil
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error fetching user to deactivate", "username", username, "error", err)
+		return nil, fmt.Errorf("error fetching user: %w", err)
 	}
-	createdUser.PasswordHash = "" // Don't return hash
-    mappedUser := mapper.ConvertDBUserToModel(user)
-	return &mappedUser, nil
-}
\ No newline at end of file
+	before := mapper.ConvertDBUserToModel(existing)
+	var after model.User
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		updated, err := r.Users.SetUserActiveStatus(ctx, db.SetUserActiveStatusParams{ID: existing.ID, IsActive: pgtype.Bool{Bool: false, Valid: true}})
+		if err != nil {
+			return err
+		}
+		after = mapper.ConvertDBUserToModel(updated)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourceUser,
+			ResourceID:   &after.ID,
+			Changes:      auditDiff(ctx, s.logger, before, after),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Error deactivating user", "username", username, "error", err)
+		return nil, fmt.Errorf("error deactivating user: %w", err)
+	}
+	return &after, nil
+}
+
+func (s *authService) ResetPassword(ctx context.Context, username, password string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ResetPassword")
+	defer span.End()
+	existing, err := s.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error fetching user to reset password", "username", username, "error", err)
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	hashedPassword, err := authentication.HashPassword(password)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error hashing password", "username", username, "error", err)
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+	var user model.User
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		updated, err := r.Users.UpdateUser(ctx, db.UpdateUserParams{ID: existing.ID, PasswordHash: pgtype.Text{String: hashedPassword, Valid: true}})
+		if err != nil {
+			return err
+		}
+		user = mapper.ConvertDBUserToModel(updated)
+		// The hash is not in the diff; record that it changed without what it is.
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourceUser,
+			ResourceID:   &user.ID,
+			Changes:      map[string]model.FieldChange{"password": {Old: audit.Redacted, New: audit.Redacted}},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Error resetting password", "username", username, "error", err)
+		return nil, fmt.Errorf("error resetting password: %w", err)
+	}
+	return &user, nil
+}
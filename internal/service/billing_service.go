@@ -0,0 +1,601 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/billing"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrTaxCategoryNotFound = errors.New("tax category not found")
+var ErrTaxCategoryAlreadyExists = errors.New("tax category with this code already exists")
+var ErrChargeItemNotFound = errors.New("charge item not found")
+var ErrChargeItemAlreadyExists = errors.New("charge item with this code already exists")
+var ErrChargeAlreadyCaptured = errors.New("charge already captured for this source")
+var ErrNoBillableCharges = errors.New("no unbilled charges to invoice")
+var ErrChargeNotBillable = errors.New("charge not found or already invoiced for this patient")
+var ErrInvoiceNotFound = errors.New("invoice not found")
+var ErrPaymentNotFound = errors.New("payment not found")
+var ErrPaymentExceedsBalance = errors.New("payment exceeds the invoice balance")
+var ErrRefundExceedsPayment = errors.New("refund exceeds the unrefunded payment amount")
+
+type billingService struct {
+	billingRepo     repository.BillingRepository
+	tx              repository.TxManager
+	visitRepo       repository.PatientVisitQuerier
+	patientRepo     repository.PatientRepository
+	fiscalYearStart time.Month
+	logger          *slog.Logger
+}
+
+// NewBillingService creates the billing service. Invoice numbers restart every fiscal year,
+// which begins on the first day of fiscalYearStart.
+func NewBillingService(billingRepo repository.BillingRepository, tx repository.TxManager, visitRepo repository.PatientVisitQuerier, patientRepo repository.PatientRepository, fiscalYearStart time.Month, logger *slog.Logger) BillingService {
+	return &billingService{billingRepo: billingRepo, tx: tx, visitRepo: visitRepo, patientRepo: patientRepo, fiscalYearStart: fiscalYearStart, logger: logger.With("service", "BillingService")}
+}
+
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique constraint") || strings.Contains(msg, "duplicate key")
+}
+
+func (s *billingService) CreateTaxCategory(ctx context.Context, req model.TaxCategoryCreateRequest) (*model.TaxCategory, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.CreateTaxCategory")
+	defer span.End()
+	category, err := s.billingRepo.CreateTaxCategory(ctx, db.CreateTaxCategoryParams{
+		Code:        req.Code,
+		Description: req.Description,
+		RateBps:     int32(req.RateBps),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTaxCategoryAlreadyExists
+		}
+		s.logger.ErrorContext(ctx, "Failed to create tax category", "code", req.Code, "error", err)
+		return nil, fmt.Errorf("failed to create tax category: %w", err)
+	}
+	mapped := mapper.MapTaxCategory(category)
+	return &mapped, nil
+}
+
+func (s *billingService) ListTaxCategories(ctx context.Context) ([]model.TaxCategory, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.ListTaxCategories")
+	defer span.End()
+	categories, err := s.billingRepo.ListTaxCategories(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list tax categories", "error", err)
+		return nil, fmt.Errorf("failed to list tax categories: %w", err)
+	}
+	mapped := make([]model.TaxCategory, 0, len(categories))
+	for _, c := range categories {
+		mapped = append(mapped, mapper.MapTaxCategory(c))
+	}
+	return mapped, nil
+}
+
+func (s *billingService) CreateChargeItem(ctx context.Context, req model.ChargeItemCreateRequest) (*model.ChargeItem, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.CreateChargeItem")
+	defer span.End()
+	taxCategory := req.TaxCategoryCode
+	if taxCategory == "" {
+		taxCategory = "exempt"
+	}
+	_, err := s.billingRepo.CreateChargeItem(ctx, db.CreateChargeItemParams{
+		Code:            req.Code,
+		Description:     req.Description,
+		UnitPriceMinor:  req.UnitPriceMinor,
+		TaxCategoryCode: taxCategory,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrChargeItemAlreadyExists
+		}
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key constraint") {
+			return nil, fmt.Errorf("%w: code %s", ErrTaxCategoryNotFound, taxCategory)
+		}
+		s.logger.ErrorContext(ctx, "Failed to create charge item", "code", req.Code, "error", err)
+		return nil, fmt.Errorf("failed to create charge item: %w", err)
+	}
+
+	// Re-read to include the tax rate joined from the category.
+	item, err := s.billingRepo.GetChargeItemByCode(ctx, req.Code)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to fetch new charge item", "code", req.Code, "error", err)
+		return nil, fmt.Errorf("failed to fetch charge item: %w", err)
+	}
+	mapped := mapper.MapChargeItem(item)
+	return &mapped, nil
+}
+
+func (s *billingService) ListChargeItems(ctx context.Context, params model.PaginationParams) ([]model.ChargeItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.ListChargeItems")
+	defer span.End()
+	items, err := s.billingRepo.ListChargeItems(ctx, db.ListChargeItemsParams{
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list charge items", "error", err)
+		return nil, 0, fmt.Errorf("failed to list charge items: %w", err)
+	}
+	total, err := s.billingRepo.CountChargeItems(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count charge items", "error", err)
+		return nil, 0, fmt.Errorf("failed to count charge items: %w", err)
+	}
+	mapped := make([]model.ChargeItem, 0, len(items))
+	for _, i := range items {
+		mapped = append(mapped, mapper.MapChargeItem(db.GetChargeItemByCodeRow(i)))
+	}
+	return mapped, total, nil
+}
+
+// CaptureCharge prices a charge from the charge master and records it against the patient.
+// It returns ErrChargeAlreadyCaptured when capture.SourceID was already charged for the same item.
+func (s *billingService) CaptureCharge(ctx context.Context, capture model.ChargeCapture) (*model.Charge, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.CaptureCharge")
+	defer span.End()
+	return s.capture(ctx, s.billingRepo, capture)
+}
+
+// CaptureChargeTx is CaptureCharge within the caller's transaction.
+func (s *billingService) CaptureChargeTx(ctx context.Context, r repository.Repos, capture model.ChargeCapture) (*model.Charge, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.CaptureChargeTx")
+	defer span.End()
+	return s.capture(ctx, r.Billing, capture)
+}
+
+func (s *billingService) capture(ctx context.Context, billingRepo repository.BillingRepository, capture model.ChargeCapture) (*model.Charge, error) {
+	item, err := billingRepo.GetChargeItemByCode(ctx, capture.ChargeCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: code %s", ErrChargeItemNotFound, capture.ChargeCode)
+		}
+		s.logger.ErrorContext(ctx, "Error fetching charge item", "charge_code", capture.ChargeCode, "error", err)
+		return nil, fmt.Errorf("error fetching charge item: %w", err)
+	}
+	if !item.IsActive {
+		return nil, fmt.Errorf("%w: code %s is inactive", ErrChargeItemNotFound, capture.ChargeCode)
+	}
+
+	quantity := capture.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	params := db.CreateChargeParams{
+		PatientID:       pgtype.UUID{Bytes: capture.PatientID, Valid: true},
+		ChargeItemID:    item.ID,
+		Source:          db.ChargeSource(capture.Source),
+		Description:     item.Description,
+		Quantity:        int32(quantity),
+		UnitPriceMinor:  item.UnitPriceMinor,
+		TaxRateBps:      item.TaxRateBps,
+		CreatedByUserID: pgtype.UUID{Bytes: capture.CreatedByUserID, Valid: true},
+	}
+	if capture.VisitID != nil {
+		params.VisitID = pgtype.UUID{Bytes: *capture.VisitID, Valid: true}
+	}
+	if capture.SourceID != nil {
+		params.SourceID = pgtype.UUID{Bytes: *capture.SourceID, Valid: true}
+	}
+
+	charge, err := billingRepo.CreateCharge(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrChargeAlreadyCaptured
+		}
+		s.logger.ErrorContext(ctx, "Failed to capture charge", "source", capture.Source, "charge_code", capture.ChargeCode, "patient_id", capture.PatientID, "error", err)
+		return nil, fmt.Errorf("failed to capture charge: %w", err)
+	}
+	mapped := mapper.MapCharge(charge)
+	return &mapped, nil
+}
+
+// captureCharge records an automatic charge on behalf of a clinical service within its
+// transaction, so an event is never committed without its charge. A charge code missing
+// from the charge master is logged rather than returned so it never blocks clinical work.
+func captureCharge(ctx context.Context, logger *slog.Logger, charges ChargeCapturer, r repository.Repos, capture model.ChargeCapture) error {
+	_, err := charges.CaptureChargeTx(ctx, r, capture)
+	switch {
+	case err == nil, errors.Is(err, ErrChargeAlreadyCaptured):
+		return nil
+	case errors.Is(err, ErrChargeItemNotFound):
+		logger.WarnContext(ctx, "Charge was not billed", "source", capture.Source, "source_id", derefUUID(capture.SourceID), "error", err)
+		return nil
+	default:
+		return err
+	}
+}
+
+func derefUUID(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+func (s *billingService) PostCharge(ctx context.Context, req model.ChargeCreateRequest, postedByUserID uuid.UUID) (*model.Charge, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.PostCharge")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: req.VisitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking visit for charge", "visit_id", req.VisitID, "error", err)
+		return nil, fmt.Errorf("error verifying visit for charge: %w", err)
+	}
+
+	source := req.Source
+	if source == "" {
+		source = model.ChargeSourceProcedure
+	}
+	// Posted charges carry no source ID, so the same procedure may be billed more than once.
+	return s.CaptureCharge(ctx, model.ChargeCapture{
+		PatientID:       visit.PatientID.Bytes,
+		VisitID:         &req.VisitID,
+		Source:          source,
+		ChargeCode:      req.ChargeCode,
+		Quantity:        req.Quantity,
+		CreatedByUserID: postedByUserID,
+	})
+}
+
+func (s *billingService) ListPatientCharges(ctx context.Context, patientID uuid.UUID, unbilledOnly bool, params model.PaginationParams) ([]model.Charge, int64, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.ListPatientCharges")
+	defer span.End()
+	charges, err := s.billingRepo.ListChargesByPatientID(ctx, db.ListChargesByPatientIDParams{
+		PatientID:    pgtype.UUID{Bytes: patientID, Valid: true},
+		UnbilledOnly: unbilledOnly,
+		RowLimit:     int32(params.Limit),
+		RowOffset:    int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list charges", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list charges: %w", err)
+	}
+	total, err := s.billingRepo.CountChargesByPatientID(ctx, db.CountChargesByPatientIDParams{
+		PatientID:    pgtype.UUID{Bytes: patientID, Valid: true},
+		UnbilledOnly: unbilledOnly,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count charges", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count charges: %w", err)
+	}
+	mapped := make([]model.Charge, 0, len(charges))
+	for _, c := range charges {
+		mapped = append(mapped, mapper.MapCharge(c))
+	}
+	return mapped, total, nil
+}
+
+// CreateInvoice bills the requested (or all) unbilled charges of a patient. The invoice number is
+// taken from the per-fiscal-year counter inside the same transaction, so numbers stay gapless:
+// a failed invoice rolls its number back with it.
+func (s *billingService) CreateInvoice(ctx context.Context, req model.InvoiceCreateRequest, issuedByUserID uuid.UUID) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.CreateInvoice")
+	defer span.End()
+	patientID := pgtype.UUID{Bytes: req.PatientID, Valid: true}
+	if _, err := s.patientRepo.GetPatientByID(ctx, patientID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking patient for invoice", "patient_id", req.PatientID, "error", err)
+		return nil, fmt.Errorf("error verifying patient for invoice: %w", err)
+	}
+
+	requested := make(map[uuid.UUID]struct{}, len(req.ChargeIDs))
+	chargeIDs := make([]pgtype.UUID, 0, len(req.ChargeIDs))
+	for _, id := range req.ChargeIDs {
+		if _, seen := requested[id]; seen {
+			continue
+		}
+		requested[id] = struct{}{}
+		chargeIDs = append(chargeIDs, pgtype.UUID{Bytes: id, Valid: true})
+	}
+
+	var invoiceID uuid.UUID
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		charges, err := r.Billing.LockUnbilledCharges(ctx, db.LockUnbilledChargesParams{
+			PatientID: patientID,
+			ChargeIds: chargeIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load charges: %w", err)
+		}
+		if len(charges) == 0 {
+			return ErrNoBillableCharges
+		}
+		if len(chargeIDs) > 0 && len(charges) != len(chargeIDs) {
+			return ErrChargeNotBillable
+		}
+
+		amounts := make([]billing.LineAmounts, 0, len(charges))
+		for _, c := range charges {
+			amounts = append(amounts, billing.PriceLine(billing.Line{
+				Quantity:       int64(c.Quantity),
+				UnitPriceMinor: c.UnitPriceMinor,
+				TaxRateBps:     int64(c.TaxRateBps),
+			}, int64(req.DiscountBps)))
+		}
+		totals := billing.Sum(amounts)
+
+		fiscalYear := billing.FiscalYear(time.Now(), s.fiscalYearStart)
+		sequence, err := r.Billing.NextInvoiceSequence(ctx, int32(fiscalYear))
+		if err != nil {
+			return fmt.Errorf("failed to allocate invoice number: %w", err)
+		}
+		invoice, err := r.Billing.CreateInvoice(ctx, db.CreateInvoiceParams{
+			InvoiceNumber:  billing.InvoiceNumber(fiscalYear, int(sequence)),
+			FiscalYear:     int32(fiscalYear),
+			SequenceNumber: sequence,
+			PatientID:      patientID,
+			SubtotalMinor:  totals.Gross,
+			DiscountMinor:  totals.Discount,
+			TaxMinor:       totals.Tax,
+			TotalMinor:     totals.Total,
+			Notes:          pgtype.Text{String: derefString(req.Notes), Valid: req.Notes != nil},
+			IssuedByUserID: pgtype.UUID{Bytes: issuedByUserID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create invoice: %w", err)
+		}
+
+		for i, c := range charges {
+			if _, err := r.Billing.CreateInvoiceLine(ctx, db.CreateInvoiceLineParams{
+				InvoiceID:      invoice.ID,
+				ChargeID:       c.ID,
+				Description:    c.Description,
+				Quantity:       c.Quantity,
+				UnitPriceMinor: c.UnitPriceMinor,
+				DiscountMinor:  amounts[i].Discount,
+				TaxRateBps:     c.TaxRateBps,
+				TaxMinor:       amounts[i].Tax,
+				LineTotalMinor: amounts[i].Total,
+			}); err != nil {
+				return fmt.Errorf("failed to create invoice line for charge %s: %w", uuid.UUID(c.ID.Bytes), err)
+			}
+			if err := r.Billing.AssignChargeToInvoice(ctx, db.AssignChargeToInvoiceParams{ID: c.ID, InvoiceID: invoice.ID}); err != nil {
+				return fmt.Errorf("failed to mark charge %s invoiced: %w", uuid.UUID(c.ID.Bytes), err)
+			}
+		}
+		invoiceID = invoice.ID.Bytes
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNoBillableCharges) || errors.Is(err, ErrChargeNotBillable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to invoice patient", "patient_id", req.PatientID, "error", err)
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return s.GetInvoice(ctx, invoiceID)
+}
+
+func (s *billingService) GetInvoice(ctx context.Context, invoiceID uuid.UUID) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.GetInvoice")
+	defer span.End()
+	id := pgtype.UUID{Bytes: invoiceID, Valid: true}
+	invoice, err := s.billingRepo.GetInvoiceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvoiceNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	lines, err := s.billingRepo.ListInvoiceLines(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get lines for invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to get invoice lines: %w", err)
+	}
+	payments, err := s.billingRepo.ListPaymentsByInvoiceID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get payments for invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to get invoice payments: %w", err)
+	}
+	refunds, err := s.billingRepo.ListRefundsByInvoiceID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get refunds for invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to get invoice refunds: %w", err)
+	}
+
+	mapped := mapper.MapInvoice(invoice)
+	for _, l := range lines {
+		mapped.Lines = append(mapped.Lines, mapper.MapInvoiceLine(l))
+	}
+	for _, p := range payments {
+		mapped.Payments = append(mapped.Payments, mapper.MapPayment(p))
+	}
+	for _, r := range refunds {
+		mapped.Refunds = append(mapped.Refunds, mapper.MapRefund(r))
+	}
+	return &mapped, nil
+}
+
+func (s *billingService) ListPatientInvoices(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.Invoice, int64, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.ListPatientInvoices")
+	defer span.End()
+	invoices, err := s.billingRepo.ListInvoicesByPatientID(ctx, db.ListInvoicesByPatientIDParams{
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		Limit:     int32(params.Limit),
+		Offset:    int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list invoices", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	total, err := s.billingRepo.CountInvoicesByPatientID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count invoices", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+	mapped := make([]model.Invoice, 0, len(invoices))
+	for _, i := range invoices {
+		mapped = append(mapped, mapper.MapInvoice(i))
+	}
+	return mapped, total, nil
+}
+
+// RecordPayment applies a full or partial payment to an invoice. The invoice row is locked so
+// concurrent payments cannot together exceed the balance.
+func (s *billingService) RecordPayment(ctx context.Context, invoiceID uuid.UUID, req model.PaymentCreateRequest, receivedByUserID uuid.UUID) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.RecordPayment")
+	defer span.End()
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		return postPayment(ctx, r.Billing, invoiceID, req, receivedByUserID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvoiceNotFound) || errors.Is(err, ErrPaymentExceedsBalance) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to record payment on invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	return s.GetInvoice(ctx, invoiceID)
+}
+
+// postPayment records a payment against the invoice in repo's transaction. The invoice is
+// locked first, so concurrent payments cannot together exceed its balance.
+func postPayment(ctx context.Context, repo repository.BillingRepository, invoiceID uuid.UUID, req model.PaymentCreateRequest, receivedByUserID uuid.UUID) error {
+	invoice, err := repo.LockInvoice(ctx, pgtype.UUID{Bytes: invoiceID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvoiceNotFound
+		}
+		return fmt.Errorf("failed to lock invoice: %w", err)
+	}
+	if req.AmountMinor > invoice.TotalMinor-invoice.PaidMinor {
+		return ErrPaymentExceedsBalance
+	}
+
+	if _, err := repo.CreatePayment(ctx, db.CreatePaymentParams{
+		InvoiceID:        invoice.ID,
+		PatientID:        invoice.PatientID,
+		Method:           db.PaymentMethod(req.Method),
+		AmountMinor:      req.AmountMinor,
+		Reference:        pgtype.Text{String: derefString(req.Reference), Valid: req.Reference != nil},
+		ReceivedByUserID: pgtype.UUID{Bytes: receivedByUserID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	paid := invoice.PaidMinor + req.AmountMinor
+	if _, err := repo.UpdateInvoicePayment(ctx, db.UpdateInvoicePaymentParams{
+		ID:        invoice.ID,
+		PaidMinor: paid,
+		Status:    db.InvoiceStatus(billing.InvoiceStatus(invoice.TotalMinor, paid)),
+	}); err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+	return nil
+}
+
+// RefundPayment returns part or all of a payment and reopens the invoice balance by the same amount.
+func (s *billingService) RefundPayment(ctx context.Context, paymentID uuid.UUID, req model.RefundCreateRequest, refundedByUserID uuid.UUID) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.RefundPayment")
+	defer span.End()
+	var invoiceID uuid.UUID
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		payment, err := r.Billing.LockPayment(ctx, pgtype.UUID{Bytes: paymentID, Valid: true})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPaymentNotFound
+			}
+			return fmt.Errorf("failed to lock payment: %w", err)
+		}
+		invoice, err := r.Billing.LockInvoice(ctx, payment.InvoiceID)
+		if err != nil {
+			return fmt.Errorf("failed to lock invoice: %w", err)
+		}
+		refunded, err := r.Billing.SumRefundsByPaymentID(ctx, payment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to total refunds: %w", err)
+		}
+		if req.AmountMinor > payment.AmountMinor-refunded {
+			return ErrRefundExceedsPayment
+		}
+
+		if _, err := r.Billing.CreateRefund(ctx, db.CreateRefundParams{
+			PaymentID:        payment.ID,
+			InvoiceID:        invoice.ID,
+			AmountMinor:      req.AmountMinor,
+			Reason:           req.Reason,
+			RefundedByUserID: pgtype.UUID{Bytes: refundedByUserID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to create refund: %w", err)
+		}
+
+		paid := invoice.PaidMinor - req.AmountMinor
+		if _, err := r.Billing.UpdateInvoicePayment(ctx, db.UpdateInvoicePaymentParams{
+			ID:        invoice.ID,
+			PaidMinor: paid,
+			Status:    db.InvoiceStatus(billing.InvoiceStatus(invoice.TotalMinor, paid)),
+		}); err != nil {
+			return fmt.Errorf("failed to update invoice: %w", err)
+		}
+		invoiceID = invoice.ID.Bytes
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPaymentNotFound) || errors.Is(err, ErrRefundExceedsPayment) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to refund payment", "payment_id", paymentID, "error", err)
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+	return s.GetInvoice(ctx, invoiceID)
+}
+
+func (s *billingService) GetPatientBalance(ctx context.Context, patientID uuid.UUID) (*model.PatientBalance, error) {
+	ctx, span := tracing.Start(ctx, "BillingService.GetPatientBalance")
+	defer span.End()
+	id := pgtype.UUID{Bytes: patientID, Valid: true}
+	if _, err := s.patientRepo.GetPatientByID(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking patient for balance", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("error verifying patient: %w", err)
+	}
+
+	totals, err := s.billingRepo.GetPatientInvoiceTotals(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to total invoices", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to total invoices: %w", err)
+	}
+	unbilled, err := s.billingRepo.ListUnbilledChargesByPatientID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list unbilled charges", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to list unbilled charges: %w", err)
+	}
+	amounts := make([]billing.LineAmounts, 0, len(unbilled))
+	for _, c := range unbilled {
+		amounts = append(amounts, billing.PriceLine(billing.Line{
+			Quantity:       int64(c.Quantity),
+			UnitPriceMinor: c.UnitPriceMinor,
+			TaxRateBps:     int64(c.TaxRateBps),
+		}, 0))
+	}
+
+	return &model.PatientBalance{
+		PatientID:     patientID,
+		InvoicedMinor: totals.InvoicedMinor,
+		PaidMinor:     totals.PaidMinor,
+		BalanceMinor:  totals.InvoicedMinor - totals.PaidMinor,
+		UnbilledMinor: billing.Sum(amounts).Total,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/breakglass"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrBreakGlassReasonRequired = errors.New("a justification is required for emergency access")
+var ErrBreakGlassReportNotReady = errors.New("break-glass review report is only available once the day is over")
+
+// minBreakGlassReason is the shortest justification accepted, after trimming.
+const minBreakGlassReason = 10
+
+type breakGlassService struct {
+	breakGlassRepo repository.BreakGlassRepository
+	patientRepo    repository.PatientRepository
+	auditor        AuditRecorder
+	logger         *slog.Logger
+}
+
+func NewBreakGlassService(breakGlassRepo repository.BreakGlassRepository, patientRepo repository.PatientRepository, auditor AuditRecorder, logger *slog.Logger) BreakGlassService {
+	return &breakGlassService{breakGlassRepo: breakGlassRepo, patientRepo: patientRepo, auditor: auditor, logger: logger.With("service", "BreakGlassService")}
+}
+
+// RequestAccess grants the user emergency access to the patient. The grant is audited
+// before it is stored, so an unaudited grant never exists.
+func (s *breakGlassService) RequestAccess(ctx context.Context, patientID uuid.UUID, req model.BreakGlassRequest, userID uuid.UUID) (*model.BreakGlassGrant, error) {
+	ctx, span := tracing.Start(ctx, "BreakGlassService.RequestAccess")
+	defer span.End()
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) < minBreakGlassReason {
+		return nil, ErrBreakGlassReasonRequired
+	}
+	if _, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	grantID := uuid.New()
+	grantedAt := time.Now().UTC().Truncate(audit.Precision)
+	expiresAt := breakglass.ExpiresAt(grantedAt, req.DurationMinutes)
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionBreakGlass,
+		ResourceType: model.AuditResourceBreakGlassGrant,
+		ResourceID:   &grantID,
+		PatientID:    &patientID,
+		Changes: map[string]model.FieldChange{
+			"reason":     {New: reason},
+			"expires_at": {New: expiresAt.Format(time.RFC3339Nano)},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	grant, err := s.breakGlassRepo.CreateBreakGlassGrant(ctx, db.CreateBreakGlassGrantParams{
+		ID:        pgtype.UUID{Bytes: grantID, Valid: true},
+		UserID:    pgtype.UUID{Bytes: userID, Valid: true},
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		Reason:    reason,
+		GrantedAt: pgtype.Timestamptz{Time: grantedAt, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create grant", "user_id", userID, "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to create break-glass grant: %w", err)
+	}
+	s.logger.WarnContext(ctx, "BREAK-GLASS grant issued", "grant_id", grantID, "user_id", userID, "patient_id", patientID, "expires_at", expiresAt)
+	result := mapper.MapBreakGlassGrant(grant)
+	return &result, nil
+}
+
+// ActiveGrant returns the ID of the user's unexpired grant for the patient, or nil.
+func (s *breakGlassService) ActiveGrant(ctx context.Context, userID, patientID uuid.UUID) (*uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "BreakGlassService.ActiveGrant")
+	defer span.End()
+	grant, err := s.breakGlassRepo.GetActiveBreakGlassGrant(ctx, db.GetActiveBreakGlassGrantParams{
+		UserID:    pgtype.UUID{Bytes: userID, Valid: true},
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		AsOf:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		s.logger.ErrorContext(ctx, "Failed to check grants", "user_id", userID, "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to check break-glass access: %w", err)
+	}
+	grantID := uuid.UUID(grant.ID.Bytes)
+	return &grantID, nil
+}
+
+// GetReport returns the review of the UTC day containing date, generating and storing it
+// on first request. The current day has no report yet.
+func (s *breakGlassService) GetReport(ctx context.Context, date time.Time) (*model.BreakGlassReport, error) {
+	ctx, span := tracing.Start(ctx, "BreakGlassService.GetReport")
+	defer span.End()
+	start, end := breakglass.DayBounds(date)
+	if end.After(time.Now()) {
+		return nil, ErrBreakGlassReportNotReady
+	}
+	stored, err := s.breakGlassRepo.GetBreakGlassReport(ctx, pgtype.Date{Time: start, Valid: true})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return s.storeReport(ctx, start)
+	case err != nil:
+		s.logger.ErrorContext(ctx, "Failed to get report", "date", start.Format(breakglass.DateLayout), "error", err)
+		return nil, fmt.Errorf("failed to get break-glass report: %w", err)
+	}
+	return decodeBreakGlassReport(stored)
+}
+
+func (s *breakGlassService) ListReports(ctx context.Context, params model.PaginationParams) ([]model.BreakGlassReportSummary, int64, error) {
+	ctx, span := tracing.Start(ctx, "BreakGlassService.ListReports")
+	defer span.End()
+	rows, err := s.breakGlassRepo.ListBreakGlassReports(ctx, db.ListBreakGlassReportsParams{
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list reports", "error", err)
+		return nil, 0, fmt.Errorf("failed to list break-glass reports: %w", err)
+	}
+	total, err := s.breakGlassRepo.CountBreakGlassReports(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count reports", "error", err)
+		return nil, 0, fmt.Errorf("failed to count break-glass reports: %w", err)
+	}
+	reports := make([]model.BreakGlassReportSummary, 0, len(rows))
+	for _, r := range rows {
+		reports = append(reports, mapper.MapBreakGlassReportSummary(r))
+	}
+	return reports, total, nil
+}
+
+// GenerateDueReports stores the review of the day before now. Safe to run on every
+// replica: a report that already exists is left alone.
+func (s *breakGlassService) GenerateDueReports(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.Start(ctx, "BreakGlassService.GenerateDueReports")
+	defer span.End()
+	day := breakglass.PreviousDay(now)
+	_, err := s.breakGlassRepo.GetBreakGlassReport(ctx, pgtype.Date{Time: day, Valid: true})
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to check break-glass report for %s: %w", day.Format(breakglass.DateLayout), err)
+	}
+	_, err = s.storeReport(ctx, day)
+	return err
+}
+
+// storeReport builds the review of the day starting at day and stores it. If another
+// replica stored it first, theirs is returned.
+func (s *breakGlassService) storeReport(ctx context.Context, day time.Time) (*model.BreakGlassReport, error) {
+	start, end := breakglass.DayBounds(day)
+	windowStart := pgtype.Timestamptz{Time: start, Valid: true}
+	windowEnd := pgtype.Timestamptz{Time: end, Valid: true}
+	grantRows, err := s.breakGlassRepo.ListBreakGlassGrantsForReview(ctx, db.ListBreakGlassGrantsForReviewParams{WindowStart: windowStart, WindowEnd: windowEnd})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list grants", "date", start.Format(breakglass.DateLayout), "error", err)
+		return nil, fmt.Errorf("failed to list break-glass grants: %w", err)
+	}
+	accessRows, err := s.breakGlassRepo.ListBreakGlassAccesses(ctx, db.ListBreakGlassAccessesParams{WindowStart: windowStart, WindowEnd: windowEnd})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list accesses", "date", start.Format(breakglass.DateLayout), "error", err)
+		return nil, fmt.Errorf("failed to list break-glass accesses: %w", err)
+	}
+
+	grants := make([]breakglass.Grant, 0, len(grantRows))
+	for _, g := range grantRows {
+		grants = append(grants, mapper.MapBreakGlassReviewGrant(g))
+	}
+	accesses := make([]model.BreakGlassAccess, 0, len(accessRows))
+	for _, a := range accessRows {
+		accesses = append(accesses, mapper.MapBreakGlassAccess(a))
+	}
+	report := breakglass.BuildReport(start, grants, accesses, time.Now().UTC().Truncate(audit.Precision))
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode break-glass report: %w", err)
+	}
+	_, err = s.breakGlassRepo.CreateBreakGlassReport(ctx, db.CreateBreakGlassReportParams{
+		ReportDate:  pgtype.Date{Time: start, Valid: true},
+		GrantCount:  int32(report.GrantCount),
+		AccessCount: int32(report.AccessCount),
+		Report:      body,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		stored, err := s.breakGlassRepo.GetBreakGlassReport(ctx, pgtype.Date{Time: start, Valid: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get break-glass report: %w", err)
+		}
+		return decodeBreakGlassReport(stored)
+	case err != nil:
+		s.logger.ErrorContext(ctx, "Failed to store report", "date", report.Date, "error", err)
+		return nil, fmt.Errorf("failed to store break-glass report: %w", err)
+	}
+	s.logger.InfoContext(ctx, "Stored review", "date", report.Date, "grant_count", report.GrantCount, "access_count", report.AccessCount)
+	return &report, nil
+}
+
+func decodeBreakGlassReport(stored db.BreakGlassReport) (*model.BreakGlassReport, error) {
+	var report model.BreakGlassReport
+	if err := json.Unmarshal(stored.Report, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode break-glass report for %s: %w", stored.ReportDate.Time.Format(breakglass.DateLayout), err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrCareTeamMemberNotDoctor = errors.New("only doctors can be assigned to a care team")
+var ErrCareTeamMemberExists = errors.New("user is already on this patient's care team")
+var ErrCareTeamMemberNotFound = errors.New("user is not on this patient's care team")
+
+const auditResourceCareTeam = "care_team"
+
+type careTeamService struct {
+	careTeamRepo repository.CareTeamRepository
+	tx           repository.TxManager // Changes the care team together with its audit event
+	patientRepo  repository.PatientRepository
+	userRepo     repository.UserRepository
+	breakGlass   BreakGlassService
+	auditor      AuditRecorder
+	logger       *slog.Logger
+}
+
+func NewCareTeamService(careTeamRepo repository.CareTeamRepository, tx repository.TxManager, patientRepo repository.PatientRepository, userRepo repository.UserRepository, breakGlass BreakGlassService, auditor AuditRecorder, logger *slog.Logger) CareTeamService {
+	return &careTeamService{careTeamRepo: careTeamRepo, tx: tx, patientRepo: patientRepo, userRepo: userRepo, breakGlass: breakGlass, auditor: auditor, logger: logger.With("service", "CareTeamService")}
+}
+
+// AuthorizePatientAccess scopes doctors to the patients in their care: those they are on
+// the care team of or have recorded a visit for, plus any they hold a break-glass grant
+// for. Everyone else sees demographics only. Calls without an authenticated user come
+// from inside the application and see the whole record.
+func (s *careTeamService) AuthorizePatientAccess(ctx context.Context, patientID uuid.UUID) (context.Context, PatientAccessLevel, error) {
+	ctx, span := tracing.Start(ctx, "CareTeamService.AuthorizePatientAccess")
+	defer span.End()
+	md := audit.FromContext(ctx)
+	if md.ActorUserID == nil {
+		return ctx, PatientAccessClinical, nil
+	}
+	if md.ActorRole != model.RoleDoctor {
+		return ctx, PatientAccessDemographics, nil
+	}
+
+	inCare, err := s.careTeamRepo.IsInCareOf(ctx, db.IsInCareOfParams{
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		UserID:    pgtype.UUID{Bytes: *md.ActorUserID, Valid: true},
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to check care of patient", "patient_id", patientID, "user_id", *md.ActorUserID, "error", err)
+		return ctx, 0, fmt.Errorf("failed to check patient access: %w", err)
+	}
+	if inCare {
+		return ctx, PatientAccessClinical, nil
+	}
+
+	grantID, err := s.breakGlass.ActiveGrant(ctx, *md.ActorUserID, patientID)
+	if err != nil {
+		return ctx, 0, err
+	}
+	if grantID == nil {
+		// Out of scope looks the same as not existing, so IDs cannot be probed.
+		return ctx, 0, ErrPatientNotFound
+	}
+	s.logger.WarnContext(ctx, "BREAK-GLASS access", "user_id", *md.ActorUserID, "patient_id", patientID, "grant_id", *grantID)
+	return audit.WithBreakGlass(ctx, *grantID), PatientAccessClinical, nil
+}
+
+func (s *careTeamService) PatientListScope(ctx context.Context) (*uuid.UUID, PatientAccessLevel) {
+	ctx, span := tracing.Start(ctx, "CareTeamService.PatientListScope")
+	defer span.End()
+	md := audit.FromContext(ctx)
+	switch {
+	case md.ActorUserID == nil:
+		return nil, PatientAccessClinical
+	case md.ActorRole == model.RoleDoctor:
+		return md.ActorUserID, PatientAccessClinical
+	default:
+		return nil, PatientAccessDemographics
+	}
+}
+
+// AddMember assigns a doctor to the patient's care team. A doctor may only assign
+// colleagues to patients in their own care.
+func (s *careTeamService) AddMember(ctx context.Context, patientID uuid.UUID, req model.CareTeamMemberRequest, assignedByUserID uuid.UUID) (*model.CareTeamMember, error) {
+	ctx, span := tracing.Start(ctx, "CareTeamService.AddMember")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, pgtype.UUID{Bytes: req.UserID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get user", "user_id", req.UserID, "error", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if model.UserRole(user.Role) != model.RoleDoctor {
+		return nil, ErrCareTeamMemberNotDoctor
+	}
+
+	var member db.CareTeamMember
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		member, err = r.CareTeam.AddCareTeamMember(ctx, db.AddCareTeamMemberParams{
+			PatientID:        pgtype.UUID{Bytes: patientID, Valid: true},
+			UserID:           user.ID,
+			AssignedByUserID: pgtype.UUID{Bytes: assignedByUserID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: auditResourceCareTeam,
+			ResourceID:   &req.UserID,
+			PatientID:    &patientID,
+			Changes:      map[string]model.FieldChange{"user_id": {New: req.UserID.String()}},
+		})
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrCareTeamMemberExists
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to add user to care team", "user_id", req.UserID, "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to add care team member: %w", err)
+	}
+
+	return &model.CareTeamMember{
+		PatientID:        patientID,
+		UserID:           req.UserID,
+		Username:         user.Username,
+		FirstName:        optionalText(user.FirstName.String),
+		LastName:         optionalText(user.LastName.String),
+		Role:             model.UserRole(user.Role),
+		AssignedByUserID: assignedByUserID,
+		AssignedAt:       member.AssignedAt.Time,
+	}, nil
+}
+
+func (s *careTeamService) RemoveMember(ctx context.Context, patientID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "CareTeamService.RemoveMember")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return err
+	}
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if _, err := r.CareTeam.RemoveCareTeamMember(ctx, db.RemoveCareTeamMemberParams{
+			PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+			UserID:    pgtype.UUID{Bytes: userID, Valid: true},
+		}); err != nil {
+			return err
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionDelete,
+			ResourceType: auditResourceCareTeam,
+			ResourceID:   &userID,
+			PatientID:    &patientID,
+			Changes:      map[string]model.FieldChange{"user_id": {Old: userID.String()}},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCareTeamMemberNotFound
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return err
+		}
+		s.logger.ErrorContext(ctx, "Failed to remove user from care team", "user_id", userID, "patient_id", patientID, "error", err)
+		return fmt.Errorf("failed to remove care team member: %w", err)
+	}
+	return nil
+}
+
+func (s *careTeamService) ListMembers(ctx context.Context, patientID uuid.UUID) ([]model.CareTeamMember, error) {
+	ctx, span := tracing.Start(ctx, "CareTeamService.ListMembers")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return nil, err
+	}
+	rows, err := s.careTeamRepo.ListCareTeamMembers(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list care team", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to list care team: %w", err)
+	}
+	members := make([]model.CareTeamMember, 0, len(rows))
+	for _, r := range rows {
+		members = append(members, mapper.MapCareTeamMember(r))
+	}
+	return members, nil
+}
+
+// checkPatient returns ErrPatientNotFound unless the patient exists and is in the
+// caller's scope.
+func (s *careTeamService) checkPatient(ctx context.Context, patientID uuid.UUID) error {
+	if _, _, err := s.AuthorizePatientAccess(ctx, patientID); err != nil {
+		return err
+	}
+	if _, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return fmt.Errorf("failed to get patient: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// careTeams maps each patient to the users on their care team.
+type careTeams map[uuid.UUID][]uuid.UUID
+
+func (c careTeams) has(patientID, userID uuid.UUID) bool {
+	for _, member := range c[patientID] {
+		if member == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeCareTeamRepo struct {
+	repository.CareTeamRepository
+	teams careTeams
+}
+
+func (f *fakeCareTeamRepo) IsInCareOf(_ context.Context, arg db.IsInCareOfParams) (bool, error) {
+	return f.teams.has(arg.PatientID.Bytes, arg.UserID.Bytes), nil
+}
+
+func (f *fakeCareTeamRepo) RemoveCareTeamMember(_ context.Context, arg db.RemoveCareTeamMemberParams) (db.CareTeamMember, error) {
+	if !f.teams.has(arg.PatientID.Bytes, arg.UserID.Bytes) {
+		return db.CareTeamMember{}, pgx.ErrNoRows
+	}
+	return db.CareTeamMember{PatientID: arg.PatientID, UserID: arg.UserID}, nil
+}
+
+type fakeBreakGlass struct {
+	BreakGlassService
+	grants map[[2]uuid.UUID]uuid.UUID // (user, patient) -> grant
+}
+
+func (f *fakeBreakGlass) ActiveGrant(_ context.Context, userID, patientID uuid.UUID) (*uuid.UUID, error) {
+	if grantID, ok := f.grants[[2]uuid.UUID{userID, patientID}]; ok {
+		return &grantID, nil
+	}
+	return nil, nil
+}
+
+type fakePatientRepo struct {
+	repository.PatientRepository
+	patients []db.Patient
+	teams    careTeams
+}
+
+func (f *fakePatientRepo) GetPatientByID(_ context.Context, id pgtype.UUID) (db.Patient, error) {
+	for _, p := range f.patients {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return db.Patient{}, pgx.ErrNoRows
+}
+
+func (f *fakePatientRepo) ListPatients(context.Context, db.ListPatientsParams) ([]db.Patient, error) {
+	return f.patients, nil
+}
+
+func (f *fakePatientRepo) CountPatients(context.Context) (int64, error) {
+	return int64(len(f.patients)), nil
+}
+
+func (f *fakePatientRepo) ListPatientsInCareOf(_ context.Context, arg db.ListPatientsInCareOfParams) ([]db.Patient, error) {
+	var inCare []db.Patient
+	for _, p := range f.patients {
+		if f.teams.has(p.ID.Bytes, arg.UserID.Bytes) {
+			inCare = append(inCare, p)
+		}
+	}
+	return inCare, nil
+}
+
+func (f *fakePatientRepo) CountPatientsInCareOf(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	inCare, _ := f.ListPatientsInCareOf(ctx, db.ListPatientsInCareOfParams{UserID: userID})
+	return int64(len(inCare)), nil
+}
+
+type fakeAuditor struct{ entries []model.AuditEntry }
+
+func (f *fakeAuditor) Record(_ context.Context, entry model.AuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditor) RecordTx(ctx context.Context, _ repository.Repos, entry model.AuditEntry) error {
+	return f.Record(ctx, entry)
+}
+
+// unavailableAuditor fails every append, as when the audit trail cannot be written.
+type unavailableAuditor struct{}
+
+func (unavailableAuditor) Record(context.Context, model.AuditEntry) error {
+	return fmt.Errorf("%w: connection refused", ErrAuditUnavailable)
+}
+
+func (a unavailableAuditor) RecordTx(ctx context.Context, _ repository.Repos, entry model.AuditEntry) error {
+	return a.Record(ctx, entry)
+}
+
+// careTeamFixture is a doctor with one patient in their care, one outside it and one
+// they hold a break-glass grant for.
+type careTeamFixture struct {
+	doctor, inCare, outOfCare, granted, grant uuid.UUID
+	teams                                     careTeams
+	patients                                  *fakePatientRepo
+	access                                    CareTeamService
+}
+
+func newCareTeamFixture() careTeamFixture {
+	f := careTeamFixture{doctor: uuid.New(), inCare: uuid.New(), outOfCare: uuid.New(), granted: uuid.New(), grant: uuid.New()}
+	f.teams = careTeams{f.inCare: {f.doctor}}
+	f.patients = &fakePatientRepo{teams: f.teams}
+	for _, id := range []uuid.UUID{f.inCare, f.outOfCare, f.granted} {
+		f.patients.patients = append(f.patients.patients, db.Patient{
+			ID:             pgtype.UUID{Bytes: id, Valid: true},
+			FirstName:      "Asha",
+			MedicalHistory: pgtype.Text{String: "asthma", Valid: true},
+		})
+	}
+	breakGlass := &fakeBreakGlass{grants: map[[2]uuid.UUID]uuid.UUID{{f.doctor, f.granted}: f.grant}}
+	f.access = NewCareTeamService(&fakeCareTeamRepo{teams: f.teams}, nil, f.patients, nil, breakGlass, &fakeAuditor{}, discardLogger)
+	return f
+}
+
+func TestAuthorizePatientAccess(t *testing.T) {
+	f := newCareTeamFixture()
+	doctor := audit.WithActor(context.Background(), f.doctor, model.RoleDoctor)
+	receptionist := audit.WithActor(context.Background(), uuid.New(), model.RoleReceptionist)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		patientID uuid.UUID
+		wantLevel PatientAccessLevel
+		wantErr   error
+		wantGrant *uuid.UUID
+	}{
+		{name: "doctor on the care team", ctx: doctor, patientID: f.inCare, wantLevel: PatientAccessClinical},
+		{name: "doctor outside the care team", ctx: doctor, patientID: f.outOfCare, wantErr: ErrPatientNotFound},
+		{name: "doctor with a break-glass grant", ctx: doctor, patientID: f.granted, wantLevel: PatientAccessClinical, wantGrant: &f.grant},
+		{name: "receptionist", ctx: receptionist, patientID: f.outOfCare, wantLevel: PatientAccessDemographics},
+		{name: "no authenticated user", ctx: context.Background(), patientID: f.outOfCare, wantLevel: PatientAccessClinical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, level, err := f.access.AuthorizePatientAccess(tt.ctx, tt.patientID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLevel, level)
+			assert.Equal(t, tt.wantGrant, audit.FromContext(ctx).BreakGlassGrantID)
+		})
+	}
+}
+
+func TestGetPatientDetailsRedactsForReceptionists(t *testing.T) {
+	f := newCareTeamFixture()
+	patients := NewPatientService(f.patients, nil, &fakeAuditor{}, f.access, discardLogger)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantHistory *string
+	}{
+		{name: "doctor on the care team", ctx: audit.WithActor(context.Background(), f.doctor, model.RoleDoctor), wantHistory: ptr("asthma")},
+		{name: "receptionist", ctx: audit.WithActor(context.Background(), uuid.New(), model.RoleReceptionist)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patient, err := patients.GetPatientDetails(tt.ctx, f.inCare)
+			require.NoError(t, err)
+			assert.Equal(t, "Asha", patient.FirstName)
+			assert.Equal(t, tt.wantHistory, patient.MedicalHistory)
+		})
+	}
+}
+
+func TestListPatientsIsScopedToTheCareTeam(t *testing.T) {
+	f := newCareTeamFixture()
+	patients := NewPatientService(f.patients, nil, &fakeAuditor{}, f.access, discardLogger)
+	params := model.PaginationParams{Limit: 10}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantIDs     []uuid.UUID
+		wantHistory bool
+	}{
+		{
+			name:        "doctor sees only the care team's patients",
+			ctx:         audit.WithActor(context.Background(), f.doctor, model.RoleDoctor),
+			wantIDs:     []uuid.UUID{f.inCare},
+			wantHistory: true,
+		},
+		{
+			name:    "receptionist sees everyone without medical history",
+			ctx:     audit.WithActor(context.Background(), uuid.New(), model.RoleReceptionist),
+			wantIDs: []uuid.UUID{f.inCare, f.outOfCare, f.granted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, total, err := patients.ListPatients(tt.ctx, params)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(tt.wantIDs), total)
+			var ids []uuid.UUID
+			for _, p := range list {
+				ids = append(ids, p.ID)
+				assert.Equal(t, tt.wantHistory, p.MedicalHistory != nil)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRemoveMemberFailsWhenTheAuditEventIsNotRecorded(t *testing.T) {
+	f := newCareTeamFixture()
+	careTeam := &fakeCareTeamRepo{teams: f.teams}
+	doctor := audit.WithActor(context.Background(), f.doctor, model.RoleDoctor)
+
+	auditor := &fakeAuditor{}
+	service := NewCareTeamService(careTeam, fakeTx{careTeam: careTeam}, f.patients, nil, &fakeBreakGlass{}, auditor, discardLogger)
+	require.NoError(t, service.RemoveMember(doctor, f.inCare, f.doctor))
+	require.Len(t, auditor.entries, 1)
+	assert.Equal(t, model.AuditActionDelete, auditor.entries[0].Action)
+
+	// The removal and its event share a transaction, so the error rolls both back.
+	service = NewCareTeamService(careTeam, fakeTx{careTeam: careTeam}, f.patients, nil, &fakeBreakGlass{}, unavailableAuditor{}, discardLogger)
+	assert.ErrorIs(t, service.RemoveMember(doctor, f.inCare, f.doctor), ErrAuditUnavailable)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/consent"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrConsentRecordNotFound = errors.New("consent record not found")
+var ErrConsentAlreadyWithdrawn = errors.New("consent already withdrawn")
+var ErrConsentConflict = errors.New("consent changed while it was being recorded, try again")
+
+// consentSupersededReason is recorded on a consent replaced by a newer signature.
+const consentSupersededReason = "superseded by a newer consent"
+
+type consentService struct {
+	consentRepo repository.ConsentRepository
+	tx          repository.TxManager
+	patientRepo repository.PatientRepository
+	auditor     AuditRecorder
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
+}
+
+func NewConsentService(consentRepo repository.ConsentRepository, tx repository.TxManager, patientRepo repository.PatientRepository, auditor AuditRecorder, access PatientAccessAuthorizer, logger *slog.Logger) ConsentService {
+	return &consentService{consentRepo: consentRepo, tx: tx, patientRepo: patientRepo, auditor: auditor, access: access, logger: logger.With("service", "ConsentService")}
+}
+
+func (s *consentService) ListConsentTypes() []model.ConsentTypeInfo {
+	return model.ConsentTypes
+}
+
+// RecordConsent stores a signed consent witnessed by witnessUserID. A consent of the same
+// type still in force is withdrawn as superseded in the same transaction.
+func (s *consentService) RecordConsent(ctx context.Context, patientID uuid.UUID, req model.ConsentCreateRequest, witnessUserID uuid.UUID) (*model.ConsentRecord, error) {
+	ctx, span := tracing.Start(ctx, "ConsentService.RecordConsent")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	signedAt := now
+	if req.SignedAtStr != "" {
+		parsed, err := time.Parse(time.RFC3339, req.SignedAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signed_at format: %w. Expected RFC3339", err)
+		}
+		signedAt = parsed
+	}
+	if err := consent.ValidateSignedAt(signedAt, now); err != nil {
+		return nil, err
+	}
+
+	var record model.ConsentRecord
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		superseded, err := r.Consent.WithdrawActiveConsent(ctx, db.WithdrawActiveConsentParams{
+			WithdrawnAt:       pgtype.Timestamptz{Time: now, Valid: true},
+			WithdrawnByUserID: pgtype.UUID{Bytes: witnessUserID, Valid: true},
+			WithdrawalReason:  pgtype.Text{String: consentSupersededReason, Valid: true},
+			PatientID:         pgtype.UUID{Bytes: patientID, Valid: true},
+			ConsentType:       db.ConsentType(req.Type),
+		})
+		if err != nil {
+			return err
+		}
+		created, err := r.Consent.CreateConsentRecord(ctx, db.CreateConsentRecordParams{
+			PatientID:         pgtype.UUID{Bytes: patientID, Valid: true},
+			ConsentType:       db.ConsentType(req.Type),
+			TextVersion:       req.TextVersion,
+			SignedAt:          pgtype.Timestamptz{Time: signedAt, Valid: true},
+			WitnessedByUserID: pgtype.UUID{Bytes: witnessUserID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, old := range superseded {
+			// old is the row as updated; before the update it had no withdrawal.
+			before, after := mapper.MapConsentRecord(old), mapper.MapConsentRecord(old)
+			before.WithdrawnAt, before.WithdrawnByUserID, before.WithdrawalReason = nil, nil, nil
+			if err := s.auditor.RecordTx(ctx, r, model.AuditEntry{
+				Action:       model.AuditActionUpdate,
+				ResourceType: model.AuditResourceConsent,
+				ResourceID:   &after.ID,
+				PatientID:    &patientID,
+				Changes:      auditDiff(ctx, s.logger, before, after),
+			}); err != nil {
+				return err
+			}
+		}
+		record = mapper.MapConsentRecord(created)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourceConsent,
+			ResourceID:   &record.ID,
+			PatientID:    &patientID,
+			Changes:      auditDiff(ctx, s.logger, nil, record),
+		})
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrConsentConflict
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to record consent", "type", req.Type, "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to record consent: %w", err)
+	}
+	return &record, nil
+}
+
+func (s *consentService) ListConsents(ctx context.Context, patientID uuid.UUID) ([]model.ConsentRecord, error) {
+	ctx, span := tracing.Start(ctx, "ConsentService.ListConsents")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return nil, err
+	}
+	rows, err := s.consentRepo.ListConsentRecordsByPatientID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list consents", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to list consents: %w", err)
+	}
+	records := make([]model.ConsentRecord, 0, len(rows))
+	for _, r := range rows {
+		records = append(records, mapper.MapConsentRecord(r))
+	}
+	return records, nil
+}
+
+// WithdrawConsent records that the patient withdrew a consent. withdrawnByUserID is the
+// member of staff who took the withdrawal.
+func (s *consentService) WithdrawConsent(ctx context.Context, patientID, consentID uuid.UUID, req model.ConsentWithdrawRequest, withdrawnByUserID uuid.UUID) (*model.ConsentRecord, error) {
+	ctx, span := tracing.Start(ctx, "ConsentService.WithdrawConsent")
+	defer span.End()
+	if err := s.checkPatient(ctx, patientID); err != nil {
+		return nil, err
+	}
+	existing, err := s.consentRepo.GetConsentRecordByID(ctx, db.GetConsentRecordByIDParams{
+		ID:        pgtype.UUID{Bytes: consentID, Valid: true},
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConsentRecordNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get consent", "consent_id", consentID, "error", err)
+		return nil, fmt.Errorf("failed to get consent: %w", err)
+	}
+	if existing.WithdrawnAt.Valid {
+		return nil, ErrConsentAlreadyWithdrawn
+	}
+
+	before := mapper.MapConsentRecord(existing)
+	var after model.ConsentRecord
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		withdrawn, err := r.Consent.WithdrawConsentRecord(ctx, db.WithdrawConsentRecordParams{
+			WithdrawnAt:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			WithdrawnByUserID: pgtype.UUID{Bytes: withdrawnByUserID, Valid: true},
+			WithdrawalReason:  textParam(req.Reason),
+			ID:                existing.ID,
+		})
+		if err != nil {
+			return err
+		}
+		after = mapper.MapConsentRecord(withdrawn)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourceConsent,
+			ResourceID:   &after.ID,
+			PatientID:    &patientID,
+			Changes:      auditDiff(ctx, s.logger, before, after),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConsentAlreadyWithdrawn
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to withdraw consent", "consent_id", consentID, "error", err)
+		return nil, fmt.Errorf("failed to withdraw consent: %w", err)
+	}
+	s.logger.InfoContext(ctx, "Patient withdrew consent", "patient_id", patientID, "type", after.Type, "consent_id", after.ID)
+	return &after, nil
+}
+
+// RequireConsent returns a consent.RefusedError unless the patient's consent of type t is
+// in force. Call it before acting on the patient's behalf outside the hospital.
+func (s *consentService) RequireConsent(ctx context.Context, patientID uuid.UUID, t model.ConsentType) error {
+	ctx, span := tracing.Start(ctx, "ConsentService.RequireConsent")
+	defer span.End()
+	latest, err := s.consentRepo.GetLatestConsentRecord(ctx, db.GetLatestConsentRecordParams{
+		PatientID:   pgtype.UUID{Bytes: patientID, Valid: true},
+		ConsentType: db.ConsentType(t),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return consent.Check(t, nil)
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to check consent", "type", t, "patient_id", patientID, "error", err)
+		return fmt.Errorf("failed to check consent: %w", err)
+	}
+	record := mapper.MapConsentRecord(latest)
+	return consent.Check(t, &record)
+}
+
+// checkPatient returns ErrPatientNotFound unless the patient exists and is in the
+// caller's scope.
+func (s *consentService) checkPatient(ctx context.Context, patientID uuid.UUID) error {
+	if _, _, err := s.access.AuthorizePatientAccess(ctx, patientID); err != nil {
+		return err
+	}
+	if _, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return fmt.Errorf("failed to get patient: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// reencryptionBatchSize is how many rows are read per page while re-encrypting.
+const reencryptionBatchSize = 500
+
+type fieldEncryptionService struct {
+	repo   repository.FieldEncryptionRepository
+	logger *slog.Logger
+}
+
+func NewFieldEncryptionService(repo repository.FieldEncryptionRepository, logger *slog.Logger) FieldEncryptionService {
+	return &fieldEncryptionService{repo: repo, logger: logger.With("service", "FieldEncryptionService")}
+}
+
+// Reencrypt walks every patient, visit and visit revision in ID order and rewrites the
+// rows holding plaintext or values sealed under an old master key. It is safe to run while
+// the application is serving and to run again after an interruption.
+func (s *fieldEncryptionService) Reencrypt(ctx context.Context) (*model.ReencryptionResult, error) {
+	ctx, span := tracing.Start(ctx, "FieldEncryptionService.Reencrypt")
+	defer span.End()
+	var result model.ReencryptionResult
+	afterID := pgtype.UUID{Valid: true}
+	for {
+		batch, err := s.repo.ReencryptPatients(ctx, afterID, reencryptionBatchSize)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to re-encrypt patients", "after_id", uuid.UUID(afterID.Bytes), "error", err)
+			return &result, fmt.Errorf("failed to re-encrypt patients: %w", err)
+		}
+		result.PatientsScanned += batch.Scanned
+		result.PatientsRewritten += batch.Rewritten
+		result.Skipped += batch.Skipped
+		if batch.Scanned < reencryptionBatchSize {
+			break
+		}
+		afterID = batch.LastID
+	}
+
+	afterID = pgtype.UUID{Valid: true}
+	for {
+		batch, err := s.repo.ReencryptPatientVisits(ctx, afterID, reencryptionBatchSize)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to re-encrypt visits", "after_id", uuid.UUID(afterID.Bytes), "error", err)
+			return &result, fmt.Errorf("failed to re-encrypt visits: %w", err)
+		}
+		result.VisitsScanned += batch.Scanned
+		result.VisitsRewritten += batch.Rewritten
+		result.Skipped += batch.Skipped
+		if batch.Scanned < reencryptionBatchSize {
+			break
+		}
+		afterID = batch.LastID
+	}
+
+	afterID = pgtype.UUID{Valid: true}
+	for {
+		batch, err := s.repo.ReencryptPatientVisitRevisions(ctx, afterID, reencryptionBatchSize)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to re-encrypt visit revisions", "after_id", uuid.UUID(afterID.Bytes), "error", err)
+			return &result, fmt.Errorf("failed to re-encrypt visit revisions: %w", err)
+		}
+		result.RevisionsScanned += batch.Scanned
+		result.RevisionsRewritten += batch.Rewritten
+		result.Skipped += batch.Skipped
+		if batch.Scanned < reencryptionBatchSize {
+			break
+		}
+		afterID = batch.LastID
+	}
+	return &result, nil
+}
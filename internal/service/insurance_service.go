@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/insurance"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPolicyNotFound = errors.New("insurance policy not found")
+var ErrPolicyPriorityTaken = errors.New("patient already has an active policy with this priority")
+var ErrInvalidPolicyDates = errors.New("valid_to must not be before valid_from")
+var ErrPolicyPatientMismatch = errors.New("policy does not belong to the invoiced patient")
+var ErrPolicyNotValid = errors.New("policy is inactive or not valid on the invoice date")
+var ErrInvalidDiagnosisCode = errors.New("invalid ICD-10 diagnosis code")
+var ErrNothingToClaim = errors.New("invoice has no outstanding balance to claim")
+var ErrClaimNotFound = errors.New("insurance claim not found")
+var ErrClaimAlreadyOpen = errors.New("an open claim already exists for this invoice and policy")
+var ErrClaimInvalidTransition = errors.New("invalid claim status transition")
+var ErrClaimPaidExceedsClaimed = errors.New("paid amount exceeds the claimed amount")
+var ErrPayerUnavailable = errors.New("payer gateway unavailable")
+var ErrPayerGatewayNotConfigured = errors.New("no payer gateway is configured")
+var ErrClaimAlreadySent = errors.New("claim was already acknowledged by the payer")
+
+type insuranceService struct {
+	insuranceRepo  repository.InsuranceRepository
+	patientRepo    repository.PatientRepository
+	billingService BillingService
+	consents       ConsentChecker // Sending a claim or an eligibility check shares the patient's data with the payer
+	tx             repository.TxManager // Changes a claim together with the payment it posts
+	gateway        insurance.PayerGateway
+	logger         *slog.Logger
+}
+
+// NewInsuranceService returns the insurance service. gateway may be nil when no payer
+// integration is configured; eligibility checks and claims then fail with
+// ErrPayerGatewayNotConfigured. Nothing is sent to a payer without the patient's data
+// sharing consent.
+func NewInsuranceService(insuranceRepo repository.InsuranceRepository, patientRepo repository.PatientRepository, billingService BillingService, consents ConsentChecker, tx repository.TxManager, gateway insurance.PayerGateway, logger *slog.Logger) InsuranceService {
+	return &insuranceService{insuranceRepo: insuranceRepo, patientRepo: patientRepo, billingService: billingService, consents: consents, tx: tx, gateway: gateway, logger: logger.With("service", "InsuranceService")}
+}
+
+func (s *insuranceService) AddPolicy(ctx context.Context, patientID uuid.UUID, req model.InsurancePolicyCreateRequest, createdByUserID uuid.UUID) (*model.InsurancePolicy, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.AddPolicy")
+	defer span.End()
+	validFrom, err := time.Parse("2006-01-02", req.ValidFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid valid_from format: %w. Expected YYYY-MM-DD", err)
+	}
+	validTo := pgtype.Date{}
+	if req.ValidTo != nil {
+		to, err := time.Parse("2006-01-02", *req.ValidTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid valid_to format: %w. Expected YYYY-MM-DD", err)
+		}
+		if to.Before(validFrom) {
+			return nil, ErrInvalidPolicyDates
+		}
+		validTo = pgtype.Date{Time: to, Valid: true}
+	}
+
+	if _, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking patient for new policy", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("error verifying patient for policy: %w", err)
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = model.PolicyPriorityPrimary
+	}
+	policy, err := s.insuranceRepo.CreateInsurancePolicy(ctx, db.CreateInsurancePolicyParams{
+		PatientID:       pgtype.UUID{Bytes: patientID, Valid: true},
+		PayerCode:       req.PayerCode,
+		PayerName:       req.PayerName,
+		PlanName:        pgtype.Text{String: derefString(req.PlanName), Valid: req.PlanName != nil},
+		MemberID:        req.MemberID,
+		GroupNumber:     pgtype.Text{String: derefString(req.GroupNumber), Valid: req.GroupNumber != nil},
+		ValidFrom:       pgtype.Date{Time: validFrom, Valid: true},
+		ValidTo:         validTo,
+		Priority:        db.PolicyPriority(priority),
+		CreatedByUserID: pgtype.UUID{Bytes: createdByUserID, Valid: true},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: %s", ErrPolicyPriorityTaken, priority)
+		}
+		s.logger.ErrorContext(ctx, "Failed to add policy", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to add insurance policy: %w", err)
+	}
+	mapped := mapper.MapInsurancePolicy(policy)
+	return &mapped, nil
+}
+
+func (s *insuranceService) ListPatientPolicies(ctx context.Context, patientID uuid.UUID) ([]model.InsurancePolicy, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.ListPatientPolicies")
+	defer span.End()
+	policies, err := s.insuranceRepo.ListInsurancePoliciesByPatientID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list policies", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to list insurance policies: %w", err)
+	}
+	mapped := make([]model.InsurancePolicy, 0, len(policies))
+	for _, p := range policies {
+		mapped = append(mapped, mapper.MapInsurancePolicy(p))
+	}
+	return mapped, nil
+}
+
+func (s *insuranceService) DeactivatePolicy(ctx context.Context, policyID uuid.UUID) (*model.InsurancePolicy, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.DeactivatePolicy")
+	defer span.End()
+	policy, err := s.insuranceRepo.DeactivateInsurancePolicy(ctx, pgtype.UUID{Bytes: policyID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPolicyNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to deactivate policy", "policy_id", policyID, "error", err)
+		return nil, fmt.Errorf("failed to deactivate insurance policy: %w", err)
+	}
+	mapped := mapper.MapInsurancePolicy(policy)
+	return &mapped, nil
+}
+
+func (s *insuranceService) getPolicy(ctx context.Context, policyID uuid.UUID) (*model.InsurancePolicy, error) {
+	policy, err := s.insuranceRepo.GetInsurancePolicyByID(ctx, pgtype.UUID{Bytes: policyID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPolicyNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get policy", "policy_id", policyID, "error", err)
+		return nil, fmt.Errorf("failed to get insurance policy: %w", err)
+	}
+	mapped := mapper.MapInsurancePolicy(policy)
+	return &mapped, nil
+}
+
+// VerifyEligibility asks the payer whether the policy covers dateOfService and records the answer.
+// When the payer cannot be reached the check is recorded as "unknown" rather than failing.
+func (s *insuranceService) VerifyEligibility(ctx context.Context, policyID uuid.UUID, dateOfService time.Time, checkedByUserID uuid.UUID) (*model.EligibilityCheck, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.VerifyEligibility")
+	defer span.End()
+	policy, err := s.getPolicy(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp insurance.EligibilityResponse
+	if !policy.CoversDate(dateOfService) {
+		// No need to ask the payer about a policy we already know does not apply.
+		resp = insurance.EligibilityResponse{Status: model.EligibilityIneligible, Message: "policy inactive or outside its validity period"}
+	} else if s.gateway == nil {
+		return nil, ErrPayerGatewayNotConfigured
+	} else {
+		if err := s.requireDataSharing(ctx, policy.PatientID); err != nil {
+			return nil, err
+		}
+		resp, err = s.gateway.CheckEligibility(ctx, insurance.EligibilityRequest{
+			PayerCode:     policy.PayerCode,
+			MemberID:      policy.MemberID,
+			GroupNumber:   derefString(policy.GroupNumber),
+			DateOfService: dateOfService,
+		})
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Eligibility check failed", "policy_id", policyID, "error", err)
+			resp = insurance.EligibilityResponse{Status: model.EligibilityUnknown, Message: "payer could not be reached"}
+		}
+	}
+
+	var check db.EligibilityCheck
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		check, err = r.Insurance.CreateEligibilityCheck(ctx, db.CreateEligibilityCheckParams{
+			PolicyID:        pgtype.UUID{Bytes: policyID, Valid: true},
+			Status:          db.EligibilityStatus(resp.Status),
+			Message:         pgtype.Text{String: resp.Message, Valid: resp.Message != ""},
+			PayerReference:  pgtype.Text{String: resp.Reference, Valid: resp.Reference != ""},
+			CheckedByUserID: pgtype.UUID{Bytes: checkedByUserID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record eligibility check: %w", err)
+		}
+		if _, err := r.Insurance.UpdatePolicyEligibility(ctx, db.UpdatePolicyEligibilityParams{
+			ID:                pgtype.UUID{Bytes: policyID, Valid: true},
+			EligibilityStatus: db.EligibilityStatus(resp.Status),
+		}); err != nil {
+			return fmt.Errorf("failed to update policy eligibility: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to save eligibility for policy", "policy_id", policyID, "error", err)
+		return nil, fmt.Errorf("failed to save eligibility check: %w", err)
+	}
+	mapped := mapper.MapEligibilityCheck(check)
+	return &mapped, nil
+}
+
+// CreateClaim claims an invoice's outstanding balance from the policy's payer. The claim is
+// committed before it is sent, so no transaction is held open while the payer answers.
+func (s *insuranceService) CreateClaim(ctx context.Context, req model.InsuranceClaimCreateRequest, submittedByUserID uuid.UUID) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.CreateClaim")
+	defer span.End()
+	if s.gateway == nil {
+		return nil, ErrPayerGatewayNotConfigured
+	}
+	codes := make([]string, 0, len(req.DiagnosisCodes))
+	for _, c := range req.DiagnosisCodes {
+		code, ok := insurance.NormalizeDiagnosisCode(c)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDiagnosisCode, c)
+		}
+		codes = append(codes, code)
+	}
+
+	invoice, err := s.billingService.GetInvoice(ctx, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.getPolicy(ctx, req.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+	if policy.PatientID != invoice.PatientID {
+		return nil, ErrPolicyPatientMismatch
+	}
+	if !policy.CoversDate(invoice.IssuedAt) {
+		return nil, ErrPolicyNotValid
+	}
+	if invoice.BalanceMinor <= 0 {
+		return nil, ErrNothingToClaim
+	}
+	if err := s.requireDataSharing(ctx, invoice.PatientID); err != nil {
+		return nil, err
+	}
+
+	var claim db.InsuranceClaim
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		claim, err = r.Insurance.CreateInsuranceClaim(ctx, db.CreateInsuranceClaimParams{
+			InvoiceID:         pgtype.UUID{Bytes: invoice.ID, Valid: true},
+			PolicyID:          pgtype.UUID{Bytes: policy.ID, Valid: true},
+			PatientID:         pgtype.UUID{Bytes: invoice.PatientID, Valid: true},
+			DiagnosisCodes:    codes,
+			ClaimedMinor:      invoice.BalanceMinor,
+			SubmittedByUserID: pgtype.UUID{Bytes: submittedByUserID, Valid: true},
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrClaimAlreadyOpen
+			}
+			return fmt.Errorf("failed to create claim: %w", err)
+		}
+		if _, err := r.Insurance.CreateClaimStatusHistory(ctx, db.CreateClaimStatusHistoryParams{
+			ClaimID:         claim.ID,
+			Status:          db.ClaimStatusSubmitted,
+			ChangedByUserID: pgtype.UUID{Bytes: submittedByUserID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to record claim history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrClaimAlreadyOpen) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to create claim for invoice", "invoice_id", req.InvoiceID, "error", err)
+		return nil, fmt.Errorf("failed to create claim: %w", err)
+	}
+
+	if err := s.sendClaim(ctx, claim, *policy, invoice.InvoiceNumber); err != nil {
+		return nil, err
+	}
+	return s.GetClaim(ctx, claim.ID.Bytes)
+}
+
+// SendClaim sends a claim the payer has not acknowledged again, under the same claim ID.
+func (s *insuranceService) SendClaim(ctx context.Context, claimID uuid.UUID) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.SendClaim")
+	defer span.End()
+	if s.gateway == nil {
+		return nil, ErrPayerGatewayNotConfigured
+	}
+	claim, err := s.insuranceRepo.GetInsuranceClaimByID(ctx, pgtype.UUID{Bytes: claimID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClaimNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get claim", "claim_id", claimID, "error", err)
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+	if claim.SentToPayerAt.Valid || claim.Status != db.ClaimStatusSubmitted {
+		// A claim the payer has answered has reached it.
+		return nil, ErrClaimAlreadySent
+	}
+	// The consent may have been withdrawn since the claim was raised.
+	if err := s.requireDataSharing(ctx, claim.PatientID.Bytes); err != nil {
+		return nil, err
+	}
+	policy, err := s.getPolicy(ctx, claim.PolicyID.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.billingService.GetInvoice(ctx, claim.InvoiceID.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sendClaim(ctx, claim, *policy, invoice.InvoiceNumber); err != nil {
+		return nil, err
+	}
+	return s.GetClaim(ctx, claimID)
+}
+
+// sendClaim submits a committed claim to the payer and stores the acknowledgement. The claim
+// ID is the idempotency key, so sending it again after a timeout cannot raise a second claim
+// with the payer. A claim the payer did not acknowledge stays unsent, for SendClaim.
+func (s *insuranceService) sendClaim(ctx context.Context, claim db.InsuranceClaim, policy model.InsurancePolicy, invoiceNumber string) error {
+	ack, err := s.gateway.SubmitClaim(ctx, insurance.ClaimSubmission{
+		ClaimID:        claim.ID.Bytes,
+		PayerCode:      policy.PayerCode,
+		MemberID:       policy.MemberID,
+		GroupNumber:    derefString(policy.GroupNumber),
+		InvoiceNumber:  invoiceNumber,
+		DiagnosisCodes: claim.DiagnosisCodes,
+		ClaimedMinor:   claim.ClaimedMinor,
+	})
+	if err != nil {
+		s.logger.WarnContext(ctx, "Claim saved but not sent to the payer", "claim_id", claim.ID, "error", err)
+		return fmt.Errorf("%w: claim %s was saved but not sent: %v", ErrPayerUnavailable, uuid.UUID(claim.ID.Bytes), err)
+	}
+	if _, err := s.insuranceRepo.MarkInsuranceClaimSent(ctx, db.MarkInsuranceClaimSentParams{
+		ID:             claim.ID,
+		PayerReference: pgtype.Text{String: ack.Reference, Valid: ack.Reference != ""},
+	}); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		// The payer has the claim; sending it again returns the same acknowledgement.
+		s.logger.ErrorContext(ctx, "Claim sent but storing the payer's acknowledgement failed", "claim_id", claim.ID, "error", err)
+		return fmt.Errorf("claim sent but the acknowledgement was not stored: %w", err)
+	}
+	return nil
+}
+
+// requireDataSharing refuses to send the patient's data to a payer without their data
+// sharing consent.
+func (s *insuranceService) requireDataSharing(ctx context.Context, patientID uuid.UUID) error {
+	if err := s.consents.RequireConsent(ctx, patientID, model.ConsentDataSharing); err != nil {
+		s.logger.WarnContext(ctx, "Sharing with the payer refused", "patient_id", patientID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *insuranceService) GetClaim(ctx context.Context, claimID uuid.UUID) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.GetClaim")
+	defer span.End()
+	claim, err := s.insuranceRepo.GetInsuranceClaimByID(ctx, pgtype.UUID{Bytes: claimID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClaimNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get claim", "claim_id", claimID, "error", err)
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+	history, err := s.insuranceRepo.ListClaimStatusHistory(ctx, claim.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get history for claim", "claim_id", claimID, "error", err)
+		return nil, fmt.Errorf("failed to get claim history: %w", err)
+	}
+
+	mapped := mapper.MapInsuranceClaim(claim)
+	for _, h := range history {
+		mapped.History = append(mapped.History, mapper.MapClaimStatusHistory(h))
+	}
+	return &mapped, nil
+}
+
+func (s *insuranceService) ListInvoiceClaims(ctx context.Context, invoiceID uuid.UUID) ([]model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.ListInvoiceClaims")
+	defer span.End()
+	claims, err := s.insuranceRepo.ListInsuranceClaimsByInvoiceID(ctx, pgtype.UUID{Bytes: invoiceID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list claims for invoice", "invoice_id", invoiceID, "error", err)
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	mapped := make([]model.InsuranceClaim, 0, len(claims))
+	for _, c := range claims {
+		mapped = append(mapped, mapper.MapInsuranceClaim(c))
+	}
+	return mapped, nil
+}
+
+func (s *insuranceService) ListClaimsByStatus(ctx context.Context, status model.ClaimStatus, params model.PaginationParams) ([]model.InsuranceClaim, int64, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.ListClaimsByStatus")
+	defer span.End()
+	claims, err := s.insuranceRepo.ListInsuranceClaimsByStatus(ctx, db.ListInsuranceClaimsByStatusParams{
+		Status: db.ClaimStatus(status),
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list claims", "status", status, "error", err)
+		return nil, 0, fmt.Errorf("failed to list claims: %w", err)
+	}
+	total, err := s.insuranceRepo.CountInsuranceClaimsByStatus(ctx, db.ClaimStatus(status))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count claims", "status", status, "error", err)
+		return nil, 0, fmt.Errorf("failed to count claims: %w", err)
+	}
+	mapped := make([]model.InsuranceClaim, 0, len(claims))
+	for _, c := range claims {
+		mapped = append(mapped, mapper.MapInsuranceClaim(c))
+	}
+	return mapped, total, nil
+}
+
+// UpdateClaimStatus records the payer's response. A paid claim posts an insurance payment to the
+// invoice in the same transaction as the status change, so a claim is paid exactly once.
+func (s *insuranceService) UpdateClaimStatus(ctx context.Context, claimID uuid.UUID, req model.ClaimStatusUpdateRequest, changedByUserID uuid.UUID) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "InsuranceService.UpdateClaimStatus")
+	defer span.End()
+	current, err := s.GetClaim(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.Status.CanTransitionTo(req.Status) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrClaimInvalidTransition, current.Status, req.Status)
+	}
+
+	params := db.UpdateInsuranceClaimStatusParams{
+		ID:             pgtype.UUID{Bytes: claimID, Valid: true},
+		Status:         db.ClaimStatus(req.Status),
+		FromStatus:     db.ClaimStatus(current.Status),
+		PayerReference: pgtype.Text{String: derefString(req.PayerReference), Valid: req.PayerReference != nil},
+	}
+	switch req.Status {
+	case model.ClaimStatusRejected:
+		params.RejectionReason = pgtype.Text{String: derefString(req.RejectionReason), Valid: true}
+	case model.ClaimStatusPaid:
+		if *req.PaidMinor > current.ClaimedMinor {
+			return nil, ErrClaimPaidExceedsClaimed
+		}
+		invoice, err := s.billingService.GetInvoice(ctx, current.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		if *req.PaidMinor > invoice.BalanceMinor {
+			return nil, ErrPaymentExceedsBalance
+		}
+		params.PaidMinor = pgtype.Int8{Int64: *req.PaidMinor, Valid: true}
+	}
+
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if _, err := r.Insurance.UpdateInsuranceClaimStatus(ctx, params); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Someone else moved the claim on since we read it.
+				return fmt.Errorf("%w: claim is no longer %s", ErrClaimInvalidTransition, current.Status)
+			}
+			return fmt.Errorf("failed to update claim: %w", err)
+		}
+		if _, err := r.Insurance.CreateClaimStatusHistory(ctx, db.CreateClaimStatusHistoryParams{
+			ClaimID:         params.ID,
+			Status:          params.Status,
+			Note:            pgtype.Text{String: derefString(req.Note), Valid: req.Note != nil},
+			ChangedByUserID: pgtype.UUID{Bytes: changedByUserID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to record claim history: %w", err)
+		}
+		if req.Status != model.ClaimStatusPaid {
+			return nil
+		}
+		reference := fmt.Sprintf("claim %s", claimID)
+		if current.PayerReference != nil {
+			reference = *current.PayerReference
+		}
+		return postPayment(ctx, r.Billing, current.InvoiceID, model.PaymentCreateRequest{
+			Method:      model.PaymentMethodInsurance,
+			AmountMinor: *req.PaidMinor,
+			Reference:   &reference,
+		}, changedByUserID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrClaimInvalidTransition) || errors.Is(err, ErrInvoiceNotFound) || errors.Is(err, ErrPaymentExceedsBalance) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to update claim", "claim_id", claimID, "status", req.Status, "error", err)
+		return nil, fmt.Errorf("failed to update claim status: %w", err)
+	}
+	return s.GetClaim(ctx, claimID)
+}
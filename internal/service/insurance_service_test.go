@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/consent"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/insurance"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInsuranceRepo struct {
+	repository.InsuranceRepository
+	claims []db.InsuranceClaim
+}
+
+func (f *fakeInsuranceRepo) GetInsuranceClaimByID(_ context.Context, id pgtype.UUID) (db.InsuranceClaim, error) {
+	for _, c := range f.claims {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return db.InsuranceClaim{}, nil
+}
+
+func TestSendClaimNeedsDataSharingConsent(t *testing.T) {
+	patientID := uuid.New()
+	claim := db.InsuranceClaim{
+		ID:        pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+		Status:    db.ClaimStatusSubmitted,
+	}
+	gateway := insurance.NewFakeGateway()
+	claims := NewInsuranceService(&fakeInsuranceRepo{claims: []db.InsuranceClaim{claim}}, nil, nil, fakeConsents{}, nil, gateway, discardLogger)
+
+	_, err := claims.SendClaim(context.Background(), claim.ID.Bytes)
+	assert.ErrorIs(t, err, consent.ErrConsentRequired)
+}
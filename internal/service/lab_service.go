@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrLabTestNotFound = errors.New("lab test not found")
+var ErrLabTestAlreadyExists = errors.New("lab test code already exists")
+var ErrLabOrderNotFound = errors.New("lab order not found")
+var ErrLabAnalyteNotFound = errors.New("analyte not found for this lab test")
+var ErrLabOrderInvalidTransition = errors.New("lab order status does not allow this action")
+var ErrLabOrderHasNoResults = errors.New("lab order has no results to verify")
+var ErrVisitForLabOrderNotFound = errors.New("visit for lab order not found")
+
+type labService struct {
+	labRepo     repository.LabRepository
+	tx          repository.TxManager // Writes a test with its analytes, and an order with its results
+	visitRepo   repository.PatientVisitQuerier
+	patientRepo repository.PatientRepository
+	evaluator   ObservationEvaluator // Raises critical-result alerts for numeric results
+	charges     ChargeCapturer       // Bills each order against the test's charge code
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
+}
+
+func NewLabService(labRepo repository.LabRepository, tx repository.TxManager, visitRepo repository.PatientVisitQuerier, patientRepo repository.PatientRepository, evaluator ObservationEvaluator, charges ChargeCapturer, access PatientAccessAuthorizer, logger *slog.Logger) LabService {
+	return &labService{labRepo: labRepo, tx: tx, visitRepo: visitRepo, patientRepo: patientRepo, evaluator: evaluator, charges: charges, access: access, logger: logger.With("service", "LabService")}
+}
+
+// redactLabOrder removes the clinical notes and results a caller with demographics-only
+// access may not see. Lab technicians produce the results, so they see them in full.
+func redactLabOrder(ctx context.Context, o *model.LabOrder, level PatientAccessLevel) {
+	if !canSeeLabResults(ctx, level) {
+		o.ClinicalNotes, o.Results = nil, nil
+	}
+}
+
+func canSeeLabResults(ctx context.Context, level PatientAccessLevel) bool {
+	return level >= PatientAccessClinical || audit.FromContext(ctx).ActorRole == model.RoleLabTechnician
+}
+
+func float8FromPtr(f *float64) pgtype.Float8 {
+	if f == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *f, Valid: true}
+}
+
+func labFlagFromPtr(f *model.LabResultFlag) db.NullLabResultFlag {
+	if f == nil {
+		return db.NullLabResultFlag{}
+	}
+	return db.NullLabResultFlag{LabResultFlag: db.LabResultFlag(*f), Valid: true}
+}
+
+func (s *labService) CreateLabTest(ctx context.Context, req model.LabTestCreateRequest) (*model.LabTest, error) {
+	ctx, span := tracing.Start(ctx, "LabService.CreateLabTest")
+	defer span.End()
+	var test db.LabTest
+	var analytes []db.LabAnalyte
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		test, err = r.Labs.CreateLabTest(ctx, db.CreateLabTestParams{
+			Code:         req.Code,
+			Name:         req.Name,
+			SpecimenType: db.LabSpecimenType(req.SpecimenType),
+			Description:  pgtype.Text{String: derefString(req.Description), Valid: req.Description != nil},
+			ChargeCode:   pgtype.Text{String: derefString(req.ChargeCode), Valid: req.ChargeCode != nil},
+		})
+		if err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "foreign key constraint") {
+				return fmt.Errorf("%w: code %s", ErrChargeItemNotFound, derefString(req.ChargeCode))
+			}
+			if strings.Contains(strings.ToLower(err.Error()), "unique constraint") ||
+				strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
+				return ErrLabTestAlreadyExists
+			}
+			return fmt.Errorf("failed to create lab test: %w", err)
+		}
+
+		analytes = make([]db.LabAnalyte, 0, len(req.Analytes))
+		for i, a := range req.Analytes {
+			displayOrder := a.DisplayOrder
+			if displayOrder == 0 {
+				displayOrder = i
+			}
+			analyte, err := r.Labs.CreateLabAnalyte(ctx, db.CreateLabAnalyteParams{
+				TestID:        test.ID,
+				Code:          a.Code,
+				Name:          a.Name,
+				Unit:          pgtype.Text{String: derefString(a.Unit), Valid: a.Unit != nil},
+				ReferenceLow:  float8FromPtr(a.ReferenceLow),
+				ReferenceHigh: float8FromPtr(a.ReferenceHigh),
+				CriticalLow:   float8FromPtr(a.CriticalLow),
+				CriticalHigh:  float8FromPtr(a.CriticalHigh),
+				DisplayOrder:  int32(displayOrder),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create analyte %s: %w", a.Code, err)
+			}
+			analytes = append(analytes, analyte)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrChargeItemNotFound) || errors.Is(err, ErrLabTestAlreadyExists) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to create lab test", "code", req.Code, "error", err)
+		return nil, err
+	}
+
+	mapped := mapper.MapLabTest(test, analytes)
+	return &mapped, nil
+}
+
+func (s *labService) ListLabTests(ctx context.Context, params model.PaginationParams) ([]model.LabTest, int64, error) {
+	ctx, span := tracing.Start(ctx, "LabService.ListLabTests")
+	defer span.End()
+	tests, err := s.labRepo.ListLabTests(ctx, db.ListLabTestsParams{
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list lab tests", "error", err)
+		return nil, 0, fmt.Errorf("failed to list lab tests: %w", err)
+	}
+
+	mapped := make([]model.LabTest, 0, len(tests))
+	for _, t := range tests {
+		analytes, err := s.labRepo.ListLabAnalytesByTestID(ctx, t.ID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to list analytes", "test_code", t.Code, "error", err)
+			return nil, 0, fmt.Errorf("failed to list lab analytes: %w", err)
+		}
+		mapped = append(mapped, mapper.MapLabTest(t, analytes))
+	}
+
+	total, err := s.labRepo.CountLabTests(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count lab tests", "error", err)
+		return nil, 0, fmt.Errorf("failed to count lab tests: %w", err)
+	}
+	return mapped, total, nil
+}
+
+func (s *labService) OrderLabTest(ctx context.Context, req model.LabOrderCreateRequest, orderedByUserID uuid.UUID) (*model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.OrderLabTest")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: req.VisitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: visit ID %s", ErrVisitForLabOrderNotFound, req.VisitID)
+		}
+		s.logger.ErrorContext(ctx, "Error checking visit for new lab order", "visit_id", req.VisitID, "error", err)
+		return nil, fmt.Errorf("error verifying visit for lab order: %w", err)
+	}
+	ctx, _, err = s.access.AuthorizePatientAccess(ctx, uuid.UUID(visit.PatientID.Bytes))
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, fmt.Errorf("%w: visit ID %s", ErrVisitForLabOrderNotFound, req.VisitID)
+		}
+		return nil, err
+	}
+
+	test, err := s.labRepo.GetLabTestByCode(ctx, req.TestCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: code %s", ErrLabTestNotFound, req.TestCode)
+		}
+		s.logger.ErrorContext(ctx, "Error fetching lab test", "test_code", req.TestCode, "error", err)
+		return nil, fmt.Errorf("error fetching lab test: %w", err)
+	}
+	if !test.IsActive {
+		return nil, fmt.Errorf("%w: code %s is inactive", ErrLabTestNotFound, req.TestCode)
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = model.LabPriorityRoutine
+	}
+
+	// The order is only created if its charge is captured with it.
+	var order db.LabOrder
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		order, err = r.Labs.CreateLabOrder(ctx, db.CreateLabOrderParams{
+			VisitID:         pgtype.UUID{Bytes: req.VisitID, Valid: true},
+			TestID:          test.ID,
+			OrderedByUserID: pgtype.UUID{Bytes: orderedByUserID, Valid: true},
+			Priority:        db.LabOrderPriority(priority),
+			ClinicalNotes:   pgtype.Text{String: derefString(req.ClinicalNotes), Valid: req.ClinicalNotes != nil},
+		})
+		if err != nil || !test.ChargeCode.Valid {
+			return err
+		}
+		orderID := uuid.UUID(order.ID.Bytes)
+		return captureCharge(ctx, s.logger, s.charges, r, model.ChargeCapture{
+			PatientID:       visit.PatientID.Bytes,
+			VisitID:         &req.VisitID,
+			Source:          model.ChargeSourceLabOrder,
+			SourceID:        &orderID,
+			ChargeCode:      test.ChargeCode.String,
+			CreatedByUserID: orderedByUserID,
+		})
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create lab order", "visit_id", req.VisitID, "error", err)
+		return nil, fmt.Errorf("failed to create lab order: %w", err)
+	}
+
+	mapped := mapper.MapLabOrder(order)
+	return &mapped, nil
+}
+
+// getOrder fetches an order and attaches its results.
+func (s *labService) getOrder(ctx context.Context, orderID uuid.UUID) (*model.LabOrder, error) {
+	order, err := s.labRepo.GetLabOrderByID(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLabOrderNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get lab order", "order_id", orderID, "error", err)
+		return nil, fmt.Errorf("failed to get lab order: %w", err)
+	}
+	return s.withResults(ctx, order)
+}
+
+func (s *labService) withResults(ctx context.Context, order db.LabOrder) (*model.LabOrder, error) {
+	results, err := s.labRepo.ListLabResultsByOrderID(ctx, order.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list results for lab order", "order_id", order.ID, "error", err)
+		return nil, fmt.Errorf("failed to list lab results: %w", err)
+	}
+	mapped := mapper.MapLabOrder(order)
+	for _, r := range results {
+		mapped.Results = append(mapped.Results, mapper.MapLabResult(r))
+	}
+	return &mapped, nil
+}
+
+func (s *labService) GetLabOrder(ctx context.Context, orderID uuid.UUID) (*model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.GetLabOrder")
+	defer span.End()
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: order.VisitID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error fetching visit for lab order", "order_id", orderID, "visit_id", order.VisitID, "error", err)
+		return nil, fmt.Errorf("error fetching visit for lab order: %w", err)
+	}
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, uuid.UUID(visit.PatientID.Bytes))
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrLabOrderNotFound
+		}
+		return nil, err
+	}
+	redactLabOrder(ctx, order, level)
+	return order, nil
+}
+
+func (s *labService) ListVisitLabOrders(ctx context.Context, visitID uuid.UUID) ([]model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.ListVisitLabOrders")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking visit before listing lab orders", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("error verifying visit before listing lab orders: %w", err)
+	}
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, uuid.UUID(visit.PatientID.Bytes))
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+
+	orders, err := s.labRepo.ListLabOrdersByVisitID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list lab orders", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to list lab orders: %w", err)
+	}
+
+	mapped := make([]model.LabOrder, 0, len(orders))
+	for _, o := range orders {
+		order, err := s.withResults(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		redactLabOrder(ctx, order, level)
+		mapped = append(mapped, *order)
+	}
+	return mapped, nil
+}
+
+func (s *labService) ListLabWorklist(ctx context.Context, status model.LabOrderStatus, params model.PaginationParams) ([]model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.ListLabWorklist")
+	defer span.End()
+	orders, err := s.labRepo.ListLabOrdersByStatus(ctx, db.ListLabOrdersByStatusParams{
+		Status: db.LabOrderStatus(status),
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list lab orders with status", "status", status, "error", err)
+		return nil, fmt.Errorf("failed to list lab orders: %w", err)
+	}
+	mapped := make([]model.LabOrder, 0, len(orders))
+	for _, o := range orders {
+		mapped = append(mapped, mapper.MapLabOrder(o))
+	}
+	return mapped, nil
+}
+
+// requireTransition loads the order and checks the lifecycle allows moving to next.
+func (s *labService) requireTransition(ctx context.Context, orderID uuid.UUID, next model.LabOrderStatus) (db.LabOrder, error) {
+	order, err := s.labRepo.GetLabOrderByID(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.LabOrder{}, ErrLabOrderNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to fetch lab order", "order_id", orderID, "error", err)
+		return db.LabOrder{}, fmt.Errorf("failed to fetch lab order: %w", err)
+	}
+	current := model.LabOrderStatus(order.Status)
+	if !current.CanTransitionTo(next) {
+		return db.LabOrder{}, fmt.Errorf("%w: %s -> %s", ErrLabOrderInvalidTransition, current, next)
+	}
+	return order, nil
+}
+
+func (s *labService) MarkSpecimenCollected(ctx context.Context, orderID uuid.UUID, collectedByUserID uuid.UUID) (*model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.MarkSpecimenCollected")
+	defer span.End()
+	if _, err := s.requireTransition(ctx, orderID, model.LabStatusCollected); err != nil {
+		return nil, err
+	}
+	order, err := s.labRepo.MarkLabOrderCollected(ctx, db.MarkLabOrderCollectedParams{
+		ID:                pgtype.UUID{Bytes: orderID, Valid: true},
+		CollectedByUserID: pgtype.UUID{Bytes: collectedByUserID, Valid: true},
+	})
+	if err != nil {
+		// The conditional UPDATE matches no rows if another request moved the order first.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLabOrderInvalidTransition
+		}
+		s.logger.ErrorContext(ctx, "Failed to mark lab order collected", "order_id", orderID, "error", err)
+		return nil, fmt.Errorf("failed to mark lab order collected: %w", err)
+	}
+	return s.withResults(ctx, order)
+}
+
+func (s *labService) EnterLabResults(ctx context.Context, orderID uuid.UUID, req model.LabResultEntryRequest, enteredByUserID uuid.UUID) (*model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.EnterLabResults")
+	defer span.End()
+	order, err := s.requireTransition(ctx, orderID, model.LabStatusResulted)
+	if err != nil {
+		return nil, err
+	}
+
+	analytes, err := s.labRepo.ListLabAnalytesByTestID(ctx, order.TestID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list analytes for lab order", "order_id", orderID, "error", err)
+		return nil, fmt.Errorf("failed to list lab analytes: %w", err)
+	}
+	byCode := make(map[string]db.LabAnalyte, len(analytes))
+	for _, a := range analytes {
+		byCode[a.Code] = a
+	}
+
+	for _, value := range req.Results {
+		if _, ok := byCode[value.AnalyteCode]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrLabAnalyteNotFound, value.AnalyteCode)
+		}
+	}
+
+	// The results and the order's status are saved together, so a failure leaves the order
+	// as it was rather than with some of its results.
+	// Only numeric values that differ from what was stored are evaluated, so correcting one
+	// result does not raise the alerts for the others again.
+	var updated db.LabOrder
+	var changed []model.LabResultValue
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		previous, err := r.Labs.ListLabResultsByOrderID(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list lab results: %w", err)
+		}
+		stored := make(map[string]pgtype.Float8, len(previous))
+		for _, p := range previous {
+			stored[p.AnalyteCode] = p.ValueNumeric
+		}
+
+		changed = changed[:0]
+		for _, value := range req.Results {
+			analyte := byCode[value.AnalyteCode]
+			modelAnalyte := mapper.MapLabAnalyte(analyte)
+
+			// Text results are not compared with the ranges; they keep the technician's flag.
+			flag := value.Flag
+			if value.ValueNumeric != nil {
+				computed := modelAnalyte.Flag(*value.ValueNumeric)
+				flag = &computed
+			}
+
+			numeric := float8FromPtr(value.ValueNumeric)
+			_, err := r.Labs.UpsertLabResult(ctx, db.UpsertLabResultParams{
+				OrderID:         order.ID,
+				AnalyteID:       analyte.ID,
+				ValueNumeric:    numeric,
+				ValueText:       pgtype.Text{String: derefString(value.ValueText), Valid: value.ValueText != nil},
+				Unit:            analyte.Unit,
+				ReferenceLow:    analyte.ReferenceLow,
+				ReferenceHigh:   analyte.ReferenceHigh,
+				Flag:            labFlagFromPtr(flag),
+				Comment:         pgtype.Text{String: derefString(value.Comment), Valid: value.Comment != nil},
+				EnteredByUserID: pgtype.UUID{Bytes: enteredByUserID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save lab result for %s: %w", value.AnalyteCode, err)
+			}
+			if before, ok := stored[value.AnalyteCode]; numeric.Valid && (!ok || before != numeric) {
+				changed = append(changed, value)
+			}
+			stored[value.AnalyteCode] = numeric
+		}
+
+		updated, err = r.Labs.MarkLabOrderResulted(ctx, db.MarkLabOrderResultedParams{
+			ID:               order.ID,
+			ResultedByUserID: pgtype.UUID{Bytes: enteredByUserID, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrLabOrderInvalidTransition
+			}
+			return fmt.Errorf("failed to mark lab order resulted: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrLabOrderInvalidTransition) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to save results for lab order", "order_id", orderID, "error", err)
+		return nil, err
+	}
+
+	for _, value := range changed {
+		if flag := mapper.MapLabAnalyte(byCode[value.AnalyteCode]).Flag(*value.ValueNumeric); flag.IsCritical() {
+			s.logger.WarnContext(ctx, "Critical result recorded", "analyte_code", value.AnalyteCode, "flag", flag, "order_id", orderID)
+		}
+		// Alert rules are keyed by analyte code; a failed evaluation must not lose the result.
+		if _, err := s.evaluator.EvaluateObservation(ctx, order.VisitID.Bytes, model.ObservationSourceLabResult, value.AnalyteCode, *value.ValueNumeric); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to evaluate alert rules", "analyte_code", value.AnalyteCode, "order_id", orderID, "error", err)
+		}
+	}
+	return s.withResults(ctx, updated)
+}
+
+func (s *labService) VerifyLabResults(ctx context.Context, orderID uuid.UUID, verifiedByUserID uuid.UUID) (*model.LabOrder, error) {
+	ctx, span := tracing.Start(ctx, "LabService.VerifyLabResults")
+	defer span.End()
+	order, err := s.requireTransition(ctx, orderID, model.LabStatusVerified)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.labRepo.ListLabResultsByOrderID(ctx, order.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list results for lab order", "order_id", orderID, "error", err)
+		return nil, fmt.Errorf("failed to list lab results: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, ErrLabOrderHasNoResults
+	}
+
+	updated, err := s.labRepo.MarkLabOrderVerified(ctx, db.MarkLabOrderVerifiedParams{
+		ID:               order.ID,
+		VerifiedByUserID: pgtype.UUID{Bytes: verifiedByUserID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLabOrderInvalidTransition
+		}
+		s.logger.ErrorContext(ctx, "Failed to verify lab order", "order_id", orderID, "error", err)
+		return nil, fmt.Errorf("failed to verify lab order: %w", err)
+	}
+	return s.withResults(ctx, updated)
+}
+
+func (s *labService) GetCumulativeLabResults(ctx context.Context, patientID uuid.UUID, analyteCode *string, params model.PaginationParams) ([]model.CumulativeLabResult, error) {
+	ctx, span := tracing.Start(ctx, "LabService.GetCumulativeLabResults")
+	defer span.End()
+	_, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking patient before listing lab results", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("error verifying patient before listing lab results: %w", err)
+	}
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if !canSeeLabResults(ctx, level) {
+		return []model.CumulativeLabResult{}, nil
+	}
+
+	rows, err := s.labRepo.ListCumulativeLabResultsByPatientID(ctx, db.ListCumulativeLabResultsByPatientIDParams{
+		PatientID:   pgtype.UUID{Bytes: patientID, Valid: true},
+		AnalyteCode: pgtype.Text{String: derefString(analyteCode), Valid: analyteCode != nil},
+		RowLimit:    int32(params.Limit),
+		RowOffset:   int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list cumulative lab results", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to list cumulative lab results: %w", err)
+	}
+
+	mapped := make([]model.CumulativeLabResult, 0, len(rows))
+	for _, r := range rows {
+		mapped = append(mapped, mapper.MapCumulativeLabResult(r))
+	}
+	return mapped, nil
+}
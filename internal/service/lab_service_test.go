@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLabRepo holds one collected order for a test with the analytes given, and the
+// results saved for it.
+type fakeLabRepo struct {
+	repository.LabRepository
+	order    db.LabOrder
+	analytes []db.LabAnalyte
+	results  map[pgtype.UUID]db.UpsertLabResultParams // by analyte
+}
+
+func newFakeLabRepo(analyteCodes ...string) *fakeLabRepo {
+	f := &fakeLabRepo{
+		order:   db.LabOrder{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, VisitID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Status: db.LabOrderStatusCollected},
+		results: make(map[pgtype.UUID]db.UpsertLabResultParams),
+	}
+	for _, code := range analyteCodes {
+		f.analytes = append(f.analytes, db.LabAnalyte{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: code})
+	}
+	return f
+}
+
+func (f *fakeLabRepo) GetLabOrderByID(context.Context, pgtype.UUID) (db.LabOrder, error) {
+	return f.order, nil
+}
+
+func (f *fakeLabRepo) ListLabAnalytesByTestID(context.Context, pgtype.UUID) ([]db.LabAnalyte, error) {
+	return f.analytes, nil
+}
+
+func (f *fakeLabRepo) UpsertLabResult(_ context.Context, arg db.UpsertLabResultParams) (db.LabResult, error) {
+	f.results[arg.AnalyteID] = arg
+	return db.LabResult{}, nil
+}
+
+func (f *fakeLabRepo) ListLabResultsByOrderID(context.Context, pgtype.UUID) ([]db.ListLabResultsByOrderIDRow, error) {
+	var rows []db.ListLabResultsByOrderIDRow
+	for _, a := range f.analytes {
+		if r, ok := f.results[a.ID]; ok {
+			rows = append(rows, db.ListLabResultsByOrderIDRow{AnalyteID: a.ID, AnalyteCode: a.Code, ValueNumeric: r.ValueNumeric, ValueText: r.ValueText, Flag: r.Flag})
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeLabRepo) MarkLabOrderResulted(context.Context, db.MarkLabOrderResultedParams) (db.LabOrder, error) {
+	f.order.Status = db.LabOrderStatusResulted
+	return f.order, nil
+}
+
+func (f *fakeLabRepo) GetLabTestByCode(_ context.Context, code string) (db.LabTest, error) {
+	return db.LabTest{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: code, ChargeCode: pgtype.Text{String: "LAB-" + code, Valid: true}, IsActive: true}, nil
+}
+
+func (f *fakeLabRepo) CreateLabOrder(_ context.Context, arg db.CreateLabOrderParams) (db.LabOrder, error) {
+	f.order = db.LabOrder{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, VisitID: arg.VisitID, TestID: arg.TestID, Status: db.LabOrderStatusOrdered}
+	return f.order, nil
+}
+
+// fakeChargeCapturer fails every capture with err.
+type fakeChargeCapturer struct {
+	ChargeCapturer
+	err error
+}
+
+func (f fakeChargeCapturer) CaptureChargeTx(context.Context, repository.Repos, model.ChargeCapture) (*model.Charge, error) {
+	return nil, f.err
+}
+
+// fakeTx runs the unit of work once against the fake repositories.
+type fakeTx struct {
+	labs     repository.LabRepository
+	careTeam repository.CareTeamRepository
+}
+
+func (f fakeTx) WithinTx(_ context.Context, fn func(repository.Repos) error) error {
+	return fn(repository.Repos{Labs: f.labs, CareTeam: f.careTeam})
+}
+
+// fakeVisitRepo holds visits by ID.
+type fakeVisitRepo struct {
+	repository.PatientVisitQuerier
+	visits map[pgtype.UUID]db.PatientVisit
+}
+
+func (f fakeVisitRepo) GetPatientVisitByID(_ context.Context, id pgtype.UUID) (db.PatientVisit, error) {
+	return f.visits[id], nil
+}
+
+type fakeEvaluator struct{ evaluated map[string][]float64 }
+
+func (f *fakeEvaluator) EvaluateObservation(_ context.Context, _ uuid.UUID, _ model.ObservationSource, code string, value float64) (*model.Alert, error) {
+	f.evaluated[code] = append(f.evaluated[code], value)
+	return nil, nil
+}
+
+func numericResult(code string, value float64) model.LabResultValue {
+	return model.LabResultValue{AnalyteCode: code, ValueNumeric: &value}
+}
+
+func TestEnterLabResultsEvaluatesOnlyChangedValues(t *testing.T) {
+	labs := newFakeLabRepo("K", "NA", "CULTURE")
+	evaluator := &fakeEvaluator{evaluated: make(map[string][]float64)}
+	service := NewLabService(labs, fakeTx{labs: labs}, nil, nil, evaluator, nil, nil, discardLogger)
+	ctx := context.Background()
+	technician := uuid.New()
+
+	_, err := service.EnterLabResults(ctx, labs.order.ID.Bytes, model.LabResultEntryRequest{Results: []model.LabResultValue{
+		numericResult("K", 4.2),
+		numericResult("NA", 140),
+		{AnalyteCode: "CULTURE", ValueText: ptr("no growth")},
+	}}, technician)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]float64{"K": {4.2}, "NA": {140}}, evaluator.evaluated)
+
+	// Correcting the potassium sends the same sodium again; only the potassium is evaluated.
+	_, err = service.EnterLabResults(ctx, labs.order.ID.Bytes, model.LabResultEntryRequest{Results: []model.LabResultValue{
+		numericResult("K", 6.9),
+		numericResult("NA", 140),
+	}}, technician)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]float64{"K": {4.2, 6.9}, "NA": {140}}, evaluator.evaluated)
+}
+
+func TestEnterLabResultsFlags(t *testing.T) {
+	labs := newFakeLabRepo("K", "CULTURE", "SMEAR")
+	labs.analytes[0].ReferenceHigh = pgtype.Float8{Float64: 5.1, Valid: true}
+	service := NewLabService(labs, fakeTx{labs: labs}, nil, nil, &fakeEvaluator{evaluated: make(map[string][]float64)}, nil, nil, discardLogger)
+
+	order, err := service.EnterLabResults(context.Background(), labs.order.ID.Bytes, model.LabResultEntryRequest{Results: []model.LabResultValue{
+		numericResult("K", 5.8),
+		{AnalyteCode: "CULTURE", ValueText: ptr("growth"), Flag: ptr(model.LabFlagAbnormal)},
+		{AnalyteCode: "SMEAR", ValueText: ptr("see comment")},
+	}}, uuid.New())
+	require.NoError(t, err)
+
+	flags := make(map[string]*model.LabResultFlag)
+	for _, r := range order.Results {
+		flags[r.AnalyteCode] = r.Flag
+	}
+	assert.Equal(t, map[string]*model.LabResultFlag{
+		"K":       ptr(model.LabFlagHigh),
+		"CULTURE": ptr(model.LabFlagAbnormal),
+		"SMEAR":   nil,
+	}, flags)
+}
+
+func TestGetLabOrderScopesToCareTeam(t *testing.T) {
+	f := newCareTeamFixture()
+	labs := newFakeLabRepo("K")
+	labs.results[labs.analytes[0].ID] = db.UpsertLabResultParams{ValueNumeric: pgtype.Float8{Float64: 4.2, Valid: true}}
+	labs.order.ClinicalNotes = pgtype.Text{String: "query hypokalaemia", Valid: true}
+	visits := fakeVisitRepo{visits: map[pgtype.UUID]db.PatientVisit{}}
+	service := NewLabService(labs, fakeTx{labs: labs}, visits, f.patients, nil, nil, f.access, discardLogger)
+	doctor := audit.WithActor(context.Background(), f.doctor, model.RoleDoctor)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		patientID   uuid.UUID
+		wantErr     error
+		wantResults bool
+	}{
+		{name: "doctor on the care team", ctx: doctor, patientID: f.inCare, wantResults: true},
+		{name: "doctor outside the care team", ctx: doctor, patientID: f.outOfCare, wantErr: ErrLabOrderNotFound},
+		{name: "receptionist", ctx: audit.WithActor(context.Background(), uuid.New(), model.RoleReceptionist), patientID: f.inCare},
+		{name: "lab technician", ctx: audit.WithActor(context.Background(), uuid.New(), model.RoleLabTechnician), patientID: f.outOfCare, wantResults: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visits.visits[labs.order.VisitID] = db.PatientVisit{ID: labs.order.VisitID, PatientID: pgtype.UUID{Bytes: tt.patientID, Valid: true}}
+			order, err := service.GetLabOrder(tt.ctx, labs.order.ID.Bytes)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantResults {
+				assert.Len(t, order.Results, 1)
+				assert.NotNil(t, order.ClinicalNotes)
+			} else {
+				assert.Empty(t, order.Results)
+				assert.Nil(t, order.ClinicalNotes)
+			}
+		})
+	}
+}
+
+func TestOrderLabTestFailsWhenTheChargeIsNotCaptured(t *testing.T) {
+	f := newCareTeamFixture()
+	visitID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	visits := fakeVisitRepo{visits: map[pgtype.UUID]db.PatientVisit{
+		visitID: {ID: visitID, PatientID: pgtype.UUID{Bytes: f.inCare, Valid: true}},
+	}}
+	doctor := audit.WithActor(context.Background(), f.doctor, model.RoleDoctor)
+	req := model.LabOrderCreateRequest{VisitID: visitID.Bytes, TestCode: "CBC"}
+
+	tests := []struct {
+		name      string
+		chargeErr error
+		wantErr   bool
+	}{
+		{name: "charge code not in the charge master", chargeErr: ErrChargeItemNotFound},
+		{name: "charge already captured", chargeErr: ErrChargeAlreadyCaptured},
+		{name: "billing unavailable", chargeErr: errors.New("connection reset"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labs := newFakeLabRepo()
+			service := NewLabService(labs, fakeTx{labs: labs}, visits, f.patients, nil, fakeChargeCapturer{err: tt.chargeErr}, f.access, discardLogger)
+			order, err := service.OrderLabTest(doctor, req, f.doctor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uuid.UUID(visitID.Bytes), order.VisitID)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/notify"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPatientNoContactPhone = errors.New("patient has no contact phone")
+
+const notificationChannelSMS = "sms"
+
+type notificationService struct {
+	patientRepo repository.PatientRepository
+	consents    ConsentChecker
+	sms         notify.SMSSender
+	auditor     AuditRecorder
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
+}
+
+func NewNotificationService(patientRepo repository.PatientRepository, consents ConsentChecker, sms notify.SMSSender, auditor AuditRecorder, access PatientAccessAuthorizer, logger *slog.Logger) NotificationService {
+	return &notificationService{patientRepo: patientRepo, consents: consents, sms: sms, auditor: auditor, access: access, logger: logger.With("service", "NotificationService")}
+}
+
+// SendSMS texts the patient's contact phone. Nothing is sent unless the patient's SMS
+// contact consent is in force.
+func (s *notificationService) SendSMS(ctx context.Context, patientID uuid.UUID, req model.SMSNotificationRequest) (*model.NotificationReceipt, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.SendSMS")
+	defer span.End()
+	ctx, _, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	patient, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+	if err := s.consents.RequireConsent(ctx, patientID, model.ConsentSMSContact); err != nil {
+		s.logger.WarnContext(ctx, "SMS to patient refused", "patient_id", patientID, "error", err)
+		return nil, err
+	}
+	if !patient.ContactPhone.Valid || patient.ContactPhone.String == "" {
+		return nil, ErrPatientNoContactPhone
+	}
+
+	ref, err := s.sms.SendSMS(ctx, notify.SMS{To: patient.ContactPhone.String, Body: req.Body})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to send SMS", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to send SMS: %w", err)
+	}
+	receipt := model.NotificationReceipt{PatientID: patientID, Channel: notificationChannelSMS, Reference: ref, SentAt: time.Now()}
+	recordAudit(ctx, s.logger, s.auditor, model.AuditEntry{
+		Action:       model.AuditActionNotify,
+		ResourceType: model.AuditResourceNotification,
+		PatientID:    &patientID,
+		Changes: map[string]model.FieldChange{
+			"channel":   {New: receipt.Channel},
+			"reference": {New: receipt.Reference},
+		},
+	})
+	return &receipt, nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/sar"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPatientExportNotFound = errors.New("patient export not found")
+var ErrPatientExportNotReady = errors.New("patient export is not ready yet")
+var ErrPatientExportExpired = errors.New("patient export has expired, request a new one")
+
+// exportPageSize is how many rows are read at a time while assembling an export.
+const exportPageSize = 500
+
+type patientExportService struct {
+	exportRepo    repository.PatientExportRepository
+	tx            repository.TxManager // Queues a job together with its audit event
+	patientRepo   repository.PatientRepository
+	visitRepo     repository.PatientVisitQuerier
+	labRepo       repository.LabRepository
+	insuranceRepo repository.InsuranceRepository
+	consentRepo   repository.ConsentRepository
+	consents      ConsentChecker // The archive leaves the hospital, so it needs data sharing consent
+	auditService  AuditService
+	logger        *slog.Logger
+}
+
+func NewPatientExportService(exportRepo repository.PatientExportRepository, tx repository.TxManager, patientRepo repository.PatientRepository, visitRepo repository.PatientVisitQuerier, labRepo repository.LabRepository, insuranceRepo repository.InsuranceRepository, consentRepo repository.ConsentRepository, consents ConsentChecker, auditService AuditService, logger *slog.Logger) PatientExportService {
+	return &patientExportService{
+		exportRepo:    exportRepo,
+		tx:            tx,
+		patientRepo:   patientRepo,
+		visitRepo:     visitRepo,
+		labRepo:       labRepo,
+		insuranceRepo: insuranceRepo,
+		consentRepo:   consentRepo,
+		consents:      consents,
+		auditService:  auditService,
+		logger:        logger.With("service", "PatientExportService"),
+	}
+}
+
+// RequestExport queues an export of everything held about the patient. While one is
+// pending or running for the patient, that job is returned instead of a new one.
+func (s *patientExportService) RequestExport(ctx context.Context, patientID uuid.UUID, requestedByUserID uuid.UUID) (*model.PatientExport, error) {
+	ctx, span := tracing.Start(ctx, "PatientExportService.RequestExport")
+	defer span.End()
+	pid := pgtype.UUID{Bytes: patientID, Valid: true}
+	if _, err := s.patientRepo.GetPatientByID(ctx, pid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+	if err := s.consents.RequireConsent(ctx, patientID, model.ConsentDataSharing); err != nil {
+		s.logger.WarnContext(ctx, "Export refused", "patient_id", patientID, "error", err)
+		return nil, err
+	}
+
+	open, err := s.exportRepo.GetOpenPatientExportJob(ctx, pid)
+	if err == nil {
+		export := mapper.MapPatientExport(open)
+		return &export, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.ErrorContext(ctx, "Failed to check open exports", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to request export: %w", err)
+	}
+
+	var job db.PatientExportJob
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		if job, err = r.PatientExports.CreatePatientExportJob(ctx, db.CreatePatientExportJobParams{
+			PatientID:         pid,
+			RequestedByUserID: pgtype.UUID{Bytes: requestedByUserID, Valid: true},
+		}); err != nil {
+			return err
+		}
+		jobID := uuid.UUID(job.ID.Bytes)
+		return s.auditService.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourcePatientExport,
+			ResourceID:   &jobID,
+			PatientID:    &patientID,
+		})
+	})
+	if isUniqueViolation(err) {
+		// Another request queued one first.
+		job, err = s.exportRepo.GetOpenPatientExportJob(ctx, pid)
+	}
+	if err != nil {
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to queue export", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to request export: %w", err)
+	}
+
+	export := mapper.MapPatientExport(job)
+	s.logger.InfoContext(ctx, "Export requested", "export_id", export.ID, "patient_id", patientID, "requested_by_user_id", requestedByUserID)
+	return &export, nil
+}
+
+func (s *patientExportService) ListExports(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.PatientExport, int64, error) {
+	ctx, span := tracing.Start(ctx, "PatientExportService.ListExports")
+	defer span.End()
+	pid := pgtype.UUID{Bytes: patientID, Valid: true}
+	jobs, err := s.exportRepo.ListPatientExportJobs(ctx, db.ListPatientExportJobsParams{
+		PatientID: pid,
+		Limit:     int32(params.Limit),
+		Offset:    int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list exports", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list exports: %w", err)
+	}
+	total, err := s.exportRepo.CountPatientExportJobs(ctx, pid)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count exports", "patient_id", patientID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count exports: %w", err)
+	}
+	exports := make([]model.PatientExport, 0, len(jobs))
+	for _, j := range jobs {
+		exports = append(exports, mapper.MapPatientExport(j))
+	}
+	return exports, total, nil
+}
+
+func (s *patientExportService) GetExport(ctx context.Context, patientID, exportID uuid.UUID) (*model.PatientExport, error) {
+	ctx, span := tracing.Start(ctx, "PatientExportService.GetExport")
+	defer span.End()
+	job, err := s.exportRepo.GetPatientExportJob(ctx, db.GetPatientExportJobParams{
+		ID:        pgtype.UUID{Bytes: exportID, Valid: true},
+		PatientID: pgtype.UUID{Bytes: patientID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientExportNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get export", "export_id", exportID, "error", err)
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+	export := mapper.MapPatientExport(job)
+	return &export, nil
+}
+
+// DownloadExport returns the archive of a completed export that has not expired. The
+// download is audited as a read of the patient's record.
+func (s *patientExportService) DownloadExport(ctx context.Context, patientID, exportID uuid.UUID) (*model.PatientExport, []byte, error) {
+	ctx, span := tracing.Start(ctx, "PatientExportService.DownloadExport")
+	defer span.End()
+	export, err := s.GetExport(ctx, patientID, exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case export.Status == model.PatientExportExpired,
+		export.Status == model.PatientExportCompleted && !export.Downloadable(time.Now()):
+		return nil, nil, ErrPatientExportExpired
+	case export.Status != model.PatientExportCompleted:
+		return nil, nil, ErrPatientExportNotReady
+	}
+	// The consent may have been withdrawn since the export was requested.
+	if err := s.consents.RequireConsent(ctx, patientID, model.ConsentDataSharing); err != nil {
+		s.logger.WarnContext(ctx, "Export download refused", "export_id", exportID, "patient_id", patientID, "error", err)
+		return nil, nil, err
+	}
+
+	archive, err := s.exportRepo.GetPatientExportArchive(ctx, pgtype.UUID{Bytes: exportID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrPatientExportExpired
+		}
+		s.logger.ErrorContext(ctx, "Failed to read archive of export", "export_id", exportID, "error", err)
+		return nil, nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	// The archive is the whole record, so it is not handed out unaudited.
+	if err := s.auditService.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionRead,
+		ResourceType: model.AuditResourcePatientExport,
+		ResourceID:   &export.ID,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, nil, err
+	}
+	return export, archive, nil
+}
+
+// ProcessPendingExports builds the archives of queued exports until none are left. Jobs
+// whose worker stopped without finishing are picked up again after sar.StaleAfter.
+func (s *patientExportService) ProcessPendingExports(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.Start(ctx, "PatientExportService.ProcessPendingExports")
+	defer span.End()
+	for {
+		job, err := s.exportRepo.ClaimPatientExportJob(ctx, pgtype.Timestamptz{Time: now.Add(-sar.StaleAfter), Valid: true})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to claim export: %w", err)
+		}
+		if err := s.process(ctx, job); err != nil {
+			return err
+		}
+	}
+}
+
+// process builds one claimed export. Failures building the archive fail the job; only
+// failures recording the outcome are returned.
+func (s *patientExportService) process(ctx context.Context, job db.PatientExportJob) error {
+	exportID := uuid.UUID(job.ID.Bytes)
+	if job.Attempts > sar.MaxAttempts {
+		s.logger.ErrorContext(ctx, "Giving up on export", "export_id", exportID, "attempts", sar.MaxAttempts)
+		return s.fail(ctx, job, fmt.Sprintf("gave up after %d attempts", sar.MaxAttempts))
+	}
+
+	bundle, err := s.assemble(ctx, exportID, uuid.UUID(job.PatientID.Bytes))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to assemble export", "export_id", exportID, "error", err)
+		return s.fail(ctx, job, "could not read the patient's records")
+	}
+	var archive bytes.Buffer
+	if err := sar.WriteZip(&archive, *bundle); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to write export", "export_id", exportID, "error", err)
+		return s.fail(ctx, job, "could not write the archive")
+	}
+
+	expiresAt := bundle.GeneratedAt.Add(sar.DownloadTTL)
+	if _, err := s.exportRepo.CompletePatientExportJob(ctx, job.ID, archive.Bytes(), pgtype.Timestamptz{Time: expiresAt, Valid: true}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Taken over by another worker after running too long; its result stands.
+			return nil
+		}
+		return fmt.Errorf("failed to store export %s: %w", exportID, err)
+	}
+	s.logger.InfoContext(ctx, "Export completed", "export_id", exportID, "bytes", archive.Len(), "expires_at", expiresAt)
+	return nil
+}
+
+func (s *patientExportService) fail(ctx context.Context, job db.PatientExportJob, reason string) error {
+	_, err := s.exportRepo.FailPatientExportJob(ctx, db.FailPatientExportJobParams{
+		ID:    job.ID,
+		Error: pgtype.Text{String: reason, Valid: true},
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to mark export %s failed: %w", uuid.UUID(job.ID.Bytes), err)
+	}
+	return nil
+}
+
+// assemble reads everything held about the patient.
+func (s *patientExportService) assemble(ctx context.Context, exportID, patientID uuid.UUID) (*sar.Bundle, error) {
+	pid := pgtype.UUID{Bytes: patientID, Valid: true}
+	patient, err := s.patientRepo.GetPatientByID(ctx, pid)
+	if err != nil {
+		return nil, fmt.Errorf("get patient: %w", err)
+	}
+	bundle := &sar.Bundle{
+		ExportID:    exportID,
+		GeneratedAt: time.Now(),
+		Patient:     mapper.ConvertDBPatientToModel(&patient),
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		rows, err := s.visitRepo.ListPatientVisitsByPatientID(ctx, db.ListPatientVisitsByPatientIDParams{PatientID: pid, Limit: exportPageSize, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("list visits: %w", err)
+		}
+		for _, r := range rows {
+			visit, err := mapper.MapPatientVisit(&db.PatientVisit{
+				ID:           r.ID,
+				PatientID:    r.PatientID,
+				DoctorID:     r.DoctorID,
+				VisitDate:    r.VisitDate,
+				Symptoms:     r.Symptoms,
+				Diagnosis:    r.Diagnosis,
+				Prescription: r.Prescription,
+				Notes:        r.Notes,
+				CreatedAt:    r.CreatedAt,
+				UpdatedAt:    r.UpdatedAt,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("map visit: %w", err)
+			}
+			bundle.Visits = append(bundle.Visits, *visit)
+		}
+		if len(rows) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		rows, err := s.labRepo.ListCumulativeLabResultsByPatientID(ctx, db.ListCumulativeLabResultsByPatientIDParams{PatientID: pid, RowLimit: exportPageSize, RowOffset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("list lab results: %w", err)
+		}
+		for _, r := range rows {
+			bundle.LabResults = append(bundle.LabResults, mapper.MapCumulativeLabResult(r))
+		}
+		if len(rows) < exportPageSize {
+			break
+		}
+	}
+
+	policies, err := s.insuranceRepo.ListInsurancePoliciesByPatientID(ctx, pid)
+	if err != nil {
+		return nil, fmt.Errorf("list insurance policies: %w", err)
+	}
+	for _, p := range policies {
+		bundle.InsurancePolicies = append(bundle.InsurancePolicies, mapper.MapInsurancePolicy(p))
+	}
+
+	consents, err := s.consentRepo.ListConsentRecordsByPatientID(ctx, pid)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	for _, c := range consents {
+		bundle.Consents = append(bundle.Consents, mapper.MapConsentRecord(c))
+	}
+
+	err = s.auditService.ExportPatientAccessLog(ctx, patientID, exportPageSize, func(entries []model.AccessLogEntry) error {
+		bundle.AccessLog = append(bundle.AccessLog, entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// ExpireExports deletes archives whose download window has closed.
+func (s *patientExportService) ExpireExports(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.Start(ctx, "PatientExportService.ExpireExports")
+	defer span.End()
+	expired, err := s.exportRepo.ExpirePatientExports(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to expire exports: %w", err)
+	}
+	if expired > 0 {
+		s.logger.InfoContext(ctx, "Expired exports", "expired", expired)
+	}
+	return nil
+}
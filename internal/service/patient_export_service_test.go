@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/consent"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeConsents holds the consent types in force for each patient.
+type fakeConsents map[uuid.UUID][]model.ConsentType
+
+func (f fakeConsents) RequireConsent(_ context.Context, patientID uuid.UUID, t model.ConsentType) error {
+	for _, given := range f[patientID] {
+		if given == t {
+			return nil
+		}
+	}
+	return consent.Check(t, nil)
+}
+
+func TestRequestExportNeedsDataSharingConsent(t *testing.T) {
+	f := newCareTeamFixture()
+	consents := fakeConsents{f.inCare: {model.ConsentSMSContact}}
+	// Without an export repository, getting past the consent check would panic.
+	exports := NewPatientExportService(nil, nil, f.patients, nil, nil, nil, nil, consents, nil, discardLogger)
+
+	_, err := exports.RequestExport(context.Background(), f.inCare, uuid.New())
+	assert.ErrorIs(t, err, consent.ErrConsentRequired)
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/himanshu-holmes/hms/internal/db"
 	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/metrics"
 	"github.com/himanshu-holmes/hms/internal/model"
 	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -20,16 +22,30 @@ import (
 var ErrPatientNotFound = errors.New("patient not found")
 var ErrPatientUpdateForbidden = errors.New("user not authorized to update this patient's medical history")
 var ErrPatientConflict = errors.New("patient data conflicts with existing record")
+var ErrPatientVersionConflict = errors.New("patient was changed since it was read")
 
 type patientService struct {
 	patientRepo repository.PatientRepository
+	tx          repository.TxManager
+	auditor     AuditRecorder
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
 }
 
-func NewPatientService(patientRepo repository.PatientRepository) PatientService {
-	return &patientService{patientRepo: patientRepo}
+func NewPatientService(patientRepo repository.PatientRepository, tx repository.TxManager, auditor AuditRecorder, access PatientAccessAuthorizer, logger *slog.Logger) PatientService {
+	return &patientService{patientRepo: patientRepo, tx: tx, auditor: auditor, access: access, logger: logger.With("service", "PatientService")}
+}
+
+// redactPatient removes what a caller with demographics-only access may not see.
+func redactPatient(p *model.Patient, level PatientAccessLevel) {
+	if level < PatientAccessClinical {
+		p.MedicalHistory = nil
+	}
 }
 
 func (s *patientService) RegisterPatient(ctx context.Context, req model.ParsedPatientRequest, registeredByUserID uuid.UUID) (*model.Patient, error) {
+	ctx, span := tracing.Start(ctx, "PatientService.RegisterPatient")
+	defer span.End()
 	// patient := &model.Patient{
 	// 	// ID will be generated by DB
 	// 	FirstName:          req.FirstName,
@@ -49,72 +65,152 @@ func (s *patientService) RegisterPatient(ctx context.Context, req model.ParsedPa
 		DateOfBirth: func() pgtype.Date {
 			parsedDate, err := time.Parse("2006-01-02", req.DateOfBirth.Format("2006-01-02")) // Adjust format as per your date string
 			if err != nil {
-				log.Printf("PatientService: Invalid date format for DateOfBirth: %v", err)
+				s.logger.WarnContext(ctx, "Invalid date of birth", "error", err)
 				return pgtype.Date{Valid: false}
 			}
 			return pgtype.Date{Time: parsedDate, Valid: true}
 		}(),
-		Gender:             db.NullGenderEnum{GenderEnum: db.GenderEnum(*req.Gender), Valid: req.Gender != nil},
-		ContactPhone:       pgtype.Text{String: *req.ContactPhone, Valid: req.ContactPhone != nil},
-		ContactEmail:       pgtype.Text{String: *req.ContactEmail, Valid: req.ContactEmail != nil},
-		Address:            pgtype.Text{String: *req.Address, Valid: req.Address != nil},
-		MedicalHistory:     pgtype.Text{String: *req.MedicalHistory, Valid: req.MedicalHistory != nil}, // Receptionist can set initial medical history
+		Gender:             db.NullGenderEnum{GenderEnum: db.GenderEnum(derefGender(req.Gender)), Valid: req.Gender != nil},
+		ContactPhone:       pgtype.Text{String: derefString(req.ContactPhone), Valid: req.ContactPhone != nil},
+		ContactEmail:       pgtype.Text{String: derefString(req.ContactEmail), Valid: req.ContactEmail != nil},
+		Address:            pgtype.Text{String: derefString(req.Address), Valid: req.Address != nil},
+		MedicalHistory:     pgtype.Text{String: derefString(req.MedicalHistory), Valid: req.MedicalHistory != nil}, // Receptionist can set initial medical history
 		RegisteredByUserID: pgtype.UUID{Bytes: registeredByUserID, Valid: true},
 
 	}
 
-	patient, err := s.patientRepo.CreatePatient(ctx, patientParams)
+	// The patient is only created if its audit event is recorded with it.
+	var patient db.Patient
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		var err error
+		if patient, err = r.Patients.CreatePatient(ctx, patientParams); err != nil {
+			return err
+		}
+		created := mapper.ConvertDBPatientToModel(&patient)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &created.ID,
+			PatientID:    &created.ID,
+			Changes:      auditDiff(ctx, s.logger, nil, created),
+		})
+	})
 	if err != nil {
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") ||
 			strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
-			log.Printf("PatientService: Conflict creating patient: %v", err)
+			s.logger.WarnContext(ctx, "Conflict creating patient", "error", err)
 			return nil, ErrPatientConflict
 		}
-		log.Printf("PatientService: Failed to register patient in repo: %v", err)
+		s.logger.ErrorContext(ctx, "Failed to register patient in repo", "error", err)
 		return nil, fmt.Errorf("failed to register patient: %w", err)
 	}
 
+	metrics.PatientsRegistered.Inc()
  formattedPatient := mapper.ConvertDBPatientToModel(&patient)
-	
-	
 	return &formattedPatient, nil
 }
 
 func (s *patientService) GetPatientDetails(ctx context.Context, patientID uuid.UUID) (*model.Patient, error) {
+	ctx, span := tracing.Start(ctx, "PatientService.GetPatientDetails")
+	defer span.End()
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
 	convertedPatientID := pgtype.UUID{Bytes: patientID, Valid: true}
 	patient, err := s.patientRepo.GetPatientByID(ctx, convertedPatientID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrPatientNotFound
 		}
-		log.Printf("PatientService: Failed to get patient details for ID %s: %v", patientID, err)
+		s.logger.ErrorContext(ctx, "Failed to get patient details", "patient_id", patientID, "error", err)
 		return nil, fmt.Errorf("failed to get patient details: %w", err)
 	}
     formattedPatient := mapper.ConvertDBPatientToModel(&patient)
+	// Reads fail closed: the record is not returned unless the access was recorded.
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionRead,
+		ResourceType: model.AuditResourcePatient,
+		ResourceID:   &patientID,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, err
+	}
+	redactPatient(&formattedPatient, level)
 	return &formattedPatient, nil
 }
 
-func (s *patientService) ListPatients(ctx context.Context, params model.PaginationParams) ([]model.Patient, int64, error) {
+// FindPatientByContact matches phone numbers and email addresses after normalising them,
+// so "+91 98765 43210" finds "+919876543210". Exactly one of phone and email is used,
+// phone first. The match is then read like GetPatientDetails.
+func (s *patientService) FindPatientByContact(ctx context.Context, phone, email string) (*model.Patient, error) {
+	ctx, span := tracing.Start(ctx, "PatientService.FindPatientByContact")
+	defer span.End()
+	var patient db.Patient
+	var err error
+	if phone != "" {
+		patient, err = s.patientRepo.GetPatientByContactPhone(ctx, phone)
+	} else {
+		patient, err = s.patientRepo.GetPatientByContactEmail(ctx, email)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to look up patient by contact", "error", err)
+		return nil, fmt.Errorf("failed to look up patient: %w", err)
+	}
+	return s.GetPatientDetails(ctx, uuid.UUID(patient.ID.Bytes))
+}
 
-	patients, err := s.patientRepo.ListPatients(ctx, db.ListPatientsParams{
-		Limit:  int32(params.Limit),
-		Offset: int32(params.Offset),
-	})
+func (s *patientService) ListPatients(ctx context.Context, params model.PaginationParams) ([]model.Patient, int64, error) {
+	ctx, span := tracing.Start(ctx, "PatientService.ListPatients")
+	defer span.End()
+	careOf, level := s.access.PatientListScope(ctx)
+	var patients []db.Patient
+	var err error
+	if careOf != nil {
+		patients, err = s.patientRepo.ListPatientsInCareOf(ctx, db.ListPatientsInCareOfParams{
+			UserID:    pgtype.UUID{Bytes: *careOf, Valid: true},
+			RowLimit:  int32(params.Limit),
+			RowOffset: int32(params.Offset),
+		})
+	} else {
+		patients, err = s.patientRepo.ListPatients(ctx, db.ListPatientsParams{
+			Limit:  int32(params.Limit),
+			Offset: int32(params.Offset),
+		})
+	}
 	if err != nil {
-		log.Printf("PatientService: Failed to list patients: %v", err)
+		s.logger.ErrorContext(ctx, "Failed to list patients", "error", err)
 		return nil, 0, fmt.Errorf("failed to list patients: %w", err)
 	}
     var formattedPatients []model.Patient
 	for _, patient := range patients {
 		formattedPatient := mapper.ConvertDBPatientToModel(&patient)
+		redactPatient(&formattedPatient, level)
 		formattedPatients = append(formattedPatients, formattedPatient)
 	}
 
-	total, err := s.patientRepo.CountPatients(ctx)
+	var total int64
+	if careOf != nil {
+		total, err = s.patientRepo.CountPatientsInCareOf(ctx, pgtype.UUID{Bytes: *careOf, Valid: true})
+	} else {
+		total, err = s.patientRepo.CountPatients(ctx)
+	}
 	if err != nil {
-		log.Printf("PatientService: Failed to count patients: %v", err)
+		s.logger.ErrorContext(ctx, "Failed to count patients", "error", err)
 		return nil, 0, fmt.Errorf("failed to count patients: %w", err)
 	}
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionList,
+		ResourceType: model.AuditResourcePatient,
+	}); err != nil {
+		return nil, 0, err
+	}
 	
 	return formattedPatients, total, nil
 }
@@ -125,17 +221,31 @@ func (s *patientService) UpdatePatientDetails(
 	req model.ParsedPatientRequest,
 	updaterRole model.UserRole,
 	updaterID uuid.UUID, // ID of the user performing the update
+	ifMatch *int32, // Version the update was made against; nil applies it to the current one
 ) (*model.Patient, error) {
+	ctx, span := tracing.Start(ctx, "PatientService.UpdatePatientDetails")
+	defer span.End()
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
 	convertedPatientID := pgtype.UUID{Bytes: patientID, Valid: true}
 	existingPatient, err := s.patientRepo.GetPatientByID(ctx, convertedPatientID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows)  {
 			return nil, ErrPatientNotFound
 		}
-		log.Printf("PatientService: Failed to fetch patient %s for update: %v", patientID, err)
+		s.logger.ErrorContext(ctx, "Failed to fetch patient for update", "patient_id", patientID, "error", err)
 		return nil, fmt.Errorf("failed to fetch patient for update: %w", err)
 	}
-	
+	if ifMatch != nil && *ifMatch != existingPatient.Version {
+		return nil, ErrPatientVersionConflict
+	}
+	before := mapper.ConvertDBPatientToModel(&existingPatient)
+	if existingPatient.PseudonymizedAt.Valid && req.IdentifiersSet() {
+		// Erased identifiers must not come back.
+		return nil, ErrPatientPseudonymized
+	}
 
 	// Apply updates based on request fields.
 	// ParsedPatientRequest will have zero values for fields not in the JSON update request.
@@ -197,7 +307,7 @@ func (s *patientService) UpdatePatientDetails(
 				changed = true
 			} else if updaterRole == model.RoleReceptionist && !isNewMH && *req.MedicalHistory != existingPatient.MedicalHistory.String {
 				// Receptionist trying to change existing non-null medical history
-				log.Printf("PatientService: Receptionist %s attempted to update existing medical history for patient %s.", updaterID, patientID)
+				s.logger.WarnContext(ctx, "Receptionist attempted to update existing medical history", "user_id", updaterID, "patient_id", patientID)
 				return nil, ErrPatientUpdateForbidden
 			}
 		}
@@ -205,8 +315,17 @@ func (s *patientService) UpdatePatientDetails(
 
    
 	if !changed {
-		log.Printf("PatientService: No changes detected for patient %s update. Returning existing.", patientID)
+		s.logger.InfoContext(ctx, "No changes to patient; returning existing", "patient_id", patientID)
 		formattedPatient := mapper.ConvertDBPatientToModel(&existingPatient)
+		if err := s.auditor.Record(ctx, model.AuditEntry{
+			Action:       model.AuditActionRead,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &patientID,
+			PatientID:    &patientID,
+		}); err != nil {
+			return nil, err
+		}
+		redactPatient(&formattedPatient, level)
 		return &formattedPatient, nil // No actual update needed
 	}
 
@@ -220,52 +339,92 @@ func (s *patientService) UpdatePatientDetails(
 		ContactEmail:     existingPatient.ContactEmail,
 		Address:          existingPatient.Address,
 		MedicalHistory:   existingPatient.MedicalHistory,
+
+		// Only applies if nobody else updated the patient since it was read above.
+		ExpectedVersion: existingPatient.Version,
 		 // Assuming updaterID is the user performing the update
 	}
-	_, err = s.patientRepo.UpdatePatient(ctx, updateParams)
+	// The update, the refetch of its timestamps and the audit event run in one
+	// transaction, so the patient returned is the one written and recorded.
+	var updatedPatient db.Patient
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		stored, err := r.Patients.UpdatePatient(ctx, updateParams)
+		if err != nil {
+			return err
+		}
+		if updatedPatient, err = r.Patients.GetPatientByID(ctx, convertedPatientID); err != nil {
+			return err
+		}
+		after := mapper.ConvertDBPatientToModel(&stored)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &patientID,
+			PatientID:    &patientID,
+			Changes:      auditDiff(ctx, s.logger, before, after),
+		})
+	})
 	if err != nil {
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientVersionConflict
+		}
 		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") ||
 			strings.Contains(strings.ToLower(err.Error()), "duplicate key") {
-			log.Printf("PatientService: Conflict updating patient %s: %v", patientID, err)
+			s.logger.WarnContext(ctx, "Conflict updating patient", "patient_id", patientID, "error", err)
 			return nil, ErrPatientConflict
 		}
-		log.Printf("PatientService: Failed to update patient %s in repo: %v", patientID, err)
+		s.logger.ErrorContext(ctx, "Failed to update patient in repo", "patient_id", patientID, "error", err)
 		return nil, fmt.Errorf("failed to update patient: %w", err)
 	}
-
-	// Refetch to get updated timestamps and ensure consistency
-	convertedPatientID = pgtype.UUID{Bytes: patientID, Valid: true}
-	updatedPatient, err := s.patientRepo.GetPatientByID(ctx, convertedPatientID)
-	if err != nil {
-		log.Printf("PatientService: Error fetching updated patient %s: %v. Returning locally modified patient.", patientID, err)
-		formattedPatient := mapper.ConvertDBPatientToModel(&updatedPatient)
-		return &formattedPatient, nil // Return what we have if refetch fails
-	}
 	formattedPatient := mapper.ConvertDBPatientToModel(&updatedPatient)
+	redactPatient(&formattedPatient, level)
 	return &formattedPatient, nil
 }
 
 func (s *patientService) DeletePatientRecord(ctx context.Context, patientID uuid.UUID, deletedByUserID uuid.UUID) error {
-	// First, check if patient exists to provide a better error message
-	convertedPatientID := pgtype.UUID{Bytes: [16]byte(patientID), Valid: true}
-	_, err := s.patientRepo.GetPatientByID(ctx, convertedPatientID)
+	ctx, span := tracing.Start(ctx, "PatientService.DeletePatientRecord")
+	defer span.End()
+	ctx, _, err := s.access.AuthorizePatientAccess(ctx, patientID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrPatientNotFound
-		}
-		log.Printf("PatientService: Error checking patient %s before delete: %v", patientID, err)
-		return fmt.Errorf("error preparing to delete patient: %w", err)
+		return err
 	}
-
-	convertedPatientID = pgtype.UUID{Bytes: patientID, Valid: true}
-	_, err = s.patientRepo.SoftDeletePatient(ctx, convertedPatientID)
+	convertedPatientID := pgtype.UUID{Bytes: [16]byte(patientID), Valid: true}
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		// First, check if patient exists to provide a better error message
+		if _, err := r.Patients.GetPatientByID(ctx, convertedPatientID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPatientNotFound
+			}
+			s.logger.ErrorContext(ctx, "Error checking patient before delete", "patient_id", patientID, "error", err)
+			return fmt.Errorf("error preparing to delete patient: %w", err)
+		}
+		deleted, err := r.Patients.SoftDeletePatient(ctx, convertedPatientID)
+		if err != nil {
+			return err
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionDelete,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &patientID,
+			PatientID:    &patientID,
+			Changes: map[string]model.FieldChange{
+				"deleted_at": {New: deleted.DeletedAt.Time.UTC().Format(time.RFC3339Nano)},
+			},
+		})
+	})
 	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) || errors.Is(err, ErrAuditUnavailable) {
+			return err
+		}
 		// The repository DeletePatient might return specific errors for "already deleted" or "not found"
 		// which could be sql.ErrNoRows or a custom error.
 		if errors.Is(err, pgx.ErrNoRows) || strings.Contains(err.Error(), "already deleted") { // Adapt to repo's specific error
 			return ErrPatientNotFound // Treat as "not found" if already deleted for idempotency
 		}
-		log.Printf("PatientService: Failed to delete patient %s by user %s: %v", patientID, deletedByUserID, err)
+		s.logger.ErrorContext(ctx, "Failed to delete patient", "patient_id", patientID, "deleted_by_user_id", deletedByUserID, "error", err)
 		return fmt.Errorf("failed to delete patient: %w", err)
 	}
 	return nil
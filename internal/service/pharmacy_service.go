@@ -0,0 +1,601 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/pharmacy"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPharmacyLocationNotFound = errors.New("pharmacy location not found")
+var ErrPharmacyLocationAlreadyExists = errors.New("pharmacy location with this code already exists")
+var ErrPharmacyItemNotFound = errors.New("pharmacy item not found")
+var ErrPharmacyItemAlreadyExists = errors.New("pharmacy item with this code already exists")
+var ErrBatchNotFound = errors.New("batch not found")
+var ErrBatchExpired = errors.New("batch is already expired")
+var ErrBatchExpiryMismatch = errors.New("lot number already received with a different expiry date")
+var ErrNoPrescription = errors.New("visit has no prescription to dispense against")
+var ErrInsufficientStock = pharmacy.ErrInsufficientStock
+var ErrStockMovementNotFound = errors.New("stock movement not found")
+var ErrNotADispense = errors.New("only dispensed stock can be returned")
+var ErrReturnExceedsDispensed = errors.New("return exceeds the quantity dispensed")
+
+type pharmacyService struct {
+	pharmacyRepo repository.PharmacyRepository
+	tx           repository.TxManager
+	visitRepo    repository.PatientVisitQuerier
+	access       PatientAccessAuthorizer
+	logger       *slog.Logger
+}
+
+func NewPharmacyService(pharmacyRepo repository.PharmacyRepository, tx repository.TxManager, visitRepo repository.PatientVisitQuerier, access PatientAccessAuthorizer, logger *slog.Logger) PharmacyService {
+	return &pharmacyService{pharmacyRepo: pharmacyRepo, tx: tx, visitRepo: visitRepo, access: access, logger: logger.With("service", "PharmacyService")}
+}
+
+// redactDispensation removes what was dispensed, which reveals the prescription, from a
+// caller with demographics-only access. Pharmacists dispense and return the stock, so they
+// see it in full.
+func redactDispensation(ctx context.Context, m *model.StockMovement, level PatientAccessLevel) {
+	if level < PatientAccessClinical && audit.FromContext(ctx).ActorRole != model.RolePharmacist {
+		m.ItemID, m.ItemCode, m.BatchID, m.LotNumber = uuid.Nil, "", uuid.Nil, ""
+		m.Reason = nil
+	}
+}
+
+func isCheckViolation(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "check constraint")
+}
+
+// movementRow joins a new ledger entry with the batch and item it moved, matching the listing queries.
+func movementRow(m db.StockMovement, batch db.PharmacyBatch, item db.PharmacyItem) db.ListStockMovementsRow {
+	return db.ListStockMovementsRow{
+		ID:                 m.ID,
+		MovementType:       m.MovementType,
+		LocationID:         m.LocationID,
+		BatchID:            m.BatchID,
+		Quantity:           m.Quantity,
+		VisitID:            m.VisitID,
+		ReturnedMovementID: m.ReturnedMovementID,
+		Reference:          m.Reference,
+		Reason:             m.Reason,
+		CreatedByUserID:    m.CreatedByUserID,
+		CreatedAt:          m.CreatedAt,
+		LotNumber:          batch.LotNumber,
+		ItemID:             item.ID,
+		ItemCode:           item.Code,
+	}
+}
+
+func optionalUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+func (s *pharmacyService) CreateLocation(ctx context.Context, req model.PharmacyLocationCreateRequest) (*model.PharmacyLocation, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.CreateLocation")
+	defer span.End()
+	location, err := s.pharmacyRepo.CreatePharmacyLocation(ctx, db.CreatePharmacyLocationParams{Code: req.Code, Name: req.Name})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPharmacyLocationAlreadyExists
+		}
+		s.logger.ErrorContext(ctx, "Failed to create location", "code", req.Code, "error", err)
+		return nil, fmt.Errorf("failed to create pharmacy location: %w", err)
+	}
+	mapped := mapper.MapPharmacyLocation(location)
+	return &mapped, nil
+}
+
+func (s *pharmacyService) ListLocations(ctx context.Context) ([]model.PharmacyLocation, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListLocations")
+	defer span.End()
+	locations, err := s.pharmacyRepo.ListPharmacyLocations(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list locations", "error", err)
+		return nil, fmt.Errorf("failed to list pharmacy locations: %w", err)
+	}
+	mapped := make([]model.PharmacyLocation, 0, len(locations))
+	for _, l := range locations {
+		mapped = append(mapped, mapper.MapPharmacyLocation(l))
+	}
+	return mapped, nil
+}
+
+func (s *pharmacyService) CreateItem(ctx context.Context, req model.PharmacyItemCreateRequest) (*model.PharmacyItem, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.CreateItem")
+	defer span.End()
+	unit := req.Unit
+	if unit == "" {
+		unit = "unit"
+	}
+	item, err := s.pharmacyRepo.CreatePharmacyItem(ctx, db.CreatePharmacyItemParams{
+		Code:         req.Code,
+		Name:         req.Name,
+		DosageForm:   pgtype.Text{String: derefString(req.DosageForm), Valid: req.DosageForm != nil},
+		Strength:     pgtype.Text{String: derefString(req.Strength), Valid: req.Strength != nil},
+		Unit:         unit,
+		ReorderLevel: int32(req.ReorderLevel),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPharmacyItemAlreadyExists
+		}
+		s.logger.ErrorContext(ctx, "Failed to create item", "code", req.Code, "error", err)
+		return nil, fmt.Errorf("failed to create pharmacy item: %w", err)
+	}
+	mapped := mapper.MapPharmacyItem(item)
+	return &mapped, nil
+}
+
+func (s *pharmacyService) ListItems(ctx context.Context, params model.PaginationParams) ([]model.PharmacyItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListItems")
+	defer span.End()
+	items, err := s.pharmacyRepo.ListPharmacyItems(ctx, db.ListPharmacyItemsParams{
+		Limit:  int32(params.Limit),
+		Offset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list items", "error", err)
+		return nil, 0, fmt.Errorf("failed to list pharmacy items: %w", err)
+	}
+	total, err := s.pharmacyRepo.CountPharmacyItems(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count items", "error", err)
+		return nil, 0, fmt.Errorf("failed to count pharmacy items: %w", err)
+	}
+	mapped := make([]model.PharmacyItem, 0, len(items))
+	for _, i := range items {
+		mapped = append(mapped, mapper.MapPharmacyItem(i))
+	}
+	return mapped, total, nil
+}
+
+func (s *pharmacyService) getActiveLocation(ctx context.Context, repo repository.PharmacyRepository, locationID uuid.UUID) error {
+	location, err := repo.GetPharmacyLocationByID(ctx, pgtype.UUID{Bytes: locationID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPharmacyLocationNotFound
+		}
+		return fmt.Errorf("error fetching pharmacy location: %w", err)
+	}
+	if !location.IsActive {
+		return fmt.Errorf("%w: %s is inactive", ErrPharmacyLocationNotFound, location.Code)
+	}
+	return nil
+}
+
+func (s *pharmacyService) getActiveItem(ctx context.Context, repo repository.PharmacyRepository, itemID uuid.UUID) (db.PharmacyItem, error) {
+	item, err := repo.GetPharmacyItemByID(ctx, pgtype.UUID{Bytes: itemID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return item, fmt.Errorf("%w: %s", ErrPharmacyItemNotFound, itemID)
+		}
+		return item, fmt.Errorf("error fetching pharmacy item: %w", err)
+	}
+	if !item.IsActive {
+		return item, fmt.Errorf("%w: %s is inactive", ErrPharmacyItemNotFound, item.Code)
+	}
+	return item, nil
+}
+
+// ReceiveStock books a goods receipt into a location, creating batches for new lots.
+func (s *pharmacyService) ReceiveStock(ctx context.Context, req model.GoodsReceiptRequest, receivedByUserID uuid.UUID) ([]model.StockMovement, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ReceiveStock")
+	defer span.End()
+	today := time.Now()
+	expiries := make([]time.Time, len(req.Lines))
+	for i, line := range req.Lines {
+		expiry, err := time.Parse("2006-01-02", line.ExpiryDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry_date format: %w. Expected YYYY-MM-DD", err)
+		}
+		if pharmacy.IsExpired(expiry, today) {
+			return nil, fmt.Errorf("%w: lot %s expired on %s", ErrBatchExpired, line.LotNumber, line.ExpiryDate)
+		}
+		expiries[i] = expiry
+	}
+
+	var movements []model.StockMovement
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if err := s.getActiveLocation(ctx, r.Pharmacy, req.LocationID); err != nil {
+			return err
+		}
+		locationID := pgtype.UUID{Bytes: req.LocationID, Valid: true}
+		movements = make([]model.StockMovement, 0, len(req.Lines))
+		for i, line := range req.Lines {
+			item, err := s.getActiveItem(ctx, r.Pharmacy, line.ItemID)
+			if err != nil {
+				return err
+			}
+			batch, err := r.Pharmacy.UpsertPharmacyBatch(ctx, db.UpsertPharmacyBatchParams{
+				ItemID:     item.ID,
+				LotNumber:  line.LotNumber,
+				ExpiryDate: pgtype.Date{Time: expiries[i], Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record batch: %w", err)
+			}
+			if !batch.ExpiryDate.Time.Equal(expiries[i]) {
+				return fmt.Errorf("%w: %s lot %s expires %s", ErrBatchExpiryMismatch, item.Code, batch.LotNumber, batch.ExpiryDate.Time.Format("2006-01-02"))
+			}
+			if _, err := r.Pharmacy.ApplyStockDelta(ctx, db.ApplyStockDeltaParams{LocationID: locationID, BatchID: batch.ID, Delta: int32(line.Quantity)}); err != nil {
+				return fmt.Errorf("failed to update stock level: %w", err)
+			}
+			movement, err := r.Pharmacy.CreateStockMovement(ctx, db.CreateStockMovementParams{
+				MovementType:    db.StockMovementTypeReceipt,
+				LocationID:      locationID,
+				BatchID:         batch.ID,
+				Quantity:        int32(line.Quantity),
+				Reference:       pgtype.Text{String: derefString(req.Reference), Valid: req.Reference != nil},
+				CreatedByUserID: pgtype.UUID{Bytes: receivedByUserID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record stock movement: %w", err)
+			}
+			movements = append(movements, mapper.MapStockMovement(movementRow(movement, batch, item)))
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPharmacyLocationNotFound) || errors.Is(err, ErrPharmacyItemNotFound) || errors.Is(err, ErrBatchExpiryMismatch) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to receive stock into location", "location_id", req.LocationID, "error", err)
+		return nil, fmt.Errorf("failed to receive stock: %w", err)
+	}
+	return movements, nil
+}
+
+// DispenseForVisit dispenses a visit's prescription, drawing each line from the
+// earliest-expiring unexpired batches at the location.
+func (s *pharmacyService) DispenseForVisit(ctx context.Context, visitID uuid.UUID, req model.DispenseRequest, dispensedByUserID uuid.UUID) ([]model.StockMovement, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.DispenseForVisit")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error fetching visit for dispense", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("error fetching visit: %w", err)
+	}
+	if !visit.Prescription.Valid || strings.TrimSpace(visit.Prescription.String) == "" {
+		return nil, ErrNoPrescription
+	}
+
+	today := time.Now()
+	locationID := pgtype.UUID{Bytes: req.LocationID, Valid: true}
+	var movements []model.StockMovement
+	var dispensed []db.PharmacyItem
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if err := s.getActiveLocation(ctx, r.Pharmacy, req.LocationID); err != nil {
+			return err
+		}
+		movements = make([]model.StockMovement, 0, len(req.Lines))
+		dispensed = make([]db.PharmacyItem, 0, len(req.Lines))
+		for _, line := range req.Lines {
+			item, err := s.getActiveItem(ctx, r.Pharmacy, line.ItemID)
+			if err != nil {
+				return err
+			}
+			rows, err := r.Pharmacy.LockItemStockAtLocation(ctx, db.LockItemStockAtLocationParams{LocationID: locationID, ItemID: item.ID})
+			if err != nil {
+				return fmt.Errorf("failed to lock stock: %w", err)
+			}
+			stock := make([]pharmacy.BatchStock, 0, len(rows))
+			batches := make(map[uuid.UUID]db.PharmacyBatch, len(rows))
+			for _, row := range rows {
+				stock = append(stock, pharmacy.BatchStock{BatchID: row.BatchID.Bytes, ExpiryDate: row.ExpiryDate.Time, Quantity: int(row.Quantity)})
+				batches[row.BatchID.Bytes] = db.PharmacyBatch{ID: row.BatchID, ItemID: item.ID, LotNumber: row.LotNumber, ExpiryDate: row.ExpiryDate}
+			}
+			allocations, err := pharmacy.AllocateFEFO(stock, line.Quantity, today)
+			if err != nil {
+				return fmt.Errorf("item %s: %w", item.Code, err)
+			}
+
+			for _, a := range allocations {
+				batch := batches[a.BatchID]
+				if _, err := r.Pharmacy.ApplyStockDelta(ctx, db.ApplyStockDeltaParams{LocationID: locationID, BatchID: batch.ID, Delta: -int32(a.Quantity)}); err != nil {
+					return fmt.Errorf("failed to update stock level: %w", err)
+				}
+				movement, err := r.Pharmacy.CreateStockMovement(ctx, db.CreateStockMovementParams{
+					MovementType:    db.StockMovementTypeDispense,
+					LocationID:      locationID,
+					BatchID:         batch.ID,
+					Quantity:        -int32(a.Quantity),
+					VisitID:         visit.ID,
+					CreatedByUserID: pgtype.UUID{Bytes: dispensedByUserID, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record stock movement: %w", err)
+				}
+				movements = append(movements, mapper.MapStockMovement(movementRow(movement, batch, item)))
+			}
+			dispensed = append(dispensed, item)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPharmacyLocationNotFound) || errors.Is(err, ErrPharmacyItemNotFound) || errors.Is(err, ErrInsufficientStock) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to dispense", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to dispense: %w", err)
+	}
+
+	for _, item := range dispensed {
+		s.checkReorderLevel(ctx, req.LocationID, item, today)
+	}
+	return movements, nil
+}
+
+// checkReorderLevel logs a reorder alert when an item's unexpired stock at a location
+// has fallen to its reorder level. Failures are logged, never returned.
+func (s *pharmacyService) checkReorderLevel(ctx context.Context, locationID uuid.UUID, item db.PharmacyItem, today time.Time) {
+	if item.ReorderLevel == 0 {
+		return
+	}
+	onHand, err := s.pharmacyRepo.GetItemStockOnHand(ctx, db.GetItemStockOnHandParams{
+		LocationID: pgtype.UUID{Bytes: locationID, Valid: true},
+		ItemID:     item.ID,
+		AsOf:       pgtype.Date{Time: today, Valid: true},
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to check reorder level", "item_code", item.Code, "location_id", locationID, "error", err)
+		return
+	}
+	if pharmacy.NeedsReorder(onHand, int(item.ReorderLevel)) {
+		s.logger.WarnContext(ctx, "REORDER", "item_code", item.Code, "location_id", locationID, "on_hand", onHand, "reorder_level", item.ReorderLevel)
+	}
+}
+
+// ReturnDispensed puts part or all of a dispense back into the batch and location it came from.
+func (s *pharmacyService) ReturnDispensed(ctx context.Context, movementID uuid.UUID, req model.StockReturnRequest, returnedByUserID uuid.UUID) (*model.StockMovement, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ReturnDispensed")
+	defer span.End()
+	var result model.StockMovement
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		original, err := r.Pharmacy.LockStockMovement(ctx, pgtype.UUID{Bytes: movementID, Valid: true})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrStockMovementNotFound
+			}
+			return fmt.Errorf("error fetching stock movement: %w", err)
+		}
+		if original.MovementType != db.StockMovementTypeDispense {
+			return ErrNotADispense
+		}
+		returned, err := r.Pharmacy.SumReturnedQuantity(ctx, original.ID)
+		if err != nil {
+			return fmt.Errorf("failed to sum returns: %w", err)
+		}
+		if returned+int64(req.Quantity) > -int64(original.Quantity) {
+			return fmt.Errorf("%w: dispensed %d, already returned %d", ErrReturnExceedsDispensed, -original.Quantity, returned)
+		}
+
+		batch, err := r.Pharmacy.GetPharmacyBatchByID(ctx, original.BatchID)
+		if err != nil {
+			return fmt.Errorf("error fetching batch: %w", err)
+		}
+		item, err := r.Pharmacy.GetPharmacyItemByID(ctx, batch.ItemID)
+		if err != nil {
+			return fmt.Errorf("error fetching pharmacy item: %w", err)
+		}
+		if _, err := r.Pharmacy.ApplyStockDelta(ctx, db.ApplyStockDeltaParams{LocationID: original.LocationID, BatchID: original.BatchID, Delta: int32(req.Quantity)}); err != nil {
+			return fmt.Errorf("failed to update stock level: %w", err)
+		}
+		movement, err := r.Pharmacy.CreateStockMovement(ctx, db.CreateStockMovementParams{
+			MovementType:       db.StockMovementTypeReturn,
+			LocationID:         original.LocationID,
+			BatchID:            original.BatchID,
+			Quantity:           int32(req.Quantity),
+			VisitID:            original.VisitID,
+			ReturnedMovementID: original.ID,
+			Reason:             pgtype.Text{String: derefString(req.Reason), Valid: req.Reason != nil},
+			CreatedByUserID:    pgtype.UUID{Bytes: returnedByUserID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record stock movement: %w", err)
+		}
+		result = mapper.MapStockMovement(movementRow(movement, batch, item))
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrStockMovementNotFound) || errors.Is(err, ErrNotADispense) || errors.Is(err, ErrReturnExceedsDispensed) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to return stock for movement", "movement_id", movementID, "error", err)
+		return nil, fmt.Errorf("failed to return stock: %w", err)
+	}
+	return &result, nil
+}
+
+// AdjustStock records a counted correction, breakage or write-off against a batch.
+func (s *pharmacyService) AdjustStock(ctx context.Context, req model.StockAdjustmentRequest, adjustedByUserID uuid.UUID) (*model.StockMovement, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.AdjustStock")
+	defer span.End()
+	var result model.StockMovement
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if err := s.getActiveLocation(ctx, r.Pharmacy, req.LocationID); err != nil {
+			return err
+		}
+		batch, err := r.Pharmacy.GetPharmacyBatchByID(ctx, pgtype.UUID{Bytes: req.BatchID, Valid: true})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrBatchNotFound
+			}
+			return fmt.Errorf("error fetching batch: %w", err)
+		}
+		item, err := r.Pharmacy.GetPharmacyItemByID(ctx, batch.ItemID)
+		if err != nil {
+			return fmt.Errorf("error fetching pharmacy item: %w", err)
+		}
+		locationID := pgtype.UUID{Bytes: req.LocationID, Valid: true}
+		if _, err := r.Pharmacy.ApplyStockDelta(ctx, db.ApplyStockDeltaParams{LocationID: locationID, BatchID: batch.ID, Delta: int32(req.Quantity)}); err != nil {
+			if isCheckViolation(err) {
+				return fmt.Errorf("%w: adjustment would take %s lot %s below zero", ErrInsufficientStock, item.Code, batch.LotNumber)
+			}
+			return fmt.Errorf("failed to update stock level: %w", err)
+		}
+		movement, err := r.Pharmacy.CreateStockMovement(ctx, db.CreateStockMovementParams{
+			MovementType:    db.StockMovementTypeAdjustment,
+			LocationID:      locationID,
+			BatchID:         batch.ID,
+			Quantity:        int32(req.Quantity),
+			Reason:          pgtype.Text{String: req.Reason, Valid: true},
+			CreatedByUserID: pgtype.UUID{Bytes: adjustedByUserID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record stock movement: %w", err)
+		}
+		result = mapper.MapStockMovement(movementRow(movement, batch, item))
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPharmacyLocationNotFound) || errors.Is(err, ErrBatchNotFound) || errors.Is(err, ErrInsufficientStock) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to adjust batch at location", "batch_id", req.BatchID, "location_id", req.LocationID, "error", err)
+		return nil, fmt.Errorf("failed to adjust stock: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *pharmacyService) ListStockLevels(ctx context.Context, locationID, itemID *uuid.UUID) ([]model.StockLevel, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListStockLevels")
+	defer span.End()
+	rows, err := s.pharmacyRepo.ListStockLevels(ctx, db.ListStockLevelsParams{
+		LocationID: optionalUUID(locationID),
+		ItemID:     optionalUUID(itemID),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list stock levels", "error", err)
+		return nil, fmt.Errorf("failed to list stock levels: %w", err)
+	}
+	today := time.Now()
+	levels := make([]model.StockLevel, 0, len(rows))
+	for _, r := range rows {
+		level := mapper.MapStockLevel(r)
+		level.Expired = pharmacy.IsExpired(level.ExpiryDate, today)
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func (s *pharmacyService) ListMovements(ctx context.Context, filter model.StockMovementFilter, params model.PaginationParams) ([]model.StockMovement, int64, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListMovements")
+	defer span.End()
+	rows, err := s.pharmacyRepo.ListStockMovements(ctx, db.ListStockMovementsParams{
+		LocationID: optionalUUID(filter.LocationID),
+		ItemID:     optionalUUID(filter.ItemID),
+		BatchID:    optionalUUID(filter.BatchID),
+		RowLimit:   int32(params.Limit),
+		RowOffset:  int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list stock movements", "error", err)
+		return nil, 0, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+	total, err := s.pharmacyRepo.CountStockMovements(ctx, db.CountStockMovementsParams{
+		LocationID: optionalUUID(filter.LocationID),
+		ItemID:     optionalUUID(filter.ItemID),
+		BatchID:    optionalUUID(filter.BatchID),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count stock movements", "error", err)
+		return nil, 0, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+	movements := make([]model.StockMovement, 0, len(rows))
+	for _, r := range rows {
+		movements = append(movements, mapper.MapStockMovement(r))
+	}
+	return movements, total, nil
+}
+
+func (s *pharmacyService) ListVisitDispensations(ctx context.Context, visitID uuid.UUID) ([]model.StockMovement, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListVisitDispensations")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Error checking visit before listing dispensations", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("error verifying visit before listing dispensations: %w", err)
+	}
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, uuid.UUID(visit.PatientID.Bytes))
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+	rows, err := s.pharmacyRepo.ListVisitStockMovements(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list dispensations", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to list dispensations: %w", err)
+	}
+	movements := make([]model.StockMovement, 0, len(rows))
+	for _, r := range rows {
+		movement := mapper.MapStockMovement(db.ListStockMovementsRow(r))
+		redactDispensation(ctx, &movement, level)
+		movements = append(movements, movement)
+	}
+	return movements, nil
+}
+
+func (s *pharmacyService) ListReorderAlerts(ctx context.Context, locationID *uuid.UUID) ([]model.ReorderAlert, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.ListReorderAlerts")
+	defer span.End()
+	rows, err := s.pharmacyRepo.ListItemsBelowReorderLevel(ctx, db.ListItemsBelowReorderLevelParams{
+		AsOf:       pgtype.Date{Time: time.Now(), Valid: true},
+		LocationID: optionalUUID(locationID),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list reorder alerts", "error", err)
+		return nil, fmt.Errorf("failed to list reorder alerts: %w", err)
+	}
+	alerts := make([]model.ReorderAlert, 0, len(rows))
+	for _, r := range rows {
+		alerts = append(alerts, mapper.MapReorderAlert(r))
+	}
+	return alerts, nil
+}
+
+// NearExpiryReport lists stock on hand expiring within the next withinDays days,
+// together with lots that have already expired and should be written off.
+func (s *pharmacyService) NearExpiryReport(ctx context.Context, withinDays int, locationID *uuid.UUID) ([]model.StockLevel, error) {
+	ctx, span := tracing.Start(ctx, "PharmacyService.NearExpiryReport")
+	defer span.End()
+	today := time.Now()
+	rows, err := s.pharmacyRepo.ListNearExpiryStock(ctx, db.ListNearExpiryStockParams{
+		Cutoff:     pgtype.Date{Time: today.AddDate(0, 0, withinDays), Valid: true},
+		LocationID: optionalUUID(locationID),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list near-expiry stock", "error", err)
+		return nil, fmt.Errorf("failed to list near-expiry stock: %w", err)
+	}
+	levels := make([]model.StockLevel, 0, len(rows))
+	for _, r := range rows {
+		level := mapper.MapStockLevel(db.ListStockLevelsRow(r))
+		level.Expired = pharmacy.IsExpired(level.ExpiryDate, today)
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/audit"
+	"github.com/himanshu-holmes/hms/internal/db"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/pseudonym"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrPseudonymizationRequestNotFound = errors.New("pseudonymization request not found")
+var ErrPseudonymizationPending = errors.New("a pseudonymization request for this patient is already waiting for approval")
+var ErrPseudonymizationDecided = errors.New("pseudonymization request has already been decided")
+var ErrPseudonymizationSelfApproval = errors.New("a pseudonymization request must be decided by someone other than its requester")
+var ErrPatientPseudonymized = errors.New("patient has been pseudonymized")
+
+// exportsCancelledReason is recorded on exports of a patient who is pseudonymized.
+const exportsCancelledReason = "cancelled: the patient was pseudonymized"
+
+type pseudonymizationService struct {
+	repo        repository.PseudonymizationRepository
+	tx          repository.TxManager
+	patientRepo repository.PatientRepository
+	auditor     AuditRecorder
+	logger      *slog.Logger
+}
+
+func NewPseudonymizationService(repo repository.PseudonymizationRepository, tx repository.TxManager, patientRepo repository.PatientRepository, auditor AuditRecorder, logger *slog.Logger) PseudonymizationService {
+	return &pseudonymizationService{repo: repo, tx: tx, patientRepo: patientRepo, auditor: auditor, logger: logger.With("service", "PseudonymizationService")}
+}
+
+// Preview is a dry run of pseudonymizing the patient. Nothing is changed.
+func (s *pseudonymizationService) Preview(ctx context.Context, patientID uuid.UUID) (*model.PseudonymizationPreview, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.Preview")
+	defer span.End()
+	patient, err := s.getPatient(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if patient.PseudonymizedAt != nil {
+		return nil, ErrPatientPseudonymized
+	}
+	preview := pseudonym.Preview(*patient)
+	// Reads fail closed: the preview shows the patient's identifiers.
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionRead,
+		ResourceType: model.AuditResourcePatient,
+		ResourceID:   &patientID,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// RequestPseudonymization records an erasure request for an admin to approve.
+func (s *pseudonymizationService) RequestPseudonymization(ctx context.Context, patientID uuid.UUID, req model.PseudonymizationCreateRequest, requestedByUserID uuid.UUID) (*model.PseudonymizationRequest, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.RequestPseudonymization")
+	defer span.End()
+	patient, err := s.getPatient(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	if patient.PseudonymizedAt != nil {
+		return nil, ErrPatientPseudonymized
+	}
+	var request model.PseudonymizationRequest
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		created, err := r.Pseudonymization.CreatePseudonymizationRequest(ctx, db.CreatePseudonymizationRequestParams{
+			PatientID:         pgtype.UUID{Bytes: patientID, Valid: true},
+			RequestedByUserID: pgtype.UUID{Bytes: requestedByUserID, Valid: true},
+			Reason:            req.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		request = mapper.MapPseudonymizationRequest(created)
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourcePseudonymizationRequest,
+			ResourceID:   &request.ID,
+			PatientID:    &patientID,
+			Changes:      auditDiff(ctx, s.logger, nil, request),
+		})
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPseudonymizationPending
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to create request", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to create pseudonymization request: %w", err)
+	}
+	s.logger.InfoContext(ctx, "Pseudonymization requested", "request_id", request.ID, "patient_id", patientID, "requested_by_user_id", requestedByUserID)
+	return &request, nil
+}
+
+func (s *pseudonymizationService) GetRequest(ctx context.Context, requestID uuid.UUID) (*model.PseudonymizationRequest, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.GetRequest")
+	defer span.End()
+	r, err := s.repo.GetPseudonymizationRequest(ctx, pgtype.UUID{Bytes: requestID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPseudonymizationRequestNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get request", "pseudonymization_request_id", requestID, "error", err)
+		return nil, fmt.Errorf("failed to get pseudonymization request: %w", err)
+	}
+	request := mapper.MapPseudonymizationRequest(r)
+	return &request, nil
+}
+
+func (s *pseudonymizationService) ListRequests(ctx context.Context, status *model.PseudonymizationStatus, params model.PaginationParams) ([]model.PseudonymizationRequest, int64, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.ListRequests")
+	defer span.End()
+	var filter db.NullPseudonymizationStatus
+	if status != nil {
+		filter = db.NullPseudonymizationStatus{PseudonymizationStatus: db.PseudonymizationStatus(*status), Valid: true}
+	}
+	rows, err := s.repo.ListPseudonymizationRequests(ctx, db.ListPseudonymizationRequestsParams{
+		Status:    filter,
+		RowLimit:  int32(params.Limit),
+		RowOffset: int32(params.Offset),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list requests", "error", err)
+		return nil, 0, fmt.Errorf("failed to list pseudonymization requests: %w", err)
+	}
+	total, err := s.repo.CountPseudonymizationRequests(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to count requests", "error", err)
+		return nil, 0, fmt.Errorf("failed to count pseudonymization requests: %w", err)
+	}
+	requests := make([]model.PseudonymizationRequest, 0, len(rows))
+	for _, r := range rows {
+		requests = append(requests, mapper.MapPseudonymizationRequest(r))
+	}
+	return requests, total, nil
+}
+
+// ApproveRequest pseudonymizes the patient and marks the request approved in one
+// transaction. The patient's exports are cancelled and their archives deleted, since they
+// hold the identifiers being erased.
+func (s *pseudonymizationService) ApproveRequest(ctx context.Context, requestID uuid.UUID, req model.PseudonymizationDecisionRequest, approvedByUserID uuid.UUID) (*model.PseudonymizationRequest, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.ApproveRequest")
+	defer span.End()
+	var request model.PseudonymizationRequest
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		before, err := s.lockPending(ctx, r.Pseudonymization, requestID, approvedByUserID)
+		if err != nil {
+			return err
+		}
+		patient, err := r.Pseudonymization.LockPatient(ctx, before.PatientID)
+		if err != nil {
+			return fmt.Errorf("lock patient: %w", err)
+		}
+		if patient.PseudonymizedAt.Valid {
+			return ErrPatientPseudonymized
+		}
+
+		token, err := pseudonym.NewToken()
+		if err != nil {
+			return err
+		}
+		ids := pseudonym.Replace(patient.DateOfBirth.Time, token)
+		if _, err := r.Pseudonymization.PseudonymizePatient(ctx, db.PseudonymizePatientParams{
+			ID:          patient.ID,
+			FirstName:   ids.FirstName,
+			LastName:    ids.LastName,
+			DateOfBirth: pgtype.Date{Time: ids.DateOfBirth, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("pseudonymize patient: %w", err)
+		}
+		if _, err := r.Pseudonymization.PseudonymizePatientPolicies(ctx, db.PseudonymizePatientPoliciesParams{PatientID: patient.ID, MemberID: token}); err != nil {
+			return fmt.Errorf("pseudonymize insurance policies: %w", err)
+		}
+		if _, err := r.Pseudonymization.CancelPatientExports(ctx, db.CancelPatientExportsParams{PatientID: patient.ID, Reason: exportsCancelledReason}); err != nil {
+			return fmt.Errorf("cancel exports: %w", err)
+		}
+		if _, err := r.Pseudonymization.DeletePatientExportArchives(ctx, patient.ID); err != nil {
+			return fmt.Errorf("delete export archives: %w", err)
+		}
+
+		after, err := r.Pseudonymization.DecidePseudonymizationRequest(ctx, db.DecidePseudonymizationRequestParams{
+			ID:              before.ID,
+			Status:          db.PseudonymizationStatusApproved,
+			DecidedByUserID: pgtype.UUID{Bytes: approvedByUserID, Valid: true},
+			DecisionNote:    textParam(req.Note),
+		})
+		if err != nil {
+			return err
+		}
+
+		patientID := uuid.UUID(after.PatientID.Bytes)
+		changes := make(map[string]model.FieldChange)
+		for _, field := range pseudonym.ChangedFields() {
+			// The audit trail cannot be erased, so it must not keep the old identifiers.
+			changes[field] = model.FieldChange{Old: audit.Redacted, New: audit.Redacted}
+		}
+		if err := s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionPseudonymize,
+			ResourceType: model.AuditResourcePatient,
+			ResourceID:   &patientID,
+			PatientID:    &patientID,
+			Changes:      changes,
+		}); err != nil {
+			return err
+		}
+		request, err = s.auditDecision(ctx, r, before, after)
+		return err
+	})
+	if err != nil {
+		if isPseudonymizationError(err) || errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to approve request", "pseudonymization_request_id", requestID, "error", err)
+		return nil, fmt.Errorf("failed to approve pseudonymization request: %w", err)
+	}
+	s.logger.InfoContext(ctx, "Patient pseudonymized", "patient_id", request.PatientID, "request_id", request.ID, "approved_by_user_id", approvedByUserID)
+	return &request, nil
+}
+
+// RejectRequest closes a request without changing the patient.
+func (s *pseudonymizationService) RejectRequest(ctx context.Context, requestID uuid.UUID, req model.PseudonymizationDecisionRequest, rejectedByUserID uuid.UUID) (*model.PseudonymizationRequest, error) {
+	ctx, span := tracing.Start(ctx, "PseudonymizationService.RejectRequest")
+	defer span.End()
+	var request model.PseudonymizationRequest
+	err := s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		before, err := s.lockPending(ctx, r.Pseudonymization, requestID, rejectedByUserID)
+		if err != nil {
+			return err
+		}
+		after, err := r.Pseudonymization.DecidePseudonymizationRequest(ctx, db.DecidePseudonymizationRequestParams{
+			ID:              before.ID,
+			Status:          db.PseudonymizationStatusRejected,
+			DecidedByUserID: pgtype.UUID{Bytes: rejectedByUserID, Valid: true},
+			DecisionNote:    textParam(req.Note),
+		})
+		if err != nil {
+			return err
+		}
+		request, err = s.auditDecision(ctx, r, before, after)
+		return err
+	})
+	if err != nil {
+		if isPseudonymizationError(err) || errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to reject request", "pseudonymization_request_id", requestID, "error", err)
+		return nil, fmt.Errorf("failed to reject pseudonymization request: %w", err)
+	}
+	return &request, nil
+}
+
+// lockPending locks a request that deciderID may decide.
+func (s *pseudonymizationService) lockPending(ctx context.Context, repo repository.PseudonymizationRepository, requestID, deciderID uuid.UUID) (db.PseudonymizationRequest, error) {
+	r, err := repo.LockPseudonymizationRequest(ctx, pgtype.UUID{Bytes: requestID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r, ErrPseudonymizationRequestNotFound
+		}
+		return r, fmt.Errorf("lock request: %w", err)
+	}
+	if r.Status != db.PseudonymizationStatusPending {
+		return r, ErrPseudonymizationDecided
+	}
+	if uuid.UUID(r.RequestedByUserID.Bytes) == deciderID {
+		return r, ErrPseudonymizationSelfApproval
+	}
+	return r, nil
+}
+
+// auditDecision records the decision in the transaction r is bound to and returns the
+// decided request.
+func (s *pseudonymizationService) auditDecision(ctx context.Context, r repository.Repos, before, after db.PseudonymizationRequest) (model.PseudonymizationRequest, error) {
+	b, a := mapper.MapPseudonymizationRequest(before), mapper.MapPseudonymizationRequest(after)
+	return a, s.auditor.RecordTx(ctx, r, model.AuditEntry{
+		Action:       model.AuditActionUpdate,
+		ResourceType: model.AuditResourcePseudonymizationRequest,
+		ResourceID:   &a.ID,
+		PatientID:    &a.PatientID,
+		Changes:      auditDiff(ctx, s.logger, b, a),
+	})
+}
+
+func (s *pseudonymizationService) getPatient(ctx context.Context, patientID uuid.UUID) (*model.Patient, error) {
+	p, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: patientID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get patient", "patient_id", patientID, "error", err)
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+	patient := mapper.ConvertDBPatientToModel(&p)
+	return &patient, nil
+}
+
+func isPseudonymizationError(err error) bool {
+	return errors.Is(err, ErrPseudonymizationRequestNotFound) ||
+		errors.Is(err, ErrPseudonymizationDecided) ||
+		errors.Is(err, ErrPseudonymizationSelfApproval) ||
+		errors.Is(err, ErrPatientPseudonymized)
+}
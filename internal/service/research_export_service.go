@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/research"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrResearchExportDisabled = errors.New("research exports are not configured")
+
+// researchPageSize is how many patients are read, with their visits, at a time.
+const researchPageSize = 500
+
+type researchExportService struct {
+	researchRepo repository.ResearchRepository
+	ids          *research.Pseudonymizer
+	auditService AuditService
+	logger       *slog.Logger
+}
+
+// NewResearchExportService returns the research export service. ids may be nil when no
+// research key is configured, in which case every export fails with
+// ErrResearchExportDisabled.
+func NewResearchExportService(researchRepo repository.ResearchRepository, ids *research.Pseudonymizer, auditService AuditService, logger *slog.Logger) ResearchExportService {
+	return &researchExportService{researchRepo: researchRepo, ids: ids, auditService: auditService, logger: logger.With("service", "ResearchExportService")}
+}
+
+// Export de-identifies every patient with research consent in force, and their visits.
+// The export is recorded in the audit trail before anything is returned.
+func (s *researchExportService) Export(ctx context.Context, opts research.Options, now time.Time) (*research.Dataset, error) {
+	ctx, span := tracing.Start(ctx, "ResearchExportService.Export")
+	defer span.End()
+	if s.ids == nil {
+		return nil, ErrResearchExportDisabled
+	}
+	deid, err := research.NewDeidentifier(opts, s.ids, now)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &research.Dataset{
+		GeneratedAt: now.UTC(),
+		Options:     opts,
+		Patients:    []research.PatientRow{},
+		Visits:      []research.VisitRow{},
+	}
+	var after pgtype.UUID
+	after.Valid = true
+	for {
+		page, err := s.researchRepo.ListResearchPatients(ctx, after, researchPageSize)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to list patients", "error", err)
+			return nil, fmt.Errorf("failed to list patients: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		patients := make(map[uuid.UUID]model.Patient, len(page))
+		ids := make([]pgtype.UUID, len(page))
+		for i := range page {
+			p := mapper.ConvertDBPatientToModel(&page[i])
+			patients[p.ID] = p
+			ids[i] = page[i].ID
+			dataset.Patients = append(dataset.Patients, deid.Patient(p))
+		}
+		visits, err := s.researchRepo.ListVisitsForPatients(ctx, ids)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to list visits", "error", err)
+			return nil, fmt.Errorf("failed to list visits: %w", err)
+		}
+		for i := range visits {
+			v, err := mapper.MapPatientVisit(&visits[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to map visit: %w", err)
+			}
+			dataset.Visits = append(dataset.Visits, deid.Visit(patients[v.PatientID], *v))
+		}
+
+		after = page[len(page)-1].ID
+		if len(page) < researchPageSize {
+			break
+		}
+	}
+	dataset.KAnonymity = research.CheckKAnonymity(dataset.Patients, opts.K)
+
+	// The dataset is derived from every consenting patient's record, so it is not handed
+	// out unaudited.
+	if err := s.auditService.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionExport,
+		ResourceType: model.AuditResourceResearchDataset,
+		Changes: map[string]model.FieldChange{
+			"dates":     {New: opts.Dates},
+			"geography": {New: opts.Geography},
+			"free_text": {New: opts.FreeText},
+			"patients":  {New: len(dataset.Patients)},
+			"visits":    {New: len(dataset.Visits)},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
@@ -2,22 +2,32 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/himanshu-holmes/hms/internal/model"
-	
+	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/research"
 )
 
 type AuthService interface {
 	Login(ctx context.Context, req model.LoginRequest) (*model.LoginResponse, error)
 	CreateUser(ctx context.Context, req model.UserCreateRequest) (*model.User, error)
+	// DeactivateUser stops the active user with this username from logging in.
+	DeactivateUser(ctx context.Context, username string) (*model.User, error)
+	// ResetPassword replaces the password of the active user with this username.
+	ResetPassword(ctx context.Context, username, password string) (*model.User, error)
 }
 
 type PatientService interface {
 	RegisterPatient(ctx context.Context, req model.ParsedPatientRequest, registeredByUserID uuid.UUID) (*model.Patient, error)
 	GetPatientDetails(ctx context.Context, patientID uuid.UUID) (*model.Patient, error)
+	// FindPatientByContact looks a patient up by exact phone number or email address.
+	FindPatientByContact(ctx context.Context, phone, email string) (*model.Patient, error)
 	ListPatients(ctx context.Context, params model.PaginationParams) ([]model.Patient, int64, error)
-	UpdatePatientDetails(context.Context, uuid.UUID, model.ParsedPatientRequest, model.UserRole, uuid.UUID) (*model.Patient, error)
+	// UpdatePatientDetails applies the update if the patient is still at version ifMatch,
+	// or to whatever version is current when ifMatch is nil.
+	UpdatePatientDetails(ctx context.Context, patientID uuid.UUID, req model.ParsedPatientRequest, updaterRole model.UserRole, updaterID uuid.UUID, ifMatch *int32) (*model.Patient, error)
 	DeletePatientRecord(ctx context.Context, patientID uuid.UUID, deletedByUserID uuid.UUID) error
 }
 
@@ -25,5 +35,202 @@ type PatientVisitService interface {
 	RecordPatientVisit(ctx context.Context, req model.ParsedPatientVisitRequest) (*model.PatientVisit, error)
 	GetPatientVisitDetails(ctx context.Context, visitID uuid.UUID) (*model.PatientVisit, error)
 	ListPatientVisits(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.PatientVisit, int64, error)
-	UpdatePatientVisit(ctx context.Context, visitID uuid.UUID, req model.ParsedPatientVisitRequest) (*model.PatientVisit, error) // DoctorID is in ParsedPatientVisitRequest
+	UpdatePatientVisit(ctx context.Context, visitID uuid.UUID, req model.ParsedPatientVisitRequest, ifMatch *int32) (*model.PatientVisit, error) // DoctorID is in ParsedPatientVisitRequest
+	FinalizeVisit(ctx context.Context, visitID, doctorID uuid.UUID, ifMatch *int32) (*model.PatientVisit, error)
+	GetVisitHistory(ctx context.Context, visitID uuid.UUID) (*model.VisitHistory, error)
+}
+
+type LabService interface {
+	CreateLabTest(ctx context.Context, req model.LabTestCreateRequest) (*model.LabTest, error)
+	ListLabTests(ctx context.Context, params model.PaginationParams) ([]model.LabTest, int64, error)
+	OrderLabTest(ctx context.Context, req model.LabOrderCreateRequest, orderedByUserID uuid.UUID) (*model.LabOrder, error)
+	GetLabOrder(ctx context.Context, orderID uuid.UUID) (*model.LabOrder, error)
+	ListVisitLabOrders(ctx context.Context, visitID uuid.UUID) ([]model.LabOrder, error)
+	ListLabWorklist(ctx context.Context, status model.LabOrderStatus, params model.PaginationParams) ([]model.LabOrder, error)
+	MarkSpecimenCollected(ctx context.Context, orderID uuid.UUID, collectedByUserID uuid.UUID) (*model.LabOrder, error)
+	EnterLabResults(ctx context.Context, orderID uuid.UUID, req model.LabResultEntryRequest, enteredByUserID uuid.UUID) (*model.LabOrder, error)
+	VerifyLabResults(ctx context.Context, orderID uuid.UUID, verifiedByUserID uuid.UUID) (*model.LabOrder, error)
+	GetCumulativeLabResults(ctx context.Context, patientID uuid.UUID, analyteCode *string, params model.PaginationParams) ([]model.CumulativeLabResult, error)
+}
+
+// ObservationEvaluator checks a numeric observation recorded on a visit against the
+// critical-value rules and raises an alert for the visit's doctor when one is breached.
+type ObservationEvaluator interface {
+	EvaluateObservation(ctx context.Context, visitID uuid.UUID, source model.ObservationSource, code string, value float64) (*model.Alert, error)
+}
+
+type AlertService interface {
+	ObservationEvaluator
+	RecordObservation(ctx context.Context, visitID uuid.UUID, req model.ObservationCreateRequest, recordedByUserID uuid.UUID) (*model.Observation, error)
+	CreateAlertRule(ctx context.Context, req model.AlertRuleCreateRequest, createdByUserID uuid.UUID) (*model.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]model.AlertRule, error)
+	DeactivateAlertRule(ctx context.Context, ruleID uuid.UUID) (*model.AlertRule, error)
+	ListAlerts(ctx context.Context, doctorID uuid.UUID, status *model.AlertStatus, params model.PaginationParams) ([]model.Alert, int64, error)
+	AcknowledgeAlert(ctx context.Context, alertID uuid.UUID, userID uuid.UUID) (*model.Alert, error)
+	EscalateDueAlerts(ctx context.Context, now time.Time) (int, error)
+}
+
+// ChargeCapturer records billable charges raised by clinical events such as visits and lab orders.
+type ChargeCapturer interface {
+	CaptureCharge(ctx context.Context, capture model.ChargeCapture) (*model.Charge, error)
+	// CaptureChargeTx records the charge with r, so it commits or rolls back with the event
+	// that raised it.
+	CaptureChargeTx(ctx context.Context, r repository.Repos, capture model.ChargeCapture) (*model.Charge, error)
+}
+
+type BillingService interface {
+	ChargeCapturer
+	CreateTaxCategory(ctx context.Context, req model.TaxCategoryCreateRequest) (*model.TaxCategory, error)
+	ListTaxCategories(ctx context.Context) ([]model.TaxCategory, error)
+	CreateChargeItem(ctx context.Context, req model.ChargeItemCreateRequest) (*model.ChargeItem, error)
+	ListChargeItems(ctx context.Context, params model.PaginationParams) ([]model.ChargeItem, int64, error)
+	PostCharge(ctx context.Context, req model.ChargeCreateRequest, postedByUserID uuid.UUID) (*model.Charge, error)
+	ListPatientCharges(ctx context.Context, patientID uuid.UUID, unbilledOnly bool, params model.PaginationParams) ([]model.Charge, int64, error)
+	CreateInvoice(ctx context.Context, req model.InvoiceCreateRequest, issuedByUserID uuid.UUID) (*model.Invoice, error)
+	GetInvoice(ctx context.Context, invoiceID uuid.UUID) (*model.Invoice, error)
+	ListPatientInvoices(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.Invoice, int64, error)
+	RecordPayment(ctx context.Context, invoiceID uuid.UUID, req model.PaymentCreateRequest, receivedByUserID uuid.UUID) (*model.Invoice, error)
+	RefundPayment(ctx context.Context, paymentID uuid.UUID, req model.RefundCreateRequest, refundedByUserID uuid.UUID) (*model.Invoice, error)
+	GetPatientBalance(ctx context.Context, patientID uuid.UUID) (*model.PatientBalance, error)
+}
+
+type InsuranceService interface {
+	AddPolicy(ctx context.Context, patientID uuid.UUID, req model.InsurancePolicyCreateRequest, createdByUserID uuid.UUID) (*model.InsurancePolicy, error)
+	ListPatientPolicies(ctx context.Context, patientID uuid.UUID) ([]model.InsurancePolicy, error)
+	DeactivatePolicy(ctx context.Context, policyID uuid.UUID) (*model.InsurancePolicy, error)
+	VerifyEligibility(ctx context.Context, policyID uuid.UUID, dateOfService time.Time, checkedByUserID uuid.UUID) (*model.EligibilityCheck, error)
+	CreateClaim(ctx context.Context, req model.InsuranceClaimCreateRequest, submittedByUserID uuid.UUID) (*model.InsuranceClaim, error)
+	// SendClaim sends a claim the payer did not acknowledge when it was created again.
+	SendClaim(ctx context.Context, claimID uuid.UUID) (*model.InsuranceClaim, error)
+	GetClaim(ctx context.Context, claimID uuid.UUID) (*model.InsuranceClaim, error)
+	ListInvoiceClaims(ctx context.Context, invoiceID uuid.UUID) ([]model.InsuranceClaim, error)
+	ListClaimsByStatus(ctx context.Context, status model.ClaimStatus, params model.PaginationParams) ([]model.InsuranceClaim, int64, error)
+	UpdateClaimStatus(ctx context.Context, claimID uuid.UUID, req model.ClaimStatusUpdateRequest, changedByUserID uuid.UUID) (*model.InsuranceClaim, error)
+}
+
+type PharmacyService interface {
+	CreateLocation(ctx context.Context, req model.PharmacyLocationCreateRequest) (*model.PharmacyLocation, error)
+	ListLocations(ctx context.Context) ([]model.PharmacyLocation, error)
+	CreateItem(ctx context.Context, req model.PharmacyItemCreateRequest) (*model.PharmacyItem, error)
+	ListItems(ctx context.Context, params model.PaginationParams) ([]model.PharmacyItem, int64, error)
+	ReceiveStock(ctx context.Context, req model.GoodsReceiptRequest, receivedByUserID uuid.UUID) ([]model.StockMovement, error)
+	DispenseForVisit(ctx context.Context, visitID uuid.UUID, req model.DispenseRequest, dispensedByUserID uuid.UUID) ([]model.StockMovement, error)
+	ReturnDispensed(ctx context.Context, movementID uuid.UUID, req model.StockReturnRequest, returnedByUserID uuid.UUID) (*model.StockMovement, error)
+	AdjustStock(ctx context.Context, req model.StockAdjustmentRequest, adjustedByUserID uuid.UUID) (*model.StockMovement, error)
+	ListStockLevels(ctx context.Context, locationID, itemID *uuid.UUID) ([]model.StockLevel, error)
+	ListMovements(ctx context.Context, filter model.StockMovementFilter, params model.PaginationParams) ([]model.StockMovement, int64, error)
+	ListVisitDispensations(ctx context.Context, visitID uuid.UUID) ([]model.StockMovement, error)
+	ListReorderAlerts(ctx context.Context, locationID *uuid.UUID) ([]model.ReorderAlert, error)
+	NearExpiryReport(ctx context.Context, withinDays int, locationID *uuid.UUID) ([]model.StockLevel, error)
+}
+
+// AuditRecorder appends an event to the audit trail. Errors wrap ErrAuditUnavailable.
+type AuditRecorder interface {
+	Record(ctx context.Context, entry model.AuditEntry) error
+	// RecordTx appends the event in the transaction r is bound to, so it commits or rolls
+	// back with the write it records. Call it last in the unit of work: the chain stays
+	// locked until the transaction ends.
+	RecordTx(ctx context.Context, r repository.Repos, entry model.AuditEntry) error
+}
+
+type AuditService interface {
+	AuditRecorder
+	ListEvents(ctx context.Context, filter model.AuditEventFilter, params model.PaginationParams) ([]model.AuditEvent, int64, error)
+	VerifyChain(ctx context.Context) (*model.AuditChainVerification, error)
+	ListPatientAccessLog(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.AccessLogEntry, int64, error)
+	// ExportPatientAccessLog passes the whole access log to write, newest first, pageSize
+	// entries at a time. write is called at least once, with an empty page when there is
+	// nothing to export.
+	ExportPatientAccessLog(ctx context.Context, patientID uuid.UUID, pageSize int, write func([]model.AccessLogEntry) error) error
+}
+
+// PatientAccessLevel is how much of a patient's record the caller may see.
+type PatientAccessLevel int
+
+const (
+	// PatientAccessDemographics hides the medical history and the clinical fields of visits.
+	PatientAccessDemographics PatientAccessLevel = iota + 1
+	// PatientAccessClinical is the whole record.
+	PatientAccessClinical
+)
+
+// PatientAccessAuthorizer decides how much of a patient's record the user in ctx may see.
+type PatientAccessAuthorizer interface {
+	// AuthorizePatientAccess returns ErrPatientNotFound when the patient is outside the
+	// user's scope. The returned context carries any break-glass grant the access relies
+	// on, so the audit events recorded with it are flagged.
+	AuthorizePatientAccess(ctx context.Context, patientID uuid.UUID) (context.Context, PatientAccessLevel, error)
+	// PatientListScope returns whose patients the user may list (nil for all patients)
+	// and how much of each record they may see.
+	PatientListScope(ctx context.Context) (careOf *uuid.UUID, level PatientAccessLevel)
+}
+
+type BreakGlassService interface {
+	ActiveGrant(ctx context.Context, userID, patientID uuid.UUID) (*uuid.UUID, error)
+	RequestAccess(ctx context.Context, patientID uuid.UUID, req model.BreakGlassRequest, userID uuid.UUID) (*model.BreakGlassGrant, error)
+	GetReport(ctx context.Context, date time.Time) (*model.BreakGlassReport, error)
+	ListReports(ctx context.Context, params model.PaginationParams) ([]model.BreakGlassReportSummary, int64, error)
+	// GenerateDueReports stores the review of the last complete day if it is missing.
+	GenerateDueReports(ctx context.Context, now time.Time) error
+}
+
+type CareTeamService interface {
+	PatientAccessAuthorizer
+	AddMember(ctx context.Context, patientID uuid.UUID, req model.CareTeamMemberRequest, assignedByUserID uuid.UUID) (*model.CareTeamMember, error)
+	RemoveMember(ctx context.Context, patientID, userID uuid.UUID) error
+	ListMembers(ctx context.Context, patientID uuid.UUID) ([]model.CareTeamMember, error)
+}
+
+// FieldEncryptionService maintains the encrypted columns, e.g. after a master key rotation.
+type FieldEncryptionService interface {
+	Reencrypt(ctx context.Context) (*model.ReencryptionResult, error)
+}
+
+// ConsentChecker is called before acting on a patient's behalf outside the hospital,
+// such as exporting their data or contacting them.
+type ConsentChecker interface {
+	// RequireConsent returns an error matching consent.ErrConsentRequired unless the
+	// patient's consent of type t is in force.
+	RequireConsent(ctx context.Context, patientID uuid.UUID, t model.ConsentType) error
+}
+
+type ConsentService interface {
+	ConsentChecker
+	ListConsentTypes() []model.ConsentTypeInfo
+	RecordConsent(ctx context.Context, patientID uuid.UUID, req model.ConsentCreateRequest, witnessUserID uuid.UUID) (*model.ConsentRecord, error)
+	ListConsents(ctx context.Context, patientID uuid.UUID) ([]model.ConsentRecord, error)
+	WithdrawConsent(ctx context.Context, patientID, consentID uuid.UUID, req model.ConsentWithdrawRequest, withdrawnByUserID uuid.UUID) (*model.ConsentRecord, error)
+}
+
+// NotificationService sends messages to patients, subject to their consent.
+type NotificationService interface {
+	SendSMS(ctx context.Context, patientID uuid.UUID, req model.SMSNotificationRequest) (*model.NotificationReceipt, error)
+}
+
+// PatientExportService answers subject access requests with an archive of everything held
+// about a patient, built in the background and downloadable until it expires.
+type PatientExportService interface {
+	RequestExport(ctx context.Context, patientID uuid.UUID, requestedByUserID uuid.UUID) (*model.PatientExport, error)
+	ListExports(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.PatientExport, int64, error)
+	GetExport(ctx context.Context, patientID, exportID uuid.UUID) (*model.PatientExport, error)
+	DownloadExport(ctx context.Context, patientID, exportID uuid.UUID) (*model.PatientExport, []byte, error)
+	ProcessPendingExports(ctx context.Context, now time.Time) error
+	ExpireExports(ctx context.Context, now time.Time) error
+}
+
+// PseudonymizationService carries out erasure requests by replacing a patient's direct
+// identifiers, once an admin other than the requester approves.
+type PseudonymizationService interface {
+	Preview(ctx context.Context, patientID uuid.UUID) (*model.PseudonymizationPreview, error)
+	RequestPseudonymization(ctx context.Context, patientID uuid.UUID, req model.PseudonymizationCreateRequest, requestedByUserID uuid.UUID) (*model.PseudonymizationRequest, error)
+	GetRequest(ctx context.Context, requestID uuid.UUID) (*model.PseudonymizationRequest, error)
+	ListRequests(ctx context.Context, status *model.PseudonymizationStatus, params model.PaginationParams) ([]model.PseudonymizationRequest, int64, error)
+	ApproveRequest(ctx context.Context, requestID uuid.UUID, req model.PseudonymizationDecisionRequest, approvedByUserID uuid.UUID) (*model.PseudonymizationRequest, error)
+	RejectRequest(ctx context.Context, requestID uuid.UUID, req model.PseudonymizationDecisionRequest, rejectedByUserID uuid.UUID) (*model.PseudonymizationRequest, error)
+}
+
+// ResearchExportService builds de-identified datasets of consenting patients and their
+// visits for research.
+type ResearchExportService interface {
+	Export(ctx context.Context, opts research.Options, now time.Time) (*research.Dataset, error)
 }
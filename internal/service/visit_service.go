@@ -6,7 +6,7 @@ import (
 	"fmt"
 
 	// "go/format"
-	"log"
+	"log/slog"
 	"strings"
 
 	// "time"
@@ -14,8 +14,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/himanshu-holmes/hms/internal/db"
 	"github.com/himanshu-holmes/hms/internal/mapper"
+	"github.com/himanshu-holmes/hms/internal/metrics"
 	"github.com/himanshu-holmes/hms/internal/model"
 	"github.com/himanshu-holmes/hms/internal/repository"
+	"github.com/himanshu-holmes/hms/internal/tracing"
+	"github.com/himanshu-holmes/hms/internal/visithistory"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -23,14 +26,24 @@ import (
 var ErrVisitNotFound = errors.New("patient visit not found")
 var ErrVisitUpdateForbidden = errors.New("not authorized to update this visit record")
 var ErrPatientForVisitNotFound = errors.New("patient for visit not found")
+var ErrVisitVersionConflict = errors.New("patient visit was changed since it was read")
+var ErrVisitAmendmentReasonRequired = errors.New("amendment_reason is required to change a finalized visit")
+var ErrVisitAlreadyFinalized = errors.New("patient visit is already finalized")
+var ErrVisitRecordForbidden = errors.New("only a doctor in the patient's care can record a visit")
 
 type patientVisitService struct {
 	visitRepo   repository.PatientVisitQuerier
+	revisions   repository.VisitRevisionRepository
+	tx          repository.TxManager // Changes visits together with the checks and revisions they depend on
 	patientRepo repository.PatientRepository // To check if patient exists
+	charges     ChargeCapturer               // Bills the consultation for each recorded visit
+	auditor     AuditRecorder
+	access      PatientAccessAuthorizer
+	logger      *slog.Logger
 }
 
-func NewPatientVisitService(visitRepo repository.PatientVisitQuerier, patientRepo repository.PatientRepository) PatientVisitService {
-	return &patientVisitService{visitRepo: visitRepo, patientRepo: patientRepo}
+func NewPatientVisitService(visitRepo repository.PatientVisitQuerier, revisions repository.VisitRevisionRepository, tx repository.TxManager, patientRepo repository.PatientRepository, charges ChargeCapturer, auditor AuditRecorder, access PatientAccessAuthorizer, logger *slog.Logger) PatientVisitService {
+	return &patientVisitService{visitRepo: visitRepo, revisions: revisions, tx: tx, patientRepo: patientRepo, charges: charges, auditor: auditor, access: access, logger: logger.With("service", "PatientVisitService")}
 }
 
 func derefString(ptr *string) string {
@@ -40,16 +53,35 @@ func derefString(ptr *string) string {
 	return *ptr
 }
 
+func derefGender(ptr *model.Gender) model.Gender {
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
+
+// redactVisit removes the clinical fields a caller with demographics-only access may not see.
+func redactVisit(v *model.PatientVisit, level PatientAccessLevel) {
+	if level < PatientAccessClinical {
+		v.Symptoms, v.Diagnosis, v.Prescription, v.Notes = nil, nil, nil, nil
+	}
+}
+
 func (s *patientVisitService) RecordPatientVisit(ctx context.Context, req model.ParsedPatientVisitRequest) (*model.PatientVisit, error) {
-// Check if patient exists
-	_, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: [16]byte(req.PatientID), Valid: true})
+	ctx, span := tracing.Start(ctx, "PatientVisitService.RecordPatientVisit")
+	defer span.End()
+	// Recording a visit needs the patient in the doctor's care, or a break-glass grant; it
+	// does not itself widen the doctor's scope.
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, req.PatientID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows)  {
+		if errors.Is(err, ErrPatientNotFound) {
 			return nil, fmt.Errorf("%w: patient ID %s", ErrPatientForVisitNotFound, req.PatientID)
 		}
-		log.Printf("VisitService: Error checking patient %s for new visit: %v", req.PatientID, err)
+		return nil, err
+	}
+	if level < PatientAccessClinical {
+		return nil, ErrVisitRecordForbidden
 	}
- 
 	visitParams  := &db.CreatePatientVisitParams{
 		PatientID: pgtype.UUID{Bytes: [16]byte(req.PatientID), Valid: true},
 		DoctorID:  pgtype.UUID{Bytes: [16]byte(req.DoctorID), Valid: true},
@@ -60,47 +92,114 @@ func (s *patientVisitService) RecordPatientVisit(ctx context.Context, req model.
 		Notes: pgtype.Text{String: derefString(req.Notes), Valid: req.Notes != nil},
 
 	}
-	
 
-	visit, err := s.visitRepo.CreatePatientVisit(ctx, *visitParams)
+	// The patient is checked in the same transaction, so it cannot be deleted in between,
+	// and the visit is only created if its charge and audit event are recorded with it.
+	var visit db.PatientVisit
+	var formattedVisit *model.PatientVisit
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		if _, err := r.Patients.GetPatientByID(ctx, visitParams.PatientID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: patient ID %s", ErrPatientForVisitNotFound, req.PatientID)
+			}
+			s.logger.ErrorContext(ctx, "Error checking patient for new visit", "patient_id", req.PatientID, "error", err)
+			return err
+		}
+		var err error
+		if visit, err = r.Visits.CreatePatientVisit(ctx, *visitParams); err != nil {
+			return err
+		}
+		if formattedVisit, err = mapper.MapPatientVisit(&visit); err != nil {
+			return err
+		}
+		visitID := uuid.UUID(visit.ID.Bytes)
+		if err := captureCharge(ctx, s.logger, s.charges, r, model.ChargeCapture{
+			PatientID:       req.PatientID,
+			VisitID:         &visitID,
+			Source:          model.ChargeSourceVisit,
+			SourceID:        &visitID,
+			ChargeCode:      model.VisitChargeCode,
+			CreatedByUserID: req.DoctorID,
+		}); err != nil {
+			return err
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionCreate,
+			ResourceType: model.AuditResourceVisit,
+			ResourceID:   &visitID,
+			PatientID:    &req.PatientID,
+			Changes:      auditDiff(ctx, s.logger, nil, formattedVisit),
+		})
+	})
 	if err != nil {
+		if errors.Is(err, ErrPatientForVisitNotFound) || errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
 		// Handle potential foreign key constraint errors if patient_id or doctor_id is invalid at DB level
 		if strings.Contains(strings.ToLower(err.Error()), "foreign key constraint") {
-			log.Printf("VisitService: Foreign key constraint error creating visit for patient %s by doctor %s: %v", req.PatientID, req.DoctorID, err)
+			s.logger.ErrorContext(ctx, "Foreign key violation creating visit", "patient_id", req.PatientID, "doctor_id", req.DoctorID, "error", err)
 			return nil, fmt.Errorf("invalid patient or doctor reference for visit")
 		}
-		log.Printf("VisitService: Failed to record patient visit in repo: %v", err)
+		s.logger.ErrorContext(ctx, "Failed to record patient visit in repo", "error", err)
 		return nil, fmt.Errorf("failed to record patient visit: %w", err)
 	}
 	
 
-	formattedVisit,err := mapper.MapPatientVisit(&visit)
-	
+	metrics.VisitsRecorded.Inc()
 	return formattedVisit, nil
 }
 
 func (s *patientVisitService) GetPatientVisitDetails(ctx context.Context, visitID uuid.UUID) (*model.PatientVisit, error) {
+	ctx, span := tracing.Start(ctx, "PatientVisitService.GetPatientVisitDetails")
+	defer span.End()
 	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrVisitNotFound
 		}
-		log.Printf("VisitService: Failed to get visit details for ID %s: %v", visitID, err)
+		s.logger.ErrorContext(ctx, "Failed to get visit details", "visit_id", visitID, "error", err)
 		return nil, fmt.Errorf("failed to get visit details: %w", err)
 	}
 	formmatedVisit,err := mapper.MapPatientVisit(&visit)
+	// Reads fail closed: visit details are not returned unless the access was recorded.
+	patientID := uuid.UUID(visit.PatientID.Bytes)
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionRead,
+		ResourceType: model.AuditResourceVisit,
+		ResourceID:   &visitID,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, err
+	}
+	redactVisit(formmatedVisit, level)
 	return formmatedVisit, nil
 }
 
 func (s *patientVisitService) ListPatientVisits(ctx context.Context, patientID uuid.UUID, params model.PaginationParams) ([]model.PatientVisit, int64, error) {
+	ctx, span := tracing.Start(ctx, "PatientVisitService.ListPatientVisits")
+	defer span.End()
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, 0, fmt.Errorf("%w: patient ID %s", ErrPatientForVisitNotFound, patientID)
+		}
+		return nil, 0, err
+	}
 	// Optional: Check if patient exists first to return a 404 if patient_id is invalid
-	_, err := s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: [16]byte(patientID), Valid: true})
+	_, err = s.patientRepo.GetPatientByID(ctx, pgtype.UUID{Bytes: [16]byte(patientID), Valid: true})
 	if err != nil  {
 		if errors.Is(err, pgx.ErrNoRows)  {
-			log.Printf("VisitService: Attempted to list visits for non-existent patient ID %s", patientID)
+			s.logger.WarnContext(ctx, "Listing visits of non-existent patient", "patient_id", patientID)
 			return []model.PatientVisit{}, 0, fmt.Errorf("%w: patient ID %s", ErrPatientForVisitNotFound, patientID) // Return empty slice and 0 total
 		}
-		log.Printf("VisitService: Error checking patient %s before listing visits: %v", patientID, err)
+		s.logger.ErrorContext(ctx, "Error checking patient before listing visits", "patient_id", patientID, "error", err)
 		return nil, 0, fmt.Errorf("error verifying patient before listing visits: %w", err)
 	}
 
@@ -112,7 +211,7 @@ func (s *patientVisitService) ListPatientVisits(ctx context.Context, patientID u
 	}
 	visits, err := s.visitRepo.ListPatientVisitsByPatientID(ctx, dbParams)
 	if err != nil {
-		log.Printf("VisitService: Failed to list patient visits for patient %s: %v", patientID, err)
+		s.logger.ErrorContext(ctx, "Failed to list patient visits", "patient_id", patientID, "error", err)
 		return nil, 0, fmt.Errorf("failed to list patient visits: %w", err)
 	}
 
@@ -130,31 +229,60 @@ func (s *patientVisitService) ListPatientVisits(ctx context.Context, patientID u
 		}
 		mappedVisit, err := mapper.MapPatientVisit(patientVisit)
 		if err != nil {
-			log.Printf("VisitService: Failed to map visit for patient %s: %v", patientID, err)
+			s.logger.ErrorContext(ctx, "Failed to map visit", "patient_id", patientID, "error", err)
 			return nil, 0, fmt.Errorf("failed to map patient visits: %w", err)
 		}
+		redactVisit(mappedVisit, level)
 		mappedVisits[i] = *mappedVisit
 	}
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionList,
+		ResourceType: model.AuditResourceVisit,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, 0, err
+	}
 	return mappedVisits, int64(len(mappedVisits)), nil
 }
 
-func (s *patientVisitService) UpdatePatientVisit(ctx context.Context, visitID uuid.UUID, req model.ParsedPatientVisitRequest) (*model.PatientVisit, error) {
+// UpdatePatientVisit applies req to the visit if it is still at version ifMatch, or to
+// whatever version is current when ifMatch is nil. The version it replaces is kept as a
+// revision; once the visit is finalized the change is an addendum and needs a reason.
+func (s *patientVisitService) UpdatePatientVisit(ctx context.Context, visitID uuid.UUID, req model.ParsedPatientVisitRequest, ifMatch *int32) (*model.PatientVisit, error) {
+	ctx, span := tracing.Start(ctx, "PatientVisitService.UpdatePatientVisit")
+	defer span.End()
 	existingVisit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrVisitNotFound
 		}
-		log.Printf("VisitService: Failed to fetch visit %s for update: %v", visitID, err)
+		s.logger.ErrorContext(ctx, "Failed to fetch visit for update", "visit_id", visitID, "error", err)
 		return nil, fmt.Errorf("failed to fetch visit for update: %w", err)
 	}
 	
 
+	before, err := mapper.MapPatientVisit(&existingVisit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to map visit before update", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to map existing visit: %w", err)
+	}
+	ctx, _, err = s.access.AuthorizePatientAccess(ctx, existingVisit.PatientID.Bytes)
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+
 	// Authorization: Ensure the authenticated doctor (from req.DoctorID) is the one who created this visit.
 	// req.DoctorID is set by the handler/parser from the authenticated user's token.
 	if existingVisit.DoctorID.Bytes != req.DoctorID {
-		log.Printf("VisitService: Unauthorized attempt to update visit %s. Visit DoctorID: %s, Requester DoctorID: %s", visitID, existingVisit.DoctorID, req.DoctorID)
+		s.logger.WarnContext(ctx, "Unauthorized attempt to update visit", "visit_id", visitID, "visit_doctor_id", existingVisit.DoctorID, "doctor_id", req.DoctorID)
 		return nil, ErrVisitUpdateForbidden
 	}
+	if ifMatch != nil && *ifMatch != existingVisit.Version {
+		return nil, ErrVisitVersionConflict
+	}
 
 	changed := false
 	// Apply updates from req. ParsedPatientVisitRequest will have zero values for fields not in JSON.
@@ -181,15 +309,28 @@ func (s *patientVisitService) UpdatePatientVisit(ctx context.Context, visitID uu
 	// PatientID and DoctorID of the visit itself are not updatable.
 
 	if !changed {
-		log.Printf("VisitService: No changes detected for visit %s update. Returning existing.", visitID)
+		s.logger.InfoContext(ctx, "No changes to visit; returning existing", "visit_id", visitID)
+		if err := s.auditor.Record(ctx, model.AuditEntry{
+			Action:       model.AuditActionRead,
+			ResourceType: model.AuditResourceVisit,
+			ResourceID:   &visitID,
+			PatientID:    uuidPtrOf(existingVisit.PatientID),
+		}); err != nil {
+			return nil, err
+		}
 		mappedVisit, err := mapper.MapPatientVisit(&existingVisit)
 		if err != nil {
-			log.Printf("VisitService: Failed to map existing visit %s: %v", visitID, err)
+			s.logger.ErrorContext(ctx, "Failed to map existing visit", "visit_id", visitID, "error", err)
 			return nil, fmt.Errorf("failed to map existing visit: %w", err)
 		}
 		return mappedVisit, nil
 	}
 
+	kind, err := visithistory.KindFor(existingVisit.FinalizedAt.Valid, req.AmendmentReason)
+	if err != nil {
+		return nil, ErrVisitAmendmentReasonRequired
+	}
+
 	updateParams := db.UpdatePatientVisitParams{
 		ID:           existingVisit.ID,
 		VisitDate:    existingVisit.VisitDate,
@@ -197,18 +338,207 @@ func (s *patientVisitService) UpdatePatientVisit(ctx context.Context, visitID uu
 		Diagnosis:    existingVisit.Diagnosis,
 		Prescription: existingVisit.Prescription,
 		Notes:        existingVisit.Notes,
+
+		// Only applies if nobody else updated the visit since it was read above.
+		ExpectedVersion: existingVisit.Version,
 	}
-	patientVisit, err := s.visitRepo.UpdatePatientVisit(ctx, updateParams) // The repository should also check DoctorID for safety
+	var updatedVisit *model.PatientVisit
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		current, err := lockVisitAt(ctx, r.VisitRevisions, visitID, existingVisit.Version)
+		if err != nil {
+			return err
+		}
+		if _, err := r.VisitRevisions.CreatePatientVisitRevision(ctx, revisionOf(current, kind, req.AmendmentReason, req.DoctorID)); err != nil {
+			return err
+		}
+		patientVisit, err := r.VisitRevisions.UpdatePatientVisit(ctx, updateParams)
+		if err != nil {
+			return err
+		}
+		if updatedVisit, err = mapper.MapPatientVisit(&patientVisit); err != nil {
+			return err
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourceVisit,
+			ResourceID:   &visitID,
+			PatientID:    uuidPtrOf(patientVisit.PatientID),
+			Changes:      auditDiff(ctx, s.logger, before, updatedVisit),
+		})
+	})
 	if err != nil {
-		// The repo's UpdateVisit might return an error if rows affected is 0,
-		// which could mean not found or not authorized at the DB level (if DoctorID is in WHERE clause).
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "no rows updated") || errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrVisitNotFound // Or potentially ErrVisitUpdateForbidden if repo implies that
+		// No row matched the version read above: someone else updated or deleted the
+		// visit in between. The handler reloads it to tell the two apart.
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, ErrVisitVersionConflict) {
+			return nil, ErrVisitVersionConflict
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
 		}
-		log.Printf("VisitService: Failed to update patient visit %s in repo: %v", visitID, err)
+		s.logger.ErrorContext(ctx, "Failed to update patient visit in repo", "visit_id", visitID, "error", err)
 		return nil, fmt.Errorf("failed to update patient visit: %w", err)
 	}
 
-	updatedVisit, err := mapper.MapPatientVisit(&patientVisit)
 	return updatedVisit, nil
-}
\ No newline at end of file
+}
+
+// lockVisitAt locks the visit inside tx and checks it is still at version expected, so the
+// revision written next holds exactly the version being replaced.
+func lockVisitAt(ctx context.Context, tx repository.VisitRevisionRepository, visitID uuid.UUID, expected int32) (db.PatientVisit, error) {
+	current, err := tx.LockPatientVisit(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		return current, err
+	}
+	if current.Version != expected {
+		return current, ErrVisitVersionConflict
+	}
+	return current, nil
+}
+
+// revisionOf is the revision that keeps v as it was before a change of the given kind.
+func revisionOf(v db.PatientVisit, kind model.VisitRevisionKind, reason *string, authorID uuid.UUID) db.CreatePatientVisitRevisionParams {
+	return db.CreatePatientVisitRevisionParams{
+		VisitID:              v.ID,
+		Version:              v.Version + 1,
+		Kind:                 db.VisitRevisionKind(kind),
+		Reason:               pgtype.Text{String: derefString(reason), Valid: reason != nil},
+		AuthorUserID:         pgtype.UUID{Bytes: authorID, Valid: true},
+		PreviousVisitDate:    v.VisitDate,
+		PreviousSymptoms:     v.Symptoms,
+		PreviousDiagnosis:    v.Diagnosis,
+		PreviousPrescription: v.Prescription,
+		PreviousNotes:        v.Notes,
+	}
+}
+
+// FinalizeVisit signs the visit as its recording doctor. Later changes are addenda.
+func (s *patientVisitService) FinalizeVisit(ctx context.Context, visitID, doctorID uuid.UUID, ifMatch *int32) (*model.PatientVisit, error) {
+	ctx, span := tracing.Start(ctx, "PatientVisitService.FinalizeVisit")
+	defer span.End()
+	existingVisit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to fetch visit to finalize", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to fetch visit to finalize: %w", err)
+	}
+	before, err := mapper.MapPatientVisit(&existingVisit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map existing visit: %w", err)
+	}
+	ctx, _, err = s.access.AuthorizePatientAccess(ctx, existingVisit.PatientID.Bytes)
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+	if existingVisit.DoctorID.Bytes != doctorID {
+		return nil, ErrVisitUpdateForbidden
+	}
+	if existingVisit.FinalizedAt.Valid {
+		return nil, ErrVisitAlreadyFinalized
+	}
+	if ifMatch != nil && *ifMatch != existingVisit.Version {
+		return nil, ErrVisitVersionConflict
+	}
+
+	var after *model.PatientVisit
+	err = s.tx.WithinTx(ctx, func(r repository.Repos) error {
+		current, err := lockVisitAt(ctx, r.VisitRevisions, visitID, existingVisit.Version)
+		if err != nil {
+			return err
+		}
+		if _, err := r.VisitRevisions.CreatePatientVisitRevision(ctx, revisionOf(current, model.VisitRevisionFinalize, nil, doctorID)); err != nil {
+			return err
+		}
+		finalized, err := r.VisitRevisions.FinalizePatientVisit(ctx, db.FinalizePatientVisitParams{
+			FinalizedByUserID: pgtype.UUID{Bytes: doctorID, Valid: true},
+			ID:                current.ID,
+			ExpectedVersion:   current.Version,
+		})
+		if err != nil {
+			return err
+		}
+		if after, err = mapper.MapPatientVisit(&finalized); err != nil {
+			return fmt.Errorf("failed to map finalized visit: %w", err)
+		}
+		return s.auditor.RecordTx(ctx, r, model.AuditEntry{
+			Action:       model.AuditActionUpdate,
+			ResourceType: model.AuditResourceVisit,
+			ResourceID:   &visitID,
+			PatientID:    uuidPtrOf(finalized.PatientID),
+			Changes:      auditDiff(ctx, s.logger, before, after),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, ErrVisitVersionConflict) {
+			return nil, ErrVisitVersionConflict
+		}
+		if errors.Is(err, ErrAuditUnavailable) {
+			return nil, err
+		}
+		s.logger.ErrorContext(ctx, "Failed to finalize patient visit", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to finalize patient visit: %w", err)
+	}
+
+	return after, nil
+}
+
+// GetVisitHistory returns every version of the visit with what changed and who changed it.
+// Callers without clinical access see which clinical fields changed but not their values.
+func (s *patientVisitService) GetVisitHistory(ctx context.Context, visitID uuid.UUID) (*model.VisitHistory, error) {
+	ctx, span := tracing.Start(ctx, "PatientVisitService.GetVisitHistory")
+	defer span.End()
+	visit, err := s.visitRepo.GetPatientVisitByID(ctx, pgtype.UUID{Bytes: visitID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get visit for history", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to get visit: %w", err)
+	}
+	patientID := uuid.UUID(visit.PatientID.Bytes)
+	ctx, level, err := s.access.AuthorizePatientAccess(ctx, patientID)
+	if err != nil {
+		if errors.Is(err, ErrPatientNotFound) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, err
+	}
+
+	revs, err := s.revisions.ListPatientVisitRevisions(ctx, visit.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list revisions", "visit_id", visitID, "error", err)
+		return nil, fmt.Errorf("failed to list visit revisions: %w", err)
+	}
+	current, err := mapper.MapPatientVisit(&visit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map visit: %w", err)
+	}
+	revisions := make([]visithistory.Revision, len(revs))
+	for i, r := range revs {
+		revisions[i] = mapper.MapVisitRevision(r)
+	}
+
+	if err := s.auditor.Record(ctx, model.AuditEntry{
+		Action:       model.AuditActionRead,
+		ResourceType: model.AuditResourceVisit,
+		ResourceID:   &visitID,
+		PatientID:    &patientID,
+	}); err != nil {
+		return nil, err
+	}
+	entries := visithistory.Timeline(*current, revisions)
+	if level < PatientAccessClinical {
+		visithistory.Redact(entries)
+	}
+	return &model.VisitHistory{
+		VisitID:        visitID,
+		PatientID:      patientID,
+		CurrentVersion: current.Version,
+		FinalizedAt:    current.FinalizedAt,
+		Entries:        entries,
+	}, nil
+}
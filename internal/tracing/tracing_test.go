@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record installs a tracer provider that keeps every span for the test.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestTraceID(t *testing.T) {
+	record(t)
+	assert.Equal(t, "", TraceID(context.Background()))
+
+	ctx, span := Start(context.Background(), "PatientService.RegisterPatient")
+	defer span.End()
+	assert.Len(t, TraceID(ctx), 32)
+	assert.Equal(t, span.SpanContext().TraceID().String(), TraceID(ctx))
+}
+
+func TestQueryTracerNamesSpansAfterSqlcQueries(t *testing.T) {
+	recorder := record(t)
+	tracer := QueryTracer{}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "-- name: GetPatientByContactEmail :one\nSELECT * FROM patients WHERE contact_email = $1",
+		Args: []any{"jane@example.com"},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: &pgconn.PgError{Code: "23505", Message: `duplicate key value "jane@example.com"`}})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "db GetPatientByContactEmail", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.operation.name", "GetPatientByContactEmail"))
+	for _, kv := range spans[0].Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "jane", "arguments stay out of spans")
+		assert.NotContains(t, kv.Value.Emit(), "SELECT", "SQL stays out of spans")
+	}
+
+	assert.Equal(t, "db unnamed", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.NotContains(t, spans[1].Status().Description, "jane")
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.response.status_code", "23505"))
+	assert.Empty(t, spans[1].Events(), "error messages stay out of spans")
+}
@@ -0,0 +1,137 @@
+// Package visithistory rebuilds the timeline of a visit from the versions kept each time it
+// changed. Every change stores the visit as it was before, so a version's fields are those
+// stored by the change after it, or the visit as it is now for the latest version.
+package visithistory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+)
+
+// ErrReasonRequired rejects changes to a finalized visit that do not say why.
+var ErrReasonRequired = errors.New("a reason is required to amend a finalized visit")
+
+// Redacted replaces clinical values for callers who may not see them.
+const Redacted = "[redacted]"
+
+// clinicalFields are hidden from callers without clinical access.
+var clinicalFields = map[string]bool{"symptoms": true, "diagnosis": true, "prescription": true, "notes": true}
+
+// Snapshot is the content of a visit at one version.
+type Snapshot struct {
+	VisitDate    time.Time
+	Symptoms     *string
+	Diagnosis    *string
+	Prescription *string
+	Notes        *string
+}
+
+// SnapshotOf returns the content of v.
+func SnapshotOf(v model.PatientVisit) Snapshot {
+	return Snapshot{VisitDate: v.VisitDate, Symptoms: v.Symptoms, Diagnosis: v.Diagnosis, Prescription: v.Prescription, Notes: v.Notes}
+}
+
+// Revision is one stored change: the version it produced and the snapshot it replaced.
+type Revision struct {
+	Version      int32
+	Kind         model.VisitRevisionKind
+	Reason       *string
+	AuthorUserID uuid.UUID
+	RevisedAt    time.Time
+	Previous     Snapshot
+}
+
+// KindFor is the kind of an update to a visit that is or is not finalized. Finalized
+// visits may only be amended with a reason.
+func KindFor(finalized bool, reason *string) (model.VisitRevisionKind, error) {
+	if !finalized {
+		return model.VisitRevisionEdit, nil
+	}
+	if reason == nil || *reason == "" {
+		return "", ErrReasonRequired
+	}
+	return model.VisitRevisionAddendum, nil
+}
+
+// Timeline lists every version of the visit, oldest first. The first entry is the visit as
+// recorded; each later entry is a revision with the fields it changed. revisions must be
+// ordered by version.
+func Timeline(current model.PatientVisit, revisions []Revision) []model.VisitHistoryEntry {
+	snapshots := make([]Snapshot, 0, len(revisions)+1)
+	for _, r := range revisions {
+		snapshots = append(snapshots, r.Previous)
+	}
+	snapshots = append(snapshots, SnapshotOf(current))
+
+	entries := make([]model.VisitHistoryEntry, 0, len(revisions)+1)
+	entries = append(entries, model.VisitHistoryEntry{
+		Version:      1,
+		Kind:         model.VisitRevisionCreated,
+		AuthorUserID: current.DoctorID,
+		At:           current.CreatedAt,
+		Changes:      Diff(Snapshot{}, snapshots[0]),
+	})
+	for i, r := range revisions {
+		entries = append(entries, model.VisitHistoryEntry{
+			Version:      r.Version,
+			Kind:         r.Kind,
+			AuthorUserID: r.AuthorUserID,
+			At:           r.RevisedAt,
+			Reason:       r.Reason,
+			Changes:      Diff(snapshots[i], snapshots[i+1]),
+		})
+	}
+	return entries
+}
+
+// Diff returns the fields that differ between two snapshots. A zero before means the
+// visit did not exist yet.
+func Diff(before, after Snapshot) map[string]model.FieldChange {
+	changes := map[string]model.FieldChange{}
+	if !before.VisitDate.Equal(after.VisitDate) {
+		change := model.FieldChange{New: after.VisitDate}
+		if !before.VisitDate.IsZero() {
+			change.Old = before.VisitDate
+		}
+		changes["visit_date"] = change
+	}
+	text := func(name string, old, new *string) {
+		if old == nil && new == nil || old != nil && new != nil && *old == *new {
+			return
+		}
+		change := model.FieldChange{}
+		if old != nil {
+			change.Old = *old
+		}
+		if new != nil {
+			change.New = *new
+		}
+		changes[name] = change
+	}
+	text("symptoms", before.Symptoms, after.Symptoms)
+	text("diagnosis", before.Diagnosis, after.Diagnosis)
+	text("prescription", before.Prescription, after.Prescription)
+	text("notes", before.Notes, after.Notes)
+	return changes
+}
+
+// Redact hides the values of clinical fields, keeping which fields changed.
+func Redact(entries []model.VisitHistoryEntry) {
+	for _, e := range entries {
+		for name, change := range e.Changes {
+			if !clinicalFields[name] {
+				continue
+			}
+			if change.Old != nil {
+				change.Old = Redacted
+			}
+			if change.New != nil {
+				change.New = Redacted
+			}
+			e.Changes[name] = change
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package visithistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himanshu-holmes/hms/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func str(s string) *string { return &s }
+
+func TestKindFor(t *testing.T) {
+	kind, err := KindFor(false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, model.VisitRevisionEdit, kind)
+
+	_, err = KindFor(true, nil)
+	assert.ErrorIs(t, err, ErrReasonRequired)
+	_, err = KindFor(true, str(""))
+	assert.ErrorIs(t, err, ErrReasonRequired)
+
+	kind, err = KindFor(true, str("Lab result came back"))
+	require.NoError(t, err)
+	assert.Equal(t, model.VisitRevisionAddendum, kind)
+}
+
+func TestTimeline(t *testing.T) {
+	doctor, colleague := uuid.New(), uuid.New()
+	created := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	visitDate := created.Add(-time.Hour)
+	current := model.PatientVisit{
+		ID:           uuid.New(),
+		DoctorID:     doctor,
+		VisitDate:    visitDate,
+		Symptoms:     str("cough"),
+		Diagnosis:    str("J45 asthma"),
+		Prescription: str("salbutamol"),
+		CreatedAt:    created,
+		Version:      4,
+	}
+	original := Snapshot{VisitDate: visitDate, Symptoms: str("cough"), Diagnosis: str("J20 bronchitis")}
+	edited := Snapshot{VisitDate: visitDate, Symptoms: str("cough"), Diagnosis: str("J45 asthma")}
+	revisions := []Revision{
+		{Version: 2, Kind: model.VisitRevisionEdit, AuthorUserID: doctor, RevisedAt: created.Add(time.Hour), Previous: original},
+		{Version: 3, Kind: model.VisitRevisionFinalize, AuthorUserID: doctor, RevisedAt: created.Add(2 * time.Hour), Previous: edited},
+		{Version: 4, Kind: model.VisitRevisionAddendum, Reason: str("Started inhaler"), AuthorUserID: colleague, RevisedAt: created.Add(3 * time.Hour), Previous: edited},
+	}
+
+	entries := Timeline(current, revisions)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, model.VisitRevisionCreated, entries[0].Kind)
+	assert.Equal(t, int32(1), entries[0].Version)
+	assert.Equal(t, doctor, entries[0].AuthorUserID)
+	assert.Equal(t, model.FieldChange{New: "J20 bronchitis"}, entries[0].Changes["diagnosis"])
+	assert.Equal(t, model.FieldChange{New: visitDate}, entries[0].Changes["visit_date"])
+	assert.NotContains(t, entries[0].Changes, "prescription")
+
+	assert.Equal(t, map[string]model.FieldChange{"diagnosis": {Old: "J20 bronchitis", New: "J45 asthma"}}, entries[1].Changes)
+	assert.Empty(t, entries[2].Changes)
+	assert.Equal(t, model.VisitRevisionFinalize, entries[2].Kind)
+
+	assert.Equal(t, colleague, entries[3].AuthorUserID)
+	assert.Equal(t, "Started inhaler", *entries[3].Reason)
+	assert.Equal(t, map[string]model.FieldChange{"prescription": {New: "salbutamol"}}, entries[3].Changes)
+}
+
+func TestTimelineWithoutRevisions(t *testing.T) {
+	current := model.PatientVisit{DoctorID: uuid.New(), VisitDate: time.Now(), Notes: str("follow up")}
+	entries := Timeline(current, nil)
+	require.Len(t, entries, 1)
+	assert.Equal(t, model.FieldChange{New: "follow up"}, entries[0].Changes["notes"])
+}
+
+func TestRedact(t *testing.T) {
+	entries := []model.VisitHistoryEntry{{Changes: map[string]model.FieldChange{
+		"diagnosis":  {Old: "J20", New: "J45"},
+		"notes":      {New: "call back"},
+		"visit_date": {Old: "a", New: "b"},
+	}}}
+	Redact(entries)
+	assert.Equal(t, model.FieldChange{Old: Redacted, New: Redacted}, entries[0].Changes["diagnosis"])
+	assert.Equal(t, model.FieldChange{New: Redacted}, entries[0].Changes["notes"])
+	assert.Equal(t, model.FieldChange{Old: "a", New: "b"}, entries[0].Changes["visit_date"])
+}
@@ -0,0 +1,133 @@
+// Package worker runs background jobs on a fixed interval.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/tracing"
+)
+
+// RunPeriodic calls fn every interval until ctx is cancelled. Errors are logged to logger and the
+// job keeps running; fn is expected to be safe to run concurrently on several replicas.
+func RunPeriodic(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	runPeriodic(ctx, logger, name, interval, fn, nil)
+}
+
+func runPeriodic(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, fn func(ctx context.Context) error, state *jobState) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.InfoContext(ctx, "Worker started", "worker", name, "interval", interval)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "Worker stopped", "worker", name)
+			return
+		case <-ticker.C:
+			state.begin()
+			runCtx, span := tracing.Start(ctx, "worker "+name)
+			err := fn(runCtx)
+			if err != nil {
+				logger.ErrorContext(runCtx, "Worker run failed", "worker", name, "error", err)
+			}
+			span.End()
+			state.end()
+		}
+	}
+}
+
+// Monitor runs jobs like RunPeriodic and keeps track of them, so a health check can
+// report a job that has stopped or is stuck in a run. A failed run does not make a job
+// unhealthy: it is retried on the next tick, and whatever it failed on has checks of
+// its own.
+type Monitor struct {
+	stuckAfter time.Duration
+	now        func() time.Time
+	logger     *slog.Logger
+
+	mu   sync.Mutex
+	jobs map[string]*jobState
+}
+
+// NewMonitor returns a Monitor that reports a job stuck once a run has taken longer than
+// stuckAfter, or three of its intervals if that is longer.
+func NewMonitor(stuckAfter time.Duration, logger *slog.Logger) *Monitor {
+	return &Monitor{stuckAfter: stuckAfter, now: time.Now, logger: logger, jobs: map[string]*jobState{}}
+}
+
+type jobState struct {
+	mu           sync.Mutex
+	now          func() time.Time
+	interval     time.Duration
+	runningSince time.Time
+	stopped      bool
+}
+
+func (s *jobState) begin() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runningSince = s.now()
+}
+
+func (s *jobState) end() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runningSince = time.Time{}
+}
+
+// RunPeriodic runs fn like the package's RunPeriodic, tracked under name.
+func (m *Monitor) RunPeriodic(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	state := &jobState{now: m.now, interval: interval}
+	m.mu.Lock()
+	m.jobs[name] = state
+	m.mu.Unlock()
+
+	defer func() {
+		state.mu.Lock()
+		state.stopped = true
+		state.mu.Unlock()
+	}()
+	runPeriodic(ctx, m.logger, name, interval, fn, state)
+}
+
+// Check reports every job that has stopped or is stuck in a run.
+func (m *Monitor) Check(ctx context.Context) error {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.jobs))
+	for name := range m.jobs {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+	sort.Strings(names)
+
+	now := m.now()
+	var errs []error
+	for _, name := range names {
+		m.mu.Lock()
+		state := m.jobs[name]
+		m.mu.Unlock()
+
+		state.mu.Lock()
+		stuckAfter := max(m.stuckAfter, 3*state.interval)
+		switch {
+		case state.stopped:
+			errs = append(errs, fmt.Errorf("%s: stopped", name))
+		case !state.runningSince.IsZero() && now.Sub(state.runningSince) > stuckAfter:
+			errs = append(errs, fmt.Errorf("%s: run in progress for %s", name, now.Sub(state.runningSince).Round(time.Second)))
+		}
+		state.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/himanshu-holmes/hms/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	m := NewMonitor(time.Minute, logging.Discard())
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	running := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		m.RunPeriodic(ctx, "export", time.Millisecond, func(context.Context) error {
+			once.Do(func() { close(running) })
+			<-release
+			return nil
+		})
+	}()
+	<-running
+	require.NoError(t, m.Check(ctx), "a run in progress is healthy")
+
+	now = now.Add(2 * time.Minute)
+	assert.EqualError(t, m.Check(ctx), "export: run in progress for 2m0s")
+
+	close(release)
+	cancel()
+	<-stopped
+	assert.EqualError(t, m.Check(context.Background()), "export: stopped")
+}